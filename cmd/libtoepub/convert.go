@@ -0,0 +1,108 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// conv is shared by all calls; conversions keep their state per call.
+var conv = converter.New()
+
+// logger reports only warnings and errors, on stderr, so the library stays
+// quiet inside host applications.
+var logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+// libOptions is the JSON options object accepted by ToepubConvert.
+type libOptions struct {
+	Format         string      `json:"format"` // md (default), html, or pdf
+	Metadata       libMetadata `json:"metadata"`
+	TOCPage        bool        `json:"toc_page"`
+	NumberSections bool        `json:"number_sections"`
+	SmartQuotes    bool        `json:"smart_quotes"`
+	SplitLevel     string      `json:"split_level"`
+	EPUBVersion    string      `json:"epub_version"`
+}
+
+// libMetadata overrides metadata from the document.
+type libMetadata struct {
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Language    string   `json:"language"`
+	Publisher   string   `json:"publisher"`
+	Description string   `json:"description"`
+	Rights      string   `json:"rights"`
+	Subjects    []string `json:"subjects"`
+}
+
+// convert converts input with the JSON options and returns the EPUB bytes.
+func convert(input []byte, optionsJSON string) ([]byte, error) {
+	var lo libOptions
+	if optionsJSON != "" {
+		if err := json.Unmarshal([]byte(optionsJSON), &lo); err != nil {
+			return nil, fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	format := parser.FormatMarkdown
+	switch lo.Format {
+	case "", "md", "markdown":
+	case "html", "htm":
+		format = parser.FormatHTML
+	case "pdf":
+		format = parser.FormatPDF
+	default:
+		return nil, fmt.Errorf("%w: %s", converter.ErrUnsupportedFmt, lo.Format)
+	}
+
+	level, err := converter.ParseSplitLevel(lo.SplitLevel)
+	if err != nil {
+		return nil, err
+	}
+	version, err := epub.ParseVersion(lo.EPUBVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := converter.Options{
+		CLIMetadata: &model.Metadata{
+			Title:       lo.Metadata.Title,
+			Authors:     lo.Metadata.Authors,
+			Language:    lo.Metadata.Language,
+			Publisher:   lo.Metadata.Publisher,
+			Description: lo.Metadata.Description,
+			Rights:      lo.Metadata.Rights,
+			Subjects:    lo.Metadata.Subjects,
+		},
+		Typography: lo.SmartQuotes,
+		SplitLevel: level,
+		Logger:     logger,
+		EPUB: epub.Options{
+			TOCPage:        lo.TOCPage,
+			NumberSections: lo.NumberSections,
+			Version:        version,
+		},
+	}
+
+	var out bytes.Buffer
+	if _, err := conv.ConvertReader(bytes.NewReader(input), format, &out, opts); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,35 @@
+//go:build cgo
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+func TestConvert(t *testing.T) {
+	data, err := convert([]byte("# One\n\nHello.\n"), `{"metadata": {"title": "From C"}, "epub_version": "3.0"}`)
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, "OEBPS/toc.ncx")
+}
+
+func TestConvert_InvalidOptions(t *testing.T) {
+	_, err := convert([]byte("# One\n"), `{"format": "docx"}`)
+	assert.ErrorIs(t, err, converter.ErrUnsupportedFmt)
+
+	_, err = convert([]byte("# One\n"), `{not json`)
+	assert.Error(t, err)
+}
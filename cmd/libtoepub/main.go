@@ -0,0 +1,57 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+//go:build cgo
+
+// Command libtoepub builds the converter as a C shared library, so other
+// languages can convert documents in-process:
+//
+//	go build -buildmode=c-shared -o libtoepub.so ./cmd/libtoepub
+//
+// The build also writes libtoepub.h declaring the exported functions.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import "unsafe"
+
+// ToepubConvert converts inputLen bytes of input to EPUB. options is a JSON
+// object (see libOptions), or NULL for defaults. On success it returns 0
+// and sets *out and *outLen to the EPUB bytes; on failure it returns 1 and
+// sets *errMsg. Free *out and *errMsg with ToepubFree.
+//
+//export ToepubConvert
+func ToepubConvert(input *C.char, inputLen C.int, options *C.char, out **C.char, outLen *C.int, errMsg **C.char) C.int {
+	*out, *outLen, *errMsg = nil, 0, nil
+
+	var optionsJSON string
+	if options != nil {
+		optionsJSON = C.GoString(options)
+	}
+
+	data, err := convert(C.GoBytes(unsafe.Pointer(input), inputLen), optionsJSON)
+	if err != nil {
+		*errMsg = C.CString(err.Error())
+		return 1
+	}
+
+	*out = (*C.char)(C.CBytes(data))
+	*outLen = C.int(len(data))
+	return 0
+}
+
+// ToepubFree frees memory returned by ToepubConvert.
+//
+//export ToepubFree
+func ToepubFree(p unsafe.Pointer) {
+	C.free(p)
+}
+
+func main() {}
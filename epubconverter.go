@@ -0,0 +1,94 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+// Package epubconverter is the public API of the EPUB converter for use as
+// a library. It exposes the converter and the document model, so other
+// modules can register parsers for their own input formats:
+//
+//	conv := epubconverter.New()
+//	conv.RegisterParser("bookxml", myParser) // handles ".bxml" files
+//	result, err := conv.Convert([]string{"book.bxml"}, epubconverter.Options{})
+package epubconverter
+
+import (
+	"log/slog"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// Conversion types.
+type (
+	Converter        = converter.Converter
+	Options          = converter.Options
+	DocumentHook     = converter.DocumentHook
+	ConversionResult = model.ConversionResult
+	Warning          = model.Warning
+	EventHandler     = converter.EventHandler
+	EventFunc        = converter.EventFunc
+	RemoteImages     = converter.RemoteImages
+	ImageSize        = converter.ImageSize
+	CoverOptions     = converter.CoverOptions
+	ValidateOptions  = converter.ValidateOptions
+)
+
+// Resource fetchers. Set Options.Fetcher to read images and stylesheets
+// from somewhere other than local disk, or implement ResourceFetcher.
+type (
+	ResourceFetcher = converter.ResourceFetcher
+	FileFetcher     = converter.FileFetcher
+	HTTPFetcher     = converter.HTTPFetcher
+	DataURIFetcher  = converter.DataURIFetcher
+	BucketFetcher   = converter.BucketFetcher
+	SchemeFetcher   = converter.SchemeFetcher
+)
+
+// Parser types. A Parser registered with Converter.RegisterParser handles
+// the file extensions returned by its SupportedExtensions method.
+type (
+	Parser = parser.Parser
+	Format = parser.Format
+)
+
+// Document model types returned by parsers.
+type (
+	Document        = model.Document
+	Chapter         = model.Chapter
+	Resource        = model.Resource
+	Metadata        = model.Metadata
+	TableOfContents = model.TableOfContents
+	TOCEntry        = model.TOCEntry
+)
+
+// Built-in input formats.
+const (
+	FormatMarkdown = parser.FormatMarkdown
+	FormatHTML     = parser.FormatHTML
+	FormatPDF      = parser.FormatPDF
+)
+
+// New creates a Converter with the built-in Markdown, HTML, and PDF parsers.
+func New() *Converter {
+	return converter.New()
+}
+
+// NewSchemeFetcher returns a fetcher for local files, data URIs, http and
+// https URLs, and public s3 and gs buckets.
+func NewSchemeFetcher() *SchemeFetcher {
+	return converter.NewSchemeFetcher()
+}
+
+// LogEvents returns an EventHandler that logs each event to logger.
+func LogEvents(logger *slog.Logger) EventHandler {
+	return converter.LogEvents(logger)
+}
+
+// NewDocument creates an empty document for a parser to fill in.
+func NewDocument() *Document {
+	return model.NewDocument()
+}
@@ -0,0 +1,56 @@
+package epubconverter_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	epubconverter "github.com/dauquangthanh/epub-converter"
+)
+
+// lineParser is a parser for a format unknown to the converter: each
+// non-empty line becomes a chapter.
+type lineParser struct{}
+
+func (lineParser) Parse(content []byte, basePath string) (*epubconverter.Document, error) {
+	doc := epubconverter.NewDocument()
+	doc.Metadata.Title = "Lines"
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		doc.AddChapter(epubconverter.Chapter{
+			Title:   line,
+			Level:   1,
+			Content: "<h1>" + line + "</h1>",
+			Order:   len(doc.Chapters),
+		})
+	}
+	return doc, nil
+}
+
+func (lineParser) SupportedExtensions() []string {
+	return []string{".lines"}
+}
+
+func TestRegisterParser(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "book.lines")
+	require.NoError(t, os.WriteFile(input, []byte("First\nSecond\n"), 0o644))
+
+	conv := epubconverter.New()
+	conv.RegisterParser("lines", lineParser{})
+
+	result, err := conv.Convert([]string{dir}, epubconverter.Options{OutputPath: filepath.Join(dir, "book.epub")})
+	require.NoError(t, err)
+	assert.Equal(t, "lines", result.Stats.InputFormat)
+	assert.Equal(t, 1, result.Stats.InputFiles)
+	assert.True(t, result.Success)
+
+	// The format can also be forced by name
+	for _, format := range []string{"lines", "LINES"} {
+		_, err := conv.Convert([]string{input}, epubconverter.Options{InputFormat: format, OutputPath: filepath.Join(dir, "forced.epub")})
+		assert.NoError(t, err, format)
+	}
+}
@@ -0,0 +1,343 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// benchStages are the pipeline stages timed by bench, in pipeline order.
+// Time outside them, such as reading the input, is reported as "other".
+var benchStages = []string{"parse", "images", "build", "write"}
+
+// benchCmd represents the bench command
+var benchCmd = &cobra.Command{
+	Use:   "bench <input>... [flags]",
+	Short: "Measure conversion time and allocations",
+	Long: `Convert the input repeatedly and report how long each pipeline stage
+takes (parse, images, build, write) and how much memory a conversion
+allocates.
+
+The books are written to a temporary directory and discarded. One
+conversion runs first as a warm-up and is not counted. --cpuprofile
+writes a pprof CPU profile of the measured runs and --memprofile an
+allocation profile, for "go tool pprof".`,
+	Example: `  # Time ten conversions of a book
+  toepub bench book.md -n 10
+
+  # Profile the conversion of a directory
+  toepub bench chapters/ --cpuprofile cpu.pprof --memprofile mem.pprof`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runBench,
+}
+
+// Bench flags
+var (
+	benchRuns       int
+	benchCPUProfile string
+	benchMemProfile string
+)
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+
+	benchCmd.Flags().IntVarP(&benchRuns, "runs", "n", 5, "Number of measured conversions")
+	benchCmd.Flags().StringVar(&benchCPUProfile, "cpuprofile", "", "Write a CPU profile of the measured runs to file")
+	benchCmd.Flags().StringVar(&benchMemProfile, "memprofile", "", "Write an allocation profile to file")
+	benchCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// benchRun is the measurement of one conversion.
+type benchRun struct {
+	total  time.Duration
+	stages map[string]time.Duration
+	bytes  uint64 // Bytes allocated
+	allocs uint64 // Heap objects allocated
+}
+
+// runBench executes the bench command
+func runBench(cmd *cobra.Command, args []string) error {
+	if benchRuns < 1 {
+		return fmt.Errorf("invalid --runs %d: must be at least 1", benchRuns)
+	}
+
+	dir, err := os.MkdirTemp("", "toepub-bench-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	timer := newStageTimer(slog.Default().Handler())
+	conv := converter.New()
+	opts := converter.Options{
+		OutputPath: filepath.Join(dir, "bench.epub"),
+		Logger:     slog.New(timer),
+	}
+	if _, err := conv.Convert(args, opts); err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if benchCPUProfile != "" {
+		f, err := os.Create(benchCPUProfile)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := pprof.StartCPUProfile(f); err != nil {
+			return fmt.Errorf("starting CPU profile: %w", err)
+		}
+	}
+	if benchMemProfile != "" {
+		runtime.MemProfileRate = 4096
+	}
+
+	runs := make([]benchRun, 0, benchRuns)
+	for range benchRuns {
+		timer.reset()
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		_, err := conv.Convert(args, opts)
+		total := time.Since(start)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			pprof.StopCPUProfile()
+			return handleConvertError(cmd, err)
+		}
+		runs = append(runs, benchRun{
+			total:  total,
+			stages: timer.summed(),
+			bytes:  after.TotalAlloc - before.TotalAlloc,
+			allocs: after.Mallocs - before.Mallocs,
+		})
+	}
+
+	if benchCPUProfile != "" {
+		pprof.StopCPUProfile()
+	}
+	if benchMemProfile != "" {
+		if err := writeAllocProfile(benchMemProfile); err != nil {
+			return err
+		}
+	}
+
+	if outputFmt == "json" {
+		outputBenchJSON(cmd, args, runs)
+	} else {
+		outputBenchHuman(cmd, args, runs)
+	}
+	return nil
+}
+
+// writeAllocProfile writes the allocations sampled so far to path.
+func writeAllocProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := pprof.Lookup("allocs").WriteTo(f, 0); err != nil {
+		f.Close()
+		return fmt.Errorf("writing allocation profile: %w", err)
+	}
+	return f.Close()
+}
+
+// benchTimings returns the timings of each stage across runs, followed by
+// "other" and "total".
+func benchTimings(runs []benchRun) ([]string, map[string][]time.Duration) {
+	names := append(slices.Clone(benchStages), "other", "total")
+	timings := make(map[string][]time.Duration, len(names))
+	for _, run := range runs {
+		other := run.total
+		for _, stage := range benchStages {
+			timings[stage] = append(timings[stage], run.stages[stage])
+			other -= run.stages[stage]
+		}
+		timings["other"] = append(timings["other"], max(other, 0))
+		timings["total"] = append(timings["total"], run.total)
+	}
+	return names, timings
+}
+
+// benchSummary returns the mean, minimum, and maximum of durations.
+func benchSummary(durations []time.Duration) (mean, lo, hi time.Duration) {
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+	return sum / time.Duration(len(durations)), slices.Min(durations), slices.Max(durations)
+}
+
+// benchAllocs returns the mean bytes and objects allocated per run.
+func benchAllocs(runs []benchRun) (bytes, allocs uint64) {
+	for _, run := range runs {
+		bytes += run.bytes
+		allocs += run.allocs
+	}
+	return bytes / uint64(len(runs)), allocs / uint64(len(runs))
+}
+
+// outputBenchHuman prints the benchmark as a table of stage timings
+func outputBenchHuman(cmd *cobra.Command, args []string, runs []benchRun) {
+	noun := "runs"
+	if len(runs) == 1 {
+		noun = "run"
+	}
+	cmd.Printf("Benchmarked %s: %d %s after a warm-up\n\n", args[0], len(runs), noun)
+	cmd.Printf("  %-8s %12s %12s %12s\n", "Stage", "Mean", "Min", "Max")
+	names, timings := benchTimings(runs)
+	for _, name := range names {
+		mean, lo, hi := benchSummary(timings[name])
+		cmd.Printf("  %-8s %12s %12s %12s\n", name, formatBenchDuration(mean), formatBenchDuration(lo), formatBenchDuration(hi))
+	}
+
+	bytes, allocs := benchAllocs(runs)
+	cmd.Printf("\n  Allocated %.1f MB in %d objects per run\n", float64(bytes)/(1<<20), allocs)
+	if benchCPUProfile != "" {
+		cmd.Printf("  CPU profile written to %s\n", benchCPUProfile)
+	}
+	if benchMemProfile != "" {
+		cmd.Printf("  Allocation profile written to %s\n", benchMemProfile)
+	}
+}
+
+// formatBenchDuration formats a stage timing to millisecond precision
+func formatBenchDuration(d time.Duration) string {
+	return fmt.Sprintf("%.2fms", milliseconds(d))
+}
+
+// outputBenchJSON prints the benchmark as JSON to stdout
+func outputBenchJSON(cmd *cobra.Command, args []string, runs []benchRun) {
+	output := jsonBench{Inputs: args, Runs: len(runs)}
+	names, timings := benchTimings(runs)
+	for _, name := range names {
+		mean, lo, hi := benchSummary(timings[name])
+		output.Stages = append(output.Stages, jsonBenchStage{
+			Stage:  name,
+			MeanMS: milliseconds(mean),
+			MinMS:  milliseconds(lo),
+			MaxMS:  milliseconds(hi),
+		})
+	}
+	output.BytesPerRun, output.AllocsPerRun = benchAllocs(runs)
+	output.CPUProfile = benchCPUProfile
+	output.MemProfile = benchMemProfile
+
+	data, _ := json.MarshalIndent(output, "", "  ")
+	cmd.Println(string(data))
+}
+
+// milliseconds returns d in fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type jsonBench struct {
+	Inputs       []string         `json:"inputs"`
+	Runs         int              `json:"runs"`
+	Stages       []jsonBenchStage `json:"stages"`
+	BytesPerRun  uint64           `json:"bytes_per_run"`
+	AllocsPerRun uint64           `json:"allocs_per_run"`
+	CPUProfile   string           `json:"cpu_profile,omitempty"`
+	MemProfile   string           `json:"mem_profile,omitempty"`
+}
+
+type jsonBenchStage struct {
+	Stage  string  `json:"stage"`
+	MeanMS float64 `json:"mean_ms"`
+	MinMS  float64 `json:"min_ms"`
+	MaxMS  float64 `json:"max_ms"`
+}
+
+// stageTimer is a slog handler summing the durations logged by each
+// pipeline stage, the "duration" attribute of records with a "stage"
+// attribute. Records are passed on to next as usual, so -v still logs.
+type stageTimer struct {
+	next   slog.Handler
+	totals *stageTotals // Shared with the handlers derived by With
+}
+
+// stageTotals are the durations summed per stage.
+type stageTotals struct {
+	mu     sync.Mutex
+	stages map[string]time.Duration
+}
+
+func newStageTimer(next slog.Handler) *stageTimer {
+	return &stageTimer{next: next, totals: &stageTotals{stages: make(map[string]time.Duration)}}
+}
+
+// reset clears the durations summed so far.
+func (t *stageTimer) reset() {
+	t.totals.mu.Lock()
+	defer t.totals.mu.Unlock()
+	clear(t.totals.stages)
+}
+
+// summed returns the durations summed per stage since the last reset.
+func (t *stageTimer) summed() map[string]time.Duration {
+	t.totals.mu.Lock()
+	defer t.totals.mu.Unlock()
+	return maps.Clone(t.totals.stages)
+}
+
+func (t *stageTimer) Enabled(ctx context.Context, level slog.Level) bool {
+	// Stage summaries are logged at info, below the default level
+	return level >= slog.LevelInfo || t.next.Enabled(ctx, level)
+}
+
+func (t *stageTimer) Handle(ctx context.Context, r slog.Record) error {
+	var stage string
+	var duration time.Duration
+	timed := false
+	r.Attrs(func(a slog.Attr) bool {
+		switch a.Key {
+		case "stage":
+			stage = a.Value.String()
+		case "duration":
+			if a.Value.Kind() == slog.KindDuration {
+				duration = a.Value.Duration()
+				timed = true
+			}
+		}
+		return true
+	})
+	if stage != "" && timed {
+		t.totals.mu.Lock()
+		t.totals.stages[stage] += duration
+		t.totals.mu.Unlock()
+	}
+
+	if t.next.Enabled(ctx, r.Level) {
+		return t.next.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (t *stageTimer) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &stageTimer{next: t.next.WithAttrs(attrs), totals: t.totals}
+}
+
+func (t *stageTimer) WithGroup(name string) slog.Handler {
+	return &stageTimer{next: t.next.WithGroup(name), totals: t.totals}
+}
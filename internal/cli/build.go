@@ -0,0 +1,95 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build [project] [flags]",
+	Short: "Build every book of a multi-book project",
+	Long: `Build the books listed in a project file: toepub.yaml by default, or
+the book.yaml written by "toepub init".
+
+A project defines several books that share stylesheets, templates, and
+metadata. Books are built one after another into the project's output
+directory, sharing a parse cache, so files used by several books are
+parsed once. A book that fails does not stop the others; the exit code
+is that of the first failure.`,
+	Example: `  # Build every book of ./toepub.yaml
+  toepub build
+
+  # Build one book of a project in another directory
+  toepub build series/ --book volume-2`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBuild,
+}
+
+// buildBooks are the names of the books to build
+var buildBooks []string
+
+func init() {
+	rootCmd.AddCommand(buildCmd)
+
+	buildCmd.Flags().StringArrayVar(&buildBooks, "book", nil, "Build only the book with NAME, repeatable")
+	buildCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// runBuild executes the build command
+func runBuild(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	project, err := converter.LoadProject(path)
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	conv := converter.New()
+	results, err := conv.BuildProject(project, buildBooks, converter.Options{})
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	exitCode := ExitSuccess
+	output := jsonBuildOutput{Success: true}
+	for _, result := range results {
+		if result.Error != nil && exitCode == ExitSuccess {
+			exitCode = determineExitCode(result.Error)
+		}
+		if outputFmt == "json" {
+			output.Books = append(output.Books, newJSONOutput(result))
+			continue
+		}
+		if result.Success {
+			_ = outputResult(cmd, result)
+		}
+		if result.Error != nil {
+			outputHumanError(cmd, result.Error)
+		}
+	}
+
+	if outputFmt == "json" {
+		output.Success = exitCode == ExitSuccess
+		data, _ := json.MarshalIndent(output, "", "  ")
+		cmd.Println(string(data))
+	}
+	if exitCode != ExitSuccess {
+		os.Exit(exitCode)
+	}
+	return nil
+}
@@ -8,15 +8,22 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/epub"
 	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
 )
 
 // Exit codes following BSD sysexits.h conventions
@@ -37,7 +44,8 @@ var convertCmd = &cobra.Command{
 	Long: `Convert input file(s) to EPUB 3+ format.
 
 Supports Markdown (.md), HTML (.html, .htm), and PDF (.pdf) input.
-Multiple files or directories are combined into a single EPUB.`,
+Multiple files or directories are combined into a single EPUB. A .zip,
+.tar, or .tar.gz archive is converted like a directory.`,
 	Example: `  # Convert single Markdown file
   toepub convert document.md
 
@@ -50,6 +58,12 @@ Multiple files or directories are combined into a single EPUB.`,
   # Convert directory
   toepub convert ./docs/
 
+  # Convert a directory tree, skipping entries listed in .toepubignore
+  toepub convert ./docs/ --recursive
+
+  # Convert a book project exported as an archive
+  toepub convert docs-bundle.zip
+
   # Set metadata
   toepub convert document.md --title "My Book" --author "John Doe"
 
@@ -59,6 +73,26 @@ Multiple files or directories are combined into a single EPUB.`,
   # JSON output for scripting
   toepub convert document.md --format json
 
+  # Write the EPUB contents as a directory, then zip them
+  toepub convert ./docs/ --output-dir build/epub/
+  toepub pack build/epub/ -o book.epub
+
+  # Parse now, build later from the saved document
+  toepub convert ./docs/ --emit-ir book.json
+  toepub convert --from-ir book.json -o book.epub
+
+  # Embed images linked from the web
+  toepub convert article.html --download-remote-images --remote-allow example.com
+
+  # Skip parsing unchanged files on repeated builds
+  toepub convert ./docs/ --cache-dir .toepub-cache
+
+  # Rebuild on every change while editing
+  toepub convert ./docs/ -o book.epub --watch
+
+  # A photo book from a directory of images, four to a page
+  toepub convert photos/ --gallery-grid 4
+
   # From stdin
   cat document.md | toepub convert -`,
 	Args: cobra.MinimumNArgs(1),
@@ -67,13 +101,88 @@ Multiple files or directories are combined into a single EPUB.`,
 
 // Command flags
 var (
-	outputPath  string
-	outputFmt   string
-	title       string
-	author      string
-	language    string
-	coverImage  string
-	inputFormat string
+	outputPath   string
+	outputFmt    string
+	title        string
+	author       string
+	languages    []string
+	coverImage   string
+	inputFormat  string
+	layout       string
+	viewport     string
+	spread       string
+	orientation  string
+	direction    string
+	identifiers  []string
+	uniqueID     string
+	contributors []string
+	templateDir  string
+	navTitle     string
+	audioFiles   []string
+	allowScripts []string
+	inlineScript bool
+	tocDepth     int
+	tocPage      bool
+	bibliography string
+	glossary     string
+	lexicons     []string
+	stylesheets  []string
+	notes        string
+	numberSects  bool
+	dropCaps     bool
+	chapterSpace string
+	smartQuotes  bool
+	hyphenate    bool
+	hyphenPats   string
+	pageBreaks   bool
+	recursive    bool
+	splitLevel   string
+	galleries    []string
+	galleryGrid  int
+	excludes     []string
+	epubVersion  string
+	cssPlacement string
+	codeTheme    string
+	mathOutput   string
+	noDefaultCSS bool
+	publisher    string
+	description  string
+	rights       string
+	license      string
+	pubDate      string
+	subjects     []string
+	isbn         string
+	series       string
+	seriesIndex  string
+	collections  []string
+	emitIR       string
+	fromIR       bool
+	cacheDir     string
+	maxMemory    int
+	maxSize      string
+	epubcheck    bool
+	jobs         int
+	watch        bool
+	downloadImgs bool
+	remoteAllow  []string
+	remoteDeny   []string
+	fetchTimeout time.Duration
+	remoteMaxMB  int
+	webpMode     string
+	maxImageSize string
+	grayscale    bool
+	contrast     float64
+	coverSize    string
+	coverCrop    bool
+	fillAltText  bool
+	gifMode      string
+	strict       bool
+	checkA11y    bool
+	readerProf   string
+	outputDir    string
+	unpacked     bool
+	manifest     bool
+	nameTemplate string
 )
 
 func init() {
@@ -81,50 +190,345 @@ func init() {
 
 	// Define flags
 	convertCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path")
+	convertCmd.Flags().StringVar(&nameTemplate, "output-template", "", "Name the output from the book's metadata, such as '{title}-{author}-{date}.epub'; fields: "+strings.Join(converter.TemplateFields(), ", "))
+	convertCmd.Flags().StringVar(&outputDir, "output-dir", "", "Write the EPUB contents as a directory tree at DIR instead of a zip (pack it with toepub pack)")
+	convertCmd.Flags().BoolVar(&unpacked, "unpacked", false, "Write the EPUB contents as a directory tree named after the output file, without .epub")
 	convertCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
 	convertCmd.Flags().StringVarP(&title, "title", "t", "", "Override book title")
 	convertCmd.Flags().StringVarP(&author, "author", "a", "", "Override author name")
-	convertCmd.Flags().StringVarP(&language, "language", "l", "", "Book language (BCP 47 code)")
+	convertCmd.Flags().StringArrayVarP(&languages, "language", "l", nil, "Book language (BCP 47 code); repeat for a multilingual book, main language first")
 	convertCmd.Flags().StringVarP(&coverImage, "cover", "c", "", "Cover image path")
+	convertCmd.Flags().StringVar(&publisher, "publisher", "", "Publisher name")
+	convertCmd.Flags().StringVar(&description, "description", "", "Book description")
+	convertCmd.Flags().StringVar(&rights, "rights", "", "Rights statement (e.g., \"© 2025 Jane Doe. All rights reserved.\")")
+	convertCmd.Flags().StringVar(&license, "license", "", "License preset expanded into the rights statement: "+strings.Join(model.LicenseNames(), ", "))
+	convertCmd.Flags().StringVar(&pubDate, "date", "", "Publication date as YYYY-MM-DD, YYYY-MM, or YYYY")
+	convertCmd.Flags().StringArrayVar(&subjects, "subject", nil, "Subject or keyword, repeatable")
+	convertCmd.Flags().StringVar(&isbn, "isbn", "", "ISBN-10 or ISBN-13, checked and written as urn:isbn: (same as --identifier isbn:VALUE)")
+	convertCmd.Flags().StringVar(&series, "series", "", "Series the book belongs to")
+	convertCmd.Flags().StringVar(&seriesIndex, "series-index", "", "Position of the book in its series (e.g., 2 or 2.5)")
+	convertCmd.Flags().StringArrayVar(&collections, "collection", nil, "Collection the book belongs to, repeatable")
 	convertCmd.Flags().StringVar(&inputFormat, "input-format", "", "Force input format: md, html, pdf")
+	convertCmd.Flags().StringVar(&layout, "layout", "", "Rendition layout: reflowable or pre-paginated")
+	convertCmd.Flags().StringVar(&viewport, "viewport", "", "Fixed-layout page size as WIDTHxHEIGHT (e.g., 1200x1600)")
+	convertCmd.Flags().StringVar(&spread, "spread", "", "Fixed-layout spread behavior: none, landscape, both, auto")
+	convertCmd.Flags().StringVar(&orientation, "orientation", "", "Fixed-layout page orientation: auto, landscape, portrait")
+	convertCmd.Flags().StringVar(&direction, "direction", "", "Reading direction: ltr, rtl, or default (left to the reading system)")
+	convertCmd.Flags().StringArrayVar(&identifiers, "identifier", nil, "Book identifier as SCHEME:VALUE (e.g., isbn:9780306406157), repeatable")
+	convertCmd.Flags().StringArrayVar(&contributors, "contributor", nil, "Contributor as ROLE:NAME (editor, translator, illustrator, narrator), repeatable")
+	convertCmd.Flags().StringVar(&templateDir, "template-dir", "", "Directory with custom content.xhtml.tmpl, nav.xhtml.tmpl, package.opf.tmpl")
+	convertCmd.Flags().StringVar(&navTitle, "nav-title", "", "Table of contents heading (default: localized from book language)")
+	convertCmd.Flags().StringArrayVar(&audioFiles, "audio", nil, "Audio file to embed (mp3, m4a, aac, opus), repeatable")
+	convertCmd.Flags().StringArrayVar(&allowScripts, "allow-script", nil, "Preserve HTML scripts whose file name matches GLOB (e.g., quiz.js, \"*\"), repeatable")
+	convertCmd.Flags().BoolVar(&inlineScript, "allow-inline-scripts", false, "Preserve inline HTML scripts and event handlers")
+	convertCmd.Flags().IntVar(&tocDepth, "toc-depth", 0, "Maximum heading depth shown in the table of contents (0 = all)")
+	convertCmd.Flags().BoolVar(&tocPage, "toc-page", false, "Add a visible contents page at the start of the book")
+	convertCmd.Flags().BoolVar(&numberSects, "number-sections", false, "Prefix headings and TOC entries with section numbers (1, 1.1, 1.1.2)")
+	convertCmd.Flags().BoolVar(&dropCaps, "drop-caps", false, "Start each chapter with a drop cap")
+	convertCmd.Flags().StringVar(&chapterSpace, "chapter-spacing", "", "Space above each chapter's first heading as a CSS length (e.g., 20vh, 6em)")
+	convertCmd.Flags().BoolVar(&smartQuotes, "smart-quotes", false, "Convert straight quotes, --, ---, and ... to typographic quotes, dashes, and ellipses")
+	convertCmd.Flags().BoolVar(&hyphenate, "hyphenate", false, "Insert soft hyphens into long words for justified text (built in: en, de, fr, es, it, pt)")
+	convertCmd.Flags().StringVar(&hyphenPats, "hyphenate-patterns", "", "TeX hyphenation pattern file for the book language, used instead of the built-in dictionary")
+	convertCmd.Flags().BoolVar(&pageBreaks, "pagebreak-markers", false, "Mark page-break directives with numbered epub:type=\"pagebreak\" anchors")
+	convertCmd.Flags().StringVar(&bibliography, "bibliography", "", "BibTeX (.bib) or CSL-JSON (.json) file for [@key] citations")
+	convertCmd.Flags().StringVar(&notes, "notes", model.NotesPopup, "Footnote placement: popup (asides after each chapter), chapter (numbered list per chapter), or book (endnotes chapter)")
+	convertCmd.Flags().StringArrayVar(&stylesheets, "css", nil, "Stylesheet linked from every chapter, after the built-in one, repeatable")
+	convertCmd.Flags().StringVar(&glossary, "glossary", "", "YAML file mapping glossary terms to definitions")
+	convertCmd.Flags().StringArrayVar(&lexicons, "lexicon", nil, "PLS pronunciation lexicon, or YAML file mapping terms to IPA pronunciations, repeatable")
+	convertCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include files in subdirectories of directory inputs (honors .toepubignore)")
+	convertCmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip input files matching GLOB (e.g., README.md, \"drafts/**\"), repeatable")
+	convertCmd.Flags().StringVar(&readerProf, "profile", epub.ProfileGeneric, "Reader profile: kindle, kobo (kepub spans, .kepub.epub), apple, or generic; sets defaults for flags not given")
+	convertCmd.Flags().StringVar(&epubVersion, "epub-version", epub.Version33, "Target EPUB version: 3.3, or 3.0 to add toc.ncx and legacy cover metadata")
+	convertCmd.Flags().StringVar(&cssPlacement, "default-css", epub.DefaultCSSFirst, "Built-in stylesheet placement: first (your CSS wins), last (built-in rules win), or none")
+	convertCmd.Flags().StringVar(&codeTheme, "code-theme", epub.CodeThemeLight, "Code highlighting colors: light (dark in night mode), dark, or none")
+	convertCmd.Flags().StringVar(&mathOutput, "math", epub.MathMathML, "Equation output: mathml, svg (images), or both (MathML with image fallback)")
+	convertCmd.Flags().BoolVar(&noDefaultCSS, "no-default-css", false, "Leave out the built-in stylesheet (same as --default-css none)")
+	convertCmd.Flags().StringArrayVar(&galleries, "gallery", nil, "Directory of images made into photo pages after the inputs, captioned from NAME.txt or the file name, repeatable")
+	convertCmd.Flags().IntVar(&galleryGrid, "gallery-grid", 0, "Images per photo page, laid out in a grid (0 = one image per page)")
+	convertCmd.Flags().StringVar(&splitLevel, "split-level", "none", "Start a new XHTML file at each h1 (1), h1 and h2 (2), or only per input file (none)")
+	convertCmd.Flags().StringVar(&emitIR, "emit-ir", "", "Write the parsed document as JSON to FILE instead of building an EPUB")
+	convertCmd.Flags().BoolVar(&fromIR, "from-ir", false, "Build the EPUB from a document JSON file written by --emit-ir")
+	convertCmd.Flags().BoolVar(&downloadImgs, "download-remote-images", false, "Download images referenced by http(s) URL into the book instead of linking them")
+	convertCmd.Flags().StringArrayVar(&remoteAllow, "remote-allow", nil, "Only download remote images from DOMAIN and its subdomains, repeatable")
+	convertCmd.Flags().StringArrayVar(&remoteDeny, "remote-deny", nil, "Never download remote images from DOMAIN and its subdomains, repeatable")
+	convertCmd.Flags().DurationVar(&fetchTimeout, "remote-timeout", 30*time.Second, "Timeout for downloading each remote image")
+	convertCmd.Flags().IntVar(&remoteMaxMB, "remote-max-size", 20, "Largest remote image to download, in MB")
+	convertCmd.Flags().StringVar(&webpMode, "webp", converter.WebPConvert, "WebP images: png (convert), keep (embed as image/webp), or fallback (keep with a PNG fallback)")
+	convertCmd.Flags().StringVar(&maxImageSize, "max-image-size", "", "Downscale larger JPEG and PNG images to fit WIDTHxHEIGHT, such as 1600x2400 (0 leaves a dimension unbounded)")
+	convertCmd.Flags().BoolVar(&grayscale, "grayscale", false, "Convert JPEG and PNG images to grayscale for e-ink devices, making them smaller")
+	convertCmd.Flags().Float64Var(&contrast, "contrast", 1, "Contrast factor for --grayscale images, such as 1.2 (1 = unchanged)")
+	convertCmd.Flags().StringVar(&coverSize, "cover-size", "", "Check the cover against WIDTHxHEIGHT, such as "+converter.RecommendedCoverSize.String()+", and downscale larger covers")
+	convertCmd.Flags().BoolVar(&coverCrop, "cover-crop", false, "Crop the cover to the --cover-size aspect ratio around its center (default size "+converter.RecommendedCoverSize.String()+")")
+	convertCmd.Flags().BoolVar(&fillAltText, "fill-alt-text", false, "Give images without alt text one from their figure caption, title, or file name")
+	convertCmd.Flags().StringVar(&gifMode, "animated-gif", converter.GIFKeep, "Animated GIFs: keep, png (first frame as PNG), or static (first frame as GIF)")
+	convertCmd.Flags().BoolVar(&strict, "strict", false, "Fail on problems in the built book, such as malformed XHTML or broken links, instead of warning")
+	convertCmd.Flags().BoolVar(&epubcheck, "epubcheck", false, "Run epubcheck (a bundled epubcheck.jar or epubcheck on PATH) on the built book; its errors fail with exit code 65")
+	convertCmd.Flags().BoolVar(&manifest, "manifest", false, "Write a build manifest beside the EPUB (book.epub.json) with its SHA-256, chapter word counts, resources, and the flags given")
+	convertCmd.Flags().BoolVar(&checkA11y, "check-a11y", false, "Audit the built book for accessibility problems, such as skipped heading levels and tables without headers")
+	convertCmd.Flags().StringVar(&maxSize, "max-size", "", "Warn when the EPUB is larger than SIZE, such as 5MB or 650KB, listing its largest resources")
+	convertCmd.Flags().IntVar(&jobs, "jobs", 0, "Images and chapters processed at once (0 = number of CPUs)")
+	convertCmd.Flags().IntVar(&maxMemory, "max-memory", 0, "Memory budget in MB; images over it are spilled to disk, and larger text fails cleanly (0 = no limit)")
+	convertCmd.Flags().BoolVarP(&watch, "watch", "w", false, "Rebuild the book whenever an input file changes, reusing unchanged files and chapters")
+	convertCmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Cache parsed input files in DIR so unchanged files are not parsed again")
+	convertCmd.Flags().StringVar(&uniqueID, "unique-id", "", "Scheme of the identifier to use as unique-identifier (e.g., isbn)")
 }
 
+// cssLengthRe matches a non-negative CSS length with a unit.
+var cssLengthRe = regexp.MustCompile(`^\d+(\.\d+)?(em|rem|ex|ch|px|pt|pc|cm|mm|in|vh|vw|%)$`)
+
 // runConvert executes the convert command
 func runConvert(cmd *cobra.Command, args []string) error {
+	if tocDepth < 0 {
+		return fmt.Errorf("invalid --toc-depth %d: must be 0 or greater", tocDepth)
+	}
+
+	if chapterSpace != "" && !cssLengthRe.MatchString(chapterSpace) {
+		return fmt.Errorf("invalid --chapter-spacing %q: expected a CSS length such as 20vh or 6em", chapterSpace)
+	}
+
+	level, err := converter.ParseSplitLevel(splitLevel)
+	if err != nil {
+		return fmt.Errorf("invalid --split-level %q: must be 1, 2 or none", splitLevel)
+	}
+
+	webp, err := converter.ParseWebP(webpMode)
+	if err != nil {
+		return fmt.Errorf("invalid --webp %q: must be png, keep or fallback", webpMode)
+	}
+
+	animatedGIF, err := converter.ParseAnimatedGIF(gifMode)
+	if err != nil {
+		return fmt.Errorf("invalid --animated-gif %q: must be keep, png or static", gifMode)
+	}
+
+	imageSize, err := converter.ParseImageSize(maxImageSize)
+	if err != nil {
+		return fmt.Errorf("invalid --max-image-size: %w", err)
+	}
+
+	sizeLimit, err := converter.ParseByteSize(maxSize)
+	if err != nil {
+		return fmt.Errorf("invalid --max-size: %w", err)
+	}
+
+	cover, err := converter.ParseImageSize(coverSize)
+	if err != nil || (coverSize != "" && (cover.Width == 0 || cover.Height == 0)) {
+		return fmt.Errorf("invalid --cover-size %q: must be WIDTHxHEIGHT, such as %s", coverSize, converter.RecommendedCoverSize)
+	}
+	if coverCrop && cover.IsZero() {
+		cover = converter.RecommendedCoverSize
+	}
+
+	if jobs < 0 {
+		return fmt.Errorf("invalid --jobs %d: must be 0 or more", jobs)
+	}
+
+	if contrast <= 0 {
+		return fmt.Errorf("invalid --contrast %g: must be greater than 0", contrast)
+	}
+
+	version, err := epub.ParseVersion(epubVersion)
+	if err != nil {
+		return fmt.Errorf("invalid --epub-version: %w (supported: 3.0, 3.3)", err)
+	}
+
+	defaultCSS, err := epub.ParseDefaultCSS(cssPlacement)
+	if err != nil {
+		return fmt.Errorf("invalid --default-css %q: must be first, last or none", cssPlacement)
+	}
+	if noDefaultCSS {
+		defaultCSS = epub.DefaultCSSNone
+	}
+
+	theme, err := epub.ParseCodeTheme(codeTheme)
+	if err != nil {
+		return fmt.Errorf("invalid --code-theme %q: must be light, dark or none", codeTheme)
+	}
+
+	mathMode, err := epub.ParseMath(mathOutput)
+	if err != nil {
+		return fmt.Errorf("invalid --math %q: must be mathml, svg or both", mathOutput)
+	}
+
+	notePlacement, err := model.ParseNotePlacement(notes)
+	if err != nil {
+		return fmt.Errorf("invalid --notes %q: must be chapter, book or popup", notes)
+	}
+
+	profile, err := epub.ParseProfile(readerProf)
+	if err != nil {
+		return fmt.Errorf("invalid --profile %q: must be kindle, kobo, apple or generic", readerProf)
+	}
+	// The profile sets defaults; flags given explicitly still win
+	flags := cmd.Flags()
+	if !flags.Changed("epub-version") {
+		version = epub.ProfileVersion(profile)
+	}
+	if profile == epub.ProfileKindle {
+		if !flags.Changed("animated-gif") {
+			animatedGIF = converter.GIFStatic
+		}
+		if !flags.Changed("math") {
+			mathMode = epub.MathSVG
+		}
+		if cover.IsZero() {
+			cover = converter.RecommendedCoverSize
+		}
+	}
+
 	// Build CLI metadata overrides
-	cliMeta := buildCLIMetadata()
+	cliMeta, err := buildCLIMetadata()
+	if err != nil {
+		return err
+	}
 
 	// Build converter options
 	opts := converter.Options{
-		OutputPath:  outputPath,
-		InputFormat: inputFormat,
-		CLIMetadata: cliMeta,
+		OutputPath:   outputPath,
+		InputFormat:  inputFormat,
+		CLIMetadata:  cliMeta,
+		TemplateDir:  templateDir,
+		Audio:        audioFiles,
+		Bibliography: bibliography,
+		Glossary:     glossary,
+		Lexicons:     lexicons,
+		Stylesheets:  stylesheets,
+		Notes:        notePlacement,
+		Typography:   smartQuotes,
+		Hyphenate:    hyphenate || hyphenPats != "",
+		Hyphenation:  hyphenPats,
+		Recursive:    recursive,
+		SplitLevel:   level,
+		Gallery:      galleries,
+		GalleryGrid:  galleryGrid,
+		Exclude:      excludes,
+		EmitIR:       emitIR,
+		CacheDir:     cacheDir,
+		MaxMemory:    int64(maxMemory) << 20,
+		MaxSize:      sizeLimit,
+		Workers:      jobs,
+		WebP:         webp,
+		MaxImageSize: imageSize,
+		Grayscale:    grayscale,
+		Contrast:     contrast,
+		Cover:        converter.CoverOptions{Size: cover, Crop: coverCrop},
+		FillAltText:  fillAltText,
+		AnimatedGIF:  animatedGIF,
+		Strict:       strict,
+		CheckA11y:    checkA11y,
+		EPUBCheck:    epubcheck,
+		RemoteImages: converter.RemoteImages{
+			Download: downloadImgs,
+			Timeout:  fetchTimeout,
+			MaxSize:  int64(remoteMaxMB) << 20,
+			Allow:    remoteAllow,
+			Deny:     remoteDeny,
+		},
+		Scripts: parser.ScriptPolicy{
+			Allowed: allowScripts,
+			Inline:  inlineScript,
+		},
+		EPUB: epub.Options{
+			NavTitle:       navTitle,
+			TOCDepth:       tocDepth,
+			TOCPage:        tocPage,
+			NumberSections: numberSects,
+			DropCaps:       dropCaps,
+			ChapterSpacing: chapterSpace,
+			PageBreaks:     pageBreaks,
+			Version:        version,
+			DefaultCSS:     defaultCSS,
+			CodeTheme:      theme,
+			Math:           mathMode,
+			Profile:        profile,
+		},
+	}
+
+	if outputDir != "" {
+		if outputPath != "" {
+			return fmt.Errorf("--output-dir cannot be combined with --output; use --unpacked -o DIR")
+		}
+		opts.OutputPath = outputDir
+		opts.Unpacked = true
+	}
+	opts.Unpacked = opts.Unpacked || unpacked
+	if opts.Unpacked && emitIR != "" {
+		return fmt.Errorf("--output-dir and --unpacked write an EPUB, so they cannot be combined with --emit-ir")
+	}
+
+	if manifest {
+		if emitIR != "" {
+			return fmt.Errorf("--manifest describes a built EPUB, so it cannot be combined with --emit-ir")
+		}
+		opts.Manifest = buildManifestOptions(flags)
+	}
+
+	if nameTemplate != "" {
+		if outputPath != "" || outputDir != "" || emitIR != "" {
+			return fmt.Errorf("--output-template names the EPUB, so it cannot be combined with --output, --output-dir, or --emit-ir")
+		}
+		if err := converter.CheckOutputTemplate(nameTemplate); err != nil {
+			return fmt.Errorf("invalid --output-template: %w", err)
+		}
+		opts.NameTemplate = nameTemplate
+	}
+
+	if fromIR && (len(args) != 1 || args[0] == "-" || emitIR != "") {
+		return fmt.Errorf("--from-ir takes a single document JSON file and cannot be combined with --emit-ir")
 	}
 
 	// Handle stdin input
 	if len(args) == 1 && args[0] == "-" {
+		if watch {
+			return fmt.Errorf("--watch needs input files; it cannot watch stdin")
+		}
 		return handleStdinInput(cmd, opts)
 	}
 
 	// Resolve output path if not specified
-	if opts.OutputPath == "" {
+	if opts.OutputPath == "" && opts.NameTemplate == "" {
 		opts.OutputPath = resolveDefaultOutputPath(args)
+		if profile == epub.ProfileKobo {
+			// Kobo readers only use kepub features in .kepub.epub files
+			opts.OutputPath = strings.TrimSuffix(opts.OutputPath, ".epub") + ".kepub.epub"
+		}
+		if opts.Unpacked {
+			opts.OutputPath = strings.TrimSuffix(opts.OutputPath, ".epub")
+		}
 	}
 
 	// Print progress for human output
-	if outputFmt != "json" {
+	if outputFmt != "json" && !quiet {
 		printInputSummary(cmd, args)
 	}
 
 	// Create converter and run conversion
 	conv := converter.New()
-	result, err := conv.Convert(args, opts)
-	if err != nil {
-		return handleConvertError(cmd, err)
+	var convert convertFunc = conv.Convert
+	if fromIR {
+		convert = func(inputs []string, opts converter.Options) (*model.ConversionResult, error) {
+			return conv.ConvertIR(inputs[0], opts)
+		}
 	}
+	if watch {
+		return watchInputs(cmd, args, opts, convert)
+	}
+	result, err := convert(args, opts)
+	return finishConversion(cmd, result, err)
+}
 
-	// Output result
-	return outputResult(cmd, result)
+// buildManifestOptions records this toepub version and the flags given on
+// the command line, with their values, in the build manifest.
+func buildManifestOptions(flags *pflag.FlagSet) *converter.ManifestOptions {
+	given := make(map[string]any)
+	flags.Visit(func(f *pflag.Flag) {
+		if values, ok := f.Value.(pflag.SliceValue); ok {
+			given[f.Name] = values.GetSlice()
+			return
+		}
+		given[f.Name] = f.Value.String()
+	})
+	return &converter.ManifestOptions{Generator: "toepub " + version, Options: given}
 }
 
 // printInputSummary shows what files are being converted
@@ -132,17 +536,17 @@ func printInputSummary(cmd *cobra.Command, inputs []string) {
 	if len(inputs) == 1 {
 		info, err := os.Stat(inputs[0])
 		if err == nil && info.IsDir() {
-			cmd.PrintErrf("Converting directory: %s\n", inputs[0])
+			cmd.PrintErrf(msg.ConvertingDir+"\n", inputs[0])
 		} else {
-			cmd.PrintErrf("Converting: %s\n", inputs[0])
+			cmd.PrintErrf(msg.Converting+"\n", inputs[0])
 		}
 	} else {
-		cmd.PrintErrf("Converting %d files...\n", len(inputs))
+		cmd.PrintErrf(msg.ConvertingFiles+"\n", len(inputs))
 	}
 }
 
 // buildCLIMetadata creates metadata from CLI flags
-func buildCLIMetadata() *model.Metadata {
+func buildCLIMetadata() (*model.Metadata, error) {
 	meta := model.NewMetadata()
 
 	if title != "" {
@@ -151,14 +555,131 @@ func buildCLIMetadata() *model.Metadata {
 	if author != "" {
 		meta.Authors = []string{author}
 	}
-	if language != "" {
-		meta.Language = language
+	if len(languages) > 0 {
+		meta.Language = languages[0]
+		meta.Languages = languages[1:]
 	}
 	if coverImage != "" {
 		meta.CoverImage = coverImage
 	}
+	meta.Publisher = publisher
+	meta.Description = description
+	meta.Rights = rights
+	if license != "" {
+		preset, ok := model.LookupLicense(license)
+		if !ok {
+			return nil, fmt.Errorf("invalid --license %q: expected one of %s", license, strings.Join(model.LicenseNames(), ", "))
+		}
+		meta.License = preset.ID
+	}
+	meta.Subjects = subjects
+
+	if pubDate != "" {
+		date, err := parseDate(pubDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --date %q: expected YYYY-MM-DD, YYYY-MM, or YYYY", pubDate)
+		}
+		meta.Date = date
+	}
+
+	for _, c := range contributors {
+		contributor, ok := model.ParseContributor(c)
+		if !ok {
+			return nil, fmt.Errorf("invalid --contributor %q: expected ROLE:NAME with a role such as editor or a MARC relator code such as trl", c)
+		}
+		meta.Contributors = append(meta.Contributors, contributor)
+	}
+
+	for _, id := range identifiers {
+		meta.AddIdentifier(model.ParseIdentifier(id))
+	}
+	if isbn != "" {
+		if _, err := model.NormalizeISBN(isbn); err != nil {
+			return nil, fmt.Errorf("invalid --isbn: %w", err)
+		}
+		meta.AddIdentifier(model.Identifier{Scheme: model.SchemeISBN, Value: isbn})
+	}
+
+	collections, err := buildCollections()
+	if err != nil {
+		return nil, err
+	}
+	meta.Collections = collections
+	if uniqueID != "" {
+		meta.UniqueID = strings.ToLower(uniqueID)
+	}
+
+	meta.Direction, err = model.ParseDirection(direction)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --direction %q: must be ltr, rtl or default", direction)
+	}
+
+	rendition, err := buildRendition()
+	if err != nil {
+		return nil, err
+	}
+	meta.Rendition = rendition
+
+	return meta, nil
+}
+
+// buildCollections creates the series and collections from the collection flags
+func buildCollections() ([]model.Collection, error) {
+	var result []model.Collection
+
+	if seriesIndex != "" {
+		if series == "" {
+			return nil, fmt.Errorf("--series-index requires --series")
+		}
+		if _, err := strconv.ParseFloat(seriesIndex, 64); err != nil {
+			return nil, fmt.Errorf("invalid --series-index %q: expected a number", seriesIndex)
+		}
+	}
+	if series != "" {
+		result = append(result, model.Collection{Name: series, Type: model.CollectionSeries, Position: seriesIndex})
+	}
+
+	for _, name := range collections {
+		result = append(result, model.Collection{Name: name})
+	}
+	return result, nil
+}
 
-	return meta
+// parseDate parses a publication date given as a full date, a month, or a
+// year. Partial dates refer to their first day.
+func parseDate(s string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{"2006-01-02", "2006-01", "2006", time.RFC3339} {
+		var date time.Time
+		if date, err = time.Parse(layout, s); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// buildRendition creates rendition properties from the layout flags
+func buildRendition() (model.Rendition, error) {
+	var rendition model.Rendition
+	var err error
+
+	if rendition.Layout, err = model.ParseLayout(layout); err != nil {
+		return rendition, fmt.Errorf("invalid --layout %q: must be reflowable or pre-paginated", layout)
+	}
+	if rendition.Spread, err = model.ParseSpread(spread); err != nil {
+		return rendition, fmt.Errorf("invalid --spread %q: must be none, landscape, both or auto", spread)
+	}
+	if rendition.Orientation, err = model.ParseOrientation(orientation); err != nil {
+		return rendition, fmt.Errorf("invalid --orientation %q: must be auto, landscape or portrait", orientation)
+	}
+
+	if viewport != "" {
+		if rendition.ViewportWidth, rendition.ViewportHeight, err = model.ParseViewport(viewport); err != nil {
+			return rendition, fmt.Errorf("invalid --viewport %q: expected WIDTHxHEIGHT", viewport)
+		}
+	}
+
+	return rendition, nil
 }
 
 // handleStdinInput handles conversion from stdin
@@ -175,17 +696,16 @@ func handleStdinInput(cmd *cobra.Command, opts converter.Options) error {
 	}
 
 	// Set default output path for stdin
-	if opts.OutputPath == "" {
+	if opts.OutputPath == "" && opts.NameTemplate == "" {
 		opts.OutputPath = "output.epub"
+		if opts.Unpacked {
+			opts.OutputPath = "output"
+		}
 	}
 
 	conv := converter.New()
 	result, err := conv.ConvertContent(content, opts)
-	if err != nil {
-		return handleConvertError(cmd, err)
-	}
-
-	return outputResult(cmd, result)
+	return finishConversion(cmd, result, err)
 }
 
 // readStdin reads all content from stdin
@@ -222,8 +742,11 @@ func resolveDefaultOutputPath(inputs []string) string {
 			// Directory: use directory name
 			return filepath.Base(input) + ".epub"
 		}
-		// File: replace extension
-		ext := filepath.Ext(input)
+		// File: replace extension, such as .tar.gz for archives
+		ext := converter.ArchiveExtension(input)
+		if ext == "" {
+			ext = filepath.Ext(input)
+		}
 		return strings.TrimSuffix(input, ext) + ".epub"
 	}
 
@@ -256,32 +779,34 @@ func determineExitCode(err error) int {
 	if err == nil {
 		return ExitSuccess
 	}
+	return classifyError(err).exit
+}
 
-	errStr := err.Error()
-
-	if strings.Contains(errStr, "file not found") ||
-		strings.Contains(errStr, "no such file") {
-		return ExitFileNotFound
-	}
-
-	if strings.Contains(errStr, "unsupported format") ||
-		strings.Contains(errStr, "unknown format") {
-		return ExitFormatError
+// finishConversion outputs the result of a conversion, or its error. A
+// book that epubcheck found errors in was still written, so it is output
+// with epubcheck's findings before exiting with ExitFormatError.
+func finishConversion(cmd *cobra.Command, result *model.ConversionResult, err error) error {
+	if errors.Is(err, converter.ErrEPUBCheck) {
+		_ = outputResult(cmd, result)
+		if outputFmt != "json" {
+			cmd.PrintErrf("%s %s\n", symbolError, err)
+		}
+		os.Exit(ExitFormatError)
 	}
-
-	if strings.Contains(errStr, "permission denied") ||
-		strings.Contains(errStr, "not writable") {
-		return ExitNotWritable
+	if err != nil {
+		return handleConvertError(cmd, err)
 	}
-
-	return ExitGeneralError
+	return outputResult(cmd, result)
 }
 
 // outputResult outputs the conversion result in the appropriate format
 func outputResult(cmd *cobra.Command, result *model.ConversionResult) error {
-	if outputFmt == "json" {
+	switch {
+	case outputFmt == "json":
 		outputJSON(cmd, result)
-	} else {
+	case quiet:
+		printWarnings(cmd, result.Warnings)
+	default:
 		outputHuman(cmd, result)
 	}
 	return nil
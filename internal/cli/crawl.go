@@ -0,0 +1,86 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// Crawl flags
+var (
+	crawlDepth    int
+	crawlMaxPages int
+)
+
+// crawlCmd represents the crawl command
+var crawlCmd = &cobra.Command{
+	Use:   "crawl <url> [flags]",
+	Short: "Convert a website into an EPUB for offline reading",
+	Long: `Download the pages of a website, following links on the same site
+from the start URL, and convert them into a single EPUB with their images
+and stylesheets.
+
+Each page becomes a chapter holding its main content (its main element or
+article, without navigation menus). Chapters follow the order of the
+site's navigation menus; pages no menu lists come after, in the order they
+were found. Links between the downloaded pages lead to their chapters, and
+links to other pages stay on the web.`,
+	Example: `  # A documentation site, two links deep
+  toepub crawl https://docs.example.com --depth 2
+
+  # A single article
+  toepub crawl https://example.com/post --depth 0 -o post.epub`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCrawl,
+}
+
+func init() {
+	rootCmd.AddCommand(crawlCmd)
+
+	crawlCmd.Flags().IntVar(&crawlDepth, "depth", 1, "Links followed away from the start page (0 converts the start page alone)")
+	crawlCmd.Flags().IntVar(&crawlMaxPages, "max-pages", 200, "Most pages converted")
+	crawlCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Output file path (default: HOST.epub)")
+	crawlCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+	crawlCmd.Flags().StringVarP(&title, "title", "t", "", "Override book title (default: the site name or first page title)")
+	crawlCmd.Flags().StringVarP(&author, "author", "a", "", "Override author name")
+	crawlCmd.Flags().StringArrayVarP(&languages, "language", "l", nil, "Book language (BCP 47 code)")
+	crawlCmd.Flags().StringVarP(&coverImage, "cover", "c", "", "Cover image path")
+}
+
+// runCrawl executes the crawl command
+func runCrawl(cmd *cobra.Command, args []string) error {
+	if crawlDepth < 0 {
+		return fmt.Errorf("invalid --depth %d: must be 0 or more", crawlDepth)
+	}
+	if crawlMaxPages < 1 {
+		return fmt.Errorf("invalid --max-pages %d: must be 1 or more", crawlMaxPages)
+	}
+
+	cliMeta, err := buildCLIMetadata()
+	if err != nil {
+		return err
+	}
+
+	if outputFmt != "json" && !quiet {
+		cmd.PrintErrf(msg.Crawling+"\n", args[0])
+	}
+
+	conv := converter.New()
+	result, err := conv.Crawl(args[0], converter.CrawlOptions{
+		Depth:    crawlDepth,
+		MaxPages: crawlMaxPages,
+	}, converter.Options{
+		OutputPath:  outputPath,
+		CLIMetadata: cliMeta,
+	})
+	return finishConversion(cmd, result, err)
+}
@@ -0,0 +1,89 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// Error types reported in JSON output. They are stable, so wrappers can
+// match on them instead of on error messages.
+const (
+	ErrorTypeGeneral         = "error"
+	ErrorTypeNoInput         = "no_input"
+	ErrorTypeFileNotFound    = "file_not_found"
+	ErrorTypeNotWritable     = "not_writable"
+	ErrorTypeUnsupportedFmt  = "unsupported_format"
+	ErrorTypeInvalidEPUB     = "invalid_epub"
+	ErrorTypeInvalidDocument = "invalid_document"
+	ErrorTypeInvalidMetadata = "invalid_metadata"
+	ErrorTypeParse           = "parse_error"
+	ErrorTypeMemoryLimit     = "memory_limit"
+	ErrorTypeInvalidProject  = "invalid_project"
+	ErrorTypeCrawl           = "crawl_error"
+)
+
+// errorClass is the exit code and JSON error type for a kind of error.
+type errorClass struct {
+	exit int
+	kind string
+}
+
+// errorClasses maps sentinel errors to their class. The first match wins,
+// so a parse error caused by a missing file reports file_not_found.
+var errorClasses = []struct {
+	err   error
+	class errorClass
+}{
+	{converter.ErrNoInput, errorClass{ExitInvalidArgs, ErrorTypeNoInput}},
+	{converter.ErrFileNotFound, errorClass{ExitFileNotFound, ErrorTypeFileNotFound}},
+	{converter.ErrImageNotFound, errorClass{ExitFileNotFound, ErrorTypeFileNotFound}},
+	{converter.ErrMediaNotFound, errorClass{ExitFileNotFound, ErrorTypeFileNotFound}},
+	{fs.ErrNotExist, errorClass{ExitFileNotFound, ErrorTypeFileNotFound}},
+	{converter.ErrOutputNotWrite, errorClass{ExitNotWritable, ErrorTypeNotWritable}},
+	{fs.ErrPermission, errorClass{ExitNotWritable, ErrorTypeNotWritable}},
+	{converter.ErrUnsupportedFmt, errorClass{ExitFormatError, ErrorTypeUnsupportedFmt}},
+	{converter.ErrUnsupportedImage, errorClass{ExitFormatError, ErrorTypeUnsupportedFmt}},
+	{converter.ErrUnsupportedMedia, errorClass{ExitFormatError, ErrorTypeUnsupportedFmt}},
+	{parser.ErrUnsupportedBibliography, errorClass{ExitFormatError, ErrorTypeUnsupportedFmt}},
+	{epub.ErrUnsupportedVersion, errorClass{ExitFormatError, ErrorTypeUnsupportedFmt}},
+	{parser.ErrInvalidEPUB, errorClass{ExitFormatError, ErrorTypeInvalidEPUB}},
+	{epub.ErrInvalidPackage, errorClass{ExitFormatError, ErrorTypeInvalidEPUB}},
+	{epub.ErrInvalidDocument, errorClass{ExitFormatError, ErrorTypeInvalidDocument}},
+	{epub.ErrMissingTitle, errorClass{ExitFormatError, ErrorTypeInvalidDocument}},
+	{epub.ErrNoChapters, errorClass{ExitFormatError, ErrorTypeInvalidDocument}},
+	{epub.ErrMalformedXHTML, errorClass{ExitFormatError, ErrorTypeInvalidDocument}},
+	{epub.ErrBrokenLink, errorClass{ExitFormatError, ErrorTypeInvalidDocument}},
+	{model.ErrInvalidISBN, errorClass{ExitFormatError, ErrorTypeInvalidMetadata}},
+	{model.ErrUnknownUniqueID, errorClass{ExitFormatError, ErrorTypeInvalidMetadata}},
+	{converter.ErrEPUBCheck, errorClass{ExitFormatError, ErrorTypeInvalidEPUB}},
+	{converter.ErrMemoryLimit, errorClass{ExitGeneralError, ErrorTypeMemoryLimit}},
+	{converter.ErrInvalidProject, errorClass{ExitInvalidArgs, ErrorTypeInvalidProject}},
+	{converter.ErrCrawl, errorClass{ExitGeneralError, ErrorTypeCrawl}},
+}
+
+// classifyError returns the exit code and JSON error type for err.
+func classifyError(err error) errorClass {
+	for _, c := range errorClasses {
+		if errors.Is(err, c.err) {
+			return c.class
+		}
+	}
+
+	var parseErr *converter.ParseError
+	if errors.As(err, &parseErr) {
+		return errorClass{ExitFormatError, ErrorTypeParse}
+	}
+	return errorClass{ExitGeneralError, ErrorTypeGeneral}
+}
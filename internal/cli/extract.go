@@ -0,0 +1,73 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// extractCmd represents the extract command
+var extractCmd = &cobra.Command{
+	Use:   "extract <book.epub> [flags]",
+	Short: "Extract an EPUB into editable Markdown sources",
+	Long: `Extract an EPUB into Markdown sources for editing.
+
+Writes one Markdown file per chapter, an images directory, and
+00-metadata.md holding the book metadata as front matter. Converting the
+directory again with "toepub convert" rebuilds the book.`,
+	Example: `  # Extract to ./book
+  toepub extract book.epub
+
+  # Edit and rebuild
+  toepub extract book.epub -o src
+  toepub convert src/ -o book.epub`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExtract,
+}
+
+// extractOutput is the extract output directory
+var extractOutput string
+
+func init() {
+	rootCmd.AddCommand(extractCmd)
+
+	extractCmd.Flags().StringVarP(&extractOutput, "output", "o", "", "Output directory (default: input name without extension)")
+	extractCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// runExtract executes the extract command
+func runExtract(cmd *cobra.Command, args []string) error {
+	outputDir := extractOutput
+	if outputDir == "" {
+		outputDir = strings.TrimSuffix(args[0], filepath.Ext(args[0]))
+	}
+
+	conv := converter.New()
+	result, err := conv.Extract(args[0], outputDir)
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if outputFmt == "json" || quiet {
+		return outputResult(cmd, result)
+	}
+
+	// Every chapter and image is a file, besides the metadata file
+	printWarnings(cmd, result.Warnings)
+	files := result.Stats.ChapterCount + result.Stats.ImageCount + 1
+	cmd.Printf("%s "+msg.Extracted+"\n", symbolSuccess, files, result.OutputPath)
+	cmd.Printf("  - "+msg.ChapterCount+"\n", result.Stats.ChapterCount)
+	cmd.Printf("  - "+msg.ImageCount+"\n", result.Stats.ImageCount)
+	cmd.Printf("  - "+msg.Duration+"\n", result.Stats.Duration.Seconds())
+	return nil
+}
@@ -0,0 +1,437 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// messages holds the CLI's human-readable output in one language. Most are
+// fmt formats taking the arguments noted; JSON output is never translated.
+type messages struct {
+	Converting      string // Input file
+	ConvertingDir   string // Input directory
+	ConvertingFiles string // Number of input files
+	Crawling        string // Start URL
+	Created         string // Output path, size in KB
+	CreatedProject  string // Project directory
+	ChapterCount    string // Number of chapters
+	ImageCount      string // Number of images
+	Duration        string // Seconds
+	Watching        string
+	Rebuilding      string
+	Packed          string // Directory, output file
+	Extracted       string // Number of files, output directory
+	Updated         string // EPUB file
+	NoProblems      string // EPUB file
+	ProblemFound    string // Number of problems (1), EPUB file
+	ProblemsFound   string // Number of problems, EPUB file
+	NextStep        string // Project directory
+	Listening       string // Listen address
+	StatsFor        string // Input, input format
+	InputFiles      string
+	Chapters        string
+	Headings        string
+	Words           string
+	Images          string
+	PredictedSize   string
+	MissingCount    string // Number of images, number missing
+	Missing         string // Missing image
+
+	// Labels, each ending in its colon
+	Warning string
+	Note    string
+	Error   string
+
+	// ErrorKinds names each JSON error type, printed above the error itself,
+	// which is in English. English messages have none.
+	ErrorKinds map[string]string
+}
+
+// catalog maps primary language subtags to CLI messages.
+var catalog = map[string]messages{
+	"en": {
+		Converting:      "Converting: %s",
+		ConvertingDir:   "Converting directory: %s",
+		ConvertingFiles: "Converting %d files...",
+		Crawling:        "Crawling: %s",
+		Created:         "Created %s (%d KB)",
+		CreatedProject:  "Created %s",
+		ChapterCount:    "%d chapters",
+		ImageCount:      "%d images",
+		Duration:        "Duration: %.1fs",
+		Watching:        "Watching for changes (press Ctrl+C to stop)...",
+		Rebuilding:      "Change detected, rebuilding...",
+		Packed:          "Packed %s into %s",
+		Extracted:       "Extracted %d files to %s",
+		Updated:         "Updated %s",
+		NoProblems:      "No problems found in %s",
+		ProblemFound:    "%d problem found in %s",
+		ProblemsFound:   "%d problems found in %s",
+		NextStep:        "Next: cd %s && toepub build",
+		Listening:       "Listening on %s",
+		StatsFor:        "Stats for %s (%s)",
+		InputFiles:      "Input files",
+		Chapters:        "Chapters",
+		Headings:        "Headings",
+		Words:           "Words",
+		Images:          "Images",
+		PredictedSize:   "Predicted size",
+		MissingCount:    "%d (%d missing)",
+		Missing:         "Missing: %s",
+		Warning:         "Warning:",
+		Note:            "Note:",
+		Error:           "Error:",
+	},
+	"de": {
+		Converting:      "Konvertiere: %s",
+		ConvertingDir:   "Konvertiere Verzeichnis: %s",
+		ConvertingFiles: "Konvertiere %d Dateien...",
+		Crawling:        "Durchsuche: %s",
+		Created:         "%s erstellt (%d KB)",
+		CreatedProject:  "%s erstellt",
+		ChapterCount:    "%d Kapitel",
+		ImageCount:      "%d Bilder",
+		Duration:        "Dauer: %.1f s",
+		Watching:        "Überwache Änderungen (Strg+C zum Beenden)...",
+		Rebuilding:      "Änderung erkannt, erstelle neu...",
+		Packed:          "%s in %s gepackt",
+		Extracted:       "%d Dateien nach %s extrahiert",
+		Updated:         "%s aktualisiert",
+		NoProblems:      "Keine Probleme in %s gefunden",
+		ProblemFound:    "%d Problem in %s gefunden",
+		ProblemsFound:   "%d Probleme in %s gefunden",
+		NextStep:        "Weiter: cd %s && toepub build",
+		Listening:       "Warte auf Verbindungen unter %s",
+		StatsFor:        "Statistik für %s (%s)",
+		InputFiles:      "Eingabedateien",
+		Chapters:        "Kapitel",
+		Headings:        "Überschriften",
+		Words:           "Wörter",
+		Images:          "Bilder",
+		PredictedSize:   "Erwartete Größe",
+		MissingCount:    "%d (%d fehlen)",
+		Missing:         "Fehlt: %s",
+		Warning:         "Warnung:",
+		Note:            "Hinweis:",
+		Error:           "Fehler:",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "Keine Eingabe",
+			ErrorTypeFileNotFound:    "Datei nicht gefunden",
+			ErrorTypeNotWritable:     "Ausgabe nicht beschreibbar",
+			ErrorTypeUnsupportedFmt:  "Nicht unterstütztes Format",
+			ErrorTypeInvalidEPUB:     "Ungültiges EPUB",
+			ErrorTypeInvalidDocument: "Ungültiges Dokument",
+			ErrorTypeInvalidMetadata: "Ungültige Metadaten",
+			ErrorTypeParse:           "Eingabe konnte nicht gelesen werden",
+			ErrorTypeMemoryLimit:     "Speicherlimit überschritten",
+			ErrorTypeInvalidProject:  "Ungültiges Projekt",
+			ErrorTypeCrawl:           "Website konnte nicht durchsucht werden",
+		},
+	},
+	"es": {
+		Converting:      "Convirtiendo: %s",
+		ConvertingDir:   "Convirtiendo directorio: %s",
+		ConvertingFiles: "Convirtiendo %d archivos...",
+		Crawling:        "Rastreando: %s",
+		Created:         "Creado %s (%d KB)",
+		CreatedProject:  "Creado %s",
+		ChapterCount:    "%d capítulos",
+		ImageCount:      "%d imágenes",
+		Duration:        "Duración: %.1f s",
+		Watching:        "Vigilando cambios (pulse Ctrl+C para detener)...",
+		Rebuilding:      "Cambio detectado, reconstruyendo...",
+		Packed:          "%s empaquetado en %s",
+		Extracted:       "%d archivos extraídos en %s",
+		Updated:         "%s actualizado",
+		NoProblems:      "No se encontraron problemas en %s",
+		ProblemFound:    "%d problema encontrado en %s",
+		ProblemsFound:   "%d problemas encontrados en %s",
+		NextStep:        "Siguiente: cd %s && toepub build",
+		Listening:       "Escuchando en %s",
+		StatsFor:        "Estadísticas de %s (%s)",
+		InputFiles:      "Archivos",
+		Chapters:        "Capítulos",
+		Headings:        "Encabezados",
+		Words:           "Palabras",
+		Images:          "Imágenes",
+		PredictedSize:   "Tamaño previsto",
+		MissingCount:    "%d (faltan %d)",
+		Missing:         "Falta: %s",
+		Warning:         "Advertencia:",
+		Note:            "Nota:",
+		Error:           "Error:",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "Sin entrada",
+			ErrorTypeFileNotFound:    "Archivo no encontrado",
+			ErrorTypeNotWritable:     "No se puede escribir la salida",
+			ErrorTypeUnsupportedFmt:  "Formato no compatible",
+			ErrorTypeInvalidEPUB:     "EPUB no válido",
+			ErrorTypeInvalidDocument: "Documento no válido",
+			ErrorTypeInvalidMetadata: "Metadatos no válidos",
+			ErrorTypeParse:           "No se pudo analizar la entrada",
+			ErrorTypeMemoryLimit:     "Límite de memoria superado",
+			ErrorTypeInvalidProject:  "Proyecto no válido",
+			ErrorTypeCrawl:           "No se pudo rastrear el sitio",
+		},
+	},
+	"fr": {
+		Converting:      "Conversion : %s",
+		ConvertingDir:   "Conversion du répertoire : %s",
+		ConvertingFiles: "Conversion de %d fichiers...",
+		Crawling:        "Exploration : %s",
+		Created:         "%s créé (%d Ko)",
+		CreatedProject:  "%s créé",
+		ChapterCount:    "%d chapitres",
+		ImageCount:      "%d images",
+		Duration:        "Durée : %.1f s",
+		Watching:        "Surveillance des modifications (Ctrl+C pour arrêter)...",
+		Rebuilding:      "Modification détectée, reconstruction...",
+		Packed:          "%s empaqueté dans %s",
+		Extracted:       "%d fichiers extraits dans %s",
+		Updated:         "%s mis à jour",
+		NoProblems:      "Aucun problème trouvé dans %s",
+		ProblemFound:    "%d problème trouvé dans %s",
+		ProblemsFound:   "%d problèmes trouvés dans %s",
+		NextStep:        "Étape suivante : cd %s && toepub build",
+		Listening:       "En écoute sur %s",
+		StatsFor:        "Statistiques de %s (%s)",
+		InputFiles:      "Fichiers",
+		Chapters:        "Chapitres",
+		Headings:        "Titres",
+		Words:           "Mots",
+		Images:          "Images",
+		PredictedSize:   "Taille estimée",
+		MissingCount:    "%d (%d manquantes)",
+		Missing:         "Manquante : %s",
+		Warning:         "Avertissement :",
+		Note:            "Remarque :",
+		Error:           "Erreur :",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "Aucune entrée",
+			ErrorTypeFileNotFound:    "Fichier introuvable",
+			ErrorTypeNotWritable:     "Impossible d'écrire la sortie",
+			ErrorTypeUnsupportedFmt:  "Format non pris en charge",
+			ErrorTypeInvalidEPUB:     "EPUB non valide",
+			ErrorTypeInvalidDocument: "Document non valide",
+			ErrorTypeInvalidMetadata: "Métadonnées non valides",
+			ErrorTypeParse:           "Impossible d'analyser l'entrée",
+			ErrorTypeMemoryLimit:     "Limite de mémoire dépassée",
+			ErrorTypeInvalidProject:  "Projet non valide",
+			ErrorTypeCrawl:           "Impossible d'explorer le site",
+		},
+	},
+	"ja": {
+		Converting:      "変換中: %s",
+		ConvertingDir:   "ディレクトリを変換中: %s",
+		ConvertingFiles: "%d 個のファイルを変換中...",
+		Crawling:        "クロール中: %s",
+		Created:         "%s を作成しました (%d KB)",
+		CreatedProject:  "%s を作成しました",
+		ChapterCount:    "%d 章",
+		ImageCount:      "画像 %d 枚",
+		Duration:        "所要時間: %.1f 秒",
+		Watching:        "変更を監視中 (Ctrl+C で停止)...",
+		Rebuilding:      "変更を検出しました。再ビルド中...",
+		Packed:          "%s を %s にパッケージしました",
+		Extracted:       "%d 個のファイルを %s に展開しました",
+		Updated:         "%s を更新しました",
+		NoProblems:      "%s に問題は見つかりませんでした",
+		ProblemFound:    "%[2]s で %[1]d 件の問題が見つかりました",
+		ProblemsFound:   "%[2]s で %[1]d 件の問題が見つかりました",
+		NextStep:        "次の手順: cd %s && toepub build",
+		Listening:       "%s で待機中",
+		StatsFor:        "%s の統計 (%s)",
+		InputFiles:      "入力ファイル",
+		Chapters:        "章",
+		Headings:        "見出し",
+		Words:           "語数",
+		Images:          "画像",
+		PredictedSize:   "予測サイズ",
+		MissingCount:    "%d (%d 件欠落)",
+		Missing:         "欠落: %s",
+		Warning:         "警告:",
+		Note:            "注意:",
+		Error:           "エラー:",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "入力がありません",
+			ErrorTypeFileNotFound:    "ファイルが見つかりません",
+			ErrorTypeNotWritable:     "出力に書き込めません",
+			ErrorTypeUnsupportedFmt:  "サポートされていない形式です",
+			ErrorTypeInvalidEPUB:     "無効な EPUB です",
+			ErrorTypeInvalidDocument: "無効なドキュメントです",
+			ErrorTypeInvalidMetadata: "無効なメタデータです",
+			ErrorTypeParse:           "入力を解析できません",
+			ErrorTypeMemoryLimit:     "メモリ上限を超えました",
+			ErrorTypeInvalidProject:  "無効なプロジェクトです",
+			ErrorTypeCrawl:           "サイトをクロールできません",
+		},
+	},
+	"vi": {
+		Converting:      "Đang chuyển đổi: %s",
+		ConvertingDir:   "Đang chuyển đổi thư mục: %s",
+		ConvertingFiles: "Đang chuyển đổi %d tệp...",
+		Crawling:        "Đang thu thập: %s",
+		Created:         "Đã tạo %s (%d KB)",
+		CreatedProject:  "Đã tạo %s",
+		ChapterCount:    "%d chương",
+		ImageCount:      "%d hình ảnh",
+		Duration:        "Thời gian: %.1f giây",
+		Watching:        "Đang theo dõi thay đổi (nhấn Ctrl+C để dừng)...",
+		Rebuilding:      "Phát hiện thay đổi, đang tạo lại...",
+		Packed:          "Đã đóng gói %s thành %s",
+		Extracted:       "Đã trích xuất %d tệp vào %s",
+		Updated:         "Đã cập nhật %s",
+		NoProblems:      "Không tìm thấy vấn đề nào trong %s",
+		ProblemFound:    "Tìm thấy %d vấn đề trong %s",
+		ProblemsFound:   "Tìm thấy %d vấn đề trong %s",
+		NextStep:        "Tiếp theo: cd %s && toepub build",
+		Listening:       "Đang lắng nghe tại %s",
+		StatsFor:        "Thống kê cho %s (%s)",
+		InputFiles:      "Tệp đầu vào",
+		Chapters:        "Chương",
+		Headings:        "Tiêu đề",
+		Words:           "Số từ",
+		Images:          "Hình ảnh",
+		PredictedSize:   "Kích thước dự kiến",
+		MissingCount:    "%d (thiếu %d)",
+		Missing:         "Thiếu: %s",
+		Warning:         "Cảnh báo:",
+		Note:            "Ghi chú:",
+		Error:           "Lỗi:",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "Không có đầu vào",
+			ErrorTypeFileNotFound:    "Không tìm thấy tệp",
+			ErrorTypeNotWritable:     "Không thể ghi đầu ra",
+			ErrorTypeUnsupportedFmt:  "Định dạng không được hỗ trợ",
+			ErrorTypeInvalidEPUB:     "EPUB không hợp lệ",
+			ErrorTypeInvalidDocument: "Tài liệu không hợp lệ",
+			ErrorTypeInvalidMetadata: "Siêu dữ liệu không hợp lệ",
+			ErrorTypeParse:           "Không thể phân tích đầu vào",
+			ErrorTypeMemoryLimit:     "Vượt quá giới hạn bộ nhớ",
+			ErrorTypeInvalidProject:  "Dự án không hợp lệ",
+			ErrorTypeCrawl:           "Không thể thu thập trang web",
+		},
+	},
+	"zh": {
+		Converting:      "正在转换: %s",
+		ConvertingDir:   "正在转换目录: %s",
+		ConvertingFiles: "正在转换 %d 个文件...",
+		Crawling:        "正在抓取: %s",
+		Created:         "已创建 %s (%d KB)",
+		CreatedProject:  "已创建 %s",
+		ChapterCount:    "%d 个章节",
+		ImageCount:      "%d 张图片",
+		Duration:        "耗时: %.1f 秒",
+		Watching:        "正在监视更改 (按 Ctrl+C 停止)...",
+		Rebuilding:      "检测到更改，正在重新构建...",
+		Packed:          "已将 %s 打包为 %s",
+		Extracted:       "已将 %d 个文件提取到 %s",
+		Updated:         "已更新 %s",
+		NoProblems:      "在 %s 中未发现问题",
+		ProblemFound:    "在 %[2]s 中发现 %[1]d 个问题",
+		ProblemsFound:   "在 %[2]s 中发现 %[1]d 个问题",
+		NextStep:        "下一步: cd %s && toepub build",
+		Listening:       "正在监听 %s",
+		StatsFor:        "%s 的统计 (%s)",
+		InputFiles:      "输入文件",
+		Chapters:        "章节",
+		Headings:        "标题",
+		Words:           "字数",
+		Images:          "图片",
+		PredictedSize:   "预计大小",
+		MissingCount:    "%d (缺少 %d)",
+		Missing:         "缺少: %s",
+		Warning:         "警告:",
+		Note:            "注意:",
+		Error:           "错误:",
+		ErrorKinds: map[string]string{
+			ErrorTypeNoInput:         "没有输入",
+			ErrorTypeFileNotFound:    "找不到文件",
+			ErrorTypeNotWritable:     "无法写入输出",
+			ErrorTypeUnsupportedFmt:  "不支持的格式",
+			ErrorTypeInvalidEPUB:     "无效的 EPUB",
+			ErrorTypeInvalidDocument: "无效的文档",
+			ErrorTypeInvalidMetadata: "无效的元数据",
+			ErrorTypeParse:           "无法解析输入",
+			ErrorTypeMemoryLimit:     "超出内存限制",
+			ErrorTypeInvalidProject:  "无效的项目",
+			ErrorTypeCrawl:           "无法抓取网站",
+		},
+	},
+}
+
+// defaultMessageLanguage is used when the locale has no translation.
+const defaultMessageLanguage = "en"
+
+// msg holds the messages of the selected language. Execute selects it from
+// the locale, and --lang overrides it once flags are parsed.
+var msg = catalog[defaultMessageLanguage]
+
+// uiLang is the --lang flag.
+var uiLang string
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&uiLang, "lang", "", "Language of messages: "+strings.Join(messageLanguages(), ", ")+" (default: from LC_ALL, LC_MESSAGES, or LANG)")
+}
+
+// messageLanguages returns the languages messages are translated to.
+func messageLanguages() []string {
+	return slices.Sorted(maps.Keys(catalog))
+}
+
+// primaryLanguage returns the lowercase primary subtag of a BCP 47 tag or
+// POSIX locale, such as "vi" for "vi_VN.UTF-8".
+func primaryLanguage(tag string) string {
+	tag = strings.ToLower(tag)
+	if i := strings.IndexAny(tag, "-_.@"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// localeLanguage returns the primary language of the locale set for
+// messages in the environment, checked in the order POSIX gives them
+// precedence, or "" when none is set.
+func localeLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return primaryLanguage(value)
+		}
+	}
+	return ""
+}
+
+// selectMessages selects the messages for the locale, falling back to
+// English when it has no translation, including the C and POSIX locales.
+func selectMessages() {
+	m, ok := catalog[localeLanguage()]
+	if !ok {
+		m = catalog[defaultMessageLanguage]
+	}
+	msg = m
+	rootCmd.SetErrPrefix(msg.Error)
+}
+
+// setupMessages applies --lang, whose language must have a translation.
+func setupMessages() error {
+	if uiLang == "" {
+		return nil
+	}
+	m, ok := catalog[primaryLanguage(uiLang)]
+	if !ok {
+		return fmt.Errorf("invalid --lang %q: must be one of %s", uiLang, strings.Join(messageLanguages(), ", "))
+	}
+	msg = m
+	rootCmd.SetErrPrefix(msg.Error)
+	return nil
+}
@@ -0,0 +1,74 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"encoding/json"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:   "init <directory> [flags]",
+	Short: "Create a starter project for a new book",
+	Long: `Create a starter project for a new book in a new or empty directory:
+
+  book.yaml            Project file read by "toepub build"
+  chapters/            Markdown chapters, read in alphabetical order
+  images/cover.png     Placeholder cover, 1600x2560
+  styles/custom.css    Stylesheet linked from every chapter
+
+The title defaults to one made from the directory name.`,
+	Example: `  # Start a book and build it
+  toepub init my-book --author "Jane Doe"
+  cd my-book && toepub build`,
+	Args: cobra.ExactArgs(1),
+	RunE: runInit,
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	initCmd.Flags().StringVarP(&title, "title", "t", "", "Book title (default: made from the directory name)")
+	initCmd.Flags().StringVarP(&author, "author", "a", "", "Author name")
+	initCmd.Flags().StringArrayVarP(&languages, "language", "l", nil, "Book language (BCP 47 code, default \"en\")")
+	initCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// runInit executes the init command
+func runInit(cmd *cobra.Command, args []string) error {
+	meta := model.Metadata{Title: title}
+	if author != "" {
+		meta.Authors = []string{author}
+	}
+	if len(languages) > 0 {
+		meta.Language = languages[0]
+	}
+
+	files, err := converter.Scaffold(args[0], meta)
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	switch {
+	case outputFmt == "json":
+		data, _ := json.MarshalIndent(jsonOutput{Success: true, Output: args[0], Files: files}, "", "  ")
+		cmd.Println(string(data))
+	case !quiet:
+		cmd.Printf("%s "+msg.CreatedProject+"\n", symbolSuccess, args[0])
+		for _, file := range files {
+			cmd.Printf("  - %s\n", file)
+		}
+		cmd.Printf("\n"+msg.NextStep+"\n", args[0])
+	}
+	return nil
+}
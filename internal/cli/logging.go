@@ -0,0 +1,80 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Logging flags, shared by all commands
+var (
+	verbosity int
+	quiet     bool
+	logLevel  string
+)
+
+func init() {
+	rootCmd.PersistentFlags().CountVarP(&verbosity, "verbose", "v", "Log pipeline stages (-v) or every step (-vv) to stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Print only warnings and errors")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Log level: debug, info, warn, error (overrides -v and -q)")
+	rootCmd.PersistentPreRunE = setupCommand
+}
+
+// setupCommand applies the flags shared by all commands: the language of
+// messages and the logging flags.
+func setupCommand(cmd *cobra.Command, args []string) error {
+	if err := setupMessages(); err != nil {
+		return err
+	}
+	return setupLogging(cmd, args)
+}
+
+// setupLogging sends the pipeline's structured logs to stderr at the level
+// selected by the logging flags.
+func setupLogging(cmd *cobra.Command, args []string) error {
+	level, err := resolveLogLevel()
+	if err != nil {
+		return err
+	}
+	handler := slog.NewTextHandler(cmd.ErrOrStderr(), &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// resolveLogLevel returns the log level for the logging flags. Warnings
+// are logged by default; -v adds stage summaries and -vv adds every step.
+func resolveLogLevel() (slog.Level, error) {
+	switch strings.ToLower(logLevel) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	case "":
+	default:
+		return 0, fmt.Errorf("invalid --log-level %q: must be debug, info, warn or error", logLevel)
+	}
+
+	switch {
+	case quiet:
+		return slog.LevelError, nil
+	case verbosity >= 2:
+		return slog.LevelDebug, nil
+	case verbosity == 1:
+		return slog.LevelInfo, nil
+	default:
+		return slog.LevelWarn, nil
+	}
+}
@@ -0,0 +1,150 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// metaCmd groups commands that work on the metadata of existing EPUBs
+var metaCmd = &cobra.Command{
+	Use:   "meta",
+	Short: "Read and edit the metadata of existing EPUB files",
+}
+
+// metaSetCmd represents the meta set command
+var metaSetCmd = &cobra.Command{
+	Use:   "set <book.epub> [flags]",
+	Short: "Change metadata and cover image of an existing EPUB",
+	Long: `Change the metadata and cover image of an existing EPUB in place.
+
+Only the package document and cover image are rewritten; content documents
+are copied unchanged, so the source documents are not needed. The file is
+replaced only after the updated EPUB has been written completely.`,
+	Example: `  # Fix the title and author
+  toepub meta set book.epub --title "My Book" --author "Jane Doe"
+
+  # Replace the cover image
+  toepub meta set book.epub --cover new.jpg`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMetaSet,
+}
+
+// metaGetCmd represents the meta get command
+var metaGetCmd = &cobra.Command{
+	Use:   "get <book.epub> [flags]",
+	Short: "Print the metadata of an existing EPUB",
+	Long: `Print the metadata of an existing EPUB, read from its package document.
+
+JSON and YAML output list identifiers, titles, creators, and contributors with
+their refinements (such as role and file-as), the other Dublin Core elements,
+collections, and any further meta properties. OPF output prints the metadata
+element as written in the package document.`,
+	Example: `  # Print the metadata as JSON
+  toepub meta get book.epub
+
+  # Print the metadata element of the package document
+  toepub meta get book.epub --format opf`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMetaGet,
+}
+
+// metaGetFormat is the output format of meta get
+var metaGetFormat string
+
+// Meta set flags
+var (
+	metaTitle       string
+	metaAuthors     []string
+	metaLanguage    string
+	metaPublisher   string
+	metaDescription string
+	metaCover       string
+)
+
+func init() {
+	rootCmd.AddCommand(metaCmd)
+	metaCmd.AddCommand(metaGetCmd)
+	metaCmd.AddCommand(metaSetCmd)
+
+	metaGetCmd.Flags().StringVarP(&metaGetFormat, "format", "f", "json", "Output format: json, yaml, or opf")
+
+	metaSetCmd.Flags().StringVarP(&metaTitle, "title", "t", "", "New book title")
+	metaSetCmd.Flags().StringArrayVarP(&metaAuthors, "author", "a", nil, "Author name, replacing all existing authors (repeatable)")
+	metaSetCmd.Flags().StringVarP(&metaLanguage, "language", "l", "", "New book language (BCP 47 code)")
+	metaSetCmd.Flags().StringVar(&metaPublisher, "publisher", "", "New publisher name")
+	metaSetCmd.Flags().StringVar(&metaDescription, "description", "", "New book description")
+	metaSetCmd.Flags().StringVarP(&metaCover, "cover", "c", "", "New cover image path")
+}
+
+// runMetaGet executes the meta get command
+func runMetaGet(cmd *cobra.Command, args []string) error {
+	switch metaGetFormat {
+	case "json", "yaml", "opf":
+	default:
+		return fmt.Errorf("invalid --format %q: expected json, yaml, or opf", metaGetFormat)
+	}
+
+	conv := converter.New()
+	meta, err := conv.ReadMetadata(args[0])
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	// Metadata goes to stdout, to be piped into cataloging tools
+	out := cmd.OutOrStdout()
+	switch metaGetFormat {
+	case "yaml":
+		return yaml.NewEncoder(out).Encode(meta)
+	case "opf":
+		_, err := fmt.Fprintln(out, meta.OPF)
+		return err
+	default:
+		enc := json.NewEncoder(out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(meta)
+	}
+}
+
+// runMetaSet executes the meta set command
+func runMetaSet(cmd *cobra.Command, args []string) error {
+	changed := false
+	for _, name := range []string{"title", "author", "language", "publisher", "description", "cover"} {
+		changed = changed || cmd.Flags().Changed(name)
+	}
+	if !changed {
+		return fmt.Errorf("no changes given: set at least one of --title, --author, --language, --publisher, --description, --cover")
+	}
+
+	meta := &model.Metadata{
+		Title:       metaTitle,
+		Authors:     metaAuthors,
+		Language:    metaLanguage,
+		Publisher:   metaPublisher,
+		Description: metaDescription,
+		CoverImage:  metaCover,
+	}
+
+	conv := converter.New()
+	if err := conv.UpdateMetadata(args[0], meta); err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if !quiet {
+		cmd.Printf("%s "+msg.Updated+"\n", symbolSuccess, args[0])
+	}
+	return nil
+}
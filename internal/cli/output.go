@@ -31,16 +31,46 @@ func outputHuman(cmd *cobra.Command, result *model.ConversionResult) {
 	}
 
 	// Print warnings first
-	for _, warning := range result.Warnings {
-		cmd.PrintErrf("%s Warning: %s\n", symbolWarning, warning)
-	}
+	printWarnings(cmd, result.Warnings)
 
 	// Print success message
 	sizeKB := result.Stats.OutputSize / 1024
-	cmd.Printf("%s Created %s (%d KB)\n", symbolSuccess, result.OutputPath, sizeKB)
-	cmd.Printf("  - %d chapters\n", result.Stats.ChapterCount)
-	cmd.Printf("  - %d images\n", result.Stats.ImageCount)
-	cmd.Printf("  - Duration: %.1fs\n", result.Stats.Duration.Seconds())
+	cmd.Printf("%s "+msg.Created+"\n", symbolSuccess, result.OutputPath, sizeKB)
+	cmd.Printf("  - "+msg.ChapterCount+"\n", result.Stats.ChapterCount)
+	cmd.Printf("  - "+msg.ImageCount+"\n", result.Stats.ImageCount)
+	cmd.Printf("  - "+msg.Duration+"\n", result.Stats.Duration.Seconds())
+}
+
+// printWarnings prints warnings to stderr, labeled by severity. Warnings
+// sharing a code are grouped under one heading, in order of first appearance
+func printWarnings(cmd *cobra.Command, warnings []model.Warning) {
+	var order []string
+	groups := make(map[string][]model.Warning)
+	for _, warning := range warnings {
+		key := warning.Severity + "/" + warning.Code
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], warning)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		label := msg.Warning
+		if group[0].Severity == model.SeverityInfo {
+			label = msg.Note
+		}
+		if len(group) == 1 || group[0].Code == "" {
+			for _, warning := range group {
+				cmd.PrintErrf("%s %s %s\n", symbolWarning, label, warning)
+			}
+			continue
+		}
+		cmd.PrintErrf("%s %s %d × %s\n", symbolWarning, label, len(group), group[0].Code)
+		for _, warning := range group {
+			cmd.PrintErrf("    %s\n", warning)
+		}
+	}
 }
 
 // outputProgress prints progress message during conversion
@@ -48,15 +78,27 @@ func outputProgress(cmd *cobra.Command, message string) {
 	cmd.PrintErrf("%s\n", message)
 }
 
-// outputHumanError prints human-readable error to stderr
+// outputHumanError prints human-readable error to stderr. In languages
+// other than English the kind of error is named in that language first
 func outputHumanError(cmd *cobra.Command, err error) {
 	cmd.PrintErrln()
-	cmd.PrintErrf("%s Error: %s\n", symbolError, err.Error())
+	if kind, ok := msg.ErrorKinds[classifyError(err).kind]; ok {
+		cmd.PrintErrf("%s %s %s\n", symbolError, msg.Error, kind)
+		cmd.PrintErrf("  %s\n", err.Error())
+	} else {
+		cmd.PrintErrf("%s %s %s\n", symbolError, msg.Error, err.Error())
+	}
 	cmd.PrintErrln()
 }
 
 // outputJSON prints JSON output to stdout
 func outputJSON(cmd *cobra.Command, result *model.ConversionResult) {
+	data, _ := json.MarshalIndent(newJSONOutput(result), "", "  ")
+	cmd.Println(string(data))
+}
+
+// newJSONOutput converts a conversion result to its JSON output
+func newJSONOutput(result *model.ConversionResult) jsonOutput {
 	output := jsonOutput{
 		Success: result.Success,
 	}
@@ -71,39 +113,69 @@ func outputJSON(cmd *cobra.Command, result *model.ConversionResult) {
 			OutputSize:  result.Stats.OutputSize,
 			DurationMS:  result.Stats.Duration.Milliseconds(),
 		}
-		output.Warnings = result.Warnings
+		for _, res := range result.Stats.Resources {
+			output.Stats.Resources = append(output.Stats.Resources, jsonResourceSize{File: res.File, Size: res.Size})
+		}
+		for _, warning := range result.Warnings {
+			output.Warnings = append(output.Warnings, jsonWarning{
+				Code:     warning.Code,
+				Severity: warning.Severity,
+				File:     warning.File,
+				Message:  warning.Message,
+			})
+		}
 	} else {
 		output.Error = &jsonError{
 			Code:    determineExitCode(result.Error),
+			Type:    classifyError(result.Error).kind,
 			Message: result.Error.Error(),
 		}
 	}
 
-	data, _ := json.MarshalIndent(output, "", "  ")
-	cmd.Println(string(data))
+	return output
 }
 
 // JSON output structures
 
 type jsonOutput struct {
-	Success  bool        `json:"success"`
-	Output   string      `json:"output,omitempty"`
-	Stats    *jsonStats  `json:"stats,omitempty"`
-	Warnings []string    `json:"warnings,omitempty"`
-	Error    *jsonError  `json:"error,omitempty"`
+	Success  bool          `json:"success"`
+	Output   string        `json:"output,omitempty"`
+	Files    []string      `json:"files,omitempty"`
+	Stats    *jsonStats    `json:"stats,omitempty"`
+	Warnings []jsonWarning `json:"warnings,omitempty"`
+	Error    *jsonError    `json:"error,omitempty"`
+}
+
+type jsonBuildOutput struct {
+	Success bool         `json:"success"`
+	Books   []jsonOutput `json:"books"`
 }
 
 type jsonStats struct {
-	InputFormat string `json:"input_format"`
-	InputFiles  int    `json:"input_files"`
-	Chapters    int    `json:"chapters"`
-	Images      int    `json:"images"`
-	OutputSize  int64  `json:"output_size"`
-	DurationMS  int64  `json:"duration_ms"`
+	InputFormat string             `json:"input_format"`
+	InputFiles  int                `json:"input_files"`
+	Chapters    int                `json:"chapters"`
+	Images      int                `json:"images"`
+	OutputSize  int64              `json:"output_size"`
+	DurationMS  int64              `json:"duration_ms"`
+	Resources   []jsonResourceSize `json:"resources,omitempty"`
+}
+
+type jsonResourceSize struct {
+	File string `json:"file"`
+	Size int64  `json:"size"`
+}
+
+type jsonWarning struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	File     string `json:"file,omitempty"`
+	Message  string `json:"message"`
 }
 
 type jsonError struct {
 	Code    int    `json:"code"`
+	Type    string `json:"type"`
 	Message string `json:"message"`
 	Detail  string `json:"detail,omitempty"`
 }
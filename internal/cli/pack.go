@@ -0,0 +1,69 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// packOutput is the EPUB written by the pack command
+var packOutput string
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack <dir> [flags]",
+	Short: "Zip an unpacked EPUB directory into an EPUB file",
+	Long: `Zip a directory holding the contents of an EPUB, such as one written by
+convert --output-dir or --unpacked and then edited, into an EPUB file.
+
+The mimetype file is stored first and uncompressed, as reading systems
+require, followed by the other files. Hidden files, such as .DS_Store, are
+left out. The directory must have a mimetype file and a
+META-INF/container.xml pointing at its package document.`,
+	Example: `  # Pack build/epub/ into build/epub.epub
+  toepub pack build/epub/
+
+  # Choose the EPUB file
+  toepub pack build/epub/ -o book.epub`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPack,
+}
+
+func init() {
+	rootCmd.AddCommand(packCmd)
+
+	packCmd.Flags().StringVarP(&packOutput, "output", "o", "", "Output EPUB file path (default: DIR.epub)")
+}
+
+// runPack executes the pack command
+func runPack(cmd *cobra.Command, args []string) error {
+	dir := filepath.Clean(args[0])
+	output := packOutput
+	if output == "" {
+		if strings.EqualFold(filepath.Ext(dir), ".epub") {
+			return fmt.Errorf("%s already ends in .epub; name the EPUB file with -o", dir)
+		}
+		output = dir + ".epub"
+	}
+
+	conv := converter.New()
+	if err := conv.Pack(dir, output); err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if !quiet {
+		cmd.Printf("%s "+msg.Packed+"\n", symbolSuccess, dir, output)
+	}
+	return nil
+}
@@ -40,6 +40,7 @@ Examples:
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
+	selectMessages()
 	return rootCmd.Execute()
 }
 
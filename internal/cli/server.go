@@ -0,0 +1,114 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/server"
+)
+
+// serverCmd runs the HTTP conversion API
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Serve conversions over HTTP",
+	Long: `Serve conversions over HTTP.
+
+POST /convert accepts a multipart/form-data upload in the "file" field,
+with metadata in form fields (title, author, language, publisher,
+description, rights, subject) or a JSON "metadata" field, and streams the
+EPUB back. With --allow-urls, a "url" field or an application/json body
+such as {"url": "https://...", "metadata": {"title": "..."}} names a
+document to fetch instead. GET /healthz reports that the server is up.`,
+	Example: `  # Serve on port 8080
+  toepub server --listen :8080
+
+  # Convert an upload
+  curl -F file=@book.md -F title="My Book" http://localhost:8080/convert -o book.epub`,
+	Args: cobra.NoArgs,
+	RunE: runServer,
+}
+
+// Server flags
+var (
+	listenAddr    string
+	maxConcurrent int
+	maxUploadMB   int64
+	allowURLs     bool
+	allowPrivate  bool
+	maxMemoryMB   int64
+)
+
+func init() {
+	rootCmd.AddCommand(serverCmd)
+
+	serverCmd.Flags().StringVar(&listenAddr, "listen", ":8080", "Address to listen on")
+	serverCmd.Flags().IntVar(&maxConcurrent, "max-concurrent", server.DefaultMaxConcurrent, "Conversions run at once; further requests get 503")
+	serverCmd.Flags().Int64Var(&maxUploadMB, "max-upload-size", server.DefaultMaxUploadSize>>20, "Largest accepted document, in MB")
+	serverCmd.Flags().Int64Var(&maxMemoryMB, "max-memory", 0, "Memory budget of each conversion in MB (0 = no limit)")
+	serverCmd.Flags().BoolVar(&allowURLs, "allow-urls", false, "Accept requests naming a URL to fetch the document from")
+	serverCmd.Flags().BoolVar(&allowPrivate, "allow-private-urls", false, "Fetch URLs on loopback, private, and link-local addresses too")
+}
+
+// runServer serves the API until interrupted, then drains running requests
+func runServer(cmd *cobra.Command, args []string) error {
+	if maxConcurrent < 1 {
+		return fmt.Errorf("invalid --max-concurrent %d: must be 1 or greater", maxConcurrent)
+	}
+	if maxUploadMB < 1 {
+		return fmt.Errorf("invalid --max-upload-size %d: must be 1 or greater", maxUploadMB)
+	}
+
+	srv := server.New(server.Config{
+		MaxConcurrent: maxConcurrent,
+		MaxUploadSize: maxUploadMB << 20,
+		AllowURLs:     allowURLs,
+		AllowPrivate:  allowPrivate,
+		MaxMemory:     maxMemoryMB << 20,
+		Logger:        slog.Default(),
+	})
+	httpServer := &http.Server{
+		Addr:              listenAddr,
+		Handler:           srv.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	if !quiet {
+		cmd.PrintErrf(msg.Listening+"\n", listenAddr)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serving on %s: %w", listenAddr, err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("shutting down: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats <input>... [flags]",
+	Short: "Describe the book inputs would make, without building it",
+	Long: `Read and parse the inputs as convert does and report what the book
+would hold: input files, chapters, headings, words, and images, with the
+images that are referenced but missing, and the predicted size of the
+EPUB. Nothing is written.
+
+Given a project directory or file, as read by "toepub build", the stats
+are those of its book: its inputs, stylesheets, cover, and metadata. A
+project of several books needs --book.
+
+Words in Chinese and Japanese text are counted one per character. The
+predicted size compresses the text as the EPUB would and adds images at
+their size on disk, so it is close to, but not exactly, the size of the
+built book.`,
+	Example: `  # Check a manuscript before building it
+  toepub stats chapters/ --recursive
+
+  # The book of a project made with "toepub init"
+  toepub stats my-book/
+
+  # Chapters as split at each h2
+  toepub stats book.md --split-level 2 -f json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runStats,
+}
+
+// statsBook is the name of the project book to describe
+var statsBook string
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().BoolVarP(&recursive, "recursive", "r", false, "Include files in subdirectories of directory inputs (honors .toepubignore)")
+	statsCmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip input files matching GLOB, repeatable")
+	statsCmd.Flags().StringVar(&inputFormat, "input-format", "", "Force input format: md, html, pdf")
+	statsCmd.Flags().StringVar(&splitLevel, "split-level", "none", "Count chapters as split at each h1 (1), h1 and h2 (2), or per input file (none)")
+	statsCmd.Flags().StringVar(&statsBook, "book", "", "Describe the project book with NAME")
+	statsCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// runStats executes the stats command
+func runStats(cmd *cobra.Command, args []string) error {
+	level, err := converter.ParseSplitLevel(splitLevel)
+	if err != nil {
+		return fmt.Errorf("invalid --split-level %q: must be 1, 2 or none", splitLevel)
+	}
+
+	inputs, opts, err := statsInputs(args, converter.Options{
+		InputFormat: inputFormat,
+		Recursive:   recursive,
+		Exclude:     excludes,
+		SplitLevel:  level,
+	})
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	conv := converter.New()
+	stats, err := conv.Stats(inputs, opts)
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if outputFmt == "json" {
+		outputStatsJSON(cmd, stats)
+		return nil
+	}
+	outputStatsHuman(cmd, args, stats)
+	return nil
+}
+
+// statsInputs returns the inputs and options to describe. A single project
+// directory or file stands for the inputs and options of its book, chosen
+// with --book when it has several; other arguments are the inputs.
+func statsInputs(args []string, opts converter.Options) ([]string, converter.Options, error) {
+	if len(args) > 1 {
+		return args, opts, nil
+	}
+	path := args[0]
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return args, opts, nil
+	case info.IsDir():
+		path = converter.FindProject(path)
+		if _, err := os.Stat(path); err != nil {
+			return args, opts, nil
+		}
+	case !strings.EqualFold(filepath.Ext(path), ".yaml") && !strings.EqualFold(filepath.Ext(path), ".yml"):
+		return args, opts, nil
+	}
+
+	project, err := converter.LoadProject(path)
+	if err != nil {
+		return nil, opts, err
+	}
+	book := project.Books[0]
+	switch {
+	case statsBook != "":
+		var ok bool
+		if book, ok = project.Book(statsBook); !ok {
+			return nil, opts, fmt.Errorf("%w: no book is named %q", converter.ErrInvalidProject, statsBook)
+		}
+	case len(project.Books) > 1:
+		return nil, opts, fmt.Errorf("%w: %s lists %d books; choose one with --book", converter.ErrInvalidProject, path, len(project.Books))
+	}
+
+	opts = project.BookOptions(book, opts)
+	opts.CacheDir = "" // Nothing is written, not even the parse cache
+	return project.Inputs(book), opts, nil
+}
+
+// outputStatsHuman prints the stats as a table, after any warnings
+func outputStatsHuman(cmd *cobra.Command, args []string, stats *converter.InputStats) {
+	printWarnings(cmd, stats.Warnings)
+
+	images := fmt.Sprint(stats.Images)
+	if len(stats.MissingImages) > 0 {
+		images = fmt.Sprintf(msg.MissingCount, stats.Images, len(stats.MissingImages))
+	}
+	cmd.Printf(msg.StatsFor+"\n\n", args[0], stats.InputFormat)
+	cmd.Printf("  %s %d\n", statsLabel(msg.InputFiles), stats.InputFiles)
+	cmd.Printf("  %s %d\n", statsLabel(msg.Chapters), stats.Chapters)
+	cmd.Printf("  %s %d\n", statsLabel(msg.Headings), stats.Headings)
+	cmd.Printf("  %s %d\n", statsLabel(msg.Words), stats.Words)
+	cmd.Printf("  %s %s\n", statsLabel(msg.Images), images)
+	cmd.Printf("  %s ~%s\n", statsLabel(msg.PredictedSize), FormatFileSize(stats.PredictedSize))
+	if len(stats.MissingImages) > 0 {
+		cmd.Println()
+	}
+	for _, image := range stats.MissingImages {
+		cmd.Printf("  "+msg.Missing+"\n", image)
+	}
+}
+
+// statsLabel pads a stats table label to 16 columns, counting Chinese,
+// Japanese, and Korean characters as the two columns terminals give them.
+func statsLabel(label string) string {
+	width := 0
+	for _, r := range label {
+		width++
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			width++
+		}
+	}
+	return label + strings.Repeat(" ", max(16-width, 0))
+}
+
+// outputStatsJSON prints the stats as JSON to stdout
+func outputStatsJSON(cmd *cobra.Command, stats *converter.InputStats) {
+	output := jsonInputStats{
+		InputFormat:   stats.InputFormat,
+		InputFiles:    stats.InputFiles,
+		Chapters:      stats.Chapters,
+		Headings:      stats.Headings,
+		Words:         stats.Words,
+		Images:        stats.Images,
+		MissingImages: stats.MissingImages,
+		PredictedSize: stats.PredictedSize,
+	}
+	for _, warning := range stats.Warnings {
+		output.Warnings = append(output.Warnings, jsonWarning{
+			Code:     warning.Code,
+			Severity: warning.Severity,
+			File:     warning.File,
+			Message:  warning.Message,
+		})
+	}
+
+	data, _ := json.MarshalIndent(output, "", "  ")
+	cmd.Println(string(data))
+}
+
+type jsonInputStats struct {
+	InputFormat   string        `json:"input_format"`
+	InputFiles    int           `json:"input_files"`
+	Chapters      int           `json:"chapters"`
+	Headings      int           `json:"headings"`
+	Words         int           `json:"words"`
+	Images        int           `json:"images"`
+	MissingImages []string      `json:"missing_images,omitempty"`
+	PredictedSize int64         `json:"predicted_size"`
+	Warnings      []jsonWarning `json:"warnings,omitempty"`
+}
@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestStats_Project(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-book")
+	_, err := converter.Scaffold(dir, model.Metadata{})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"stats", dir, "--format", "json"})
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		outputFmt = "human"
+	})
+	require.NoError(t, rootCmd.Execute())
+
+	var stats jsonInputStats
+	require.NoError(t, json.Unmarshal(out.Bytes(), &stats))
+	assert.Equal(t, "markdown", stats.InputFormat)
+	assert.Equal(t, 2, stats.InputFiles)
+	assert.Equal(t, 2, stats.Chapters)
+	assert.Empty(t, stats.MissingImages)
+	assert.NoDirExists(t, filepath.Join(dir, ".toepub-cache"))
+}
+
+func TestStatsInputs(t *testing.T) {
+	dir := t.TempDir()
+	project := filepath.Join(dir, "toepub.yaml")
+	require.NoError(t, os.WriteFile(project, []byte("books:\n  - name: one\n    input: one/\n  - name: two\n    input: [two.md]\n    recursive: true\n"), 0o644))
+
+	_, _, err := statsInputs([]string{project}, converter.Options{})
+	assert.ErrorIs(t, err, converter.ErrInvalidProject)
+
+	statsBook = "two"
+	t.Cleanup(func() { statsBook = "" })
+	inputs, opts, err := statsInputs([]string{dir}, converter.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "two.md")}, inputs)
+	assert.True(t, opts.Recursive)
+	assert.Empty(t, opts.CacheDir)
+
+	// Other inputs are described as they are
+	inputs, _, err = statsInputs([]string{dir, "extra.md"}, converter.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{dir, "extra.md"}, inputs)
+}
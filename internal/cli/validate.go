@@ -0,0 +1,80 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+)
+
+// validateCmd represents the validate command
+var validateCmd = &cobra.Command{
+	Use:   "validate <book.epub> [flags]",
+	Short: "Check an existing EPUB for problems",
+	Long: `Check an existing EPUB for malformed XHTML and links that do not resolve
+within the book.
+
+With --a11y, the documents are also audited for accessibility problems,
+following the DAISY Ace rules: missing language declarations, skipped
+heading levels, links without text, tables without header cells, and
+images without alt text.
+
+Each problem is printed as a warning. The exit code is 65 when problems
+are found, so the command can gate a CI pipeline.`,
+	Example: `  # Check a book
+  toepub validate book.epub
+
+  # Include the accessibility audit
+  toepub validate book.epub --a11y`,
+	Args: cobra.ExactArgs(1),
+	RunE: runValidate,
+}
+
+// validateA11y enables the accessibility audit
+var validateA11y bool
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+
+	validateCmd.Flags().BoolVar(&validateA11y, "a11y", false, "Also audit the book for accessibility problems")
+	validateCmd.Flags().StringVarP(&outputFmt, "format", "f", "human", "Output format: human or json")
+}
+
+// runValidate executes the validate command
+func runValidate(cmd *cobra.Command, args []string) error {
+	conv := converter.New()
+	result, err := conv.Validate(args[0], converter.ValidateOptions{A11y: validateA11y})
+	if err != nil {
+		return handleConvertError(cmd, err)
+	}
+
+	if outputFmt == "json" {
+		outputJSON(cmd, result)
+	} else {
+		printWarnings(cmd, result.Warnings)
+	}
+	if len(result.Warnings) == 0 {
+		if outputFmt != "json" && !quiet {
+			cmd.Printf("%s "+msg.NoProblems+"\n", symbolSuccess, args[0])
+		}
+		return nil
+	}
+
+	if outputFmt != "json" {
+		found := msg.ProblemsFound
+		if len(result.Warnings) == 1 {
+			found = msg.ProblemFound
+		}
+		cmd.PrintErrf("%s "+found+"\n", symbolError, len(result.Warnings), args[0])
+	}
+	os.Exit(ExitFormatError)
+	return nil
+}
@@ -0,0 +1,144 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package cli
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// watchInterval is how often watched inputs are checked for changes.
+const watchInterval = 500 * time.Millisecond
+
+// convertFunc runs one conversion of the inputs.
+type convertFunc func(inputs []string, opts converter.Options) (*model.ConversionResult, error)
+
+// fileStamp identifies a version of a watched file.
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watchInputs converts the inputs, then converts them again each time a
+// file under them changes, until interrupted. Rebuilds keep unchanged
+// files and chapters from the last build (Options.Incremental), and a
+// failed build is reported without stopping the watch.
+func watchInputs(cmd *cobra.Command, inputs []string, opts converter.Options, convert convertFunc) error {
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts.Incremental = true
+	ignore := watchIgnored(opts)
+	build := func() {
+		result, err := convert(inputs, opts)
+		if err == nil || errors.Is(err, converter.ErrEPUBCheck) {
+			// An output named from the book's metadata is only known now
+			maps.Copy(ignore, watchIgnored(converter.Options{OutputPath: result.OutputPath, Manifest: opts.Manifest}))
+			_ = outputResult(cmd, result)
+			if err != nil && outputFmt != "json" {
+				cmd.PrintErrf("%s %s\n", symbolError, err)
+			}
+			return
+		}
+		if outputFmt == "json" {
+			outputJSON(cmd, &model.ConversionResult{Error: err})
+		} else {
+			outputHumanError(cmd, err)
+		}
+	}
+
+	build()
+	state := snapshotInputs(inputs, ignore)
+	if outputFmt != "json" && !quiet {
+		cmd.PrintErrln(msg.Watching)
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		next := snapshotInputs(inputs, ignore)
+		if maps.Equal(state, next) {
+			continue
+		}
+		state = next
+		if outputFmt != "json" && !quiet {
+			cmd.PrintErrln(msg.Rebuilding)
+		}
+		build()
+	}
+}
+
+// watchIgnored returns the absolute paths a build writes to, which must
+// not trigger the next build: the output file, its build manifest, and the
+// cache directory.
+func watchIgnored(opts converter.Options) map[string]bool {
+	ignore := make(map[string]bool)
+	paths := []string{opts.OutputPath, opts.EmitIR, opts.CacheDir}
+	if opts.Manifest != nil && opts.OutputPath != "" {
+		paths = append(paths, converter.ManifestPath(opts.OutputPath))
+	}
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			ignore[abs] = true
+		}
+	}
+	return ignore
+}
+
+// snapshotInputs returns the modification time and size of each file
+// under the inputs, skipping hidden directories and ignored paths. Files
+// that cannot be read are left out, so their return is a change.
+func snapshotInputs(inputs []string, ignore map[string]bool) map[string]fileStamp {
+	files := make(map[string]fileStamp)
+	for _, input := range inputs {
+		_ = filepath.WalkDir(input, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			abs, _ := filepath.Abs(path)
+			if ignore[abs] {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if path != input && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				files[abs] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return files
+}
@@ -0,0 +1,163 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+var (
+	imgTagRe     = regexp.MustCompile(`<img\b[^>]*>`)
+	altAttrRe    = regexp.MustCompile(`\salt\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	srcAttrRe    = regexp.MustCompile(`\ssrc\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	titleAttrRe  = regexp.MustCompile(`\stitle\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	decorativeRe = regexp.MustCompile(`\s(?:role\s*=\s*["'](?:presentation|none)["']|aria-hidden\s*=\s*["']true["'])`)
+	figureRe     = regexp.MustCompile(`(?s)<figure\b.*?</figure>`)
+	figcaptionRe = regexp.MustCompile(`(?s)<figcaption\b[^>]*>(.*?)</figcaption>`)
+	tagRe        = regexp.MustCompile(`<[^>]*>`)
+)
+
+// placeholderAlts are alt texts that say nothing about the image.
+var placeholderAlts = map[string]bool{
+	"image": true, "img": true, "picture": true, "figure": true, "graphic": true, "alt": true,
+}
+
+// checkAltText reports each image without meaningful alt text: none, empty,
+// a placeholder, or the image's file name. Images marked decorative with
+// role="presentation" or aria-hidden="true" are skipped. With
+// opts.FillAltText, such images get alt text from their figure caption,
+// title, or file name, reported as a note instead.
+func (c *Converter) checkAltText(doc *model.Document, rep *reporter, opts Options) {
+	missing := 0
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		content := chapter.Content
+
+		// Captions of the figures in the chapter, by byte range
+		type figure struct {
+			start, end int
+			caption    string
+		}
+		var figures []figure
+		for _, loc := range figureRe.FindAllStringIndex(content, -1) {
+			caption := ""
+			if m := figcaptionRe.FindStringSubmatch(content[loc[0]:loc[1]]); m != nil {
+				caption = plainText(m[1])
+			}
+			figures = append(figures, figure{loc[0], loc[1], caption})
+		}
+
+		var out strings.Builder
+		last := 0
+		for _, loc := range imgTagRe.FindAllStringIndex(content, -1) {
+			tag := content[loc[0]:loc[1]]
+			src := attrMatch(srcAttrRe, tag)
+			if decorativeRe.MatchString(tag) || meaningfulAlt(attrMatch(altAttrRe, tag), src) {
+				continue
+			}
+			missing++
+
+			if !opts.FillAltText {
+				rep.warn(model.Warning{
+					Code:    model.WarnMissingAltText,
+					File:    chapter.FileName,
+					Message: fmt.Sprintf("Image %s in %s has no meaningful alt text", src, chapter.FileName),
+				})
+				continue
+			}
+
+			caption := ""
+			for _, f := range figures {
+				if loc[0] >= f.start && loc[1] <= f.end {
+					caption = f.caption
+				}
+			}
+			alt, from := caption, "caption"
+			if alt == "" {
+				alt, from = strings.TrimSpace(attrMatch(titleAttrRe, tag)), "title"
+			}
+			if alt == "" {
+				alt, from = altFromFileName(src), "file name"
+			}
+			rep.warn(model.Warning{
+				Code:     model.WarnMissingAltText,
+				Severity: model.SeverityInfo,
+				File:     chapter.FileName,
+				Message:  fmt.Sprintf("Image %s in %s: alt text %q filled from its %s", src, chapter.FileName, alt, from),
+			})
+
+			out.WriteString(content[last:loc[0]])
+			out.WriteString(setAlt(tag, alt))
+			last = loc[1]
+		}
+		if last > 0 {
+			out.WriteString(content[last:])
+			chapter.Content = out.String()
+		}
+	}
+	if missing > 0 {
+		opts.logger().Debug("checked alt text", "stage", "images", "missing", missing, "filled", opts.FillAltText)
+	}
+}
+
+// attrMatch returns the unescaped value of the attribute matched by re in tag.
+func attrMatch(re *regexp.Regexp, tag string) string {
+	m := re.FindStringSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1] + m[2])
+}
+
+// meaningfulAlt reports whether alt describes the image at src.
+func meaningfulAlt(alt, src string) bool {
+	alt = strings.ToLower(strings.TrimSpace(alt))
+	if alt == "" || placeholderAlts[alt] {
+		return false
+	}
+	base := strings.ToLower(path.Base(src))
+	return alt != base && alt != strings.TrimSuffix(base, path.Ext(base))
+}
+
+// altFromFileName turns an image file name such as "sunset_over-lake.jpg"
+// into alt text such as "Sunset over lake".
+func altFromFileName(src string) string {
+	name := path.Base(src)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	name = strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r)
+	}), " ")
+	if name == "" {
+		return "Image"
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// setAlt returns the img tag with its alt attribute set to alt.
+func setAlt(tag, alt string) string {
+	value := ` alt="` + html.EscapeString(alt) + `"`
+	if loc := altAttrRe.FindStringIndex(tag); loc != nil {
+		return tag[:loc[0]] + value + tag[loc[1]:]
+	}
+	return "<img" + value + tag[len("<img"):]
+}
+
+// plainText returns the text of an HTML fragment, with tags removed and
+// whitespace collapsed.
+func plainText(fragment string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tagRe.ReplaceAllString(fragment, " "))), " ")
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestMeaningfulAlt(t *testing.T) {
+	assert.True(t, meaningfulAlt("A lighthouse at dusk", "../images/photo.jpg"))
+	assert.False(t, meaningfulAlt("", "../images/photo.jpg"))
+	assert.False(t, meaningfulAlt(" Image ", "../images/photo.jpg"))
+	assert.False(t, meaningfulAlt("photo", "../images/photo.jpg"))
+	assert.False(t, meaningfulAlt("photo.JPG", "../images/photo.jpg"))
+}
+
+func TestAltFromFileName(t *testing.T) {
+	assert.Equal(t, "Sunset over lake", altFromFileName("../images/sunset_over-lake.jpg"))
+	assert.Equal(t, "Image", altFromFileName("../images/.png"))
+}
+
+func TestConverter_CheckAltText(t *testing.T) {
+	content := `<p><img src="../images/a.png" alt="A chart"/><img src="../images/b.png"/>` +
+		`<img src="../images/rule.png" alt="" role="presentation"/></p>` +
+		`<figure><img src="../images/c.png" alt="c"/><figcaption>Sales <em>by</em> year</figcaption></figure>` +
+		`<img src="../images/old_map.png" alt='' title="Map of 1850"/><img src="../images/my-dog.jpg" alt="image"/>`
+
+	t.Run("warn", func(t *testing.T) {
+		doc := model.NewDocument()
+		doc.AddChapter(model.Chapter{FileName: "content/chapter-001.xhtml", Content: content})
+		rep := newReporter(&model.ConversionResult{}, Options{})
+
+		New().checkAltText(doc, rep, Options{})
+		require.Len(t, rep.result.Warnings, 4)
+		for _, w := range rep.result.Warnings {
+			assert.Equal(t, model.WarnMissingAltText, w.Code)
+			assert.Equal(t, model.SeverityWarning, w.Severity)
+			assert.Equal(t, "content/chapter-001.xhtml", w.File)
+		}
+		assert.Equal(t, content, doc.Chapters[0].Content)
+	})
+
+	t.Run("fill", func(t *testing.T) {
+		doc := model.NewDocument()
+		doc.AddChapter(model.Chapter{FileName: "content/chapter-001.xhtml", Content: content})
+		rep := newReporter(&model.ConversionResult{}, Options{})
+
+		New().checkAltText(doc, rep, Options{FillAltText: true})
+		require.Len(t, rep.result.Warnings, 4)
+		assert.Equal(t, model.SeverityInfo, rep.result.Warnings[0].Severity)
+
+		got := doc.Chapters[0].Content
+		assert.Contains(t, got, `<img alt="B" src="../images/b.png"/>`)
+		assert.Contains(t, got, `<img src="../images/c.png" alt="Sales by year"/>`)
+		assert.Contains(t, got, `<img src="../images/old_map.png" alt="Map of 1850" title="Map of 1850"/>`)
+		assert.Contains(t, got, `<img src="../images/my-dog.jpg" alt="My dog"/>`)
+		assert.Contains(t, got, `alt="" role="presentation"`)
+	})
+}
@@ -0,0 +1,338 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// maxArchiveSize limits the expanded contents of an archive input, which
+// are held in memory.
+const maxArchiveSize = 1 << 30
+
+// archiveExtensions are the extensions of archive inputs, longest first
+// so that .tar.gz is not taken for .gz.
+var archiveExtensions = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// ArchiveExtension returns the extension of name if it is an archive the
+// converter expands as an input, such as ".zip" or ".tar.gz", or "".
+func ArchiveExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return name[len(name)-len(ext):]
+		}
+	}
+	return ""
+}
+
+// inputArchive is a .zip, .tar, or .tar.gz input, expanded in memory.
+type inputArchive struct {
+	Path string // Archive file, the directory its files appear in
+	FS   fs.FS
+}
+
+// openArchiveTree expands the archive at name and returns its book
+// directory: the archive's single top-level directory, as in exported
+// repositories, or else its root.
+func openArchiveTree(name string) (inputTree, error) {
+	fsys, err := readArchive(name)
+	if err != nil {
+		return inputTree{}, &ParseError{File: name, Err: err}
+	}
+	archive := &inputArchive{Path: filepath.Clean(name), FS: fsys}
+
+	root := "."
+	entries, _ := fs.ReadDir(fsys, ".")
+	entries = slices.DeleteFunc(entries, func(entry fs.DirEntry) bool {
+		// Resource forks that macOS adds to the archives it makes
+		return strings.HasPrefix(entry.Name(), ".") || entry.Name() == "__MACOSX"
+	})
+	if len(entries) == 1 && entries[0].IsDir() {
+		root = entries[0].Name()
+	}
+	sub, err := fs.Sub(fsys, root)
+	if err != nil {
+		return inputTree{}, &ParseError{File: name, Err: err}
+	}
+	return inputTree{
+		Dir:     filepath.Join(archive.Path, filepath.FromSlash(root)),
+		FS:      sub,
+		Archive: archive,
+		Root:    root,
+	}, nil
+}
+
+// readArchive reads the archive at name into memory.
+func readArchive(name string) (fs.FS, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("reading archive: %w", err)
+	}
+
+	if strings.EqualFold(ArchiveExtension(name), ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("reading zip archive: %w", err)
+		}
+		var size uint64
+		for _, f := range zr.File {
+			size += f.UncompressedSize64
+		}
+		if size > maxArchiveSize {
+			return nil, fmt.Errorf("zip archive expands to more than %d MB", maxArchiveSize>>20)
+		}
+		return zr, nil
+	}
+
+	var r io.Reader = bytes.NewReader(data)
+	if !strings.EqualFold(ArchiveExtension(name), ".tar") {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading tar archive: %w", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return readTar(io.LimitReader(r, maxArchiveSize+1))
+}
+
+// readTar expands the regular files of a tar archive. Entries whose names
+// lead outside the archive are skipped.
+func readTar(r io.Reader) (fs.FS, error) {
+	fsys := make(archiveFS)
+	tr := tar.NewReader(r)
+	var size int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar archive: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean(hdr.Name), "/")
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("reading tar archive: %w", err)
+		}
+		if size += int64(len(data)); size > maxArchiveSize {
+			return nil, fmt.Errorf("tar archive expands to more than %d MB", maxArchiveSize>>20)
+		}
+		fsys.add(name, data, hdr.ModTime)
+	}
+	return fsys, nil
+}
+
+// inputArchives returns the archives the files are read from, in order.
+func inputArchives(files []inputFile) []*inputArchive {
+	var archives []*inputArchive
+	for _, file := range files {
+		if file.Archive != nil && !slices.Contains(archives, file.Archive) {
+			archives = append(archives, file.Archive)
+		}
+	}
+	return archives
+}
+
+// bookName returns the name of a book whose first input is the file when
+// it has no title or output path: the file's name without its extension,
+// or the archive's name for a file in an archive.
+func (f inputFile) bookName() string {
+	if f.Archive != nil {
+		base := filepath.Base(f.Archive.Path)
+		return strings.TrimSuffix(base, ArchiveExtension(base))
+	}
+	return strings.TrimSuffix(filepath.Base(f.Path), filepath.Ext(f.Path))
+}
+
+// archiveFetcher reads the resources of archive inputs from the archives,
+// and other resources with next, or from disk if next is nil.
+type archiveFetcher struct {
+	archives []*inputArchive
+	next     ResourceFetcher
+}
+
+// Fetch reads the resource at ref.
+func (f archiveFetcher) Fetch(ref string) ([]byte, error) {
+	if archive, name, ok := f.locate(ref); ok {
+		data, err := fs.ReadFile(archive.FS, name)
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+			return nil, missingError(ref)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrFetch, ref, err)
+		}
+		return data, nil
+	}
+	if f.next == nil {
+		return FileFetcher{}.Fetch(ref)
+	}
+	return f.next.Fetch(ref)
+}
+
+// locate returns the archive holding ref and the name of ref in it.
+func (f archiveFetcher) locate(ref string) (*inputArchive, string, bool) {
+	for _, archive := range f.archives {
+		if rel, ok := strings.CutPrefix(ref, archive.Path+string(filepath.Separator)); ok {
+			return archive, filepath.ToSlash(rel), true
+		}
+	}
+	return nil, "", false
+}
+
+// loadArchiveMedia reads the audio, video, and scripts that archive inputs
+// reference into memory, as only files on disk are streamed at build time.
+// Those missing from the archives are left for processMedia to report.
+func loadArchiveMedia(doc *model.Document, archives []*inputArchive) {
+	if len(archives) == 0 {
+		return
+	}
+	fetcher := archiveFetcher{archives: archives}
+	for i, res := range doc.Resources {
+		if len(res.Data) > 0 || strings.HasPrefix(res.MediaType, "image/") || res.MediaType == "text/css" {
+			continue
+		}
+		archive, name, ok := fetcher.locate(res.SourcePath)
+		if !ok {
+			continue
+		}
+		if data, err := fs.ReadFile(archive.FS, name); err == nil {
+			doc.Resources[i].Data = data
+		}
+	}
+}
+
+// archiveFS is the expanded contents of a tar archive: the data of each
+// file, and the directories leading to them, by slash-separated path.
+type archiveFS map[string]*archiveEntry
+
+// archiveEntry is a file or directory of an archiveFS.
+type archiveEntry struct {
+	data    []byte
+	dir     bool
+	modTime time.Time
+}
+
+// add stores a file, adding the directories leading to it.
+func (fsys archiveFS) add(name string, data []byte, modTime time.Time) {
+	fsys[name] = &archiveEntry{data: data, modTime: modTime}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		if _, ok := fsys[dir]; ok {
+			break
+		}
+		fsys[dir] = &archiveEntry{dir: true, modTime: modTime}
+	}
+}
+
+// Open opens the named file or directory.
+func (fsys archiveFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	entry, ok := fsys[name]
+	if name == "." {
+		entry, ok = &archiveEntry{dir: true}, true
+	}
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info := archiveInfo{name: path.Base(name), entry: entry}
+	if !entry.dir {
+		return &archiveFile{Reader: bytes.NewReader(entry.data), info: info}, nil
+	}
+	var entries []fs.DirEntry
+	for p, e := range fsys {
+		if path.Dir(p) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(archiveInfo{name: path.Base(p), entry: e}))
+		}
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return &archiveDir{info: info, entries: entries}, nil
+}
+
+// archiveInfo describes an archiveFS entry.
+type archiveInfo struct {
+	name  string
+	entry *archiveEntry
+}
+
+func (i archiveInfo) Name() string       { return i.name }
+func (i archiveInfo) Size() int64        { return int64(len(i.entry.data)) }
+func (i archiveInfo) ModTime() time.Time { return i.entry.modTime }
+func (i archiveInfo) IsDir() bool        { return i.entry.dir }
+func (i archiveInfo) Sys() any           { return nil }
+
+func (i archiveInfo) Mode() fs.FileMode {
+	if i.entry.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// archiveFile is an open archiveFS file.
+type archiveFile struct {
+	*bytes.Reader
+	info archiveInfo
+}
+
+func (f *archiveFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *archiveFile) Close() error               { return nil }
+
+// archiveDir is an open archiveFS directory.
+type archiveDir struct {
+	info    archiveInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *archiveDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *archiveDir) Close() error               { return nil }
+
+func (d *archiveDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir returns the next n entries of the directory, or all remaining
+// entries if n <= 0.
+func (d *archiveDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
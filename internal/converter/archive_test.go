@@ -0,0 +1,169 @@
+package converter
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// writeZip writes the files as a zip archive at name.
+func writeZip(t *testing.T, name string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		w, err := zw.Create(file)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(files[file]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, os.WriteFile(name, buf.Bytes(), 0o644))
+}
+
+// writeTarGz writes the files as a gzipped tar archive at name.
+func writeTarGz(t *testing.T, name string, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file, Mode: 0o644, Size: int64(len(files[file])), ModTime: time.Now()}))
+		_, err := tw.Write([]byte(files[file]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(name, buf.Bytes(), 0o644))
+}
+
+func TestConverter_Convert_ZipArchive(t *testing.T) {
+	dir := t.TempDir()
+	figure := pngResource(t, 4, 3).Data
+	archive := filepath.Join(dir, "handbook.zip")
+	writeZip(t, archive, map[string]string{
+		// Exported repositories keep the book in a top-level directory
+		"handbook-main/SUMMARY.md":          "- [Intro](intro.md)\n- [Usage](guide/usage.md)\n",
+		"handbook-main/intro.md":            "---\ntitle: Handbook\n---\n# Intro\n\n![Flow](art/flow.png)\n",
+		"handbook-main/guide/usage.md":      "# Usage\n\n![Gone](../art/missing.png)\n",
+		"handbook-main/art/flow.png":        string(figure),
+		"handbook-main/unlisted.md":         "# Unlisted\n",
+		"__MACOSX/handbook-main/._intro.md": "fork",
+	})
+
+	files, err := New().expandInputs([]string{archive}, Options{})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	assert.Equal(t, filepath.Join(archive, "handbook-main", "intro.md"), files[0].Path)
+	assert.Equal(t, "handbook-main/guide/usage.md", files[1].Name)
+
+	t.Chdir(dir)
+	result, err := New().Convert([]string{"handbook.zip"}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "handbook.epub", result.OutputPath)
+	assert.Equal(t, 2, result.Stats.InputFiles)
+	assert.Equal(t, 1, result.Stats.ImageCount)
+	assert.Equal(t, string(figure), readEPUBEntry(t, "handbook.epub", "OEBPS/images/flow.png"))
+
+	var missing []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnMissingImage {
+			missing = append(missing, w)
+		}
+	}
+	require.Len(t, missing, 1)
+	assert.Equal(t, filepath.Join("handbook.zip", "handbook-main", "art", "missing.png"), missing[0].File)
+}
+
+func TestConverter_Convert_ArchiveSidecarFiles(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "proj.zip")
+	writeZip(t, archive, map[string]string{
+		"book.md":        "---\ntitle: Terms\nglossary: terms.yaml\nbibliography: refs/works.bib\n---\n# One\n\nAs [@doe2019] says, see the API.\n",
+		"terms.yaml":     "API: Application programming interface\n",
+		"refs/works.bib": "@book{doe2019, author = {Doe, Jane}, title = {A Study}, year = {2019}}\n",
+		"words.pls":      "<lexicon/>",
+	})
+
+	output := filepath.Join(dir, "out.epub")
+	_, err := New().Convert([]string{archive}, Options{
+		OutputPath: output,
+		Lexicons:   []string{filepath.Join(archive, "words.pls")},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/glossary.xhtml"), "Application programming interface")
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/bibliography.xhtml"), "A Study")
+	assert.Equal(t, "<lexicon/>", readEPUBEntry(t, output, "OEBPS/lexicons/words.pls"))
+
+	// A file the front matter names must be in the archive
+	writeZip(t, archive, map[string]string{"book.md": "---\nglossary: terms.yaml\n---\n# One\n"})
+	_, err = New().Convert([]string{archive}, Options{OutputPath: output})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+	assert.ErrorContains(t, err, filepath.Join("proj.zip", "terms.yaml"))
+}
+
+func TestConverter_Convert_TarArchive(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "notes.tar.gz")
+	writeTarGz(t, archive, map[string]string{
+		"./b.md":          "# Second\n",
+		"./a.md":          "# First\n",
+		"./style.css":     "p { margin: 0; }",
+		"./drafts/c.md":   "# Draft\n",
+		"../escaped.md":   "# Escaped\n",
+		"./.toepubignore": "b.md\n",
+	})
+
+	files, err := New().expandInputs([]string{archive}, Options{Recursive: true})
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"a.md", "drafts/c.md"}, names)
+
+	output := filepath.Join(dir, "out.epub")
+	result, err := New().Convert([]string{archive}, Options{OutputPath: output})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Stats.ChapterCount, "one chapter and the colophon")
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/chapter-001.xhtml"), "First")
+}
+
+func TestConverter_Convert_BadArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "broken.zip")
+	require.NoError(t, os.WriteFile(archive, []byte("not a zip"), 0o644))
+
+	_, err := New().Convert([]string{archive}, Options{})
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, archive, parseErr.File)
+}
+
+func TestArchiveFS(t *testing.T) {
+	fsys := make(archiveFS)
+	fsys.add("book/one.md", []byte("# One\n"), time.Time{})
+	fsys.add("book/art/figure.png", pngHeader, time.Time{})
+	fsys.add("README.md", []byte("readme"), time.Time{})
+	require.NoError(t, fstest.TestFS(fsys, "book/one.md", "book/art/figure.png", "README.md"))
+}
+
+func TestArchiveExtension(t *testing.T) {
+	assert.Equal(t, ".tar.gz", ArchiveExtension("book.tar.gz"))
+	assert.Equal(t, ".TGZ", ArchiveExtension("BOOK.TGZ"))
+	assert.Equal(t, ".zip", ArchiveExtension("dir/book.zip"))
+	assert.Equal(t, "", ArchiveExtension("book.gz"))
+	assert.Equal(t, "", ArchiveExtension("book.md"))
+}
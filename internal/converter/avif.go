@@ -0,0 +1,91 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// errNoDecoder is returned when no decoder is registered for an image
+// format that needs decoding.
+var errNoDecoder = errors.New("no decoder registered")
+
+// avifBrands are the ftyp brands of AVIF still images and sequences.
+var avifBrands = []string{"avif", "avis"}
+
+// isAVIF reports whether data starts with an ISO-BMFF ftyp box whose major
+// or compatible brands name AVIF.
+func isAVIF(data []byte) bool {
+	if len(data) < 16 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+	size := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	size = min(size, len(data))
+
+	// Major brand at 8, minor version at 12, compatible brands from 16
+	for off := 8; off+4 <= size; off += 4 {
+		if off == 12 {
+			continue
+		}
+		for _, brand := range avifBrands {
+			if string(data[off:off+4]) == brand {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertAVIF decodes AVIF data with the decoder registered in the image
+// package and re-encodes it as JPEG, or as PNG if it has transparency.
+// The standard library has no AVIF decoder; programs that embed the
+// converter can register one by importing it. Without one, errNoDecoder
+// is returned and the image is embedded as-is.
+func (h *ImageHandler) convertAVIF(data []byte) ([]byte, string, error) {
+	return convertRegistered(data, "AVIF")
+}
+
+// convertRegistered decodes data of format with the decoders registered
+// in the image package and re-encodes it as JPEG, or as PNG if it has
+// transparency.
+func convertRegistered(data []byte, format string) ([]byte, string, error) {
+	img, err := decodeRegistered(data, format)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var buf bytes.Buffer
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, "", fmt.Errorf("encoding JPEG: %w", err)
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("encoding PNG: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// decodeRegistered decodes data with the decoders registered in the image
+// package, returning errNoDecoder if none recognizes it.
+func decodeRegistered(data []byte, format string) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if errors.Is(err, image.ErrFormat) {
+		return nil, fmt.Errorf("%w for %s", errNoDecoder, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", format, err)
+	}
+	return img, nil
+}
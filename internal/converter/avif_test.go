@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// avifHeader builds the start of an ISO-BMFF file with the given major
+// and compatible brands.
+func avifHeader(major string, compatible ...string) []byte {
+	size := 16 + 4*len(compatible)
+	data := []byte{0, 0, 0, byte(size)}
+	data = append(data, "ftyp"+major+"\x00\x00\x00\x00"...)
+	for _, brand := range compatible {
+		data = append(data, brand...)
+	}
+	return append(data, "\x00\x00\x00\x08meta"...)
+}
+
+func TestIsAVIF(t *testing.T) {
+	assert.True(t, isAVIF(avifHeader("avif", "mif1", "miaf")))
+	assert.True(t, isAVIF(avifHeader("avis")))
+	assert.True(t, isAVIF(avifHeader("mif1", "avif")))
+	assert.False(t, isAVIF(avifHeader("heic", "mif1")))
+	assert.False(t, isAVIF(avifHeader("mp42", "isom")))
+	assert.False(t, isAVIF(pngHeader))
+
+	mediaType, convert := NewImageHandler().detectImageFormat(avifHeader("avif"), "photo.bin")
+	assert.Equal(t, "image/avif", mediaType)
+	assert.True(t, convert)
+}
+
+func TestConverter_AVIFWithoutDecoder(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "photo.avif"), avifHeader("avif", "mif1"), 0o644))
+	writeFiles(t, dir, map[string]string{"book.md": "# One\n\n![photo](photo.avif)\n"})
+
+	output := filepath.Join(dir, "book.epub")
+	result, err := New().Convert([]string{filepath.Join(dir, "book.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+
+	// Kept as-is, reported for lacking a fallback
+	require.NotEmpty(t, result.Warnings)
+	assert.Contains(t, result.Warnings[0].Message, "no PNG fallback")
+
+	archive, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer archive.Close()
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, "OEBPS/images/photo.avif")
+}
@@ -0,0 +1,118 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// cacheVersion is part of every cache key. Bump it when parser output
+// changes in a way the build version does not capture.
+const cacheVersion = 1
+
+// buildVersion identifies the running build, so a new release does not
+// reuse documents parsed by an older one.
+var buildVersion = sync.OnceValue(func() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	version := info.Main.Version
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+			version += " " + s.Value
+		}
+	}
+	return version
+})
+
+// parseCache stores parsed input files in a directory as document IR, so
+// converting unchanged files again skips parsing. Entries are keyed on the
+// file content and everything else that affects the parser's output.
+type parseCache struct {
+	dir string
+}
+
+// newParseCache returns the cache in dir, creating the directory, or nil
+// if dir is empty.
+func newParseCache(dir string) (*parseCache, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%w: cannot create cache directory %s", ErrOutputNotWrite, dir)
+	}
+	return &parseCache{dir: dir}, nil
+}
+
+// parseKey returns the cache key for content parsed by p. The base path,
+// as given and absolute, is included because parsers resolve resource
+// paths against it.
+func parseKey(content []byte, p parser.Parser, format parser.Format, basePath string, opts Options) string {
+	abs, _ := filepath.Abs(basePath)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d\x00%d\x00%s\x00%s\x00%T\x00%s\x00%s\x00%+v\x00%s\x00",
+		cacheVersion, irVersion, buildVersion(), format, p, basePath, abs, opts.Scripts, opts.Notes)
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file holding the entry for key.
+func (pc *parseCache) path(key string) string {
+	return filepath.Join(pc.dir, key[:2], key+".json")
+}
+
+// load returns the document stored under key. Missing and unreadable
+// entries are misses.
+func (pc *parseCache) load(key string) (*model.Document, bool) {
+	f, err := os.Open(pc.path(key))
+	if err != nil {
+		return nil, false
+	}
+	defer f.Close()
+
+	doc, err := ReadIR(f)
+	if err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+// store saves doc under key. The entry is written to a temporary file and
+// renamed, so concurrent conversions never read a partial entry.
+func (pc *parseCache) store(key string, doc *model.Document) error {
+	name := pc.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteIR(tmp, doc); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// countingParser wraps the Markdown parser and counts parse calls.
+type countingParser struct {
+	parser.Parser
+	calls atomic.Int32
+}
+
+func (p *countingParser) Parse(content []byte, basePath string) (*model.Document, error) {
+	p.calls.Add(1)
+	return p.Parser.Parse(content, basePath)
+}
+
+func TestConverter_Convert_Cache(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01-one.md": "# One\n\nFirst.\n",
+		"02-two.md": "# Two\n\nSecond.\n",
+	})
+
+	counter := &countingParser{Parser: parser.NewMarkdownParser()}
+	conv := New()
+	conv.RegisterParser(parser.FormatMarkdown, counter)
+
+	opts := Options{
+		OutputPath: filepath.Join(dir, "book.epub"),
+		CacheDir:   filepath.Join(dir, "cache"),
+	}
+	first, err := conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), counter.calls.Load())
+
+	second, err := conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), counter.calls.Load(), "unchanged files are not parsed again")
+	assert.Equal(t, first.Stats.ChapterCount, second.Stats.ChapterCount)
+
+	// Only the changed file is parsed again
+	writeFiles(t, dir, map[string]string{"02-two.md": "# Two\n\nRevised.\n"})
+	_, err = conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), counter.calls.Load())
+
+	// Options that change parser output are part of the key
+	opts.Scripts = parser.ScriptPolicy{Inline: true}
+	_, err = conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(5), counter.calls.Load())
+}
+
+func TestParseCache_CorruptEntry(t *testing.T) {
+	cache, err := newParseCache(t.TempDir())
+	require.NoError(t, err)
+
+	key := parseKey([]byte("# One"), parser.NewMarkdownParser(), parser.FormatMarkdown, ".", Options{})
+	require.NoError(t, cache.store(key, model.NewDocument()))
+	_, ok := cache.load(key)
+	assert.True(t, ok)
+
+	require.NoError(t, os.WriteFile(cache.path(key), []byte("{"), 0o644))
+	_, ok = cache.load(key)
+	assert.False(t, ok)
+}
+
+func TestNewParseCache_Disabled(t *testing.T) {
+	cache, err := newParseCache("")
+	require.NoError(t, err)
+	assert.Nil(t, cache)
+}
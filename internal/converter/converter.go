@@ -11,10 +11,16 @@ package converter
 import (
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/dauquangthanh/epub-converter/internal/epub"
@@ -31,26 +37,123 @@ var (
 	ErrConversionFailed = errors.New("conversion failed")
 )
 
+// ParseError is returned when an input cannot be parsed. It wraps the
+// parser's error, so errors.Is still matches parser sentinels.
+type ParseError struct {
+	File string // Input file, empty for content read from stdin
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return "parsing content: " + e.Err.Error()
+	}
+	return "parsing " + e.File + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
 // Options configures the conversion process.
 type Options struct {
-	OutputPath  string          // Output EPUB file path
-	InputFormat string          // Force input format (md, html, pdf)
-	CLIMetadata *model.Metadata // Metadata overrides from CLI flags
+	OutputPath   string              // Output EPUB file path, or directory when Unpacked is set
+	NameTemplate string              // Output path named from the book's metadata, such as "{title}-{author}.epub", when OutputPath is empty
+	Unpacked     bool                // Write the EPUB contents as a directory tree at OutputPath instead of a zip
+	InputFormat  string              // Force input format (md, html, pdf)
+	CLIMetadata  *model.Metadata     // Metadata overrides from CLI flags
+	TemplateDir  string              // Directory with custom XHTML/OPF templates
+	EPUB         epub.Options        // EPUB generation options
+	Audio        []string            // Audio files to embed in the package
+	Scripts      parser.ScriptPolicy // JavaScript preserved by the HTML parser
+	Bibliography string              // BibTeX or CSL-JSON file with citation references
+	Glossary     string              // YAML file mapping glossary terms to definitions
+	Lexicons     []string            // PLS pronunciation lexicons, or YAML files mapping terms to IPA pronunciations
+	Stylesheets  []string            // CSS files linked from every chapter, ahead of the chapter's own stylesheets
+	Notes        string              // Footnote placement: model.NotesPopup (default), model.NotesChapter, or model.NotesBook
+	Typography   bool                // Convert straight quotes, dashes, and ellipses using the book language
+	Hyphenate    bool                // Insert soft hyphens into long words using the dictionary of each chapter's language
+	Hyphenation  string              // TeX hyphenation pattern file used for the book language instead of the built-in dictionary
+	Recursive    bool                // Include files in subdirectories of directory inputs
+	Exclude      []string            // Glob patterns of input files to skip, in .toepubignore syntax
+	SplitLevel   int                 // Start a new XHTML file at h1 (1) or h1 and h2 (2); 0 keeps one per input
+	Gallery      []string            // Directories of images made into photo pages after the inputs
+	GalleryGrid  int                 // Images per gallery page, laid out in a grid; 0 or 1 gives each image a page
+	Progress     ProgressFunc        // Called as files are parsed, images processed, and chapters written
+	Hooks        []DocumentHook      // Run in order on the finished document before the EPUB is built
+	Logger       *slog.Logger        // Receives pipeline events; nil uses slog.Default()
+	EmitIR       string              // Write the parsed document as JSON to this path instead of building an EPUB
+	CacheDir     string              // Directory caching parsed input files; unchanged files skip parsing
+	Fetcher      ResourceFetcher     // Reads images and stylesheets; nil streams local files from disk
+	MaxMemory    int64               // Bytes of text and resource data held in memory, spilling resources to disk; 0 means no limit
+	MaxSize      int64               // Warn when the EPUB is larger, naming its largest resources; 0 means no limit
+	EPUBCheck    bool                // Run epubcheck on the written EPUB, failing with ErrEPUBCheck on errors; ConvertReader ignores it
+	Manifest     *ManifestOptions    // Write a JSON build manifest beside the EPUB, at ManifestPath(OutputPath); ConvertReader ignores it
+	Workers      int                 // Images and content documents processed at once; 0 uses GOMAXPROCS
+	Incremental  bool                // Keep parsed files and rendered chapters in the Converter for the next conversion of the same book
+	Events       EventHandler        // Receives warnings and notes as they happen, in addition to the result
+	RemoteImages RemoteImages        // Download images referenced by URL instead of linking them
+	WebP         string              // WebPConvert (default), WebPKeep, or WebPFallback
+	MaxImageSize ImageSize           // Downscale larger JPEG and PNG images to fit; zero keeps their size
+	Grayscale    bool                // Convert JPEG and PNG images to grayscale, for e-ink devices
+	Contrast     float64             // Contrast factor for grayscale images, such as 1.2; 0 or 1 leaves it unchanged
+	Cover        CoverOptions        // Normalize the cover image to a target size
+	FillAltText  bool                // Give images without alt text one from their caption, title, or file name
+	AnimatedGIF  string              // GIFKeep (default), GIFPNG, or GIFStatic
+	Strict       bool                // Fail on problems in the built book, such as malformed XHTML or broken links, instead of warning
+	CheckA11y    bool                // Audit the built book for accessibility problems, reported as warnings
+}
+
+// logger returns the logger for pipeline events.
+func (o Options) logger() *slog.Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return slog.Default()
+}
+
+// DocumentHook rewrites a parsed document before the EPUB is built, such
+// as to edit chapters, add pages, or filter resources. Returning an error
+// stops the conversion.
+type DocumentHook func(doc *model.Document) error
+
+// Progress stages reported to a ProgressFunc.
+const (
+	StageParse  = "parse"  // An input file was parsed
+	StageImages = "images" // An image was processed
+	StageWrite  = "write"  // A chapter was written to the EPUB
+)
+
+// ProgressFunc receives the number of items completed in a stage out of
+// the stage's total.
+type ProgressFunc func(stage string, current, total int)
+
+// report calls f if it is set.
+func (f ProgressFunc) report(stage string, current, total int) {
+	if f != nil {
+		f(stage, current, total)
+	}
 }
 
-// Converter orchestrates the document conversion pipeline.
+// Converter orchestrates the document conversion pipeline. Conversions
+// keep their state per call, so one Converter can serve many goroutines
+// once its parsers are registered.
 type Converter struct {
-	parsers  map[parser.Format]parser.Parser
-	builder  *epub.Builder
+	parsers    map[parser.Format]parser.Parser
+	extensions map[string]parser.Format // Lowercase file extension to format
 	imgHandler *ImageHandler
+	builds     *buildCache  // Kept between conversions with Options.Incremental
+	outputs    *outputNames // Output files named from templates, by book
 }
 
 // New creates a new Converter with default parsers.
 func New() *Converter {
 	c := &Converter{
 		parsers:    make(map[parser.Format]parser.Parser),
-		builder:    epub.NewBuilder(),
+		extensions: make(map[string]parser.Format),
 		imgHandler: NewImageHandler(),
+		builds:     newBuildCache(),
+		outputs:    newOutputNames(),
 	}
 
 	// Register default parsers
@@ -61,25 +164,36 @@ func New() *Converter {
 	return c
 }
 
-// RegisterParser adds a parser for a specific format.
+// RegisterParser adds a parser for a specific format. Files with one of
+// the parser's SupportedExtensions are detected as that format, replacing
+// any earlier parser registered for the same extension.
 func (c *Converter) RegisterParser(format parser.Format, p parser.Parser) {
 	c.parsers[format] = p
+	for _, ext := range p.SupportedExtensions() {
+		ext = strings.ToLower(ext)
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		c.extensions[ext] = format
+	}
 }
 
 // Convert converts input files to EPUB format.
 func (c *Converter) Convert(inputs []string, opts Options) (*model.ConversionResult, error) {
 	start := time.Now()
+	log := opts.logger()
 	result := &model.ConversionResult{
 		Success:  false,
-		Warnings: make([]string, 0),
+		Warnings: make([]model.Warning, 0),
 	}
+	rep := newReporter(result, opts)
 
-	if len(inputs) == 0 {
+	if len(inputs) == 0 && len(opts.Gallery) == 0 {
 		return result, ErrNoInput
 	}
 
 	// Expand directories and validate inputs
-	files, err := c.expandInputs(inputs)
+	files, err := c.expandInputs(inputs, opts)
 	if err != nil {
 		return result, err
 	}
@@ -87,71 +201,165 @@ func (c *Converter) Convert(inputs []string, opts Options) (*model.ConversionRes
 	if len(files) == 0 {
 		return result, fmt.Errorf("%w: no supported files found", ErrNoInput)
 	}
+	log.Debug("expanded inputs", "stage", "input", "files", len(files))
+
+	// Images and stylesheets of archive inputs are read from the archives
+	archives := inputArchives(files)
+	if len(archives) > 0 {
+		opts.Fetcher = archiveFetcher{archives: archives, next: opts.Fetcher}
+	}
 
 	// Detect format from first file if not specified
-	format := c.detectFormat(files[0], opts.InputFormat)
-	if format == parser.FormatUnknown {
-		return result, fmt.Errorf("%w: cannot detect format for %s", ErrUnsupportedFmt, files[0])
+	format, err := c.inputFormat(files, opts.InputFormat)
+	if err != nil {
+		return result, err
 	}
 
-	// Get parser for format
-	p := c.getParser(format)
-	if p == nil {
-		return result, fmt.Errorf("%w: no parser for format %s", ErrUnsupportedFmt, format)
+	builder, err := c.newBuilder(opts, rep)
+	if err != nil {
+		return result, err
+	}
+
+	// Get parser for format; galleries need none
+	var p parser.Parser
+	if format != parser.FormatGallery {
+		p = c.getParser(format)
+		if p == nil {
+			return result, fmt.Errorf("%w: no parser for format %s", ErrUnsupportedFmt, format)
+		}
+		p = c.configureParser(p, opts, rep)
+	}
+
+	cache, err := newParseCache(opts.CacheDir)
+	if err != nil {
+		return result, err
 	}
 
-	// Parse all input files
+	budget := newMemoryBudget(opts)
+	defer budget.cleanup()
+
+	// Parse all input files, noting the file each chapter came from
 	doc := model.NewDocument()
+	var sources []string
 	for i, file := range files {
-		content, err := os.ReadFile(file)
+		parseStart := time.Now()
+		rep.file = file.Path
+		parsedDoc, err := c.parseInput(file, p, format, doc, budget, cache, opts)
 		if err != nil {
-			return result, fmt.Errorf("reading %s: %w", file, err)
+			return result, err
 		}
 
-		basePath := filepath.Dir(file)
-		parsedDoc, err := p.Parse(content, basePath)
-		if err != nil {
-			return result, fmt.Errorf("parsing %s: %w", file, err)
-		}
+		log.Info("parsed file", "stage", "parse", "file", file.Path, "format", fileFormat(file, format).String(),
+			"chapters", len(parsedDoc.Chapters), "duration", time.Since(parseStart))
+
+		opts.Progress.report(StageParse, i+1, len(files))
 
 		// Merge parsed content into main document
-		c.mergeDocument(doc, parsedDoc, i)
+		merged := len(doc.Chapters)
+		c.mergeDocument(doc, parsedDoc, i, file.Depth, rep)
+		if file.Type != "" {
+			for j := merged; j < len(doc.Chapters); j++ {
+				doc.Chapters[j].Type = file.Type
+			}
+		}
+		for range doc.Chapters[merged:] {
+			sources = append(sources, filepath.Clean(file.Path))
+		}
+		if err := budget.checkText(doc); err != nil {
+			return result, err
+		}
+	}
+	rep.file = ""
+	linkInputFiles(doc, sources)
+	if opts.Incremental {
+		// Forget files that are no longer part of the book
+		c.builds.sweep()
 	}
 
-	// Apply CLI metadata overrides
-	if opts.CLIMetadata != nil {
-		doc.Metadata.Merge(opts.CLIMetadata)
+	if err := prepareDocument(doc, opts); err != nil {
+		return result, err
 	}
 
 	// Ensure document has a title
 	if doc.Metadata.Title == "" {
 		// Use first input file name as title
-		doc.Metadata.Title = strings.TrimSuffix(filepath.Base(files[0]), filepath.Ext(files[0]))
+		doc.Metadata.Title = files[0].bookName()
 	}
 
 	// Process cover image if specified
 	if doc.Metadata.CoverImage != "" {
-		if err := c.processCoverImage(doc, result); err != nil {
-			result.AddWarning(fmt.Sprintf("Cover image: %s", err))
+		if err := c.processCoverImage(doc, rep, opts); err != nil {
+			log.Debug("skipped cover image", "stage", "images", "file", doc.Metadata.CoverImage, "error", err)
+			rep.warn(model.Warning{
+				Code:    model.WarnCoverImage,
+				File:    doc.Metadata.CoverImage,
+				Message: fmt.Sprintf("Cover image: %s", err),
+			})
+		} else {
+			log.Debug("embedded cover image", "stage", "images", "file", doc.Metadata.CoverImage)
 		}
 	}
 
-	// Process images
-	c.processImages(doc, result)
+	// Attach audio files given explicitly
+	if err := c.attachAudio(doc, opts.Audio); err != nil {
+		return result, err
+	}
 
-	// Build EPUB
-	epubData, err := c.builder.Build(doc)
-	if err != nil {
-		return result, fmt.Errorf("building EPUB: %w", err)
+	// Process images, stylesheets, and media
+	c.embedRemoteImages(doc, rep, opts)
+	c.processImages(doc, rep, opts)
+	c.dedupImages(doc, opts)
+	c.addImageFallbacks(doc, rep, opts)
+	c.processStylesheets(doc, rep, opts.Fetcher)
+	loadArchiveMedia(doc, archives)
+	c.processMedia(doc, rep)
+	c.checkAltText(doc, rep, opts)
+	log.Debug("processed resources", "stage", "resources", "resources", len(doc.Resources))
+
+	// Stop after the parse phase when only the document IR is wanted
+	if opts.EmitIR != "" {
+		size, err := emitIR(opts.EmitIR, doc)
+		if err != nil {
+			return result, err
+		}
+		log.Info("wrote document IR", "stage", "write", "file", opts.EmitIR, "bytes", size)
+
+		result.Success = true
+		result.OutputPath = opts.EmitIR
+		result.Stats = model.ConversionStats{
+			InputFormat:  format.String(),
+			InputFiles:   len(files),
+			ChapterCount: len(doc.Chapters),
+			ImageCount:   len(doc.Resources),
+			OutputSize:   size,
+			Duration:     time.Since(start),
+		}
+		return result, nil
+	}
+
+	if err := runHooks(doc, opts.Hooks, log); err != nil {
+		return result, err
+	}
+
+	// Spill resource data to disk if the document is over the memory budget
+	if err := budget.fit(doc); err != nil {
+		return result, err
 	}
 
-	// Write output file
+	// Build EPUB, streaming it to the output file
 	outputPath := opts.OutputPath
+	if outputPath == "" && opts.NameTemplate != "" {
+		outputPath, err = c.templatedOutputPath(doc, strings.Join(inputs, "\x00"), files[0].bookName(), opts)
+		if err != nil {
+			return result, err
+		}
+	}
 	if outputPath == "" {
-		outputPath = strings.TrimSuffix(filepath.Base(files[0]), filepath.Ext(files[0])) + ".epub"
+		outputPath = files[0].bookName() + ".epub"
 	}
 
-	if err := c.writeOutput(outputPath, epubData); err != nil {
+	outputSize, err := c.writeOutput(outputPath, doc, builder, opts.Unpacked, log)
+	if err != nil {
 		return result, err
 	}
 
@@ -163,9 +371,16 @@ func (c *Converter) Convert(inputs []string, opts Options) (*model.ConversionRes
 		InputFiles:   len(files),
 		ChapterCount: len(doc.Chapters),
 		ImageCount:   len(doc.Resources),
-		OutputSize:   int64(len(epubData)),
+		OutputSize:   outputSize,
 		Duration:     time.Since(start),
 	}
+	checkOutputSize(result, doc, rep, opts)
+	if err := writeManifest(result, doc, files, opts); err != nil {
+		return result, err
+	}
+	if err := runEPUBCheck(result, rep, opts); err != nil {
+		return result, err
+	}
 
 	return result, nil
 }
@@ -173,10 +388,12 @@ func (c *Converter) Convert(inputs []string, opts Options) (*model.ConversionRes
 // ConvertContent converts raw content bytes to EPUB.
 func (c *Converter) ConvertContent(content []byte, opts Options) (*model.ConversionResult, error) {
 	start := time.Now()
+	log := opts.logger()
 	result := &model.ConversionResult{
 		Success:  false,
-		Warnings: make([]string, 0),
+		Warnings: make([]model.Warning, 0),
 	}
+	rep := newReporter(result, opts)
 
 	// Detect format
 	format := c.detectFormatFromString(opts.InputFormat)
@@ -184,21 +401,35 @@ func (c *Converter) ConvertContent(content []byte, opts Options) (*model.Convers
 		format = parser.FormatMarkdown // Default to markdown
 	}
 
+	builder, err := c.newBuilder(opts, rep)
+	if err != nil {
+		return result, err
+	}
+
 	// Get parser
 	p := c.getParser(format)
 	if p == nil {
 		return result, fmt.Errorf("%w: no parser for format %s", ErrUnsupportedFmt, format)
 	}
+	p = c.configureParser(p, opts, rep)
+
+	budget := newMemoryBudget(opts)
+	defer budget.cleanup()
+	if err := budget.checkInput("", int64(len(content)), nil); err != nil {
+		return result, err
+	}
 
 	// Parse content
 	doc, err := p.Parse(content, ".")
 	if err != nil {
-		return result, fmt.Errorf("parsing content: %w", err)
+		return result, &ParseError{Err: err}
 	}
+	log.Info("parsed content", "stage", "parse", "format", format.String(),
+		"chapters", len(doc.Chapters), "duration", time.Since(start))
+	opts.Progress.report(StageParse, 1, 1)
 
-	// Apply CLI metadata overrides
-	if opts.CLIMetadata != nil {
-		doc.Metadata.Merge(opts.CLIMetadata)
+	if err := prepareDocument(doc, opts); err != nil {
+		return result, err
 	}
 
 	// Ensure document has a title
@@ -206,19 +437,53 @@ func (c *Converter) ConvertContent(content []byte, opts Options) (*model.Convers
 		doc.Metadata.Title = "Untitled Document"
 	}
 
-	// Build EPUB
-	epubData, err := c.builder.Build(doc)
-	if err != nil {
-		return result, fmt.Errorf("building EPUB: %w", err)
+	c.embedRemoteImages(doc, rep, opts)
+	c.checkAltText(doc, rep, opts)
+
+	// Stop after the parse phase when only the document IR is wanted
+	if opts.EmitIR != "" {
+		size, err := emitIR(opts.EmitIR, doc)
+		if err != nil {
+			return result, err
+		}
+		log.Info("wrote document IR", "stage", "write", "file", opts.EmitIR, "bytes", size)
+
+		result.Success = true
+		result.OutputPath = opts.EmitIR
+		result.Stats = model.ConversionStats{
+			InputFormat:  format.String(),
+			InputFiles:   1,
+			ChapterCount: len(doc.Chapters),
+			ImageCount:   len(doc.Resources),
+			OutputSize:   size,
+			Duration:     time.Since(start),
+		}
+		return result, nil
+	}
+
+	if err := runHooks(doc, opts.Hooks, log); err != nil {
+		return result, err
 	}
 
-	// Write output
+	// Spill resource data to disk if the document is over the memory budget
+	if err := budget.fit(doc); err != nil {
+		return result, err
+	}
+
+	// Build EPUB, streaming it to the output file
 	outputPath := opts.OutputPath
+	if outputPath == "" && opts.NameTemplate != "" {
+		outputPath, err = c.templatedOutputPath(doc, "", "output", opts)
+		if err != nil {
+			return result, err
+		}
+	}
 	if outputPath == "" {
 		outputPath = "output.epub"
 	}
 
-	if err := c.writeOutput(outputPath, epubData); err != nil {
+	outputSize, err := c.writeOutput(outputPath, doc, builder, opts.Unpacked, log)
+	if err != nil {
 		return result, err
 	}
 
@@ -230,16 +495,501 @@ func (c *Converter) ConvertContent(content []byte, opts Options) (*model.Convers
 		InputFiles:   1,
 		ChapterCount: len(doc.Chapters),
 		ImageCount:   len(doc.Resources),
-		OutputSize:   int64(len(epubData)),
+		OutputSize:   outputSize,
 		Duration:     time.Since(start),
 	}
+	checkOutputSize(result, doc, rep, opts)
+	if err := writeManifest(result, doc, nil, opts); err != nil {
+		return result, err
+	}
+	if err := runEPUBCheck(result, rep, opts); err != nil {
+		return result, err
+	}
 
 	return result, nil
 }
 
-// expandInputs expands directories and validates file existence.
-func (c *Converter) expandInputs(inputs []string) ([]string, error) {
-	var files []string
+// ConvertReader converts content read from r to an EPUB written to w,
+// without reading input files or writing output files. Images,
+// stylesheets, and the bibliography and glossary files named in front
+// matter are read through opts.Fetcher; without one they are dropped with
+// a warning.
+func (c *Converter) ConvertReader(r io.Reader, format parser.Format, w io.Writer, opts Options) (*model.ConversionResult, error) {
+	start := time.Now()
+	log := opts.logger()
+	result := &model.ConversionResult{
+		Success:  false,
+		Warnings: make([]model.Warning, 0),
+	}
+	rep := newReporter(result, opts)
+
+	builder, err := c.newBuilder(opts, rep)
+	if err != nil {
+		return result, err
+	}
+
+	p := c.getParser(format)
+	if p == nil {
+		return result, fmt.Errorf("%w: no parser for format %s", ErrUnsupportedFmt, format)
+	}
+	p = c.configureParser(p, opts, rep)
+	if fp, ok := p.(parser.FetchParser); ok && opts.Fetcher == nil {
+		// Content from a stream must not name local files to read
+		p = fp.WithFetcher(nil)
+	}
+
+	// Read at most one byte over the budget, enough to tell it is exceeded
+	budget := newMemoryBudget(opts)
+	defer budget.cleanup()
+	if budget != nil {
+		r = io.LimitReader(r, budget.limit+1)
+	}
+
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return result, fmt.Errorf("reading input: %w", err)
+	}
+	if err := budget.checkInput("", int64(len(content)), nil); err != nil {
+		return result, err
+	}
+
+	doc, err := p.Parse(content, "")
+	if err != nil {
+		return result, &ParseError{Err: err}
+	}
+	log.Info("parsed content", "stage", "parse", "format", format.String(),
+		"chapters", len(doc.Chapters), "duration", time.Since(start))
+	opts.Progress.report(StageParse, 1, 1)
+
+	if err := prepareDocument(doc, opts); err != nil {
+		return result, err
+	}
+
+	// Ensure document has a title
+	if doc.Metadata.Title == "" {
+		doc.Metadata.Title = "Untitled Document"
+	}
+
+	c.embedRemoteImages(doc, rep, opts)
+
+	// Without a fetcher, resources on disk are not read for streams
+	if opts.Fetcher != nil {
+		c.processImages(doc, rep, opts)
+		c.dedupImages(doc, opts)
+		c.addImageFallbacks(doc, rep, opts)
+		c.processStylesheets(doc, rep, opts.Fetcher)
+	} else {
+		dropFileResources(doc, rep)
+		c.dedupImages(doc, opts)
+	}
+	c.checkAltText(doc, rep, opts)
+
+	if err := runHooks(doc, opts.Hooks, log); err != nil {
+		return result, err
+	}
+
+	// Spill resource data to disk if the document is over the memory budget
+	if err := budget.fit(doc); err != nil {
+		return result, err
+	}
+
+	buildStart := time.Now()
+	cw := &countingWriter{w: w}
+	if err := builder.WriteTo(doc, cw); err != nil {
+		return result, err
+	}
+	log.Info("built EPUB", "stage", "build", "chapters", len(doc.Chapters),
+		"resources", len(doc.Resources), "duration", time.Since(buildStart))
+
+	// Build result
+	result.Success = true
+	result.Stats = model.ConversionStats{
+		InputFormat:  format.String(),
+		InputFiles:   1,
+		ChapterCount: len(doc.Chapters),
+		ImageCount:   len(doc.Resources),
+		OutputSize:   cw.n,
+		Duration:     time.Since(start),
+	}
+	checkOutputSize(result, doc, rep, opts)
+	if err := runEPUBCheck(result, rep, opts); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// parseInput reads and parses an input file, or makes the photo pages of a
+// gallery directory, checking the file against the memory budget.
+func (c *Converter) parseInput(file inputFile, p parser.Parser, format parser.Format, doc *model.Document, budget *memoryBudget, cache *parseCache, opts Options) (*model.Document, error) {
+	if file.Gallery != nil {
+		parsed, err := parser.ParseGallery(file.Gallery, opts.GalleryGrid, opts.fetcher())
+		if err != nil {
+			return nil, &ParseError{File: file.Path, Err: err}
+		}
+		return parsed, nil
+	}
+
+	if info, err := file.stat(); err == nil {
+		if err := budget.checkInput(file.Path, info.Size(), doc); err != nil {
+			return nil, err
+		}
+	}
+	content, err := file.read()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file.Path, err)
+	}
+
+	parsed, err := c.parseFile(p, format, content, filepath.Dir(file.Path), cache, opts)
+	if err != nil {
+		return nil, &ParseError{File: file.Path, Err: err}
+	}
+	return parsed, nil
+}
+
+// parseFile parses the content of an input file, reusing the document
+// parsed by an earlier conversion with Options.Incremental, or cached in
+// Options.CacheDir, for the same content and options.
+func (c *Converter) parseFile(p parser.Parser, format parser.Format, content []byte, basePath string, cache *parseCache, opts Options) (*model.Document, error) {
+	if cache == nil && !opts.Incremental {
+		return p.Parse(content, basePath)
+	}
+
+	log := opts.logger()
+	key := parseKey(content, p, format, basePath, opts)
+	if opts.Incremental {
+		if doc, ok := c.builds.load(key); ok {
+			log.Debug("reused document from the last build", "stage", "parse", "key", key)
+			return doc, nil
+		}
+	}
+	remember := func(doc *model.Document) {
+		if !opts.Incremental {
+			return
+		}
+		if err := c.builds.store(key, doc); err != nil {
+			log.Warn("could not keep parsed document", "stage", "parse", "error", err)
+		}
+	}
+
+	if cache != nil {
+		if doc, ok := cache.load(key); ok {
+			log.Debug("reused cached document", "stage", "parse", "key", key)
+			remember(doc)
+			return doc, nil
+		}
+	}
+
+	doc, err := p.Parse(content, basePath)
+	if err != nil {
+		return nil, err
+	}
+	remember(doc)
+	if cache != nil {
+		if err := cache.store(key, doc); err != nil {
+			// A cache that cannot be written only costs the next run a parse
+			log.Warn("could not cache parsed document", "stage", "parse", "error", err)
+		}
+	}
+	return doc, nil
+}
+
+// prepareDocument applies the steps shared by all conversions once the
+// input is parsed: chapter splitting, matter grouping, metadata overrides,
+// bibliography and glossary files, shared stylesheets, typography, and
+// hyphenation.
+func prepareDocument(doc *model.Document, opts Options) error {
+	log := opts.logger()
+
+	// Start new XHTML files at headings when requested
+	if opts.SplitLevel > 0 {
+		splitChapters(doc, opts.SplitLevel)
+		log.Debug("split chapters", "stage", "split", "level", opts.SplitLevel, "chapters", len(doc.Chapters))
+	}
+
+	// Group front, body, and back matter in the reading order
+	groupMatter(doc)
+
+	// Apply CLI metadata overrides
+	if opts.CLIMetadata != nil {
+		doc.Metadata.Merge(opts.CLIMetadata)
+	}
+	if err := doc.Metadata.NormalizeISBNs(); err != nil {
+		return err
+	}
+	if err := doc.Metadata.CheckUniqueID(); err != nil {
+		return err
+	}
+	// Chapters in other languages make the book multilingual
+	for _, chapter := range doc.Chapters {
+		doc.Metadata.AddLanguage(chapter.Language)
+	}
+
+	if err := loadBibliography(doc, opts.Bibliography); err != nil {
+		return err
+	}
+	if opts.Bibliography != "" {
+		log.Info("loaded bibliography", "stage", "bibliography", "file", opts.Bibliography, "references", len(doc.References))
+	}
+
+	if err := loadGlossary(doc, opts.Glossary); err != nil {
+		return err
+	}
+	if opts.Glossary != "" {
+		log.Info("loaded glossary", "stage", "glossary", "file", opts.Glossary, "terms", len(doc.Glossary))
+	}
+
+	if err := loadLexicons(doc, opts.Lexicons, opts.fetcher()); err != nil {
+		return err
+	}
+	if len(doc.Pronunciations) > 0 {
+		log.Info("loaded pronunciations", "stage", "pronunciation", "terms", len(doc.Pronunciations))
+	}
+
+	if err := linkStylesheets(doc, opts.Stylesheets); err != nil {
+		return err
+	}
+
+	// Normalize quotes and dashes once the book language is known
+	if opts.Typography {
+		applyTypography(doc)
+		log.Debug("applied typography", "stage", "typography", "language", doc.Metadata.Language)
+	}
+
+	// Hyphenate last, once the text is final
+	if opts.Hyphenate {
+		custom, err := loadHyphenationPatterns(opts.Hyphenation)
+		if err != nil {
+			return err
+		}
+		if missing := applyHyphenation(doc, custom); len(missing) > 0 {
+			log.Warn("no hyphenation dictionary, chapters left unhyphenated", "stage", "hyphenation", "languages", missing)
+		}
+		log.Debug("applied hyphenation", "stage", "hyphenation", "language", doc.Metadata.Language)
+	}
+	return nil
+}
+
+// runHooks runs the document hooks in order, stopping at the first error.
+func runHooks(doc *model.Document, hooks []DocumentHook, log *slog.Logger) error {
+	for i, hook := range hooks {
+		if err := hook(doc); err != nil {
+			return fmt.Errorf("document hook %d: %w", i+1, err)
+		}
+	}
+	if len(hooks) > 0 {
+		log.Debug("ran document hooks", "stage", "hooks", "hooks", len(hooks))
+	}
+	return nil
+}
+
+// dropFileResources removes resources that would be read from a source
+// path at build time, along with chapter links to dropped stylesheets.
+func dropFileResources(doc *model.Document, rep *reporter) {
+	kept := make([]model.Resource, 0, len(doc.Resources))
+	dropped := make(map[string]bool)
+
+	for _, res := range doc.Resources {
+		if len(res.Data) == 0 && res.SourcePath != "" {
+			rep.warn(model.Warning{
+				Code:    model.WarnResourceNotLoaded,
+				File:    res.SourcePath,
+				Message: fmt.Sprintf("Resource %s: not available when converting a stream", res.SourcePath),
+			})
+			dropped[res.FileName] = true
+			continue
+		}
+		kept = append(kept, res)
+	}
+	doc.Resources = kept
+
+	if len(dropped) == 0 {
+		return
+	}
+	for i := range doc.Chapters {
+		stylesheets := doc.Chapters[i].Stylesheets[:0]
+		for _, css := range doc.Chapters[i].Stylesheets {
+			if !dropped[css] {
+				stylesheets = append(stylesheets, css)
+			}
+		}
+		doc.Chapters[i].Stylesheets = stylesheets
+	}
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// retargetTOCEntries rewrites entry hrefs to renamed chapter files,
+// keeping fragment identifiers.
+func retargetTOCEntries(entries []model.TOCEntry, renamed map[string]string) []model.TOCEntry {
+	result := make([]model.TOCEntry, 0, len(entries))
+	for _, entry := range entries {
+		file, fragment, hasFragment := strings.Cut(entry.Href, "#")
+		if newFile, ok := renamed[file]; ok {
+			entry.Href = newFile
+			if hasFragment {
+				entry.Href += "#" + fragment
+			}
+		}
+		entry.Children = retargetTOCEntries(entry.Children, renamed)
+		result = append(result, entry)
+	}
+	return result
+}
+
+// loadBibliography adds references from a bibliography file given on the
+// command line, replacing references with the same key from front matter.
+func loadBibliography(doc *model.Document, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	refs, err := parser.LoadBibliography(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return fmt.Errorf("loading bibliography: %w", err)
+	}
+	for _, ref := range refs {
+		doc.AddReference(ref)
+	}
+	return nil
+}
+
+// loadGlossary adds terms from a glossary file given on the command line,
+// replacing definitions of the same terms from the input documents.
+func loadGlossary(doc *model.Document, path string) error {
+	if path == "" {
+		return nil
+	}
+
+	entries, err := parser.LoadGlossary(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return fmt.Errorf("loading glossary: %w", err)
+	}
+	for _, entry := range entries {
+		doc.AddGlossaryEntry(entry)
+	}
+	return nil
+}
+
+// loadLexicons attaches PLS pronunciation lexicons given on the command
+// line and adds the pronunciations of YAML pronunciation maps, which the
+// builder marks in the text and gathers into a lexicon of their own.
+// Files are read with fetcher, so those in archive inputs are found.
+func loadLexicons(doc *model.Document, paths []string, fetcher ResourceFetcher) error {
+	for _, path := range paths {
+		data, err := fetcher.Fetch(path)
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(filepath.Ext(path), ".pls") {
+			lexicon := parser.LexiconResource(path)
+			lexicon.Data = data
+			doc.AddResource(lexicon)
+			continue
+		}
+
+		entries, err := parser.ParsePronunciations(data)
+		if err != nil {
+			return fmt.Errorf("loading pronunciations: %w", err)
+		}
+		for _, entry := range entries {
+			doc.AddPronunciation(entry)
+		}
+	}
+	return nil
+}
+
+// linkStylesheets adds the CSS files at paths to the document and links
+// them from every chapter, ahead of the chapter's own stylesheets. A file
+// the chapters already link keeps one link, in the shared position.
+func linkStylesheets(doc *model.Document, paths []string) error {
+	var hrefs []string
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%w: %s", ErrFileNotFound, path)
+		}
+		css, ok := parser.StylesheetResource(path)
+		if !ok {
+			continue
+		}
+		if !slices.ContainsFunc(doc.Resources, func(res model.Resource) bool { return res.FileName == css.FileName }) {
+			doc.AddResource(css)
+		}
+		hrefs = append(hrefs, css.FileName)
+	}
+	if len(hrefs) == 0 {
+		return nil
+	}
+
+	for i := range doc.Chapters {
+		own := slices.DeleteFunc(doc.Chapters[i].Stylesheets, func(href string) bool { return slices.Contains(hrefs, href) })
+		doc.Chapters[i].Stylesheets = append(slices.Clone(hrefs), own...)
+	}
+	return nil
+}
+
+// newBuilder creates an EPUB builder for one conversion, with EPUB options
+// and templates from opts.TemplateDir, reporting problems in the built
+// documents to rep. With opts.Incremental, content documents rendered by
+// the last build are reused.
+func (c *Converter) newBuilder(opts Options, rep *reporter) (*epub.Builder, error) {
+	builder := epub.NewBuilder()
+
+	epubOpts := opts.EPUB
+	epubOpts.Strict = epubOpts.Strict || opts.Strict
+	epubOpts.CheckA11y = epubOpts.CheckA11y || opts.CheckA11y
+	if epubOpts.Notes == "" {
+		epubOpts.Notes = opts.Notes
+	}
+	epubOpts.Warn = rep.warn
+	if epubOpts.Workers == 0 {
+		epubOpts.Workers = opts.Workers
+	}
+	if opts.Incremental && epubOpts.Chapters == nil {
+		epubOpts.Chapters = c.builds.chapters
+	}
+	if opts.Progress != nil {
+		epubOpts.Progress = func(current, total int) {
+			opts.Progress(StageWrite, current, total)
+		}
+	}
+	builder.SetOptions(epubOpts)
+
+	if opts.TemplateDir == "" {
+		return builder, nil
+	}
+
+	templates, err := epub.LoadTemplates(opts.TemplateDir)
+	if err != nil {
+		return nil, fmt.Errorf("loading templates: %w", err)
+	}
+	builder.SetTemplates(templates)
+	return builder, nil
+}
+
+// expandInputs expands directories and archives and validates file
+// existence. Files given explicitly keep their order; directories and
+// archives expand in the order of their order file, or by front matter
+// weight and then alphabetically.
+func (c *Converter) expandInputs(inputs []string, opts Options) ([]inputFile, error) {
+	var files []inputFile
+	exclude := parseIgnoreRules(opts.Exclude)
 
 	for _, input := range inputs {
 		info, err := os.Stat(input)
@@ -247,54 +997,147 @@ func (c *Converter) expandInputs(inputs []string) ([]string, error) {
 			return nil, fmt.Errorf("%w: %s", ErrFileNotFound, input)
 		}
 
-		if info.IsDir() {
-			// Expand directory (non-recursive)
-			dirFiles, err := c.expandDirectory(input)
+		if !info.IsDir() {
+			if exclude.ignored(filepath.ToSlash(filepath.Clean(input)), false) {
+				continue
+			}
+			if ArchiveExtension(input) == "" {
+				files = append(files, inputFile{Path: input})
+				continue
+			}
+			tree, err := openArchiveTree(input)
 			if err != nil {
 				return nil, err
 			}
-			files = append(files, dirFiles...)
-		} else {
-			files = append(files, input)
+			archived, err := c.expandTree(tree, opts.Recursive, exclude)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, archived...)
+			continue
+		}
+
+		dirFiles, err := c.expandTree(diskTree(input), opts.Recursive, exclude)
+		if err != nil {
+			return nil, err
+		}
+		// A directory of only images is a photo book
+		if len(dirFiles) == 0 {
+			if gallery, err := expandGallery(input, exclude); err == nil {
+				files = append(files, gallery)
+				continue
+			}
+		}
+		files = append(files, dirFiles...)
+	}
+
+	for _, dir := range opts.Gallery {
+		gallery, err := expandGallery(dir, exclude)
+		if err != nil {
+			return nil, err
 		}
+		files = append(files, gallery)
 	}
 
-	// Sort files alphabetically for consistent ordering
-	sort.Strings(files)
 	return files, nil
 }
 
-// expandDirectory lists supported files in a directory.
-func (c *Converter) expandDirectory(dir string) ([]string, error) {
-	entries, err := os.ReadDir(dir)
+// expandTree lists the files of a directory or archive in reading order:
+// those its order file lists, or its supported files sorted alphabetically
+// and then by the weights their front matter declares.
+func (c *Converter) expandTree(tree inputTree, recursive bool, exclude ignoreRules) ([]inputFile, error) {
+	if orderFile, ok := findOrderFile(tree); ok {
+		listed, err := readOrderFile(tree, orderFile)
+		if err != nil {
+			return nil, err
+		}
+		var files []inputFile
+		for _, file := range listed {
+			if rel, err := filepath.Rel(tree.Dir, file.Path); err != nil || !exclude.ignored(filepath.ToSlash(rel), false) {
+				files = append(files, file)
+			}
+		}
+		return files, nil
+	}
+
+	files, err := c.expandDirectory(tree, recursive, exclude)
+	if err != nil {
+		return nil, err
+	}
+	// Sort files alphabetically for consistent ordering, then by the
+	// weights their front matter declares
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return c.orderByWeight(files), nil
+}
+
+// expandDirectory lists supported files in a directory, descending into
+// subdirectories when recursive is set. Entries matching the directory's
+// .toepubignore rules or the exclude rules are skipped.
+func (c *Converter) expandDirectory(tree inputTree, recursive bool, exclude ignoreRules) ([]inputFile, error) {
+	rules, err := loadIgnoreRules(tree.file(ignoreFileName))
 	if err != nil {
 		return nil, err
 	}
+	rules = append(rules, exclude...)
+
+	var files []inputFile
+	err = fs.WalkDir(tree.FS, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
 
-	var files []string
-	for _, entry := range entries {
 		if entry.IsDir() {
-			continue
+			if !recursive || rules.ignored(p, true) {
+				return fs.SkipDir
+			}
+			return nil
 		}
 
-		ext := strings.ToLower(filepath.Ext(entry.Name()))
-		if c.isSupportedExtension(ext) {
-			files = append(files, filepath.Join(dir, entry.Name()))
+		ext := strings.ToLower(path.Ext(entry.Name()))
+		if c.isSupportedExtension(ext) && !rules.ignored(p, false) {
+			files = append(files, tree.file(p))
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return files, nil
 }
 
-// isSupportedExtension checks if file extension is supported.
+// isSupportedExtension checks if a registered parser handles the extension.
 func (c *Converter) isSupportedExtension(ext string) bool {
-	supported := []string{".md", ".markdown", ".html", ".htm", ".pdf"}
-	for _, s := range supported {
-		if ext == s {
-			return true
+	_, ok := c.extensions[strings.ToLower(ext)]
+	return ok
+}
+
+// inputFormat determines the format of the input files from the first
+// that is not a gallery, or from the explicit format. Inputs that are all
+// galleries have the gallery format.
+func (c *Converter) inputFormat(files []inputFile, explicit string) (parser.Format, error) {
+	for _, file := range files {
+		if file.Gallery != nil {
+			continue
+		}
+		format := c.detectFormat(file.Path, explicit)
+		if format == parser.FormatUnknown {
+			return format, fmt.Errorf("%w: cannot detect format for %s", ErrUnsupportedFmt, file.Path)
 		}
+		return format, nil
+	}
+	return parser.FormatGallery, nil
+}
+
+// fileFormat returns the format of an input file of a book in format.
+func fileFormat(file inputFile, format parser.Format) parser.Format {
+	if file.Gallery != nil {
+		return parser.FormatGallery
 	}
-	return false
+	return format
 }
 
 // detectFormat determines the input format from file extension or explicit format.
@@ -303,31 +1146,23 @@ func (c *Converter) detectFormat(file string, explicit string) parser.Format {
 		return c.detectFormatFromString(explicit)
 	}
 
-	ext := strings.ToLower(filepath.Ext(file))
-	switch ext {
-	case ".md", ".markdown":
-		return parser.FormatMarkdown
-	case ".html", ".htm":
-		return parser.FormatHTML
-	case ".pdf":
-		return parser.FormatPDF
-	default:
-		return parser.FormatUnknown
+	if format, ok := c.extensions[strings.ToLower(filepath.Ext(file))]; ok {
+		return format
 	}
+	return parser.FormatUnknown
 }
 
-// detectFormatFromString converts format string to Format type.
+// detectFormatFromString resolves a format name, such as "markdown", or a
+// file extension without the dot, such as "md", to a registered format.
 func (c *Converter) detectFormatFromString(s string) parser.Format {
-	switch strings.ToLower(s) {
-	case "md", "markdown":
-		return parser.FormatMarkdown
-	case "html", "htm":
-		return parser.FormatHTML
-	case "pdf":
-		return parser.FormatPDF
-	default:
-		return parser.FormatUnknown
+	s = strings.ToLower(s)
+	if _, ok := c.parsers[parser.Format(s)]; ok {
+		return parser.Format(s)
 	}
+	if format, ok := c.extensions["."+s]; ok {
+		return format
+	}
+	return parser.FormatUnknown
 }
 
 // getParser returns the parser for the given format.
@@ -335,44 +1170,144 @@ func (c *Converter) getParser(format parser.Format) parser.Parser {
 	return c.parsers[format]
 }
 
-// mergeDocument merges a parsed document into the main document.
-func (c *Converter) mergeDocument(main, parsed *model.Document, index int) {
+// configureParser returns the parser with parser-specific options applied,
+// reporting its events to rep. Registered parsers are shared between
+// conversions and never modified.
+func (c *Converter) configureParser(p parser.Parser, opts Options, rep *reporter) parser.Parser {
+	if sp, ok := p.(parser.ScriptPolicyParser); ok {
+		p = sp.WithScriptPolicy(opts.Scripts)
+	}
+	if np, ok := p.(parser.NotesParser); ok && opts.Notes != "" {
+		p = np.WithNotes(opts.Notes)
+	}
+	if ep, ok := p.(parser.EventParser); ok {
+		p = ep.WithEvents(rep.warn)
+	}
+	if fp, ok := p.(parser.FetchParser); ok && opts.Fetcher != nil {
+		p = fp.WithFetcher(opts.Fetcher)
+	}
+	return p
+}
+
+// mergeDocument merges a parsed document into the main document. Its TOC
+// entries are nested depth levels below the previous document's entries.
+func (c *Converter) mergeDocument(main, parsed *model.Document, index, depth int, rep *reporter) {
 	// Merge metadata (first file wins, except explicit overrides)
 	if index == 0 {
 		main.Metadata = parsed.Metadata
 	}
 
+	// Merge resources. A file an earlier input already embeds from the same
+	// source is shared; a different file with the same name is renamed.
+	// This input's references follow either change.
+	existing := make(map[string]model.Resource, len(main.Resources))
+	for _, res := range main.Resources {
+		existing[strings.ToLower(res.FileName)] = res
+	}
+	moved := make(map[string]string)
+	incoming := make(map[string]bool, len(parsed.Resources))
+	var added []model.Resource
+	for _, res := range parsed.Resources {
+		if shared, ok := sharedResource(main.Resources, res); ok {
+			if shared.FileName != res.FileName {
+				moved[res.FileName] = shared.FileName
+			}
+			continue
+		}
+		incoming[strings.ToLower(res.FileName)] = true
+		added = append(added, res)
+	}
+	taken := func(name string) bool {
+		_, ok := existing[strings.ToLower(name)]
+		return ok || incoming[strings.ToLower(name)]
+	}
+	for _, res := range added {
+		if _, ok := existing[strings.ToLower(res.FileName)]; ok {
+			name, n := uniqueFileName(res.FileName, taken)
+			rep.warn(model.Warning{
+				Code:     model.WarnDuplicateID,
+				Severity: model.SeverityInfo,
+				Message:  fmt.Sprintf("%s is also the name of a different file in an earlier input; saved as %s", res.FileName, name),
+			})
+			moved[res.FileName] = name
+			res.FileName = name
+			res.ID = fmt.Sprintf("%s-%d", res.ID, n)
+		}
+		existing[strings.ToLower(res.FileName)] = res
+		main.AddResource(res)
+	}
+
+	// An empty input leaves a blank page in the reading order
+	for _, chapter := range parsed.Chapters {
+		if strings.TrimSpace(chapter.Content) == "" {
+			rep.warn(model.Warning{
+				Code:    model.WarnReadingOrder,
+				Message: fmt.Sprintf("Input %s has no content; it adds a blank page to the reading order", rep.file),
+			})
+			break
+		}
+	}
+
 	// Update chapter ordering for merged chapters
 	offset := len(main.Chapters)
+	renamed := make(map[string]string, len(parsed.Chapters))
 	for i, chapter := range parsed.Chapters {
 		chapter.Order = offset + i
 		chapter.ID = fmt.Sprintf("chapter-%03d", chapter.Order+1)
-		chapter.FileName = fmt.Sprintf("content/chapter-%03d.xhtml", chapter.Order+1)
+		fileName := fmt.Sprintf("content/chapter-%03d.xhtml", chapter.Order+1)
+		renamed[chapter.FileName] = fileName
+		chapter.FileName = fileName
+		if len(moved) > 0 {
+			retargetResources(&chapter, moved)
+		}
 		main.AddChapter(chapter)
 	}
 
-	// Merge TOC entries
-	main.TOC.Entries = append(main.TOC.Entries, parsed.TOC.Entries...)
+	// Merge TOC entries, pointing them at the renamed chapter files
+	main.TOC.Entries = nestTOCEntries(main.TOC.Entries, retargetTOCEntries(parsed.TOC.Entries, renamed), depth)
 
-	// Merge resources
-	for _, res := range parsed.Resources {
-		main.AddResource(res)
+	// Merge references, later inputs replacing entries with the same key
+	for _, ref := range parsed.References {
+		main.AddReference(ref)
+	}
+
+	// Merge glossary terms, later inputs replacing definitions of the same term
+	for _, entry := range parsed.Glossary {
+		main.AddGlossaryEntry(entry)
 	}
 }
 
-// processCoverImage loads and embeds the cover image.
-func (c *Converter) processCoverImage(doc *model.Document, result *model.ConversionResult) error {
+// processCoverImage loads and embeds the cover image, through opts.Fetcher
+// if it is set, normalizing it to opts.Cover. It is named images/cover,
+// numbered if a chapter image already has that name.
+func (c *Converter) processCoverImage(doc *model.Document, rep *reporter, opts Options) error {
 	coverPath := doc.Metadata.CoverImage
 
-	resource, err := c.imgHandler.ProcessImage(coverPath, ".")
+	var resource *model.Resource
+	var err error
+	if opts.Fetcher != nil {
+		resource, err = c.images(opts).FetchImage(opts.Fetcher, coverPath)
+	} else {
+		resource, err = c.images(opts).ProcessImage(coverPath, ".")
+	}
 	if err != nil {
 		return err
 	}
+	if err := normalizeCover(resource, coverPath, rep, opts); err != nil {
+		return err
+	}
 
 	// Mark as cover image
 	resource.IsCover = true
 	resource.ID = "cover-image"
-	resource.FileName = "images/cover" + extensionFromMediaType(resource.MediaType)
+	resource.FileName, _ = uniqueFileName("images/cover"+extensionFromMediaType(resource.MediaType), func(name string) bool {
+		for _, res := range doc.Resources {
+			if strings.EqualFold(res.FileName, name) {
+				return true
+			}
+		}
+		return false
+	})
 
 	doc.AddResource(*resource)
 	return nil
@@ -389,17 +1324,59 @@ func extensionFromMediaType(mediaType string) string {
 		return ".gif"
 	case "image/svg+xml":
 		return ".svg"
+	case "image/webp":
+		return ".webp"
+	case "image/avif":
+		return ".avif"
 	default:
 		return ".bin"
 	}
 }
 
-// processImages handles image resources in the document.
-func (c *Converter) processImages(doc *model.Document, result *model.ConversionResult) {
-	// Process each image resource that doesn't have data loaded yet
-	processedResources := make([]model.Resource, 0, len(doc.Resources))
+// processImages handles image resources in the document, reporting each
+// image probed to progress. Images are probed and converted on
+// opts.Workers goroutines; warnings keep the order of the resources. Local
+// images are streamed from disk at build time unless opts.Fetcher is set,
+// which loads them into memory.
+func (c *Converter) processImages(doc *model.Document, rep *reporter, opts Options) {
+	log := opts.logger()
+	start := time.Now()
+	images := c.images(opts)
 
-	for _, res := range doc.Resources {
+	// Probe, and where needed convert, the images not loaded yet at once
+	var pending []int
+	for i, res := range doc.Resources {
+		if len(res.Data) == 0 && strings.HasPrefix(res.MediaType, "image/") && res.SourcePath != "" {
+			pending = append(pending, i)
+		}
+	}
+	type probe struct {
+		res *model.Resource
+		err error
+	}
+	probes := make(map[int]probe, len(pending))
+	var mu sync.Mutex
+	done := 0
+	forEach(len(pending), opts.workers(), func(n int) {
+		src := doc.Resources[pending[n]].SourcePath
+		var p probe
+		if opts.Fetcher != nil {
+			p.res, p.err = images.FetchImage(opts.Fetcher, src)
+		} else {
+			p.res, p.err = images.ProbeImage(src, ".")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		probes[pending[n]] = p
+		done++
+		opts.Progress.report(StageImages, done, len(pending))
+	})
+
+	// Replace resources with processed ones, in their original order
+	processedResources := make([]model.Resource, 0, len(doc.Resources))
+	probed := 0
+	for i, res := range doc.Resources {
 		// Skip if data is already loaded (e.g., cover image)
 		if len(res.Data) > 0 {
 			processedResources = append(processedResources, res)
@@ -414,48 +1391,137 @@ func (c *Converter) processImages(doc *model.Document, result *model.ConversionR
 
 		// Skip if no source path specified
 		if res.SourcePath == "" {
-			result.AddWarning(fmt.Sprintf("Image %s: no source path specified", res.FileName))
+			rep.warn(model.Warning{
+				Code:    model.WarnMissingImage,
+				File:    res.FileName,
+				Message: fmt.Sprintf("Image %s: no source path specified", res.FileName),
+			})
 			continue
 		}
 
-		// Load image data from source path
-		loadedRes, err := c.imgHandler.ProcessImage(res.SourcePath, ".")
-		if err != nil {
+		// Local data is streamed from the source path at build time
+		p := probes[i]
+		if p.err != nil {
 			// Image not found or unsupported - add warning and skip
-			result.AddWarning(fmt.Sprintf("Image %s: %s", res.SourcePath, err))
+			log.Debug("skipped image", "stage", "images", "file", res.SourcePath, "error", p.err)
+			rep.warn(model.Warning{
+				Code:    model.WarnMissingImage,
+				File:    res.SourcePath,
+				Message: fmt.Sprintf("Image %s: %s", res.SourcePath, p.err),
+			})
 			continue
 		}
+		log.Debug("probed image", "stage", "images", "file", res.SourcePath, "type", p.res.MediaType)
+		probed++
 
 		// Preserve original ID and FileName from parser
-		loadedRes.ID = res.ID
-		loadedRes.FileName = res.FileName
-		processedResources = append(processedResources, *loadedRes)
+		p.res.ID = res.ID
+		p.res.FileName = res.FileName
+		processedResources = append(processedResources, *p.res)
+	}
+
+	doc.Resources = processedResources
+	log.Info("processed images", "stage", "images", "images", probed, "duration", time.Since(start))
+}
+
+// processStylesheets loads chapter-specific stylesheets referenced by parsers,
+// through fetcher if it is set. Stylesheets that cannot be read are dropped
+// with a warning.
+func (c *Converter) processStylesheets(doc *model.Document, rep *reporter, fetcher ResourceFetcher) {
+	if fetcher == nil {
+		fetcher = FileFetcher{}
+	}
+
+	processedResources := make([]model.Resource, 0, len(doc.Resources))
+	missing := make(map[string]bool)
+
+	for _, res := range doc.Resources {
+		if res.MediaType != "text/css" || len(res.Data) > 0 || res.SourcePath == "" {
+			processedResources = append(processedResources, res)
+			continue
+		}
+
+		data, err := fetcher.Fetch(res.SourcePath)
+		if err != nil {
+			rep.warn(model.Warning{
+				Code:    model.WarnMissingStylesheet,
+				File:    res.SourcePath,
+				Message: fmt.Sprintf("Stylesheet %s: %s", res.SourcePath, err),
+			})
+			missing[res.FileName] = true
+			continue
+		}
+
+		res.Data = data
+		processedResources = append(processedResources, res)
 	}
 
-	// Replace resources with processed ones
 	doc.Resources = processedResources
+
+	if len(missing) == 0 {
+		return
+	}
+	for i := range doc.Chapters {
+		kept := doc.Chapters[i].Stylesheets[:0]
+		for _, css := range doc.Chapters[i].Stylesheets {
+			if !missing[css] {
+				kept = append(kept, css)
+			}
+		}
+		doc.Chapters[i].Stylesheets = kept
+	}
 }
 
-// writeOutput writes EPUB data to the output file.
-func (c *Converter) writeOutput(path string, data []byte) error {
+// writeOutput builds the EPUB with builder and streams it to the output file,
+// or writes its contents to the output directory when unpacked is set.
+// Returns the size of the written EPUB.
+func (c *Converter) writeOutput(path string, doc *model.Document, builder *epub.Builder, unpacked bool, log *slog.Logger) (int64, error) {
+	if unpacked {
+		return c.writeUnpacked(path, doc, builder, log)
+	}
+
 	// Ensure parent directory exists
 	dir := filepath.Dir(path)
 	if dir != "" && dir != "." {
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("%w: cannot create directory %s", ErrOutputNotWrite, dir)
+			return 0, fmt.Errorf("%w: cannot create directory %s", ErrOutputNotWrite, dir)
 		}
 	}
 
-	// Write to temp file first, then rename (atomic operation)
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
-		return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	// Write to a uniquely named temp file first, then rename (atomic
+	// operation), so concurrent conversions never share a temp file
+	start := time.Now()
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	tmpPath := f.Name()
+	if err := f.Chmod(0644); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+
+	if err := builder.WriteTo(doc, f); err != nil {
+		f.Close()
+		os.Remove(tmpPath)
+		return 0, err
+	}
+	log.Info("built EPUB", "stage", "build", "chapters", len(doc.Chapters),
+		"resources", len(doc.Resources), "duration", time.Since(start))
+
+	start = time.Now()
+	info, statErr := f.Stat()
+	if err := f.Close(); err != nil || statErr != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, errors.Join(statErr, err))
 	}
 
 	if err := os.Rename(tmpPath, path); err != nil {
 		os.Remove(tmpPath)
-		return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
 	}
+	log.Info("wrote output", "stage", "write", "file", path, "bytes", info.Size(), "duration", time.Since(start))
 
-	return nil
+	return info.Size(), nil
 }
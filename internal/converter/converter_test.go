@@ -0,0 +1,343 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+func TestConverter_ConvertReader(t *testing.T) {
+	input := "---\ntitle: Streamed\n---\n\n# One\n\nText with ![a figure](figure.png).\n\n# Two\n\nMore text.\n"
+
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, Options{})
+	require.NoError(t, err)
+
+	assert.True(t, result.Success)
+	assert.Empty(t, result.OutputPath)
+	assert.Equal(t, int64(out.Len()), result.Stats.OutputSize)
+	assert.Equal(t, 0, result.Stats.ImageCount)
+	require.Len(t, result.Warnings, 2)
+	assert.Equal(t, model.WarnResourceNotLoaded, result.Warnings[0].Code)
+	assert.Equal(t, model.SeverityWarning, result.Warnings[0].Severity)
+	assert.Equal(t, "figure.png", result.Warnings[0].File)
+
+	// The chapter still refers to the image left out of the book
+	assert.Equal(t, model.WarnBrokenLink, result.Warnings[1].Code)
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(archive.File))
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, "mimetype", names[0])
+	assert.Contains(t, names, "OEBPS/content.opf")
+	assert.NotContains(t, names, "OEBPS/images/figure.png")
+}
+
+func TestConverter_ConvertReader_FrontMatterFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"terms.yaml": "API: Application programming interface\n"})
+	input := "---\nglossary: " + filepath.Join(dir, "terms.yaml") + "\n---\n# One\n\nThe API.\n"
+
+	// Without a fetcher, files named in front matter are not read
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, Options{})
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Warnings)
+	assert.Equal(t, model.WarnResourceNotLoaded, result.Warnings[0].Code)
+	assert.Equal(t, filepath.Join(dir, "terms.yaml"), result.Warnings[0].File)
+	assert.NotContains(t, out.String(), "glossary.xhtml")
+
+	// With one, they are read through it
+	out.Reset()
+	_, err = New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, Options{Fetcher: FileFetcher{}})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "glossary.xhtml")
+}
+
+func TestConverter_ConvertReader_UnknownFormat(t *testing.T) {
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader("text"), parser.FormatUnknown, &out, Options{})
+	assert.ErrorIs(t, err, ErrUnsupportedFmt)
+	assert.Zero(t, out.Len())
+}
+
+func TestConverter_Convert_Progress(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01-one.md": "# One\n\nFirst.\n",
+		"02-two.md": "# Two\n\nSecond.\n",
+	})
+
+	type event struct {
+		stage          string
+		current, total int
+	}
+	var events []event
+	opts := Options{
+		OutputPath: filepath.Join(dir, "book.epub"),
+		Progress: func(stage string, current, total int) {
+			events = append(events, event{stage, current, total})
+		},
+	}
+
+	result, err := New().Convert([]string{dir}, opts)
+	require.NoError(t, err)
+
+	chapters := result.Stats.ChapterCount
+	want := []event{{StageParse, 1, 2}, {StageParse, 2, 2}}
+	for i := 1; i <= chapters; i++ {
+		want = append(want, event{StageWrite, i, chapters})
+	}
+	assert.Equal(t, want, events)
+}
+
+func TestConverter_Convert_LoadsImages(t *testing.T) {
+	dir := t.TempDir()
+	figure := pngResource(t, 4, 3).Data
+	writeFiles(t, dir, map[string]string{
+		"book/one.md":         "# One\n\n![A figure](art/figure.png)\n\n![Gone](art/missing.png)\n",
+		"book/art/figure.png": string(figure),
+	})
+
+	// Image paths are relative to the Markdown file, not the working directory
+	output := filepath.Join(dir, "book.epub")
+	result, err := New().Convert([]string{filepath.Join(dir, "book", "one.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stats.ImageCount)
+
+	var missing []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnMissingImage {
+			missing = append(missing, w)
+		}
+	}
+	require.Len(t, missing, 1)
+	assert.Contains(t, missing[0].File, "missing.png")
+
+	archive, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer archive.Close()
+	var data []byte
+	for _, f := range archive.File {
+		if f.Name == "OEBPS/images/figure.png" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err = io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+		}
+	}
+	assert.Equal(t, figure, data)
+}
+
+func TestConverter_Convert_Languages(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01-en.md": "---\ntitle: Bilingual\nlang: en\n---\n\n# One\n\n\"Hello\"\n",
+		"02-fr.md": "---\nlang: fr\n---\n\n# Un\n\n\"Bonjour\"\n",
+	})
+
+	output := filepath.Join(dir, "book.epub")
+	_, err := New().Convert([]string{dir}, Options{OutputPath: output, Typography: true})
+	require.NoError(t, err)
+
+	chapters := readChapters(t, output)
+	assert.Contains(t, chapters["OEBPS/content/chapter-001.xhtml"], `xml:lang="en"`)
+	assert.Contains(t, chapters["OEBPS/content/chapter-001.xhtml"], "“Hello”")
+	assert.Contains(t, chapters["OEBPS/content/chapter-002.xhtml"], `xml:lang="fr"`)
+	assert.Contains(t, chapters["OEBPS/content/chapter-002.xhtml"], "«\u202fBonjour\u202f»")
+
+	archive, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer archive.Close()
+	rc, err := archive.Open("OEBPS/content.opf")
+	require.NoError(t, err)
+	opf, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Contains(t, string(opf), "<dc:language>en</dc:language>")
+	assert.Contains(t, string(opf), "<dc:language>fr</dc:language>")
+}
+
+func TestConverter_ConvertReader_MetadataProperties(t *testing.T) {
+	input := `---
+title: Properties
+author:
+  - name: Jane Doe
+    file-as: Doe, Jane
+contributors:
+  - name: Ann Lee
+    role: translator
+    file-as: Lee, Ann
+dc:coverage: Northern Europe
+dcterms:issued: 1899-05-01
+schema:accessMode: [textual, visual]
+dcterms:alternative:
+  value: La Saga
+  alternate-script: ラ・サーガ
+dcterms:modified: 2000-01-01T00:00:00Z
+---
+
+# One
+`
+
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, Options{})
+	require.NoError(t, err)
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	rc, err := archive.Open("OEBPS/content.opf")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	opf := string(data)
+
+	for _, want := range []string{
+		`<dc:creator id="creator-1">Jane Doe</dc:creator>`,
+		`<meta refines="#creator-1" property="file-as">Doe, Jane</meta>`,
+		`<meta refines="#contributor-1" property="file-as">Lee, Ann</meta>`,
+		`<dc:coverage>Northern Europe</dc:coverage>`,
+		`<meta property="dcterms:issued">1899-05-01</meta>`,
+		`<meta property="schema:accessMode">textual</meta>`,
+		`<meta property="schema:accessMode">visual</meta>`,
+		`<meta id="property-2" property="dcterms:alternative">La Saga</meta>`,
+		`<meta refines="#property-2" property="alternate-script">ラ・サーガ</meta>`,
+	} {
+		assert.Contains(t, opf, want)
+	}
+	assert.Equal(t, 1, strings.Count(opf, "dcterms:modified"))
+	assert.NotContains(t, opf, "2000-01-01T00:00:00Z")
+}
+
+func TestConverter_ConvertReader_Hooks(t *testing.T) {
+	var seen []string
+	opts := Options{Hooks: []DocumentHook{
+		func(doc *model.Document) error {
+			seen = append(seen, "first")
+			doc.Metadata.Title = "Rewritten"
+			doc.AddChapter(model.Chapter{
+				ID:       "appendix",
+				Title:    "Appendix",
+				Level:    1,
+				Content:  "<h1>Appendix</h1>",
+				FileName: "content/appendix.xhtml",
+				Order:    len(doc.Chapters),
+			})
+			return nil
+		},
+		func(doc *model.Document) error {
+			seen = append(seen, "second:"+doc.Metadata.Title)
+			return nil
+		},
+	}}
+
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader("# One\n"), parser.FormatMarkdown, &out, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second:Rewritten"}, seen)
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	found := false
+	for _, f := range archive.File {
+		found = found || f.Name == "OEBPS/content/appendix.xhtml"
+	}
+	assert.True(t, found)
+}
+
+func TestConverter_ConvertReader_HookError(t *testing.T) {
+	errRejected := errors.New("rejected")
+	opts := Options{Hooks: []DocumentHook{func(*model.Document) error { return errRejected }}}
+
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader("# One\n"), parser.FormatMarkdown, &out, opts)
+	assert.ErrorIs(t, err, errRejected)
+	assert.Zero(t, out.Len())
+}
+
+func TestConverter_ConvertReader_Logger(t *testing.T) {
+	var logs bytes.Buffer
+	opts := Options{
+		Logger:     slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Typography: true,
+	}
+
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader("# One\n\n\"Quoted\"\n"), parser.FormatMarkdown, &out, opts)
+	require.NoError(t, err)
+
+	for _, stage := range []string{"parse", "typography", "build"} {
+		assert.Contains(t, logs.String(), "stage="+stage)
+	}
+}
+
+func TestConverter_ConcurrentConversions(t *testing.T) {
+	conv := New()
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			title := fmt.Sprintf("Book %d", i)
+			opts := Options{
+				OutputPath:  filepath.Join(dir, fmt.Sprintf("book-%d.epub", i)),
+				InputFormat: "html",
+				Scripts:     parser.ScriptPolicy{Inline: i%2 == 0},
+				EPUB:        epub.Options{NumberSections: i%2 == 0},
+				Logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+			}
+			input := fmt.Sprintf("<html><head><title>%s</title></head><body><h1>One</h1><p>Text</p><h1>Two</h1></body></html>", title)
+			_, errs[i] = conv.ConvertContent([]byte(input), opts)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		require.NoError(t, err)
+		archive, err := zip.OpenReader(filepath.Join(dir, fmt.Sprintf("book-%d.epub", i)))
+		require.NoError(t, err)
+		for _, f := range archive.File {
+			if f.Name != "OEBPS/content.opf" {
+				continue
+			}
+			rc, err := f.Open()
+			require.NoError(t, err)
+			opf, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			assert.Contains(t, string(opf), fmt.Sprintf("Book %d", i))
+		}
+		archive.Close()
+	}
+}
+
+func TestConverter_ConvertContent_TOCMatchesHeadingIDs(t *testing.T) {
+	input := "# Q & A\n\nText.\n\n## Notes\n\nOne.\n\n## Notes\n\nTwo.\n"
+
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+}
@@ -0,0 +1,102 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/draw"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// RecommendedCoverSize is the cover size most retailers recommend.
+var RecommendedCoverSize = ImageSize{Width: 1600, Height: 2560}
+
+// coverAspectTolerance is how far a cover's aspect ratio may be from the
+// target's, as a fraction, before it is reported.
+const coverAspectTolerance = 0.02
+
+// CoverOptions normalizes the cover image to a target size.
+type CoverOptions struct {
+	Size ImageSize // Target size; larger covers are downscaled to fit, smaller ones reported. Zero leaves the cover as-is
+	Crop bool      // Crop the cover to the target's aspect ratio, around its center
+}
+
+// normalizeCover checks a JPEG or PNG cover against opts.Cover.Size,
+// reporting covers that are smaller or of a different shape, then crops
+// and downscales it as configured.
+func normalizeCover(res *model.Resource, source string, rep *reporter, opts Options) error {
+	target := opts.Cover.Size
+	if target.Width <= 0 || target.Height <= 0 || !adjustable(res.MediaType) || len(res.Data) == 0 {
+		return nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(res.Data))
+	if err != nil {
+		return fmt.Errorf("decoding cover: %w", err)
+	}
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width < target.Width || height < target.Height {
+		rep.warn(model.Warning{
+			Code:    model.WarnCoverImage,
+			File:    source,
+			Message: fmt.Sprintf("Cover image is %dx%d, smaller than the recommended %s", width, height, target),
+		})
+	}
+
+	// Crop to the target's aspect ratio, or report a different shape
+	want := float64(target.Width) / float64(target.Height)
+	got := float64(width) / float64(height)
+	crop := bounds
+	if math.Abs(got-want)/want > coverAspectTolerance {
+		if !opts.Cover.Crop {
+			rep.warn(model.Warning{
+				Code:    model.WarnCoverImage,
+				File:    source,
+				Message: fmt.Sprintf("Cover image aspect ratio %.2f differs from %.2f (%s); crop it to fit", got, want, target),
+			})
+		} else if got > want {
+			w := int(float64(height)*want + 0.5)
+			x := bounds.Min.X + (width-w)/2
+			crop = image.Rect(x, bounds.Min.Y, x+w, bounds.Max.Y)
+		} else {
+			h := int(float64(width)/want + 0.5)
+			y := bounds.Min.Y + (height-h)/2
+			crop = image.Rect(bounds.Min.X, y, bounds.Max.X, y+h)
+		}
+	}
+
+	outW, outH, scale := target.fit(crop.Dx(), crop.Dy())
+	if !scale && crop == bounds {
+		return nil
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, outW, outH))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, crop, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if res.MediaType == "image/jpeg" {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, dst)
+	}
+	if err != nil {
+		return fmt.Errorf("encoding cover: %w", err)
+	}
+	res.Data = buf.Bytes()
+	opts.logger().Debug("normalized cover image", "stage", "images", "from", fmt.Sprintf("%dx%d", width, height), "to", fmt.Sprintf("%dx%d", outW, outH))
+	return nil
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// pngResource returns a PNG resource of the given size.
+func pngResource(t *testing.T, width, height int) *model.Resource {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))))
+	return &model.Resource{MediaType: "image/png", Data: buf.Bytes()}
+}
+
+// pngSize returns the size of PNG data.
+func pngSize(t *testing.T, data []byte) [2]int {
+	t.Helper()
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	return [2]int{cfg.Width, cfg.Height}
+}
+
+func TestNormalizeCover(t *testing.T) {
+	target := ImageSize{Width: 160, Height: 256}
+
+	t.Run("small and square", func(t *testing.T) {
+		rep := newReporter(&model.ConversionResult{}, Options{})
+		res := pngResource(t, 100, 100)
+		require.NoError(t, normalizeCover(res, "cover.png", rep, Options{Cover: CoverOptions{Size: target}}))
+		require.Len(t, rep.result.Warnings, 2)
+		assert.Contains(t, rep.result.Warnings[0].Message, "smaller than the recommended 160x256")
+		assert.Contains(t, rep.result.Warnings[1].Message, "aspect ratio 1.00")
+		assert.Equal(t, [2]int{100, 100}, pngSize(t, res.Data))
+	})
+
+	t.Run("crop and downscale", func(t *testing.T) {
+		rep := newReporter(&model.ConversionResult{}, Options{})
+		res := pngResource(t, 800, 800)
+		require.NoError(t, normalizeCover(res, "cover.png", rep, Options{Cover: CoverOptions{Size: target, Crop: true}}))
+		assert.Empty(t, rep.result.Warnings)
+		assert.Equal(t, [2]int{160, 256}, pngSize(t, res.Data))
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		rep := newReporter(&model.ConversionResult{}, Options{})
+		res := pngResource(t, 10, 10)
+		data := res.Data
+		require.NoError(t, normalizeCover(res, "cover.png", rep, Options{}))
+		assert.Empty(t, rep.result.Warnings)
+		assert.Equal(t, data, res.Data)
+	})
+}
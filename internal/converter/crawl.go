@@ -0,0 +1,600 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// ErrCrawl is returned when a website cannot be crawled, such as when its
+// start page cannot be fetched or is not HTML.
+var ErrCrawl = errors.New("crawling website")
+
+// Crawl defaults.
+const (
+	defaultCrawlMaxPages = 200
+	defaultCrawlTimeout  = 30 * time.Second
+)
+
+// CrawlOptions configures a website crawl.
+type CrawlOptions struct {
+	Depth    int          // Links followed away from the start page; 0 converts the start page alone
+	MaxPages int          // Most pages converted; 0 means 200
+	Client   *http.Client // nil uses a client with a 30 second timeout
+}
+
+// crawlSkippedExts are extensions of link targets that are not pages,
+// skipped without fetching them.
+var crawlSkippedExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".webp": true, ".avif": true,
+	".ico": true, ".css": true, ".js": true, ".json": true, ".xml": true, ".pdf": true, ".zip": true,
+	".gz": true, ".tar": true, ".mp3": true, ".mp4": true, ".webm": true, ".woff": true, ".woff2": true,
+	".ttf": true, ".epub": true,
+}
+
+// crawlPage is a fetched page of the website.
+type crawlPage struct {
+	url   *url.URL
+	doc   *html.Node
+	depth int
+	order int // Position in crawl order
+}
+
+// crawler downloads a website's pages and their images and stylesheets
+// into dir.
+type crawler struct {
+	start  *url.URL
+	crawl  CrawlOptions
+	opts   Options
+	client *http.Client
+	dir    string
+	rep    *reporter
+
+	pages  []*crawlPage
+	nav    map[string]int    // Page URL to position in the site navigation
+	assets map[string]string // Asset URL to saved path, "" when it failed
+	local  []string          // Pairs of saved paths and the URLs they came from
+}
+
+// Crawl downloads the same-origin pages linked from startURL, up to
+// crawl.Depth links away, with their images and stylesheets, and converts
+// them into one EPUB. Each page is a chapter holding its main content, or
+// several with opts.SplitLevel, in the order of the site's navigation
+// menus; pages no menu lists follow in the order they were found. Links
+// between the pages point to their chapters.
+func (c *Converter) Crawl(startURL string, crawl CrawlOptions, opts Options) (*model.ConversionResult, error) {
+	result := &model.ConversionResult{Warnings: make([]model.Warning, 0)}
+
+	start, err := url.Parse(startURL)
+	if err != nil || (start.Scheme != "http" && start.Scheme != "https") || start.Host == "" {
+		return result, fmt.Errorf("%w: %s is not an http or https URL", ErrCrawl, startURL)
+	}
+	start.Fragment = ""
+
+	dir, err := os.MkdirTemp("", "toepub-crawl-")
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrCrawl, err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := crawl.Client
+	if client == nil {
+		client = &http.Client{Timeout: defaultCrawlTimeout}
+	}
+	cr := &crawler{
+		start:  start,
+		crawl:  crawl,
+		opts:   opts,
+		client: client,
+		dir:    dir,
+		rep:    newReporter(result, opts),
+		nav:    make(map[string]int),
+		assets: make(map[string]string),
+	}
+	if err := cr.fetchPages(); err != nil {
+		return result, err
+	}
+	files, err := cr.save()
+	if err != nil {
+		return result, err
+	}
+
+	// Convert the saved pages, which link to each other by their saved
+	// names, reporting the URLs they came from rather than their saved paths
+	unlocalize := strings.NewReplacer(cr.local...)
+	restore := func(event model.Warning) model.Warning {
+		event.File = unlocalize.Replace(event.File)
+		event.Message = unlocalize.Replace(event.Message)
+		return event
+	}
+	if opts.Events != nil {
+		events := opts.Events
+		opts.Events = EventFunc(func(event model.Warning) { events.HandleEvent(restore(event)) })
+	}
+	if opts.OutputPath == "" {
+		opts.OutputPath = strings.ReplaceAll(start.Host, ":", "-") + ".epub"
+	}
+	if title := siteName(cr.pages[0].doc); title != "" && (opts.CLIMetadata == nil || opts.CLIMetadata.Title == "") {
+		meta := model.NewMetadata()
+		if opts.CLIMetadata != nil {
+			overrides := *opts.CLIMetadata
+			meta = &overrides
+		}
+		meta.Title = title
+		opts.CLIMetadata = meta
+	}
+	opts.InputFormat = string(parser.FormatHTML)
+
+	converted, err := c.Convert(files, opts)
+	if converted != nil {
+		for i, event := range converted.Warnings {
+			converted.Warnings[i] = restore(event)
+		}
+		converted.Warnings = append(result.Warnings, converted.Warnings...)
+	}
+	return converted, err
+}
+
+// fetchPages fetches pages breadth first from the start page, following
+// links until crawl.Depth or crawl.MaxPages is reached, and puts them in
+// reading order.
+func (cr *crawler) fetchPages() error {
+	log := cr.opts.logger()
+	maxPages := cr.crawl.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultCrawlMaxPages
+	}
+
+	seen := map[string]bool{crawlKey(cr.start): true}
+	queue := []*crawlPage{{url: cr.start}}
+	for len(queue) > 0 && len(cr.pages) < maxPages {
+		page := queue[0]
+		queue = queue[1:]
+
+		doc, final, err := cr.fetchPage(page.url)
+		if err == nil && len(cr.pages) == 0 && isHTTPSUpgrade(final, cr.start) {
+			// The site moved to https; its pages are crawled there
+			cr.start = final
+		}
+		if err == nil && !sameOrigin(final, cr.start) {
+			err = fmt.Errorf("redirected to %s on another site", final)
+		}
+		if err != nil {
+			if len(cr.pages) == 0 {
+				return fmt.Errorf("%w: %s: %w", ErrCrawl, page.url, err)
+			}
+			cr.rep.warn(model.Warning{
+				Code:    model.WarnPageSkipped,
+				File:    page.url.String(),
+				Message: fmt.Sprintf("Page %s: %s; left out", page.url, err),
+			})
+			continue
+		}
+		if key := crawlKey(final); key != crawlKey(page.url) {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		page.url, page.doc, page.order = final, doc, len(cr.pages)
+		cr.pages = append(cr.pages, page)
+		log.Info("fetched page", "stage", "crawl", "url", final.String(), "depth", page.depth)
+
+		for _, link := range navLinks(doc, final) {
+			if _, ok := cr.nav[crawlKey(link)]; !ok {
+				cr.nav[crawlKey(link)] = len(cr.nav)
+			}
+		}
+		if page.depth >= cr.crawl.Depth {
+			continue
+		}
+		for _, link := range pageLinks(doc, final) {
+			if !sameOrigin(link, cr.start) || seen[crawlKey(link)] {
+				continue
+			}
+			seen[crawlKey(link)] = true
+			queue = append(queue, &crawlPage{url: link, depth: page.depth + 1})
+		}
+	}
+
+	// Order pages by the site navigation, the start page first unless the
+	// navigation places it
+	rank := func(p *crawlPage) int {
+		if n, ok := cr.nav[crawlKey(p.url)]; ok {
+			return n
+		}
+		if p.order == 0 {
+			return -1
+		}
+		return len(cr.nav) + p.order
+	}
+	sort.SliceStable(cr.pages, func(i, j int) bool { return rank(cr.pages[i]) < rank(cr.pages[j]) })
+	return nil
+}
+
+// fetchPage downloads and parses the HTML page at u, returning it with
+// its URL after redirects.
+func (cr *crawler) fetchPage(u *url.URL) (*html.Node, *url.URL, error) {
+	resp, err := cr.client.Get(u.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("%s", resp.Status)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, nil, fmt.Errorf("not an HTML page (%s)", mediaType)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, defaultMaxFetchSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	final := *resp.Request.URL
+	final.Fragment = ""
+	return doc, &final, nil
+}
+
+// save writes each page as page-001.html and onward in reading order,
+// holding only its main content, with its images and stylesheets
+// downloaded beside it. It returns the saved paths.
+func (cr *crawler) save() ([]string, error) {
+	files := make(map[string]string, len(cr.pages))
+	for i, page := range cr.pages {
+		files[crawlKey(page.url)] = fmt.Sprintf("page-%03d.html", i+1)
+	}
+
+	paths := make([]string, 0, len(cr.pages))
+	for _, page := range cr.pages {
+		keepMainContent(page.doc)
+		cr.localize(page, files)
+
+		var buf bytes.Buffer
+		if err := html.Render(&buf, page.doc); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrCrawl, page.url, err)
+		}
+		p := filepath.Join(cr.dir, files[crawlKey(page.url)])
+		if err := os.WriteFile(p, buf.Bytes(), 0o644); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrCrawl, err)
+		}
+		paths = append(paths, p)
+		cr.local = append(cr.local, p, page.url.String())
+	}
+	return paths, nil
+}
+
+// localize points a page's links to other saved pages at their files and
+// other relative links at their URLs, and downloads its images,
+// stylesheets, audio, and video.
+func (cr *crawler) localize(page *crawlPage, files map[string]string) {
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "a":
+				if href := attr(n, "href"); href != "" && !strings.HasPrefix(href, "#") {
+					if target, err := page.url.Parse(href); err == nil {
+						if file, ok := files[crawlKey(target)]; ok {
+							if target.Fragment != "" {
+								file += "#" + target.Fragment
+							}
+							setAttr(n, "href", file)
+						} else if target.Scheme == "http" || target.Scheme == "https" {
+							setAttr(n, "href", target.String())
+						}
+					}
+				}
+			case "img":
+				src := attr(n, "src")
+				if lazy := attr(n, "data-src"); lazy != "" && (src == "" || strings.HasPrefix(src, "data:")) {
+					setAttr(n, "src", lazy)
+				}
+				removeAttr(n, "srcset")
+				cr.localizeRef(n, "src", page.url, model.WarnMissingImage)
+			case "audio", "video", "source":
+				removeAttr(n, "srcset")
+				cr.localizeRef(n, "src", page.url, model.WarnMissingMedia)
+				if n.Data == "video" {
+					cr.localizeRef(n, "poster", page.url, model.WarnMissingImage)
+				}
+			case "track":
+				// Text tracks are not embedded; they stay on the site
+				if attr(n, "src") != "" {
+					remoteRef(n, "src", page.url)
+				}
+			case "link":
+				href := attr(n, "href")
+				isStylesheet := slices.ContainsFunc(strings.Fields(attr(n, "rel")), func(w string) bool { return strings.EqualFold(w, "stylesheet") })
+				if isStylesheet && href != "" {
+					if local := cr.asset(page.url, href, model.WarnMissingStylesheet); local != "" {
+						setAttr(n, "href", local)
+					} else {
+						n.Parent.RemoveChild(n)
+					}
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; {
+			following := c.NextSibling
+			walk(c)
+			c = following
+		}
+	}
+	walk(page.doc)
+}
+
+// localizeRef points the attribute key of n at the file it refers to,
+// downloaded into assets/. A file that cannot be downloaded is left at its
+// URL, as remoteRef does.
+func (cr *crawler) localizeRef(n *html.Node, key string, base *url.URL, code string) {
+	ref := attr(n, key)
+	if ref == "" || strings.HasPrefix(ref, "data:") {
+		return
+	}
+	if local := cr.asset(base, ref, code); local != "" {
+		setAttr(n, key, local)
+	} else {
+		remoteRef(n, key, base)
+	}
+}
+
+// remoteRef points the attribute key of n at the http or https URL it
+// refers to, relative to base, and removes any other reference, so a page
+// cannot make the converter read a local file.
+func remoteRef(n *html.Node, key string, base *url.URL) {
+	if target, err := base.Parse(attr(n, key)); err == nil && (target.Scheme == "http" || target.Scheme == "https") {
+		setAttr(n, key, target.String())
+	} else {
+		removeAttr(n, key)
+	}
+}
+
+// asset downloads the image, stylesheet, or media file at ref, relative to
+// the page at base, into assets/, returning its saved path relative to the
+// pages, or "" with a warning of code when it cannot be downloaded.
+func (cr *crawler) asset(base *url.URL, ref, code string) string {
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	u.Fragment = ""
+	if local, ok := cr.assets[u.String()]; ok {
+		return local
+	}
+
+	data, err := HTTPFetcher{Client: cr.client}.Fetch(u.String())
+	if err != nil {
+		cr.assets[u.String()] = ""
+		cr.rep.warn(model.Warning{
+			Code:    code,
+			File:    u.String(),
+			Message: fmt.Sprintf("Asset %s: %s; left out", u, err),
+		})
+		return ""
+	}
+
+	name := path.Base(u.Path)
+	if name == "" || name == "/" || name == "." {
+		name = "asset"
+	}
+	if path.Ext(name) == "" {
+		if code == model.WarnMissingStylesheet {
+			name += ".css"
+		} else {
+			name += extensionFromMediaType(http.DetectContentType(data))
+		}
+	}
+	taken := func(name string) bool {
+		_, err := os.Stat(filepath.Join(cr.dir, "assets", name))
+		return err == nil
+	}
+	name, _ = uniqueFileName(name, taken)
+
+	p := filepath.Join(cr.dir, "assets", name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err == nil {
+		err = os.WriteFile(p, data, 0o644)
+	}
+	if err != nil {
+		cr.assets[u.String()] = ""
+		return ""
+	}
+	local := "assets/" + name
+	cr.assets[u.String()] = local
+	cr.local = append(cr.local, p, u.String())
+	return local
+}
+
+// keepMainContent reduces the body of a page to its main content: the
+// main element, or the first article, without navigation menus. Pages
+// with neither keep their body without navigation, header, and footer.
+func keepMainContent(doc *html.Node) {
+	body := findElement(doc, func(n *html.Node) bool { return n.Data == "body" })
+	if body == nil {
+		return
+	}
+	main := findElement(body, func(n *html.Node) bool {
+		return n.Data == "main" || attr(n, "role") == "main"
+	})
+	if main == nil {
+		main = findElement(body, func(n *html.Node) bool { return n.Data == "article" })
+	}
+
+	if main != nil {
+		main.Parent.RemoveChild(main)
+		for body.FirstChild != nil {
+			body.RemoveChild(body.FirstChild)
+		}
+		body.AppendChild(main)
+	} else {
+		for c := body.FirstChild; c != nil; {
+			next := c.NextSibling
+			if c.Type == html.ElementNode && (c.Data == "header" || c.Data == "footer") {
+				body.RemoveChild(c)
+			}
+			c = next
+		}
+	}
+	for {
+		nav := findElement(body, isNavElement)
+		if nav == nil {
+			break
+		}
+		nav.Parent.RemoveChild(nav)
+	}
+}
+
+// navLinks returns the links in a page's navigation menus, in order.
+func navLinks(doc *html.Node, base *url.URL) []*url.URL {
+	var links []*url.URL
+	var walk func(n *html.Node, inNav bool)
+	walk = func(n *html.Node, inNav bool) {
+		if n.Type == html.ElementNode {
+			inNav = inNav || isNavElement(n)
+			if inNav && n.Data == "a" {
+				if link := pageLink(n, base); link != nil {
+					links = append(links, link)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, inNav)
+		}
+	}
+	walk(doc, false)
+	return links
+}
+
+// pageLinks returns the links in a page that may lead to other pages.
+func pageLinks(doc *html.Node, base *url.URL) []*url.URL {
+	var links []*url.URL
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			if link := pageLink(n, base); link != nil {
+				links = append(links, link)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	return links
+}
+
+// pageLink returns the URL a link element leads to, without its fragment,
+// or nil if it does not lead to an http or https page.
+func pageLink(n *html.Node, base *url.URL) *url.URL {
+	href := strings.TrimSpace(attr(n, "href"))
+	if href == "" || strings.HasPrefix(href, "#") {
+		return nil
+	}
+	u, err := base.Parse(href)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || crawlSkippedExts[strings.ToLower(path.Ext(u.Path))] {
+		return nil
+	}
+	u.Fragment = ""
+	return u
+}
+
+// siteName returns the site name a page declares in og:site_name, or "".
+func siteName(doc *html.Node) string {
+	meta := findElement(doc, func(n *html.Node) bool {
+		return n.Data == "meta" && attr(n, "property") == "og:site_name"
+	})
+	if meta == nil {
+		return ""
+	}
+	return strings.TrimSpace(attr(meta, "content"))
+}
+
+// isNavElement reports whether n is a navigation menu.
+func isNavElement(n *html.Node) bool {
+	return n.Type == html.ElementNode && (n.Data == "nav" || attr(n, "role") == "navigation")
+}
+
+// crawlKey identifies a page URL, ignoring its fragment and the
+// difference between an empty path and "/".
+func crawlKey(u *url.URL) string {
+	k := *u
+	k.Fragment = ""
+	k.Host = strings.ToLower(k.Host)
+	if k.Path == "" {
+		k.Path = "/"
+	}
+	return k.String()
+}
+
+// isHTTPSUpgrade reports whether u is the https version of the http URL
+// origin's host.
+func isHTTPSUpgrade(u, origin *url.URL) bool {
+	return origin.Scheme == "http" && u.Scheme == "https" && strings.EqualFold(u.Hostname(), origin.Hostname())
+}
+
+// sameOrigin reports whether u has the scheme, host, and port of origin.
+func sameOrigin(u, origin *url.URL) bool {
+	return u.Scheme == origin.Scheme && strings.EqualFold(u.Host, origin.Host)
+}
+
+// findElement returns the first element under n, in document order, that
+// match accepts.
+func findElement(n *html.Node, match func(*html.Node) bool) *html.Node {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && match(c) {
+			return c
+		}
+		if found := findElement(c, match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// setAttr sets an attribute of n, adding it if needed.
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+// removeAttr removes an attribute of n.
+func removeAttr(n *html.Node, key string) {
+	for i, a := range n.Attr {
+		if a.Key == key {
+			n.Attr = append(n.Attr[:i], n.Attr[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package converter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestConverter_Crawl(t *testing.T) {
+	const layout = `<html><head><title>%s</title><meta property="og:site_name" content="Example Docs">` +
+		`<link rel="stylesheet" href="/site.css"></head><body>` +
+		`<nav><a href="/">Home</a> <a href="/guide">Guide</a> <a href="/api">API</a> <a href="https://other.example/">Elsewhere</a></nav>` +
+		`<main>%s</main><footer>© Example</footer></body></html>`
+	pages := map[string]struct{ title, body string }{
+		"/":      {"Welcome", `<h1>Welcome</h1><p>Read the <a href="/api#calls">API calls</a>, <a href="/faq">the FAQ</a>, or <a href="/missing">the lost page</a>.</p>`},
+		"/api":   {"API", `<h1>API</h1><h2 id="calls">Calls</h2><img src="img/diagram.png" alt="Request flow"><p><a href="/deep">Deeper</a></p>`},
+		"/guide": {"Guide", `<h1>Guide</h1>`},
+		"/faq":   {"FAQ", `<h1>FAQ</h1>`},
+		"/deep":  {"Too deep", `<h1>Too deep</h1>`},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/site.css":
+			w.Header().Set("Content-Type", "text/css")
+			_, _ = w.Write([]byte("body { margin: 0; }"))
+		case "/img/diagram.png":
+			_, _ = w.Write(pngHeader)
+		default:
+			page, ok := pages[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(fmt.Sprintf(layout, page.title, page.body)))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	var doc *model.Document
+	opts := Options{
+		OutputPath: filepath.Join(dir, "site.epub"),
+		Hooks:      []DocumentHook{func(d *model.Document) error { doc = d; return nil }},
+	}
+	result, err := New().Crawl(srv.URL+"/", CrawlOptions{Depth: 1}, opts)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+
+	// Pages in navigation order, then pages found by links, then the colophon
+	require.Len(t, doc.Chapters, 5)
+	var titles []string
+	for _, chapter := range doc.Chapters[:4] {
+		titles = append(titles, chapter.Title)
+	}
+	assert.Equal(t, []string{"Welcome", "Guide", "API", "FAQ"}, titles)
+	assert.Equal(t, "Example Docs", doc.Metadata.Title)
+
+	home := doc.Chapters[0].Content
+	assert.Contains(t, home, `href="chapter-003.xhtml#calls"`)
+	assert.Contains(t, home, `href="chapter-004.xhtml"`)
+	assert.NotContains(t, home, "Elsewhere")
+	assert.NotContains(t, home, "© Example")
+
+	api := doc.Chapters[2].Content
+	assert.Contains(t, api, `src="../images/diagram.png"`)
+	assert.Contains(t, api, `href="`+srv.URL+`/deep"`)
+	assert.Contains(t, home, `href="`+srv.URL+`/missing"`)
+	assert.Contains(t, doc.Chapters[0].Stylesheets, "styles/site.css")
+
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, model.WarnPageSkipped, result.Warnings[0].Code)
+	assert.Equal(t, srv.URL+"/missing", result.Warnings[0].File)
+}
+
+func TestConverter_Crawl_SplitPages(t *testing.T) {
+	// The first page becomes two chapters, moving the later pages along
+	pages := map[string]string{
+		"/":      `<h1>Start</h1><p>See <a href="/usage#flags">flags</a> and <a href="/faq">the FAQ</a>.</p><h1>More</h1><p><a href="/#start">Top</a></p>`,
+		"/usage": `<h1>Usage</h1><h2 id="flags">Flags</h2>`,
+		"/faq":   `<h1>FAQ</h1>`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte("<html><head><title>Docs</title></head><body>" + body + "</body></html>"))
+	}))
+	defer srv.Close()
+
+	var doc *model.Document
+	opts := Options{
+		OutputPath: filepath.Join(t.TempDir(), "site.epub"),
+		SplitLevel: 1,
+		Hooks:      []DocumentHook{func(d *model.Document) error { doc = d; return nil }},
+	}
+	result, err := New().Crawl(srv.URL+"/", CrawlOptions{Depth: 1}, opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+
+	require.Len(t, doc.Chapters, 5)
+	assert.Contains(t, doc.Chapters[3].Content, ">FAQ</h1>")
+	assert.Contains(t, doc.Chapters[0].Content, `href="chapter-003.xhtml#flags"`)
+	assert.Contains(t, doc.Chapters[0].Content, `href="chapter-004.xhtml"`)
+	assert.Contains(t, doc.Chapters[1].Content, `href="chapter-001.xhtml#start"`)
+}
+
+func TestConverter_Crawl_StartPage(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	_, err := New().Crawl(srv.URL, CrawlOptions{}, Options{OutputPath: filepath.Join(t.TempDir(), "site.epub")})
+	assert.ErrorIs(t, err, ErrCrawl)
+
+	_, err = New().Crawl("ftp://example.com/", CrawlOptions{}, Options{})
+	assert.ErrorIs(t, err, ErrCrawl)
+
+	// A start page redirecting to another site is not crawled either
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte("<html><body><h1>Other</h1></body></html>"))
+	}))
+	defer other.Close()
+	redirect := httptest.NewServer(http.RedirectHandler(other.URL+"/", http.StatusFound))
+	defer redirect.Close()
+	_, err = New().Crawl(redirect.URL+"/", CrawlOptions{}, Options{OutputPath: filepath.Join(t.TempDir(), "site.epub")})
+	assert.ErrorIs(t, err, ErrCrawl)
+	assert.ErrorContains(t, err, "on another site")
+}
+
+func TestConverter_Crawl_Media(t *testing.T) {
+	page := `<html><head><title>Media</title></head><body><main><h1>Media</h1>` +
+		`<video src="clip.mp4" poster="/still.png"><track src="captions.vtt" kind="captions"></video>` +
+		`<audio><source src="/gone.mp3" type="audio/mpeg"></audio>` +
+		`<audio src="file:///etc/passwd"></audio><img src="file:///etc/hostname" alt="Local">` +
+		`</main></body></html>`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte(page))
+		case "/clip.mp4":
+			_, _ = w.Write([]byte("media"))
+		case "/still.png":
+			_, _ = w.Write(pngHeader)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	var doc *model.Document
+	opts := Options{
+		OutputPath: filepath.Join(t.TempDir(), "site.epub"),
+		Hooks:      []DocumentHook{func(d *model.Document) error { doc = d; return nil }},
+	}
+	result, err := New().Crawl(srv.URL+"/", CrawlOptions{}, opts)
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `src="../video/clip.mp4"`)
+	assert.Contains(t, content, `poster="../images/still.png"`)
+	assert.Contains(t, content, `src="`+srv.URL+`/captions.vtt"`)
+	assert.Contains(t, content, `src="`+srv.URL+`/gone.mp3"`)
+	assert.NotContains(t, content, "/etc/")
+
+	var missing []string
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnMissingMedia {
+			missing = append(missing, w.File)
+		}
+	}
+	assert.Equal(t, []string{srv.URL + "/gone.mp3"}, missing)
+}
@@ -0,0 +1,129 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"crypto/sha256"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// imageRefRe matches chapter references to files in the images directory.
+var imageRefRe = regexp.MustCompile(`\.\./(images/[^"'<>()\s]+)`)
+
+// dedupImages collapses images with identical bytes, such as a logo
+// repeated in every chapter, into one resource and points the chapters'
+// references at it. The cover is kept over its duplicates; otherwise the
+// first image is. Only images of equal size are hashed.
+func (c *Converter) dedupImages(doc *model.Document, opts Options) {
+	bySize := make(map[int64][]int)
+	for i, res := range doc.Resources {
+		if !strings.HasPrefix(res.MediaType, "image/") || res.Fallback != "" {
+			continue
+		}
+		if size, ok := resourceSize(res); ok {
+			bySize[size] = append(bySize[size], i)
+		}
+	}
+
+	renamed := make(map[string]string) // Duplicate file name to kept file name
+	drop := make(map[int]bool)
+	for _, group := range bySize {
+		if len(group) < 2 {
+			continue
+		}
+		kept := make(map[[sha256.Size]byte]int)
+		for _, i := range group {
+			sum, ok := resourceHash(doc.Resources[i])
+			if !ok {
+				continue
+			}
+			k, seen := kept[sum]
+			if !seen {
+				kept[sum] = i
+				continue
+			}
+			dup := i
+			if doc.Resources[i].IsCover && !doc.Resources[k].IsCover {
+				kept[sum] = i
+				dup, k = k, i
+			}
+			renamed[doc.Resources[dup].FileName] = doc.Resources[k].FileName
+			drop[dup] = true
+		}
+	}
+	if len(drop) == 0 {
+		return
+	}
+
+	// A duplicate may have been the kept image of an earlier pair
+	for dup, name := range renamed {
+		for next, ok := renamed[name]; ok; next, ok = renamed[name] {
+			name = next
+		}
+		renamed[dup] = name
+	}
+
+	for i := range doc.Chapters {
+		doc.Chapters[i].Content = imageRefRe.ReplaceAllStringFunc(doc.Chapters[i].Content, func(match string) string {
+			if name, ok := renamed[match[len("../"):]]; ok {
+				return "../" + name
+			}
+			return match
+		})
+	}
+
+	resources := doc.Resources[:0]
+	for i, res := range doc.Resources {
+		if !drop[i] {
+			resources = append(resources, res)
+		}
+	}
+	doc.Resources = resources
+	opts.logger().Debug("removed duplicate images", "stage", "images", "images", len(drop))
+}
+
+// resourceSize returns the size of a resource's data, in memory or on disk.
+func resourceSize(res model.Resource) (int64, bool) {
+	if len(res.Data) > 0 {
+		return int64(len(res.Data)), true
+	}
+	if res.SourcePath == "" {
+		return 0, false
+	}
+	info, err := os.Stat(res.SourcePath)
+	if err != nil {
+		return 0, false
+	}
+	return info.Size(), true
+}
+
+// resourceHash returns the SHA-256 of a resource's data, streaming it from
+// disk if it is not in memory.
+func resourceHash(res model.Resource) ([sha256.Size]byte, bool) {
+	if len(res.Data) > 0 {
+		return sha256.Sum256(res.Data), true
+	}
+	f, err := os.Open(res.SourcePath)
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum, true
+}
@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestConverter_DedupImages(t *testing.T) {
+	dir := t.TempDir()
+	logo := append(append([]byte{}, pngHeader...), "logo"...)
+	other := append(append([]byte{}, pngHeader...), "icon"...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo.png"), logo, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "logo-copy.png"), logo, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "icon.png"), other, 0o644))
+	writeFiles(t, dir, map[string]string{
+		"one.md": "# One\n\n![logo](logo.png)\n\n![icon](icon.png)\n",
+		"two.md": "# Two\n\n![logo](logo-copy.png)\n",
+	})
+
+	output := filepath.Join(dir, "book.epub")
+	_, err := New().Convert([]string{filepath.Join(dir, "one.md"), filepath.Join(dir, "two.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+
+	archive, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	var names []string
+	var chapters string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		if strings.HasPrefix(f.Name, "OEBPS/content/chapter-") {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			chapters += string(content)
+		}
+	}
+	assert.Contains(t, names, "OEBPS/images/logo.png")
+	assert.Contains(t, names, "OEBPS/images/icon.png")
+	assert.NotContains(t, names, "OEBPS/images/logo-copy.png")
+	assert.NotContains(t, chapters, "logo-copy.png")
+	assert.Equal(t, 2, strings.Count(chapters, "../images/logo.png"))
+}
+
+func TestConverter_DedupImages_KeepsCover(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{Content: `<img src="../images/art.png" alt=""/>`})
+	doc.AddResource(model.Resource{ID: "img-art", FileName: "images/art.png", MediaType: "image/png", Data: []byte("same")})
+	doc.AddResource(model.Resource{ID: "cover-image", FileName: "images/cover.png", MediaType: "image/png", Data: []byte("same"), IsCover: true})
+
+	New().dedupImages(doc, Options{})
+	require.Len(t, doc.Resources, 1)
+	assert.True(t, doc.Resources[0].IsCover)
+	assert.Equal(t, `<img src="../images/cover.png" alt=""/>`, doc.Chapters[0].Content)
+}
@@ -0,0 +1,181 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// epubcheckTimeout bounds a run of epubcheck, which starts a JVM.
+const epubcheckTimeout = 5 * time.Minute
+
+var (
+	// ErrEPUBCheck is returned, with the result, when epubcheck reports
+	// errors in a book that was written.
+	ErrEPUBCheck = errors.New("epubcheck reported errors")
+
+	// ErrEPUBCheckNotFound is returned when Options.EPUBCheck is set but
+	// neither a bundled epubcheck.jar nor an epubcheck command is found.
+	ErrEPUBCheckNotFound = errors.New("epubcheck not found")
+)
+
+// epubcheckJars are the paths, relative to the toepub executable, where a
+// bundled epubcheck.jar is looked for before epubcheck on PATH.
+var epubcheckJars = []string{
+	"epubcheck.jar",
+	filepath.Join("epubcheck", "epubcheck.jar"),
+}
+
+// epubcheckReport is the part of epubcheck's --json report that is read.
+type epubcheckReport struct {
+	Messages []struct {
+		ID        string `json:"ID"`
+		Severity  string `json:"severity"`
+		Message   string `json:"message"`
+		Locations []struct {
+			Path   string `json:"path"`
+			Line   int    `json:"line"`
+			Column int    `json:"column"`
+		} `json:"locations"`
+	} `json:"messages"`
+}
+
+// epubcheckCommand returns the command running epubcheck: java -jar with
+// a bundled epubcheck.jar, or the epubcheck command on PATH.
+func epubcheckCommand() ([]string, error) {
+	if exe, err := os.Executable(); err == nil {
+		if java, err := exec.LookPath("java"); err == nil {
+			for _, jar := range epubcheckJars {
+				path := filepath.Join(filepath.Dir(exe), jar)
+				if _, err := os.Stat(path); err == nil {
+					return []string{java, "-jar", path}, nil
+				}
+			}
+		}
+	}
+	if bin, err := exec.LookPath("epubcheck"); err == nil {
+		return []string{bin}, nil
+	}
+	return nil, fmt.Errorf("%w: install epubcheck on PATH, or Java and an epubcheck.jar next to toepub", ErrEPUBCheckNotFound)
+}
+
+// runEPUBCheck runs epubcheck on the written book when Options.EPUBCheck
+// is set, adding its findings to the result as epubcheck warnings. Errors
+// and fatal errors also fail with ErrEPUBCheck; the book is left in place.
+func runEPUBCheck(result *model.ConversionResult, rep *reporter, opts Options) error {
+	if !opts.EPUBCheck {
+		return nil
+	}
+	log := opts.logger()
+	start := time.Now()
+
+	command, err := epubcheckCommand()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "toepub-epubcheck-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	report := filepath.Join(dir, "report.json")
+
+	ctx, cancel := context.WithTimeout(context.Background(), epubcheckTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	args := append(append([]string{}, command[1:]...), result.OutputPath, "--json", report)
+	if opts.Unpacked {
+		// Checks the directory tree as it would be packed
+		args = append(args, "--mode", "exp")
+	}
+	cmd := exec.CommandContext(ctx, command[0], args...)
+	cmd.Stderr = &stderr
+	// epubcheck exits non-zero when it finds errors, so its report decides
+	runErr := cmd.Run()
+
+	data, err := os.ReadFile(report)
+	if err != nil {
+		if runErr != nil {
+			return fmt.Errorf("epubcheck: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+		}
+		return fmt.Errorf("epubcheck wrote no report: %w", err)
+	}
+	warnings, errs, err := parseEPUBCheckReport(data)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		rep.warn(w)
+	}
+	log.Info("ran epubcheck", "stage", "check", "messages", len(warnings), "errors", errs, "duration", time.Since(start))
+
+	if errs > 0 {
+		return fmt.Errorf("%w: %d in %s", ErrEPUBCheck, errs, result.OutputPath)
+	}
+	return nil
+}
+
+// parseEPUBCheckReport converts the messages of an epubcheck JSON report
+// to warnings, returning them with the number of errors and fatal errors.
+// Errors and warnings are reported as warnings; usage hints and
+// information as notes.
+func parseEPUBCheckReport(data []byte) ([]model.Warning, int, error) {
+	var report epubcheckReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, 0, fmt.Errorf("reading epubcheck report: %w", err)
+	}
+
+	var warnings []model.Warning
+	errs := 0
+	for _, m := range report.Messages {
+		severity := model.SeverityWarning
+		switch m.Severity {
+		case "FATAL", "ERROR":
+			errs++
+		case "WARNING":
+		case "SUPPRESSED":
+			continue
+		default:
+			severity = model.SeverityInfo
+		}
+
+		message := fmt.Sprintf("%s %s: %s", m.Severity, m.ID, m.Message)
+		var file string
+		if len(m.Locations) > 0 {
+			loc := m.Locations[0]
+			file = loc.Path
+			if loc.Line > 0 {
+				message += fmt.Sprintf(" (%s:%d:%d)", loc.Path, loc.Line, loc.Column)
+			} else {
+				message += fmt.Sprintf(" (%s)", loc.Path)
+			}
+			if more := len(m.Locations) - 1; more > 0 {
+				message += fmt.Sprintf(" and %d more", more)
+			}
+		}
+		warnings = append(warnings, model.Warning{
+			Code:     model.WarnEPUBCheck,
+			Severity: severity,
+			File:     file,
+			Message:  message,
+		})
+	}
+	return warnings, errs, nil
+}
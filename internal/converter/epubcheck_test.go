@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+const epubcheckSample = `{
+  "checker": {"nFatal": 0, "nError": 1, "nWarning": 1},
+  "messages": [
+    {"ID": "RSC-005", "severity": "ERROR", "message": "Error while parsing file: element \"foo\" not allowed here",
+     "locations": [{"path": "OEBPS/content/chapter-1.xhtml", "line": 12, "column": 4},
+                   {"path": "OEBPS/content/chapter-2.xhtml", "line": 3, "column": 1}]},
+    {"ID": "OPF-085", "severity": "WARNING", "message": "dc:identifier is not a valid UUID",
+     "locations": [{"path": "OEBPS/package.opf", "line": -1, "column": -1}]},
+    {"ID": "ACC-009", "severity": "USAGE", "message": "Missing epub:type on table", "locations": []},
+    {"ID": "CSS-028", "severity": "SUPPRESSED", "message": "Font-face reference", "locations": []}
+  ]
+}`
+
+func TestParseEPUBCheckReport(t *testing.T) {
+	warnings, errs, err := parseEPUBCheckReport([]byte(epubcheckSample))
+	require.NoError(t, err)
+	assert.Equal(t, 1, errs)
+	require.Len(t, warnings, 3)
+
+	assert.Equal(t, model.Warning{
+		Code:     model.WarnEPUBCheck,
+		Severity: model.SeverityWarning,
+		File:     "OEBPS/content/chapter-1.xhtml",
+		Message:  `ERROR RSC-005: Error while parsing file: element "foo" not allowed here (OEBPS/content/chapter-1.xhtml:12:4) and 1 more`,
+	}, warnings[0])
+	assert.Equal(t, "WARNING OPF-085: dc:identifier is not a valid UUID (OEBPS/package.opf)", warnings[1].Message)
+	assert.Equal(t, model.SeverityWarning, warnings[1].Severity)
+	assert.Equal(t, model.SeverityInfo, warnings[2].Severity)
+	assert.Empty(t, warnings[2].File)
+
+	_, _, err = parseEPUBCheckReport([]byte("Check finished with errors"))
+	assert.Error(t, err)
+}
+
+func TestConverter_Convert_EPUBCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake epubcheck is a shell script")
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"book.md": "# One\n\nText.\n"})
+	convert := func() (*model.ConversionResult, error) {
+		return New().Convert([]string{filepath.Join(dir, "book.md")}, Options{
+			OutputPath: filepath.Join(dir, "book.epub"),
+			EPUBCheck:  true,
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		_, err := convert()
+		assert.ErrorIs(t, err, ErrEPUBCheckNotFound)
+	})
+
+	// fakeEPUBCheck puts an epubcheck on PATH that writes report as its
+	// --json report, after checking it was given the book
+	fakeEPUBCheck := func(t *testing.T, report string) {
+		bin := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "report.json"), []byte(report), 0o644))
+		script := "#!/bin/sh\n[ -f \"$1\" ] && [ \"$2\" = --json ] || exit 2\ncp \"" + filepath.Join(bin, "report.json") + "\" \"$3\"\nexit 1\n"
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "epubcheck"), []byte(script), 0o755))
+		t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		fakeEPUBCheck(t, epubcheckSample)
+		result, err := convert()
+		assert.ErrorIs(t, err, ErrEPUBCheck)
+		require.NotNil(t, result)
+		assert.True(t, result.Success)
+		assert.FileExists(t, result.OutputPath)
+
+		var found int
+		for _, w := range result.Warnings {
+			if w.Code == model.WarnEPUBCheck {
+				found++
+			}
+		}
+		assert.Equal(t, 3, found)
+	})
+
+	t.Run("clean", func(t *testing.T) {
+		fakeEPUBCheck(t, `{"messages": []}`)
+		result, err := convert()
+		require.NoError(t, err)
+		assert.True(t, result.Success)
+	})
+}
@@ -0,0 +1,68 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// EventHandler receives conversion events as they happen: skipped
+// resources, guessed encodings, and the other warnings and notes that also
+// end up in ConversionResult.Warnings. Events are delivered on the
+// converting goroutine, in order.
+type EventHandler interface {
+	HandleEvent(event model.Warning)
+}
+
+// EventFunc adapts a function to an EventHandler.
+type EventFunc func(event model.Warning)
+
+// HandleEvent calls f.
+func (f EventFunc) HandleEvent(event model.Warning) {
+	f(event)
+}
+
+// LogEvents returns a handler that logs each event to logger, at info
+// level for notes and warn level for warnings.
+func LogEvents(logger *slog.Logger) EventHandler {
+	return EventFunc(func(event model.Warning) {
+		level := slog.LevelWarn
+		if event.Severity == model.SeverityInfo {
+			level = slog.LevelInfo
+		}
+		logger.Log(context.Background(), level, event.Message,
+			"stage", "events", "code", event.Code, "severity", event.Severity, "file", event.File)
+	})
+}
+
+// reporter records a conversion's events in its result and passes them
+// on to the handler set in Options.Events.
+type reporter struct {
+	result  *model.ConversionResult
+	handler EventHandler
+	file    string // Input file being parsed, for events without a file
+}
+
+// newReporter returns a reporter for result.
+func newReporter(result *model.ConversionResult, opts Options) *reporter {
+	return &reporter{result: result, handler: opts.Events}
+}
+
+// warn records an event, which is a warning unless its severity is set.
+func (r *reporter) warn(event model.Warning) {
+	if event.File == "" {
+		event.File = r.file
+	}
+	r.result.AddWarning(event)
+	if r.handler != nil {
+		r.handler.HandleEvent(r.result.Warnings[len(r.result.Warnings)-1])
+	}
+}
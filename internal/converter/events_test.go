@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+func TestConverter_ConvertReader_Events(t *testing.T) {
+	var events []model.Warning
+	opts := Options{Events: EventFunc(func(e model.Warning) { events = append(events, e) })}
+
+	input := "# One\n\nCaf\xe9 ![A diagram](figure.png) ![remote](https://example.com/a.png)\n"
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, opts)
+	require.NoError(t, err)
+
+	// Parser and converter events arrive in order and match the result
+	assert.Equal(t, result.Warnings, events)
+	codes := make([]string, 0, len(events))
+	for _, e := range events {
+		codes = append(codes, e.Code)
+	}
+	assert.Equal(t, []string{model.WarnEncodingGuessed, model.WarnRemoteResource, model.WarnResourceNotLoaded, model.WarnBrokenLink}, codes)
+}
+
+func TestLogEvents(t *testing.T) {
+	var buf bytes.Buffer
+	handler := LogEvents(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	handler.HandleEvent(model.Warning{Code: model.WarnMissingImage, Severity: model.SeverityWarning, File: "a.png", Message: "Image a.png: missing"})
+	handler.HandleEvent(model.Warning{Code: model.WarnRemoteResource, Severity: model.SeverityInfo, Message: "linked"})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "level=WARN")
+	assert.Contains(t, lines[0], "code=missing_image")
+	assert.Contains(t, lines[0], "file=a.png")
+	assert.Contains(t, lines[1], "level=INFO")
+}
@@ -0,0 +1,330 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// metadataFileName is the Markdown file holding only the front matter of an
+// extracted book. Its name sorts before the chapters, so converting the
+// directory again uses its metadata.
+const metadataFileName = "00-metadata.md"
+
+// sectionNumberRe matches the section number the builder adds to headings.
+var sectionNumberRe = regexp.MustCompile(`(?s)<span class="section-number">.*?</span>`)
+
+// Extract unpacks an EPUB into Markdown sources in outputDir: one Markdown
+// file per chapter, an images directory, and a front-matter metadata file.
+// Converting the directory again produces an equivalent book. Events are
+// logged to slog.Default().
+func (c *Converter) Extract(input, outputDir string) (*model.ConversionResult, error) {
+	start := time.Now()
+	result := &model.ConversionResult{OutputPath: outputDir}
+	result.Stats.InputFormat = parser.FormatEPUB.String()
+	result.Stats.InputFiles = 1
+
+	content, err := os.ReadFile(input)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, fmt.Errorf("%w: %s", ErrFileNotFound, input)
+	}
+	if err != nil {
+		return result, fmt.Errorf("reading %s: %w", input, err)
+	}
+
+	doc, err := parser.NewEPUBParser().Parse(content, filepath.Dir(input))
+	if err != nil {
+		return result, &ParseError{File: input, Err: err}
+	}
+	slog.Info("parsed file", "stage", "parse", "file", input, "format", parser.FormatEPUB.String(),
+		"chapters", len(doc.Chapters), "duration", time.Since(start))
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return result, fmt.Errorf("%w: %s", ErrOutputNotWrite, outputDir)
+	}
+
+	// Write images first so chapters can link to their new paths
+	images, err := writeExtractedImages(doc, outputDir, &result.Stats.OutputSize)
+	if err != nil {
+		return result, err
+	}
+	result.Stats.ImageCount = len(images)
+
+	// Footnotes and endnotes go back where they are referenced, and pages
+	// holding nothing else are left out, as is the colophon the builder adds
+	notes := referencedNotes(doc.Chapters)
+	chapters := make([]model.Chapter, 0, len(doc.Chapters))
+	for _, chapter := range doc.Chapters {
+		if !generatedChapter(chapter, notes) {
+			chapters = append(chapters, chapter)
+		}
+	}
+
+	names := extractedChapterNames(chapters)
+	if err := writeExtractedFile(filepath.Join(outputDir, metadataFileName), frontMatter(doc, images), &result.Stats.OutputSize); err != nil {
+		return result, err
+	}
+
+	for _, chapter := range chapters {
+		writer := &markdownWriter{
+			rewriteURL: func(u string) string {
+				return rewriteExtractedURL(u, chapter.FileName, images, names)
+			},
+			note: func(href string) *html.Node {
+				return notes[extractedTarget(href, chapter.FileName)]
+			},
+		}
+		md, err := writer.Convert(chapter.Content)
+		if err != nil {
+			return result, fmt.Errorf("converting %s: %w", chapter.FileName, err)
+		}
+		if chapter.Language != "" && !strings.EqualFold(chapter.Language, doc.Metadata.Language) {
+			// Keep the language of a chapter written in another one
+			md = fmt.Sprintf("---\nlang: %s\n---\n\n", chapter.Language) + md
+		}
+		if err := writeExtractedFile(filepath.Join(outputDir, names[chapter.FileName]), md, &result.Stats.OutputSize); err != nil {
+			return result, err
+		}
+		slog.Debug("wrote chapter", "stage", "write", "file", names[chapter.FileName])
+	}
+	result.Stats.ChapterCount = len(chapters)
+
+	result.Success = true
+	result.Stats.Duration = time.Since(start)
+	return result, nil
+}
+
+// writeExtractedImages writes the book's images to outputDir/images and
+// returns their new paths, relative to outputDir, keyed by EPUB path.
+func writeExtractedImages(doc *model.Document, outputDir string, size *int64) (map[string]string, error) {
+	images := make(map[string]string, len(doc.Resources))
+	used := make(map[string]bool, len(doc.Resources))
+
+	for _, res := range doc.Resources {
+		name := path.Base(res.FileName)
+		ext := path.Ext(name)
+		for i := 2; used[name]; i++ {
+			name = strings.TrimSuffix(path.Base(res.FileName), ext) + "-" + strconv.Itoa(i) + ext
+		}
+		used[name] = true
+
+		if err := os.MkdirAll(filepath.Join(outputDir, "images"), 0o755); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutputNotWrite, outputDir)
+		}
+		if err := writeExtractedFile(filepath.Join(outputDir, "images", name), string(res.Data), size); err != nil {
+			return nil, err
+		}
+		images[res.FileName] = "images/" + name
+	}
+	return images, nil
+}
+
+// referencedNotes returns the footnotes and endnotes of the chapters that
+// are referenced from a noteref link, keyed by EPUB path and id.
+func referencedNotes(chapters []model.Chapter) map[string]*html.Node {
+	notes := make(map[string]*html.Node)
+	referenced := make(map[string]bool)
+	for _, chapter := range chapters {
+		nodes, err := html.ParseFragment(strings.NewReader(chapter.Content), &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"})
+		if err != nil {
+			continue
+		}
+		var walk func(*html.Node)
+		walk = func(n *html.Node) {
+			if n.Type == html.ElementNode {
+				if id := attr(n, "id"); id != "" && isNote(n) {
+					notes[chapter.FileName+"#"+id] = n
+				}
+				if n.DataAtom == atom.A && hasToken(attr(n, "epub:type"), "noteref") {
+					referenced[extractedTarget(attr(n, "href"), chapter.FileName)] = true
+				}
+			}
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c)
+			}
+		}
+		for _, n := range nodes {
+			walk(n)
+		}
+	}
+
+	for key := range notes {
+		if !referenced[key] {
+			delete(notes, key)
+		}
+	}
+	return notes
+}
+
+// generatedChapter reports whether a chapter is left out of the extracted
+// sources: the colophon the builder adds to every book, or a page listing
+// only notes that are written where they are referenced.
+func generatedChapter(chapter model.Chapter, notes map[string]*html.Node) bool {
+	if hasToken(chapter.Type, "colophon") && strings.Contains(chapter.Content, `class="packaged-by"`) {
+		return true
+	}
+	nodes, err := html.ParseFragment(strings.NewReader(chapter.Content), &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"})
+	if err != nil {
+		return false
+	}
+	body := &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+	writer := &markdownWriter{note: func(href string) *html.Node {
+		return notes[extractedTarget(href, chapter.FileName)]
+	}}
+	return writer.notesOnly(body)
+}
+
+// extractedTarget resolves a link in a chapter to the EPUB path it points
+// to, keeping its fragment.
+func extractedTarget(raw, chapterFile string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return ""
+	}
+	target := chapterFile
+	if u.Path != "" {
+		target = path.Join(path.Dir(chapterFile), u.Path)
+	}
+	return target + "#" + u.Fragment
+}
+
+// extractedChapterNames returns numbered Markdown file names for chapters,
+// keyed by EPUB path, such as "01-introduction.md". They are named after
+// their first heading, without the section number the builder adds.
+func extractedChapterNames(chapters []model.Chapter) map[string]string {
+	width := len(strconv.Itoa(len(chapters)))
+	if width < 2 {
+		width = 2
+	}
+
+	names := make(map[string]string, len(chapters))
+	for i, chapter := range chapters {
+		title := chapter.Title
+		if m := headingTagRe.FindStringSubmatch(chapter.Content); m != nil {
+			title = headingText(sectionNumberRe.ReplaceAllString(m[2], ""))
+		}
+		slug := strings.Join(strings.FieldsFunc(sanitizeID(strings.ToLower(title)), func(r rune) bool { return r == '-' }), "-")
+		if len(slug) > 40 {
+			slug = strings.TrimRight(slug[:40], "-")
+		}
+		if slug == "" {
+			slug = "chapter"
+		}
+		names[chapter.FileName] = fmt.Sprintf("%0*d-%s.md", width, i+1, slug)
+	}
+	return names
+}
+
+// rewriteExtractedURL maps a URL in a chapter to the extracted image or
+// chapter file it points to. External and unknown URLs are unchanged.
+func rewriteExtractedURL(raw, chapterFile string, images, chapters map[string]string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" {
+		return raw
+	}
+
+	target := path.Join(path.Dir(chapterFile), u.Path)
+	if image, ok := images[target]; ok {
+		return image
+	}
+	if name, ok := chapters[target]; ok {
+		if u.Fragment != "" {
+			return name + "#" + u.Fragment
+		}
+		return name
+	}
+	return raw
+}
+
+// frontMatter returns a Markdown file holding the book metadata as YAML
+// front matter, in the keys read by the Markdown parser.
+func frontMatter(doc *model.Document, images map[string]string) string {
+	meta := doc.Metadata
+	fm := yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value interface{}) {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return
+		}
+		fm.Content = append(fm.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &v)
+	}
+
+	add("title", meta.Title)
+	switch len(meta.Authors) {
+	case 0:
+	case 1:
+		add("author", meta.Authors[0])
+	default:
+		add("author", meta.Authors)
+	}
+	switch {
+	case len(meta.Languages) > 0:
+		add("language", append([]string{meta.Language}, meta.Languages...))
+	case meta.Language != "":
+		add("language", meta.Language)
+	}
+	identifier := false
+	for _, id := range meta.Identifiers {
+		switch {
+		case id.Scheme == model.SchemeISBN:
+			add(id.Scheme, strings.TrimPrefix(id.Value, model.ISBNPrefix))
+		case id.Scheme == model.SchemeDOI:
+			add(id.Scheme, id.Value)
+		case !identifier:
+			// Keep the book identity so reading systems see the same book
+			add("identifier", id.Value)
+			identifier = true
+		}
+	}
+	if meta.Publisher != "" {
+		add("publisher", meta.Publisher)
+	}
+	if meta.Description != "" {
+		add("description", meta.Description)
+	}
+	for _, res := range doc.Resources {
+		if res.IsCover {
+			add("cover", images[res.FileName])
+		}
+	}
+
+	out, err := yaml.Marshal(&fm)
+	if err != nil {
+		return "---\n---\n"
+	}
+	return "---\n" + string(out) + "---\n"
+}
+
+// writeExtractedFile writes an extracted file and adds its size to size.
+func writeExtractedFile(name, content string, size *int64) error {
+	if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutputNotWrite, name)
+	}
+	*size += int64(len(content))
+	return nil
+}
@@ -0,0 +1,159 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+func TestMarkdownWriter_Convert(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"heading and paragraph", `<h1 id="intro">Intro</h1><p>Some <em>emph</em> and <strong>bold</strong>.</p>`, "# Intro\n\nSome *emph* and **bold**.\n"},
+		{"custom heading id", `<h2 id="custom">Setup</h2>`, "## Setup {#custom}\n"},
+		{"escaping", `<p>1 &lt; 2 with under_score</p>`, "1 \\< 2 with under\\_score\n"},
+		{"link and image", `<p><a href="https://example.com">site</a> <img src="../images/a.png" alt="A"/></p>`, "[site](https://example.com) ![A](images/a.png)\n"},
+		{"lists", `<ul><li>one</li><li>two<ol><li>nested</li></ol></li></ul>`, "- one\n- two\n  1. nested\n"},
+		{"code block", `<pre><code class="language-go">x := 1</code></pre>`, "```go\nx := 1\n```\n"},
+		{"footnote", `<p>See<sup id="fnref:1"><a epub:type="noteref" href="#fn:1" role="doc-noteref">1</a></sup>.</p><div class="footnotes"><hr /><aside epub:type="footnote" id="fn:1" role="doc-footnote"><p>Note.&#160;<a href="#fnref:1" role="doc-backlink">↩</a></p></aside></div>`, "See[^1].\n\n[^1]: Note.\n"},
+		{"unreferenced note", `<aside epub:type="footnote" id="fn:2"><p>Kept.</p></aside>`, "<aside epub:type=\"footnote\" id=\"fn:2\"><p>Kept.</p></aside>\n"},
+		{"page break", `<p>a</p><div class="page-break" id="pagebreak-1"></div><p>b</p>`, "a\n\n<!-- pagebreak -->\n\nb\n"},
+	}
+
+	images := map[string]string{"images/a.png": "images/a.png"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notes := referencedNotes([]model.Chapter{{FileName: "content/chapter-001.xhtml", Content: tt.in}})
+			writer := &markdownWriter{
+				rewriteURL: func(u string) string {
+					return rewriteExtractedURL(u, "content/chapter-001.xhtml", images, nil)
+				},
+				note: func(href string) *html.Node {
+					return notes[extractedTarget(href, "content/chapter-001.xhtml")]
+				},
+			}
+			md, err := writer.Convert(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, md)
+		})
+	}
+}
+
+func TestConverter_Extract(t *testing.T) {
+	dir := t.TempDir()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Round Trip"
+	doc.Metadata.Authors = []string{"Ann", "Bob"}
+	doc.Metadata.Publisher = "Acme"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "First Steps",
+		Content:  `<h1 id="first-steps">First Steps</h1><p>See <a href="chapter-002.xhtml#more">more</a>.</p><p><img src="../images/fig.png" alt="Fig"/></p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch2",
+		Title:    "More",
+		Content:  `<h1 id="more">More</h1><p>Text.</p>`,
+		FileName: "content/chapter-002.xhtml",
+	})
+	doc.AddResource(model.Resource{ID: "fig", FileName: "images/fig.png", MediaType: "image/png", Data: []byte("fig")})
+	doc.AddResource(model.Resource{ID: "cover-image", FileName: "images/cover.png", MediaType: "image/png", Data: []byte("cover"), IsCover: true})
+
+	data, err := epub.NewBuilder().Build(doc)
+	require.NoError(t, err)
+	input := filepath.Join(dir, "book.epub")
+	require.NoError(t, os.WriteFile(input, data, 0o644))
+
+	out := filepath.Join(dir, "src")
+	result, err := New().Extract(input, out)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, 2, result.Stats.ChapterCount)
+	assert.Equal(t, 2, result.Stats.ImageCount)
+
+	meta, err := os.ReadFile(filepath.Join(out, metadataFileName))
+	require.NoError(t, err)
+	assert.Contains(t, string(meta), "title: Round Trip\n")
+	assert.Contains(t, string(meta), "    - Ann\n    - Bob\n")
+	assert.Contains(t, string(meta), "publisher: Acme\n")
+	assert.Contains(t, string(meta), "cover: images/cover.png\n")
+
+	first, err := os.ReadFile(filepath.Join(out, "01-first-steps.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# First Steps\n\nSee [more](02-more.md#more).\n\n![Fig](images/fig.png)\n", string(first))
+
+	image, err := os.ReadFile(filepath.Join(out, "images", "fig.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "fig", string(image))
+
+	// Converting the sources again restores the book
+	rebuilt, err := New().Convert([]string{out}, Options{OutputPath: filepath.Join(dir, "rebuilt.epub")})
+	require.NoError(t, err)
+	assert.True(t, rebuilt.Success)
+	assert.Equal(t, 3, rebuilt.Stats.InputFiles)
+}
+
+func TestConverter_Extract_RoundTrip(t *testing.T) {
+	for _, notes := range []string{model.NotesPopup, model.NotesChapter, model.NotesBook} {
+		t.Run(notes, func(t *testing.T) {
+			dir := t.TempDir()
+			src := filepath.Join(dir, "src")
+			require.NoError(t, os.Mkdir(src, 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(src, "01.md"), []byte("# Intro\n\nSee [setup](02.md#install).[^a]\n\n[^a]: A *note*.\n\n    Its second paragraph.\n"), 0o644))
+			require.NoError(t, os.WriteFile(filepath.Join(src, "02.md"), []byte("# Setup\n\n## Install\n\nRun it.[^b]\n\n[^b]: Another note.\n"), 0o644))
+
+			opts := Options{OutputPath: filepath.Join(dir, "book.epub"), Notes: notes, EPUB: epub.Options{NumberSections: true}}
+			result, err := New().Convert([]string{src}, opts)
+			require.NoError(t, err)
+			require.Empty(t, result.Warnings)
+
+			out := filepath.Join(dir, "extracted")
+			extracted, err := New().Extract(opts.OutputPath, out)
+			require.NoError(t, err)
+			assert.Equal(t, 2, extracted.Stats.ChapterCount)
+
+			intro, err := os.ReadFile(filepath.Join(out, "01-intro.md"))
+			require.NoError(t, err)
+			assert.Equal(t, "# Intro\n\nSee [setup](02-setup.md#install).[^1]\n\n[^1]: A *note*.\n\n    Its second paragraph.\n", string(intro))
+			setup, err := os.ReadFile(filepath.Join(out, "02-setup.md"))
+			require.NoError(t, err)
+			assert.Equal(t, "# Setup\n\n## Install\n\nRun it.[^1]\n\n[^1]: Another note.\n", string(setup))
+
+			opts.OutputPath = filepath.Join(dir, "rebuilt.epub")
+			rebuilt, err := New().Convert([]string{out}, opts)
+			require.NoError(t, err)
+			assert.Empty(t, rebuilt.Warnings)
+			assert.Equal(t, result.Stats.ChapterCount, rebuilt.Stats.ChapterCount)
+		})
+	}
+}
+
+func TestConverter_Extract_InvalidEPUB(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "book.epub")
+	require.NoError(t, os.WriteFile(input, []byte("not a zip"), 0o644))
+
+	_, err := New().Extract(input, filepath.Join(dir, "src"))
+	require.Error(t, err)
+	assert.ErrorIs(t, err, parser.ErrInvalidEPUB)
+
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, input, parseErr.File)
+}
+
+func TestConverter_Extract_MissingFile(t *testing.T) {
+	_, err := New().Extract(filepath.Join(t.TempDir(), "missing.epub"), t.TempDir())
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
@@ -0,0 +1,230 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrFetch is returned when a resource cannot be fetched.
+var ErrFetch = errors.New("fetching resource")
+
+// defaultMaxFetchSize limits HTTP responses when HTTPFetcher.MaxSize is 0.
+const defaultMaxFetchSize = 50 << 20
+
+// ResourceFetcher reads the images and stylesheets a document references.
+// The reference is the path a parser resolved against its input, or a URL.
+// Implementations must be safe for concurrent use.
+type ResourceFetcher interface {
+	Fetch(ref string) ([]byte, error)
+}
+
+// FileFetcher reads resources from local disk, the default when
+// Options.Fetcher is nil.
+type FileFetcher struct{}
+
+// Fetch reads the file at ref.
+func (FileFetcher) Fetch(ref string) ([]byte, error) {
+	data, err := os.ReadFile(ref)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, missingError(ref)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrFetch, ref, err)
+	}
+	return data, nil
+}
+
+// fetcher returns the fetcher for the files a conversion reads:
+// o.Fetcher, or a FileFetcher if it is nil.
+func (o Options) fetcher() ResourceFetcher {
+	if o.Fetcher != nil {
+		return o.Fetcher
+	}
+	return FileFetcher{}
+}
+
+// missingError reports a file that does not exist. It matches both
+// ErrFileNotFound and fs.ErrNotExist, which parsers check for files that
+// are optional.
+type missingError string
+
+func (e missingError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrFileNotFound, string(e))
+}
+
+func (e missingError) Is(target error) bool {
+	return target == ErrFileNotFound || target == fs.ErrNotExist
+}
+
+// HTTPFetcher downloads http and https resources.
+type HTTPFetcher struct {
+	Client  *http.Client // nil uses a client with a 30 second timeout
+	MaxSize int64        // Largest response body in bytes; 0 means 50 MB
+}
+
+// Fetch downloads the resource at ref.
+func (f HTTPFetcher) Fetch(ref string) ([]byte, error) {
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	maxSize := f.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultMaxFetchSize
+	}
+
+	resp, err := client.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFetch, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, ref)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %s: %s", ErrFetch, ref, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrFetch, ref, err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: %s: larger than %d bytes", ErrFetch, ref, maxSize)
+	}
+	return data, nil
+}
+
+// DataURIFetcher decodes data: URIs, such as "data:image/png;base64,...".
+type DataURIFetcher struct{}
+
+// Fetch returns the data held in the URI ref.
+func (DataURIFetcher) Fetch(ref string) ([]byte, error) {
+	rest, ok := strings.CutPrefix(ref, "data:")
+	if !ok {
+		return nil, fmt.Errorf("%w: not a data URI", ErrFetch)
+	}
+	header, payload, ok := strings.Cut(rest, ",")
+	if !ok {
+		return nil, fmt.Errorf("%w: data URI has no comma", ErrFetch)
+	}
+
+	if strings.HasSuffix(header, ";base64") {
+		// Line breaks are common in URIs copied from documents
+		payload = strings.Join(strings.Fields(payload), "")
+		data, err := base64.StdEncoding.DecodeString(payload)
+		if err != nil {
+			data, err = base64.RawStdEncoding.DecodeString(payload)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: decoding data URI: %w", ErrFetch, err)
+		}
+		return data, nil
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding data URI: %w", ErrFetch, err)
+	}
+	return []byte(data), nil
+}
+
+// BucketFetcher reads s3:// and gs:// references from publicly readable
+// buckets over HTTPS. Private buckets need a ResourceFetcher built on the
+// provider's SDK.
+type BucketFetcher struct {
+	HTTP     HTTPFetcher
+	S3Region string // Region for S3 URLs; empty uses the global endpoint
+}
+
+// Fetch downloads the object at ref.
+func (f BucketFetcher) Fetch(ref string) ([]byte, error) {
+	u, err := url.Parse(ref)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid bucket URL %s", ErrFetch, ref)
+	}
+	key := strings.TrimPrefix(u.EscapedPath(), "/")
+
+	switch u.Scheme {
+	case "s3":
+		host := u.Host + ".s3.amazonaws.com"
+		if f.S3Region != "" {
+			host = u.Host + ".s3." + f.S3Region + ".amazonaws.com"
+		}
+		return f.HTTP.Fetch("https://" + host + "/" + key)
+	case "gs":
+		return f.HTTP.Fetch("https://storage.googleapis.com/" + u.Host + "/" + key)
+	default:
+		return nil, fmt.Errorf("%w: unsupported bucket scheme %q", ErrFetch, u.Scheme)
+	}
+}
+
+// SchemeFetcher routes each reference to a fetcher by its URL scheme.
+// References without a scheme, such as local paths, go to Default.
+type SchemeFetcher struct {
+	Schemes map[string]ResourceFetcher // Lowercase scheme, such as "https" or "s3"
+	Default ResourceFetcher            // nil uses FileFetcher
+}
+
+// NewSchemeFetcher returns a fetcher for local files, data URIs, http and
+// https URLs, and public s3 and gs buckets.
+func NewSchemeFetcher() *SchemeFetcher {
+	web := HTTPFetcher{}
+	return &SchemeFetcher{
+		Schemes: map[string]ResourceFetcher{
+			"data":  DataURIFetcher{},
+			"http":  web,
+			"https": web,
+			"s3":    BucketFetcher{HTTP: web},
+			"gs":    BucketFetcher{HTTP: web},
+		},
+		Default: FileFetcher{},
+	}
+}
+
+// Fetch reads ref with the fetcher registered for its scheme.
+func (f *SchemeFetcher) Fetch(ref string) ([]byte, error) {
+	if scheme := refScheme(ref); scheme != "" {
+		fetcher, ok := f.Schemes[scheme]
+		if !ok {
+			return nil, fmt.Errorf("%w: no fetcher for scheme %q", ErrFetch, scheme)
+		}
+		return fetcher.Fetch(ref)
+	}
+	if f.Default == nil {
+		return FileFetcher{}.Fetch(ref)
+	}
+	return f.Default.Fetch(ref)
+}
+
+// refScheme returns the lowercase URL scheme of ref, or "" for paths.
+// Single letters are Windows drive letters rather than schemes.
+func refScheme(ref string) string {
+	scheme, _, ok := strings.Cut(ref, ":")
+	if !ok || len(scheme) < 2 || strings.ContainsAny(scheme, `/\`) {
+		return ""
+	}
+	for i, r := range scheme {
+		isLetter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+		if !isLetter && (i == 0 || !(r >= '0' && r <= '9' || r == '+' || r == '-' || r == '.')) {
+			return ""
+		}
+	}
+	return strings.ToLower(scheme)
+}
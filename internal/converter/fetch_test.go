@@ -0,0 +1,161 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// pngHeader is enough of a PNG file for format detection.
+var pngHeader = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A, 0, 0, 0, 0}
+
+// mapFetcher serves resources from memory.
+type mapFetcher map[string][]byte
+
+func (m mapFetcher) Fetch(ref string) ([]byte, error) {
+	data, ok := m[ref]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, ref)
+	}
+	return data, nil
+}
+
+func TestFileFetcher(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "style.css")
+	require.NoError(t, os.WriteFile(name, []byte("p {}"), 0o644))
+
+	data, err := FileFetcher{}.Fetch(name)
+	require.NoError(t, err)
+	assert.Equal(t, "p {}", string(data))
+
+	_, err = FileFetcher{}.Fetch(name + ".missing")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/image.png":
+			_, _ = w.Write(pngHeader)
+		case "/large":
+			_, _ = w.Write(make([]byte, 100))
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	data, err := HTTPFetcher{}.Fetch(srv.URL + "/image.png")
+	require.NoError(t, err)
+	assert.Equal(t, pngHeader, data)
+
+	_, err = HTTPFetcher{}.Fetch(srv.URL + "/missing.png")
+	assert.ErrorIs(t, err, ErrFileNotFound)
+
+	_, err = HTTPFetcher{}.Fetch(srv.URL + "/error")
+	assert.ErrorIs(t, err, ErrFetch)
+
+	_, err = HTTPFetcher{MaxSize: 10}.Fetch(srv.URL + "/large")
+	assert.ErrorIs(t, err, ErrFetch)
+}
+
+func TestDataURIFetcher(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  string
+		want string
+	}{
+		{"base64", "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("png")), "png"},
+		{"unpadded", "data:text/plain;base64,cG5n", "png"},
+		{"percent-encoded", "data:text/css,p%20%7B%7D", "p {}"},
+		{"no media type", "data:,plain", "plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := DataURIFetcher{}.Fetch(tt.ref)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+
+	_, err := DataURIFetcher{}.Fetch("data:image/png;base64")
+	assert.ErrorIs(t, err, ErrFetch)
+	_, err = DataURIFetcher{}.Fetch("image.png")
+	assert.ErrorIs(t, err, ErrFetch)
+}
+
+func TestSchemeFetcher(t *testing.T) {
+	f := &SchemeFetcher{
+		Schemes: map[string]ResourceFetcher{
+			"data": DataURIFetcher{},
+			"s3":   mapFetcher{"s3://bucket/a.css": []byte("from s3")},
+		},
+		Default: mapFetcher{"images/a.png": []byte("local"), `C:\book\a.png`: []byte("drive")},
+	}
+
+	for ref, want := range map[string]string{
+		"data:,inline":      "inline",
+		"S3://bucket/a.css": "",
+		"s3://bucket/a.css": "from s3",
+		"images/a.png":      "local",
+		`C:\book\a.png`:     "drive",
+	} {
+		data, err := f.Fetch(ref)
+		if want == "" {
+			assert.Error(t, err, ref)
+			continue
+		}
+		require.NoError(t, err, ref)
+		assert.Equal(t, want, string(data), ref)
+	}
+
+	_, err := f.Fetch("ftp://host/a.png")
+	assert.ErrorIs(t, err, ErrFetch)
+}
+
+func TestRefScheme(t *testing.T) {
+	assert.Equal(t, "https", refScheme("HTTPS://example.com/a.png"))
+	assert.Equal(t, "data", refScheme("data:,x"))
+	assert.Equal(t, "", refScheme(`C:\images\a.png`))
+	assert.Equal(t, "", refScheme("images/a:b.png"))
+	assert.Equal(t, "", refScheme("a.png"))
+}
+
+func TestConverter_ConvertReader_Fetcher(t *testing.T) {
+	input := "# One\n\n![A figure](figure.png)\n\n![A lost figure](lost.png)\n"
+	opts := Options{Fetcher: mapFetcher{"figure.png": pngHeader}}
+
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader(input), parser.FormatMarkdown, &out, opts)
+	require.NoError(t, err)
+
+	require.Len(t, result.Warnings, 2)
+	assert.Contains(t, result.Warnings[0].Message, "lost.png")
+	assert.Equal(t, model.WarnBrokenLink, result.Warnings[1].Code)
+	assert.Contains(t, result.Warnings[1].Message, "../images/lost.png")
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	found := false
+	for _, f := range archive.File {
+		if strings.HasPrefix(f.Name, "OEBPS/images/figure") {
+			found = true
+		}
+	}
+	assert.True(t, found)
+}
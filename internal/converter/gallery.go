@@ -0,0 +1,44 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// expandGallery lists the images in dir, in file name order, as a gallery
+// input. Images matching the directory's .toepubignore rules or the
+// exclude rules are skipped, and a directory without images is an error.
+func expandGallery(dir string, exclude ignoreRules) (inputFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return inputFile{}, fmt.Errorf("%w: %s", ErrFileNotFound, dir)
+	}
+	rules, err := loadIgnoreRules(diskTree(dir).file(ignoreFileName))
+	if err != nil {
+		return inputFile{}, err
+	}
+	rules = append(rules, exclude...)
+
+	var images []string
+	for _, entry := range entries {
+		if !entry.IsDir() && parser.IsGalleryImage(entry.Name()) && !rules.ignored(entry.Name(), false) {
+			images = append(images, filepath.Join(dir, entry.Name()))
+		}
+	}
+	if len(images) == 0 {
+		return inputFile{}, fmt.Errorf("%w: no images in gallery %s", ErrNoInput, dir)
+	}
+	sort.Strings(images)
+	return inputFile{Path: dir, Gallery: images}, nil
+}
@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Convert_Gallery(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"intro.md":          "# Intro\n",
+		"photos/a.png":      string(pngResource(t, 4, 3).Data),
+		"photos/b.png":      string(pngResource(t, 3, 4).Data),
+		"photos/b.txt":      "Second photo\n",
+		"photos/notes.yaml": "ignored: true\n",
+		"empty/notes.yaml":  "ignored: true\n",
+	})
+
+	files, err := New().expandInputs([]string{filepath.Join(dir, "photos")}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []inputFile{{
+		Path:    filepath.Join(dir, "photos"),
+		Gallery: []string{filepath.Join(dir, "photos", "a.png"), filepath.Join(dir, "photos", "b.png")},
+	}}, files)
+
+	_, err = New().expandInputs(nil, Options{Gallery: []string{filepath.Join(dir, "empty")}})
+	assert.ErrorIs(t, err, ErrNoInput)
+
+	output := filepath.Join(dir, "book.epub")
+	result, err := New().Convert([]string{filepath.Join(dir, "intro.md")}, Options{
+		OutputPath:  output,
+		Gallery:     []string{filepath.Join(dir, "photos")},
+		GalleryGrid: 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", result.Stats.InputFormat)
+
+	page := readEPUBEntry(t, output, "OEBPS/content/chapter-002.xhtml")
+	assert.Contains(t, page, `<img src="../images/a.png" alt="A"/>`)
+	assert.Contains(t, page, `<figcaption>Second photo</figcaption>`)
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/nav.xhtml"), `<a href="content/chapter-002.xhtml">A</a>`)
+
+	result, err = New().Convert([]string{filepath.Join(dir, "photos")}, Options{OutputPath: output})
+	require.NoError(t, err)
+	assert.Equal(t, "gallery", result.Stats.InputFormat)
+	assert.Equal(t, 3, result.Stats.ChapterCount, "two photo pages and the colophon")
+}
@@ -0,0 +1,82 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"strings"
+)
+
+// Animated GIF handling for Options.AnimatedGIF. Many e-readers show only
+// the first frame of an animation, or flicker through it on e-ink.
+const (
+	GIFKeep   = "keep"   // Embed animated GIFs as-is (default)
+	GIFPNG    = "png"    // Replace animated GIFs with a PNG of their first frame
+	GIFStatic = "static" // Replace animated GIFs with a one-frame GIF of their first frame
+)
+
+// ParseAnimatedGIF parses an --animated-gif value.
+func ParseAnimatedGIF(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", GIFKeep:
+		return GIFKeep, nil
+	case GIFPNG:
+		return GIFPNG, nil
+	case GIFStatic:
+		return GIFStatic, nil
+	default:
+		return "", fmt.Errorf("invalid animated GIF handling %q: must be keep, png or static", s)
+	}
+}
+
+// flattensGIFs reports whether animated GIFs are replaced by a still image.
+func (h *ImageHandler) flattensGIFs() bool {
+	return h.AnimatedGIF == GIFPNG || h.AnimatedGIF == GIFStatic
+}
+
+// flattenGIF returns the first frame of an animated GIF as PNG or as a
+// one-frame GIF, following h.AnimatedGIF, with its media type. Still GIFs
+// and GIFs that fail to decode are returned unchanged.
+func (h *ImageHandler) flattenGIF(data []byte) ([]byte, string, error) {
+	if !h.flattensGIFs() {
+		return data, "image/gif", nil
+	}
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil || len(g.Image) < 2 {
+		return data, "image/gif", nil
+	}
+
+	// The first frame may cover only part of the canvas
+	frame := g.Image[0]
+	canvas := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if canvas.Empty() {
+		canvas = frame.Bounds()
+	}
+
+	var buf bytes.Buffer
+	if h.AnimatedGIF == GIFPNG {
+		still := image.NewNRGBA(canvas)
+		draw.Draw(still, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		if err := png.Encode(&buf, still); err != nil {
+			return nil, "", fmt.Errorf("encoding PNG: %w", err)
+		}
+		return buf.Bytes(), "image/png", nil
+	}
+
+	still := image.NewPaletted(canvas, frame.Palette)
+	draw.Draw(still, frame.Bounds(), frame, frame.Bounds().Min, draw.Src)
+	if err := gif.Encode(&buf, still, nil); err != nil {
+		return nil, "", fmt.Errorf("encoding GIF: %w", err)
+	}
+	return buf.Bytes(), "image/gif", nil
+}
@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeGIF writes a GIF with the given number of 4x4 frames to dir.
+func writeGIF(t *testing.T, dir, name string, frames int) {
+	t.Helper()
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		frame.SetColorIndex(0, 0, uint8(i%2))
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, 10)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, anim))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644))
+}
+
+func TestParseAnimatedGIF(t *testing.T) {
+	for in, want := range map[string]string{"": GIFKeep, "KEEP": GIFKeep, "png": GIFPNG, "static": GIFStatic} {
+		got, err := ParseAnimatedGIF(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseAnimatedGIF("webm")
+	assert.Error(t, err)
+}
+
+func TestImageHandler_AnimatedGIF(t *testing.T) {
+	dir := t.TempDir()
+	writeGIF(t, dir, "spinner.gif", 3)
+	writeGIF(t, dir, "still.gif", 1)
+
+	t.Run("keep", func(t *testing.T) {
+		res, err := NewImageHandler().ProbeImage("spinner.gif", dir)
+		require.NoError(t, err)
+		assert.Equal(t, "image/gif", res.MediaType)
+		assert.Empty(t, res.Data)
+	})
+
+	t.Run("png", func(t *testing.T) {
+		res, err := (&ImageHandler{AnimatedGIF: GIFPNG}).ProbeImage("spinner.gif", dir)
+		require.NoError(t, err)
+		assert.Equal(t, "image/png", res.MediaType)
+		assert.Equal(t, "images/spinner.png", res.FileName)
+		cfg, err := png.DecodeConfig(bytes.NewReader(res.Data))
+		require.NoError(t, err)
+		assert.Equal(t, 4, cfg.Width)
+	})
+
+	t.Run("static", func(t *testing.T) {
+		res, err := (&ImageHandler{AnimatedGIF: GIFStatic}).ProbeImage("spinner.gif", dir)
+		require.NoError(t, err)
+		assert.Equal(t, "image/gif", res.MediaType)
+		g, err := gif.DecodeAll(bytes.NewReader(res.Data))
+		require.NoError(t, err)
+		assert.Len(t, g.Image, 1)
+	})
+
+	t.Run("still GIFs are unchanged", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(dir, "still.gif"))
+		require.NoError(t, err)
+		res, err := (&ImageHandler{AnimatedGIF: GIFPNG}).ProbeImage("still.gif", dir)
+		require.NoError(t, err)
+		assert.Equal(t, "image/gif", res.MediaType)
+		assert.Equal(t, data, res.Data)
+	})
+}
@@ -0,0 +1,64 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"image"
+	"image/color"
+)
+
+// grayscale returns img in grayscale with its contrast scaled by contrast
+// around mid-gray. Opaque images become 8-bit gray, which encodes smaller;
+// others keep their alpha channel.
+func grayscale(img image.Image, contrast float64) image.Image {
+	if contrast <= 0 {
+		contrast = 1
+	}
+	bounds := img.Bounds()
+	opaque := false
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		opaque = o.Opaque()
+	}
+
+	if opaque {
+		dst := image.NewGray(bounds)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				g := color.GrayModel.Convert(img.At(x, y)).(color.Gray)
+				dst.SetGray(x, y, color.Gray{Y: stretch(g.Y, contrast)})
+			}
+		}
+		return dst
+	}
+
+	dst := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			v := stretch(uint8((19595*uint32(c.R)+38470*uint32(c.G)+7471*uint32(c.B)+1<<15)>>16), contrast)
+			dst.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: c.A})
+		}
+	}
+	return dst
+}
+
+// stretch scales the gray level v by contrast around 128, clamped to 0-255.
+func stretch(v uint8, contrast float64) uint8 {
+	if contrast == 1 {
+		return v
+	}
+	s := (float64(v)-128)*contrast + 128.5
+	switch {
+	case s < 0:
+		return 0
+	case s > 255:
+		return 255
+	default:
+		return uint8(s)
+	}
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStretch(t *testing.T) {
+	assert.Equal(t, uint8(100), stretch(100, 1))
+	assert.Equal(t, uint8(72), stretch(100, 2))
+	assert.Equal(t, uint8(0), stretch(10, 3))
+	assert.Equal(t, uint8(255), stretch(250, 3))
+}
+
+func TestGrayscale(t *testing.T) {
+	opaque := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	opaque.Set(0, 0, color.RGBA{R: 255, A: 255})
+	opaque.Set(1, 0, color.RGBA{G: 255, A: 255})
+	gray, ok := grayscale(opaque, 1).(*image.Gray)
+	require.True(t, ok)
+	assert.Equal(t, uint8(76), gray.GrayAt(0, 0).Y)
+	assert.Equal(t, uint8(150), gray.GrayAt(1, 0).Y)
+
+	// Transparency survives
+	clear := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	clear.SetNRGBA(0, 0, color.NRGBA{R: 255, A: 128})
+	out, ok := grayscale(clear, 1).(*image.NRGBA)
+	require.True(t, ok)
+	assert.Equal(t, color.NRGBA{R: 76, G: 76, B: 76, A: 128}, out.NRGBAAt(0, 0))
+}
+
+func TestImageHandler_Grayscale(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 200
+		if i%4 == 3 {
+			img.Pix[i] = 255
+		}
+	}
+	var data bytes.Buffer
+	require.NoError(t, png.Encode(&data, img))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "photo.png"), data.Bytes(), 0o644))
+
+	res, err := (&ImageHandler{Grayscale: true}).ProbeImage("photo.png", dir)
+	require.NoError(t, err)
+	require.NotEmpty(t, res.Data)
+	decoded, err := png.Decode(bytes.NewReader(res.Data))
+	require.NoError(t, err)
+	assert.Equal(t, color.GrayModel, decoded.ColorModel())
+	assert.Less(t, len(res.Data), data.Len())
+}
@@ -0,0 +1,113 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// heicTimeout bounds each run of an external HEIC converter.
+const heicTimeout = 2 * time.Minute
+
+// heifBrands are the ftyp major brands of HEIC and other HEIF images.
+var heifBrands = []string{"heic", "heix", "heim", "heis", "hevc", "hevx", "mif1", "msf1"}
+
+// heicTool is an external command that converts a HEIC file to JPEG.
+type heicTool struct {
+	name string
+	args func(in, out string) []string
+}
+
+// heicTools are tried in order when no HEIC decoder is registered:
+// libheif's heif-convert, ImageMagick, and sips on macOS.
+var heicTools = []heicTool{
+	{"heif-convert", func(in, out string) []string { return []string{"-q", "90", in, out} }},
+	{"magick", func(in, out string) []string { return []string{in, "-quality", "90", out} }},
+	{"sips", func(in, out string) []string { return []string{"-s", "format", "jpeg", in, "--out", out} }},
+}
+
+// isHEIF reports whether data starts with an ISO-BMFF ftyp box whose major
+// brand names a HEIF image. AVIF, which shares the container, is checked
+// first by detectImageFormat.
+func isHEIF(data []byte) bool {
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+	for _, brand := range heifBrands {
+		if string(data[8:12]) == brand {
+			return true
+		}
+	}
+	return false
+}
+
+// convertHEIC converts HEIC data, as exported by iPhones, to JPEG. A
+// decoder registered in the image package is used if there is one;
+// otherwise the first external converter found on PATH. HEIC cannot be
+// embedded as-is, so the image is unsupported if neither is available.
+func (h *ImageHandler) convertHEIC(data []byte) ([]byte, string, error) {
+	out, mediaType, err := convertRegistered(data, "HEIC")
+	if !errors.Is(err, errNoDecoder) {
+		return out, mediaType, err
+	}
+
+	for _, tool := range heicTools {
+		bin, err := exec.LookPath(tool.name)
+		if err != nil {
+			continue
+		}
+		out, err := runHEICTool(bin, tool, data)
+		if err != nil {
+			return nil, "", err
+		}
+		return out, "image/jpeg", nil
+	}
+
+	names := make([]string, len(heicTools))
+	for i, tool := range heicTools {
+		names[i] = tool.name
+	}
+	return nil, "", fmt.Errorf("%w: HEIC needs one of %s on PATH", ErrUnsupportedImage, strings.Join(names, ", "))
+}
+
+// runHEICTool converts data to JPEG with the external tool at bin.
+func runHEICTool(bin string, tool heicTool, data []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "toepub-heic-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "image.heic"), filepath.Join(dir, "image.jpg")
+	if err := os.WriteFile(in, data, 0o600); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), heicTimeout)
+	defer cancel()
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, tool.args(in, out)...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %s", tool.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	jpg, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("%s wrote no output: %w", tool.name, err)
+	}
+	return jpg, nil
+}
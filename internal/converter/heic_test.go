@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHEIF(t *testing.T) {
+	assert.True(t, isHEIF(avifHeader("heic", "mif1")))
+	assert.True(t, isHEIF(avifHeader("mif1", "heic")))
+	assert.False(t, isHEIF(avifHeader("mp42", "isom")))
+	assert.False(t, isHEIF(pngHeader))
+
+	// AVIF shares the container and wins
+	mediaType, _ := NewImageHandler().detectImageFormat(avifHeader("mif1", "avif"), "photo.heic")
+	assert.Equal(t, "image/avif", mediaType)
+	mediaType, convert := NewImageHandler().detectImageFormat(avifHeader("heic", "mif1"), "photo.bin")
+	assert.Equal(t, "image/heic", mediaType)
+	assert.True(t, convert)
+}
+
+func TestImageHandler_HEIC(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake converter is a shell script")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "IMG_0001.HEIC"), avifHeader("heic", "mif1"), 0o644))
+
+	t.Run("no converter", func(t *testing.T) {
+		t.Setenv("PATH", t.TempDir())
+		_, err := NewImageHandler().ProcessImage("IMG_0001.HEIC", dir)
+		assert.ErrorIs(t, err, ErrUnsupportedImage)
+		assert.Contains(t, err.Error(), "heif-convert")
+	})
+
+	t.Run("external converter", func(t *testing.T) {
+		jpeg := append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, make([]byte, 16)...)
+		bin := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "converted.jpg"), jpeg, 0o644))
+		script := "#!/bin/sh\ncp \"" + filepath.Join(bin, "converted.jpg") + "\" \"$4\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(bin, "heif-convert"), []byte(script), 0o755))
+		t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		res, err := NewImageHandler().ProcessImage("IMG_0001.HEIC", dir)
+		require.NoError(t, err)
+		assert.Equal(t, "image/jpeg", res.MediaType)
+		assert.Equal(t, "images/IMG_0001.jpg", res.FileName)
+		assert.Equal(t, jpeg, res.Data)
+	})
+}
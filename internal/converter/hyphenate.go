@@ -0,0 +1,283 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// softHyphen marks a place where a reading system may break a word,
+// shown as a hyphen only when the line breaks there.
+const softHyphen = "\u00ad"
+
+// minHyphenatedWord is the length in letters below which words are left
+// whole; breaking shorter words gains little on a justified line.
+const minHyphenatedWord = 6
+
+// hyphenator finds hyphenation points with Liang's algorithm, as used by
+// TeX: patterns give the letters around a point a value, and points where
+// the highest value is odd are breaks.
+type hyphenator struct {
+	patterns   map[string][]uint8 // Letters to the value before each letter and after the last
+	exceptions map[string][]int   // Lowercase words to their break positions
+	maxLen     int                // Letters in the longest pattern
+	leftMin    int                // Letters kept before the first break
+	rightMin   int                // Letters kept after the last break
+}
+
+// newHyphenator compiles patterns in TeX notation, such as "1tion" or
+// "s2s1ing". Patterns containing hyphens, such as "ta-ble", are exception
+// words hyphenated exactly as given.
+func newHyphenator(patterns []string, leftMin, rightMin int) *hyphenator {
+	h := &hyphenator{
+		patterns:   make(map[string][]uint8, len(patterns)),
+		exceptions: make(map[string][]int),
+		leftMin:    leftMin,
+		rightMin:   rightMin,
+	}
+	for _, p := range patterns {
+		if strings.Contains(p, "-") {
+			h.addException(p)
+			continue
+		}
+		var letters []rune
+		values := []uint8{0}
+		for _, r := range strings.ToLower(p) {
+			if r >= '0' && r <= '9' {
+				values[len(values)-1] = uint8(r - '0')
+				continue
+			}
+			letters = append(letters, r)
+			values = append(values, 0)
+		}
+		if len(letters) == 0 {
+			continue
+		}
+		// Patterns for the same letters, as generated rules may give, combine
+		if prev, ok := h.patterns[string(letters)]; ok {
+			for i := range values {
+				values[i] = max(values[i], prev[i])
+			}
+		}
+		h.patterns[string(letters)] = values
+		h.maxLen = max(h.maxLen, len(letters))
+	}
+	return h
+}
+
+// addException records the breaks of an exception word such as "ta-ble".
+func (h *hyphenator) addException(word string) {
+	var breaks []int
+	n := 0
+	for _, r := range strings.ToLower(word) {
+		if r == '-' {
+			breaks = append(breaks, n)
+			continue
+		}
+		n++
+	}
+	h.exceptions[strings.ReplaceAll(strings.ToLower(word), "-", "")] = breaks
+}
+
+// points returns the rune offsets in word before which it may break.
+func (h *hyphenator) points(word string) []int {
+	runes := []rune(word)
+	lower := []rune(strings.ToLower(word))
+	if len(lower) != len(runes) || len(runes) < h.leftMin+h.rightMin {
+		return nil
+	}
+	if breaks, ok := h.exceptions[string(lower)]; ok {
+		return breaks
+	}
+
+	// The word is padded with dots, which patterns use to match its ends;
+	// values[i] belongs to the point before padded[i]
+	padded := append(append([]rune{'.'}, lower...), '.')
+	values := make([]uint8, len(padded)+1)
+	for i := range padded {
+		for j := i + 1; j <= len(padded) && j-i <= h.maxLen; j++ {
+			pattern, ok := h.patterns[string(padded[i:j])]
+			if !ok {
+				continue
+			}
+			for k, v := range pattern {
+				values[i+k] = max(values[i+k], v)
+			}
+		}
+	}
+
+	var breaks []int
+	for p := h.leftMin; p <= len(runes)-h.rightMin; p++ {
+		if values[p+1]%2 == 1 {
+			breaks = append(breaks, p)
+		}
+	}
+	return breaks
+}
+
+// hyphenate inserts soft hyphens at the hyphenation points of word.
+func (h *hyphenator) hyphenate(word string) string {
+	breaks := h.points(word)
+	if len(breaks) == 0 {
+		return word
+	}
+	var buf strings.Builder
+	for i, r := range []rune(word) {
+		if slices.Contains(breaks, i) {
+			buf.WriteString(softHyphen)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// parseHyphenationPatterns reads a hyphenation pattern file: either TeX
+// source with \patterns{...} and \hyphenation{...} groups, or the plain
+// lists of the hyph-utf8 project with one pattern or exception per line.
+// Percent signs start comments.
+func parseHyphenationPatterns(text string) []string {
+	var patterns []string
+	for line := range strings.Lines(text) {
+		if i := strings.IndexByte(line, '%'); i >= 0 {
+			line = line[:i]
+		}
+		for _, field := range strings.Fields(line) {
+			// Strip group markers around the patterns
+			if i := strings.IndexByte(field, '{'); i >= 0 {
+				field = field[i+1:]
+			}
+			field = strings.TrimRight(field, "}")
+			if field == "" || strings.HasPrefix(field, `\`) {
+				continue
+			}
+			patterns = append(patterns, field)
+		}
+	}
+	return patterns
+}
+
+// loadHyphenationPatterns compiles the hyphenation pattern file at path,
+// returning nil if path is empty.
+func loadHyphenationPatterns(path string) (*hyphenator, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading hyphenation patterns: %w", err)
+	}
+	patterns := parseHyphenationPatterns(string(data))
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("loading hyphenation patterns: no patterns in %s", path)
+	}
+	return newHyphenator(patterns, 2, 3), nil
+}
+
+// hyphenatedWordRe matches words and the character references that must
+// be passed over whole.
+var hyphenatedWordRe = regexp.MustCompile(`&#?\w+;|\p{L}[\p{L}\p{M}]*`)
+
+// unhyphenatedElements are elements whose text is never hyphenated:
+// verbatim text, and headings, whose text also becomes navigation labels.
+var unhyphenatedElements = map[string]bool{
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"title": true, "abbr": true,
+}
+
+// applyHyphenation inserts soft hyphens into the long words of all
+// chapters, using the dictionary of each chapter's language or the book
+// language. Chapters in the book language use custom when it is set. The
+// languages without a dictionary are returned; their chapters are left
+// unchanged.
+func applyHyphenation(doc *model.Document, custom *hyphenator) []string {
+	var missing []string
+	for i, chapter := range doc.Chapters {
+		lang := chapter.Language
+		if lang == "" {
+			lang = doc.Metadata.Language
+		}
+		h := custom
+		if h == nil || !strings.EqualFold(lang, doc.Metadata.Language) {
+			h = hyphenationDictionary(lang)
+		}
+		if h == nil {
+			if !slices.Contains(missing, lang) {
+				missing = append(missing, lang)
+			}
+			continue
+		}
+		doc.Chapters[i].Content = hyphenateMarkup(chapter.Content, h)
+	}
+	return missing
+}
+
+// hyphenateMarkup hyphenates the text between tags of an XHTML fragment.
+func hyphenateMarkup(content string, h *hyphenator) string {
+	var buf strings.Builder
+	skipped := 0
+	last := 0
+
+	write := func(text string) {
+		if skipped > 0 {
+			buf.WriteString(text)
+			return
+		}
+		buf.WriteString(hyphenatedWordRe.ReplaceAllStringFunc(text, func(word string) string {
+			if strings.HasPrefix(word, "&") || len([]rune(word)) < minHyphenatedWord || isUpperWord(word) {
+				return word
+			}
+			return h.hyphenate(word)
+		}))
+	}
+
+	for _, loc := range markupTagRe.FindAllStringSubmatchIndex(content, -1) {
+		write(content[last:loc[0]])
+		buf.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+
+		if loc[4] < 0 {
+			continue // Comment or declaration
+		}
+		name := strings.ToLower(content[loc[4]:loc[5]])
+		selfClosing := strings.HasSuffix(content[loc[0]:loc[1]], "/>")
+		if (!verbatimElements[name] && !unhyphenatedElements[name]) || selfClosing {
+			continue
+		}
+		if loc[3] > loc[2] {
+			if skipped > 0 {
+				skipped--
+			}
+		} else {
+			skipped++
+		}
+	}
+	write(content[last:])
+	return buf.String()
+}
+
+// isUpperWord reports whether a word is written in capitals, such as an
+// acronym, which is not hyphenated.
+func isUpperWord(word string) bool {
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// shown replaces soft hyphens with visible hyphens.
+func shown(s string) string {
+	return strings.ReplaceAll(s, softHyphen, "-")
+}
+
+func TestHyphenationDictionary(t *testing.T) {
+	tests := []struct {
+		lang, word, want string
+	}{
+		{"en-US", "settlement", "set-tle-ment"},
+		{"en", "communication", "com-munica-tion"},
+		{"en", "stopped", "stopped"},
+		{"en", "carelessness", "care-less-ness"},
+		{"de", "Wasserflasche", "Was-ser-fla-sche"},
+		{"de", "Vereinigung", "Ver-ei-ni-gung"},
+		{"fr", "bibliothèque", "bi-blio-thè-que"},
+		{"es", "muchacho", "mu-cha-cho"},
+		{"it", "costruire", "co-strui-re"},
+		{"pt-BR", "trabalho", "tra-ba-lho"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.word, func(t *testing.T) {
+			h := hyphenationDictionary(tt.lang)
+			require.NotNil(t, h)
+			assert.Equal(t, tt.want, shown(h.hyphenate(tt.word)))
+		})
+	}
+	assert.Nil(t, hyphenationDictionary("vi"))
+}
+
+func TestHyphenateMarkup(t *testing.T) {
+	en := hyphenationDictionary("en")
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"text", `<p>A settlement of <em>happiness</em>.</p>`, `<p>A set-tle-ment of <em>hap-pi-ness</em>.</p>`},
+		{"short and capitalized words", `<p>Little NATIONALITY kitten</p>`, `<p>Lit-tle NATIONALITY kit-ten</p>`},
+		{"attributes and entities untouched", `<a title="settlement">x&nbsp;&amp;</a>`, `<a title="settlement">x&nbsp;&amp;</a>`},
+		{"headings and code untouched", `<h2>Settlement</h2><p><code>settlement</code> settlement</p>`, `<h2>Settlement</h2><p><code>settlement</code> set-tle-ment</p>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shown(hyphenateMarkup(tt.in, en)))
+		})
+	}
+}
+
+func TestApplyHyphenation_CustomPatterns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hyph-en.tex")
+	require.NoError(t, os.WriteFile(path, []byte(`% Test patterns
+\patterns{
+1na 1la 1ti
+}
+\hyphenation{ pla-net-arium }
+`), 0o644))
+
+	custom, err := loadHyphenationPatterns(path)
+	require.NoError(t, err)
+
+	doc := model.NewDocument()
+	doc.Metadata.Language = "en"
+	doc.AddChapter(model.Chapter{Content: "<p>banana planetarium</p>"})
+	doc.AddChapter(model.Chapter{Language: "de", Content: "<p>Wasserflasche</p>"})
+	doc.AddChapter(model.Chapter{Language: "vi", Content: "<p>Nguyễn</p>"})
+
+	missing := applyHyphenation(doc, custom)
+	assert.Equal(t, []string{"vi"}, missing)
+	assert.Equal(t, "<p>ba-nana pla-net-arium</p>", shown(doc.Chapters[0].Content))
+	assert.Equal(t, "<p>Was-ser-fla-sche</p>", shown(doc.Chapters[1].Content))
+	assert.Equal(t, "<p>Nguyễn</p>", doc.Chapters[2].Content)
+
+	_, err = loadHyphenationPatterns(filepath.Join(t.TempDir(), "missing.tex"))
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
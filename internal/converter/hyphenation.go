@@ -0,0 +1,148 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"strings"
+	"sync"
+)
+
+// The built-in dictionaries are conservative: a missed break only loosens
+// a line, while a wrong one shows up as a misplaced hyphen. Complete TeX
+// patterns can be loaded with Options.Hyphenation.
+
+// englishPatterns break English words at doubled consonants and before
+// common suffixes, the points that are right in nearly every word.
+var englishPatterns = []string{
+	// Between doubled consonants before a vowel: let-ter, pas-sion
+	"b1ba", "b1be", "b1bi", "b1bo", "b1bu", "b1by",
+	"c1ca", "c1ce", "c1ci", "c1co", "c1cu",
+	"d1da", "d1de", "d1di", "d1do", "d1du",
+	"f1fa", "f1fe", "f1fi", "f1fo", "f1fu",
+	"g1ga", "g1ge", "g1gi", "g1go", "g1gu",
+	"l1la", "l1le", "l1li", "l1lo", "l1lu", "l1ly",
+	"m1ma", "m1me", "m1mi", "m1mo", "m1mu",
+	"n1na", "n1ne", "n1ni", "n1no", "n1nu",
+	"p1pa", "p1pe", "p1pi", "p1po", "p1pu",
+	"r1ra", "r1re", "r1ri", "r1ro", "r1ru",
+	"s1sa", "s1se", "s1si", "s1so", "s1su",
+	"t1ta", "t1te", "t1ti", "t1to", "t1tu",
+	"z1za", "z1ze", "z1zi", "z1zo",
+	"b1ble", "d1dle", "g1gle", "p1ple", "t1tle", "z1zle",
+
+	// The -ed of one-syllable past tenses: stopped, called
+	"b2bed.", "g2ged.", "l2led.", "m2med.", "n2ned.", "p2ped.", "r2red.", "s2sed.", "z2zed.", "f2fed.", "ck2ed.",
+
+	// Doubled consonants ending a word stem: spell-ing, miss-ing
+	"l2l1ing", "s2s1ing", "f2f1ing", "z2z1ing",
+	"ck1a", "ck1e", "ck1i", "ck1o", "ck1u",
+
+	// Suffixes
+	"1tion", "1sion", "1cion", "1cial", "1tial", "1cian", "1cious", "1tious", "1tient",
+	"1ment", "1ness", "1less", "1ful", "1ship", "1hood", "1ward", "1ture", "1ity",
+
+	// Prefixes
+	".under1", ".over1", ".counter1",
+}
+
+// syllableRules describe a language whose words break between syllables:
+// before a consonant followed by a vowel, and between consonants except
+// inside the clusters that begin a syllable.
+type syllableRules struct {
+	vowels     string
+	consonants string
+	onsets     []string // Consonant clusters kept together at the start of a syllable
+	extra      []string // Further patterns, such as for prefixes
+	leftMin    int
+	rightMin   int
+}
+
+// patterns returns the rules as patterns for Liang's algorithm.
+func (r syllableRules) patterns() []string {
+	var patterns []string
+	for _, c := range r.consonants {
+		for _, v := range r.vowels {
+			patterns = append(patterns, "1"+string(c)+string(v))
+		}
+	}
+	for _, onset := range r.onsets {
+		letters := strings.Split(onset, "")
+		patterns = append(patterns, "1"+strings.Join(letters, "2"))
+	}
+	return append(patterns, r.extra...)
+}
+
+// hyphenator compiles the rules.
+func (r syllableRules) hyphenator() *hyphenator {
+	return newHyphenator(r.patterns(), r.leftMin, r.rightMin)
+}
+
+// Syllable rules of the built-in dictionaries other than English.
+var (
+	germanRules = syllableRules{
+		vowels:     "aeiouyäöü",
+		consonants: "bcdfghjklmnpqrstvwxzß",
+		onsets:     []string{"bl", "br", "dr", "fl", "fr", "gl", "gr", "kl", "kn", "kr", "pl", "pr", "tr", "ch", "ck", "sch", "ph", "th"},
+		extra:      []string{".ve2r1", ".zer1", ".un1", ".er1"},
+		leftMin:    2,
+		rightMin:   2,
+	}
+	frenchRules = syllableRules{
+		vowels:     "aeiouyàâäéèêëîïôöùûüÿæœ",
+		consonants: "bcçdfghjklmnpqrstvwxz",
+		onsets:     []string{"bl", "br", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr", "tr", "vr", "ch", "ph", "th", "gn"},
+		leftMin:    2,
+		rightMin:   3,
+	}
+	spanishRules = syllableRules{
+		vowels:     "aeiouáéíóúü",
+		consonants: "bcdfghjklmnñpqrstvwxyz",
+		onsets:     []string{"bl", "br", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr", "tr", "ch", "ll", "rr"},
+		leftMin:    2,
+		rightMin:   2,
+	}
+	italianRules = syllableRules{
+		vowels:     "aeiouàèéìíòóùú",
+		consonants: "bcdfghjklmnpqrstvwxyz",
+		onsets: []string{"bl", "br", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr", "tr", "vr", "ch", "gh", "gn",
+			"sb", "sc", "sd", "sf", "sg", "sl", "sm", "sn", "sp", "sq", "sr", "st", "sv"},
+		leftMin:  2,
+		rightMin: 2,
+	}
+	portugueseRules = syllableRules{
+		vowels:     "aeiouáàâãéêíóôõúü",
+		consonants: "bcçdfghjklmnpqrstvwxyz",
+		onsets:     []string{"bl", "br", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr", "tr", "vr", "ch", "lh", "nh"},
+		leftMin:    2,
+		rightMin:   3,
+	}
+)
+
+// hyphenationDictionaries maps primary language subtags to their built-in
+// dictionaries, compiled on first use.
+var hyphenationDictionaries = map[string]func() *hyphenator{
+	"en": sync.OnceValue(func() *hyphenator { return newHyphenator(englishPatterns, 2, 3) }),
+	"de": sync.OnceValue(germanRules.hyphenator),
+	"fr": sync.OnceValue(frenchRules.hyphenator),
+	"es": sync.OnceValue(spanishRules.hyphenator),
+	"it": sync.OnceValue(italianRules.hyphenator),
+	"pt": sync.OnceValue(portugueseRules.hyphenator),
+}
+
+// hyphenationDictionary returns the built-in dictionary for a BCP 47
+// language tag, or nil if the language has none.
+func hyphenationDictionary(lang string) *hyphenator {
+	primary := strings.ToLower(lang)
+	if i := strings.IndexAny(primary, "-_"); i >= 0 {
+		primary = primary[:i]
+	}
+	if dictionary, ok := hyphenationDictionaries[primary]; ok {
+		return dictionary()
+	}
+	return nil
+}
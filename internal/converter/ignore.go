@@ -0,0 +1,129 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// ignoreFileName is the file in an input directory listing entries to skip.
+const ignoreFileName = ".toepubignore"
+
+// defaultIgnoreRules skip hidden and dependency directories, which never
+// hold chapters. An ignore file can re-include them with "!".
+var defaultIgnoreRules = []string{".*/", "node_modules/"}
+
+// ignoreRule is one line of an ignore file.
+type ignoreRule struct {
+	pattern  string
+	negate   bool // "!pattern" re-includes a previously ignored entry
+	dirOnly  bool // "pattern/" matches directories only
+	anchored bool // patterns with a slash match the path from the root
+}
+
+// ignoreRules is an ordered list of rules; the last matching rule wins.
+type ignoreRules []ignoreRule
+
+// parseIgnoreRules parses gitignore-style lines. Blank lines and lines
+// starting with "#" are skipped.
+func parseIgnoreRules(lines []string) ignoreRules {
+	var rules ignoreRules
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		rule.pattern = line
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// loadIgnoreRules returns the default rules followed by the rules in the
+// ignore file, if it exists.
+func loadIgnoreRules(file inputFile) (ignoreRules, error) {
+	rules := parseIgnoreRules(defaultIgnoreRules)
+
+	content, err := file.read()
+	if errors.Is(err, fs.ErrNotExist) {
+		return rules, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", file.Path, err)
+	}
+	return append(rules, parseIgnoreRules(strings.Split(string(content), "\n"))...), nil
+}
+
+// ignored reports whether the slash-separated path rel, relative to the
+// input directory, is excluded.
+func (r ignoreRules) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range r {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		name := rel
+		if !rule.anchored {
+			name = path.Base(rel)
+		}
+		if matchGlob(rule.pattern, name) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matchGlob reports whether a slash-separated name matches pattern. Each
+// segment is matched with path.Match, and a "**" segment matches any
+// number of segments, including none.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+// matchSegments matches pattern segments against name segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
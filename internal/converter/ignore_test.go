@@ -0,0 +1,97 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"drafts/**", "drafts/a.md", true},
+		{"drafts/**", "drafts/old/b.md", true},
+		{"drafts/**", "notes/a.md", false},
+		{"**/README.md", "README.md", true},
+		{"**/README.md", "a/b/README.md", true},
+		{"a/**/z.md", "a/z.md", true},
+		{"a/**/z.md", "a/b/c/z.md", true},
+		{"a/*.md", "a/b/c.md", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestExpandDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"01-intro.md",
+		"notes.txt",
+		"README.md",
+		"part1/02-setup.md",
+		"part1/drafts/wip.md",
+		"part2/03-usage.html",
+		"node_modules/pkg/readme.md",
+		".git/description.md",
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte("# x\n"), 0o644))
+	}
+	ignore := "# meta files\nREADME.md\ndrafts/\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ignoreFileName), []byte(ignore), 0o644))
+
+	c := New()
+	rel := func(files []inputFile) []string {
+		out := make([]string, len(files))
+		for i, f := range files {
+			r, err := filepath.Rel(dir, f.Path)
+			require.NoError(t, err)
+			out[i] = filepath.ToSlash(r)
+		}
+		return out
+	}
+
+	files, err := c.expandDirectory(diskTree(dir), false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01-intro.md"}, rel(files))
+
+	files, err = c.expandDirectory(diskTree(dir), true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01-intro.md", "part1/02-setup.md", "part2/03-usage.html"}, rel(files))
+}
+
+func TestExpandInputs_Exclude(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"README.md":         "# Readme\n",
+		"01-intro.md":       "# Intro\n",
+		"drafts/idea.md":    "# Idea\n",
+		"drafts/old/old.md": "# Old\n",
+		"part/02-body.md":   "# Body\n",
+	})
+
+	opts := Options{Recursive: true, Exclude: []string{"README.md", "drafts/**"}}
+	files, err := New().expandInputs([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []inputFile{
+		{Path: filepath.Join(dir, "01-intro.md")},
+		{Path: filepath.Join(dir, "part", "02-body.md")},
+	}, files)
+
+	// Explicit files are filtered too, such as those from a shell glob
+	files, err = New().expandInputs([]string{filepath.Join(dir, "README.md"), filepath.Join(dir, "01-intro.md")}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []inputFile{{Path: filepath.Join(dir, "01-intro.md")}}, files)
+}
@@ -15,7 +15,10 @@ import (
 	"image/gif"
 	"image/jpeg"
 	"image/png"
+	"io"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -31,7 +34,14 @@ var (
 )
 
 // ImageHandler processes images for EPUB embedding.
-type ImageHandler struct{}
+type ImageHandler struct {
+	KeepWebP  bool      // Embed WebP images as-is instead of converting them to PNG
+	MaxSize   ImageSize // Downscale larger JPEG and PNG images to fit; zero keeps their size
+	Grayscale bool      // Convert JPEG and PNG images to grayscale, for e-ink devices
+	Contrast  float64   // Contrast factor for grayscale images; 0 or 1 leaves it unchanged
+
+	AnimatedGIF string // GIFKeep (default), GIFPNG, or GIFStatic
+}
 
 // NewImageHandler creates a new image handler.
 func NewImageHandler() *ImageHandler {
@@ -52,20 +62,80 @@ func (h *ImageHandler) ProcessImage(path string, basePath string) (*model.Resour
 		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, path)
 	}
 
+	return h.loadImage(data, path)
+}
+
+// FetchImage reads and validates an image through fetcher.
+func (h *ImageHandler) FetchImage(fetcher ResourceFetcher, ref string) (*model.Resource, error) {
+	data, err := fetcher.Fetch(ref)
+	if errors.Is(err, ErrFileNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, ref)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Name URLs after their path, without the query
+	name := ref
+	if refScheme(ref) != "" {
+		name = "image"
+		if u, err := url.Parse(ref); err == nil && path.Base(u.Path) != "." && path.Base(u.Path) != "/" {
+			name = path.Base(u.Path)
+		}
+	}
+	return h.loadImage(data, name)
+}
+
+// loadImage builds an image resource from data read from path.
+func (h *ImageHandler) loadImage(data []byte, path string) (*model.Resource, error) {
 	// Detect and validate format
 	mediaType, needsConversion := h.detectImageFormat(data, path)
+	needsConversion = needsConversion && h.converts(mediaType)
 	if mediaType == "" {
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, path)
 	}
 
-	// Convert WebP to PNG if needed
-	if needsConversion {
+	// Convert WebP to PNG, HEIC to JPEG, and AVIF to JPEG or PNG when a
+	// decoder is registered; otherwise AVIF is embedded as-is
+	if needsConversion && mediaType == "image/webp" {
 		var convertErr error
 		data, convertErr = h.convertWebPToPNG(data)
 		if convertErr != nil {
 			return nil, fmt.Errorf("converting WebP to PNG: %w", convertErr)
 		}
 		mediaType = "image/png"
+	} else if needsConversion && mediaType == "image/heic" {
+		var convertErr error
+		data, mediaType, convertErr = h.convertHEIC(data)
+		if convertErr != nil {
+			return nil, fmt.Errorf("converting HEIC %s: %w", path, convertErr)
+		}
+	} else if needsConversion && mediaType == "image/avif" {
+		converted, convertedType, convertErr := h.convertAVIF(data)
+		switch {
+		case errors.Is(convertErr, errNoDecoder):
+			needsConversion = false
+		case convertErr != nil:
+			return nil, fmt.Errorf("converting AVIF: %w", convertErr)
+		default:
+			data, mediaType = converted, convertedType
+		}
+	}
+
+	// Replace animated GIFs with their first frame if requested
+	if mediaType == "image/gif" && h.flattensGIFs() {
+		var flattenErr error
+		data, mediaType, flattenErr = h.flattenGIF(data)
+		if flattenErr != nil {
+			return nil, fmt.Errorf("flattening GIF %s: %w", path, flattenErr)
+		}
+		needsConversion = mediaType != "image/gif"
+	}
+
+	// Downscale oversized images and convert to grayscale if requested
+	data, err := h.adjust(data, mediaType)
+	if err != nil {
+		return nil, fmt.Errorf("adjusting %s: %w", path, err)
 	}
 
 	// Generate resource ID and filename
@@ -75,7 +145,7 @@ func (h *ImageHandler) ProcessImage(path string, basePath string) (*model.Resour
 
 	// Update extension if converted
 	if needsConversion {
-		baseName = name + ".png"
+		baseName = name + extensionFromMediaType(mediaType)
 	}
 
 	resource := &model.Resource{
@@ -88,6 +158,54 @@ func (h *ImageHandler) ProcessImage(path string, basePath string) (*model.Resour
 	return resource, nil
 }
 
+// ProbeImage validates an image file by reading only its header.
+// Images that can be embedded as-is are returned without data and with
+// SourcePath set, so the EPUB builder can stream them from disk. Images
+// that need conversion are fully loaded via ProcessImage.
+func (h *ImageHandler) ProbeImage(path string, basePath string) (*model.Resource, error) {
+	fullPath := path
+	if !filepath.IsAbs(path) {
+		fullPath = filepath.Join(basePath, path)
+	}
+
+	f, err := os.Open(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrImageNotFound, path)
+	}
+	defer f.Close()
+
+	header := make([]byte, 1024)
+	n, err := io.ReadFull(f, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, path)
+	}
+
+	mediaType, needsConversion := h.detectImageFormat(header[:n], path)
+	needsConversion = needsConversion && h.converts(mediaType)
+	if mediaType == "" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedImage, path)
+	}
+	if needsConversion {
+		return h.ProcessImage(path, basePath)
+	}
+	if mediaType == "image/gif" && h.flattensGIFs() {
+		return h.ProcessImage(path, basePath)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err == nil && h.needsAdjust(f, mediaType) {
+		return h.ProcessImage(path, basePath)
+	}
+
+	baseName := filepath.Base(path)
+	name := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+
+	return &model.Resource{
+		ID:         "img-" + sanitizeID(name),
+		FileName:   "images/" + baseName,
+		MediaType:  mediaType,
+		SourcePath: fullPath,
+	}, nil
+}
+
 // detectImageFormat determines the image MIME type from content and filename.
 // Returns media type and whether conversion is needed.
 func (h *ImageHandler) detectImageFormat(data []byte, filename string) (string, bool) {
@@ -109,10 +227,21 @@ func (h *ImageHandler) detectImageFormat(data []byte, filename string) (string,
 		if len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP" {
 			return "image/webp", true // Needs conversion
 		}
+		// AVIF: ....ftypavif
+		if isAVIF(data) {
+			return "image/avif", true // Converted if a decoder is registered
+		}
+		// HEIC/HEIF: ....ftypheic and related brands
+		if isHEIF(data) {
+			return "image/heic", true // Needs conversion
+		}
 	}
 
 	// SVG detection by content (starts with <?xml or <svg)
 	content := strings.TrimSpace(string(data[:min(len(data), 1024)]))
+	if content == "" {
+		return "", false
+	}
 	if strings.HasPrefix(content, "<?xml") || strings.HasPrefix(content, "<svg") ||
 		strings.Contains(content[:min(len(content), 256)], "<svg") {
 		return "image/svg+xml", false
@@ -131,6 +260,10 @@ func (h *ImageHandler) detectImageFormat(data []byte, filename string) (string,
 		return "image/svg+xml", false
 	case ".webp":
 		return "image/webp", true
+	case ".avif":
+		return "image/avif", true
+	case ".heic", ".heif":
+		return "image/heic", true
 	default:
 		return "", false
 	}
@@ -153,6 +286,48 @@ func (h *ImageHandler) convertWebPToPNG(data []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// converts reports whether images of mediaType are converted rather than
+// embedded as-is.
+func (h *ImageHandler) converts(mediaType string) bool {
+	return mediaType != "image/webp" || !h.KeepWebP
+}
+
+// PNGFallback returns a PNG copy of a WebP or AVIF resource, read from its
+// data or source path, for reading systems that cannot show the format.
+func (h *ImageHandler) PNGFallback(res model.Resource) (*model.Resource, error) {
+	data := res.Data
+	if len(data) == 0 {
+		var err error
+		if data, err = os.ReadFile(res.SourcePath); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrImageNotFound, res.SourcePath)
+		}
+	}
+
+	var out []byte
+	if res.MediaType == "image/avif" {
+		img, err := decodeRegistered(data, "AVIF")
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("encoding PNG: %w", err)
+		}
+		out = buf.Bytes()
+	} else {
+		var err error
+		if out, err = h.convertWebPToPNG(data); err != nil {
+			return nil, fmt.Errorf("converting WebP to PNG: %w", err)
+		}
+	}
+	return &model.Resource{
+		ID:        res.ID + "-png",
+		FileName:  strings.TrimSuffix(res.FileName, path.Ext(res.FileName)) + ".png",
+		MediaType: "image/png",
+		Data:      out,
+	}, nil
+}
+
 // ValidateImage checks if image data is valid.
 func (h *ImageHandler) ValidateImage(data []byte) error {
 	_, _, err := image.Decode(bytes.NewReader(data))
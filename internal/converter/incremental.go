@@ -0,0 +1,82 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// buildCache keeps what a conversion with Options.Incremental produced
+// for the next one: parsed input files, stored as document IR so each
+// conversion gets its own copy, and rendered content documents. Entries a
+// conversion does not use are dropped when it finishes, so the cache
+// holds about one book.
+type buildCache struct {
+	mu       sync.Mutex
+	docs     map[string][]byte // Parse key to document IR
+	used     map[string]bool   // Parse keys used since the last sweep
+	chapters *epub.ChapterCache
+}
+
+// newBuildCache returns an empty build cache.
+func newBuildCache() *buildCache {
+	return &buildCache{
+		docs:     make(map[string][]byte),
+		used:     make(map[string]bool),
+		chapters: epub.NewChapterCache(),
+	}
+}
+
+// load returns a copy of the document stored under key.
+func (bc *buildCache) load(key string) (*model.Document, bool) {
+	bc.mu.Lock()
+	data, ok := bc.docs[key]
+	if ok {
+		bc.used[key] = true
+	}
+	bc.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	doc, err := ReadIR(bytes.NewReader(data))
+	if err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+// store saves doc under key.
+func (bc *buildCache) store(key string, doc *model.Document) error {
+	var buf bytes.Buffer
+	if err := WriteIR(&buf, doc); err != nil {
+		return err
+	}
+
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	bc.docs[key] = buf.Bytes()
+	bc.used[key] = true
+	return nil
+}
+
+// sweep drops the documents not used since the last sweep.
+func (bc *buildCache) sweep() {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	for key := range bc.docs {
+		if !bc.used[key] {
+			delete(bc.docs, key)
+		}
+	}
+	bc.used = make(map[string]bool)
+}
@@ -0,0 +1,80 @@
+package converter
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// readChapters returns the content documents of the EPUB at path.
+func readChapters(t *testing.T, path string) map[string]string {
+	t.Helper()
+	archive, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	chapters := make(map[string]string)
+	for _, f := range archive.File {
+		if filepath.Ext(f.Name) != ".xhtml" || f.Name == "OEBPS/nav.xhtml" {
+			continue
+		}
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		chapters[f.Name] = string(data)
+	}
+	return chapters
+}
+
+func TestConverter_Convert_Incremental(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01-one.md":   "# One\n\nFirst.\n",
+		"02-two.md":   "# Two\n\nSecond.\n",
+		"03-three.md": "# Three\n\nThird.\n",
+	})
+
+	counter := &countingParser{Parser: parser.NewMarkdownParser()}
+	conv := New()
+	conv.RegisterParser(parser.FormatMarkdown, counter)
+	opts := Options{OutputPath: filepath.Join(dir, "book.epub"), Incremental: true}
+
+	_, err := conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	first := readChapters(t, opts.OutputPath)
+	assert.Equal(t, int32(3), counter.calls.Load())
+
+	_, err = conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), counter.calls.Load(), "unchanged files are not parsed again")
+	assert.Equal(t, first, readChapters(t, opts.OutputPath))
+
+	// Only the changed file is parsed again, and the book shows the change
+	writeFiles(t, dir, map[string]string{"02-two.md": "# Two\n\nRevised.\n"})
+	_, err = conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), counter.calls.Load())
+	revised := readChapters(t, opts.OutputPath)
+	assert.Contains(t, revised["OEBPS/content/chapter-002.xhtml"], "Revised.")
+	assert.Equal(t, first["OEBPS/content/chapter-003.xhtml"], revised["OEBPS/content/chapter-003.xhtml"])
+
+	// Files removed from the book are forgotten
+	require.NoError(t, os.Remove(filepath.Join(dir, "03-three.md")))
+	_, err = conv.Convert([]string{dir}, opts)
+	require.NoError(t, err)
+	assert.Len(t, conv.builds.docs, 2)
+
+	// Without the option nothing is kept
+	_, err = conv.Convert([]string{dir}, Options{OutputPath: opts.OutputPath})
+	require.NoError(t, err)
+	assert.Equal(t, int32(6), counter.calls.Load())
+}
@@ -0,0 +1,187 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// irVersion is the version of the document IR file format. ReadIR rejects
+// files written by other versions.
+const irVersion = 1
+
+// ErrInvalidIR is returned for document IR files that cannot be read.
+var ErrInvalidIR = errors.New("invalid document IR")
+
+// irFile is the JSON layout of a document IR file.
+type irFile struct {
+	Version  int             `json:"version"`
+	Document *model.Document `json:"document"`
+}
+
+// WriteIR writes the document as indented JSON, the intermediate
+// representation between the parse and build phases.
+func WriteIR(w io.Writer, doc *model.Document) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(irFile{Version: irVersion, Document: doc})
+}
+
+// ReadIR reads a document written by WriteIR.
+func ReadIR(r io.Reader) (*model.Document, error) {
+	var ir irFile
+	if err := json.NewDecoder(r).Decode(&ir); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIR, err)
+	}
+	if ir.Version != irVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d (expected %d)", ErrInvalidIR, ir.Version, irVersion)
+	}
+	if ir.Document == nil {
+		return nil, fmt.Errorf("%w: no document", ErrInvalidIR)
+	}
+	return ir.Document, nil
+}
+
+// emitIR writes the parsed document to path instead of building an EPUB.
+// Resource source paths are made absolute, so the file can be built from
+// another directory. Returns the size of the written file.
+func emitIR(path string, doc *model.Document) (int64, error) {
+	resources := make([]model.Resource, len(doc.Resources))
+	for i, res := range doc.Resources {
+		if res.SourcePath != "" {
+			if abs, err := filepath.Abs(res.SourcePath); err == nil {
+				res.SourcePath = abs
+			}
+		}
+		resources[i] = res
+	}
+	out := *doc
+	out.Resources = resources
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return 0, fmt.Errorf("%w: cannot create directory %s", ErrOutputNotWrite, dir)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	cw := &countingWriter{w: f}
+	if err := WriteIR(cw, &out); err != nil {
+		f.Close()
+		return 0, fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	return cw.n, nil
+}
+
+// ConvertIR builds an EPUB from a document IR file written with
+// Options.EmitIR. Metadata overrides, hooks, and EPUB options apply as in
+// Convert; parse options such as SplitLevel were applied when the IR was
+// written.
+func (c *Converter) ConvertIR(input string, opts Options) (*model.ConversionResult, error) {
+	start := time.Now()
+	log := opts.logger()
+	result := &model.ConversionResult{
+		Success:  false,
+		Warnings: make([]model.Warning, 0),
+	}
+	rep := newReporter(result, opts)
+
+	f, err := os.Open(input)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, fmt.Errorf("%w: %s", ErrFileNotFound, input)
+	}
+	if err != nil {
+		return result, fmt.Errorf("reading %s: %w", input, err)
+	}
+	doc, err := ReadIR(f)
+	f.Close()
+	if err != nil {
+		return result, &ParseError{File: input, Err: err}
+	}
+	log.Info("loaded document IR", "stage", "parse", "file", input, "chapters", len(doc.Chapters))
+
+	builder, err := c.newBuilder(opts, rep)
+	if err != nil {
+		return result, err
+	}
+
+	budget := newMemoryBudget(opts)
+	defer budget.cleanup()
+
+	// Apply CLI metadata overrides
+	if opts.CLIMetadata != nil {
+		doc.Metadata.Merge(opts.CLIMetadata)
+	}
+	if err := doc.Metadata.NormalizeISBNs(); err != nil {
+		return result, err
+	}
+	if err := doc.Metadata.CheckUniqueID(); err != nil {
+		return result, err
+	}
+
+	if err := runHooks(doc, opts.Hooks, log); err != nil {
+		return result, err
+	}
+
+	// Spill resource data to disk if the document is over the memory budget
+	if err := budget.fit(doc); err != nil {
+		return result, err
+	}
+
+	outputPath := opts.OutputPath
+	if outputPath == "" && opts.NameTemplate != "" {
+		stem := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+		outputPath, err = c.templatedOutputPath(doc, input, stem, opts)
+		if err != nil {
+			return result, err
+		}
+	}
+	if outputPath == "" {
+		outputPath = strings.TrimSuffix(input, filepath.Ext(input)) + ".epub"
+	}
+
+	outputSize, err := c.writeOutput(outputPath, doc, builder, opts.Unpacked, log)
+	if err != nil {
+		return result, err
+	}
+
+	result.Success = true
+	result.OutputPath = outputPath
+	result.Stats = model.ConversionStats{
+		InputFormat:  "ir",
+		InputFiles:   1,
+		ChapterCount: len(doc.Chapters),
+		ImageCount:   len(doc.Resources),
+		OutputSize:   outputSize,
+		Duration:     time.Since(start),
+	}
+	checkOutputSize(result, doc, rep, opts)
+	if err := writeManifest(result, doc, []inputFile{{Path: input}}, opts); err != nil {
+		return result, err
+	}
+	if err := runEPUBCheck(result, rep, opts); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestConverter_EmitIR_ConvertIR(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01-one.md": "---\ntitle: Saved Book\n---\n\n# One\n\nFirst.\n",
+		"02-two.md": "# Two\n\nSecond.\n",
+	})
+	irPath := filepath.Join(dir, "ir", "book.json")
+
+	result, err := New().Convert([]string{dir}, Options{EmitIR: irPath})
+	require.NoError(t, err)
+	assert.Equal(t, irPath, result.OutputPath)
+	assert.NoFileExists(t, filepath.Join(dir, "book.epub"))
+
+	f, err := os.Open(irPath)
+	require.NoError(t, err)
+	doc, err := ReadIR(f)
+	f.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "Saved Book", doc.Metadata.Title)
+	require.Len(t, doc.Chapters, 2)
+	assert.Equal(t, "content/chapter-002.xhtml", doc.Chapters[1].FileName)
+
+	opts := Options{
+		OutputPath:  filepath.Join(dir, "book.epub"),
+		CLIMetadata: &model.Metadata{Publisher: "Example Press"},
+	}
+	result, err = New().ConvertIR(irPath, opts)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, "ir", result.Stats.InputFormat)
+	assert.FileExists(t, opts.OutputPath)
+}
+
+func TestReadIR_Invalid(t *testing.T) {
+	for _, input := range []string{`{"version": 99, "document": {}}`, `{"version": 1}`, `not json`} {
+		_, err := ReadIR(strings.NewReader(input))
+		assert.ErrorIs(t, err, ErrInvalidIR, input)
+	}
+}
@@ -0,0 +1,88 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"html"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// inputLinkRe matches href attributes, capturing the markup before the
+// value and the value in double or single quotes.
+var inputLinkRe = regexp.MustCompile(`(\shref\s*=\s*)(?:"([^"]*)"|'([^']*)')`)
+
+// linkInputFiles points links between input files, such as
+// "02-setup.md#install" in a Markdown book, at the chapters the files
+// became. sources holds the input path of each chapter. A link with a
+// fragment goes to the chapter of the target file holding that id, others
+// to its first chapter. Links to anything else are unchanged.
+func linkInputFiles(doc *model.Document, sources []string) {
+	chapters := make(map[string][]int)
+	for i, source := range sources {
+		chapters[source] = append(chapters[source], i)
+	}
+	ids := make(map[int]map[string]bool)
+	owner := func(file, fragment string) (int, bool) {
+		parts, ok := chapters[file]
+		if !ok {
+			return 0, false
+		}
+		if fragment == "" {
+			return parts[0], true
+		}
+		for _, i := range parts {
+			if ids[i] == nil {
+				ids[i] = make(map[string]bool)
+				for _, m := range splitIDRe.FindAllStringSubmatch(doc.Chapters[i].Content, -1) {
+					ids[i][html.UnescapeString(m[1])] = true
+				}
+			}
+			if ids[i][fragment] {
+				return i, true
+			}
+		}
+		return parts[0], true
+	}
+
+	for i := range doc.Chapters {
+		from := sources[i]
+		doc.Chapters[i].Content = inputLinkRe.ReplaceAllStringFunc(doc.Chapters[i].Content, func(match string) string {
+			m := inputLinkRe.FindStringSubmatch(match)
+			file, fragment, ok := inputLinkTarget(from, html.UnescapeString(m[2]+m[3]))
+			if !ok {
+				return match
+			}
+			target, ok := owner(file, fragment)
+			if !ok {
+				return match
+			}
+			href := path.Base(doc.Chapters[target].FileName)
+			if fragment != "" {
+				href += "#" + fragment
+			}
+			return m[1] + `"` + html.EscapeString(href) + `"`
+		})
+	}
+}
+
+// inputLinkTarget resolves a relative link in the input file at from to
+// the path of the file it names and its fragment. Links within the same
+// document, to other sites, and to absolute paths are not resolved.
+func inputLinkTarget(from, href string) (string, string, bool) {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || u.Scheme != "" || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return "", "", false
+	}
+	return filepath.Join(filepath.Dir(from), filepath.FromSlash(u.Path)), u.Fragment, true
+}
@@ -0,0 +1,51 @@
+package converter
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestLinkInputFiles(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{FileName: "content/chapter-001.xhtml", Content: `<p><a href="b.md#later">later</a>, <a href='sub/../b.md'>b</a>, <a href="#top">top</a>, <a href="https://example.com/b.md">site</a>, <a href="c.md">c</a></p>`})
+	doc.AddChapter(model.Chapter{FileName: "content/chapter-002.xhtml", Content: `<h1 id="b">B</h1>`})
+	doc.AddChapter(model.Chapter{FileName: "content/chapter-003.xhtml", Content: `<h1 id="later">Later</h1><p><a href="../book/a.md">a</a></p>`})
+
+	linkInputFiles(doc, []string{filepath.Join("book", "a.md"), filepath.Join("book", "b.md"), filepath.Join("book", "b.md")})
+
+	assert.Equal(t, `<p><a href="chapter-003.xhtml#later">later</a>, <a href="chapter-002.xhtml">b</a>, <a href="#top">top</a>, <a href="https://example.com/b.md">site</a>, <a href="c.md">c</a></p>`, doc.Chapters[0].Content)
+	assert.Equal(t, `<h1 id="later">Later</h1><p><a href="chapter-001.xhtml">a</a></p>`, doc.Chapters[2].Content)
+}
+
+func TestConverter_Convert_LinksInputFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-intro.md"), []byte("# Intro\n\nSee [setup](02-setup.md#install).\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "02-setup.md"), []byte("# Setup\n\n## Install\n\nBack to [the intro](01-intro.md).\n"), 0o644))
+
+	output := filepath.Join(dir, "book.epub")
+	result, err := New().Convert([]string{dir}, Options{OutputPath: output, SplitLevel: 2})
+	require.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+
+	r, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer r.Close()
+	read := func(name string) string {
+		f, err := r.Open(name)
+		require.NoError(t, err)
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Contains(t, read("OEBPS/content/chapter-001.xhtml"), `<a href="chapter-003.xhtml#install">setup</a>`)
+	assert.Contains(t, read("OEBPS/content/chapter-003.xhtml"), `<a href="chapter-001.xhtml">the intro</a>`)
+}
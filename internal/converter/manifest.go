@@ -0,0 +1,174 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// manifestVersion is the version of the build manifest layout, raised when
+// fields change meaning.
+const manifestVersion = 1
+
+// ManifestOptions asks for a build manifest: a JSON sidecar beside the
+// EPUB recording its checksum, chapters, resources, and the options it was
+// built with, for reproducibility audits and publishing pipelines.
+type ManifestOptions struct {
+	Generator string         // Program that built the book, such as "toepub 1.4.0"
+	Options   map[string]any // Settings the book was built with, such as the command-line flags given
+}
+
+// buildManifest is the JSON layout of a build manifest.
+type buildManifest struct {
+	Version   int                `json:"version"`
+	Generator string             `json:"generator,omitempty"`
+	Created   time.Time          `json:"created"`
+	Output    string             `json:"output"`
+	Unpacked  bool               `json:"unpacked,omitempty"`
+	Size      int64              `json:"size"`
+	SHA256    string             `json:"sha256,omitempty"` // Of the EPUB file; an unpacked tree has none
+	Title     string             `json:"title"`
+	Inputs    []manifestInput    `json:"inputs,omitempty"`
+	Words     int                `json:"words"`
+	Chapters  []manifestChapter  `json:"chapters"`
+	Resources []manifestResource `json:"resources"`
+	Options   map[string]any     `json:"options,omitempty"`
+}
+
+// manifestInput is an input file of the book.
+type manifestInput struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256,omitempty"` // Empty for gallery directories
+}
+
+// manifestChapter is a content document of the book, generated pages
+// included, in reading order.
+type manifestChapter struct {
+	File  string `json:"file"` // Path within the package, e.g. "content/chapter-001.xhtml"
+	Title string `json:"title,omitempty"`
+	Words int    `json:"words"`
+}
+
+// manifestResource is an image, stylesheet, font, or other file embedded
+// in the book.
+type manifestResource struct {
+	File      string `json:"file"` // Path within the package, e.g. "images/photo.png"
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	SHA256    string `json:"sha256,omitempty"`
+}
+
+// ManifestPath returns the path of the build manifest written for the
+// book at output, as in book.epub.json.
+func ManifestPath(output string) string {
+	return output + ".json"
+}
+
+// writeManifest writes the build manifest of the book just written to
+// result.OutputPath from files when opts.Manifest is set. The book must
+// have been built from doc, whose chapters then include the generated
+// pages.
+func writeManifest(result *model.ConversionResult, doc *model.Document, files []inputFile, opts Options) error {
+	if opts.Manifest == nil {
+		return nil
+	}
+	inputs, err := manifestInputs(files)
+	if err != nil {
+		return err
+	}
+
+	manifest := buildManifest{
+		Version:   manifestVersion,
+		Generator: opts.Manifest.Generator,
+		Created:   time.Now().UTC().Truncate(time.Second),
+		Output:    result.OutputPath,
+		Unpacked:  opts.Unpacked,
+		Size:      result.Stats.OutputSize,
+		Title:     doc.Metadata.Title,
+		Inputs:    inputs,
+		Chapters:  make([]manifestChapter, 0, len(doc.Chapters)),
+		Resources: make([]manifestResource, 0, len(doc.Resources)),
+		Options:   opts.Manifest.Options,
+	}
+	if !opts.Unpacked {
+		sum, err := fileSHA256(result.OutputPath)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+		}
+		manifest.SHA256 = sum
+	}
+	for _, chapter := range doc.Chapters {
+		words := countWords(plainText(chapter.Content))
+		manifest.Words += words
+		manifest.Chapters = append(manifest.Chapters, manifestChapter{
+			File:  chapter.FileName,
+			Title: chapter.Title,
+			Words: words,
+		})
+	}
+	for _, res := range doc.Resources {
+		entry := manifestResource{File: res.FileName, MediaType: res.MediaType}
+		entry.Size, _ = resourceSize(res)
+		if sum, ok := resourceHash(res); ok {
+			entry.SHA256 = hex.EncodeToString(sum[:])
+		}
+		manifest.Resources = append(manifest.Resources, entry)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ManifestPath(result.OutputPath)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	opts.logger().Info("wrote build manifest", "stage", "write", "file", path, "bytes", len(data)+1)
+	return nil
+}
+
+// manifestInputs lists the input files with their checksums.
+func manifestInputs(files []inputFile) ([]manifestInput, error) {
+	inputs := make([]manifestInput, 0, len(files))
+	for _, file := range files {
+		input := manifestInput{Path: file.Path}
+		if file.Gallery == nil {
+			data, err := file.read()
+			if err != nil {
+				return nil, fmt.Errorf("reading %s: %w", file.Path, err)
+			}
+			sum := sha256.Sum256(data)
+			input.SHA256 = hex.EncodeToString(sum[:])
+		}
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of the file at path.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,79 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Convert_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	figure := pngResource(t, 4, 3).Data
+	writeFiles(t, dir, map[string]string{
+		"one.md":   "# One\n\nThree short words.\n\n![Flow](flow.png)\n",
+		"two.md":   "# Two\n\nMore.\n",
+		"flow.png": string(figure),
+	})
+	output := filepath.Join(dir, "book.epub")
+
+	result, err := New().Convert([]string{filepath.Join(dir, "one.md"), filepath.Join(dir, "two.md")}, Options{
+		OutputPath: output,
+		Manifest: &ManifestOptions{
+			Generator: "toepub test",
+			Options:   map[string]any{"title": "Manifest"},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, output+".json", ManifestPath(result.OutputPath))
+
+	data, err := os.ReadFile(ManifestPath(output))
+	require.NoError(t, err)
+	var manifest buildManifest
+	require.NoError(t, json.Unmarshal(data, &manifest))
+
+	epubData, err := os.ReadFile(output)
+	require.NoError(t, err)
+	sum := sha256.Sum256(epubData)
+	assert.Equal(t, hex.EncodeToString(sum[:]), manifest.SHA256)
+	assert.Equal(t, int64(len(epubData)), manifest.Size)
+	assert.Equal(t, manifestVersion, manifest.Version)
+	assert.Equal(t, "toepub test", manifest.Generator)
+	assert.Equal(t, map[string]any{"title": "Manifest"}, manifest.Options)
+
+	require.Len(t, manifest.Inputs, 2)
+	assert.Equal(t, filepath.Join(dir, "one.md"), manifest.Inputs[0].Path)
+	assert.Len(t, manifest.Inputs[0].SHA256, 64)
+
+	require.Len(t, manifest.Chapters, 3, "two chapters and the colophon")
+	assert.Equal(t, manifestChapter{File: "content/chapter-001.xhtml", Title: "One", Words: 4}, manifest.Chapters[0])
+	assert.Equal(t, "Two", manifest.Chapters[1].Title)
+	total := 0
+	for _, chapter := range manifest.Chapters {
+		total += chapter.Words
+	}
+	assert.Equal(t, total, manifest.Words)
+
+	var image *manifestResource
+	for i, res := range manifest.Resources {
+		if res.MediaType == "image/png" {
+			image = &manifest.Resources[i]
+		}
+	}
+	require.NotNil(t, image)
+	figureSum := sha256.Sum256(figure)
+	assert.Equal(t, "images/flow.png", image.File)
+	assert.Equal(t, int64(len(figure)), image.Size)
+	assert.Equal(t, hex.EncodeToString(figureSum[:]), image.SHA256)
+
+	// No manifest unless asked for
+	require.NoError(t, os.Remove(ManifestPath(output)))
+	_, err = New().Convert([]string{filepath.Join(dir, "one.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+	assert.NoFileExists(t, ManifestPath(output))
+}
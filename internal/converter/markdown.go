@@ -0,0 +1,680 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Patterns used when writing Markdown.
+var (
+	markdownEscapeRe = regexp.MustCompile("([\\\\`*_\\[\\]<])")
+	blockStartRe     = regexp.MustCompile(`^([#>+-]|\d+[.)])(\s|$)`)
+	whitespaceRe     = regexp.MustCompile(`\s+`)
+	hardBreakRe      = regexp.MustCompile(` ?\x00 ?`)
+	blankLinesRe     = regexp.MustCompile(`\n{3,}`)
+)
+
+// generatedClasses are spans added by the EPUB builder that are dropped or
+// unwrapped when writing Markdown, because converting again recreates them.
+var generatedClasses = map[string]bool{
+	"section-number": true,
+	"label":          true,
+}
+
+// noteTypes are the epub:type values of footnotes and endnotes.
+var noteTypes = map[string]bool{
+	"footnote": true,
+	"endnote":  true,
+	"rearnote": true,
+}
+
+// markdownWriter converts XHTML fragments to CommonMark with GFM tables.
+// Elements without a Markdown equivalent are kept as raw HTML.
+type markdownWriter struct {
+	// rewriteURL maps link and image URLs; nil keeps them unchanged
+	rewriteURL func(string) string
+
+	// note returns the footnote or endnote a link points to, or nil if it
+	// is not one. Such notes are written as Markdown footnotes where they
+	// are referenced and dropped where they are listed.
+	note func(href string) *html.Node
+
+	footnotes []*html.Node // Notes referenced so far, numbered in order
+}
+
+// Convert returns the Markdown for an XHTML fragment.
+func (w *markdownWriter) Convert(fragment string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), &html.Node{Type: html.ElementNode, DataAtom: atom.Body, Data: "body"})
+	if err != nil {
+		return "", err
+	}
+
+	var buf strings.Builder
+	w.footnotes = nil
+	w.blocks(&buf, nodes)
+	w.noteDefinitions(&buf)
+	out := blankLinesRe.ReplaceAllString(strings.TrimSpace(buf.String()), "\n\n")
+	return out + "\n", nil
+}
+
+// noteDefinitions writes the notes referenced in the fragment as Markdown
+// footnote definitions, without their links back to the reference.
+func (w *markdownWriter) noteDefinitions(buf *strings.Builder) {
+	// Notes may refer to further notes, which are appended as they go
+	for i := 0; i < len(w.footnotes); i++ {
+		var inner strings.Builder
+		w.children(&inner, w.footnotes[i])
+		indent := "    "
+		text := strings.TrimPrefix(prefixLines(strings.TrimSpace(inner.String()), indent), indent)
+		buf.WriteString("[^" + strconv.Itoa(i+1) + "]: " + text + "\n\n")
+	}
+}
+
+// noteRef returns the Markdown footnote reference for a noteref link, or
+// "" if n is not one or its note is unknown.
+func (w *markdownWriter) noteRef(n *html.Node) string {
+	if w.note == nil || !hasToken(attr(n, "epub:type"), "noteref") {
+		return ""
+	}
+	note := w.note(attr(n, "href"))
+	if note == nil {
+		return ""
+	}
+	i := slices.Index(w.footnotes, note)
+	if i < 0 {
+		w.footnotes = append(w.footnotes, note)
+		i = len(w.footnotes) - 1
+	}
+	return "[^" + strconv.Itoa(i+1) + "]"
+}
+
+// movedNote reports whether n is a note written as a Markdown footnote
+// where it is referenced.
+func (w *markdownWriter) movedNote(n *html.Node) bool {
+	id := attr(n, "id")
+	return w.note != nil && id != "" && isNote(n) && w.note("#"+id) != nil
+}
+
+// notesOnly reports whether n lists only notes written as Markdown
+// footnotes, besides headings and rules, so it can be dropped.
+func (w *markdownWriter) notesOnly(n *html.Node) bool {
+	found := false
+	var walk func(*html.Node) bool
+	walk = func(parent *html.Node) bool {
+		for c := parent.FirstChild; c != nil; c = c.NextSibling {
+			switch {
+			case c.Type == html.TextNode:
+				if strings.TrimSpace(c.Data) != "" {
+					return false
+				}
+			case c.Type != html.ElementNode:
+			case isNote(c):
+				if !w.movedNote(c) {
+					return false
+				}
+				found = true
+			case c.DataAtom == atom.Hr || headingLevel(c) > 0:
+			default:
+				if !walk(c) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	return walk(n) && found
+}
+
+// blocks writes a sequence of nodes, gathering runs of text and inline
+// elements into paragraphs.
+func (w *markdownWriter) blocks(buf *strings.Builder, nodes []*html.Node) {
+	var run strings.Builder
+	flush := func() {
+		if text := escapeBlockStart(cleanInline(run.String())); text != "" {
+			buf.WriteString(text + "\n\n")
+		}
+		run.Reset()
+	}
+
+	for _, n := range nodes {
+		if isInlineNode(n) {
+			w.inlineNode(&run, n)
+			continue
+		}
+		flush()
+		w.block(buf, n)
+	}
+	flush()
+}
+
+// block writes a block-level node followed by a blank line.
+func (w *markdownWriter) block(buf *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.CommentNode:
+		if strings.TrimSpace(n.Data) == "pagebreak" {
+			buf.WriteString("<!-- pagebreak -->\n\n")
+		}
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		heading := strings.Repeat("#", headingLevel(n)) + " " + w.inline(n)
+		if id := attr(n, "id"); id != "" && id != headingID(sourceTextOf(n)) {
+			heading += " {#" + id + "}"
+		}
+		buf.WriteString(heading + "\n\n")
+	case atom.P:
+		if text := escapeBlockStart(w.inline(n)); text != "" {
+			buf.WriteString(text + "\n\n")
+		}
+	case atom.Blockquote:
+		var inner strings.Builder
+		w.children(&inner, n)
+		buf.WriteString(prefixLines(strings.TrimSpace(inner.String()), "> ") + "\n\n")
+	case atom.Ul, atom.Ol:
+		w.list(buf, n)
+		buf.WriteString("\n")
+	case atom.Pre:
+		w.codeBlock(buf, n)
+	case atom.Hr:
+		buf.WriteString("---\n\n")
+	case atom.Table:
+		if !w.table(buf, n) {
+			buf.WriteString(w.raw(n) + "\n\n")
+		}
+	case atom.Div, atom.Section, atom.Article, atom.Main, atom.Header, atom.Footer:
+		if n.DataAtom == atom.Div && hasClass(n, "page-break") {
+			buf.WriteString("<!-- pagebreak -->\n\n")
+			return
+		}
+		if w.notesOnly(n) {
+			return
+		}
+		w.children(buf, n)
+	case atom.Aside:
+		if !w.movedNote(n) {
+			buf.WriteString(w.raw(n) + "\n\n")
+		}
+	default:
+		buf.WriteString(w.raw(n) + "\n\n")
+	}
+}
+
+// children writes the child nodes of n as blocks.
+func (w *markdownWriter) children(buf *strings.Builder, n *html.Node) {
+	var nodes []*html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		nodes = append(nodes, c)
+	}
+	w.blocks(buf, nodes)
+}
+
+// list writes a bulleted or numbered list, indenting nested blocks under
+// their item marker.
+func (w *markdownWriter) list(buf *strings.Builder, n *html.Node) {
+	number := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil {
+		number = start
+	}
+
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.DataAtom != atom.Li || w.movedNote(item) {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		var inner strings.Builder
+		w.children(&inner, item)
+		text := strings.TrimSpace(inner.String())
+		if !hasParagraphs(item) {
+			// Keep tight lists tight, including nested lists
+			text = strings.ReplaceAll(text, "\n\n", "\n")
+		}
+		indent := strings.Repeat(" ", len(marker))
+		buf.WriteString(marker + strings.TrimPrefix(prefixLines(text, indent), indent) + "\n")
+	}
+}
+
+// codeBlock writes a fenced code block, keeping the language class.
+func (w *markdownWriter) codeBlock(buf *strings.Builder, n *html.Node) {
+	code := n
+	language := ""
+	if c := n.FirstChild; c != nil && c.DataAtom == atom.Code && c.NextSibling == nil {
+		code = c
+		for _, class := range strings.Fields(attr(c, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				language = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+
+	text := strings.TrimSuffix(plainTextOf(code), "\n")
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	buf.WriteString(fence + language + "\n" + text + "\n" + fence + "\n\n")
+}
+
+// table writes a GFM table. It returns false for tables that cannot be
+// represented, such as those with block content or spanning cells.
+func (w *markdownWriter) table(buf *strings.Builder, n *html.Node) bool {
+	var rows [][]string
+	var cols int
+	var ok = true
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for c := node.FirstChild; c != nil && ok; c = c.NextSibling {
+			switch c.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(c)
+			case atom.Caption:
+				ok = false
+			case atom.Tr:
+				var row []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom != atom.Td && cell.DataAtom != atom.Th {
+						continue
+					}
+					if attr(cell, "colspan") != "" || attr(cell, "rowspan") != "" || hasBlockChildren(cell) {
+						ok = false
+						return
+					}
+					row = append(row, strings.ReplaceAll(w.inline(cell), "|", "\\|"))
+				}
+				if len(row) > cols {
+					cols = len(row)
+				}
+				rows = append(rows, row)
+			}
+		}
+	}
+	walk(n)
+	if !ok || len(rows) == 0 || cols == 0 {
+		return false
+	}
+
+	for i, row := range rows {
+		for len(row) < cols {
+			row = append(row, "")
+		}
+		buf.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			buf.WriteString(strings.Repeat("| --- ", cols) + "|\n")
+		}
+	}
+	buf.WriteString("\n")
+	return true
+}
+
+// inline returns the Markdown for the inline content of n.
+func (w *markdownWriter) inline(n *html.Node) string {
+	var buf strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.inlineNode(&buf, c)
+	}
+	return cleanInline(buf.String())
+}
+
+// cleanInline collapses whitespace in inline Markdown and turns hard break
+// placeholders into backslash line breaks.
+func cleanInline(s string) string {
+	s = whitespaceRe.ReplaceAllString(s, " ")
+	s = hardBreakRe.ReplaceAllString(strings.TrimSpace(s), "\x00")
+	return strings.ReplaceAll(s, "\x00", "\\\n")
+}
+
+// inlineNode writes a single inline node.
+func (w *markdownWriter) inlineNode(buf *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(w.inlineText(n))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Em, atom.I:
+		w.wrap(buf, n, "*")
+	case atom.Strong, atom.B:
+		w.wrap(buf, n, "**")
+	case atom.Del, atom.S:
+		w.wrap(buf, n, "~~")
+	case atom.Code:
+		text := plainTextOf(n)
+		fence := "`"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+			text = " " + text + " "
+		}
+		buf.WriteString(fence + text + fence)
+	case atom.Br:
+		buf.WriteString("\x00")
+	case atom.A:
+		if ref := w.noteRef(n); ref != "" {
+			buf.WriteString(ref)
+			return
+		}
+		if attr(n, "role") == "doc-backlink" && w.note != nil {
+			return
+		}
+		href := attr(n, "href")
+		if href == "" || attr(n, "epub:type") != "" {
+			buf.WriteString(w.raw(n))
+			return
+		}
+		buf.WriteString("[" + w.inline(n) + "](" + w.url(href) + titleSuffix(n) + ")")
+	case atom.Img:
+		buf.WriteString("![" + escapeMarkdown(attr(n, "alt")) + "](" + w.url(attr(n, "src")) + titleSuffix(n) + ")")
+	case atom.Sup:
+		if link := onlyElement(n); link != nil && link.DataAtom == atom.A {
+			if ref := w.noteRef(link); ref != "" {
+				buf.WriteString(ref)
+				return
+			}
+		}
+		buf.WriteString(w.raw(n))
+	case atom.Span:
+		if hasAnyClass(n, generatedClasses) {
+			return
+		}
+		if len(n.Attr) == 0 || hasClass(n, "drop-cap") {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				w.inlineNode(buf, c)
+			}
+			return
+		}
+		buf.WriteString(w.raw(n))
+	default:
+		buf.WriteString(w.raw(n))
+	}
+}
+
+// wrap writes the inline content of n between delimiters, keeping
+// surrounding spaces outside them.
+func (w *markdownWriter) wrap(buf *strings.Builder, n *html.Node, delim string) {
+	var inner strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.inlineNode(&inner, c)
+	}
+	text := inner.String()
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		buf.WriteString(text)
+		return
+	}
+	if strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\n") {
+		buf.WriteString(" ")
+	}
+	buf.WriteString(delim + trimmed + delim)
+	if strings.HasSuffix(text, " ") || strings.HasSuffix(text, "\n") {
+		buf.WriteString(" ")
+	}
+}
+
+// inlineText returns an escaped text node.
+func (w *markdownWriter) inlineText(n *html.Node) string {
+	return escapeMarkdown(n.Data)
+}
+
+// raw renders n as HTML after rewriting its URLs.
+func (w *markdownWriter) raw(n *html.Node) string {
+	w.rewriteNodeURLs(n)
+	var buf bytes.Buffer
+	if err := html.Render(&buf, n); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// rewriteNodeURLs applies rewriteURL to src and href attributes in n.
+func (w *markdownWriter) rewriteNodeURLs(n *html.Node) {
+	if n.Type == html.ElementNode {
+		for i, a := range n.Attr {
+			if a.Key == "src" || a.Key == "href" || a.Key == "poster" {
+				n.Attr[i].Val = w.rewrite(a.Val)
+			}
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.rewriteNodeURLs(c)
+	}
+}
+
+// url returns a rewritten URL for use in a Markdown link destination.
+func (w *markdownWriter) url(u string) string {
+	u = w.rewrite(u)
+	if strings.ContainsAny(u, " ()") {
+		return "<" + u + ">"
+	}
+	return u
+}
+
+// rewrite applies rewriteURL when set.
+func (w *markdownWriter) rewrite(u string) string {
+	if w.rewriteURL == nil {
+		return u
+	}
+	return w.rewriteURL(u)
+}
+
+// escapeMarkdown escapes characters that would start inline Markdown syntax.
+func escapeMarkdown(s string) string {
+	return markdownEscapeRe.ReplaceAllString(s, `\$1`)
+}
+
+// escapeBlockStart escapes text at the start of a paragraph that would
+// otherwise be read as a heading, quote, or list marker.
+func escapeBlockStart(s string) string {
+	m := blockStartRe.FindStringSubmatchIndex(s)
+	if m == nil {
+		return s
+	}
+	marker := s[m[2]:m[3]]
+	if len(marker) > 1 {
+		// Numbered list marker: escape the delimiter after the digits
+		return marker[:len(marker)-1] + `\` + s[m[3]-1:]
+	}
+	return `\` + s
+}
+
+// titleSuffix returns the link title part of a destination, if any.
+func titleSuffix(n *html.Node) string {
+	title := attr(n, "title")
+	if title == "" {
+		return ""
+	}
+	return ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+}
+
+// prefixLines prefixes every non-empty line of s.
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, " ")
+			continue
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// headingID returns the id Markdown parsing would generate for a heading.
+func headingID(text string) string {
+	var buf strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ' || r == '-':
+			buf.WriteRune('-')
+		case r == '_' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r > 127:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// blockElements are elements written as separate blocks.
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Blockquote: true, atom.Ul: true, atom.Ol: true, atom.Pre: true, atom.Hr: true, atom.Table: true,
+	atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true, atom.Header: true, atom.Footer: true,
+	atom.Nav: true, atom.Aside: true, atom.Figure: true, atom.Dl: true, atom.Details: true,
+	atom.Audio: true, atom.Video: true, atom.Iframe: true, atom.Form: true,
+}
+
+// isInlineNode reports whether n belongs inside a paragraph.
+func isInlineNode(n *html.Node) bool {
+	switch n.Type {
+	case html.TextNode:
+		return true
+	case html.ElementNode:
+		return !blockElements[n.DataAtom]
+	}
+	return false
+}
+
+// hasBlockChildren reports whether n contains block-level elements.
+func hasBlockChildren(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockElements[c.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasParagraphs reports whether n contains paragraphs or other blocks that
+// make a list item loose.
+func hasParagraphs(n *html.Node) bool {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockElements[c.DataAtom] && c.DataAtom != atom.Ul && c.DataAtom != atom.Ol {
+			return true
+		}
+	}
+	return false
+}
+
+// plainTextOf returns the text content of n.
+func plainTextOf(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var buf strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(plainTextOf(c))
+	}
+	return buf.String()
+}
+
+// headingLevel returns the level of a heading element, or 0 if n is not one.
+func headingLevel(n *html.Node) int {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return int(n.Data[1] - '0')
+	}
+	return 0
+}
+
+// isNote reports whether n is a footnote or endnote.
+func isNote(n *html.Node) bool {
+	for _, t := range strings.Fields(attr(n, "epub:type")) {
+		if noteTypes[t] {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyElement returns the single child element of n when it has no other
+// content besides whitespace, or nil.
+func onlyElement(n *html.Node) *html.Node {
+	var only *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.ElementNode && only == nil:
+			only = c
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+		default:
+			return nil
+		}
+	}
+	return only
+}
+
+// hasToken reports whether a space-separated attribute value holds token.
+func hasToken(value, token string) bool {
+	return slices.Contains(strings.Fields(value), token)
+}
+
+// sourceTextOf returns the text content of n without the spans the builder
+// generates, as it was written in the source.
+func sourceTextOf(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if n.DataAtom == atom.Span && hasAnyClass(n, generatedClasses) {
+		return ""
+	}
+	var buf strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(sourceTextOf(c))
+	}
+	return buf.String()
+}
+
+// attr returns the value of an attribute of n.
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// hasClass reports whether n has the given class.
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyClass reports whether n has one of the given classes.
+func hasAnyClass(n *html.Node, classes map[string]bool) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if classes[c] {
+			return true
+		}
+	}
+	return false
+}
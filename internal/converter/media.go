@@ -0,0 +1,77 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// Media handling errors
+var (
+	ErrMediaNotFound    = errors.New("media file not found")
+	ErrUnsupportedMedia = errors.New("unsupported media format")
+)
+
+// attachAudio adds audio files given on the command line to the document.
+// Attached files are placed in the package's audio directory so content can
+// reference them as "../audio/<name>".
+func (c *Converter) attachAudio(doc *model.Document, paths []string) error {
+	for _, path := range paths {
+		mediaType := model.AudioMediaType(path)
+		if mediaType == "" {
+			return fmt.Errorf("%w: %s (supported: mp3, m4a, aac, opus)", ErrUnsupportedMedia, path)
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("%w: %s", ErrMediaNotFound, path)
+		}
+
+		baseName := filepath.Base(path)
+		doc.AddResource(model.Resource{
+			ID:         "audio-" + sanitizeID(strings.TrimSuffix(baseName, filepath.Ext(baseName))),
+			FileName:   "audio/" + baseName,
+			MediaType:  mediaType,
+			SourcePath: path,
+		})
+	}
+	return nil
+}
+
+// processMedia verifies that audio, video, and script resources referenced by content exist.
+// Missing files are dropped with a warning; data is streamed at build time.
+func (c *Converter) processMedia(doc *model.Document, rep *reporter) {
+	processedResources := make([]model.Resource, 0, len(doc.Resources))
+
+	for _, res := range doc.Resources {
+		isMedia := res.IsAudio() || res.IsVideo() || res.MediaType == parser.ScriptMediaType
+		if !isMedia || len(res.Data) > 0 {
+			processedResources = append(processedResources, res)
+			continue
+		}
+
+		if _, err := os.Stat(res.SourcePath); err != nil {
+			rep.warn(model.Warning{
+				Code:    model.WarnMissingMedia,
+				File:    res.SourcePath,
+				Message: fmt.Sprintf("Media %s: %s", res.SourcePath, ErrMediaNotFound),
+			})
+			continue
+		}
+
+		processedResources = append(processedResources, res)
+	}
+
+	doc.Resources = processedResources
+}
@@ -0,0 +1,159 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ErrMemoryLimit is returned when a conversion cannot fit in
+// Options.MaxMemory, instead of exhausting the process's memory.
+var ErrMemoryLimit = errors.New("memory budget exceeded")
+
+// memoryBudget holds a conversion's document within Options.MaxMemory.
+// Chapter text must stay in memory; resource data over the budget is
+// spilled to temporary files that the EPUB builder streams from disk.
+// A nil budget is unlimited.
+type memoryBudget struct {
+	limit int64
+	dir   string // Spill directory, created on first use
+	log   *slog.Logger
+}
+
+// newMemoryBudget returns a budget of limit bytes, or nil if limit is 0.
+func newMemoryBudget(opts Options) *memoryBudget {
+	if opts.MaxMemory <= 0 {
+		return nil
+	}
+	return &memoryBudget{limit: opts.MaxMemory, log: opts.logger()}
+}
+
+// checkInput fails if reading size more bytes of input, on top of the
+// document parsed so far, would go over the budget.
+func (b *memoryBudget) checkInput(name string, size int64, doc *model.Document) error {
+	if b == nil {
+		return nil
+	}
+	if name == "" {
+		name = "input"
+	}
+	if size > b.limit {
+		return fmt.Errorf("%w: %s is larger than the %s limit", ErrMemoryLimit, name, formatMB(b.limit))
+	}
+	if used := textSize(doc); used+size > b.limit {
+		return fmt.Errorf("%w: %s is %s, with %s of chapters already loaded (limit %s)",
+			ErrMemoryLimit, name, formatMB(size), formatMB(used), formatMB(b.limit))
+	}
+	return nil
+}
+
+// checkText fails if the chapter text of doc alone is over the budget.
+func (b *memoryBudget) checkText(doc *model.Document) error {
+	if b == nil {
+		return nil
+	}
+	if used := textSize(doc); used > b.limit {
+		return fmt.Errorf("%w: chapter text needs %s (limit %s)", ErrMemoryLimit, formatMB(used), formatMB(b.limit))
+	}
+	return nil
+}
+
+// fit spills in-memory resource data to temporary files, largest first,
+// until doc fits the budget.
+func (b *memoryBudget) fit(doc *model.Document) error {
+	if b == nil {
+		return nil
+	}
+	if err := b.checkText(doc); err != nil {
+		return err
+	}
+
+	used := textSize(doc)
+	loaded := make([]int, 0, len(doc.Resources))
+	for i, res := range doc.Resources {
+		used += int64(len(res.Data))
+		if len(res.Data) > 0 {
+			loaded = append(loaded, i)
+		}
+	}
+	sort.SliceStable(loaded, func(a, c int) bool {
+		return len(doc.Resources[loaded[a]].Data) > len(doc.Resources[loaded[c]].Data)
+	})
+
+	spilled := 0
+	for _, i := range loaded {
+		if used <= b.limit {
+			break
+		}
+		res := &doc.Resources[i]
+		name, err := b.spill(res.Data)
+		if err != nil {
+			return fmt.Errorf("%w: spilling %s to disk: %w", ErrMemoryLimit, res.FileName, err)
+		}
+		used -= int64(len(res.Data))
+		res.SourcePath = name
+		res.Data = nil
+		spilled++
+	}
+	if spilled > 0 {
+		b.log.Debug("spilled resources to disk", "stage", "resources", "resources", spilled, "dir", b.dir)
+	}
+	return nil
+}
+
+// spill writes data to a new file in the spill directory.
+func (b *memoryBudget) spill(data []byte) (string, error) {
+	if b.dir == "" {
+		dir, err := os.MkdirTemp("", "toepub-spill-*")
+		if err != nil {
+			return "", err
+		}
+		b.dir = dir
+	}
+
+	f, err := os.CreateTemp(b.dir, "resource-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return "", err
+	}
+	return filepath.Clean(f.Name()), f.Close()
+}
+
+// cleanup removes the spilled files once the EPUB is written.
+func (b *memoryBudget) cleanup() {
+	if b != nil && b.dir != "" {
+		os.RemoveAll(b.dir)
+	}
+}
+
+// textSize returns the bytes of chapter content held by doc.
+func textSize(doc *model.Document) int64 {
+	if doc == nil {
+		return 0
+	}
+	var n int64
+	for _, chapter := range doc.Chapters {
+		n += int64(len(chapter.Content))
+	}
+	return n
+}
+
+// formatMB formats a byte count in megabytes for error messages.
+func formatMB(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
@@ -0,0 +1,109 @@
+package converter
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+func TestMemoryBudget_Fit(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{Content: strings.Repeat("x", 100)})
+	doc.AddResource(model.Resource{FileName: "images/large.png", Data: bytes.Repeat([]byte{1}, 400)})
+	doc.AddResource(model.Resource{FileName: "images/small.png", Data: bytes.Repeat([]byte{2}, 50)})
+
+	budget := &memoryBudget{limit: 200, log: Options{}.logger()}
+	defer budget.cleanup()
+	require.NoError(t, budget.fit(doc))
+
+	// Only the largest resource needs to leave memory
+	large, small := doc.Resources[0], doc.Resources[1]
+	assert.Empty(t, large.Data)
+	require.NotEmpty(t, large.SourcePath)
+	data, err := os.ReadFile(large.SourcePath)
+	require.NoError(t, err)
+	assert.Len(t, data, 400)
+	assert.Len(t, small.Data, 50)
+
+	budget.cleanup()
+	_, err = os.Stat(large.SourcePath)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestMemoryBudget_TextOverLimit(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{Content: strings.Repeat("x", 300)})
+
+	budget := &memoryBudget{limit: 200, log: Options{}.logger()}
+	assert.ErrorIs(t, budget.fit(doc), ErrMemoryLimit)
+	assert.ErrorIs(t, budget.checkInput("book.md", 150, doc), ErrMemoryLimit)
+	assert.ErrorIs(t, budget.checkInput("book.md", 250, nil), ErrMemoryLimit)
+	assert.NoError(t, budget.checkInput("book.md", 150, nil))
+}
+
+func TestMemoryBudget_Unlimited(t *testing.T) {
+	budget := newMemoryBudget(Options{})
+	assert.Nil(t, budget)
+	assert.NoError(t, budget.checkInput("book.md", 1<<40, nil))
+	assert.NoError(t, budget.fit(model.NewDocument()))
+	budget.cleanup()
+}
+
+func TestConverter_Convert_MaxMemory(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"book.md": "# One\n\n" + strings.Repeat("Text. ", 1000) + "\n",
+	})
+
+	_, err := New().Convert([]string{filepath.Join(dir, "book.md")}, Options{
+		OutputPath: filepath.Join(dir, "book.epub"),
+		MaxMemory:  1000,
+	})
+	assert.ErrorIs(t, err, ErrMemoryLimit)
+	assert.NoFileExists(t, filepath.Join(dir, "book.epub"))
+}
+
+func TestConverter_ConvertReader_MaxMemorySpill(t *testing.T) {
+	image := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte{0}, 4096)...)
+	opts := Options{
+		Fetcher:   mapFetcher{"figure.png": image},
+		MaxMemory: 2048,
+	}
+
+	var out bytes.Buffer
+	result, err := New().ConvertReader(strings.NewReader("# One\n\n![figure](figure.png)\n"), parser.FormatMarkdown, &out, opts)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	require.NoError(t, err)
+	for _, f := range archive.File {
+		if f.Name == "OEBPS/images/figure.png" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			assert.Equal(t, image, data)
+			return
+		}
+	}
+	t.Fatal("spilled image missing from EPUB")
+}
+
+func TestConverter_ConvertReader_MaxMemoryInput(t *testing.T) {
+	var out bytes.Buffer
+	_, err := New().ConvertReader(strings.NewReader(strings.Repeat("x", 5000)), parser.FormatMarkdown, &out, Options{MaxMemory: 1000})
+	assert.ErrorIs(t, err, ErrMemoryLimit)
+	assert.Zero(t, out.Len())
+}
@@ -0,0 +1,63 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// UpdateMetadata applies metadata overrides to an existing EPUB file in
+// place, without re-converting its content. Title, authors, language,
+// publisher, and description are replaced when set, and meta.CoverImage
+// replaces the book's cover image.
+func (c *Converter) UpdateMetadata(path string, meta *model.Metadata) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+
+	update := epub.MetadataUpdate{
+		Title:       meta.Title,
+		Authors:     meta.Authors,
+		Language:    meta.Language,
+		Publisher:   meta.Publisher,
+		Description: meta.Description,
+	}
+
+	if meta.CoverImage != "" {
+		cover, err := c.imgHandler.ProcessImage(meta.CoverImage, ".")
+		if err != nil {
+			return fmt.Errorf("cover image: %w", err)
+		}
+		cover.FileName = "images/cover" + extensionFromMediaType(cover.MediaType)
+		update.Cover = cover
+	}
+
+	if err := epub.UpdateMetadata(path, update); err != nil {
+		return fmt.Errorf("updating %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadMetadata reads the metadata of an existing EPUB file from its
+// package document.
+func (c *Converter) ReadMetadata(path string) (*epub.PackageMetadata, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+
+	meta, err := epub.ReadPackageMetadata(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return meta, nil
+}
@@ -0,0 +1,68 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// resourceRefRe matches chapter references to files in the package.
+var resourceRefRe = regexp.MustCompile(`\.\./([^"'<>()\s]+)`)
+
+// uniqueFileName returns name, or the first of name-2, name-3, and so on
+// that is not taken, with the number used (1 for name itself).
+func uniqueFileName(name string, taken func(string) bool) (string, int) {
+	ext := path.Ext(name)
+	stem := strings.TrimSuffix(name, ext)
+	n := 1
+	for taken(name) {
+		n++
+		name = stem + "-" + strconv.Itoa(n) + ext
+	}
+	return name, n
+}
+
+// sharedResource returns the resource in resources holding the same file
+// as res: read from the same path, or with identical data.
+func sharedResource(resources []model.Resource, res model.Resource) (model.Resource, bool) {
+	for _, other := range resources {
+		if res.SourcePath != "" && other.SourcePath != "" {
+			if filepath.Clean(res.SourcePath) == filepath.Clean(other.SourcePath) {
+				return other, true
+			}
+			continue
+		}
+		if len(res.Data) > 0 && other.FileName == res.FileName && bytes.Equal(res.Data, other.Data) {
+			return other, true
+		}
+	}
+	return model.Resource{}, false
+}
+
+// retargetResources points a chapter's references to resources renamed in
+// moved, a map of old to new file names.
+func retargetResources(chapter *model.Chapter, moved map[string]string) {
+	chapter.Content = resourceRefRe.ReplaceAllStringFunc(chapter.Content, func(match string) string {
+		if name, ok := moved[match[len("../"):]]; ok {
+			return "../" + name
+		}
+		return match
+	})
+	for i, css := range chapter.Stylesheets {
+		if name, ok := moved[css]; ok {
+			chapter.Stylesheets[i] = name
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package converter
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestConverter_Convert_CollidingImageNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, sub := range []string{"one", "two"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub), 0o755))
+		data := append(append([]byte{}, pngHeader...), sub...)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, sub, "photo.png"), data, 0o644))
+	}
+	writeFiles(t, dir, map[string]string{
+		"one/book.md": "# One\n\n![First photo](photo.png)\n",
+		"two/book.md": "# Two\n\n![Second photo](photo.png)\n\n![Shared photo](../one/photo.png)\n",
+	})
+
+	output := filepath.Join(dir, "book.epub")
+	result, err := New().Convert([]string{filepath.Join(dir, "one/book.md"), filepath.Join(dir, "two/book.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+
+	var notes []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnDuplicateID {
+			notes = append(notes, w)
+		}
+	}
+	require.Len(t, notes, 1)
+	assert.Equal(t, model.SeverityInfo, notes[0].Severity)
+	assert.Contains(t, notes[0].Message, "saved as images/photo-2.png")
+
+	archive, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer archive.Close()
+	files := make(map[string]string)
+	for _, f := range archive.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		require.NoError(t, err)
+		files[f.Name] = string(data)
+	}
+
+	assert.True(t, strings.HasSuffix(files["OEBPS/images/photo.png"], "one"))
+	assert.True(t, strings.HasSuffix(files["OEBPS/images/photo-2.png"], "two"))
+	assert.Contains(t, files["OEBPS/content/chapter-001.xhtml"], `src="../images/photo.png"`)
+	assert.Contains(t, files["OEBPS/content/chapter-002.xhtml"], `src="../images/photo-2.png"`)
+	assert.Contains(t, files["OEBPS/content/chapter-002.xhtml"], `src="../images/photo.png"`)
+	assert.Contains(t, files["OEBPS/content.opf"], `id="img-photo-2"`)
+}
+
+func TestUniqueFileName(t *testing.T) {
+	taken := map[string]bool{"images/a.png": true, "images/a-2.png": true}
+	name, n := uniqueFileName("images/a.png", func(name string) bool { return taken[name] })
+	assert.Equal(t, "images/a-3.png", name)
+	assert.Equal(t, 3, n)
+
+	name, n = uniqueFileName("images/b.png", func(name string) bool { return taken[name] })
+	assert.Equal(t, "images/b.png", name)
+	assert.Equal(t, 1, n)
+}
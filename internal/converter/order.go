@@ -0,0 +1,258 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// orderFileNames are the files, checked in this order, that define the
+// chapter order of a directory input instead of alphabetical sorting.
+var orderFileNames = []string{"SUMMARY.md", "index.txt"}
+
+// summaryLinkRe matches a chapter link in an mdBook-style SUMMARY.md line,
+// such as "- [Introduction](intro.md)".
+var summaryLinkRe = regexp.MustCompile(`^(?:[-*+]\s+)?\[[^\]]*\]\(\s*<?([^)>]*?)>?\s*\)`)
+
+// orderTypeRe matches a chapter type given after an order file entry, as
+// in "preface.md {.preface}".
+var orderTypeRe = regexp.MustCompile(`\s*\{\.([\w-]+)\}$`)
+
+// inputFile is an input file with its nesting depth in the book. Files
+// nested in an order file have their headings placed under the previous
+// file's entry in the table of contents.
+type inputFile struct {
+	Path    string
+	Depth   int
+	Type    string        // Body epub:type for the file's chapters, declared in the order file
+	Gallery []string      // Images made into photo pages, for a gallery directory at Path
+	Archive *inputArchive // Archive input holding the file at Name, read instead of Path
+	Name    string
+}
+
+// read returns the content of the file.
+func (f inputFile) read() ([]byte, error) {
+	if f.Archive != nil {
+		return fs.ReadFile(f.Archive.FS, f.Name)
+	}
+	return os.ReadFile(f.Path)
+}
+
+// stat returns the file's information.
+func (f inputFile) stat() (fs.FileInfo, error) {
+	if f.Archive != nil {
+		return fs.Stat(f.Archive.FS, f.Name)
+	}
+	return os.Stat(f.Path)
+}
+
+// inputTree is a directory input, on disk or inside an archive input.
+type inputTree struct {
+	Dir     string        // Path of the directory, joined to the names of its files
+	FS      fs.FS         // Contents of the directory
+	Archive *inputArchive // Archive holding the directory at Root, nil on disk
+	Root    string
+}
+
+// diskTree returns the tree of the directory dir on disk.
+func diskTree(dir string) inputTree {
+	return inputTree{Dir: dir, FS: os.DirFS(dir)}
+}
+
+// file returns the file at the slash-separated name in the tree.
+func (t inputTree) file(name string) inputFile {
+	file := inputFile{Path: filepath.Join(t.Dir, filepath.FromSlash(name))}
+	if t.Archive != nil {
+		file.Archive = t.Archive
+		file.Name = path.Join(t.Root, name)
+	}
+	return file
+}
+
+// findOrderFile returns the order file of tree, if it has one.
+func findOrderFile(tree inputTree) (inputFile, bool) {
+	for _, name := range orderFileNames {
+		file := tree.file(name)
+		if info, err := file.stat(); err == nil && !info.IsDir() {
+			return file, true
+		}
+	}
+	return inputFile{}, false
+}
+
+// readOrderFile returns the files listed in the order file of tree, with
+// paths resolved against the tree. SUMMARY.md lists files as Markdown
+// links; index.txt lists one path per line. In both, indentation nests a
+// file under the one above it, a chapter type such as {.preface} may
+// follow the entry, and lines starting with "#" are skipped.
+func readOrderFile(tree inputTree, order inputFile) ([]inputFile, error) {
+	content, err := order.read()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", order.Path, err)
+	}
+
+	summary := strings.EqualFold(filepath.Ext(order.Path), ".md")
+
+	var files []inputFile
+	var indents []int
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		entry := strings.TrimLeft(line, " \t")
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		var chapterType string
+		if m := orderTypeRe.FindStringSubmatch(entry); m != nil {
+			if chapterType, err = model.ParseChapterType(m[1]); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", order.Path, err)
+			}
+			line, entry = line[:len(line)-len(m[0])], entry[:len(entry)-len(m[0])]
+		}
+
+		target := entry
+		if summary {
+			m := summaryLinkRe.FindStringSubmatch(entry)
+			if m == nil || m[1] == "" {
+				// Headings, separators, and draft chapters without a file
+				continue
+			}
+			target, _, _ = strings.Cut(m[1], "#")
+		}
+
+		// Depth is the number of shallower indents still open
+		indent := indentWidth(line[:len(line)-len(entry)])
+		for len(indents) > 0 && indents[len(indents)-1] >= indent {
+			indents = indents[:len(indents)-1]
+		}
+		depth := len(indents)
+		indents = append(indents, indent)
+
+		file := tree.file(filepath.ToSlash(target))
+		if _, err := file.stat(); errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+			return nil, fmt.Errorf("%w: %s (listed in %s)", ErrFileNotFound, file.Path, order.Path)
+		}
+		file.Depth, file.Type = depth, chapterType
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// orderByWeight moves the Markdown files that declare a weight or order
+// in their front matter ahead of the others, lowest weight first. Files
+// with equal weights, and those without one, keep their order.
+func (c *Converter) orderByWeight(files []inputFile) []inputFile {
+	weights := make(map[string]float64)
+	for _, file := range files {
+		if c.detectFormat(file.Path, "") != parser.FormatMarkdown {
+			continue
+		}
+		content, err := file.read()
+		if err != nil {
+			continue // Reported when the file is parsed
+		}
+		if weight, ok := parser.FrontMatterWeight(content); ok {
+			weights[file.Path] = weight
+		}
+	}
+	if len(weights) == 0 {
+		return files
+	}
+
+	slices.SortStableFunc(files, func(a, b inputFile) int {
+		wa, oka := weights[a.Path]
+		wb, okb := weights[b.Path]
+		switch {
+		case oka && okb:
+			return cmp.Compare(wa, wb)
+		case oka:
+			return -1
+		case okb:
+			return 1
+		}
+		return 0
+	})
+	return files
+}
+
+// indentWidth returns the width of leading whitespace, counting tabs as
+// four spaces.
+func indentWidth(s string) int {
+	return len(s) + 3*strings.Count(s, "\t")
+}
+
+// nestTOCEntries appends added to entries depth levels down, under the
+// last entry at each level, shifting their levels by each level descended.
+func nestTOCEntries(entries, added []model.TOCEntry, depth int) []model.TOCEntry {
+	if depth == 0 || len(entries) == 0 {
+		return append(entries, added...)
+	}
+	last := &entries[len(entries)-1]
+	last.Children = nestTOCEntries(last.Children, shiftTOCLevels(added, 1), depth-1)
+	return entries
+}
+
+// shiftTOCLevels returns entries with their levels increased by n.
+func shiftTOCLevels(entries []model.TOCEntry, n int) []model.TOCEntry {
+	if n == 0 {
+		return entries
+	}
+	result := make([]model.TOCEntry, 0, len(entries))
+	for _, entry := range entries {
+		entry.Level += n
+		entry.Children = shiftTOCLevels(entry.Children, n)
+		result = append(result, entry)
+	}
+	return result
+}
+
+// matterRank orders the divisions of a book in the reading order.
+var matterRank = map[string]int{model.FrontMatter: 0, model.BodyMatter: 1, model.BackMatter: 2}
+
+// groupMatter moves front matter chapters before the body and back matter
+// chapters after it, keeping the order within each division, and reorders
+// the top-level table of contents entries to match. Chapters of no known
+// division stay with the body.
+func groupMatter(doc *model.Document) {
+	rank := func(c model.Chapter) int {
+		if r, ok := matterRank[c.Matter()]; ok {
+			return r
+		}
+		return matterRank[model.BodyMatter]
+	}
+	if slices.IsSortedFunc(doc.Chapters, func(a, b model.Chapter) int { return rank(a) - rank(b) }) {
+		return
+	}
+	slices.SortStableFunc(doc.Chapters, func(a, b model.Chapter) int { return rank(a) - rank(b) })
+
+	fileRank := make(map[string]int, len(doc.Chapters))
+	for i := range doc.Chapters {
+		doc.Chapters[i].Order = i
+		fileRank[doc.Chapters[i].FileName] = rank(doc.Chapters[i])
+	}
+	entryRank := func(entry model.TOCEntry) int {
+		file, _, _ := strings.Cut(entry.Href, "#")
+		if r, ok := fileRank[file]; ok {
+			return r
+		}
+		return matterRank[model.BodyMatter]
+	}
+	slices.SortStableFunc(doc.TOC.Entries, func(a, b model.TOCEntry) int { return entryRank(a) - entryRank(b) })
+}
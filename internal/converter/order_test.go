@@ -0,0 +1,166 @@
+package converter
+
+import (
+	"archive/zip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+}
+
+func TestExpandInputs_Summary(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"SUMMARY.md":      "# Summary\n\n[Preface](preface.md)\n\n- [Chapter 2](chapter2.md)\n  - [Details](part/details.md#top)\n- [Chapter 10](chapter10.md)\n- [Draft]()\n",
+		"preface.md":      "# Preface\n",
+		"chapter2.md":     "# Chapter 2\n",
+		"chapter10.md":    "# Chapter 10\n",
+		"part/details.md": "# Details\n",
+		"unlisted.md":     "# Unlisted\n",
+	})
+
+	files, err := New().expandInputs([]string{dir}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []inputFile{
+		{Path: filepath.Join(dir, "preface.md")},
+		{Path: filepath.Join(dir, "chapter2.md")},
+		{Path: filepath.Join(dir, "part", "details.md"), Depth: 1},
+		{Path: filepath.Join(dir, "chapter10.md")},
+	}, files)
+}
+
+func TestExpandInputs_IndexFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"index.txt":         "# order\nchapter2.md\n\tchapter2-notes.md\nchapter10.md\n",
+		"chapter2.md":       "# Two\n",
+		"chapter2-notes.md": "# Notes\n",
+		"chapter10.md":      "# Ten\n",
+	})
+
+	files, err := New().expandInputs([]string{dir}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, []inputFile{
+		{Path: filepath.Join(dir, "chapter2.md")},
+		{Path: filepath.Join(dir, "chapter2-notes.md"), Depth: 1},
+		{Path: filepath.Join(dir, "chapter10.md")},
+	}, files)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.txt"), []byte("missing.md\n"), 0o644))
+	_, err = New().expandInputs([]string{dir}, Options{})
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestExpandInputs_Weight(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md":   "# A\n",
+		"b.md":   "---\nweight: 20\n---\n# B\n",
+		"c.html": "<h1>C</h1>",
+		"d.md":   "---\norder: 5\n---\n# D\n",
+		"e.md":   "---\nweight: 20\n---\n# E\n",
+		"f.md":   "---\nweight: 7.5\n---\n# F\n",
+		"g.md":   "# G\n",
+	})
+
+	files, err := New().expandInputs([]string{dir}, Options{})
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file.Path))
+	}
+	assert.Equal(t, []string{"d.md", "f.md", "b.md", "e.md", "a.md", "c.html", "g.md"}, names)
+}
+
+// readEPUBEntry returns the content of the named file in the EPUB at path.
+func readEPUBEntry(t *testing.T, path, name string) string {
+	t.Helper()
+	archive, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer archive.Close()
+	rc, err := archive.Open(name)
+	require.NoError(t, err)
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestConverter_Convert_MatterTypes(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"index.txt":   "appendix.md {.appendix}\nchapter.md\npreface.md\n",
+		"appendix.md": "# Appendix\n",
+		"chapter.md":  "---\ntitle: Book\n---\n# Chapter\n",
+		"preface.md":  "---\ntype: Preface\n---\n# Preface\n",
+	})
+	output := filepath.Join(t.TempDir(), "book.epub")
+
+	_, err := New().Convert([]string{dir}, Options{OutputPath: output})
+	require.NoError(t, err)
+
+	opf := readEPUBEntry(t, output, "OEBPS/content.opf")
+	assert.Regexp(t, `(?s)idref="chapter-003".*idref="chapter-002".*idref="chapter-001"`, opf)
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/chapter-003.xhtml"), "<body epub:type=\"frontmatter\">\n<section epub:type=\"preface\">")
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/chapter-001.xhtml"), "<body epub:type=\"backmatter\">\n<section epub:type=\"appendix\">")
+	assert.Contains(t, readEPUBEntry(t, output, "OEBPS/content/chapter-002.xhtml"), "<body epub:type=\"bodymatter\">\n<section epub:type=\"chapter\">")
+
+	nav := readEPUBEntry(t, output, "OEBPS/nav.xhtml")
+	assert.Regexp(t, `(?s)chapter-003.xhtml#preface.*chapter-002.xhtml#chapter.*chapter-001.xhtml#appendix`, nav)
+	assert.Contains(t, nav, `<a epub:type="bodymatter" href="content/chapter-002.xhtml">`)
+	assert.Contains(t, nav, `<a epub:type="preface" href="content/chapter-003.xhtml">Preface</a>`)
+	assert.Contains(t, nav, `<a epub:type="appendix" href="content/chapter-001.xhtml">Appendix</a>`)
+
+	writeFiles(t, dir, map[string]string{"index.txt": "chapter.md {.sidebar}\n"})
+	_, err = New().Convert([]string{dir}, Options{OutputPath: output})
+	assert.ErrorContains(t, err, `unknown chapter type "sidebar"`)
+}
+
+func TestNestTOCEntries(t *testing.T) {
+	entries := []model.TOCEntry{{Title: "One", Level: 1}}
+	entries = nestTOCEntries(entries, []model.TOCEntry{{Title: "Sub", Level: 1}}, 1)
+	entries = nestTOCEntries(entries, []model.TOCEntry{{Title: "Two", Level: 1}}, 0)
+
+	require.Len(t, entries, 2)
+	require.Len(t, entries[0].Children, 1)
+	assert.Equal(t, "Sub", entries[0].Children[0].Title)
+	assert.Equal(t, 2, entries[0].Children[0].Level)
+	assert.Equal(t, "Two", entries[1].Title)
+}
+
+func TestConverter_Convert_EmptyInput(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md": "# One\n\nText.\n",
+		"b.md": "",
+		"c.md": "# Three\n\nText.\n",
+	})
+
+	result, err := New().Convert([]string{dir}, Options{OutputPath: filepath.Join(dir, "book.epub")})
+	require.NoError(t, err)
+
+	var gaps []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnReadingOrder {
+			gaps = append(gaps, w)
+		}
+	}
+	require.Len(t, gaps, 2)
+	assert.Equal(t, filepath.Join(dir, "b.md"), gaps[0].File)
+	assert.Contains(t, gaps[0].Message, "adds a blank page")
+	assert.Equal(t, "content/chapter-002.xhtml", gaps[1].File)
+}
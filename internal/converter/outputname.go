@@ -0,0 +1,197 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// maxNameFieldLength limits the bytes a template field adds to a file
+// name, keeping names within the 255 bytes file systems allow.
+const maxNameFieldLength = 80
+
+// templateSeparators are the characters that join fields in a template.
+const templateSeparators = "-_ "
+
+// unsafeNameChars are path separators and the characters Windows forbids
+// in file names.
+const unsafeNameChars = `/\:*?"<>|`
+
+// templateFieldRe matches a {field} of an output template.
+var templateFieldRe = regexp.MustCompile(`\{([a-z-]+)\}`)
+
+// templateFields are the fields of an output template, each read from the
+// book's metadata or first input file name.
+var templateFields = map[string]func(meta *model.Metadata, input string) string{
+	"title":     func(meta *model.Metadata, _ string) string { return meta.Title },
+	"author":    func(meta *model.Metadata, _ string) string { return meta.PrimaryAuthor() },
+	"authors":   func(meta *model.Metadata, _ string) string { return strings.Join(meta.Authors, ", ") },
+	"date":      func(meta *model.Metadata, _ string) string { return formatTemplateDate(meta, "2006-01-02") },
+	"year":      func(meta *model.Metadata, _ string) string { return formatTemplateDate(meta, "2006") },
+	"language":  func(meta *model.Metadata, _ string) string { return meta.Language },
+	"publisher": func(meta *model.Metadata, _ string) string { return meta.Publisher },
+	"series": func(meta *model.Metadata, _ string) string {
+		series, _ := meta.Series()
+		return series.Name
+	},
+	"series-index": func(meta *model.Metadata, _ string) string {
+		series, _ := meta.Series()
+		return series.Position
+	},
+	"isbn": func(meta *model.Metadata, _ string) string {
+		id, _ := meta.IdentifierByScheme(model.SchemeISBN)
+		return strings.TrimPrefix(id.Value, "urn:isbn:")
+	},
+	"input": func(_ *model.Metadata, input string) string { return input },
+}
+
+// formatTemplateDate formats the publication date, or returns "" if the
+// book has none.
+func formatTemplateDate(meta *model.Metadata, layout string) string {
+	if meta.Date.IsZero() {
+		return ""
+	}
+	return meta.Date.Format(layout)
+}
+
+// TemplateFields returns the names of the fields an output template can
+// use, such as "title" for {title}.
+func TemplateFields() []string {
+	return slices.Sorted(maps.Keys(templateFields))
+}
+
+// CheckOutputTemplate checks that an output template, such as
+// "{title}-{author}-{date}.epub", uses only known fields.
+func CheckOutputTemplate(template string) error {
+	if strings.TrimSpace(template) == "" {
+		return fmt.Errorf("output template is empty")
+	}
+	for _, m := range templateFieldRe.FindAllStringSubmatch(template, -1) {
+		if _, ok := templateFields[m[1]]; !ok {
+			return fmt.Errorf("unknown field {%s} in output template; use %s", m[1], "{"+strings.Join(TemplateFields(), "}, {")+"}")
+		}
+	}
+	return nil
+}
+
+// expandOutputTemplate returns the output path template names for a book
+// with metadata meta whose first input is named input. Field values are
+// made safe for file names; a field with no value is dropped with the
+// separator before it, so "{title}-{author}.epub" gives "Title.epub" for
+// a book without authors, and a directory named by an empty field alone
+// is dropped.
+func expandOutputTemplate(template string, meta *model.Metadata, input string) (string, error) {
+	if err := CheckOutputTemplate(template); err != nil {
+		return "", err
+	}
+
+	var expanded string
+	trimNext := "" // A field was empty, so these characters after it go
+	last := 0
+	for _, m := range templateFieldRe.FindAllStringSubmatchIndex(template, -1) {
+		literal := strings.TrimLeft(template[last:m[0]], trimNext)
+		last = m[1]
+
+		value := safeFileName(templateFields[template[m[2]:m[3]]](meta, input))
+		trimNext = ""
+		if value == "" {
+			// Drop the separator before the empty field, or else the one after it
+			trimmed := strings.TrimRight(literal, templateSeparators)
+			switch start := expanded + trimmed; {
+			case start == "" || strings.HasSuffix(start, "/"):
+				// The field began a path element, which goes if it is all there is
+				literal = trimmed
+				trimNext = templateSeparators + "/"
+			case trimmed != literal:
+				literal = trimmed
+			default:
+				trimNext = templateSeparators
+			}
+		}
+		expanded += literal + value
+	}
+	expanded += strings.TrimLeft(template[last:], trimNext)
+
+	name := filepath.FromSlash(expanded)
+	if stem := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)); strings.Trim(stem, templateSeparators) == "" {
+		return "", fmt.Errorf("output template %q gives an empty file name for this book", template)
+	}
+	return name, nil
+}
+
+// safeFileName makes a template field value safe as part of a file name:
+// path separators, characters Windows forbids, and control characters
+// become "_", runs of spaces become one, leading dots and trailing dots
+// and spaces are removed, and the result is cut to maxNameFieldLength.
+func safeFileName(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	value = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(unsafeNameChars, r) || unicode.IsControl(r) {
+			return '_'
+		}
+		return r
+	}, value)
+	value = strings.TrimLeft(value, ". ")
+	if len(value) > maxNameFieldLength {
+		cut := maxNameFieldLength
+		for cut > 0 && !utf8.RuneStart(value[cut]) {
+			cut--
+		}
+		value = value[:cut]
+	}
+	return strings.TrimRight(value, ". ")
+}
+
+// outputNames records the output files a Converter named from a template,
+// and the book each was written for, so that different books whose
+// metadata names the same file are not written over one another.
+type outputNames struct {
+	mu    sync.Mutex
+	books map[string]string // Output path to book key
+}
+
+// newOutputNames creates an empty record of output names.
+func newOutputNames() *outputNames {
+	return &outputNames{books: make(map[string]string)}
+}
+
+// claim returns path for the book with key, or the first of path-2,
+// path-3, and so on not already written for another book.
+func (n *outputNames) claim(path, key string) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	path, _ = uniqueFileName(path, func(name string) bool {
+		book, ok := n.books[name]
+		return ok && book != key
+	})
+	n.books[path] = key
+	return path
+}
+
+// templatedOutputPath returns the output path opts.NameTemplate names
+// for doc, a book made from the inputs identified by key, whose first
+// input is named input. The metadata defaults are applied first, so the
+// name matches the book's metadata.
+func (c *Converter) templatedOutputPath(doc *model.Document, key, input string, opts Options) (string, error) {
+	doc.Metadata.EnsureDefaults()
+	path, err := expandOutputTemplate(opts.NameTemplate, &doc.Metadata, input)
+	if err != nil {
+		return "", err
+	}
+	return c.outputs.claim(path, key), nil
+}
@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestExpandOutputTemplate(t *testing.T) {
+	meta := &model.Metadata{
+		Title:       "Go: The Good Parts?",
+		Authors:     []string{"Jane Doe", "John Roe"},
+		Date:        time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+		Language:    "en",
+		Collections: []model.Collection{{Name: "Gophers", Type: model.CollectionSeries, Position: "2"}},
+	}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{title}-{author}-{date}.epub", "Go_ The Good Parts_-Jane Doe-2025-03-14.epub"},
+		{"{series}/{series-index} {title}.epub", "Gophers/2 Go_ The Good Parts_.epub"},
+		{"{authors} ({year}).kepub.epub", "Jane Doe, John Roe (2025).kepub.epub"},
+		{"{input}_{language}.epub", "notes_en.epub"},
+		{"{title}-{isbn}.epub", "Go_ The Good Parts_.epub"},
+		{"{isbn}-{title}.epub", "Go_ The Good Parts_.epub"},
+		{"{publisher}/{title}.epub", "Go_ The Good Parts_.epub"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := expandOutputTemplate(tt.template, meta, "notes")
+			require.NoError(t, err)
+			assert.Equal(t, filepath.FromSlash(tt.want), got)
+		})
+	}
+
+	_, err := expandOutputTemplate("{isbn}.epub", meta, "notes")
+	assert.ErrorContains(t, err, "empty file name")
+	_, err = expandOutputTemplate("{subtitle}.epub", meta, "notes")
+	assert.ErrorContains(t, err, "unknown field {subtitle}")
+}
+
+func TestSafeFileName(t *testing.T) {
+	assert.Equal(t, "a_b_c_d", safeFileName(`a/b\c:d`))
+	assert.Equal(t, "Tab and new line", safeFileName("Tab\tand  new\nline"))
+	assert.Equal(t, "hidden", safeFileName("..hidden..."))
+	assert.Equal(t, "Vol_1", safeFileName("Vol\x001"))
+
+	long := safeFileName(strings.Repeat("é", 100))
+	assert.LessOrEqual(t, len(long), maxNameFieldLength)
+	assert.Equal(t, strings.Repeat("é", maxNameFieldLength/2), long)
+}
+
+func TestConverter_Convert_NameTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"one.md":   "---\ntitle: Same Title\nauthor: Jane Doe\n---\n# One\n",
+		"two.md":   "---\ntitle: Same Title\nauthor: Jane Doe\n---\n# Two\n",
+		"three.md": "---\ntitle: \"Who/What?\"\n---\n# Three\n",
+	})
+	opts := Options{NameTemplate: filepath.Join(dir, "{title}-{author}.epub")}
+
+	conv := New()
+	first, err := conv.Convert([]string{filepath.Join(dir, "one.md")}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Same Title-Jane Doe.epub"), first.OutputPath)
+	assert.FileExists(t, first.OutputPath)
+
+	// A different book with the same metadata is not written over the first
+	second, err := conv.Convert([]string{filepath.Join(dir, "two.md")}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Same Title-Jane Doe-2.epub"), second.OutputPath)
+
+	// Rebuilding a book writes over its own output
+	again, err := conv.Convert([]string{filepath.Join(dir, "one.md")}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, first.OutputPath, again.OutputPath)
+
+	third, err := conv.Convert([]string{filepath.Join(dir, "three.md")}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Who_What_-Dau Quang Thanh.epub"), third.OutputPath)
+}
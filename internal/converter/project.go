@@ -0,0 +1,273 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// ErrInvalidProject is returned for project files that do not describe a
+// list of books.
+var ErrInvalidProject = errors.New("invalid project file")
+
+// ProjectFileName is the project file looked for in a directory.
+const ProjectFileName = "toepub.yaml"
+
+// BookFileName is the project file of a single book written by Scaffold,
+// looked for in a directory without a ProjectFileName.
+const BookFileName = "book.yaml"
+
+// defaultProjectCache is the parse cache of a project, relative to the
+// project file, shared by its books.
+const defaultProjectCache = ".toepub-cache"
+
+// Project is a workspace of books built together. The books share the
+// project's stylesheets, templates, metadata, and parse cache. Paths are
+// relative to the project file.
+type Project struct {
+	Dir         string                 `yaml:"-"`            // Directory of the project file
+	Output      string                 `yaml:"output"`       // Directory the books are written to; empty means Dir
+	CacheDir    string                 `yaml:"cache-dir"`    // Parse cache shared by the books; empty means .toepub-cache
+	CSS         Paths                  `yaml:"css"`          // Stylesheets linked from every chapter of every book
+	TemplateDir string                 `yaml:"template-dir"` // Custom templates for every book
+	OutputName  string                 `yaml:"output-name"`  // Output template naming books without an output from their metadata
+	Metadata    map[string]interface{} `yaml:"metadata"`     // Metadata for every book, with the keys of Markdown front matter
+	Books       []ProjectBook          `yaml:"books"`
+}
+
+// ProjectBook is one book of a project.
+type ProjectBook struct {
+	Name      string                 `yaml:"name"`      // Selects the book to build; defaults to the base name of its output
+	Input     Paths                  `yaml:"input"`     // Files and directories, converted in order
+	Output    string                 `yaml:"output"`    // EPUB path within the project output directory; defaults to the name, or the first input's
+	Cover     string                 `yaml:"cover"`     // Cover image
+	CSS       Paths                  `yaml:"css"`       // Stylesheets linked after the project's
+	Recursive bool                   `yaml:"recursive"` // Include files in subdirectories of directory inputs
+	Metadata  map[string]interface{} `yaml:"metadata"`  // Metadata overriding the project's, key by key
+}
+
+// Paths is a list of paths, written in YAML as a list or a single string.
+type Paths []string
+
+// UnmarshalYAML reads a single path or a list of paths.
+func (p *Paths) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*p = Paths{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*p = list
+	return nil
+}
+
+// FindProject returns the path of the project file in dir: its
+// toepub.yaml, or else its book.yaml. With neither, it returns the path the
+// toepub.yaml would have.
+func FindProject(dir string) string {
+	for _, name := range []string{ProjectFileName, BookFileName} {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return filepath.Join(dir, ProjectFileName)
+}
+
+// LoadProject reads the project file at path, or the one FindProject finds
+// in path if it is a directory. Books are checked for inputs and named, and no two
+// books may be written to the same file. Books named by the project's
+// output-name template are given different names when built.
+func LoadProject(path string) (*Project, error) {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		path = FindProject(path)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrFileNotFound, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+
+	var p Project
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidProject, path, err)
+	}
+	if len(p.Books) == 0 {
+		return nil, fmt.Errorf("%w: %s lists no books", ErrInvalidProject, path)
+	}
+	p.Dir = filepath.Dir(path)
+	if p.OutputName != "" {
+		if err := CheckOutputTemplate(p.OutputName); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidProject, path, err)
+		}
+	}
+
+	names := make(map[string]bool, len(p.Books))
+	outputs := make(map[string]string, len(p.Books))
+	for i := range p.Books {
+		book := &p.Books[i]
+		if len(book.Input) == 0 {
+			return nil, fmt.Errorf("%w: book %d has no input", ErrInvalidProject, i+1)
+		}
+		templated := book.Output == "" && p.OutputName != ""
+		if book.Output == "" && !templated {
+			base := book.Name
+			if base == "" {
+				base = filepath.Base(filepath.Clean(book.Input[0]))
+				base = strings.TrimSuffix(base, filepath.Ext(base))
+			}
+			book.Output = base + ".epub"
+		}
+		if book.Name == "" {
+			base := book.Output
+			if templated {
+				base = filepath.Clean(book.Input[0])
+			}
+			base = filepath.Base(base)
+			book.Name = strings.TrimSuffix(base, filepath.Ext(base))
+		}
+		if names[book.Name] {
+			return nil, fmt.Errorf("%w: more than one book is named %q", ErrInvalidProject, book.Name)
+		}
+		names[book.Name] = true
+		if templated {
+			continue
+		}
+
+		output := p.OutputPath(*book)
+		if other, ok := outputs[output]; ok {
+			return nil, fmt.Errorf("%w: books %q and %q are both written to %s", ErrInvalidProject, other, book.Name, output)
+		}
+		outputs[output] = book.Name
+	}
+	return &p, nil
+}
+
+// path resolves a path of the project file against its directory.
+func (p *Project) path(name string) string {
+	if name == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(p.Dir, name)
+}
+
+// Inputs returns the input paths of book.
+func (p *Project) Inputs(book ProjectBook) []string {
+	inputs := make([]string, 0, len(book.Input))
+	for _, input := range book.Input {
+		inputs = append(inputs, p.path(input))
+	}
+	return inputs
+}
+
+// OutputPath returns the path book is written to, or "" if the project's
+// output-name template names it when built.
+func (p *Project) OutputPath(book ProjectBook) string {
+	if book.Output == "" {
+		return ""
+	}
+	if filepath.IsAbs(book.Output) {
+		return book.Output
+	}
+	return p.path(filepath.Join(p.Output, book.Output))
+}
+
+// Book returns the book with the given name.
+func (p *Project) Book(name string) (ProjectBook, bool) {
+	i := slices.IndexFunc(p.Books, func(book ProjectBook) bool { return book.Name == name })
+	if i < 0 {
+		return ProjectBook{}, false
+	}
+	return p.Books[i], true
+}
+
+// BookOptions returns the options converting book: opts with the output
+// path, shared and book stylesheets, templates, cover, and metadata of the
+// project. The parse cache is shared by the project's books unless opts
+// sets its own.
+func (p *Project) BookOptions(book ProjectBook, opts Options) Options {
+	opts.OutputPath = p.OutputPath(book)
+	if opts.OutputPath == "" {
+		opts.NameTemplate = p.OutputName
+		if !filepath.IsAbs(p.OutputName) {
+			opts.NameTemplate = p.path(filepath.Join(p.Output, p.OutputName))
+		}
+	}
+	opts.Recursive = opts.Recursive || book.Recursive
+	if opts.CacheDir == "" {
+		opts.CacheDir = p.path(p.CacheDir)
+		if p.CacheDir == "" {
+			opts.CacheDir = filepath.Join(p.Dir, defaultProjectCache)
+		}
+	}
+	if p.TemplateDir != "" {
+		opts.TemplateDir = p.path(p.TemplateDir)
+	}
+
+	opts.Stylesheets = slices.Clone(opts.Stylesheets)
+	for _, css := range slices.Concat(p.CSS, book.CSS) {
+		opts.Stylesheets = append(opts.Stylesheets, p.path(css))
+	}
+
+	meta := maps.Clone(p.Metadata)
+	if meta == nil {
+		meta = make(map[string]interface{}, len(book.Metadata))
+	}
+	maps.Copy(meta, book.Metadata)
+	override := parser.FrontMatterMetadata(meta)
+	if book.Cover != "" {
+		override.CoverImage = p.path(book.Cover)
+	}
+	override.Merge(opts.CLIMetadata)
+	opts.CLIMetadata = override
+	return opts
+}
+
+// BuildProject converts the books of p named in names, or all of its
+// books if names is empty, one after another with opts as the base
+// options of each. A book that fails does not stop the others; its result
+// carries the error in Error. Unknown names fail before any book is built.
+func (c *Converter) BuildProject(p *Project, names []string, opts Options) ([]*model.ConversionResult, error) {
+	books := p.Books
+	if len(names) > 0 {
+		books = make([]ProjectBook, 0, len(names))
+		for _, name := range names {
+			book, ok := p.Book(name)
+			if !ok {
+				return nil, fmt.Errorf("%w: no book is named %q", ErrInvalidProject, name)
+			}
+			books = append(books, book)
+		}
+	}
+
+	results := make([]*model.ConversionResult, 0, len(books))
+	for _, book := range books {
+		result, err := c.Convert(p.Inputs(book), p.BookOptions(book, opts))
+		if err != nil {
+			// Books epubcheck found errors in are still written
+			result.Error = fmt.Errorf("book %q: %w", book.Name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
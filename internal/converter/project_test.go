@@ -0,0 +1,125 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"toepub.yaml": "output: dist\ncss: shared/book.css\nmetadata:\n  author: Jane Doe\n  publisher: ACME\n" +
+			"books:\n  - input: vol1/\n  - name: two\n    input: [vol2, extra.md]\n    output: volume-2.epub\n" +
+			"    css: [vol2/two.css]\n    cover: vol2/cover.jpg\n    metadata:\n      title: Volume Two\n      author: John Roe\n",
+	})
+
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+	require.Len(t, project.Books, 2)
+	assert.Equal(t, "vol1", project.Books[0].Name)
+	assert.Equal(t, filepath.Join(dir, "dist", "vol1.epub"), project.OutputPath(project.Books[0]))
+
+	book, ok := project.Book("two")
+	require.True(t, ok)
+	assert.Equal(t, []string{filepath.Join(dir, "vol2"), filepath.Join(dir, "extra.md")}, project.Inputs(book))
+
+	opts := project.BookOptions(book, Options{})
+	assert.Equal(t, filepath.Join(dir, "dist", "volume-2.epub"), opts.OutputPath)
+	assert.Equal(t, filepath.Join(dir, defaultProjectCache), opts.CacheDir)
+	assert.Equal(t, []string{filepath.Join(dir, "shared", "book.css"), filepath.Join(dir, "vol2", "two.css")}, opts.Stylesheets)
+	assert.Equal(t, "Volume Two", opts.CLIMetadata.Title)
+	assert.Equal(t, []string{"John Roe"}, opts.CLIMetadata.Authors)
+	assert.Equal(t, "ACME", opts.CLIMetadata.Publisher)
+	assert.Equal(t, filepath.Join(dir, "vol2", "cover.jpg"), opts.CLIMetadata.CoverImage)
+}
+
+func TestFindProject(t *testing.T) {
+	dir := t.TempDir()
+	assert.Equal(t, filepath.Join(dir, "toepub.yaml"), FindProject(dir))
+
+	writeFiles(t, dir, map[string]string{"book.yaml": "books:\n  - input: a.md\n"})
+	assert.Equal(t, filepath.Join(dir, "book.yaml"), FindProject(dir))
+
+	writeFiles(t, dir, map[string]string{"toepub.yaml": "books:\n  - input: b.md\n"})
+	assert.Equal(t, filepath.Join(dir, "toepub.yaml"), FindProject(dir))
+}
+
+func TestLoadProject_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no books":       "output: dist\n",
+		"no input":       "books:\n  - name: one\n",
+		"same name":      "books:\n  - input: a.md\n  - input: b/a.md\n",
+		"same output":    "books:\n  - input: a.md\n  - input: b.md\n    output: a.epub\n",
+		"malformed YAML": "books: [\n",
+	}
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFiles(t, dir, map[string]string{"toepub.yaml": config})
+			_, err := LoadProject(filepath.Join(dir, "toepub.yaml"))
+			assert.ErrorIs(t, err, ErrInvalidProject)
+		})
+	}
+
+	_, err := LoadProject(filepath.Join(t.TempDir(), "toepub.yaml"))
+	assert.ErrorIs(t, err, ErrFileNotFound)
+}
+
+func TestConverter_BuildProject(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"toepub.yaml":     "css: shared/book.css\nmetadata:\n  title: Series\nbooks:\n  - input: one\n  - input: two\n  - input: missing\n",
+		"shared/book.css": "p { margin: 0; }\n",
+		"one/a.md":        "# One\n",
+		"two/a.md":        "---\ncss: two.css\n---\n# Two\n",
+		"two/two.css":     "h1 { color: red; }\n",
+	})
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+
+	_, err = New().BuildProject(project, []string{"three"}, Options{})
+	assert.ErrorIs(t, err, ErrInvalidProject)
+
+	results, err := New().BuildProject(project, nil, Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Success)
+	assert.True(t, results[1].Success)
+	assert.ErrorIs(t, results[2].Error, ErrFileNotFound)
+	assert.Contains(t, results[2].Error.Error(), `book "missing"`)
+
+	chapter := readEPUBEntry(t, filepath.Join(dir, "two.epub"), "OEBPS/content/chapter-001.xhtml")
+	assert.Regexp(t, `(?s)href="../styles/book.css".*href="../styles/two.css"`, chapter)
+	assert.Contains(t, readEPUBEntry(t, filepath.Join(dir, "one.epub"), "OEBPS/styles/book.css"), "margin: 0")
+	assert.DirExists(t, filepath.Join(dir, defaultProjectCache))
+}
+
+func TestConverter_BuildProject_OutputName(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"toepub.yaml": "output: dist\noutput-name: \"{series}/{series-index}-{title}.epub\"\n" +
+			"books:\n  - input: one.md\n    metadata: {series: Saga, series-index: 1}\n  - input: two.md\n  - input: three.md\n    output: extra.epub\n",
+		"one.md":   "---\ntitle: Dawn\n---\n# One\n",
+		"two.md":   "---\ntitle: Dusk\n---\n# Two\n",
+		"three.md": "# Three\n",
+	})
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "one", project.Books[0].Name)
+	assert.Empty(t, project.OutputPath(project.Books[0]))
+
+	results, err := New().BuildProject(project, nil, Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, filepath.Join(dir, "dist", "Saga", "1-Dawn.epub"), results[0].OutputPath)
+	assert.Equal(t, filepath.Join(dir, "dist", "Dusk.epub"), results[1].OutputPath)
+	assert.Equal(t, filepath.Join(dir, "dist", "extra.epub"), results[2].OutputPath)
+	assert.FileExists(t, results[0].OutputPath)
+
+	writeFiles(t, dir, map[string]string{"toepub.yaml": "output-name: \"{subtitle}.epub\"\nbooks:\n  - input: one.md\n"})
+	_, err = LoadProject(dir)
+	assert.ErrorIs(t, err, ErrInvalidProject)
+}
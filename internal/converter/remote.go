@@ -0,0 +1,172 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ErrRemoteDenied is returned for remote images on hosts the
+// RemoteImages policy does not allow.
+var ErrRemoteDenied = errors.New("remote host not allowed")
+
+// Remote image download defaults.
+const (
+	defaultRemoteTimeout = 30 * time.Second
+	defaultRemoteMaxSize = 20 << 20
+)
+
+// remoteImgRe matches img elements whose src is an http or https URL.
+var remoteImgRe = regexp.MustCompile(`(<img\b[^>]*?\ssrc\s*=\s*["'])(https?://[^"']+)(["'])`)
+
+// RemoteImages configures downloading images that chapters reference by
+// URL, so the book works offline. Without it, such images stay linked.
+type RemoteImages struct {
+	Download bool          // Embed remote images instead of linking them
+	Timeout  time.Duration // Per image; 0 means 30 seconds
+	MaxSize  int64         // Largest image in bytes; 0 means 20 MB
+	Allow    []string      // Hosts to download from, matching subdomains; empty allows all
+	Deny     []string      // Hosts never downloaded from, matching subdomains; wins over Allow
+	Client   *http.Client  // nil uses a client with Timeout
+}
+
+// allowed returns ErrRemoteDenied unless the policy allows host.
+func (r RemoteImages) allowed(host string) error {
+	host = strings.ToLower(host)
+	for _, d := range r.Deny {
+		if matchHost(host, d) {
+			return fmt.Errorf("%w: %s is denied", ErrRemoteDenied, host)
+		}
+	}
+	if len(r.Allow) == 0 {
+		return nil
+	}
+	for _, a := range r.Allow {
+		if matchHost(host, a) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is not in the allowed hosts", ErrRemoteDenied, host)
+}
+
+// fetcher returns the HTTP fetcher for the policy's limits.
+func (r RemoteImages) fetcher() HTTPFetcher {
+	client := r.Client
+	if client == nil {
+		timeout := r.Timeout
+		if timeout <= 0 {
+			timeout = defaultRemoteTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+	maxSize := r.MaxSize
+	if maxSize <= 0 {
+		maxSize = defaultRemoteMaxSize
+	}
+	return HTTPFetcher{Client: client, MaxSize: maxSize}
+}
+
+// matchHost reports whether host is domain or one of its subdomains.
+func matchHost(host, domain string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "."))
+	return domain != "" && (host == domain || strings.HasSuffix(host, "."+domain))
+}
+
+// DownloadImage downloads and validates a remote image under the policy.
+func (h *ImageHandler) DownloadImage(rawURL string, policy RemoteImages) (*model.Resource, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid image URL %s", ErrFetch, rawURL)
+	}
+	if err := policy.allowed(u.Hostname()); err != nil {
+		return nil, err
+	}
+	return h.FetchImage(policy.fetcher(), rawURL)
+}
+
+// embedRemoteImages handles images that chapters reference by URL. With
+// downloading enabled they become resources and their src attributes
+// point into the book; images that fail stay linked with a warning.
+// Otherwise each linked image is reported as a note.
+func (c *Converter) embedRemoteImages(doc *model.Document, rep *reporter, opts Options) {
+	log := opts.logger()
+
+	used := make(map[string]bool, len(doc.Resources))
+	for _, res := range doc.Resources {
+		used[res.FileName] = true
+	}
+	embedded := make(map[string]string) // URL to href, "" when it stays linked
+
+	for i := range doc.Chapters {
+		doc.Chapters[i].Content = remoteImgRe.ReplaceAllStringFunc(doc.Chapters[i].Content, func(match string) string {
+			m := remoteImgRe.FindStringSubmatch(match)
+			src := html.UnescapeString(m[2])
+
+			href, seen := embedded[src]
+			if !seen {
+				href = c.embedRemoteImage(doc, src, used, rep, opts)
+				embedded[src] = href
+				if href != "" {
+					log.Debug("downloaded image", "stage", "images", "url", src, "file", href)
+				}
+			}
+			if href == "" {
+				return match
+			}
+			return m[1] + "../" + href + m[3]
+		})
+	}
+}
+
+// embedRemoteImage downloads src into a new resource and returns its file
+// name, or reports the image and returns "" if it stays linked.
+func (c *Converter) embedRemoteImage(doc *model.Document, src string, used map[string]bool, rep *reporter, opts Options) string {
+	if !opts.RemoteImages.Download {
+		rep.warn(model.Warning{
+			Code:     model.WarnRemoteResource,
+			Severity: model.SeverityInfo,
+			File:     src,
+			Message:  fmt.Sprintf("Image %s: linked, not embedded", src),
+		})
+		return ""
+	}
+
+	res, err := c.images(opts).DownloadImage(src, opts.RemoteImages)
+	if err != nil {
+		rep.warn(model.Warning{
+			Code:    model.WarnMissingImage,
+			File:    src,
+			Message: fmt.Sprintf("Image %s: %s; left linked", src, err),
+		})
+		return ""
+	}
+
+	// Name the file after the URL path, unique among the book's resources
+	stem := strings.TrimSuffix(path.Base(res.FileName), path.Ext(res.FileName))
+	if stem == "" || stem == "." || stem == "/" {
+		stem = "image"
+	}
+	ext := extensionFromMediaType(res.MediaType)
+	name, _ := uniqueFileName("images/"+stem+ext, func(name string) bool { return used[name] })
+	used[name] = true
+
+	res.FileName = name
+	res.ID = "img-" + sanitizeID(strings.TrimSuffix(path.Base(name), ext)) + "-remote"
+	doc.AddResource(*res)
+	return name
+}
@@ -0,0 +1,84 @@
+package converter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestRemoteImages_Allowed(t *testing.T) {
+	policy := RemoteImages{Allow: []string{"example.com"}, Deny: []string{"ads.example.com"}}
+
+	assert.NoError(t, policy.allowed("example.com"))
+	assert.NoError(t, policy.allowed("CDN.Example.com"))
+	assert.ErrorIs(t, policy.allowed("ads.example.com"), ErrRemoteDenied)
+	assert.ErrorIs(t, policy.allowed("tracker.ads.example.com"), ErrRemoteDenied)
+	assert.ErrorIs(t, policy.allowed("notexample.com"), ErrRemoteDenied)
+	assert.NoError(t, RemoteImages{}.allowed("anywhere.org"))
+}
+
+func TestConverter_EmbedRemoteImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/img/photo.png" {
+			// Distinct bytes per URL, so the images are not deduplicated
+			_, _ = w.Write(append(append([]byte{}, pngHeader...), r.URL.RawQuery...))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"book.md": "# One\n\n![a](" + srv.URL + "/img/photo.png) ![again](" + srv.URL + "/img/photo.png?size=2)\n\n" +
+			"![same](" + srv.URL + "/img/photo.png) ![gone](" + srv.URL + "/missing.png)\n",
+	})
+
+	var events []model.Warning
+	opts := Options{
+		OutputPath:   filepath.Join(dir, "book.epub"),
+		RemoteImages: RemoteImages{Download: true},
+		Events:       EventFunc(func(e model.Warning) { events = append(events, e) }),
+	}
+	var doc *model.Document
+	opts.Hooks = []DocumentHook{func(d *model.Document) error { doc = d; return nil }}
+
+	result, err := New().Convert([]string{filepath.Join(dir, "book.md")}, opts)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+
+	// Each URL is downloaded once, under a unique name
+	var names []string
+	for _, res := range doc.Resources {
+		names = append(names, res.FileName)
+	}
+	assert.Equal(t, []string{"images/photo.png", "images/photo-2.png"}, names)
+	content := doc.Chapters[0].Content
+	assert.Equal(t, 2, strings.Count(content, `src="../images/photo.png"`))
+	assert.Contains(t, content, `src="../images/photo-2.png"`)
+
+	// Failed downloads stay linked with a warning
+	assert.Contains(t, content, srv.URL+"/missing.png")
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnMissingImage, events[0].Code)
+}
+
+func TestConverter_EmbedRemoteImages_Denied(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{Content: `<p><img src="https://ads.example.com/a.png" alt=""/></p>`})
+
+	result := &model.ConversionResult{}
+	policy := RemoteImages{Download: true, Deny: []string{"example.com"}}
+	New().embedRemoteImages(doc, newReporter(result, Options{}), Options{RemoteImages: policy})
+
+	assert.Empty(t, doc.Resources)
+	require.Len(t, result.Warnings, 1)
+	assert.Contains(t, result.Warnings[0].Message, "not allowed")
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestConverter_Convert_FixedLayoutFrontMatter(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"01.md": "---\ntitle: Picture Book\nrendition:\n  layout: fixed\n  orientation: landscape\n  spread: none\n  viewport: 800x600\n" +
+			"page-spread: right\n---\n# Page One\n",
+		"02.md": "---\npage-spread: left\n---\n# Page Two\n",
+		"03.md": "---\npage-spread: top\n---\n# Page Three\n",
+	})
+	output := filepath.Join(dir, "book.epub")
+
+	result, err := New().Convert([]string{filepath.Join(dir, "01.md"), filepath.Join(dir, "02.md"), filepath.Join(dir, "03.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+
+	opf := readEPUBEntry(t, output, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<meta property="rendition:layout">pre-paginated</meta>`)
+	assert.Contains(t, opf, `<meta property="rendition:orientation">landscape</meta>`)
+	assert.Contains(t, opf, `<meta property="rendition:spread">none</meta>`)
+	assert.Contains(t, opf, `<itemref idref="chapter-001" properties="page-spread-right"></itemref>`)
+	assert.Contains(t, opf, `<itemref idref="chapter-002" properties="page-spread-left"></itemref>`)
+	assert.Contains(t, opf, `<itemref idref="chapter-003"></itemref>`)
+
+	page := readEPUBEntry(t, output, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, page, `<meta name="viewport" content="width=800, height=600"/>`)
+
+	var invalid []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnInvalidMetadata {
+			invalid = append(invalid, w)
+		}
+	}
+	require.Len(t, invalid, 1)
+	assert.Contains(t, invalid[0].Message, `"top"`)
+
+	// Orientations other than auto, landscape, and portrait are left out
+	writeFiles(t, dir, map[string]string{"04.md": "---\nrendition:\n  orientation: sideways\n---\n# Page\n"})
+	result, err = New().Convert([]string{filepath.Join(dir, "04.md")}, Options{OutputPath: output})
+	require.NoError(t, err)
+	assert.NotContains(t, readEPUBEntry(t, output, "OEBPS/content.opf"), "rendition:orientation")
+	require.Len(t, result.Warnings, 1)
+	assert.Equal(t, model.WarnInvalidMetadata, result.Warnings[0].Code)
+}
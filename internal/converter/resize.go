@@ -0,0 +1,142 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// ImageSize is a maximum image size in pixels. A zero Width or Height
+// leaves that dimension unbounded.
+type ImageSize struct {
+	Width  int
+	Height int
+}
+
+// IsZero reports whether s sets no limit.
+func (s ImageSize) IsZero() bool {
+	return s.Width <= 0 && s.Height <= 0
+}
+
+// String formats s as WIDTHxHEIGHT.
+func (s ImageSize) String() string {
+	return strconv.Itoa(s.Width) + "x" + strconv.Itoa(s.Height)
+}
+
+// ParseImageSize parses a --max-image-size value such as "1600x2400".
+// Either dimension may be 0 to leave it unbounded.
+func ParseImageSize(s string) (ImageSize, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ImageSize{}, nil
+	}
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	width, errW := strconv.Atoi(w)
+	height, errH := strconv.Atoi(h)
+	if !ok || errW != nil || errH != nil || width < 0 || height < 0 {
+		return ImageSize{}, fmt.Errorf("invalid image size %q: must be WIDTHxHEIGHT, such as 1600x2400", s)
+	}
+	return ImageSize{Width: width, Height: height}, nil
+}
+
+// fit returns the size of a width by height image scaled down to fit s,
+// keeping its aspect ratio, and whether it needs scaling.
+func (s ImageSize) fit(width, height int) (int, int, bool) {
+	scale := 1.0
+	if s.Width > 0 && width > s.Width {
+		scale = float64(s.Width) / float64(width)
+	}
+	if s.Height > 0 && height > s.Height && float64(s.Height)/float64(height) < scale {
+		scale = float64(s.Height) / float64(height)
+	}
+	if scale == 1.0 {
+		return width, height, false
+	}
+	return scaled(width, scale), scaled(height, scale), true
+}
+
+// scaled returns n pixels scaled by scale, rounded and at least 1.
+func scaled(n int, scale float64) int {
+	if m := int(float64(n)*scale + 0.5); m > 1 {
+		return m
+	}
+	return 1
+}
+
+// adjustable reports whether images of mediaType are downscaled and made
+// grayscale. GIFs may be animated and SVGs have no pixels, so both are left
+// alone, as are WebP and AVIF images kept as-is, which cannot be re-encoded.
+func adjustable(mediaType string) bool {
+	return mediaType == "image/jpeg" || mediaType == "image/png"
+}
+
+// needsAdjust reports whether the image read from r is to be downscaled or
+// made grayscale, reading only its header.
+func (h *ImageHandler) needsAdjust(r io.Reader, mediaType string) bool {
+	if !adjustable(mediaType) || (h.MaxSize.IsZero() && !h.Grayscale) {
+		return false
+	}
+	if h.Grayscale {
+		return true
+	}
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return false
+	}
+	_, _, scale := h.MaxSize.fit(cfg.Width, cfg.Height)
+	return scale
+}
+
+// adjust downscales a JPEG or PNG image that is larger than h.MaxSize to
+// fit it and, with h.Grayscale, converts it to grayscale, re-encoding it in
+// the same format. Other images are returned unchanged.
+func (h *ImageHandler) adjust(data []byte, mediaType string) ([]byte, error) {
+	if !h.needsAdjust(bytes.NewReader(data), mediaType) {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding image: %w", err)
+	}
+
+	bounds := img.Bounds()
+	if width, height, scale := h.MaxSize.fit(bounds.Dx(), bounds.Dy()); scale {
+		var dst draw.Image
+		if mediaType == "image/jpeg" {
+			dst = image.NewRGBA(image.Rect(0, 0, width, height))
+		} else {
+			dst = image.NewNRGBA(image.Rect(0, 0, width, height))
+		}
+		draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+		img = dst
+	}
+	if h.Grayscale {
+		img = grayscale(img, h.Contrast)
+	}
+
+	var buf bytes.Buffer
+	if mediaType == "image/jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encoding adjusted image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package converter
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageSize(t *testing.T) {
+	size, err := ParseImageSize("1600x2400")
+	require.NoError(t, err)
+	assert.Equal(t, ImageSize{Width: 1600, Height: 2400}, size)
+
+	size, err = ParseImageSize("1200X0")
+	require.NoError(t, err)
+	assert.Equal(t, ImageSize{Width: 1200}, size)
+
+	size, err = ParseImageSize("")
+	require.NoError(t, err)
+	assert.True(t, size.IsZero())
+
+	for _, bad := range []string{"1600", "x2400", "-1x10", "axb"} {
+		_, err := ParseImageSize(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestImageSize_Fit(t *testing.T) {
+	w, h, scale := ImageSize{Width: 1600, Height: 2400}.fit(3200, 2400)
+	assert.True(t, scale)
+	assert.Equal(t, []int{1600, 1200}, []int{w, h})
+
+	w, h, scale = ImageSize{Width: 1600, Height: 2400}.fit(1000, 4800)
+	assert.True(t, scale)
+	assert.Equal(t, []int{500, 2400}, []int{w, h})
+
+	_, _, scale = ImageSize{Width: 1600}.fit(1600, 9000)
+	assert.False(t, scale)
+}
+
+func TestImageHandler_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	var pngData, jpegData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, img))
+	require.NoError(t, jpeg.Encode(&jpegData, img, nil))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wide.png"), pngData.Bytes(), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "wide.jpg"), jpegData.Bytes(), 0o644))
+
+	h := &ImageHandler{MaxSize: ImageSize{Width: 100, Height: 100}}
+	for _, name := range []string{"wide.png", "wide.jpg"} {
+		res, err := h.ProbeImage(name, dir)
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Data, name)
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(res.Data))
+		require.NoError(t, err)
+		assert.Equal(t, []int{100, 50}, []int{cfg.Width, cfg.Height}, name)
+	}
+
+	// Images that fit are streamed from disk untouched
+	res, err := (&ImageHandler{MaxSize: ImageSize{Width: 1600, Height: 2400}}).ProbeImage("wide.png", dir)
+	require.NoError(t, err)
+	assert.Empty(t, res.Data)
+	assert.NotEmpty(t, res.SourcePath)
+}
@@ -0,0 +1,155 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// scaffoldProject is the project file of a new book. Its placeholders are
+// the book name, title, author, and language.
+const scaffoldProject = `# Build the book with "toepub build"; see the README for every key.
+css: styles/custom.css
+metadata:
+  author: %[3]s
+  language: %[4]s
+books:
+  - name: %[1]s
+    input: chapters/
+    cover: images/cover.png
+    metadata:
+      title: %[2]s
+`
+
+// scaffoldFile is a file of a new book, by its path in the project.
+type scaffoldFile struct {
+	name    string
+	content string
+}
+
+// scaffoldChapters are the starter chapters of a new book.
+var scaffoldChapters = []scaffoldFile{
+	{"01-introduction.md", `---
+type: introduction
+---
+
+# Introduction
+
+Write your book in Markdown, one file per chapter in this directory. Files
+are read in alphabetical order, or give one a ` + "`weight:`" + ` in its front
+matter to move it.
+
+Footnotes become pop-up notes in reading systems that support them.[^1]
+
+[^1]: Like this one.
+`},
+	{"02-first-chapter.md", `# The First Chapter
+
+Put images in the ` + "`images`" + ` directory and link them relative to the
+chapter:
+
+` + "```markdown\n![A description of the picture](../images/picture.png)\n```" + `
+
+Styles in ` + "`styles/custom.css`" + ` apply to every chapter.
+`},
+}
+
+// scaffoldCSS is the starter stylesheet of a new book.
+const scaffoldCSS = `/* Linked from every chapter, after the built-in stylesheet. */
+
+h1 {
+  text-align: center;
+}
+`
+
+// Scaffold creates a starter project for a new book in dir: a book.yaml
+// project file, Markdown chapters, a stylesheet, and a placeholder cover.
+// The title defaults to one made from the directory name. dir must not
+// exist or be empty. It returns the files created, relative to dir.
+func Scaffold(dir string, meta model.Metadata) ([]string, error) {
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%w: %s is not empty", ErrOutputNotWrite, dir)
+	}
+	for _, sub := range []string{"chapters", "images", "styles"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutputNotWrite, dir)
+		}
+	}
+
+	name := filepath.Base(filepath.Clean(dir))
+	if abs, err := filepath.Abs(dir); err == nil {
+		name = filepath.Base(abs) // A name for "."
+	}
+	if meta.Title == "" {
+		meta.Title = parser.TitleFromName(name)
+	}
+	author := "Your Name"
+	if len(meta.Authors) > 0 {
+		author = meta.Authors[0]
+	}
+	if meta.Language == "" {
+		meta.Language = "en"
+	}
+
+	cover, err := placeholderCover(RecommendedCoverSize)
+	if err != nil {
+		return nil, err
+	}
+	files := []scaffoldFile{{BookFileName, fmt.Sprintf(scaffoldProject, yamlScalar(name), yamlScalar(meta.Title), yamlScalar(author), yamlScalar(meta.Language))}}
+	for _, chapter := range scaffoldChapters {
+		files = append(files, scaffoldFile{"chapters/" + chapter.name, chapter.content})
+	}
+	files = append(files, scaffoldFile{"images/cover.png", string(cover)}, scaffoldFile{"styles/custom.css", scaffoldCSS})
+
+	var size int64
+	created := make([]string, 0, len(files))
+	for _, file := range files {
+		if err := writeExtractedFile(filepath.Join(dir, filepath.FromSlash(file.name)), file.content, &size); err != nil {
+			return created, err
+		}
+		created = append(created, file.name)
+	}
+	return created, nil
+}
+
+// yamlScalar returns s written as a YAML scalar, quoted when needed.
+func yamlScalar(s string) string {
+	data, _ := yaml.Marshal(s)
+	return strings.TrimSpace(string(data))
+}
+
+// placeholderCover draws a plain cover of the given size, a frame on a
+// dark background, to be replaced by the book's own.
+func placeholderCover(size ImageSize) ([]byte, error) {
+	background, frame := color.RGBA{0x2c, 0x3e, 0x50, 0xff}, color.RGBA{0xec, 0xf0, 0xf1, 0xff}
+	img := image.NewPaletted(image.Rect(0, 0, size.Width, size.Height), color.Palette{background, frame})
+
+	margin, line := size.Width/12, size.Width/200
+	outer := image.Rect(margin, margin, size.Width-margin, size.Height-margin)
+	draw.Draw(img, outer, image.NewUniform(frame), image.Point{}, draw.Src)
+	draw.Draw(img, outer.Inset(line), image.NewUniform(background), image.Point{}, draw.Src)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("drawing cover: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestScaffold(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my-book")
+
+	files, err := Scaffold(dir, model.Metadata{Authors: []string{"Jane: Doe"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"book.yaml", "chapters/01-introduction.md", "chapters/02-first-chapter.md", "images/cover.png", "styles/custom.css"}, files)
+	for _, name := range files {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+
+	// The starter project builds as it is
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+	results, err := New().BuildProject(project, nil, Options{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	assert.Equal(t, filepath.Join(dir, "my-book.epub"), results[0].OutputPath)
+
+	opf := readEPUBEntry(t, results[0].OutputPath, "OEBPS/content.opf")
+	assert.Contains(t, opf, "<dc:title>My Book</dc:title>")
+	assert.Contains(t, opf, ">Jane: Doe</dc:creator>")
+	assert.Contains(t, opf, `properties="cover-image"`)
+
+	_, err = Scaffold(dir, model.Metadata{})
+	assert.ErrorIs(t, err, ErrOutputNotWrite)
+}
+
+func TestScaffold_EmptyDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "field_notes")
+	require.NoError(t, os.Mkdir(dir, 0o755))
+
+	_, err := Scaffold(dir, model.Metadata{Title: "Notes", Language: "fr"})
+	require.NoError(t, err)
+	project, err := LoadProject(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "field_notes", project.Books[0].Name)
+	assert.Equal(t, "Notes", project.Books[0].Metadata["title"])
+	assert.Equal(t, "fr", project.Metadata["language"])
+}
@@ -0,0 +1,101 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// sizeReportCount is the number of resources named when the book is over
+// Options.MaxSize.
+const sizeReportCount = 5
+
+// byteUnits are the size suffixes accepted by ParseByteSize, longest first.
+var byteUnits = []struct {
+	suffix string
+	size   float64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a --max-size value such as "5MB", "650 KB", or
+// "1.5GB" into bytes. Units are binary, so 1KB is 1024 bytes; a bare
+// number is bytes, and an empty value is 0 for no limit.
+func ParseByteSize(s string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(s))
+	if value == "" {
+		return 0, nil
+	}
+	multiplier := 1.0
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(value, unit.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid size %q: must be a size such as 5MB or 650KB", s)
+	}
+	return int64(n * multiplier), nil
+}
+
+// checkOutputSize lists the book's resources by size in the result when
+// Options.MaxSize is set, and warns when the EPUB is over it, naming the
+// resources that take the most space.
+func checkOutputSize(result *model.ConversionResult, doc *model.Document, rep *reporter, opts Options) {
+	if opts.MaxSize <= 0 {
+		return
+	}
+
+	sizes := make([]model.ResourceSize, 0, len(doc.Resources))
+	for _, res := range doc.Resources {
+		if size, ok := resourceSize(res); ok {
+			sizes = append(sizes, model.ResourceSize{File: res.FileName, Size: size})
+		}
+	}
+	sort.SliceStable(sizes, func(i, j int) bool { return sizes[i].Size > sizes[j].Size })
+	result.Stats.Resources = sizes
+
+	if result.Stats.OutputSize <= opts.MaxSize {
+		return
+	}
+	largest := make([]string, 0, sizeReportCount)
+	for _, s := range sizes[:min(len(sizes), sizeReportCount)] {
+		largest = append(largest, fmt.Sprintf("%s (%s)", s.File, formatSize(s.Size)))
+	}
+	message := fmt.Sprintf("EPUB is %s, over the %s limit", formatSize(result.Stats.OutputSize), formatSize(opts.MaxSize))
+	if len(largest) > 0 {
+		message += "; largest resources: " + strings.Join(largest, ", ")
+	}
+	rep.warn(model.Warning{
+		Code:    model.WarnOutputSize,
+		File:    result.OutputPath,
+		Message: message,
+	})
+}
+
+// formatSize formats a byte count in KB below a megabyte and in MB above.
+func formatSize(n int64) string {
+	if n < 1<<20 {
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return formatMB(n)
+}
@@ -0,0 +1,83 @@
+package converter
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"":      0,
+		"5MB":   5 << 20,
+		"5 mb":  5 << 20,
+		"650KB": 650 << 10,
+		"1.5GB": 3 << 29,
+		"2M":    2 << 20,
+		"4096":  4096,
+		"100 B": 100,
+	}
+	for input, want := range tests {
+		got, err := ParseByteSize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"MB", "-5MB", "0", "five MB", "5TB"} {
+		_, err := ParseByteSize(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestConverter_Convert_MaxSize(t *testing.T) {
+	dir := t.TempDir()
+	noise := func(n int) []byte {
+		data := make([]byte, n)
+		rand.New(rand.NewSource(int64(n))).Read(data)
+		return append(append([]byte{}, pngHeader...), data...)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "large.png"), noise(40000), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "small.png"), noise(5000), 0o644))
+	writeFiles(t, dir, map[string]string{
+		"book.md": "# One\n\n![large](large.png)\n\n![small](small.png)\n",
+	})
+
+	convert := func(limit int64) *model.ConversionResult {
+		result, err := New().Convert([]string{filepath.Join(dir, "book.md")}, Options{
+			OutputPath: filepath.Join(dir, "book.epub"),
+			MaxSize:    limit,
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	// Under the limit, sizes are listed without a warning
+	result := convert(1 << 20)
+	require.Len(t, result.Stats.Resources, 2)
+	assert.Equal(t, "images/large.png", result.Stats.Resources[0].File)
+	assert.Equal(t, int64(len(pngHeader)+40000), result.Stats.Resources[0].Size)
+	assert.Equal(t, "images/small.png", result.Stats.Resources[1].File)
+	for _, w := range result.Warnings {
+		assert.NotEqual(t, model.WarnOutputSize, w.Code)
+	}
+
+	result = convert(20 << 10)
+	var over []model.Warning
+	for _, w := range result.Warnings {
+		if w.Code == model.WarnOutputSize {
+			over = append(over, w)
+		}
+	}
+	require.Len(t, over, 1)
+	assert.Contains(t, over[0].Message, "over the 20.0 KB limit")
+	assert.Contains(t, over[0].Message, "images/large.png (39.1 KB), images/small.png (4.9 KB)")
+
+	// Without a limit, no breakdown is collected
+	assert.Empty(t, convert(0).Stats.Resources)
+}
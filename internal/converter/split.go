@@ -0,0 +1,275 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns used to split chapters and retarget links between the parts.
+var (
+	splitIDRe    = regexp.MustCompile(`\sid\s*=\s*["']([^"']*)["']`)
+	fragmentRe   = regexp.MustCompile(`(\shref\s*=\s*["'])#([^"']*)(["'])`)
+	chapterRe    = regexp.MustCompile(`(\shref\s*=\s*["'])(chapter-\d+\.xhtml)(#[^"']*)?(["'])`)
+	headingTagRe = regexp.MustCompile(`(?is)<h([1-6])\b[^>]*>(.*?)</h[1-6]>`)
+
+	// noteListRe matches the footnotes or endnotes listed at the end of a
+	// chapter, capturing the markup opening the list, the notes, and the
+	// markup closing it.
+	noteListRe = regexp.MustCompile(`(?s)\s*(<(?:div|section) class="footnotes"[^>]*>\s*<hr />\s*(?:<ol>\s*)?)(.*?)((?:</ol>\s*)?</(?:div|section)>)\s*$`)
+	noteItemRe = regexp.MustCompile(`<(?:aside|li)\b[^>]*\bepub:type=["'](?:foot|end)note["'][^>]*>`)
+)
+
+// chapterNote is a footnote or endnote listed at the end of a chapter.
+type chapterNote struct {
+	id     string
+	markup string
+}
+
+// chapterNoteList is the list of notes at the end of a chapter, kept apart
+// while the chapter is split so each part can list the notes it refers to.
+type chapterNoteList struct {
+	open, close string
+	notes       []chapterNote
+}
+
+// voidElements never have a closing tag, so they do not open a level of
+// nesting when written without a trailing slash.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// ParseSplitLevel parses a --split-level value: "none" keeps one XHTML
+// file per input file, and "1" or "2" starts a new file at each h1, or at
+// each h1 and h2.
+func ParseSplitLevel(s string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "0":
+		return 0, nil
+	case "1":
+		return 1, nil
+	case "2":
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("invalid split level %q: must be 1, 2 or none", s)
+	}
+}
+
+// splitChapters splits each chapter at top-level headings up to level into
+// separate XHTML files. Chapters are renumbered, and TOC entries and
+// fragment links are retargeted to the part holding their target.
+func splitChapters(doc *model.Document, level int) {
+	if level <= 0 {
+		return
+	}
+
+	// Parts are found by original file name and "file#id" keys, since ids
+	// only need to be unique within each original file
+	firstPart := make(map[string]string, len(doc.Chapters))
+	idOwner := make(map[string]string)
+	var chapters []model.Chapter
+	var sources []string
+
+	for _, chapter := range doc.Chapters {
+		body, notes := cutNoteList(chapter.Content)
+		parts := splitContent(body, level)
+		if notes != nil {
+			parts = notes.distribute(parts)
+		}
+		for i, content := range parts {
+			part := chapter
+			part.Order = len(chapters)
+			part.ID = fmt.Sprintf("chapter-%03d", part.Order+1)
+			part.FileName = fmt.Sprintf("content/chapter-%03d.xhtml", part.Order+1)
+			part.Content = content
+			if i > 0 {
+				part.Spread = "" // Only the first page keeps the side of the spread it is on
+			}
+			if m := headingTagRe.FindStringSubmatchIndex(content); len(parts) > 1 && m != nil && m[0] == 0 {
+				part.Title = headingText(content[m[4]:m[5]])
+				part.Level, _ = strconv.Atoi(content[m[2]:m[3]])
+			}
+			if i == 0 {
+				firstPart[chapter.FileName] = part.FileName
+			}
+			for _, m := range splitIDRe.FindAllStringSubmatch(content, -1) {
+				if key := chapter.FileName + "#" + m[1]; idOwner[key] == "" {
+					idOwner[key] = part.FileName
+				}
+			}
+			chapters = append(chapters, part)
+			sources = append(sources, chapter.FileName)
+		}
+	}
+
+	// Point links to other chapters, then fragment links, at the part
+	// holding their target
+	for i := range chapters {
+		file := chapters[i].FileName
+		chapters[i].Content = chapterRe.ReplaceAllStringFunc(chapters[i].Content, func(match string) string {
+			m := chapterRe.FindStringSubmatch(match)
+			target := "content/" + m[2]
+			if owner, ok := idOwner[target+html.UnescapeString(m[3])]; ok && m[3] != "" {
+				return m[1] + path.Base(owner) + m[3] + m[4]
+			}
+			if first, ok := firstPart[target]; ok {
+				return m[1] + path.Base(first) + m[3] + m[4]
+			}
+			return match
+		})
+		chapters[i].Content = fragmentRe.ReplaceAllStringFunc(chapters[i].Content, func(match string) string {
+			m := fragmentRe.FindStringSubmatch(match)
+			owner, ok := idOwner[sources[i]+"#"+html.UnescapeString(m[2])]
+			if !ok || owner == file {
+				return match
+			}
+			return m[1] + path.Base(owner) + "#" + m[2] + m[3]
+		})
+	}
+
+	doc.Chapters = chapters
+	doc.TOC.Entries = retargetSplitEntries(doc.TOC.Entries, firstPart, idOwner)
+}
+
+// splitContent splits XHTML content before each top-level heading up to
+// level. Content before the first heading stays with the first part.
+func splitContent(content string, level int) []string {
+	var parts []string
+	depth, start := 0, 0
+
+	for _, loc := range markupTagRe.FindAllStringSubmatchIndex(content, -1) {
+		if loc[4] < 0 {
+			// Comments and declarations
+			continue
+		}
+		closing := loc[3] > loc[2]
+		name := strings.ToLower(content[loc[4]:loc[5]])
+		tag := content[loc[0]:loc[1]]
+
+		switch {
+		case closing:
+			depth--
+		case strings.HasSuffix(tag, "/>") || voidElements[name]:
+		default:
+			if depth == 0 && isSplitHeading(name, level) && strings.TrimSpace(content[start:loc[0]]) != "" {
+				parts = append(parts, strings.TrimSpace(content[start:loc[0]]))
+				start = loc[0]
+			}
+			depth++
+		}
+	}
+	return append(parts, strings.TrimSpace(content[start:]))
+}
+
+// cutNoteList removes the list of notes from the end of chapter content,
+// returning the remaining content and the notes, or nil if it has none.
+func cutNoteList(content string) (string, *chapterNoteList) {
+	m := noteListRe.FindStringSubmatchIndex(content)
+	if m == nil {
+		return content, nil
+	}
+	list := &chapterNoteList{open: content[m[2]:m[3]], close: content[m[6]:m[7]]}
+	items := content[m[4]:m[5]]
+	starts := noteItemRe.FindAllStringIndex(items, -1)
+	for i, start := range starts {
+		end := len(items)
+		if i+1 < len(starts) {
+			end = starts[i+1][0]
+		}
+		note := chapterNote{markup: strings.TrimSpace(items[start[0]:end])}
+		if id := splitIDRe.FindStringSubmatch(items[start[0]:start[1]]); id != nil {
+			note.id = html.UnescapeString(id[1])
+		}
+		list.notes = append(list.notes, note)
+	}
+	if len(list.notes) == 0 {
+		return content, nil
+	}
+	return content[:m[0]], list
+}
+
+// distribute appends to each part a list of the notes first referred to
+// from it. Notes no part refers to stay with the last part.
+func (l *chapterNoteList) distribute(parts []string) []string {
+	owner := make([]int, len(l.notes))
+	for n, note := range l.notes {
+		owner[n] = len(parts) - 1
+		for i, content := range parts {
+			if refersTo(content, note.id) {
+				owner[n] = i
+				break
+			}
+		}
+	}
+
+	for i := range parts {
+		var notes []string
+		for n, note := range l.notes {
+			if owner[n] == i {
+				notes = append(notes, note.markup)
+			}
+		}
+		if len(notes) > 0 {
+			parts[i] += "\n" + l.open + strings.Join(notes, "\n") + "\n" + l.close
+		}
+	}
+	return parts
+}
+
+// refersTo reports whether content links to the fragment id.
+func refersTo(content, id string) bool {
+	for _, m := range fragmentRe.FindAllStringSubmatch(content, -1) {
+		if id != "" && html.UnescapeString(m[2]) == id {
+			return true
+		}
+	}
+	return false
+}
+
+// isSplitHeading reports whether name is a heading element at or above level.
+func isSplitHeading(name string, level int) bool {
+	if len(name) != 2 || name[0] != 'h' {
+		return false
+	}
+	n, err := strconv.Atoi(name[1:])
+	return err == nil && n >= 1 && n <= level
+}
+
+// headingText returns the plain text of heading markup.
+func headingText(s string) string {
+	return strings.TrimSpace(html.UnescapeString(markupTagRe.ReplaceAllString(s, "")))
+}
+
+// retargetSplitEntries points TOC entries at the split parts: entries with
+// a fragment go to the part holding that id, others to the first part.
+func retargetSplitEntries(entries []model.TOCEntry, firstPart, idOwner map[string]string) []model.TOCEntry {
+	result := make([]model.TOCEntry, 0, len(entries))
+	for _, entry := range entries {
+		file, fragment, hasFragment := strings.Cut(entry.Href, "#")
+		if owner, ok := idOwner[entry.Href]; hasFragment && ok {
+			entry.Href = owner + "#" + fragment
+		} else if first, ok := firstPart[file]; ok {
+			entry.Href = first
+			if hasFragment {
+				entry.Href += "#" + fragment
+			}
+		}
+		entry.Children = retargetSplitEntries(entry.Children, firstPart, idOwner)
+		result = append(result, entry)
+	}
+	return result
+}
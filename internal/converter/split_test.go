@@ -0,0 +1,113 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestSplitContent(t *testing.T) {
+	content := `<p>Preamble</p><h1 id="a">A</h1><p>x<br>y</p><h2 id="a1">A.1</h2><section><h1>Nested</h1></section><h1 id="b">B</h1>`
+
+	assert.Equal(t, []string{content}, splitContent(content, 0))
+	assert.Equal(t, []string{
+		`<p>Preamble</p>`,
+		`<h1 id="a">A</h1><p>x<br>y</p><h2 id="a1">A.1</h2><section><h1>Nested</h1></section>`,
+		`<h1 id="b">B</h1>`,
+	}, splitContent(content, 1))
+	assert.Len(t, splitContent(content, 2), 4)
+	assert.Equal(t, []string{`<h1>Only</h1><p>Text</p>`}, splitContent(`<h1>Only</h1><p>Text</p>`, 1))
+}
+
+func TestSplitChapters(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{
+		ID:       "chapter-001",
+		Title:    "One",
+		Level:    1,
+		Content:  `<h1 id="one">One</h1><p>See<a href="#fn1">1</a> and <a href="#two">two</a>.</p><h1 id="two">Two &amp; More</h1><aside id="fn1">Note</aside>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "chapter-002",
+		Title:    "Three",
+		Level:    1,
+		Content:  `<h1 id="two">Three</h1><p>Back to <a href="chapter-001.xhtml#two">two</a> and <a href="chapter-001.xhtml">one</a>.</p>`,
+		FileName: "content/chapter-002.xhtml",
+	})
+	doc.TOC.Entries = []model.TOCEntry{
+		{Title: "One", Href: "content/chapter-001.xhtml#one", Level: 1},
+		{Title: "Two", Href: "content/chapter-001.xhtml#two", Level: 1},
+		{Title: "Three", Href: "content/chapter-002.xhtml#two", Level: 1},
+	}
+
+	splitChapters(doc, 1)
+
+	require.Len(t, doc.Chapters, 3)
+	assert.Equal(t, "content/chapter-002.xhtml", doc.Chapters[1].FileName)
+	assert.Equal(t, "Two & More", doc.Chapters[1].Title)
+	assert.Equal(t, 2, doc.Chapters[2].Order)
+	assert.Contains(t, doc.Chapters[0].Content, `<a href="chapter-002.xhtml#fn1">1</a>`)
+	assert.Contains(t, doc.Chapters[0].Content, `<a href="chapter-002.xhtml#two">two</a>`)
+	assert.Contains(t, doc.Chapters[2].Content, `<a href="chapter-002.xhtml#two">two</a> and <a href="chapter-001.xhtml">one</a>`)
+
+	assert.Equal(t, "content/chapter-001.xhtml#one", doc.TOC.Entries[0].Href)
+	assert.Equal(t, "content/chapter-002.xhtml#two", doc.TOC.Entries[1].Href)
+	assert.Equal(t, "content/chapter-003.xhtml#two", doc.TOC.Entries[2].Href)
+}
+
+func TestParseSplitLevel(t *testing.T) {
+	for in, want := range map[string]int{"none": 0, "": 0, "1": 1, "2": 2} {
+		level, err := ParseSplitLevel(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, level)
+	}
+	_, err := ParseSplitLevel("3")
+	assert.Error(t, err)
+}
+
+func TestSplitChapters_Footnotes(t *testing.T) {
+	for _, tt := range []struct {
+		name, open, close, note string
+	}{
+		{"popup", "<div class=\"footnotes\">\n<hr />\n", "</div>", `<aside epub:type="footnote" id="fn:%d" role="doc-footnote">%s</aside>`},
+		{"chapter", "<section class=\"footnotes\" epub:type=\"endnotes\" role=\"doc-endnotes\">\n<hr />\n<ol>\n", "</ol>\n</section>", `<li epub:type="endnote" id="fn:%d" role="doc-endnote">%s</li>`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			note := func(n int) string {
+				return fmt.Sprintf(tt.note, n, fmt.Sprintf("\n<p>Note %d <a href=\"#fnref:%d\">back</a></p>\n", n, n)) + "\n"
+			}
+			doc := model.NewDocument()
+			doc.AddChapter(model.Chapter{
+				ID:    "chapter-001",
+				Title: "One",
+				Content: `<h1 id="one">One</h1><p>A<sup id="fnref:1"><a href="#fn:1">1</a></sup></p>` +
+					`<h1 id="two">Two</h1><p>B<sup id="fnref:2"><a href="#fn:2">2</a></sup></p>` + "\n" +
+					tt.open + note(1) + note(2) + note(3) + tt.close + "\n",
+				FileName: "content/chapter-001.xhtml",
+			})
+
+			splitChapters(doc, 1)
+
+			require.Len(t, doc.Chapters, 2)
+			first, second := doc.Chapters[0].Content, doc.Chapters[1].Content
+
+			// Each part lists the notes it refers to, and unreferenced notes
+			// stay with the last part
+			assert.Contains(t, first, `<a href="#fn:1">1</a>`)
+			assert.Contains(t, first, tt.open+strings.TrimSpace(note(1))+"\n"+tt.close)
+			assert.Contains(t, first, `<a href="#fnref:1">back</a>`)
+			assert.NotContains(t, first, `id="fn:2"`)
+			assert.Contains(t, second, `<a href="#fn:2">2</a>`)
+			assert.Contains(t, second, `id="fn:2"`)
+			assert.Contains(t, second, `id="fn:3"`)
+			assert.NotContains(t, second, `id="fn:1"`)
+			assert.True(t, strings.HasSuffix(second, tt.close))
+		})
+	}
+}
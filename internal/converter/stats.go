@@ -0,0 +1,149 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"compress/flate"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// errStatsGathered stops the conversion run by Stats once the document is
+// ready to be built.
+var errStatsGathered = errors.New("stats gathered")
+
+// Size estimates for the parts of a package that the inputs do not hold:
+// the package document, navigation, built-in stylesheet, and colophon,
+// and the XHTML wrapping and zip entry of each content document, all as
+// compressed.
+const (
+	packageOverhead  = 4 << 10
+	chapterOverhead  = 300
+	resourceOverhead = 150
+)
+
+// InputStats describes the book that inputs would make, gathered without
+// building it.
+type InputStats struct {
+	InputFormat   string          // Source format: "markdown", "html", "pdf"
+	InputFiles    int             // Number of input files
+	Chapters      int             // Content documents made from the inputs, before generated pages
+	Headings      int             // Headings of every level
+	Words         int             // Words of text; each CJK character counts as one
+	Images        int             // Images found
+	MissingImages []string        // Images referenced but not found or not readable
+	PredictedSize int64           // Estimated size of the EPUB in bytes
+	Warnings      []model.Warning // Problems found while reading the inputs
+}
+
+// Stats reads and parses inputs as Convert does, with the same options,
+// and describes the book they would make without building it.
+func (c *Converter) Stats(inputs []string, opts Options) (*InputStats, error) {
+	var doc *model.Document
+	opts.EmitIR = ""
+	opts.EPUBCheck = false
+	opts.Hooks = append(slices.Clone(opts.Hooks), func(d *model.Document) error {
+		doc = d
+		return errStatsGathered
+	})
+
+	result, err := c.Convert(inputs, opts)
+	if !errors.Is(err, errStatsGathered) {
+		if err == nil {
+			err = ErrConversionFailed
+		}
+		return nil, err
+	}
+	files, err := c.expandInputs(inputs, opts)
+	if err != nil {
+		return nil, err
+	}
+	format, err := c.inputFormat(files, opts.InputFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &InputStats{
+		InputFormat: format.String(),
+		InputFiles:  len(files),
+		Chapters:    len(doc.Chapters),
+		Warnings:    result.Warnings,
+	}
+	for _, chapter := range doc.Chapters {
+		stats.Headings += len(headingTagRe.FindAllStringIndex(chapter.Content, -1))
+		stats.Words += countWords(plainText(chapter.Content))
+	}
+	for _, res := range doc.Resources {
+		if strings.HasPrefix(res.MediaType, "image/") {
+			stats.Images++
+		}
+	}
+	for _, warning := range result.Warnings {
+		if warning.Code == model.WarnMissingImage {
+			stats.MissingImages = append(stats.MissingImages, warning.File)
+		}
+	}
+	stats.PredictedSize = predictSize(doc)
+	return stats, nil
+}
+
+// countWords counts the words of text, counting each Chinese or Japanese
+// character as a word, as those scripts do not separate words by spaces.
+func countWords(text string) int {
+	words := 0
+	for _, field := range strings.Fields(text) {
+		inWord := false
+		for _, r := range field {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+				words++
+				inWord = false
+				continue
+			}
+			if !inWord {
+				words++
+				inWord = true
+			}
+		}
+	}
+	return words
+}
+
+// predictSize estimates the size of the EPUB built from doc: its text
+// compressed as the package stores it, its other resources at their size,
+// and the files and wrapping every book has.
+func predictSize(doc *model.Document) int64 {
+	size := int64(packageOverhead)
+	text := &countingWriter{w: io.Discard}
+	zw, _ := flate.NewWriter(text, flate.DefaultCompression)
+	for _, chapter := range doc.Chapters {
+		_, _ = io.WriteString(zw, chapter.Content)
+		size += chapterOverhead
+	}
+	for _, res := range doc.Resources {
+		size += resourceOverhead
+		n := int64(len(res.Data))
+		if n == 0 && res.SourcePath != "" {
+			if info, err := os.Stat(res.SourcePath); err == nil {
+				n = info.Size()
+			}
+		}
+		if strings.HasPrefix(res.MediaType, "text/") && len(res.Data) > 0 {
+			_, _ = zw.Write(res.Data)
+			continue
+		}
+		size += n
+	}
+	_ = zw.Close()
+	return size + text.n
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverter_Stats(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.md": "# One\n\nHello world, this is text.\n\n## Two\n\n![Lost](missing.png)\n\n" + strings.Repeat("Some more words here. ", 200) + "\n",
+		"b.md": "# 三\n\n日本語のテキスト and more.\n",
+	})
+
+	stats, err := New().Stats([]string{dir}, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", stats.InputFormat)
+	assert.Equal(t, 2, stats.InputFiles)
+	assert.Equal(t, 2, stats.Chapters)
+	assert.Equal(t, 3, stats.Headings)
+	assert.Equal(t, 7+800+1+8+2, stats.Words)
+	assert.Equal(t, []string{filepath.Join(dir, "missing.png")}, stats.MissingImages)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "nothing written")
+
+	split, err := New().Stats([]string{dir}, Options{SplitLevel: 2})
+	require.NoError(t, err)
+	assert.Equal(t, 3, split.Chapters)
+
+	output := filepath.Join(t.TempDir(), "book.epub")
+	_, err = New().Convert([]string{dir}, Options{OutputPath: output})
+	require.NoError(t, err)
+	info, err := os.Stat(output)
+	require.NoError(t, err)
+	assert.InDelta(t, info.Size(), stats.PredictedSize, float64(info.Size())/4)
+}
+
+func TestCountWords(t *testing.T) {
+	assert.Equal(t, 0, countWords(" \n"))
+	assert.Equal(t, 3, countWords("three plain words"))
+	assert.Equal(t, 4, countWords("中文字 word"))
+	assert.Equal(t, 3, countWords("abc日def"))
+}
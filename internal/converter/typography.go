@@ -0,0 +1,182 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// quoteStyle holds the quotation marks used by a language.
+type quoteStyle struct {
+	OpenDouble, CloseDouble string
+	OpenSingle, CloseSingle string
+}
+
+// quoteStyles maps primary language subtags to quotation marks. French
+// guillemets are padded with narrow no-break spaces.
+var quoteStyles = map[string]quoteStyle{
+	"en": {"“", "”", "‘", "’"},
+	"nl": {"“", "”", "‘", "’"},
+	"pt": {"“", "”", "‘", "’"},
+	"zh": {"“", "”", "‘", "’"},
+	"ko": {"“", "”", "‘", "’"},
+	"vi": {"“", "”", "‘", "’"},
+	"de": {"„", "“", "‚", "‘"},
+	"cs": {"„", "“", "‚", "‘"},
+	"pl": {"„", "”", "‚", "’"},
+	"fr": {"« ", " »", "‹ ", " ›"},
+	"es": {"«", "»", "“", "”"},
+	"it": {"«", "»", "“", "”"},
+	"ru": {"«", "»", "„", "“"},
+	"ja": {"「", "」", "『", "』"},
+	"sv": {"”", "”", "’", "’"},
+}
+
+// quoteStyleFor returns the quote style for a BCP 47 language tag,
+// falling back to English quotes.
+func quoteStyleFor(lang string) quoteStyle {
+	primary := strings.ToLower(lang)
+	if i := strings.IndexAny(primary, "-_"); i >= 0 {
+		primary = primary[:i]
+	}
+	if style, ok := quoteStyles[primary]; ok {
+		return style
+	}
+	return quoteStyles["en"]
+}
+
+// Patterns and replacements used by the typography pass.
+var (
+	markupTagRe = regexp.MustCompile(`<(/?)([a-zA-Z][\w:-]*)[^>]*>|<!--[\s\S]*?-->|<[^>]*>`)
+
+	// dashReplacer converts hyphen runs to dashes and dots to an ellipsis.
+	dashReplacer = strings.NewReplacer("---", "—", "--", "–", "...", "…")
+)
+
+// verbatimElements are elements whose text is never rewritten.
+var verbatimElements = map[string]bool{
+	"code": true, "pre": true, "kbd": true, "samp": true, "tt": true,
+	"script": true, "style": true, "math": true, "var": true,
+}
+
+// applyTypography normalizes straight quotes, double and triple hyphens,
+// and three-dot ellipses in all chapters, using the quotation marks of
+// each chapter's language or the book language. Markup, attribute values,
+// and code are left unchanged, so the pass can run on the output of every
+// parser.
+func applyTypography(doc *model.Document) {
+	for i, chapter := range doc.Chapters {
+		lang := chapter.Language
+		if lang == "" {
+			lang = doc.Metadata.Language
+		}
+		doc.Chapters[i].Content = smartenMarkup(chapter.Content, quoteStyleFor(lang))
+	}
+}
+
+// smartenMarkup rewrites the text between tags of an XHTML fragment.
+func smartenMarkup(content string, style quoteStyle) string {
+	var buf strings.Builder
+	verbatim := 0
+	prev := ' ' // Last text rune, carried across tags to place quotes
+	last := 0
+
+	for _, loc := range markupTagRe.FindAllStringSubmatchIndex(content, -1) {
+		text := content[last:loc[0]]
+		if verbatim > 0 {
+			buf.WriteString(text)
+		} else {
+			buf.WriteString(smartenText(text, style, &prev))
+		}
+		buf.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+
+		if loc[4] < 0 {
+			continue // Comment or declaration
+		}
+		name := strings.ToLower(content[loc[4]:loc[5]])
+		selfClosing := strings.HasSuffix(content[loc[0]:loc[1]], "/>")
+		if !verbatimElements[name] || selfClosing {
+			continue
+		}
+		if loc[3] > loc[2] {
+			if verbatim > 0 {
+				verbatim--
+			}
+		} else {
+			verbatim++
+		}
+	}
+
+	text := content[last:]
+	if verbatim > 0 {
+		buf.WriteString(text)
+	} else {
+		buf.WriteString(smartenText(text, style, &prev))
+	}
+	return buf.String()
+}
+
+// smartenText normalizes a text run. Character references are decoded
+// first so that "&quot;" is treated like a straight quote, and the result
+// is re-escaped for XHTML text content.
+func smartenText(text string, style quoteStyle, prev *rune) string {
+	if text == "" {
+		return text
+	}
+	text = html.UnescapeString(text)
+
+	text = dashReplacer.Replace(text)
+
+	var buf strings.Builder
+	for i, r := range text {
+		switch r {
+		case '"':
+			if opensQuote(*prev) {
+				buf.WriteString(style.OpenDouble)
+			} else {
+				buf.WriteString(style.CloseDouble)
+			}
+		case '\'':
+			next, _ := utf8.DecodeRuneInString(text[i+1:])
+			switch {
+			case (unicode.IsLetter(*prev) || unicode.IsDigit(*prev)) && unicode.IsLetter(next):
+				// Apostrophe inside a word, e.g. don't
+				buf.WriteString("’")
+			case opensQuote(*prev) && unicode.IsDigit(next):
+				// Elided year, e.g. '90s
+				buf.WriteString("’")
+			case opensQuote(*prev):
+				buf.WriteString(style.OpenSingle)
+			default:
+				buf.WriteString(style.CloseSingle)
+			}
+		default:
+			buf.WriteRune(r)
+		}
+		*prev = r
+	}
+
+	return escapeText(buf.String())
+}
+
+// opensQuote reports whether a quote after prev opens a quotation.
+func opensQuote(prev rune) bool {
+	return unicode.IsSpace(prev) || strings.ContainsRune("([{—–-/", prev)
+}
+
+// escapeText escapes the characters that are special in XHTML text.
+func escapeText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
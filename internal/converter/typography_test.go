@@ -0,0 +1,45 @@
+package converter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestSmartenMarkup(t *testing.T) {
+	en := quoteStyleFor("en-US")
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{"double quotes", `<p>She said "hi".</p>`, `<p>She said “hi”.</p>`},
+		{"quotes across tags", `<p>"<em>Really</em>"</p>`, `<p>“<em>Really</em>”</p>`},
+		{"single quotes and apostrophes", `<p>'Don't,' it's the '90s.</p>`, `<p>‘Don’t,’ it’s the ’90s.</p>`},
+		{"dashes and ellipsis", `<p>1--2 --- wait...</p>`, `<p>1–2 — wait…</p>`},
+		{"entities", `<p>&quot;A &amp; B&quot; &lt;x&gt;</p>`, `<p>“A &amp; B” &lt;x&gt;</p>`},
+		{"attributes untouched", `<a href="a--b" title="x...">"link"</a>`, `<a href="a--b" title="x...">“link”</a>`},
+		{"code untouched", `<p>"x" <code>"y" --z</code> "w"</p><pre><code>a...b</code></pre>`, `<p>“x” <code>"y" --z</code> “w”</p><pre><code>a...b</code></pre>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, smartenMarkup(tt.in, en))
+		})
+	}
+}
+
+func TestApplyTypography_LanguageQuotes(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{Content: `<p>"Guten Tag," sagte er, 'ja'.</p>`})
+
+	doc.Metadata.Language = "de"
+	applyTypography(doc)
+	assert.Equal(t, `<p>„Guten Tag,“ sagte er, ‚ja‘.</p>`, doc.Chapters[0].Content)
+
+	doc.Chapters[0].Content = `<p>Il a dit "oui".</p>`
+	doc.Metadata.Language = "fr-CA"
+	applyTypography(doc)
+	assert.Equal(t, "<p>Il a dit « oui ».</p>", doc.Chapters[0].Content)
+}
@@ -0,0 +1,117 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// writeUnpacked builds the EPUB with builder and writes its contents as a
+// directory tree at dir, replacing an unpacked EPUB already there. The
+// tree is written beside dir and renamed into place only when complete.
+// Returns the size of the EPUB the tree packs into.
+func (c *Converter) writeUnpacked(dir string, doc *model.Document, builder *epub.Builder, log *slog.Logger) (int64, error) {
+	if err := checkUnpackedTarget(dir); err != nil {
+		return 0, err
+	}
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return 0, fmt.Errorf("%w: cannot create directory %s", ErrOutputNotWrite, parent)
+	}
+
+	start := time.Now()
+	f, err := os.CreateTemp(parent, filepath.Base(dir)+".*.epub.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := builder.WriteTo(doc, f); err != nil {
+		f.Close()
+		return 0, err
+	}
+	log.Info("built EPUB", "stage", "build", "chapters", len(doc.Chapters),
+		"resources", len(doc.Resources), "duration", time.Since(start))
+
+	start = time.Now()
+	info, statErr := f.Stat()
+	if err := f.Close(); err != nil || statErr != nil {
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, errors.Join(statErr, err))
+	}
+
+	tmpDir, err := os.MkdirTemp(parent, filepath.Base(dir)+".*.tmp")
+	if err == nil {
+		err = os.Chmod(tmpDir, 0755)
+	}
+	if err == nil {
+		err = epub.Unpack(f.Name(), tmpDir)
+	}
+	if err == nil {
+		err = os.RemoveAll(dir)
+	}
+	if err == nil {
+		err = os.Rename(tmpDir, dir)
+	}
+	if err != nil {
+		if tmpDir != "" {
+			os.RemoveAll(tmpDir)
+		}
+		return 0, fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	log.Info("wrote output", "stage", "write", "dir", dir, "bytes", info.Size(), "duration", time.Since(start))
+
+	return info.Size(), nil
+}
+
+// checkUnpackedTarget checks that dir can be replaced by an unpacked EPUB:
+// it does not exist, is empty, or holds an earlier unpacked EPUB.
+func checkUnpackedTarget(dir string) error {
+	info, err := os.Stat(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: %s is a file, not a directory", ErrOutputNotWrite, dir)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrOutputNotWrite, err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(dir, "mimetype")); err != nil {
+		return fmt.Errorf("%w: %s is not empty and holds no unpacked EPUB", ErrOutputNotWrite, dir)
+	}
+	return nil
+}
+
+// Pack zips the unpacked EPUB in dir, such as one written with
+// Options.Unpacked, into the EPUB file at output, with its mimetype entry
+// first and uncompressed.
+func (c *Converter) Pack(dir, output string) error {
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrFileNotFound, dir)
+	}
+	if err := epub.Pack(dir, output); err != nil {
+		return fmt.Errorf("packing %s: %w", dir, err)
+	}
+	return nil
+}
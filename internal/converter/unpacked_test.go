@@ -0,0 +1,82 @@
+package converter
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+)
+
+func TestConverter_Convert_Unpacked(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"book.md": "# One\n\nText.\n"})
+	output := filepath.Join(dir, "build", "epub")
+
+	result, err := New().Convert([]string{filepath.Join(dir, "book.md")}, Options{OutputPath: output, Unpacked: true})
+	require.NoError(t, err)
+	assert.Equal(t, output, result.OutputPath)
+	assert.Positive(t, result.Stats.OutputSize)
+
+	mimetype, err := os.ReadFile(filepath.Join(output, "mimetype"))
+	require.NoError(t, err)
+	assert.Equal(t, "application/epub+zip", string(mimetype))
+	assert.FileExists(t, filepath.Join(output, "META-INF", "container.xml"))
+	assert.FileExists(t, filepath.Join(output, "OEBPS", "content.opf"))
+	chapter, err := os.ReadFile(filepath.Join(output, "OEBPS", "content", "chapter-001.xhtml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(chapter), "Text.")
+
+	// A rebuild replaces the earlier tree
+	require.NoError(t, os.WriteFile(filepath.Join(output, "OEBPS", "stale.xhtml"), nil, 0o644))
+	_, err = New().Convert([]string{filepath.Join(dir, "book.md")}, Options{OutputPath: output, Unpacked: true})
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(output, "OEBPS", "stale.xhtml"))
+
+	// Other directories are left alone
+	_, err = New().Convert([]string{filepath.Join(dir, "book.md")}, Options{OutputPath: dir, Unpacked: true})
+	assert.ErrorIs(t, err, ErrOutputNotWrite)
+	assert.FileExists(t, filepath.Join(dir, "book.md"))
+}
+
+func TestConverter_Pack(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"book.md": "# One\n\nText.\n"})
+	unpacked := filepath.Join(dir, "book")
+	_, err := New().Convert([]string{filepath.Join(dir, "book.md")}, Options{OutputPath: unpacked, Unpacked: true})
+	require.NoError(t, err)
+
+	// Edits made to the tree, and the clutter tools leave in it
+	require.NoError(t, os.WriteFile(filepath.Join(unpacked, "mimetype"), []byte("application/epub+zip\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(unpacked, ".DS_Store"), []byte("finder"), 0o644))
+
+	output := filepath.Join(dir, "book.epub")
+	require.NoError(t, New().Pack(unpacked, output))
+
+	r, err := zip.OpenReader(output)
+	require.NoError(t, err)
+	defer r.Close()
+	require.NotEmpty(t, r.File)
+	assert.Equal(t, "mimetype", r.File[0].Name)
+	assert.Equal(t, zip.Store, r.File[0].Method)
+	var names []string
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Contains(t, names, "OEBPS/content/chapter-001.xhtml")
+	assert.NotContains(t, names, ".DS_Store")
+	assert.Equal(t, "application/epub+zip", readEPUBEntry(t, output, "mimetype"))
+
+	issues, err := epub.Validate(output, epub.ValidateOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, issues)
+
+	// Directories that are not EPUBs are refused
+	require.NoError(t, os.Remove(filepath.Join(unpacked, "META-INF", "container.xml")))
+	assert.ErrorIs(t, New().Pack(unpacked, output), epub.ErrInvalidPackage)
+	assert.ErrorIs(t, New().Pack(filepath.Join(dir, "missing"), output), ErrFileNotFound)
+}
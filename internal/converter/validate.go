@@ -0,0 +1,51 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// ValidateOptions selects the checks run by Validate.
+type ValidateOptions struct {
+	A11y bool // Audit the documents for accessibility problems
+}
+
+// Validate checks an existing EPUB for malformed XHTML and broken links
+// and, with opts.A11y, accessibility problems. The problems found are the
+// warnings of the result; the error is set only when input cannot be read
+// as an EPUB.
+func (c *Converter) Validate(input string, opts ValidateOptions) (*model.ConversionResult, error) {
+	start := time.Now()
+	result := &model.ConversionResult{OutputPath: input}
+	result.Stats.InputFormat = parser.FormatEPUB.String()
+	result.Stats.InputFiles = 1
+
+	if _, err := os.Stat(input); errors.Is(err, os.ErrNotExist) {
+		return result, fmt.Errorf("%w: %s", ErrFileNotFound, input)
+	}
+
+	issues, err := epub.Validate(input, epub.ValidateOptions{A11y: opts.A11y})
+	if err != nil {
+		return result, fmt.Errorf("validating %s: %w", input, err)
+	}
+	for _, issue := range issues {
+		result.AddWarning(issue)
+	}
+
+	result.Success = true
+	result.Stats.Duration = time.Since(start)
+	return result, nil
+}
@@ -0,0 +1,107 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package converter
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// WebP handling for Options.WebP.
+const (
+	WebPConvert  = "png"      // Convert WebP images to PNG (default)
+	WebPKeep     = "keep"     // Embed WebP images as image/webp
+	WebPFallback = "fallback" // Embed WebP images with a PNG manifest fallback
+)
+
+// ParseWebP parses a --webp value.
+func ParseWebP(s string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", WebPConvert:
+		return WebPConvert, nil
+	case WebPKeep:
+		return WebPKeep, nil
+	case WebPFallback:
+		return WebPFallback, nil
+	default:
+		return "", fmt.Errorf("invalid WebP handling %q: must be png, keep or fallback", s)
+	}
+}
+
+// images returns the image handler for the conversion's WebP handling,
+// maximum image size, grayscale, and animated GIF settings.
+func (c *Converter) images(opts Options) *ImageHandler {
+	keepWebP := opts.WebP == WebPKeep || opts.WebP == WebPFallback
+	flattenGIFs := opts.AnimatedGIF != "" && opts.AnimatedGIF != GIFKeep
+	if keepWebP || !opts.MaxImageSize.IsZero() || opts.Grayscale || flattenGIFs {
+		return &ImageHandler{
+			KeepWebP:    keepWebP,
+			MaxSize:     opts.MaxImageSize,
+			Grayscale:   opts.Grayscale,
+			Contrast:    opts.Contrast,
+			AnimatedGIF: opts.AnimatedGIF,
+		}
+	}
+	return c.imgHandler
+}
+
+// webPFallbacks reports whether kept WebP images need PNG fallbacks: when
+// requested, and always for EPUB 3.0, where WebP is not a core media type.
+func webPFallbacks(opts Options) bool {
+	return opts.WebP == WebPFallback || (opts.WebP == WebPKeep && opts.EPUB.Version == epub.Version30)
+}
+
+// addImageFallbacks adds a PNG copy of each image in a format some reading
+// systems cannot show and names it as the image's manifest fallback: WebP
+// when webPFallbacks says so, and AVIF always, as it is not a core media
+// type in any EPUB version. Images whose copy fails keep no fallback.
+func (c *Converter) addImageFallbacks(doc *model.Document, rep *reporter, opts Options) {
+	webP := webPFallbacks(opts)
+
+	used := make(map[string]bool, len(doc.Resources))
+	for _, res := range doc.Resources {
+		used[res.FileName] = true
+	}
+
+	added := 0
+	for i := range doc.Resources {
+		res := &doc.Resources[i]
+		needed := (webP && res.MediaType == "image/webp") || res.MediaType == "image/avif"
+		if !needed || res.Fallback != "" {
+			continue
+		}
+
+		png, err := c.images(opts).PNGFallback(*res)
+		if err != nil {
+			rep.warn(model.Warning{
+				Code:    model.WarnMissingImage,
+				File:    res.FileName,
+				Message: fmt.Sprintf("Image %s: no PNG fallback: %s", res.FileName, err),
+			})
+			continue
+		}
+
+		stem := strings.TrimSuffix(png.FileName, path.Ext(png.FileName))
+		for n := 2; used[png.FileName]; n++ {
+			png.FileName = stem + "-" + strconv.Itoa(n) + ".png"
+		}
+		used[png.FileName] = true
+
+		res.Fallback = png.ID
+		doc.Resources = append(doc.Resources, *png)
+		added++
+	}
+	if added > 0 {
+		opts.logger().Debug("added image fallbacks", "stage", "images", "images", added)
+	}
+}
@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"archive/zip"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+)
+
+// tinyWebP is a 1x1 lossless WebP image.
+const tinyWebP = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+// convertWebPBook converts a Markdown file showing a WebP image and
+// returns the EPUB's package document and file names.
+func convertWebPBook(t *testing.T, opts Options) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	data, err := base64.StdEncoding.DecodeString(tinyWebP)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "photo.webp"), data, 0o644))
+	writeFiles(t, dir, map[string]string{"book.md": "# One\n\n![photo](photo.webp)\n"})
+
+	opts.OutputPath = filepath.Join(dir, "book.epub")
+	_, err = New().Convert([]string{filepath.Join(dir, "book.md")}, opts)
+	require.NoError(t, err)
+
+	archive, err := zip.OpenReader(opts.OutputPath)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	var opf string
+	var names []string
+	for _, f := range archive.File {
+		names = append(names, f.Name)
+		if f.Name == "OEBPS/content.opf" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			opf = string(content)
+		}
+	}
+	return opf, names
+}
+
+func TestParseWebP(t *testing.T) {
+	for in, want := range map[string]string{"": WebPConvert, "PNG": WebPConvert, "keep": WebPKeep, "fallback": WebPFallback} {
+		got, err := ParseWebP(in)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseWebP("avif")
+	assert.Error(t, err)
+}
+
+func TestConverter_WebP(t *testing.T) {
+	t.Run("convert", func(t *testing.T) {
+		opf, _ := convertWebPBook(t, Options{})
+		assert.NotContains(t, opf, "image/webp")
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		opf, names := convertWebPBook(t, Options{WebP: WebPKeep})
+		assert.Contains(t, opf, `media-type="image/webp"`)
+		assert.NotContains(t, opf, "fallback=")
+		assert.Contains(t, names, "OEBPS/images/photo.webp")
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		opf, names := convertWebPBook(t, Options{WebP: WebPFallback})
+		assert.Contains(t, opf, `media-type="image/webp" fallback="img-photo-png"`)
+		assert.Contains(t, opf, `id="img-photo-png" href="images/photo.png" media-type="image/png"`)
+		assert.Contains(t, names, "OEBPS/images/photo.png")
+	})
+
+	t.Run("keep for EPUB 3.0", func(t *testing.T) {
+		opf, _ := convertWebPBook(t, Options{WebP: WebPKeep, EPUB: epub.Options{Version: epub.Version30}})
+		assert.Contains(t, opf, `fallback="img-photo-png"`)
+	})
+}
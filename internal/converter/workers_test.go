@@ -0,0 +1,31 @@
+package converter
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForEach(t *testing.T) {
+	for _, workers := range []int{1, 3, 16} {
+		seen := make([]int32, 10)
+		var running, peak atomic.Int32
+		forEach(len(seen), workers, func(i int) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			atomic.AddInt32(&seen[i], 1)
+			running.Add(-1)
+		})
+		for i, n := range seen {
+			assert.Equal(t, int32(1), n, "index %d", i)
+		}
+		assert.LessOrEqual(t, int(peak.Load()), workers)
+	}
+	forEach(0, 4, func(int) { t.Fatal("called with no items") })
+}
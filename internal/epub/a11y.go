@@ -0,0 +1,163 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Accessibility audit rules, named after the DAISY Ace rules they follow.
+const (
+	ruleEPUBLang     = "epub-lang"     // The package declares its language
+	ruleHTMLLang     = "html-has-lang" // Each document declares its language
+	ruleHeadingOrder = "heading-order" // Heading levels increase one at a time
+	ruleLinkName     = "link-name"     // Links have text a screen reader can announce
+	ruleTableHeader  = "td-has-header" // Data tables have header cells
+	ruleImageAlt     = "image-alt"     // Images have an alt attribute
+)
+
+// auditA11y checks the XHTML document name against the accessibility
+// rules and returns an accessibility warning for each failure.
+func auditA11y(name, doc string) []model.Warning {
+	var issues []model.Warning
+	report := func(rule, format string, args ...any) {
+		issues = append(issues, model.Warning{
+			Code:    model.WarnAccessibility,
+			File:    name,
+			Message: fmt.Sprintf("%s: %s (%s)", name, fmt.Sprintf(format, args...), rule),
+		})
+	}
+
+	var (
+		level  int    // Level of the previous heading, 0 before the first
+		link   string // href of the open link, if it has no name yet
+		inLink bool   // Inside a link that still needs a name
+		tables []bool // Whether each open table has a header cell
+		count  int    // Tables seen, for messages
+		number []int  // Number of each open table
+	)
+
+	z := html.NewTokenizer(strings.NewReader(doc))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return issues
+
+		case html.TextToken:
+			if inLink && strings.TrimSpace(string(z.Text())) != "" {
+				inLink = false
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "html":
+				if tokenAttr(tok, "xml:lang") == "" && tokenAttr(tok, "lang") == "" {
+					report(ruleHTMLLang, "document has no xml:lang or lang attribute")
+				}
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				n := int(tok.Data[1] - '0')
+				if level > 0 && n > level+1 {
+					report(ruleHeadingOrder, "heading %s follows h%d, skipping a level", tok.Data, level)
+				}
+				level = n
+			case "a":
+				_, hasHref := tokenHasAttr(tok, "href")
+				inLink = hasHref && !named(tok)
+				link = tokenAttr(tok, "href")
+			case "img":
+				alt, ok := tokenHasAttr(tok, "alt")
+				if inLink && strings.TrimSpace(alt) != "" {
+					inLink = false
+				}
+				if !ok && !presentational(tok) {
+					report(ruleImageAlt, "image %s has no alt attribute", tokenAttr(tok, "src"))
+				}
+			case "table":
+				count++
+				tables = append(tables, presentational(tok))
+				number = append(number, count)
+			case "th":
+				if len(tables) > 0 {
+					tables[len(tables)-1] = true
+				}
+			}
+
+		case html.EndTagToken:
+			tag, _ := z.TagName()
+			switch string(tag) {
+			case "a":
+				if inLink {
+					report(ruleLinkName, "link to %s has no text", link)
+				}
+				inLink = false
+			case "table":
+				if n := len(tables); n > 0 {
+					if !tables[n-1] {
+						report(ruleTableHeader, "table %d has no header cells", number[n-1])
+					}
+					tables, number = tables[:n-1], number[:n-1]
+				}
+			}
+		}
+	}
+}
+
+// tokenHasAttr returns the value of the named attribute of tok and whether
+// it is present.
+func tokenHasAttr(tok html.Token, name string) (string, bool) {
+	for _, a := range tok.Attr {
+		key := a.Key
+		if a.Namespace != "" {
+			key = a.Namespace + ":" + a.Key
+		}
+		if key == name {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+// tokenAttr returns the value of the named attribute of tok, or "".
+func tokenAttr(tok html.Token, name string) string {
+	v, _ := tokenHasAttr(tok, name)
+	return v
+}
+
+// named reports whether an element is named by an ARIA label or title.
+func named(tok html.Token) bool {
+	for _, name := range []string{"aria-label", "aria-labelledby", "title"} {
+		if strings.TrimSpace(tokenAttr(tok, name)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// presentational reports whether an element's role removes it from the
+// accessibility tree, as for layout tables and decorative images.
+func presentational(tok html.Token) bool {
+	role := tokenAttr(tok, "role")
+	return role == "presentation" || role == "none"
+}
+
+// audit runs the accessibility audit on a generated document when
+// Options.CheckA11y is set, reporting failures through Options.Warn.
+func (b *Builder) audit(name, doc string) {
+	if !b.opts.CheckA11y {
+		return
+	}
+	for _, issue := range auditA11y(name, doc) {
+		b.warn(issue)
+	}
+}
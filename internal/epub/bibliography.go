@@ -0,0 +1,250 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// bibliographyFileName is the EPUB path of the generated bibliography chapter.
+const bibliographyFileName = "content/bibliography.xhtml"
+
+// citeRe matches citation placeholders produced by the parsers.
+var citeRe = regexp.MustCompile(`<cite data-cite="([^"]*)"(?: data-locator="([^"]*)")?></cite>`)
+
+// addBibliography formats citation placeholders as author-date links and
+// appends a bibliography chapter listing the cited references. When the
+// document has references but no citations, all references are listed.
+func (b *Builder) addBibliography(doc *model.Document) {
+	refs := make(map[string]model.Reference, len(doc.References))
+	for _, ref := range doc.References {
+		refs[ref.Key] = ref
+	}
+
+	var cited []model.Reference
+	seen := make(map[string]bool)
+	missing := make(map[string]bool)
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = citeRe.ReplaceAllStringFunc(chapter.Content, func(placeholder string) string {
+			m := citeRe.FindStringSubmatch(placeholder)
+			key, locator := m[1], m[2]
+
+			ref, ok := refs[key]
+			if !ok {
+				if !missing[key] {
+					missing[key] = true
+					b.warn(model.Warning{
+						Code:    model.WarnMissingCitation,
+						File:    chapter.FileName,
+						Message: fmt.Sprintf("Citation @%s in %s is not in the bibliography", key, chapter.FileName),
+					})
+				}
+				// Unknown keys are shown as-is so they are easy to spot
+				return "<cite>" + key + "?</cite>"
+			}
+			if !seen[key] {
+				seen[key] = true
+				cited = append(cited, ref)
+			}
+
+			href := relativeHref(chapter.FileName, bibliographyFileName) + "#" + referenceID(key)
+			cite := `<cite><a epub:type="biblioref" role="doc-biblioref" href="` + html.EscapeString(href) + `">` +
+				html.EscapeString(citationLabel(ref)) + "</a>"
+			if locator != "" {
+				cite += ", " + locator
+			}
+			return cite + "</cite>"
+		})
+	}
+
+	if len(cited) == 0 {
+		cited = doc.References
+	}
+	if len(cited) == 0 {
+		return
+	}
+
+	title := b.localizedStrings().Bibliography
+	doc.AddChapter(model.Chapter{
+		ID:       "bibliography",
+		Title:    title,
+		Level:    1,
+		Content:  renderBibliography(cited, title),
+		FileName: bibliographyFileName,
+		Order:    len(doc.Chapters),
+		Type:     "backmatter",
+	})
+	doc.TOC.AddEntry(model.TOCEntry{Title: title, Href: bibliographyFileName, Level: 1})
+	b.landmarks = append(b.landmarks, landmark{Type: "bibliography", Href: bibliographyFileName, Title: title})
+}
+
+// referenceID returns the element id of a bibliography entry.
+func referenceID(key string) string {
+	return "ref-" + key
+}
+
+// citationLabel returns the author-date label for a reference, such as
+// "Smith 2020", "Smith and Doe 2020", or "Smith et al. 2020".
+func citationLabel(ref model.Reference) string {
+	var names string
+	switch len(ref.Authors) {
+	case 0:
+		names = ref.Title
+	case 1:
+		names = ref.Authors[0].Family
+	case 2:
+		names = ref.Authors[0].Family + " and " + ref.Authors[1].Family
+	default:
+		names = ref.Authors[0].Family + " et al."
+	}
+
+	if ref.Year == "" {
+		return names
+	}
+	return strings.TrimSpace(names + " " + ref.Year)
+}
+
+// renderBibliography renders references sorted by first author and year.
+func renderBibliography(refs []model.Reference, title string) string {
+	sorted := make([]model.Reference, len(refs))
+	copy(sorted, refs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := strings.ToLower(sortName(sorted[i])), strings.ToLower(sortName(sorted[j]))
+		if a != b {
+			return a < b
+		}
+		return sorted[i].Year < sorted[j].Year
+	})
+
+	var buf strings.Builder
+	buf.WriteString("<section epub:type=\"bibliography\" role=\"doc-bibliography\">\n  <h1>" + html.EscapeString(title) + "</h1>\n")
+	buf.WriteString("  <ul>\n")
+	for _, ref := range sorted {
+		buf.WriteString(`    <li id="` + html.EscapeString(referenceID(ref.Key)) + `" epub:type="biblioentry" role="doc-biblioentry">`)
+		buf.WriteString(formatReference(ref))
+		buf.WriteString("</li>\n")
+	}
+	buf.WriteString("  </ul>\n</section>")
+	return buf.String()
+}
+
+// sortName returns the name a reference is sorted by.
+func sortName(ref model.Reference) string {
+	if len(ref.Authors) > 0 {
+		return ref.Authors[0].Family
+	}
+	return ref.Title
+}
+
+// formatReference formats a reference in author-date style as XHTML:
+// authors, year, title (italic for standalone works, quoted for works in a
+// container), container, volume and pages, publisher, and DOI or URL.
+func formatReference(ref model.Reference) string {
+	var parts []string
+
+	if authors := formatAuthors(ref.Authors); authors != "" {
+		parts = append(parts, html.EscapeString(authors))
+	}
+	if ref.Year != "" {
+		parts = append(parts, html.EscapeString(ref.Year))
+	}
+
+	if ref.Container != "" {
+		parts = append(parts, "“"+html.EscapeString(terminate(ref.Title))+"”")
+		container := "<i>" + html.EscapeString(ref.Container) + "</i>"
+		if ref.Volume != "" {
+			container += " " + html.EscapeString(ref.Volume)
+		}
+		if ref.Pages != "" {
+			container += ": " + html.EscapeString(ref.Pages)
+		}
+		parts = append(parts, container)
+	} else if ref.Title != "" {
+		parts = append(parts, "<i>"+html.EscapeString(ref.Title)+"</i>")
+	}
+
+	if ref.Publisher != "" {
+		parts = append(parts, html.EscapeString(ref.Publisher))
+	}
+
+	link := ref.URL
+	if ref.DOI != "" {
+		link = "https://doi.org/" + ref.DOI
+	}
+	if link != "" {
+		escaped := html.EscapeString(link)
+		parts = append(parts, `<a href="`+escaped+`">`+escaped+"</a>")
+	}
+
+	var buf strings.Builder
+	for i, part := range parts {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+		buf.WriteString(part)
+		if !endsSentence(part) {
+			buf.WriteString(".")
+		}
+	}
+	return buf.String()
+}
+
+// terminate appends a period to s unless it already ends a sentence.
+func terminate(s string) string {
+	if endsSentence(s) {
+		return s
+	}
+	return s + "."
+}
+
+// endsSentence reports whether text, ignoring closing tags and quotes,
+// ends with sentence punctuation.
+func endsSentence(text string) bool {
+	for {
+		trimmed := strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(text, "</i>"), "</a>"), "”")
+		if trimmed == text {
+			break
+		}
+		text = trimmed
+	}
+	return strings.HasSuffix(text, ".") || strings.HasSuffix(text, "?") || strings.HasSuffix(text, "!")
+}
+
+// formatAuthors lists authors with the first inverted ("Smith, John") and
+// the rest in natural order, joined with commas and "and".
+func formatAuthors(authors []model.Author) string {
+	names := make([]string, 0, len(authors))
+	for i, a := range authors {
+		switch {
+		case a.Given == "":
+			names = append(names, a.Family)
+		case i == 0:
+			names = append(names, a.Family+", "+a.Given)
+		default:
+			names = append(names, a.Given+" "+a.Family)
+		}
+	}
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + ", and " + names[1]
+	default:
+		return strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]
+	}
+}
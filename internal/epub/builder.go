@@ -12,58 +12,153 @@ import (
 	"archive/zip"
 	"bytes"
 	"fmt"
+	"html"
 	"io"
+	"os"
 
 	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
-// Builder creates valid EPUB 3+ packages from Document models.
+// Builder creates valid EPUB 3+ packages from Document models. Each build
+// runs on its own copy of the builder, so a configured Builder can build
+// books from several goroutines; SetOptions and SetTemplates must not be
+// called while builds are running.
 type Builder struct {
-	doc *model.Document
+	doc       *model.Document // Book being built; set only on per-build copies
+	templates *Templates
+	opts      Options
+	landmarks []landmark // Landmarks for typed and generated chapters
 }
 
 // NewBuilder creates a new EPUB builder.
 func NewBuilder() *Builder {
-	return &Builder{}
+	return &Builder{templates: DefaultTemplates()}
+}
+
+// SetTemplates replaces the templates used for content, nav, and package documents.
+func (b *Builder) SetTemplates(t *Templates) {
+	if t == nil {
+		t = DefaultTemplates()
+	}
+	b.templates = t
 }
 
 // Build generates an EPUB file from the document and returns the bytes.
+// Prefer WriteTo for large books to avoid holding the archive in memory.
 func (b *Builder) Build(doc *model.Document) ([]byte, error) {
-	b.doc = doc
+	var buf bytes.Buffer
+	if err := b.WriteTo(doc, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SetOptions configures EPUB generation options.
+func (b *Builder) SetOptions(opts Options) {
+	b.opts = opts
+}
 
+// WriteTo generates an EPUB file from the document and streams it to w.
+// Zip entries are written as they are generated, and resources without
+// in-memory data are copied directly from their source path. Building adds
+// generated pages to doc, so each document is written once.
+func (b *Builder) WriteTo(doc *model.Document, w io.Writer) error {
+	build := &Builder{doc: doc, templates: b.templates, opts: b.opts}
+	return build.write(doc, w)
+}
+
+// WriteToFile generates an EPUB file and streams it to the specified writer.
+//
+// Deprecated: use WriteTo.
+func (b *Builder) WriteToFile(doc *model.Document, w io.Writer) error {
+	return b.WriteTo(doc, w)
+}
+
+// write runs the build pipeline on a per-build copy of the builder.
+func (b *Builder) write(doc *model.Document, w io.Writer) error {
 	// Ensure document has required metadata
 	doc.Metadata.EnsureDefaults()
 
 	if !doc.Valid() {
-		return nil, fmt.Errorf("invalid document: missing title or chapters")
+		return fmt.Errorf("%w: missing title or chapters", ErrInvalidDocument)
 	}
 
-	// Add colophon page at the end
-	b.addColophon(doc)
+	// Check the reading order before generated pages are added
+	if err := b.checkReadingOrder(doc); err != nil {
+		return err
+	}
+	b.addChapterLandmarks(doc)
 
-	var buf bytes.Buffer
-	if err := b.writeEPUB(&buf); err != nil {
-		return nil, fmt.Errorf("building EPUB: %w", err)
+	// Number section headings
+	if b.opts.NumberSections {
+		b.numberSections(doc)
 	}
 
-	return buf.Bytes(), nil
-}
+	// Style chapter openings with drop caps and spacing
+	b.applyChapterOpeners(doc)
 
-// WriteToFile generates an EPUB file and writes it to the specified writer.
-func (b *Builder) WriteToFile(doc *model.Document, w io.Writer) error {
-	data, err := b.Build(doc)
-	if err != nil {
+	// Mark explicit page breaks for navigation
+	if b.opts.PageBreaks {
+		b.markPageBreaks(doc)
+	}
+
+	// Number labeled figures and tables and resolve references to them
+	b.numberCrossRefs(doc)
+
+	// Gather chapter endnotes into a notes chapter
+	b.gatherNotes(doc)
+
+	// Render equations as images for reading systems without MathML
+	b.renderMath(doc)
+
+	// Add glossary when the document defines terms
+	b.addGlossary(doc)
+
+	// Add bibliography when the document has references
+	b.addBibliography(doc)
+
+	// Add back-of-book index when chapters contain index term markers
+	b.addIndex(doc)
+
+	// Mark terms for text-to-speech once generated pages hold their text
+	b.addPronunciations(doc)
+
+	// Add visible contents page at the start
+	if b.opts.TOCPage {
+		b.addTOCPage(doc)
+	}
+
+	// Add colophon page at the end
+	b.addColophon(doc)
+
+	// Rename colliding manifest and element ids
+	b.repairIDs(doc)
+	b.checkLandmarks(doc)
+
+	// Check that links resolve within the finished book
+	if err := b.checkLinks(); err != nil {
 		return err
 	}
-	_, err = w.Write(data)
-	return err
+
+	if err := b.writeEPUB(w); err != nil {
+		return fmt.Errorf("building EPUB: %w", err)
+	}
+
+	return nil
 }
 
 // writeEPUB creates the complete EPUB archive.
 func (b *Builder) writeEPUB(w io.Writer) error {
 	zw := zip.NewWriter(w)
-	defer zw.Close()
+	if err := b.writeEntries(zw); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
 
+// writeEntries writes all EPUB entries to the zip archive.
+func (b *Builder) writeEntries(zw *zip.Writer) error {
 	// 1. Write mimetype first (must be uncompressed and first entry)
 	if err := b.writeMimetype(zw); err != nil {
 		return fmt.Errorf("writing mimetype: %w", err)
@@ -74,6 +169,13 @@ func (b *Builder) writeEPUB(w io.Writer) error {
 		return fmt.Errorf("writing container.xml: %w", err)
 	}
 
+	// 2b. Write the Apple Books display options
+	if b.opts.Profile == ProfileApple {
+		if err := b.writeAppleDisplayOptions(zw); err != nil {
+			return fmt.Errorf("writing display options: %w", err)
+		}
+	}
+
 	// 3. Write OEBPS/content.opf (package document)
 	if err := b.writePackageDocument(zw); err != nil {
 		return fmt.Errorf("writing content.opf: %w", err)
@@ -84,6 +186,13 @@ func (b *Builder) writeEPUB(w io.Writer) error {
 		return fmt.Errorf("writing nav.xhtml: %w", err)
 	}
 
+	// 4b. Write OEBPS/toc.ncx for EPUB 2 reading systems
+	if b.opts.legacy() {
+		if err := b.writeNCX(zw); err != nil {
+			return fmt.Errorf("writing toc.ncx: %w", err)
+		}
+	}
+
 	// 5. Write OEBPS/content/*.xhtml (content documents)
 	if err := b.writeContentDocuments(zw); err != nil {
 		return fmt.Errorf("writing content documents: %w", err)
@@ -95,8 +204,10 @@ func (b *Builder) writeEPUB(w io.Writer) error {
 	}
 
 	// 7. Write default stylesheet
-	if err := b.writeDefaultStylesheet(zw); err != nil {
-		return fmt.Errorf("writing stylesheet: %w", err)
+	if b.opts.defaultCSS() {
+		if err := b.writeDefaultStylesheet(zw); err != nil {
+			return fmt.Errorf("writing stylesheet: %w", err)
+		}
 	}
 
 	return nil
@@ -117,6 +228,16 @@ func (b *Builder) writeMimetype(zw *zip.Writer) error {
 	return err
 }
 
+// writeAppleDisplayOptions writes META-INF/com.apple.ibooks.display-options.xml.
+func (b *Builder) writeAppleDisplayOptions(zw *zip.Writer) error {
+	w, err := zw.Create("META-INF/com.apple.ibooks.display-options.xml")
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, appleDisplayOptions(b.doc.Metadata.Rendition.FixedLayout()))
+	return err
+}
+
 // writeContainer writes META-INF/container.xml.
 func (b *Builder) writeContainer(zw *zip.Writer) error {
 	w, err := zw.Create("META-INF/container.xml")
@@ -142,7 +263,12 @@ func (b *Builder) writePackageDocument(zw *zip.Writer) error {
 		return err
 	}
 
-	opf, err := generatePackageDocument(b.doc)
+	var opf string
+	if b.templates.pkg != nil {
+		opf, err = generatePackageFromTemplate(b.templates.pkg, b.doc, b.opts)
+	} else {
+		opf, err = generatePackageDocument(b.doc, b.opts)
+	}
 	if err != nil {
 		return err
 	}
@@ -153,12 +279,16 @@ func (b *Builder) writePackageDocument(zw *zip.Writer) error {
 
 // writeNavDocument writes OEBPS/nav.xhtml.
 func (b *Builder) writeNavDocument(zw *zip.Writer) error {
-	w, err := zw.Create("OEBPS/nav.xhtml")
+	nav, err := generateNavDocument(b.templates.nav, b.doc, b.tocEntries(), b.landmarks, b.localizedStrings(), b.opts)
 	if err != nil {
 		return err
 	}
+	if err := b.verify("nav.xhtml", nav); err != nil {
+		return err
+	}
+	b.audit("nav.xhtml", nav)
 
-	nav, err := generateNavDocument(b.doc)
+	w, err := zw.Create("OEBPS/nav.xhtml")
 	if err != nil {
 		return err
 	}
@@ -167,23 +297,63 @@ func (b *Builder) writeNavDocument(zw *zip.Writer) error {
 	return err
 }
 
-// writeContentDocuments writes OEBPS/content/*.xhtml files.
+// writeNCX writes OEBPS/toc.ncx.
+func (b *Builder) writeNCX(zw *zip.Writer) error {
+	w, err := zw.Create("OEBPS/" + ncxFile)
+	if err != nil {
+		return err
+	}
+
+	ncx, err := generateNCX(b.doc, b.tocEntries())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write([]byte(ncx))
+	return err
+}
+
+// tocEntries returns the navigation entries limited to the configured depth.
+func (b *Builder) tocEntries() []model.TOCEntry {
+	return b.doc.TOC.Truncate(b.opts.TOCDepth).Entries
+}
+
+// writeContentDocuments writes OEBPS/content/*.xhtml files. Documents are
+// rendered and deflated concurrently, then written in reading order, so
+// problems are reported in the same order on every build.
 func (b *Builder) writeContentDocuments(zw *zip.Writer) error {
-	for _, chapter := range b.doc.Chapters {
-		path := "OEBPS/" + chapter.FileName
-		w, err := zw.Create(path)
-		if err != nil {
-			return err
+	done := make(chan struct{})
+	defer close(done)
+	results, release := b.renderChapters(done)
+
+	for i, chapter := range b.doc.Chapters {
+		r := <-results[i]
+		release()
+		if r.err != nil {
+			return r.err
+		}
+		if r.malformed != nil {
+			if err := b.malformed(chapter.FileName, r.malformed); err != nil {
+				return err
+			}
+		}
+		for _, issue := range r.issues {
+			b.warn(issue)
 		}
 
-		content, err := generateContentDocument(&chapter, b.doc.Metadata.Title)
+		w, err := zw.CreateRaw(&r.header)
 		if err != nil {
 			return err
 		}
-
-		if _, err := w.Write([]byte(content)); err != nil {
+		if _, err := w.Write(r.data); err != nil {
 			return err
 		}
+		if b.opts.Progress != nil {
+			b.opts.Progress(i+1, len(b.doc.Chapters))
+		}
+	}
+	if b.opts.Chapters != nil {
+		b.opts.Chapters.sweep()
 	}
 	return nil
 }
@@ -191,18 +361,60 @@ func (b *Builder) writeContentDocuments(zw *zip.Writer) error {
 // writeResources writes embedded resources (images, etc.).
 func (b *Builder) writeResources(zw *zip.Writer) error {
 	for _, resource := range b.doc.Resources {
-		path := "OEBPS/" + resource.FileName
-		w, err := zw.Create(path)
+		header := &zip.FileHeader{
+			Name:   "OEBPS/" + resource.FileName,
+			Method: zip.Deflate,
+		}
+		// Audio and video are already compressed; storing them avoids wasted
+		// CPU and lets reading systems seek within the file
+		if resource.IsAudio() || resource.IsVideo() {
+			header.Method = zip.Store
+		}
+		w, err := zw.CreateHeader(header)
 		if err != nil {
 			return err
 		}
-		if _, err := w.Write(resource.Data); err != nil {
-			return err
+		if resource.MediaType == "text/css" && b.opts.Profile == ProfileKindle {
+			err = writeRestrictedStylesheet(w, &resource, b.opts.Profile)
+		} else {
+			err = writeResourceData(w, &resource)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", resource.FileName, err)
 		}
 	}
 	return nil
 }
 
+// writeRestrictedStylesheet writes a stylesheet resource without the
+// declarations the profile does not support.
+func writeRestrictedStylesheet(w io.Writer, resource *model.Resource, profile string) error {
+	var buf bytes.Buffer
+	if err := writeResourceData(&buf, resource); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, restrictCSS(buf.String(), profile))
+	return err
+}
+
+// writeResourceData writes a resource's in-memory data, or streams it from
+// its source path when the data has not been loaded.
+func writeResourceData(w io.Writer, resource *model.Resource) error {
+	if len(resource.Data) > 0 || resource.SourcePath == "" {
+		_, err := w.Write(resource.Data)
+		return err
+	}
+
+	f, err := os.Open(resource.SourcePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
 // writeDefaultStylesheet writes a basic stylesheet.
 func (b *Builder) writeDefaultStylesheet(zw *zip.Writer) error {
 	w, err := zw.Create("OEBPS/styles/default.css")
@@ -320,16 +532,81 @@ a:hover {
 .task-list-item input {
   margin-right: 0.5em;
 }
-`
 
-	_, err = w.Write([]byte(css))
+/* Explicit page breaks */
+.page-break {
+  page-break-before: always;
+  break-before: page;
+}
+
+/* Photo pages made from image directories */
+figure.gallery {
+  margin: 0;
+  text-align: center;
+  page-break-inside: avoid;
+  break-inside: avoid;
+}
+
+figure.gallery img {
+  max-height: 90vh;
+}
+
+figure.gallery figcaption {
+  font-size: 0.9em;
+  font-style: italic;
+  margin-top: 0.5em;
+}
+
+.gallery-grid {
+  display: flex;
+  flex-wrap: wrap;
+  justify-content: space-between;
+}
+
+.gallery-grid figure.gallery {
+  width: 48%;
+  margin-bottom: 1em;
+}
+
+.gallery-grid figure.gallery img {
+  max-height: 40vh;
+}
+
+/* Ruby annotations, such as furigana */
+ruby {
+  -epub-ruby-position: over;
+  ruby-position: over;
+}
+
+rt {
+  font-size: 0.5em;
+  line-height: 1;
+}
+
+/* Equations rendered as images */
+img.math-display {
+  display: block;
+  margin: 0.5em auto;
+}
+` + b.chapterOpenerCSS() + b.themeCSS()
+
+	_, err = w.Write([]byte(restrictCSS(css, b.opts.Profile)))
 	return err
 }
 
-// addColophon adds an attribution page at the end of the book.
+// addColophon adds an attribution page at the end of the book, led by the
+// rights statement and license link when the book has them.
 func (b *Builder) addColophon(doc *model.Document) {
-	colophonContent := `<hr style="margin: 3em 0;"/>
-<div style="text-align: center; font-family: monospace; white-space: pre-wrap; padding: 2em 1em; background-color: #f9f9f9; border: 1px solid #ddd; margin: 2em 0;">
+	colophonContent := ""
+	if rights := doc.Metadata.Rights; rights != "" {
+		colophonContent += fmt.Sprintf("<p class=\"rights\">%s</p>\n", html.EscapeString(rights))
+	}
+	if license, ok := doc.Metadata.LicenseInfo(); ok && license.URL != "" {
+		colophonContent += fmt.Sprintf("<p class=\"license\"><a rel=\"license\" href=\"%s\">%s</a></p>\n",
+			html.EscapeString(license.URL), html.EscapeString(license.Name))
+	}
+	colophonContent += `<hr style="margin: 3em 0;"/>
+<div class="packaged-by" style="text-align: center; font-family: monospace; white-space: pre-wrap; padding: 2em 1em; background-color: #f9f9f9; border: 1px solid #ddd; margin: 2em 0;">
 ------------------------------------------------------------------
 Packaged by Epub Converter Application (c) 2025 Dau Quang Thanh.
 
@@ -341,12 +618,36 @@ Happy Reading!
 
 	colophon := model.Chapter{
 		ID:       "colophon",
-		Title:    "About This EPUB",
+		Title:    b.localizedStrings().Colophon,
 		Level:    1,
 		Content:  colophonContent,
 		FileName: "content/colophon.xhtml",
 		Order:    len(doc.Chapters),
+		Type:     "backmatter colophon",
 	}
 
 	doc.AddChapter(colophon)
 }
+
+// addTOCPage inserts a human-readable contents page before the first chapter.
+func (b *Builder) addTOCPage(doc *model.Document) {
+	const fileName = "content/toc.xhtml"
+
+	title := b.localizedStrings().TableOfContents
+	content := "<section epub:type=\"toc\">\n  <h1>" + html.EscapeString(title) + "</h1>\n" +
+		renderTOCList(b.tocEntries(), fileName) + "\n</section>"
+
+	tocPage := model.Chapter{
+		ID:       "toc-page",
+		Title:    title,
+		Level:    1,
+		Content:  content,
+		FileName: fileName,
+		Type:     "frontmatter",
+	}
+
+	doc.Chapters = append([]model.Chapter{tocPage}, doc.Chapters...)
+	for i := range doc.Chapters {
+		doc.Chapters[i].Order = i
+	}
+}
@@ -3,6 +3,13 @@ package epub
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -160,3 +167,1205 @@ func TestBuilder_Build_MultipleChapters(t *testing.T) {
 		assert.True(t, fileNames[fileName], "Missing: "+fileName)
 	}
 }
+
+func TestBuilder_Build_FixedLayout(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Picture Book"
+	doc.Metadata.Rendition = model.Rendition{
+		Layout: model.LayoutPrePaginated,
+		Spread: "landscape",
+	}
+	doc.AddChapter(model.Chapter{
+		ID:       "page-001",
+		Title:    "Page 1",
+		Content:  "<p>Page</p>",
+		FileName: "content/page-001.xhtml",
+		Spread:   "right",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<meta property="rendition:layout">pre-paginated</meta>`)
+	assert.Contains(t, opf, `<meta property="rendition:spread">landscape</meta>`)
+	assert.Contains(t, opf, `<itemref idref="page-001" properties="page-spread-right"></itemref>`)
+
+	page := readZipEntry(t, data, "OEBPS/content/page-001.xhtml")
+	assert.Contains(t, page, `<meta name="viewport" content="width=1200, height=1600"/>`)
+}
+
+// readZipEntry returns the contents of a named file in an EPUB archive.
+func readZipEntry(t *testing.T, data []byte, name string) string {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	for _, f := range reader.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		require.NoError(t, err)
+		defer rc.Close()
+
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Fatalf("entry %s not found", name)
+	return ""
+}
+
+func TestBuilder_Build_RightToLeft(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "كتاب"
+	doc.Metadata.Language = "ar"
+	doc.Metadata.Direction = model.DirectionRTL
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "الفصل الأول",
+		Content:  "<p>مرحبا</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<spine page-progression-direction="rtl">`)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `dir="rtl"`)
+}
+
+func TestBuilder_Build_MultipleIdentifiers(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Identified"
+	doc.Metadata.AddIdentifier(model.Identifier{Scheme: model.SchemeISBN, Value: "9780306406157"})
+	doc.Metadata.AddIdentifier(model.Identifier{Scheme: model.SchemeDOI, Value: "10.1000/182"})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<dc:identifier id="uid">9780306406157</dc:identifier>`)
+	assert.Contains(t, opf, `<meta refines="#uid" property="identifier-type" scheme="onix:codelist5">15</meta>`)
+	assert.Contains(t, opf, `<dc:identifier id="id-2">10.1000/182</dc:identifier>`)
+	assert.Contains(t, opf, `<meta refines="#id-2" property="identifier-type" scheme="onix:codelist5">06</meta>`)
+}
+
+func TestBuilder_Build_ContributorRoles(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Translated Book"
+	doc.Metadata.Authors = []string{"Original Author"}
+	doc.Metadata.Contributors = []model.Contributor{
+		{Name: "Jane Translator", Role: model.RoleTranslator},
+	}
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<dc:creator id="creator-1">Original Author</dc:creator>`)
+	assert.Contains(t, opf, `<dc:contributor id="contributor-1">Jane Translator</dc:contributor>`)
+	assert.Contains(t, opf, `<meta refines="#contributor-1" property="role" scheme="marc:relators">trl</meta>`)
+	assert.NotContains(t, opf, `<dc:creator id="creator-2">`)
+}
+
+func TestBuilder_Build_CalibreMetadata(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Volume Two"
+	doc.Metadata.Collections = []model.Collection{{Name: "The Saga", Type: model.CollectionSeries, Position: "2"}}
+	doc.Metadata.CalibreColumns = []model.CalibreColumn{
+		{Label: "genre", Datatype: model.CalibreText, Values: []string{"Fantasy", "Epic"}},
+		{Label: "read", Datatype: model.CalibreBool, Values: []string{"true"}},
+		{Label: "pages", Datatype: model.CalibreInt, Values: []string{"many"}},
+	}
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	var pkg struct {
+		Metas []struct {
+			Name    string `xml:"name,attr"`
+			Content string `xml:"content,attr"`
+		} `xml:"metadata>meta"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(readZipEntry(t, data, "OEBPS/content.opf")), &pkg))
+	metas := make(map[string]string)
+	for _, m := range pkg.Metas {
+		if m.Name != "" {
+			metas[m.Name] = m.Content
+		}
+	}
+
+	assert.Equal(t, "The Saga", metas["calibre:series"])
+	assert.Equal(t, "2", metas["calibre:series_index"])
+
+	var genre map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(metas["calibre:user_metadata:#genre"]), &genre))
+	assert.Equal(t, "text", genre["datatype"])
+	assert.Equal(t, []interface{}{"Fantasy", "Epic"}, genre["#value#"])
+	assert.Equal(t, "|", genre["is_multiple"].(map[string]interface{})["cache_to_list"])
+
+	var read map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(metas["calibre:user_metadata:#read"]), &read))
+	assert.Equal(t, true, read["#value#"])
+
+	// A value that does not fit the column's datatype is left out
+	assert.NotContains(t, metas, "calibre:user_metadata:#pages")
+}
+
+func TestBuilder_Build_License(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Open Book"
+	doc.Metadata.Authors = []string{"Jane Doe"}
+	doc.Metadata.License = "cc-by-4.0"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `prefix="cc: http://creativecommons.org/ns#"`)
+	assert.Contains(t, opf, `<link rel="cc:license" href="https://creativecommons.org/licenses/by/4.0/"></link>`)
+	assert.Contains(t, opf, "Jane Doe. This work is licensed under the Creative Commons Attribution 4.0 International License (CC BY 4.0).</dc:rights>")
+
+	colophon := readZipEntry(t, data, "OEBPS/content/colophon.xhtml")
+	assert.Contains(t, colophon, `<a rel="license" href="https://creativecommons.org/licenses/by/4.0/">`)
+}
+
+func TestBuilder_WriteTo_StreamsResourceFromSource(t *testing.T) {
+	builder := NewBuilder()
+
+	imagePath := filepath.Join(t.TempDir(), "photo.png")
+	imageData := []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+	require.NoError(t, os.WriteFile(imagePath, imageData, 0644))
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Streamed"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddResource(model.Resource{
+		ID:         "img-photo",
+		FileName:   "images/photo.png",
+		MediaType:  "image/png",
+		SourcePath: imagePath,
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, builder.WriteTo(doc, &buf))
+	assert.Equal(t, string(imageData), readZipEntry(t, buf.Bytes(), "OEBPS/images/photo.png"))
+}
+
+func TestBuilder_Build_CustomContentTemplate(t *testing.T) {
+	dir := t.TempDir()
+	custom := `<?xml version="1.0" encoding="UTF-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml">
+<head><title>{{.Title}}</title></head>
+<body><header>ACME Press</header>{{.Content}}</body>
+</html>`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ContentTemplateFile), []byte(custom), 0644))
+
+	templates, err := LoadTemplates(dir)
+	require.NoError(t, err)
+
+	builder := NewBuilder()
+	builder.SetTemplates(templates)
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Templated"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, "<header>ACME Press</header><p>Content</p>")
+
+	// Templates not present in the directory fall back to the defaults
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `<nav epub:type="toc" id="toc">`)
+}
+
+func TestBuilder_Build_CustomPackageTemplate(t *testing.T) {
+	dir := t.TempDir()
+	custom := `<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
+<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>{{.Title}}</dc:title></metadata>
+<spine>{{range .Spine}}<itemref idref="{{.ID}}"/>{{end}}</spine>
+</package>`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, PackageTemplateFile), []byte(custom), 0644))
+
+	templates, err := LoadTemplates(dir)
+	require.NoError(t, err)
+
+	builder := NewBuilder()
+	builder.SetTemplates(templates)
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Tom & Jerry"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<p>Content</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, "<dc:title>Tom &amp; Jerry</dc:title>")
+	assert.Contains(t, opf, `<itemref idref="ch1"/>`)
+}
+
+func TestLoadTemplates_InvalidTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, NavTemplateFile), []byte("{{.Broken"), 0644))
+
+	_, err := LoadTemplates(dir)
+	assert.Error(t, err)
+}
+
+func TestBuilder_Build_LocalizedNavigation(t *testing.T) {
+	newDoc := func(lang string) *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Livre"
+		doc.Metadata.Language = lang
+		doc.AddChapter(model.Chapter{
+			ID:       "ch1",
+			Title:    "Chapitre 1",
+			Content:  "<p>Contenu</p>",
+			FileName: "content/chapter-001.xhtml",
+		})
+		return doc
+	}
+
+	data, err := NewBuilder().Build(newDoc("fr-CA"))
+	require.NoError(t, err)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, "<h1>Table des matières</h1>")
+	assert.NotContains(t, nav, "Table of Contents")
+
+	builder := NewBuilder()
+	builder.SetOptions(Options{NavTitle: "Sommaire"})
+	data, err = builder.Build(newDoc("fr"))
+	require.NoError(t, err)
+
+	nav = readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, "<h1>Sommaire</h1>")
+}
+
+func TestBuilder_Build_ChapterStylesheets(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Styled"
+	doc.AddChapter(model.Chapter{
+		ID:          "ch1",
+		Title:       "Chapter 1",
+		Content:     "<p>Content</p>",
+		FileName:    "content/chapter-001.xhtml",
+		Stylesheets: []string{"styles/intro.css"},
+	})
+	doc.AddResource(model.Resource{
+		ID:        "css-intro",
+		FileName:  "styles/intro.css",
+		MediaType: "text/css",
+		Data:      []byte("p { color: red; }"),
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `href="../styles/default.css"`)
+	assert.Contains(t, chapter, `href="../styles/intro.css"`)
+
+	colophon := readZipEntry(t, data, "OEBPS/content/colophon.xhtml")
+	assert.NotContains(t, colophon, "intro.css")
+}
+
+func TestBuilder_Build_TOCPage(t *testing.T) {
+	builder := NewBuilder()
+	builder.SetOptions(Options{TOCPage: true})
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "With Contents"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  "<h1 id=\"intro\">Chapter 1</h1>",
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.TOC.AddEntry(model.TOCEntry{Title: "Chapter 1", Href: "content/chapter-001.xhtml#intro", Level: 1})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	page := readZipEntry(t, data, "OEBPS/content/toc.xhtml")
+	assert.Contains(t, page, `<body epub:type="frontmatter">`)
+	assert.Contains(t, page, `<a href="chapter-001.xhtml#intro">Chapter 1</a>`)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Regexp(t, `<spine>\s*<itemref idref="toc-page"></itemref>\s*<itemref idref="ch1"></itemref>`, opf)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `<a href="content/chapter-001.xhtml#intro">Chapter 1</a>`)
+	assert.Contains(t, nav, `<a epub:type="bodymatter" href="content/chapter-001.xhtml">`)
+}
+
+func TestBuilder_Build_Index(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Indexed"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "First",
+		Content:  `<p>About <span data-index="zebra"></span>zebras and <span data-index>Apples</span>.</p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch2",
+		Title:    "Second",
+		Content:  `<p>More <span id="z2" data-index="Zebra"></span>zebras.</p>`,
+		FileName: "content/chapter-002.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<span id="idx-1" data-index="zebra"></span>`)
+	assert.Contains(t, chapter, `<span id="idx-2" data-index="Apples">Apples</span>`)
+
+	index := readZipEntry(t, data, "OEBPS/content/index.xhtml")
+	assert.Contains(t, index, `<body epub:type="backmatter">`)
+	assert.Regexp(t, `(?s)Apples.*<h2>Z</h2>`, index)
+	assert.Contains(t, index, `<span epub:type="index-term">zebra</span> `+
+		`<a epub:type="index-locator" href="chapter-001.xhtml#idx-1">First</a>, `+
+		`<a epub:type="index-locator" href="chapter-002.xhtml#z2">Second</a>`)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `<a href="content/index.xhtml">Index</a>`)
+}
+
+func TestBuilder_Build_NoIndexWithoutMarkers(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Plain"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "First",
+		Content:  "<p>No markers</p>",
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, f := range reader.File {
+		assert.NotEqual(t, "OEBPS/content/index.xhtml", f.Name)
+	}
+}
+
+func TestBuilder_Build_Bibliography(t *testing.T) {
+	builder := NewBuilder()
+	var events []model.Warning
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Cited"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "First",
+		Content:  `<p>As shown <cite data-cite="doe2019" data-locator="p. 4"></cite> and <cite data-cite="nobody"></cite>, <cite data-cite="nobody"></cite>.</p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddReference(model.Reference{
+		Key:       "doe2019",
+		Authors:   []model.Author{{Family: "Doe", Given: "Jane"}, {Family: "Roe", Given: "Rick"}},
+		Title:     "A Study",
+		Container: "Science",
+		Year:      "2019",
+	})
+	doc.AddReference(model.Reference{Key: "uncited", Title: "Not Listed"})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<cite><a epub:type="biblioref" role="doc-biblioref" href="bibliography.xhtml#ref-doe2019">Doe and Roe 2019</a>, p. 4</cite>`)
+	assert.Contains(t, chapter, `<cite>nobody?</cite>`)
+	var missing []model.Warning
+	for _, e := range events {
+		if e.Code == model.WarnMissingCitation {
+			missing = append(missing, e)
+		}
+	}
+	require.Len(t, missing, 1)
+	assert.Equal(t, "content/chapter-001.xhtml", missing[0].File)
+	assert.Equal(t, "Citation @nobody in content/chapter-001.xhtml is not in the bibliography", missing[0].Message)
+
+	bib := readZipEntry(t, data, "OEBPS/content/bibliography.xhtml")
+	assert.Contains(t, bib, `<section epub:type="bibliography" role="doc-bibliography">`)
+	assert.Contains(t, bib, `<li id="ref-doe2019" epub:type="biblioentry" role="doc-biblioentry">Doe, Jane, and Rick Roe. 2019. “A Study.” <i>Science</i>.</li>`)
+	assert.NotContains(t, bib, "Not Listed")
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `<li><a epub:type="bibliography" href="content/bibliography.xhtml">Bibliography</a></li>`)
+}
+
+func TestBuilder_Build_CrossReferenceNumbering(t *testing.T) {
+	builder := NewBuilder()
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Numbered"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "First",
+		Content:  `<figure id="fig:a"><img src="a.png" alt=""/></figure><figure id="fig:b"><img src="b.png" alt=""/><figcaption>Bee</figcaption></figure>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch2",
+		Title:    "Second",
+		Content:  `<table id="tbl:t"><caption>Tee</caption></table><p>See <a data-ref="fig:b"></a>, <a data-ref="tbl:t"></a>, <a data-ref="fig:zz"></a>.</p>`,
+		FileName: "content/chapter-002.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	first := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, first, `<figcaption><span class="label">Figure 1.2:</span> Bee</figcaption>`)
+
+	second := readZipEntry(t, data, "OEBPS/content/chapter-002.xhtml")
+	assert.Contains(t, second, `<caption><span class="label">Table 2.1:</span> Tee</caption>`)
+	assert.Contains(t, second, `See <a href="chapter-001.xhtml#fig:b">Figure 1.2</a>, <a href="chapter-002.xhtml#tbl:t">Table 2.1</a>, ??fig:zz.`)
+}
+
+func TestBuilder_Build_NumberSections(t *testing.T) {
+	builder := NewBuilder()
+	builder.SetOptions(Options{NumberSections: true})
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Numbered"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Intro",
+		Content:  `<h1 id="intro">Intro</h1><h2 id="a">A</h2><h3 id="a1">A1</h3><h2 id="b" class="unnumbered">B</h2>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch2",
+		Title:    "Next",
+		Content:  `<h1 id="next">Next</h1><h2 id="c">C</h2>`,
+		FileName: "content/chapter-002.xhtml",
+	})
+	doc.TOC = *model.BuildFromHeadings([]model.TOCEntry{
+		{Title: "Intro", Href: "content/chapter-001.xhtml#intro", Level: 1},
+		{Title: "A", Href: "content/chapter-001.xhtml#a", Level: 2},
+		{Title: "Next", Href: "content/chapter-002.xhtml#next", Level: 1},
+		{Title: "C", Href: "content/chapter-002.xhtml#c", Level: 2},
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	first := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, first, `<h3 id="a1"><span class="section-number">1.1.1</span> A1</h3>`)
+	assert.Contains(t, first, `<h2 id="b" class="unnumbered">B</h2>`)
+
+	second := readZipEntry(t, data, "OEBPS/content/chapter-002.xhtml")
+	assert.Contains(t, second, `<h2 id="c"><span class="section-number">2.1</span> C</h2>`)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `<a href="content/chapter-001.xhtml#a">1.1 A</a>`)
+	assert.Contains(t, nav, `<a href="content/chapter-002.xhtml#next">2 Next</a>`)
+}
+
+func TestBuilder_Build_DropCapsAndChapterSpacing(t *testing.T) {
+	builder := NewBuilder()
+	builder.SetOptions(Options{DropCaps: true, ChapterSpacing: "20vh"})
+
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Fiction"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "One",
+		Content:  `<h1 id="one" class="title">One</h1><p>&#8220;Once upon a time,&#8221; she said.</p><p>Next.</p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<h1 id="one" class="chapter-opener title">One</h1>`)
+	assert.Contains(t, chapter, `<p><span class="drop-cap">&#8220;O</span>nce upon a time`)
+	assert.Contains(t, chapter, `<p>Next.</p>`)
+
+	css := readZipEntry(t, data, "OEBPS/styles/default.css")
+	assert.Contains(t, css, ".chapter-opener {\n  margin-top: 20vh;\n}")
+	assert.Contains(t, css, ".drop-cap {")
+
+	// The colophon is not body matter and keeps its plain styling
+	colophon := readZipEntry(t, data, "OEBPS/content/colophon.xhtml")
+	assert.NotContains(t, colophon, "drop-cap")
+}
+
+func TestBuilder_Build_PageBreaks(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Breaks"
+		doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: `<p>A</p><div class="page-break"></div><p>B</p>`, FileName: "content/chapter-001.xhtml"})
+		doc.AddChapter(model.Chapter{ID: "ch2", Title: "Two", Content: `<div class="page-break"></div><p>C</p>`, FileName: "content/chapter-002.xhtml"})
+		return doc
+	}
+
+	// Page breaks are always styled
+	data, err := NewBuilder().Build(newDoc())
+	require.NoError(t, err)
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/styles/default.css"), "break-before: page;")
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"), `<div class="page-break"></div>`)
+
+	// Markers are numbered across chapters when enabled
+	builder := NewBuilder()
+	builder.SetOptions(Options{PageBreaks: true})
+	data, err = builder.Build(newDoc())
+	require.NoError(t, err)
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"),
+		`<div class="page-break" id="pagebreak-1" epub:type="pagebreak" role="doc-pagebreak" title="1" aria-label="1"></div>`)
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-002.xhtml"), `id="pagebreak-2"`)
+}
+
+func TestBuilder_Build_Glossary(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Terms"
+	doc.Glossary = []model.GlossaryEntry{
+		{Term: "spine", Definition: "The reading order."},
+		{Term: "EPUB", Definition: "An <em>e-book</em> format."},
+		{Term: "R&D", Definition: "Research and development."},
+	}
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "One",
+		Content:  `<h1>The Spine</h1><p>An EPUB has a spine. Another spine, <code>epub</code>, spines, R&amp;D.</p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+
+	data, err := NewBuilder().Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<h1>The Spine</h1>`)
+	assert.Contains(t, chapter, `An <a epub:type="glossref" role="doc-glossref" href="glossary.xhtml#gloss-1">EPUB</a> has a <a epub:type="glossref" role="doc-glossref" href="glossary.xhtml#gloss-3">spine</a>. Another spine, <code>epub</code>, spines, <a epub:type="glossref" role="doc-glossref" href="glossary.xhtml#gloss-2">R&amp;D</a>.`)
+
+	glossary := readZipEntry(t, data, "OEBPS/content/glossary.xhtml")
+	assert.Contains(t, glossary, `<section epub:type="glossary" role="doc-glossary">`)
+	assert.Contains(t, glossary, `<dt id="gloss-1" epub:type="glossterm"><dfn>EPUB</dfn></dt>`+"\n"+`    <dd epub:type="glossdef">An <em>e-book</em> format.</dd>`)
+	assert.Contains(t, glossary, `<dt id="gloss-2" epub:type="glossterm"><dfn>R&amp;D</dfn></dt>`)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `epub:type="glossary" href="content/glossary.xhtml"`)
+}
+
+func TestBuilder_Build_Pronunciations(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Names"
+	doc.Metadata.Language = "en"
+	doc.Pronunciations = []model.Pronunciation{
+		{Term: "Nguyen", Phoneme: "wɪn"},
+		{Term: "New York", Phoneme: "nuː ˈjɔːrk"},
+		{Term: "York", Phoneme: "jɔːrk"},
+	}
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "One",
+		Content:  `<h1>Nguyen</h1><p>From New York to York, <code>Nguyen</code>, Nguyenville.</p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddResource(model.Resource{ID: "pls-names", FileName: "lexicons/names.pls", MediaType: model.PLSMediaType, Data: []byte("<lexicon/>")})
+
+	data, err := NewBuilder().Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `xmlns:ssml="http://www.w3.org/2001/10/synthesis"`)
+	assert.Contains(t, chapter, `<link rel="pronunciation" type="application/pls+xml" href="../lexicons/names.pls" hreflang="en"/>`+"\n"+
+		`  <link rel="pronunciation" type="application/pls+xml" href="../lexicons/pronunciations.pls" hreflang="en"/>`)
+	assert.Contains(t, chapter, `<h1><span ssml:alphabet="ipa" ssml:ph="wɪn">Nguyen</span></h1>`)
+	assert.Contains(t, chapter, `From <span ssml:alphabet="ipa" ssml:ph="nuː ˈjɔːrk">New York</span> to <span ssml:alphabet="ipa" ssml:ph="jɔːrk">York</span>, <code>Nguyen</code>, Nguyenville.`)
+
+	lexicon := readZipEntry(t, data, "OEBPS/lexicons/pronunciations.pls")
+	assert.Contains(t, lexicon, `<lexicon version="1.0" xmlns="http://www.w3.org/2005/01/pronunciation-lexicon" alphabet="ipa" xml:lang="en">`)
+	assert.Contains(t, lexicon, "<grapheme>New York</grapheme>\n    <phoneme>nuː ˈjɔːrk</phoneme>")
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content.opf"), `href="lexicons/pronunciations.pls" media-type="application/pls+xml"`)
+}
+
+func TestBuilder_Build_Math(t *testing.T) {
+	build := func(opts Options) []byte {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Math"
+		doc.AddChapter(model.Chapter{
+			ID:    "ch1",
+			Title: "One",
+			Content: `<p>Area <math><mi>π</mi><msup><mi>r</mi><mn>2</mn></msup></math>.</p>` +
+				`<math display="block" alttext="half"><mfrac><mn>1</mn><mn>2</mn></mfrac></math>`,
+			FileName: "content/chapter-001.xhtml",
+		})
+		builder := NewBuilder()
+		builder.SetOptions(opts)
+		data, err := builder.Build(doc)
+		require.NoError(t, err)
+		return data
+	}
+
+	data := build(Options{})
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"), "<math><mi>π</mi>")
+	assert.NotContains(t, readZipEntry(t, data, "OEBPS/content.opf"), "equation-001.svg")
+
+	data = build(Options{Math: MathSVG})
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.NotContains(t, chapter, "<math")
+	assert.Contains(t, chapter, `<img class="math" src="../math/equation-001.svg" alt="πr^2" style="height: `)
+	assert.Contains(t, chapter, `<img class="math-display" src="../math/equation-002.svg" alt="half"`)
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `href="math/equation-001.svg" media-type="image/svg+xml"`)
+	assert.NotContains(t, opf, `properties="mathml"`)
+	svg := readZipEntry(t, data, "OEBPS/math/equation-002.svg")
+	assert.Contains(t, svg, "<title>half</title>")
+	assert.Contains(t, svg, "<rect ")
+
+	chapter = readZipEntry(t, build(Options{Math: MathBoth}), "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<math altimg="../math/equation-001.svg" altimg-height="`)
+	assert.Contains(t, chapter, `alttext="πr^2"><mi>π</mi>`)
+	assert.NotContains(t, chapter, "epub:switch")
+
+	data = build(Options{Math: MathBoth, Version: Version30})
+	chapter = readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<epub:switch><epub:case required-namespace="http://www.w3.org/1998/Math/MathML"><math altimg=`)
+	assert.Contains(t, chapter, `</math></epub:case><epub:default><img class="math" src="../math/equation-001.svg"`)
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content.opf"), `properties="mathml switch"`)
+}
+
+func TestMathAltText(t *testing.T) {
+	tests := []struct {
+		mathML string
+		want   string
+	}{
+		{`<math><mi>x</mi><mo>=</mo><mn>1</mn></math>`, "x = 1"},
+		{`<math><mo>-</mo><mi>b</mi><mo>±</mo><msqrt><mi>d</mi></msqrt></math>`, "-b ± √d"},
+		{`<math><mfrac><mrow><mi>a</mi><mo>+</mo><mi>b</mi></mrow><mn>2</mn></mfrac></math>`, "(a + b)/2"},
+		{`<math><msubsup><mo>∑</mo><mi>i</mi><mi>n</mi></msubsup><msub><mi>x</mi><mi>i</mi></msub></math>`, "∑_i^n x_i"},
+		{`<math><mfenced><mi>a</mi><mi>b</mi></mfenced></math>`, "(a, b)"},
+		{`<math><mtable><mtr><mtd><mn>1</mn></mtd><mtd><mn>0</mn></mtd></mtr><mtr><mtd><mn>0</mn></mtd><mtd><mn>1</mn></mtd></mtr></mtable></math>`, "1, 0; 0, 1"},
+	}
+	for _, tt := range tests {
+		root, err := parseMathML(tt.mathML)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, mathAltText(root), tt.mathML)
+	}
+
+	_, err := parseMathML(`<math><mi>x</math>`)
+	assert.Error(t, err)
+}
+
+func TestBuilder_Build_BookNotes(t *testing.T) {
+	endnotes := func(chapter string) string {
+		return `<section class="footnotes" epub:type="endnotes" role="doc-endnotes"><hr /><ol>` +
+			`<li epub:type="endnote" id="fn:1" role="doc-endnote"><p>Note in ` + chapter + `. <a href="#fnref:1" role="doc-backlink">↩</a></p></li>` +
+			`</ol></section>`
+	}
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Notes"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "One",
+		Content:  `<h1>One</h1><p>Text<sup id="fnref:1"><a epub:type="noteref" href="#fn:1">1</a></sup></p>` + endnotes("one"),
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{
+		ID:       "ch2",
+		Title:    "Two",
+		Content:  `<h1>Two</h1><p>Text<sup id="fnref:1"><a epub:type="noteref" href="#fn:1">1</a></sup></p>` + endnotes("two"),
+		FileName: "content/chapter-002.xhtml",
+	})
+
+	builder := NewBuilder()
+	builder.SetOptions(Options{Notes: model.NotesBook, Strict: true})
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-002.xhtml")
+	assert.Contains(t, chapter, `<a epub:type="noteref" href="notes.xhtml#ch2-fn:1">1</a>`)
+	assert.NotContains(t, chapter, "endnote")
+
+	notes := readZipEntry(t, data, "OEBPS/content/notes.xhtml")
+	assert.Contains(t, notes, `<section epub:type="endnotes" role="doc-endnotes">`)
+	assert.Regexp(t, `(?s)<h2>One</h2>.*id="ch1-fn:1".*Note in one\. <a href="chapter-001.xhtml#fnref:1".*<h2>Two</h2>.*id="ch2-fn:1".*Note in two\. <a href="chapter-002.xhtml#fnref:1"`, notes)
+
+	nav := readZipEntry(t, data, "OEBPS/nav.xhtml")
+	assert.Contains(t, nav, `href="content/notes.xhtml">Notes</a>`)
+}
+
+func TestBuilder_Build_EPUB30Fallbacks(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Legacy & Friends"
+	doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: `<h1 id="one">One</h1>`, FileName: "content/chapter-001.xhtml"})
+	doc.AddResource(model.Resource{ID: "cover-image", FileName: "images/cover.jpg", MediaType: "image/jpeg", Data: []byte{0xFF, 0xD8}, IsCover: true})
+	doc.TOC.Entries = []model.TOCEntry{{Title: "One", Href: "content/chapter-001.xhtml#one", Level: 1}}
+
+	builder := NewBuilder()
+	builder.SetOptions(Options{Version: Version30})
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"></item>`)
+	assert.Contains(t, opf, `<spine toc="ncx">`)
+	assert.Contains(t, opf, `<meta name="cover" content="cover-image"></meta>`)
+
+	ncx := readZipEntry(t, data, "OEBPS/toc.ncx")
+	assert.Contains(t, ncx, `<text>Legacy &amp; Friends</text>`)
+	assert.Contains(t, ncx, `<navPoint id="navpoint-1" playOrder="1">`)
+	assert.Contains(t, ncx, `<content src="content/chapter-001.xhtml#one"></content>`)
+
+	// EPUB 3.3 output has no EPUB 2 fallbacks
+	builder.SetOptions(Options{})
+	data, err = builder.Build(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, readZipEntry(t, data, "OEBPS/content.opf"), "ncx")
+}
+
+func TestParseVersion(t *testing.T) {
+	v, err := ParseVersion("")
+	require.NoError(t, err)
+	assert.Equal(t, Version33, v)
+
+	v, err = ParseVersion("3.0")
+	require.NoError(t, err)
+	assert.Equal(t, Version30, v)
+
+	_, err = ParseVersion("2.0")
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestBuilder_Build_DefaultCSSPlacement(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Styled"
+		doc.AddChapter(model.Chapter{
+			ID:          "ch1",
+			Title:       "One",
+			Content:     "<p>Text</p>",
+			FileName:    "content/chapter-001.xhtml",
+			Stylesheets: []string{"styles/book.css"},
+		})
+		return doc
+	}
+	build := func(opts Options) []byte {
+		builder := NewBuilder()
+		builder.SetOptions(opts)
+		data, err := builder.Build(newDoc())
+		require.NoError(t, err)
+		return data
+	}
+
+	chapter := readZipEntry(t, build(Options{}), "OEBPS/content/chapter-001.xhtml")
+	assert.Less(t, strings.Index(chapter, "default.css"), strings.Index(chapter, "book.css"))
+
+	chapter = readZipEntry(t, build(Options{DefaultCSS: DefaultCSSLast}), "OEBPS/content/chapter-001.xhtml")
+	assert.Greater(t, strings.Index(chapter, "default.css"), strings.Index(chapter, "book.css"))
+
+	data := build(Options{DefaultCSS: DefaultCSSNone})
+	assert.NotContains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"), "default.css")
+	assert.NotContains(t, readZipEntry(t, data, "OEBPS/content.opf"), "default.css")
+	assert.NotContains(t, readZipEntry(t, data, "OEBPS/nav.xhtml"), "default.css")
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	for _, f := range reader.File {
+		assert.NotEqual(t, "OEBPS/styles/default.css", f.Name)
+	}
+}
+
+func TestCodeThemes(t *testing.T) {
+	stylesheet := func(theme string) string {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Themes"
+		doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: "<pre><code>x</code></pre>", FileName: "content/chapter-001.xhtml"})
+		builder := NewBuilder()
+		builder.SetOptions(Options{CodeTheme: theme})
+		data, err := builder.Build(doc)
+		require.NoError(t, err)
+		return readZipEntry(t, data, "OEBPS/styles/default.css")
+	}
+
+	css := stylesheet("")
+	assert.Contains(t, css, "Code highlighting: light theme")
+	assert.Contains(t, css, ".hljs-keyword")
+	assert.Contains(t, css, ".highlight .k, .chroma .k")
+	night := css[strings.Index(css, "@media (prefers-color-scheme: dark)"):]
+	assert.Contains(t, night, darkCodePalette.keyword)
+	assert.Contains(t, night, ".packaged-by")
+
+	css = stylesheet(CodeThemeDark)
+	assert.Contains(t, css, "Code highlighting: dark theme")
+	assert.NotContains(t, css, lightCodePalette.keyword)
+
+	css = stylesheet(CodeThemeNone)
+	assert.NotContains(t, css, ".hljs-")
+	assert.Contains(t, css, "@media (prefers-color-scheme: dark)")
+
+	theme, err := ParseCodeTheme("")
+	require.NoError(t, err)
+	assert.Equal(t, CodeThemeLight, theme)
+	_, err = ParseCodeTheme("solarized")
+	assert.Error(t, err)
+}
+
+func TestCheckWellFormed(t *testing.T) {
+	assert.NoError(t, checkWellFormed("<!DOCTYPE html>\n<html><body><p>a &amp; b<br/></p></body></html>"))
+
+	err := checkWellFormed("<html>\n<body>\n<p>one</div>\n</body></html>")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 3")
+	assert.Error(t, checkWellFormed("<p>a&nbsp;b</p>"))
+}
+
+func TestBuilder_Build_MalformedChapter(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Test Book"
+		doc.AddChapter(model.Chapter{
+			ID:       "ch1",
+			Title:    "Chapter 1",
+			Content:  "<h1>Chapter 1</h1><p>Extract the <title> tag</p>",
+			FileName: "content/chapter-001.xhtml",
+		})
+		doc.TOC.Entries = []model.TOCEntry{{Title: "Chapter 1", Href: "content/chapter-001.xhtml", Level: 1}}
+		return doc
+	}
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+	_, err := builder.Build(newDoc())
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnMalformedXHTML, events[0].Code)
+	assert.Equal(t, "content/chapter-001.xhtml", events[0].File)
+
+	builder.SetOptions(Options{Strict: true})
+	_, err = builder.Build(newDoc())
+	assert.ErrorIs(t, err, ErrMalformedXHTML)
+}
+
+func TestLinkTarget(t *testing.T) {
+	from := "content/chapter-001.xhtml"
+	for href, want := range map[string]string{
+		"#intro":                  "content/chapter-001.xhtml#intro",
+		"chapter-002.xhtml#s1":    "content/chapter-002.xhtml#s1",
+		"../images/a%20b.png":     "images/a b.png",
+		"../styles/extra.css?v=2": "styles/extra.css",
+		"./chapter-003.xhtml":     "content/chapter-003.xhtml",
+	} {
+		got, ok := linkTarget(from, href)
+		assert.True(t, ok, href)
+		assert.Equal(t, want, got, href)
+	}
+	for _, href := range []string{"https://example.com/", "mailto:a@b.c", "data:,x", "#", "//cdn.example.com/x.png"} {
+		_, ok := linkTarget(from, href)
+		assert.False(t, ok, href)
+	}
+}
+
+func TestBuilder_Build_BrokenLinks(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Test Book"
+		doc.AddChapter(model.Chapter{
+			ID:    "ch1",
+			Title: "Chapter 1",
+			Content: `<h1 id="one">Chapter 1</h1><p><a href="#one">top</a> <a href="chapter-002.xhtml#two">next</a> ` +
+				`<a href="chapter-002.xhtml#gone">gone</a> <img src="../images/missing.png" alt="Missing"/></p>`,
+			FileName: "content/chapter-001.xhtml",
+		})
+		doc.AddChapter(model.Chapter{
+			ID:       "ch2",
+			Title:    "Chapter 2",
+			Content:  `<h1 id="two">Chapter 2</h1>`,
+			FileName: "content/chapter-002.xhtml",
+		})
+		doc.TOC.Entries = []model.TOCEntry{
+			{Title: "Chapter 1", Href: "content/chapter-001.xhtml#one", Level: 1},
+			{Title: "Chapter 2", Href: "content/chapter-002.xhtml#nope", Level: 1},
+		}
+		return doc
+	}
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+	_, err := builder.Build(newDoc())
+	require.NoError(t, err)
+
+	var messages []string
+	for _, e := range events {
+		assert.Equal(t, model.WarnBrokenLink, e.Code)
+		messages = append(messages, e.Message)
+	}
+	assert.Equal(t, []string{
+		`Link chapter-002.xhtml#gone in content/chapter-001.xhtml: no element with id "gone"`,
+		`Link ../images/missing.png in content/chapter-001.xhtml: no such file in the book`,
+		`Link content/chapter-002.xhtml#nope in nav.xhtml: no element with id "nope"`,
+	}, messages)
+
+	builder.SetOptions(Options{Strict: true})
+	_, err = builder.Build(newDoc())
+	assert.ErrorIs(t, err, ErrBrokenLink)
+}
+
+func TestBuilder_Build_RepairsIDs(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Test Book"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  `<h1 id="intro">Intro</h1><p id="intro">Again</p><p id="intro-2">Taken</p><a href="#intro">Back</a>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddChapter(model.Chapter{ID: "nav", Title: "Chapter 2", Content: "<p>Two</p>", FileName: "content/chapter-002.xhtml"})
+	doc.AddResource(model.Resource{ID: "img-photo", FileName: "images/photo.png", MediaType: "image/png", Data: []byte("png")})
+	doc.AddResource(model.Resource{ID: "img-photo", FileName: "images/photo.jpg", MediaType: "image/jpeg", Data: []byte("jpeg")})
+	doc.AddResource(model.Resource{ID: "img-copy", FileName: "images/photo.png", MediaType: "image/png", Data: []byte("other")})
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, `<item id="nav-2" href="content/chapter-002.xhtml"`)
+	assert.Contains(t, opf, `<item id="img-photo" href="images/photo.png"`)
+	assert.Contains(t, opf, `<item id="img-photo-2" href="images/photo.jpg"`)
+	assert.Equal(t, 1, strings.Count(opf, `href="images/photo.png"`))
+
+	chapter := readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml")
+	assert.Contains(t, chapter, `<h1 id="intro">Intro</h1><p id="intro-3">Again</p><p id="intro-2">Taken</p>`)
+
+	var repairs int
+	for _, e := range events {
+		if e.Code == model.WarnDuplicateID {
+			repairs++
+		}
+	}
+	assert.Equal(t, 4, repairs)
+}
+
+func TestBuilder_Build_ReadingOrder(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Test Book"
+		doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: "<p>One</p>", FileName: "content/chapter-001.xhtml", Order: 0})
+		doc.AddChapter(model.Chapter{ID: "ch2", Title: "Two", Content: "<p>Two</p>", FileName: "content/chapter-002.xhtml", Order: 2})
+		doc.AddChapter(model.Chapter{ID: "cover", Title: "Cover", Content: "<p>Cover</p>", FileName: "content/cover.xhtml", Order: 3, Type: "cover"})
+		doc.TOC.Entries = []model.TOCEntry{{Title: "One", Href: "content/chapter-001.xhtml", Level: 1}}
+		return doc
+	}
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+	doc := newDoc()
+	_, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	require.Len(t, events, 2)
+	assert.Equal(t, model.WarnReadingOrder, events[0].Code)
+	assert.Equal(t, model.SeverityInfo, events[0].Severity)
+	assert.Contains(t, events[0].Message, "content/chapter-002.xhtml has order 2 at position 1")
+	assert.Equal(t, model.WarnReadingOrder, events[1].Code)
+	assert.Equal(t, "content/chapter-002.xhtml", events[1].File)
+	assert.Equal(t, []int{0, 1, 2}, []int{doc.Chapters[0].Order, doc.Chapters[1].Order, doc.Chapters[2].Order})
+
+	doc = newDoc()
+	doc.Chapters[1].FileName = doc.Chapters[0].FileName
+	_, err = builder.Build(doc)
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+
+	doc = newDoc()
+	doc.Chapters[1].FileName = ""
+	_, err = builder.Build(doc)
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+}
+
+func TestBuilder_CheckLandmarks(t *testing.T) {
+	doc := model.NewDocument()
+	doc.AddChapter(model.Chapter{FileName: "content/glossary.xhtml"})
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Warn: func(e model.Warning) { events = append(events, e) }})
+	builder.landmarks = []landmark{
+		{Type: "glossary", Href: "content/glossary.xhtml"},
+		{Type: "index", Href: "content/index.xhtml#a"},
+	}
+	builder.checkLandmarks(doc)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnReadingOrder, events[0].Code)
+	assert.Equal(t, "Landmark index points to content/index.xhtml#a, which is not in the reading order", events[0].Message)
+}
+
+func TestBuilder_Build_ConcurrentChapters(t *testing.T) {
+	newDoc := func() *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Test Book"
+		for i := 1; i <= 50; i++ {
+			file := fmt.Sprintf("content/chapter-%03d.xhtml", i)
+			content := fmt.Sprintf("<h1>Chapter %d</h1><p>%s</p>", i, strings.Repeat("Text. ", i*20))
+			if i%10 == 0 {
+				content += "<p>A <br> tag</p>"
+			}
+			doc.AddChapter(model.Chapter{ID: fmt.Sprintf("ch%d", i), Title: fmt.Sprintf("Chapter %d", i), Content: content, FileName: file})
+			doc.TOC.Entries = append(doc.TOC.Entries, model.TOCEntry{Title: fmt.Sprintf("Chapter %d", i), Href: file, Level: 1})
+		}
+		return doc
+	}
+
+	var events []model.Warning
+	var progress []int
+	builder := NewBuilder()
+	builder.SetOptions(Options{
+		Workers:  4,
+		Warn:     func(e model.Warning) { events = append(events, e) },
+		Progress: func(current, total int) { progress = append(progress, current) },
+	})
+	data, err := builder.Build(newDoc())
+	require.NoError(t, err)
+
+	// Documents are written, and problems reported, in reading order
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	var chapters []string
+	for _, f := range reader.File {
+		if strings.HasPrefix(f.Name, "OEBPS/content/chapter-") {
+			chapters = append(chapters, f.Name)
+		}
+	}
+	require.Len(t, chapters, 50)
+	for i, name := range chapters {
+		assert.Equal(t, fmt.Sprintf("OEBPS/content/chapter-%03d.xhtml", i+1), name)
+	}
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-030.xhtml"), "<h1>Chapter 30</h1>")
+	require.Len(t, events, 5)
+	for i, e := range events {
+		assert.Equal(t, fmt.Sprintf("content/chapter-%03d.xhtml", (i+1)*10), e.File)
+	}
+	for i, current := range progress {
+		assert.Equal(t, i+1, current)
+	}
+
+	// A failure stops the build without waiting for the other documents
+	builder.SetOptions(Options{Workers: 4, Strict: true})
+	_, err = builder.Build(newDoc())
+	assert.ErrorIs(t, err, ErrMalformedXHTML)
+	assert.Contains(t, err.Error(), "chapter-010.xhtml")
+}
+
+func TestBuilder_Build_ChapterCache(t *testing.T) {
+	newDoc := func(second string) *model.Document {
+		doc := model.NewDocument()
+		doc.Metadata.Title = "Test Book"
+		doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: "<h1>One</h1><p>A <br> tag</p>", FileName: "content/chapter-001.xhtml"})
+		doc.AddChapter(model.Chapter{ID: "ch2", Title: "Two", Content: second, FileName: "content/chapter-002.xhtml"})
+		doc.TOC.Entries = []model.TOCEntry{
+			{Title: "One", Href: "content/chapter-001.xhtml", Level: 1},
+			{Title: "Two", Href: "content/chapter-002.xhtml", Level: 1},
+		}
+		return doc
+	}
+
+	cache := NewChapterCache()
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{Chapters: cache, Warn: func(e model.Warning) { events = append(events, e) }})
+
+	first, err := builder.Build(newDoc("<h1>Two</h1><p>Second.</p>"))
+	require.NoError(t, err)
+	assert.Len(t, cache.entries, 3) // With the colophon
+	require.Len(t, events, 1)
+
+	// Reused documents are written the same and still report problems
+	events = nil
+	second, err := builder.Build(newDoc("<h1>Two</h1><p>Second.</p>"))
+	require.NoError(t, err)
+	for _, name := range []string{"OEBPS/content/chapter-001.xhtml", "OEBPS/content/chapter-002.xhtml"} {
+		assert.Equal(t, readZipEntry(t, first, name), readZipEntry(t, second, name))
+	}
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnMalformedXHTML, events[0].Code)
+
+	// A changed document replaces its old entry
+	third, err := builder.Build(newDoc("<h1>Two</h1><p>Revised.</p>"))
+	require.NoError(t, err)
+	assert.Contains(t, readZipEntry(t, third, "OEBPS/content/chapter-002.xhtml"), "Revised.")
+	assert.Len(t, cache.entries, 3) // With the colophon
+}
@@ -0,0 +1,120 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// calibreMeta is a name/content meta element read by Calibre.
+type calibreMeta struct {
+	Name    string
+	Content string
+}
+
+// calibreColumn is the JSON content of a calibre:user_metadata meta, the
+// field metadata Calibre writes for a custom column.
+type calibreColumn struct {
+	Datatype    string            `json:"datatype"`
+	IsMultiple  map[string]string `json:"is_multiple"`
+	IsMultiple2 map[string]string `json:"is_multiple2"`
+	Kind        string            `json:"kind"`
+	Name        string            `json:"name"`
+	Label       string            `json:"label"`
+	SearchTerms []string          `json:"search_terms"`
+	Display     map[string]string `json:"display"`
+	IsCustom    bool              `json:"is_custom"`
+	IsCategory  bool              `json:"is_category"`
+	IsEditable  bool              `json:"is_editable"`
+	Value       interface{}       `json:"#value#"`
+	Extra       interface{}       `json:"#extra#"`
+}
+
+// calibreMultiple is the is_multiple value of a column holding a list.
+var calibreMultiple = map[string]string{"cache_to_list": "|", "ui_to_list": ",", "list_to_ui": ", "}
+
+// buildCalibreMeta creates the metas Calibre reads for the series, which
+// it does not take from belongs-to-collection, and for custom columns.
+func buildCalibreMeta(meta *model.Metadata) []calibreMeta {
+	var items []calibreMeta
+	if series, ok := meta.Series(); ok && series.Name != "" {
+		items = append(items, calibreMeta{Name: "calibre:series", Content: series.Name})
+		if series.Position != "" {
+			items = append(items, calibreMeta{Name: "calibre:series_index", Content: series.Position})
+		}
+	}
+
+	for _, c := range meta.CalibreColumns {
+		column := calibreColumn{
+			Datatype:    c.Datatype,
+			IsMultiple:  map[string]string{},
+			IsMultiple2: map[string]string{},
+			Kind:        "field",
+			Name:        c.Label,
+			Label:       c.Label,
+			SearchTerms: []string{"#" + c.Label},
+			Display:     map[string]string{},
+			IsCustom:    true,
+			IsCategory:  c.Datatype == model.CalibreText || c.Datatype == model.CalibreBool,
+			IsEditable:  true,
+			Value:       calibreValue(c),
+		}
+		if column.Value == nil {
+			continue
+		}
+		if c.Datatype == model.CalibreText && len(c.Values) > 1 {
+			column.IsMultiple = calibreMultiple
+			column.IsMultiple2 = calibreMultiple
+		}
+		content, err := json.Marshal(column)
+		if err != nil {
+			continue
+		}
+		items = append(items, calibreMeta{Name: "calibre:user_metadata:#" + c.Label, Content: string(content)})
+	}
+	return items
+}
+
+// calibreValue converts a column value to its JSON form for the column's
+// datatype, or nil if the value does not fit the datatype.
+func calibreValue(c model.CalibreColumn) interface{} {
+	if len(c.Values) == 0 {
+		return nil
+	}
+	value := c.Values[0]
+	switch c.Datatype {
+	case model.CalibreBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case model.CalibreInt:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case model.CalibreFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case model.CalibreDatetime:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t.UTC().Format("2006-01-02T15:04:05+00:00")
+			}
+		}
+	default:
+		if len(c.Values) > 1 {
+			return c.Values
+		}
+		return value
+	}
+	return nil
+}
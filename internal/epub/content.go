@@ -10,6 +10,9 @@ package epub
 import (
 	"bytes"
 	"html"
+	"path"
+	"path/filepath"
+	"strings"
 	"text/template"
 
 	"github.com/dauquangthanh/epub-converter/internal/model"
@@ -18,40 +21,70 @@ import (
 // contentTemplate is the template for XHTML content documents
 const contentTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE html>
-<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops">
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops"{{if .SSML}} xmlns:ssml="http://www.w3.org/2001/10/synthesis"{{end}}{{if .Language}} xml:lang="{{.Language}}" lang="{{.Language}}"{{end}}{{if .Direction}} dir="{{.Direction}}"{{end}}>
 <head>
   <meta charset="UTF-8"/>
   <title>{{.Title}}</title>
-  <link rel="stylesheet" type="text/css" href="styles/default.css"/>
+{{- if .FixedLayout}}
+  <meta name="viewport" content="width={{.ViewportWidth}}, height={{.ViewportHeight}}"/>
+{{- end}}
+{{- range .Stylesheets}}
+  <link rel="stylesheet" type="text/css" href="{{.}}"/>
+{{- end}}
+{{- range .Lexicons}}
+  <link rel="pronunciation" type="application/pls+xml" href="{{.}}"{{if $.BookLanguage}} hreflang="{{$.BookLanguage}}"{{end}}/>
+{{- end}}
 </head>
-<body epub:type="bodymatter">
+<body epub:type="{{.BodyType}}"{{if .FixedLayout}} style="width: {{.ViewportWidth}}px; height: {{.ViewportHeight}}px; margin: 0; overflow: hidden;"{{end}}>
+{{- if .Section}}
+<section epub:type="{{.Section}}">
 {{.Content}}
+</section>
+{{- else}}
+{{.Content}}
+{{- end}}
 </body>
 </html>`
 
 // contentData holds data for the content template
 type contentData struct {
-	Title   string
-	Content string
+	Title          string
+	Content        string
+	FixedLayout    bool
+	ViewportWidth  int
+	ViewportHeight int
+	Language       string
+	Direction      string
+	Stylesheets    []string
+	Lexicons       []string // Pronunciation lexicon hrefs
+	BookLanguage   string   // Language of the lexicons
+	SSML           bool     // Content has ssml: attributes
+	BodyType       string   // Division of the book holding the chapter
+	Section        string   // Structural type of the chapter, empty for none
 }
 
 // generateContentDocument generates an XHTML content document.
-func generateContentDocument(chapter *model.Chapter, bookTitle string) (string, error) {
-	tmpl, err := template.New("content").Parse(contentTemplate)
-	if err != nil {
-		return "", err
-	}
-
+func generateContentDocument(tmpl *template.Template, chapter *model.Chapter, meta *model.Metadata, lexicons []string, opts Options) (string, error) {
 	title := chapter.Title
 	if title == "" {
-		title = bookTitle
+		title = meta.Title
 	}
 
 	// Escape title for XML safety, but content is already HTML
 	data := contentData{
-		Title:   html.EscapeString(title),
-		Content: chapter.Content,
+		Title:          html.EscapeString(title),
+		Content:        chapter.Content,
+		FixedLayout:    meta.Rendition.FixedLayout(),
+		ViewportWidth:  meta.Rendition.ViewportWidth,
+		ViewportHeight: meta.Rendition.ViewportHeight,
+		Language:       html.EscapeString(chapterLanguage(chapter, meta)),
+		Direction:      html.EscapeString(meta.Direction),
+		Stylesheets:    chapterStylesheets(chapter, opts),
+		Lexicons:       lexicons,
+		BookLanguage:   html.EscapeString(meta.Language),
+		SSML:           strings.Contains(chapter.Content, "ssml:"),
 	}
+	data.BodyType, data.Section = chapterSemantics(chapter, meta)
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
@@ -60,3 +93,64 @@ func generateContentDocument(chapter *model.Chapter, bookTitle string) (string,
 
 	return buf.String(), nil
 }
+
+// chapterSemantics returns the epub:type of a chapter's body, its division,
+// and of the section wrapping its content, such as "chapter" or "appendix".
+// Body chapters that declare no type are chapters, except the pages of a
+// fixed-layout book; generated pages carry their own sections. Types that
+// name no division, as some imported EPUBs use, are kept on the body.
+func chapterSemantics(chapter *model.Chapter, meta *model.Metadata) (body, section string) {
+	body = chapter.Matter()
+	if body == "" {
+		return html.EscapeString(chapter.Type), ""
+	}
+	section = chapter.Semantic()
+	if section == "" && body == model.BodyMatter && !meta.Rendition.FixedLayout() {
+		section = "chapter"
+	}
+	return body, section
+}
+
+// chapterLanguage returns the language of a chapter's content document:
+// its own, or the book language.
+func chapterLanguage(chapter *model.Chapter, meta *model.Metadata) string {
+	if chapter.Language != "" {
+		return chapter.Language
+	}
+	return meta.Language
+}
+
+// defaultStylesheet is the path of the built-in stylesheet within OEBPS.
+const defaultStylesheet = "styles/default.css"
+
+// chapterStylesheets returns the stylesheet hrefs for a chapter, relative to
+// the chapter's location: the default stylesheet placed before or after any
+// chapter-specific stylesheets, or left out.
+func chapterStylesheets(chapter *model.Chapter, opts Options) []string {
+	var hrefs []string
+	for _, css := range chapter.Stylesheets {
+		if css == defaultStylesheet {
+			continue
+		}
+		hrefs = append(hrefs, html.EscapeString(relativeHref(chapter.FileName, css)))
+	}
+
+	switch {
+	case !opts.defaultCSS():
+		return hrefs
+	case opts.DefaultCSS == DefaultCSSLast:
+		return append(hrefs, relativeHref(chapter.FileName, defaultStylesheet))
+	default:
+		return append([]string{relativeHref(chapter.FileName, defaultStylesheet)}, hrefs...)
+	}
+}
+
+// relativeHref returns the href of target relative to the document at from.
+// Both paths are relative to the OEBPS directory.
+func relativeHref(from, target string) string {
+	rel, err := filepath.Rel(path.Dir(from), target)
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
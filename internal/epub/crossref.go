@@ -0,0 +1,120 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns for numbered figures and tables and their references.
+var (
+	labeledElementRe = regexp.MustCompile(`<(figure|table)\b[^>]*\bid="((?:fig|tbl):[^"]+)"[^>]*>`)
+	crossRefRe       = regexp.MustCompile(`<a data-ref="([^"]*)"></a>`)
+)
+
+// crossRefTarget is a numbered figure or table.
+type crossRefTarget struct {
+	Label string // Display label (e.g., "Figure 3.2")
+	Href  string // EPUB path with fragment identifier
+}
+
+// numberCrossRefs numbers labeled figures and tables and resolves
+// @fig:/@tbl: references to them. Numbers are assigned in reading order when
+// the book is built, so they stay correct as content moves between files.
+// Books with several body chapters use chapter-relative numbers ("Figure
+// 3.2"); single-chapter books use plain numbers ("Table 1").
+func (b *Builder) numberCrossRefs(doc *model.Document) {
+	strs := b.localizedStrings()
+	targets := make(map[string]crossRefTarget)
+
+	bodyChapters := 0
+	for _, chapter := range doc.Chapters {
+		if isBodyMatter(chapter) {
+			bodyChapters++
+		}
+	}
+
+	chapterNum := 0
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if !isBodyMatter(*chapter) {
+			continue
+		}
+		chapterNum++
+
+		prefix := ""
+		if bodyChapters > 1 {
+			prefix = strconv.Itoa(chapterNum) + "."
+		}
+		chapter.Content = numberLabeledElements(chapter.Content, prefix, strs, func(id, label string) {
+			targets[html.UnescapeString(id)] = crossRefTarget{Label: label, Href: chapter.FileName + "#" + id}
+		})
+	}
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = crossRefRe.ReplaceAllStringFunc(chapter.Content, func(placeholder string) string {
+			id := crossRefRe.FindStringSubmatch(placeholder)[1]
+			target, ok := targets[html.UnescapeString(id)]
+			if !ok {
+				// Unknown labels are shown as-is so they are easy to spot
+				return "??" + id
+			}
+			href := relativeHref(chapter.FileName, target.Href)
+			return `<a href="` + html.EscapeString(href) + `">` + html.EscapeString(target.Label) + "</a>"
+		})
+	}
+}
+
+// captionStartRe matches the opening tag of a figure or table caption.
+var captionStartRe = regexp.MustCompile(`<(?:figcaption|caption)\b[^>]*>`)
+
+// numberLabeledElements numbers the labeled figures and tables in content,
+// reporting each label through add, and prefixes their captions with the
+// label. Elements without a caption keep their number for references only.
+func numberLabeledElements(content, prefix string, strs generatedStrings, add func(id, label string)) string {
+	locs := labeledElementRe.FindAllStringSubmatchIndex(content, -1)
+	if len(locs) == 0 {
+		return content
+	}
+
+	counts := make(map[string]int)
+	var buf strings.Builder
+	last := 0
+	for k, loc := range locs {
+		element, id := content[loc[2]:loc[3]], content[loc[4]:loc[5]]
+
+		counts[element]++
+		word := strs.Figure
+		if element == "table" {
+			word = strs.Table
+		}
+		label := word + " " + prefix + strconv.Itoa(counts[element])
+		add(id, label)
+
+		// Only use a caption before the next labeled element
+		limit := len(content)
+		if k+1 < len(locs) {
+			limit = locs[k+1][0]
+		}
+		caption := captionStartRe.FindStringIndex(content[loc[1]:limit])
+		if caption == nil {
+			continue
+		}
+		buf.WriteString(content[last : loc[1]+caption[1]])
+		buf.WriteString(`<span class="label">` + html.EscapeString(label) + ":</span> ")
+		last = loc[1] + caption[1]
+	}
+	buf.WriteString(content[last:])
+	return buf.String()
+}
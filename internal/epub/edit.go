@@ -0,0 +1,341 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// MetadataUpdate lists metadata changes for an existing EPUB. Empty fields
+// are left unchanged.
+type MetadataUpdate struct {
+	Title       string          // Replaces the main dc:title
+	Authors     []string        // Replace all dc:creator entries
+	Language    string          // Replaces the main dc:language
+	Publisher   string          // Sets dc:publisher
+	Description string          // Sets dc:description
+	Cover       *model.Resource // Replacement cover image; FileName sets the extension
+}
+
+// Patterns used to edit an existing package document.
+var (
+	rootfileRe      = regexp.MustCompile(`<rootfile\b[^>]*\bfull-path\s*=\s*["']([^"']+)["']`)
+	metadataBlockRe = regexp.MustCompile(`(?s)(<(?:opf:)?metadata\b[^>]*>)(.*?)(\s*</(?:opf:)?metadata>)`)
+	manifestEndRe   = regexp.MustCompile(`\s*</(?:opf:)?manifest>`)
+	manifestItemRe  = regexp.MustCompile(`<(?:opf:)?item\b[^>]*>`)
+	modifiedMetaRe  = regexp.MustCompile(`(?s)(<meta\b[^>]*\bproperty\s*=\s*["']dcterms:modified["'][^>]*>).*?(</meta>)`)
+	coverMetaRe     = regexp.MustCompile(`<meta\b[^>]*\bname\s*=\s*["']cover["'][^>]*>`)
+	contentAttrRe   = regexp.MustCompile(`\bcontent\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	hrefAttrRe      = regexp.MustCompile(`\bhref\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	mediaTypeAttrRe = regexp.MustCompile(`\bmedia-type\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	propertiesRe    = regexp.MustCompile(`\bproperties\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// metadataIndent is the indentation of metadata elements written by the builder.
+const metadataIndent = "\n    "
+
+// UpdateMetadata rewrites the package document of the EPUB at path, and
+// replaces its cover image when update.Cover is set, without touching the
+// content documents. The new archive is written next to path and renamed
+// over it only when complete, so a failure leaves the original intact.
+func UpdateMetadata(epubPath string, update MetadataUpdate) error {
+	r, err := zip.OpenReader(epubPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	opfPath, err := findPackageDocument(&r.Reader)
+	if err != nil {
+		return err
+	}
+	opf, err := readZipFile(&r.Reader, opfPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", opfPath, err)
+	}
+
+	opf, err = updatePackageMetadata(opf, update)
+	if err != nil {
+		return err
+	}
+
+	// Locate or add the cover image manifest item
+	var coverPath, oldCoverPath string
+	if update.Cover != nil {
+		var oldHref, newHref string
+		opf, oldHref, newHref = updateCoverItem(opf, update.Cover)
+		base := path.Dir(opfPath)
+		coverPath = path.Join(base, newHref)
+		if oldHref != "" {
+			oldCoverPath = path.Join(base, oldHref)
+		}
+	}
+
+	info, err := os.Stat(epubPath)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(epubPath), ".toepub-*.epub")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeUpdatedArchive(tmp, &r.Reader, opfPath, opf, update.Cover, oldCoverPath, coverPath); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), epubPath)
+}
+
+// findPackageDocument returns the archive path of the package document
+// named in META-INF/container.xml.
+func findPackageDocument(r *zip.Reader) (string, error) {
+	container, err := readZipFile(r, "META-INF/container.xml")
+	if err != nil {
+		return "", fmt.Errorf("%w: reading container.xml: %v", ErrInvalidPackage, err)
+	}
+	m := rootfileRe.FindStringSubmatch(container)
+	if m == nil {
+		return "", fmt.Errorf("%w: container.xml has no rootfile", ErrInvalidPackage)
+	}
+	return html.UnescapeString(m[1]), nil
+}
+
+// readZipFile returns the contents of the named archive entry.
+func readZipFile(r *zip.Reader, name string) (string, error) {
+	f, err := r.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	return string(data), err
+}
+
+// updatePackageMetadata applies update to the metadata of a package
+// document and refreshes its dcterms:modified timestamp.
+func updatePackageMetadata(opf string, update MetadataUpdate) (string, error) {
+	loc := metadataBlockRe.FindStringSubmatchIndex(opf)
+	if loc == nil {
+		return "", fmt.Errorf("%w: package document has no metadata", ErrInvalidPackage)
+	}
+	meta := opf[loc[4]:loc[5]]
+
+	if update.Title != "" {
+		meta = setDCElement(meta, "title", update.Title)
+	}
+	if update.Language != "" {
+		meta = setDCElement(meta, "language", update.Language)
+	}
+	if len(update.Authors) > 0 {
+		meta = replaceCreators(meta, update.Authors)
+	}
+	if update.Publisher != "" {
+		meta = setDCElement(meta, "publisher", update.Publisher)
+	}
+	if update.Description != "" {
+		meta = setDCElement(meta, "description", update.Description)
+	}
+
+	modified := modifiedTimestamp()
+	if modifiedMetaRe.MatchString(meta) {
+		meta = modifiedMetaRe.ReplaceAllString(meta, "${1}"+modified+"${2}")
+	} else {
+		meta += metadataIndent + `<meta property="dcterms:modified">` + modified + "</meta>"
+	}
+
+	return opf[:loc[4]] + meta + opf[loc[5]:], nil
+}
+
+// setDCElement replaces the text of the first dc:name element, or appends
+// the element when there is none.
+func setDCElement(meta, name, value string) string {
+	re := regexp.MustCompile(`(?s)(<dc:` + name + `\b[^>]*>).*?(</dc:` + name + `>)`)
+	escaped := html.EscapeString(value)
+	if loc := re.FindStringSubmatchIndex(meta); loc != nil {
+		return meta[:loc[3]] + escaped + meta[loc[4]:]
+	}
+	return meta + metadataIndent + "<dc:" + name + ">" + escaped + "</dc:" + name + ">"
+}
+
+// creatorRe matches dc:creator elements, capturing their attributes.
+var creatorRe = regexp.MustCompile(`(?s)\s*<dc:creator\b([^>]*)>.*?</dc:creator>`)
+
+// replaceCreators removes all dc:creator elements and the meta elements
+// refining them, and inserts the given authors in their place.
+func replaceCreators(meta string, authors []string) string {
+	var added strings.Builder
+	for i, author := range authors {
+		id := fmt.Sprintf("creator-%d", i+1)
+		added.WriteString(metadataIndent + `<dc:creator id="` + id + `">` + html.EscapeString(author) + "</dc:creator>")
+		added.WriteString(metadataIndent + `<meta refines="#` + id + `" property="role" scheme="marc:relators">` + model.RoleAuthor + "</meta>")
+	}
+
+	locs := creatorRe.FindAllStringSubmatchIndex(meta, -1)
+	if len(locs) == 0 {
+		return meta + added.String()
+	}
+
+	// Remove refinements of the old creators before the creators themselves
+	var ids []string
+	for _, loc := range locs {
+		if id := attrValue(idAttrRe, meta[loc[2]:loc[3]]); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		refinesRe := regexp.MustCompile(`(?s)\s*<meta\b[^>]*\brefines\s*=\s*["']#` + regexp.QuoteMeta(id) + `["'][^>]*?(?:/>|>.*?</meta>)`)
+		meta = refinesRe.ReplaceAllString(meta, "")
+	}
+
+	locs = creatorRe.FindAllStringIndex(meta, -1)
+	var buf strings.Builder
+	last := 0
+	for i, loc := range locs {
+		buf.WriteString(meta[last:loc[0]])
+		if i == 0 {
+			buf.WriteString(added.String())
+		}
+		last = loc[1]
+	}
+	buf.WriteString(meta[last:])
+	return buf.String()
+}
+
+// updateCoverItem points the cover image manifest item at the new cover,
+// adding the item when the package has none. It returns the updated package
+// document with the old (empty if new) and new cover hrefs.
+func updateCoverItem(opf string, cover *model.Resource) (string, string, string) {
+	coverID := ""
+	if m := coverMetaRe.FindString(opf); m != "" {
+		coverID = attrValue(contentAttrRe, m)
+	}
+
+	for _, loc := range manifestItemRe.FindAllStringIndex(opf, -1) {
+		item := opf[loc[0]:loc[1]]
+		props := " " + attrValue(propertiesRe, item) + " "
+		if !strings.Contains(props, " cover-image ") && (coverID == "" || attrValue(idAttrRe, item) != coverID) {
+			continue
+		}
+
+		oldHref := html.UnescapeString(attrValue(hrefAttrRe, item))
+		newHref := oldHref
+		if attrValue(mediaTypeAttrRe, item) != cover.MediaType {
+			newHref = strings.TrimSuffix(oldHref, path.Ext(oldHref)) + path.Ext(cover.FileName)
+		}
+
+		item = replaceAttr(item, hrefAttrRe, "href", html.EscapeString(newHref))
+		item = replaceAttr(item, mediaTypeAttrRe, "media-type", cover.MediaType)
+		if !strings.Contains(props, " cover-image ") {
+			item = replaceAttr(item, propertiesRe, "properties", strings.TrimSpace(props+"cover-image"))
+		}
+		return opf[:loc[0]] + item + opf[loc[1]:], oldHref, newHref
+	}
+
+	// No cover yet: add one next to the other images
+	newHref := "images/cover" + path.Ext(cover.FileName)
+	item := `<item id="cover-image" href="` + newHref + `" media-type="` + cover.MediaType + `" properties="cover-image"/>`
+	if loc := manifestEndRe.FindStringIndex(opf); loc != nil {
+		opf = opf[:loc[0]] + "\n    " + item + opf[loc[0]:]
+	}
+	return opf, "", newHref
+}
+
+// replaceAttr sets an attribute matched by re in a start tag, adding it
+// before the end of the tag when missing.
+func replaceAttr(tag string, re *regexp.Regexp, name, value string) string {
+	attr := name + `="` + value + `"`
+	if re.MatchString(tag) {
+		return re.ReplaceAllLiteralString(tag, attr)
+	}
+	end := strings.TrimSuffix(strings.TrimSuffix(tag, ">"), "/")
+	return strings.TrimRight(end, " ") + " " + attr + tag[len(end):]
+}
+
+// writeUpdatedArchive copies the entries of r to w, replacing the package
+// document and the cover image. Unchanged entries are copied without
+// recompression. When the cover moves to a new file name, references to
+// the old name in content documents and stylesheets are updated.
+func writeUpdatedArchive(w io.Writer, r *zip.Reader, opfPath, opf string, cover *model.Resource, oldCoverPath, coverPath string) error {
+	zw := zip.NewWriter(w)
+
+	renamed := oldCoverPath != "" && oldCoverPath != coverPath
+	var coverRefRe *regexp.Regexp
+	if renamed {
+		coverRefRe = regexp.MustCompile(`(["'/])` + regexp.QuoteMeta(path.Base(oldCoverPath)) + `(["'?#)])`)
+	}
+
+	for _, f := range r.File {
+		switch {
+		case f.Name == opfPath:
+			if err := writeZipEntry(zw, f.Name, []byte(opf)); err != nil {
+				return err
+			}
+		case cover != nil && (f.Name == oldCoverPath || f.Name == coverPath):
+			// Written below
+		case renamed && isRewritableDocument(f.Name):
+			content, err := readZipFile(r, f.Name)
+			if err != nil {
+				return err
+			}
+			content = coverRefRe.ReplaceAllString(content, "${1}"+path.Base(coverPath)+"${2}")
+			if err := writeZipEntry(zw, f.Name, []byte(content)); err != nil {
+				return err
+			}
+		default:
+			if err := zw.Copy(f); err != nil {
+				return fmt.Errorf("copying %s: %w", f.Name, err)
+			}
+		}
+	}
+
+	if cover != nil {
+		if err := writeZipEntry(zw, coverPath, cover.Data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// isRewritableDocument reports whether an archive entry may reference the
+// cover image by file name.
+func isRewritableDocument(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".xhtml", ".html", ".htm", ".css", ".svg":
+		return true
+	}
+	return false
+}
+
+// writeZipEntry writes a compressed archive entry.
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,87 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestUpdateMetadata(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Old Title"
+	doc.Metadata.Authors = []string{"Old Author", "Second Author"}
+	doc.Metadata.Contributors = []model.Contributor{{Name: "Ed", Role: "edt"}}
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "One",
+		Content:  `<p><img src="../images/cover.jpg" alt="Cover"/></p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.AddResource(model.Resource{ID: "cover-image", FileName: "images/cover.jpg", MediaType: "image/jpeg", Data: []byte("jpeg"), IsCover: true})
+
+	data, err := NewBuilder().Build(doc)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	err = UpdateMetadata(path, MetadataUpdate{
+		Title:     "New & Improved",
+		Authors:   []string{"Jane Doe"},
+		Publisher: "Acme",
+		Cover:     &model.Resource{FileName: "images/cover.png", MediaType: "image/png", Data: []byte("png")},
+	})
+	require.NoError(t, err)
+
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+
+	opf := readZipEntry(t, data, "OEBPS/content.opf")
+	assert.Contains(t, opf, "<dc:title>New &amp; Improved</dc:title>")
+	assert.NotContains(t, opf, "Old Author")
+	assert.NotContains(t, opf, "Second Author")
+	assert.NotContains(t, opf, `refines="#creator-2"`)
+	assert.Contains(t, opf, `<dc:creator id="creator-1">Jane Doe</dc:creator>`)
+	assert.Contains(t, opf, `<meta refines="#creator-1" property="role" scheme="marc:relators">aut</meta>`)
+	assert.Contains(t, opf, `<meta refines="#contributor-1" property="role" scheme="marc:relators">edt</meta>`)
+	assert.Contains(t, opf, "<dc:publisher>Acme</dc:publisher>")
+	assert.Contains(t, opf, `<item id="cover-image" href="images/cover.png" media-type="image/png" properties="cover-image">`)
+
+	// The cover is replaced and references to it follow the new name
+	assert.Equal(t, "png", readZipEntry(t, data, "OEBPS/images/cover.png"))
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"), `src="../images/cover.png"`)
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, "mimetype", reader.File[0].Name)
+	assert.Equal(t, zip.Store, reader.File[0].Method)
+	for _, f := range reader.File {
+		assert.NotEqual(t, "OEBPS/images/cover.jpg", f.Name)
+	}
+}
+
+func TestUpdateMetadata_InvalidPackage(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("mimetype")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("application/epub+zip"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	path := filepath.Join(t.TempDir(), "broken.epub")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	err = UpdateMetadata(path, MetadataUpdate{Title: "X"})
+	assert.ErrorIs(t, err, ErrInvalidPackage)
+
+	unchanged, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), unchanged)
+}
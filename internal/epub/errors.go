@@ -11,7 +11,11 @@ import "errors"
 
 // EPUB generation errors
 var (
-	ErrMissingTitle    = errors.New("missing required title metadata")
-	ErrNoChapters      = errors.New("document has no chapters")
-	ErrInvalidDocument = errors.New("invalid document")
+	ErrMissingTitle       = errors.New("missing required title metadata")
+	ErrNoChapters         = errors.New("document has no chapters")
+	ErrInvalidDocument    = errors.New("invalid document")
+	ErrInvalidPackage     = errors.New("invalid EPUB package")
+	ErrUnsupportedVersion = errors.New("unsupported EPUB version")
+	ErrMalformedXHTML     = errors.New("malformed XHTML")
+	ErrBrokenLink         = errors.New("broken internal links")
 )
@@ -0,0 +1,183 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// glossaryFileName is the EPUB path of the generated glossary chapter.
+const glossaryFileName = "content/glossary.xhtml"
+
+// markupTagRe matches tags and comments, capturing the closing slash and
+// element name of tags.
+var markupTagRe = regexp.MustCompile(`<(/?)([a-zA-Z][\w:-]*)[^>]*>|<!--[\s\S]*?-->|<[^>]*>`)
+
+// glossaryNoLinkElements are elements whose text is never linked to the
+// glossary.
+var glossaryNoLinkElements = map[string]bool{
+	"a": true, "code": true, "pre": true, "kbd": true, "samp": true, "dfn": true,
+	"script": true, "style": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// textEscaper escapes the characters that are special in XHTML text.
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// addGlossary appends an alphabetized glossary chapter for the document's
+// glossary entries and links the first occurrence of each term in every
+// body chapter to its definition.
+func (b *Builder) addGlossary(doc *model.Document) {
+	if len(doc.Glossary) == 0 {
+		return
+	}
+
+	entries := make([]model.GlossaryEntry, len(doc.Glossary))
+	copy(entries, doc.Glossary)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Term) < strings.ToLower(entries[j].Term)
+	})
+
+	// Match longer terms first so "web server" wins over "server"
+	hrefs := make(map[string]string, len(entries))
+	alternatives := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		hrefs[strings.ToLower(entry.Term)] = glossaryFileName + "#" + glossaryID(i)
+		alternatives = append(alternatives, regexp.QuoteMeta(textEscaper.Replace(entry.Term)))
+	}
+	sort.SliceStable(alternatives, func(i, j int) bool { return len(alternatives[i]) > len(alternatives[j]) })
+	termRe := regexp.MustCompile(`(?i)` + strings.Join(alternatives, "|"))
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if isBodyMatter(*chapter) {
+			chapter.Content = linkGlossaryTerms(chapter.Content, chapter.FileName, termRe, hrefs)
+		}
+	}
+
+	title := b.localizedStrings().Glossary
+	doc.AddChapter(model.Chapter{
+		ID:       "glossary",
+		Title:    title,
+		Level:    1,
+		Content:  renderGlossary(entries, title),
+		FileName: glossaryFileName,
+		Order:    len(doc.Chapters),
+		Type:     "backmatter",
+	})
+	doc.TOC.AddEntry(model.TOCEntry{Title: title, Href: glossaryFileName, Level: 1})
+	b.landmarks = append(b.landmarks, landmark{Type: "glossary", Href: glossaryFileName, Title: title})
+}
+
+// glossaryID returns the element id of the glossary entry at index i.
+func glossaryID(i int) string {
+	return "gloss-" + strconv.Itoa(i+1)
+}
+
+// linkGlossaryTerms links the first whole-word occurrence of each glossary
+// term in content, skipping headings, links, and code.
+func linkGlossaryTerms(content, fileName string, termRe *regexp.Regexp, hrefs map[string]string) string {
+	linked := make(map[string]bool)
+	return mapText(content, glossaryNoLinkElements, func(text string) string {
+		return linkTermsInText(text, fileName, termRe, hrefs, linked)
+	})
+}
+
+// mapText replaces each run of text in content with fn of it, leaving
+// markup and the text inside skipped elements as they are.
+func mapText(content string, skipped map[string]bool, fn func(string) string) string {
+	skip := 0
+
+	var buf strings.Builder
+	last := 0
+	for _, loc := range markupTagRe.FindAllStringSubmatchIndex(content, -1) {
+		text := content[last:loc[0]]
+		if skip == 0 {
+			text = fn(text)
+		}
+		buf.WriteString(text)
+		buf.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+
+		if loc[4] < 0 || strings.HasSuffix(content[loc[0]:loc[1]], "/>") {
+			continue
+		}
+		if !skipped[strings.ToLower(content[loc[4]:loc[5]])] {
+			continue
+		}
+		if loc[3] > loc[2] {
+			if skip > 0 {
+				skip--
+			}
+		} else {
+			skip++
+		}
+	}
+
+	text := content[last:]
+	if skip == 0 {
+		text = fn(text)
+	}
+	buf.WriteString(text)
+	return buf.String()
+}
+
+// linkTermsInText links terms in a run of escaped text that have not been
+// linked yet, recording them in linked.
+func linkTermsInText(text, fileName string, termRe *regexp.Regexp, hrefs map[string]string, linked map[string]bool) string {
+	var buf strings.Builder
+	last := 0
+	for _, loc := range termRe.FindAllStringIndex(text, -1) {
+		match := text[loc[0]:loc[1]]
+		key := strings.ToLower(html.UnescapeString(match))
+		if linked[key] || !isWordBoundary(text, loc[0], loc[1]) {
+			continue
+		}
+		linked[key] = true
+
+		href := relativeHref(fileName, hrefs[key])
+		buf.WriteString(text[last:loc[0]])
+		buf.WriteString(`<a epub:type="glossref" role="doc-glossref" href="` + html.EscapeString(href) + `">` + match + "</a>")
+		last = loc[1]
+	}
+	buf.WriteString(text[last:])
+	return buf.String()
+}
+
+// isWordBoundary reports whether text[start:end] is not part of a longer
+// word or a character reference such as "&amp;".
+func isWordBoundary(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	return !isWordRune(before) && before != '&' && before != '#' && !isWordRune(after)
+}
+
+// isWordRune reports whether r can be part of a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// renderGlossary renders glossary entries as a definition list.
+func renderGlossary(entries []model.GlossaryEntry, title string) string {
+	var buf strings.Builder
+	buf.WriteString("<section epub:type=\"glossary\" role=\"doc-glossary\">\n  <h1>" + html.EscapeString(title) + "</h1>\n")
+	buf.WriteString("  <dl>\n")
+	for i, entry := range entries {
+		buf.WriteString(`    <dt id="` + glossaryID(i) + `" epub:type="glossterm"><dfn>` + html.EscapeString(entry.Term) + "</dfn></dt>\n")
+		buf.WriteString(`    <dd epub:type="glossdef">` + entry.Definition + "</dd>\n")
+	}
+	buf.WriteString("  </dl>\n</section>")
+	return buf.String()
+}
@@ -0,0 +1,69 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import "strings"
+
+// generatedStrings holds UI strings the builder writes into the book.
+type generatedStrings struct {
+	TableOfContents string
+	Landmarks       string
+	StartOfContent  string
+	Colophon        string
+	Index           string
+	Bibliography    string
+	Glossary        string
+	Figure          string
+	Table           string
+	Notes           string
+}
+
+// defaultLanguage is used when the book language has no translation.
+const defaultLanguage = "en"
+
+// translations maps primary language subtags to generated strings.
+var translations = map[string]generatedStrings{
+	"en": {"Table of Contents", "Landmarks", "Start of Content", "About This EPUB", "Index", "Bibliography", "Glossary", "Figure", "Table", "Notes"},
+	"fr": {"Table des matières", "Repères", "Début du contenu", "À propos de cet EPUB", "Index", "Bibliographie", "Glossaire", "Figure", "Tableau", "Notes"},
+	"de": {"Inhaltsverzeichnis", "Orientierungspunkte", "Beginn des Inhalts", "Über dieses EPUB", "Register", "Literaturverzeichnis", "Glossar", "Abbildung", "Tabelle", "Anmerkungen"},
+	"es": {"Índice", "Puntos de referencia", "Inicio del contenido", "Acerca de este EPUB", "Índice alfabético", "Bibliografía", "Glosario", "Figura", "Tabla", "Notas"},
+	"it": {"Indice", "Punti di riferimento", "Inizio del contenuto", "Informazioni su questo EPUB", "Indice analitico", "Bibliografia", "Glossario", "Figura", "Tabella", "Note"},
+	"pt": {"Sumário", "Marcos", "Início do conteúdo", "Sobre este EPUB", "Índice remissivo", "Bibliografia", "Glossário", "Figura", "Tabela", "Notas"},
+	"nl": {"Inhoudsopgave", "Oriëntatiepunten", "Begin van de inhoud", "Over dit EPUB-bestand", "Register", "Bibliografie", "Woordenlijst", "Figuur", "Tabel", "Noten"},
+	"ru": {"Содержание", "Ориентиры", "Начало содержания", "Об этой книге EPUB", "Предметный указатель", "Библиография", "Глоссарий", "Рисунок", "Таблица", "Примечания"},
+	"pl": {"Spis treści", "Punkty orientacyjne", "Początek treści", "O tym EPUB", "Indeks", "Bibliografia", "Słowniczek", "Rysunek", "Tabela", "Przypisy"},
+	"vi": {"Mục lục", "Điểm mốc", "Bắt đầu nội dung", "Về EPUB này", "Chỉ mục", "Tài liệu tham khảo", "Thuật ngữ", "Hình", "Bảng", "Ghi chú"},
+	"ja": {"目次", "ランドマーク", "本文の開始", "このEPUBについて", "索引", "参考文献", "用語集", "図", "表", "注"},
+	"zh": {"目录", "地标", "正文开始", "关于此EPUB", "索引", "参考文献", "术语表", "图", "表", "注释"},
+	"ko": {"목차", "랜드마크", "본문 시작", "이 EPUB 정보", "색인", "참고문헌", "용어집", "그림", "표", "주석"},
+	"ar": {"جدول المحتويات", "معالم", "بداية المحتوى", "حول هذا الكتاب", "الفهرس", "المراجع", "مسرد المصطلحات", "شكل", "جدول", "ملاحظات"},
+	"he": {"תוכן העניינים", "ציוני דרך", "תחילת התוכן", "אודות ספר זה", "מפתח", "ביבליוגרפיה", "מילון מונחים", "איור", "טבלה", "הערות"},
+}
+
+// stringsForLanguage returns generated strings for a BCP 47 language tag,
+// falling back to English for unsupported languages.
+func stringsForLanguage(lang string) generatedStrings {
+	primary := strings.ToLower(lang)
+	if i := strings.IndexAny(primary, "-_"); i >= 0 {
+		primary = primary[:i]
+	}
+	if s, ok := translations[primary]; ok {
+		return s
+	}
+	return translations[defaultLanguage]
+}
+
+// localizedStrings returns the generated strings for the current book,
+// applying the navigation title override from the builder options.
+func (b *Builder) localizedStrings() generatedStrings {
+	s := stringsForLanguage(b.doc.Metadata.Language)
+	if b.opts.NavTitle != "" {
+		s.TableOfContents = b.opts.NavTitle
+	}
+	return s
+}
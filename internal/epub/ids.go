@@ -0,0 +1,124 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// reservedIDs are the manifest ids of the items the builder adds itself.
+var reservedIDs = []string{"nav", "css", "ncx"}
+
+// repairIDs makes the package valid where the document's names collide,
+// reporting each repair through Options.Warn. Manifest ids are made unique
+// by numbering later items, resources sharing a file name are written
+// once, and element ids repeated within a chapter are numbered so each
+// appears once; links to a repeated id keep pointing at its first use.
+func (b *Builder) repairIDs(doc *model.Document) {
+	used := make(map[string]bool)
+	for _, id := range reservedIDs {
+		used[id] = true
+	}
+	unique := func(id string) string {
+		if id == "" {
+			id = "item"
+		}
+		name := id
+		for n := 2; used[name]; n++ {
+			name = id + "-" + strconv.Itoa(n)
+		}
+		used[name] = true
+		return name
+	}
+	renamed := func(id, name, file string) {
+		b.warn(model.Warning{
+			Code:     model.WarnDuplicateID,
+			Severity: model.SeverityInfo,
+			File:     file,
+			Message:  fmt.Sprintf("Manifest id %q of %s is already in use; renamed to %q", id, file, name),
+		})
+	}
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if id := unique(chapter.ID); id != chapter.ID {
+			if chapter.ID != "" {
+				renamed(chapter.ID, id, chapter.FileName)
+			}
+			chapter.ID = id
+		}
+	}
+
+	files := make(map[string]bool, len(doc.Resources))
+	resources := doc.Resources[:0]
+	for _, res := range doc.Resources {
+		if files[res.FileName] {
+			b.warn(model.Warning{
+				Code:    model.WarnDuplicateID,
+				File:    res.FileName,
+				Message: fmt.Sprintf("Resource %s is in the book more than once; kept the first", res.FileName),
+			})
+			continue
+		}
+		files[res.FileName] = true
+		if id := unique(res.ID); id != res.ID {
+			if res.ID != "" {
+				renamed(res.ID, id, res.FileName)
+			}
+			res.ID = id
+		}
+		resources = append(resources, res)
+	}
+	doc.Resources = resources
+
+	for i := range doc.Chapters {
+		b.repairElementIDs(&doc.Chapters[i])
+	}
+}
+
+// repairElementIDs numbers the repeated uses of an element id in a chapter.
+func (b *Builder) repairElementIDs(chapter *model.Chapter) {
+	ids := idAttrsRe.FindAllStringSubmatch(chapter.Content, -1)
+	used := make(map[string]bool, len(ids))
+	repeated := false
+	for _, m := range ids {
+		id := html.UnescapeString(m[1] + m[2])
+		repeated = repeated || used[id]
+		used[id] = true
+	}
+	if !repeated {
+		return
+	}
+
+	seen := make(map[string]bool, len(ids))
+	chapter.Content = idAttrsRe.ReplaceAllStringFunc(chapter.Content, func(match string) string {
+		m := idAttrsRe.FindStringSubmatch(match)
+		id := html.UnescapeString(m[1] + m[2])
+		if !seen[id] {
+			seen[id] = true
+			return match
+		}
+
+		name := id
+		for n := 2; used[name]; n++ {
+			name = id + "-" + strconv.Itoa(n)
+		}
+		used[name] = true
+		b.warn(model.Warning{
+			Code:    model.WarnDuplicateID,
+			File:    chapter.FileName,
+			Message: fmt.Sprintf("Element id %q appears more than once in %s; renamed a copy to %q", id, chapter.FileName, name),
+		})
+		return match[:strings.Index(match, "id")] + `id="` + html.EscapeString(name) + `"`
+	})
+}
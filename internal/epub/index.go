@@ -0,0 +1,188 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// indexFileName is the EPUB path of the generated index chapter.
+const indexFileName = "content/index.xhtml"
+
+// Patterns used to collect index term markers.
+var (
+	indexMarkerRe = regexp.MustCompile(`(?is)<span\b([^>]*\bdata-index\b[^>]*)>(.*?)</span>`)
+	indexTermRe   = regexp.MustCompile(`(?i)\bdata-index\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	indexAttrRe   = regexp.MustCompile(`(?i)\s*\bdata-index\b(?:\s*=\s*(?:"[^"]*"|'[^']*'))?`)
+	idAttrRe      = regexp.MustCompile(`(?i)(?:^|\s)id\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	tagRe         = regexp.MustCompile(`<[^>]*>`)
+)
+
+// indexEntry is an index term with the locations it occurs at.
+type indexEntry struct {
+	Term      string
+	Locations []indexLocation
+}
+
+// indexLocation is a single occurrence of an index term.
+type indexLocation struct {
+	Title string // Title of the chapter containing the occurrence
+	Href  string // EPUB path with fragment identifier
+}
+
+// collectIndexEntries finds index term markers in all chapters, gives each
+// marker an id to link to, and returns the terms in alphabetical order.
+// Terms that differ only in case are merged under the first spelling seen.
+func collectIndexEntries(doc *model.Document) []indexEntry {
+	entries := make(map[string]*indexEntry)
+	next := 1
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = indexMarkerRe.ReplaceAllStringFunc(chapter.Content, func(marker string) string {
+			m := indexMarkerRe.FindStringSubmatch(marker)
+			attrs, inner := m[1], m[2]
+
+			term := indexTerm(attrs, inner)
+			if term == "" {
+				return marker
+			}
+
+			// Rewrite the marker with an explicit term so that bare
+			// data-index attributes become well-formed XHTML.
+			attrs = indexAttrRe.ReplaceAllString(attrs, "")
+			id := attrValue(idAttrRe, attrs)
+			if id == "" {
+				id = fmt.Sprintf("idx-%d", next)
+				next++
+				attrs = ` id="` + id + `"` + attrs
+			}
+			marker = "<span" + attrs + ` data-index="` + html.EscapeString(term) + `">` + inner + "</span>"
+
+			key := strings.ToLower(term)
+			entry, ok := entries[key]
+			if !ok {
+				entry = &indexEntry{Term: term}
+				entries[key] = entry
+			}
+			entry.Locations = append(entry.Locations, indexLocation{
+				Title: chapter.Title,
+				Href:  chapter.FileName + "#" + id,
+			})
+			return marker
+		})
+	}
+
+	result := make([]indexEntry, 0, len(entries))
+	for _, entry := range entries {
+		result = append(result, *entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := strings.ToLower(result[i].Term), strings.ToLower(result[j].Term)
+		if a != b {
+			return a < b
+		}
+		return result[i].Term < result[j].Term
+	})
+	return result
+}
+
+// indexTerm returns the term of a marker: the data-index value, or the
+// marker text when the attribute is empty.
+func indexTerm(attrs, inner string) string {
+	term := attrValue(indexTermRe, attrs)
+	if term == "" {
+		term = tagRe.ReplaceAllString(inner, "")
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(term)), " ")
+}
+
+// attrValue returns the first quoted attribute value matched by re.
+func attrValue(re *regexp.Regexp, attrs string) string {
+	m := re.FindStringSubmatch(attrs)
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2]
+}
+
+// renderIndex renders index entries grouped by initial letter, using the
+// EPUB Indexes vocabulary, with hrefs relative to the index document.
+func renderIndex(entries []indexEntry, title string) string {
+	var buf strings.Builder
+	buf.WriteString("<section epub:type=\"index\">\n  <h1>" + html.EscapeString(title) + "</h1>\n")
+
+	group := ""
+	for _, entry := range entries {
+		if initial := indexGroup(entry.Term); initial != group {
+			if group != "" {
+				buf.WriteString("    </ul>\n  </section>\n")
+			}
+			group = initial
+			buf.WriteString("  <section epub:type=\"index-group\">\n    <h2>" + html.EscapeString(group) + "</h2>\n")
+			buf.WriteString("    <ul epub:type=\"index-entry-list\">\n")
+		}
+
+		buf.WriteString("      <li epub:type=\"index-entry\"><span epub:type=\"index-term\">" + html.EscapeString(entry.Term) + "</span>")
+		for i, loc := range entry.Locations {
+			sep := ", "
+			if i == 0 {
+				sep = " "
+			}
+			buf.WriteString(sep + `<a epub:type="index-locator" href="` + html.EscapeString(relativeHref(indexFileName, loc.Href)) + `">` +
+				html.EscapeString(loc.Title) + "</a>")
+		}
+		buf.WriteString("</li>\n")
+	}
+	if group != "" {
+		buf.WriteString("    </ul>\n  </section>\n")
+	}
+
+	buf.WriteString("</section>")
+	return buf.String()
+}
+
+// indexGroup returns the heading an index term is listed under: its
+// upper-cased initial letter, or "#" for terms starting with other characters.
+func indexGroup(term string) string {
+	r, _ := utf8.DecodeRuneInString(term)
+	if unicode.IsLetter(r) {
+		return string(unicode.ToUpper(r))
+	}
+	return "#"
+}
+
+// addIndex appends a back-of-book index chapter when any chapter contains
+// index term markers, and links it from the table of contents and landmarks.
+func (b *Builder) addIndex(doc *model.Document) {
+	entries := collectIndexEntries(doc)
+	if len(entries) == 0 {
+		return
+	}
+
+	title := b.localizedStrings().Index
+	doc.AddChapter(model.Chapter{
+		ID:       "index",
+		Title:    title,
+		Level:    1,
+		Content:  renderIndex(entries, title),
+		FileName: indexFileName,
+		Order:    len(doc.Chapters),
+		Type:     "backmatter",
+	})
+	doc.TOC.AddEntry(model.TOCEntry{Title: title, Href: indexFileName, Level: 1})
+	b.landmarks = append(b.landmarks, landmark{Type: "index", Href: indexFileName, Title: title})
+}
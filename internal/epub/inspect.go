@@ -0,0 +1,188 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// PackageMetadata is the metadata of an existing EPUB, read from its
+// package document.
+type PackageMetadata struct {
+	Version      string          `json:"version" yaml:"version"`
+	Identifier   string          `json:"identifier,omitempty" yaml:"identifier,omitempty"` // Value of the unique-identifier
+	Identifiers  []MetadataEntry `json:"identifiers,omitempty" yaml:"identifiers,omitempty"`
+	Titles       []MetadataEntry `json:"titles,omitempty" yaml:"titles,omitempty"`
+	Creators     []MetadataEntry `json:"creators,omitempty" yaml:"creators,omitempty"`
+	Contributors []MetadataEntry `json:"contributors,omitempty" yaml:"contributors,omitempty"`
+	Languages    []string        `json:"languages,omitempty" yaml:"languages,omitempty"`
+	Publisher    string          `json:"publisher,omitempty" yaml:"publisher,omitempty"`
+	Description  string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Rights       string          `json:"rights,omitempty" yaml:"rights,omitempty"`
+	Date         string          `json:"date,omitempty" yaml:"date,omitempty"`
+	Modified     string          `json:"modified,omitempty" yaml:"modified,omitempty"`
+	Subjects     []string        `json:"subjects,omitempty" yaml:"subjects,omitempty"`
+	Collections  []MetadataEntry `json:"collections,omitempty" yaml:"collections,omitempty"`
+	Properties   []MetadataEntry `json:"properties,omitempty" yaml:"properties,omitempty"` // Further DC elements, meta properties, and links
+
+	OPF string `json:"-" yaml:"-"` // The metadata element as written in the package document
+}
+
+// MetadataEntry is a metadata element with the values of the meta
+// elements refining it, such as a creator's role and file-as name.
+type MetadataEntry struct {
+	Property    string            `json:"property,omitempty" yaml:"property,omitempty"` // Element or property name, for further properties
+	Value       string            `json:"value" yaml:"value"`
+	Refinements map[string]string `json:"refinements,omitempty" yaml:"refinements,omitempty"`
+}
+
+// opfRawPackage reads the metadata elements of a package document without
+// interpreting them.
+type opfRawPackage struct {
+	Version          string `xml:"version,attr"`
+	UniqueIdentifier string `xml:"unique-identifier,attr"`
+	Metadata         struct {
+		Elements []opfRawElement `xml:",any"`
+	} `xml:"metadata"`
+}
+
+// opfRawElement is a dc:* or meta element of a package document.
+type opfRawElement struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Value   string     `xml:",chardata"`
+}
+
+// attr returns the value of the named attribute in any namespace.
+func (e opfRawElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// ReadPackageMetadata reads the metadata of the EPUB at epubPath. Meta
+// elements refining another element, and EPUB 2 opf:role, opf:file-as,
+// and opf:scheme attributes, are gathered as that element's refinements.
+func ReadPackageMetadata(epubPath string) (*PackageMetadata, error) {
+	r, err := zip.OpenReader(epubPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer r.Close()
+
+	opfPath, err := findPackageDocument(&r.Reader)
+	if err != nil {
+		return nil, err
+	}
+	opf, err := readZipFile(&r.Reader, opfPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading %s: %v", ErrInvalidPackage, opfPath, err)
+	}
+	return parsePackageMetadata(opf)
+}
+
+// parsePackageMetadata reads the metadata of a package document.
+func parsePackageMetadata(opf string) (*PackageMetadata, error) {
+	var pkg opfRawPackage
+	if err := xml.Unmarshal([]byte(opf), &pkg); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	loc := metadataBlockRe.FindStringSubmatch(opf)
+	if loc == nil {
+		return nil, fmt.Errorf("%w: package document has no metadata", ErrInvalidPackage)
+	}
+
+	meta := &PackageMetadata{Version: pkg.Version, OPF: loc[1] + loc[2] + "\n" + strings.TrimSpace(loc[3])}
+
+	// Refinements of each id, gathered first as they may come before or
+	// after the element they refine
+	refinements := make(map[string]map[string]string)
+	for _, e := range pkg.Metadata.Elements {
+		refines := strings.TrimPrefix(e.attr("refines"), "#")
+		if e.XMLName.Local != "meta" || refines == "" {
+			continue
+		}
+		if refinements[refines] == nil {
+			refinements[refines] = make(map[string]string)
+		}
+		refinements[refines][e.attr("property")] = strings.TrimSpace(e.Value)
+	}
+
+	for _, e := range pkg.Metadata.Elements {
+		entry := MetadataEntry{Value: strings.TrimSpace(e.Value), Refinements: refinements[e.attr("id")]}
+		for _, a := range e.Attrs {
+			if a.Name.Space == opfNamespace || a.Name.Space == "opf" {
+				if entry.Refinements == nil {
+					entry.Refinements = make(map[string]string)
+				}
+				entry.Refinements[a.Name.Local] = a.Value
+			}
+		}
+
+		if e.XMLName.Local == "meta" {
+			switch {
+			case e.attr("refines") != "":
+				// Gathered above
+			case e.attr("name") != "":
+				// EPUB 2 meta, such as the cover or calibre:series
+				meta.Properties = append(meta.Properties, MetadataEntry{Property: e.attr("name"), Value: e.attr("content")})
+			case e.attr("property") == "dcterms:modified":
+				meta.Modified = entry.Value
+			case e.attr("property") == "belongs-to-collection":
+				meta.Collections = append(meta.Collections, entry)
+			default:
+				entry.Property = e.attr("property")
+				meta.Properties = append(meta.Properties, entry)
+			}
+			continue
+		}
+		if e.XMLName.Local == "link" {
+			meta.Properties = append(meta.Properties, MetadataEntry{Property: e.attr("rel"), Value: e.attr("href")})
+			continue
+		}
+		if e.XMLName.Space != dcNamespace {
+			continue
+		}
+
+		switch name := e.XMLName.Local; {
+		case name == "identifier":
+			meta.Identifiers = append(meta.Identifiers, entry)
+			if e.attr("id") == pkg.UniqueIdentifier {
+				meta.Identifier = entry.Value
+			}
+		case name == "title":
+			meta.Titles = append(meta.Titles, entry)
+		case name == "creator":
+			meta.Creators = append(meta.Creators, entry)
+		case name == "contributor":
+			meta.Contributors = append(meta.Contributors, entry)
+		case name == "language":
+			meta.Languages = append(meta.Languages, entry.Value)
+		case name == "subject":
+			meta.Subjects = append(meta.Subjects, entry.Value)
+		case name == "publisher" && meta.Publisher == "":
+			meta.Publisher = entry.Value
+		case name == "description" && meta.Description == "":
+			meta.Description = entry.Value
+		case name == "rights" && meta.Rights == "":
+			meta.Rights = entry.Value
+		case name == "date" && meta.Date == "":
+			meta.Date = entry.Value
+		default:
+			entry.Property = "dc:" + name
+			meta.Properties = append(meta.Properties, entry)
+		}
+	}
+	return meta, nil
+}
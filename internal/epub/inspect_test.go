@@ -0,0 +1,69 @@
+package epub
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestReadPackageMetadata(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Tom & Jerry"
+	doc.Metadata.Authors = []string{"Jane Doe"}
+	doc.Metadata.Contributors = []model.Contributor{{Name: "Ed", Role: model.RoleEditor}}
+	doc.Metadata.Refinements = map[string][]model.Refinement{"Jane Doe": {{Property: "file-as", Value: "Doe, Jane"}}}
+	doc.Metadata.Subjects = []string{"Fiction"}
+	doc.Metadata.Collections = []model.Collection{{Name: "The Saga", Type: model.CollectionSeries, Position: "2"}}
+	doc.Metadata.Properties = []model.Property{{Name: "dc:coverage", Value: "Europe"}}
+	doc.Metadata.AddIdentifier(model.Identifier{Scheme: model.SchemeISBN, Value: "urn:isbn:9780306406157"})
+	doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", Content: "<p>One</p>", FileName: "content/chapter-001.xhtml"})
+
+	data, err := NewBuilder().Build(doc)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	meta, err := ReadPackageMetadata(path)
+	require.NoError(t, err)
+	assert.Equal(t, "3.0", meta.Version)
+	assert.Equal(t, "urn:isbn:9780306406157", meta.Identifier)
+	assert.Equal(t, []MetadataEntry{{Value: "Tom & Jerry"}}, meta.Titles)
+	assert.Equal(t, []MetadataEntry{{Value: "Jane Doe", Refinements: map[string]string{"role": "aut", "file-as": "Doe, Jane"}}}, meta.Creators)
+	assert.Equal(t, []MetadataEntry{{Value: "Ed", Refinements: map[string]string{"role": model.RoleEditor}}}, meta.Contributors)
+	assert.Equal(t, []string{"Fiction"}, meta.Subjects)
+	assert.Equal(t, []MetadataEntry{{Value: "The Saga", Refinements: map[string]string{"collection-type": "series", "group-position": "2"}}}, meta.Collections)
+	assert.Contains(t, meta.Properties, MetadataEntry{Property: "dc:coverage", Value: "Europe"})
+	assert.Contains(t, meta.Properties, MetadataEntry{Property: "calibre:series", Value: "The Saga"})
+	assert.NotEmpty(t, meta.Modified)
+	assert.Contains(t, meta.OPF, "<dc:title>Tom &amp; Jerry</dc:title>")
+}
+
+func TestParsePackageMetadata_EPUB2(t *testing.T) {
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="BookId">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+    <dc:title>Old Book</dc:title>
+    <dc:creator opf:role="aut" opf:file-as="Doe, Jane">Jane Doe</dc:creator>
+    <dc:identifier id="BookId" opf:scheme="ISBN">9780306406157</dc:identifier>
+    <dc:date opf:event="publication">1999</dc:date>
+    <meta name="cover" content="cover-image"/>
+  </metadata>
+</package>`
+
+	meta, err := parsePackageMetadata(opf)
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", meta.Version)
+	assert.Equal(t, "9780306406157", meta.Identifier)
+	assert.Equal(t, []MetadataEntry{{Value: "Jane Doe", Refinements: map[string]string{"role": "aut", "file-as": "Doe, Jane"}}}, meta.Creators)
+	assert.Equal(t, map[string]string{"scheme": "ISBN"}, meta.Identifiers[0].Refinements)
+	assert.Equal(t, "1999", meta.Date)
+	assert.Equal(t, []MetadataEntry{{Property: "cover", Value: "cover-image"}}, meta.Properties)
+
+	_, err = parsePackageMetadata("<package><manifest/></package>")
+	assert.ErrorIs(t, err, ErrInvalidPackage)
+}
@@ -0,0 +1,162 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+var (
+	linkAttrRe = regexp.MustCompile(`\s(?:xlink:)?(?:href|src)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	idAttrsRe  = regexp.MustCompile(`\sid\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	schemeRe   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*:`)
+)
+
+// brokenLink is a link whose target is not in the package.
+type brokenLink struct {
+	from, href, reason string
+}
+
+// checkLinks verifies that every href and src in the chapters and every
+// navigation entry resolves to a file in the package and, with a fragment,
+// to an element with that id. Broken links fail the build with
+// Options.Strict and are otherwise reported through Options.Warn.
+func (b *Builder) checkLinks() error {
+	// Files in the package and the ids in each document, relative to OEBPS
+	files := map[string]bool{"nav.xhtml": true}
+	if b.opts.defaultCSS() {
+		files[defaultStylesheet] = true
+	}
+	if b.opts.legacy() {
+		files[ncxFile] = true
+	}
+	for _, res := range b.doc.Resources {
+		files[res.FileName] = true
+	}
+	ids := make(map[string]map[string]bool, len(b.doc.Chapters))
+	for _, chapter := range b.doc.Chapters {
+		files[chapter.FileName] = true
+		ids[chapter.FileName] = documentIDs(chapter.Content)
+	}
+
+	var broken []brokenLink
+	check := func(from, href, target string) {
+		if reason := resolveLink(target, files, ids); reason != "" {
+			broken = append(broken, brokenLink{from, href, reason})
+		}
+	}
+
+	for _, chapter := range b.doc.Chapters {
+		broken = append(broken, documentLinks(chapter.FileName, chapter.Content, files, ids)...)
+	}
+	var walk func(entries []model.TOCEntry)
+	walk = func(entries []model.TOCEntry) {
+		for _, entry := range entries {
+			check("nav.xhtml", entry.Href, entry.Href)
+			walk(entry.Children)
+		}
+	}
+	walk(b.tocEntries())
+	for _, l := range b.landmarks {
+		check("nav.xhtml", l.Href, l.Href)
+	}
+
+	if len(broken) == 0 {
+		return nil
+	}
+	if b.opts.Strict {
+		first := broken[0]
+		return fmt.Errorf("%w: %d broken, first %s in %s: %s", ErrBrokenLink, len(broken), first.href, first.from, first.reason)
+	}
+	for _, l := range broken {
+		b.warn(model.Warning{
+			Code:    model.WarnBrokenLink,
+			File:    l.from,
+			Message: fmt.Sprintf("Link %s in %s: %s", l.href, l.from, l.reason),
+		})
+	}
+	return nil
+}
+
+// documentIDs returns the ids of the elements in content.
+func documentIDs(content string) map[string]bool {
+	set := make(map[string]bool)
+	for _, m := range idAttrsRe.FindAllStringSubmatch(content, -1) {
+		set[html.UnescapeString(m[1]+m[2])] = true
+	}
+	return set
+}
+
+// documentLinks returns the broken links in content, the document at from.
+func documentLinks(from, content string, files map[string]bool, ids map[string]map[string]bool) []brokenLink {
+	var broken []brokenLink
+	for _, m := range linkAttrRe.FindAllStringSubmatch(content, -1) {
+		href := html.UnescapeString(m[1] + m[2])
+		target, ok := linkTarget(from, href)
+		if !ok {
+			continue
+		}
+		if reason := resolveLink(target, files, ids); reason != "" {
+			broken = append(broken, brokenLink{from, href, reason})
+		}
+	}
+	return broken
+}
+
+// linkTarget resolves href in the document at from to a path relative to
+// OEBPS, keeping its fragment. Links to other sites, data URIs, and bare
+// "#" are not checked.
+func linkTarget(from, href string) (string, bool) {
+	href = strings.TrimSpace(href)
+	if href == "" || href == "#" || schemeRe.MatchString(href) || strings.HasPrefix(href, "//") {
+		return "", false
+	}
+	file, fragment, _ := strings.Cut(href, "#")
+	file, _, _ = strings.Cut(file, "?")
+	if unescaped, err := url.PathUnescape(file); err == nil {
+		file = unescaped
+	}
+	if file == "" {
+		file = from
+	} else {
+		file = path.Join(path.Dir(from), file)
+	}
+	if fragment != "" {
+		return file + "#" + fragment, true
+	}
+	return file, true
+}
+
+// resolveLink returns why target does not resolve, or "" if it does.
+func resolveLink(target string, files map[string]bool, ids map[string]map[string]bool) string {
+	file, fragment, _ := strings.Cut(target, "#")
+	if !files[file] {
+		return "no such file in the book"
+	}
+	if fragment == "" {
+		return ""
+	}
+	set, ok := ids[file]
+	if !ok {
+		return "" // Fragments into generated documents are not checked
+	}
+	if unescaped, err := url.PathUnescape(fragment); err == nil {
+		fragment = unescaped
+	}
+	if !set[fragment] {
+		return fmt.Sprintf("no element with id %q", fragment)
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Math output modes, for reading systems with and without MathML support.
+const (
+	MathMathML = "mathml" // MathML as written, for reading systems that render it
+	MathSVG    = "svg"    // Each equation replaced by an SVG image
+	MathBoth   = "both"   // MathML with an SVG fallback image for other reading systems
+)
+
+// ParseMath validates a math output mode, returning MathMathML for an
+// empty string.
+func ParseMath(s string) (string, error) {
+	switch s {
+	case "", MathMathML:
+		return MathMathML, nil
+	case MathSVG, MathBoth:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown math output %q", s)
+	}
+}
+
+// mathNamespace is the MathML namespace, required of epub:switch cases.
+const mathNamespace = "http://www.w3.org/1998/Math/MathML"
+
+// mathBlockRe matches a math element.
+var mathBlockRe = regexp.MustCompile(`(?s)<math\b[^>]*>.*?</math>`)
+
+// renderMath renders each equation as an SVG image under math/, and
+// replaces the MathML with the image or, in MathBoth mode, points its
+// altimg at the image. EPUB 3.0 books also offer the image through
+// epub:switch, which EPUB 3.3 deprecates.
+func (b *Builder) renderMath(doc *model.Document) {
+	mode, _ := ParseMath(b.opts.Math)
+	if mode == MathMathML {
+		return
+	}
+
+	count := 0
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = mathBlockRe.ReplaceAllStringFunc(chapter.Content, func(src string) string {
+			root, err := parseMathML(src)
+			if err != nil {
+				b.warn(model.Warning{
+					Code:    model.WarnMathRender,
+					File:    chapter.FileName,
+					Message: fmt.Sprintf("Equation in %s kept as MathML: %v", chapter.FileName, err),
+				})
+				return src
+			}
+
+			alt := root.attrs["alttext"]
+			if alt == "" {
+				alt = mathAltText(root)
+			}
+			svg, height, depth := renderMathSVG(root, alt)
+
+			count++
+			res := model.Resource{
+				ID:        fmt.Sprintf("math-%03d", count),
+				FileName:  fmt.Sprintf("math/equation-%03d.svg", count),
+				MediaType: "image/svg+xml",
+				Data:      svg,
+			}
+			doc.AddResource(res)
+
+			href := html.EscapeString(relativeHref(chapter.FileName, res.FileName))
+			class := "math"
+			if root.attrs["display"] == "block" {
+				class = "math-display"
+			}
+			img := fmt.Sprintf(`<img class="%s" src="%s" alt="%s" style="height: %.2fem; vertical-align: -%.2fem"/>`,
+				class, href, html.EscapeString(alt), height, depth)
+			if mode == MathSVG {
+				return img
+			}
+
+			attrs := fmt.Sprintf(` altimg="%s" altimg-height="%.2fem" altimg-valign="-%.2fem"`, href, height, depth)
+			if root.attrs["alttext"] == "" {
+				attrs += ` alttext="` + html.EscapeString(alt) + `"`
+			}
+			src = "<math" + attrs + strings.TrimPrefix(src, "<math")
+			if b.opts.legacy() {
+				return `<epub:switch><epub:case required-namespace="` + mathNamespace + `">` + src +
+					`</epub:case><epub:default>` + img + `</epub:default></epub:switch>`
+			}
+			return src
+		})
+	}
+}
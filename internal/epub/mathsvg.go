@@ -0,0 +1,725 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Layout constants of rendered equations, in ems of the surrounding text.
+const (
+	mathAscent     = 0.75 // Height of text above the baseline
+	mathDescent    = 0.25 // Depth of text below the baseline
+	mathAxis       = 0.25 // Height of fraction bars and operators above the baseline
+	mathScript     = 0.71 // Size of scripts relative to their base
+	mathMinSize    = 0.5  // Smallest size scripts of scripts shrink to
+	mathRule       = 0.06 // Thickness of fraction bars and radical signs
+	mathPadding    = 0.05 // Space around the equation
+	mathPixelsPerE = 16   // SVG units per em
+)
+
+// mathNode is an element of a MathML expression, or a text run when name
+// is empty.
+type mathNode struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*mathNode
+}
+
+// parseMathML parses a math element, accepting the HTML named character
+// references the HTML parser leaves in place.
+func parseMathML(src string) (*mathNode, error) {
+	d := xml.NewDecoder(strings.NewReader(src))
+	d.Entity = xml.HTMLEntity
+
+	root := &mathNode{}
+	stack := []*mathNode{root}
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parsing MathML: %w", err)
+		}
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &mathNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, attr := range t.Attr {
+				n.attrs[attr.Name.Local] = attr.Value
+			}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.children = append(parent.children, &mathNode{text: string(t)})
+		}
+	}
+	if len(root.children) != 1 || root.children[0].name != "math" {
+		return nil, fmt.Errorf("parsing MathML: not a math element")
+	}
+	return root.children[0], nil
+}
+
+// elements returns the child elements of n, leaving out text runs.
+func (n *mathNode) elements() []*mathNode {
+	var elements []*mathNode
+	for _, c := range n.children {
+		if c.name != "" {
+			elements = append(elements, c)
+		}
+	}
+	return elements
+}
+
+// content returns the text of a token element with its whitespace
+// collapsed.
+func (n *mathNode) content() string {
+	var text strings.Builder
+	var walk func(*mathNode)
+	walk = func(n *mathNode) {
+		text.WriteString(n.text)
+		for _, c := range n.children {
+			walk(c)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// child returns the i-th child element of n, or an empty row.
+func (n *mathNode) child(i int) *mathNode {
+	if elements := n.elements(); i < len(elements) {
+		return elements[i]
+	}
+	return &mathNode{name: "mrow"}
+}
+
+// Operator classes, which set the space around an operator.
+var (
+	mathRelations = "=<>≤≥≠≈≡∼≅∝→←↔⇒⇐⇔∈∉∋⊂⊃⊆⊇≪≫≺≻∣∥⊥:≔"
+	mathBinary    = "+-−±∓×÷·∗∘∪∩⊕⊗∧∨∖⋅"
+	mathLargeOps  = "∑∏∐∫∬∭∮⋃⋂⋀⋁⨁⨂"
+	mathFences    = "()[]{}|‖⟨⟩⌈⌉⌊⌋"
+)
+
+// mathOperatorSpace returns the space before and after an operator, in ems,
+// where prefix is set for operators opening a row, such as the sign of -b.
+func mathOperatorSpace(op string, prefix bool) (before, after float64) {
+	switch {
+	case op == "," || op == ";":
+		return 0, 0.17
+	case prefix:
+		return 0, 0
+	case strings.Contains(mathRelations, op) && utf8.RuneCountInString(op) == 1, op == ":=":
+		return 0.28, 0.28
+	case strings.Contains(mathBinary, op) && utf8.RuneCountInString(op) == 1:
+		return 0.22, 0.22
+	case strings.Contains(mathLargeOps, op) && utf8.RuneCountInString(op) == 1:
+		return 0, 0.17
+	case len(op) > 1 && unicode.IsLetter([]rune(op)[0]):
+		return 0, 0.17 // Function names such as sin and lim
+	}
+	return 0, 0
+}
+
+// mathCharWidth estimates the advance width of a character in a serif
+// font, in ems.
+func mathCharWidth(r rune) float64 {
+	switch {
+	case r == ' ':
+		return 0.25
+	case strings.ContainsRune("il.,;:!|'`", r):
+		return 0.28
+	case strings.ContainsRune("fjtr()[]{}", r):
+		return 0.33
+	case strings.ContainsRune("mwMW", r):
+		return 0.85
+	case r >= '0' && r <= '9', r >= 'a' && r <= 'z':
+		return 0.5
+	case r >= 'A' && r <= 'Z':
+		return 0.68
+	case r >= 0x391 && r <= 0x3A9:
+		return 0.65
+	case r >= 0x3B1 && r <= 0x3C9:
+		return 0.55
+	case strings.ContainsRune(mathRelations+mathBinary, r):
+		return 0.56
+	case strings.ContainsRune(mathLargeOps, r):
+		return 0.7
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul):
+		return 1
+	}
+	return 0.6
+}
+
+// mathBox is a laid out part of an equation, measured in ems. draw writes
+// its SVG with the left end of its baseline at x, y.
+type mathBox struct {
+	width, ascent, descent float64
+	draw                   func(svg *strings.Builder, x, y float64)
+}
+
+// mathLayout lays out MathML expressions as boxes.
+type mathLayout struct {
+	display bool // Block equations, whose fractions and operators are full size
+}
+
+// layout returns the box of n at size, relative to the surrounding text.
+func (l *mathLayout) layout(n *mathNode, size float64) mathBox {
+	switch n.name {
+	case "mi":
+		text := n.content()
+		italic := utf8.RuneCountInString(text) == 1 && n.attrs["mathvariant"] == ""
+		return l.token(text, size, italic, n.attrs["mathvariant"] == "bold")
+	case "mn", "mtext":
+		return l.token(n.content(), size, false, n.attrs["mathvariant"] == "bold")
+	case "ms":
+		return l.token(`"`+n.content()+`"`, size, false, false)
+	case "mo":
+		return l.operator(n.content(), size, false)
+	case "mspace":
+		return mathBox{width: mathLength(n.attrs["width"], size), draw: func(*strings.Builder, float64, float64) {}}
+	case "msup", "msub", "msubsup":
+		return l.scripts(n, size)
+	case "mfrac":
+		return l.fraction(n, size)
+	case "msqrt":
+		return l.radical(l.row(n.elements(), size), nil, size)
+	case "mroot":
+		index := l.layout(n.child(1), math.Max(size*mathScript*mathScript, mathMinSize))
+		return l.radical(l.layout(n.child(0), size), &index, size)
+	case "mover", "munder", "munderover":
+		return l.stack(n, size)
+	case "mtable":
+		return l.table(n, size)
+	case "mfenced":
+		return l.row(fencedRow(n), size)
+	case "mphantom":
+		box := l.row(n.elements(), size)
+		box.draw = func(*strings.Builder, float64, float64) {}
+		return box
+	case "semantics", "maction":
+		return l.layout(n.child(0), size)
+	case "annotation", "annotation-xml":
+		return mathBox{draw: func(*strings.Builder, float64, float64) {}}
+	case "mstyle":
+		if n.attrs["displaystyle"] != "" {
+			inner := *l
+			inner.display = n.attrs["displaystyle"] == "true"
+			return inner.row(n.elements(), size)
+		}
+	}
+	return l.row(n.elements(), size)
+}
+
+// token returns the box of a run of text.
+func (l *mathLayout) token(text string, size float64, italic, bold bool) mathBox {
+	width := 0.0
+	for _, r := range text {
+		width += mathCharWidth(r)
+	}
+	if italic {
+		width += 0.05
+	}
+	width *= size
+
+	attrs := ""
+	if italic {
+		attrs += ` font-style="italic"`
+	}
+	if bold {
+		attrs += ` font-weight="bold"`
+	}
+	return mathBox{
+		width:   width,
+		ascent:  mathAscent * size,
+		descent: mathDescent * size,
+		draw: func(svg *strings.Builder, x, y float64) {
+			fmt.Fprintf(svg, `<text x="%s" y="%s" font-size="%s"%s>%s</text>`+"\n",
+				mathNum(x), mathNum(y), mathNum(size), attrs, html.EscapeString(text))
+		},
+	}
+}
+
+// operator returns the box of an operator with the space around it.
+func (l *mathLayout) operator(op string, size float64, prefix bool) mathBox {
+	text := op
+	if text == "-" {
+		text = "−"
+	}
+	opSize := size
+	if l.display && strings.Contains(mathLargeOps, op) && utf8.RuneCountInString(op) == 1 {
+		opSize = size * 1.4
+	}
+	box := l.token(text, opSize, false, false)
+	if opSize != size {
+		// Center the enlarged operator on the axis
+		shift := (box.ascent-box.descent)/2 - mathAxis*size
+		box.ascent, box.descent = box.ascent-shift, box.descent+shift
+		draw := box.draw
+		box.draw = func(svg *strings.Builder, x, y float64) { draw(svg, x, y+shift) }
+	}
+
+	before, after := mathOperatorSpace(op, prefix)
+	box.width += (before + after) * size
+	draw := box.draw
+	box.draw = func(svg *strings.Builder, x, y float64) { draw(svg, x+before*size, y) }
+	return box
+}
+
+// fence returns the box of a fence stretched to span ascent and descent.
+func (l *mathLayout) fence(op string, size, ascent, descent float64) mathBox {
+	box := l.token(op, size, false, false)
+	height := ascent + descent
+	natural := box.ascent + box.descent
+	if height <= natural*1.1 {
+		return box
+	}
+
+	scale := height / natural
+	draw := func(svg *strings.Builder, x, y float64) {
+		top := y - ascent
+		fmt.Fprintf(svg, `<g transform="translate(%s %s) scale(1 %s)">`+"\n", mathNum(x), mathNum(top), mathNum(scale))
+		box.draw(svg, 0, box.ascent)
+		svg.WriteString("</g>\n")
+	}
+	return mathBox{width: box.width, ascent: ascent, descent: descent, draw: draw}
+}
+
+// row returns the box of elements set side by side, with fences stretched
+// to the height of the rest of the row.
+func (l *mathLayout) row(elements []*mathNode, size float64) mathBox {
+	boxes := make([]mathBox, len(elements))
+	var ascent, descent float64
+	var fences []int
+	for i, n := range elements {
+		if n.name == "mo" && isMathFence(n) {
+			fences = append(fences, i)
+			continue
+		}
+		if n.name == "mo" {
+			boxes[i] = l.operator(n.content(), size, isMathPrefix(elements, i))
+		} else {
+			boxes[i] = l.layout(n, size)
+		}
+		ascent = math.Max(ascent, boxes[i].ascent)
+		descent = math.Max(descent, boxes[i].descent)
+	}
+	for _, i := range fences {
+		boxes[i] = l.fence(elements[i].content(), size, math.Max(ascent, mathAscent*size), math.Max(descent, mathDescent*size))
+		ascent = math.Max(ascent, boxes[i].ascent)
+		descent = math.Max(descent, boxes[i].descent)
+	}
+	if len(boxes) == 1 {
+		return boxes[0]
+	}
+
+	width := 0.0
+	for _, box := range boxes {
+		width += box.width
+	}
+	return mathBox{
+		width:   width,
+		ascent:  ascent,
+		descent: descent,
+		draw: func(svg *strings.Builder, x, y float64) {
+			for _, box := range boxes {
+				box.draw(svg, x, y)
+				x += box.width
+			}
+		},
+	}
+}
+
+// isMathFence reports whether an operator is a fence that stretches.
+func isMathFence(n *mathNode) bool {
+	op := n.content()
+	return n.attrs["stretchy"] != "false" && utf8.RuneCountInString(op) == 1 && strings.Contains(mathFences, op)
+}
+
+// isMathPrefix reports whether the i-th element of a row is a prefix
+// operator: one opening the row or following another operator.
+func isMathPrefix(elements []*mathNode, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := elements[i-1]
+	return prev.name == "mo" && !strings.Contains(")]}|‖⟩⌉⌋", prev.content())
+}
+
+// fencedRow returns the row an mfenced element stands for: its children
+// separated by commas, between parentheses.
+func fencedRow(n *mathNode) []*mathNode {
+	open, close, separators := "(", ")", ","
+	if v, ok := n.attrs["open"]; ok {
+		open = v
+	}
+	if v, ok := n.attrs["close"]; ok {
+		close = v
+	}
+	if v, ok := n.attrs["separators"]; ok {
+		separators = strings.Join(strings.Fields(v), "")
+	}
+	sep := []rune(separators)
+
+	row := []*mathNode{{name: "mo", children: []*mathNode{{text: open}}}}
+	for i, c := range n.elements() {
+		if i > 0 && len(sep) > 0 {
+			s := sep[min(i-1, len(sep)-1)]
+			row = append(row, &mathNode{name: "mo", children: []*mathNode{{text: string(s)}}})
+		}
+		row = append(row, c)
+	}
+	return append(row, &mathNode{name: "mo", children: []*mathNode{{text: close}}})
+}
+
+// scripts returns the box of a base with a superscript, a subscript, or
+// both.
+func (l *mathLayout) scripts(n *mathNode, size float64) mathBox {
+	base := l.layout(n.child(0), size)
+	scriptSize := math.Max(size*mathScript, mathMinSize)
+
+	var sub, sup *mathBox
+	switch n.name {
+	case "msub":
+		box := l.layout(n.child(1), scriptSize)
+		sub = &box
+	case "msup":
+		box := l.layout(n.child(1), scriptSize)
+		sup = &box
+	default:
+		subBox, supBox := l.layout(n.child(1), scriptSize), l.layout(n.child(2), scriptSize)
+		sub, sup = &subBox, &supBox
+	}
+
+	box := mathBox{width: base.width, ascent: base.ascent, descent: base.descent}
+	var supShift, subShift, scriptWidth float64
+	if sup != nil {
+		supShift = math.Max(0.42*size, base.ascent-0.3*size)
+		box.ascent = math.Max(box.ascent, supShift+sup.ascent)
+		scriptWidth = sup.width
+	}
+	if sub != nil {
+		subShift = math.Max(0.22*size, base.descent)
+		box.descent = math.Max(box.descent, subShift+sub.descent)
+		scriptWidth = math.Max(scriptWidth, sub.width)
+	}
+	box.width += scriptWidth + 0.05*size
+	box.draw = func(svg *strings.Builder, x, y float64) {
+		base.draw(svg, x, y)
+		if sup != nil {
+			sup.draw(svg, x+base.width, y-supShift)
+		}
+		if sub != nil {
+			sub.draw(svg, x+base.width, y+subShift)
+		}
+	}
+	return box
+}
+
+// fraction returns the box of a fraction, centered on the axis.
+func (l *mathLayout) fraction(n *mathNode, size float64) mathBox {
+	partSize := size
+	if !l.display {
+		partSize = math.Max(size*mathScript, mathMinSize)
+	}
+	num, den := l.layout(n.child(0), partSize), l.layout(n.child(1), partSize)
+
+	rule := mathRule * size
+	if n.attrs["linethickness"] == "0" || n.attrs["linethickness"] == "0px" {
+		rule = 0
+	}
+	gap := 0.12 * size
+	axis := mathAxis * size
+	pad := 0.1 * size
+	width := math.Max(num.width, den.width) + 2*pad
+
+	numShift := axis + rule/2 + gap + num.descent
+	denShift := -axis + rule/2 + gap + den.ascent
+	return mathBox{
+		width:   width,
+		ascent:  numShift + num.ascent,
+		descent: denShift + den.descent,
+		draw: func(svg *strings.Builder, x, y float64) {
+			num.draw(svg, x+(width-num.width)/2, y-numShift)
+			den.draw(svg, x+(width-den.width)/2, y+denShift)
+			if rule > 0 {
+				fmt.Fprintf(svg, `<rect x="%s" y="%s" width="%s" height="%s"/>`+"\n",
+					mathNum(x+pad/2), mathNum(y-axis-rule/2), mathNum(width-pad), mathNum(rule))
+			}
+		},
+	}
+}
+
+// radical returns the box of a square root, or of a root with an index.
+func (l *mathLayout) radical(inner mathBox, index *mathBox, size float64) mathBox {
+	rule := mathRule * size
+	gap := 0.12 * size
+	sign := 0.6 * size
+	offset := 0.0
+	if index != nil {
+		offset = math.Max(0, index.width-0.3*size)
+	}
+
+	top := inner.ascent + gap + rule
+	box := mathBox{
+		width:   offset + sign + inner.width + 0.05*size,
+		ascent:  top + 0.05*size,
+		descent: inner.descent + 0.05*size,
+	}
+	if index != nil {
+		box.ascent = math.Max(box.ascent, top*0.6+index.ascent+index.descent)
+	}
+	box.draw = func(svg *strings.Builder, x, y float64) {
+		x0 := x + offset
+		bottom := y + inner.descent
+		fmt.Fprintf(svg, `<path d="M%s %s L%s %s L%s %s L%s %s L%s %s" fill="none" stroke="#000" stroke-width="%s" stroke-linejoin="round"/>`+"\n",
+			mathNum(x0+0.05*size), mathNum(y-0.25*size),
+			mathNum(x0+0.2*size), mathNum(y-0.35*size),
+			mathNum(x0+0.35*size), mathNum(bottom),
+			mathNum(x0+sign-0.05*size), mathNum(y-top+rule/2),
+			mathNum(x0+sign+inner.width+0.05*size), mathNum(y-top+rule/2),
+			mathNum(rule))
+		inner.draw(svg, x0+sign, y)
+		if index != nil {
+			index.draw(svg, x, y-top*0.6-index.descent)
+		}
+	}
+	return box
+}
+
+// stack returns the box of a base with scripts set over or under it.
+func (l *mathLayout) stack(n *mathNode, size float64) mathBox {
+	base := l.layout(n.child(0), size)
+	scriptSize := math.Max(size*mathScript, mathMinSize)
+
+	var over, under *mathBox
+	switch n.name {
+	case "mover":
+		box := l.layout(n.child(1), scriptSize)
+		over = &box
+	case "munder":
+		box := l.layout(n.child(1), scriptSize)
+		under = &box
+	default:
+		underBox, overBox := l.layout(n.child(1), scriptSize), l.layout(n.child(2), scriptSize)
+		under, over = &underBox, &overBox
+	}
+
+	gap := 0.08 * size
+	box := mathBox{width: base.width, ascent: base.ascent, descent: base.descent}
+	var overShift, underShift float64
+	if over != nil {
+		box.width = math.Max(box.width, over.width)
+		overShift = base.ascent + gap + over.descent
+		box.ascent = overShift + over.ascent
+	}
+	if under != nil {
+		box.width = math.Max(box.width, under.width)
+		underShift = base.descent + gap + under.ascent
+		box.descent = underShift + under.descent
+	}
+	width := box.width
+	box.draw = func(svg *strings.Builder, x, y float64) {
+		base.draw(svg, x+(width-base.width)/2, y)
+		if over != nil {
+			over.draw(svg, x+(width-over.width)/2, y-overShift)
+		}
+		if under != nil {
+			under.draw(svg, x+(width-under.width)/2, y+underShift)
+		}
+	}
+	return box
+}
+
+// table returns the box of a table with centered cells, centered on the
+// axis.
+func (l *mathLayout) table(n *mathNode, size float64) mathBox {
+	var cells [][]mathBox
+	var widths []float64
+	for _, tr := range n.elements() {
+		var row []mathBox
+		for i, td := range tr.elements() {
+			cell := l.row(td.elements(), size)
+			row = append(row, cell)
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = math.Max(widths[i], cell.width)
+		}
+		cells = append(cells, row)
+	}
+
+	colGap, rowGap := 0.8*size, 0.3*size
+	width, height := 0.0, 0.0
+	for i, w := range widths {
+		if i > 0 {
+			width += colGap
+		}
+		width += w
+	}
+	baselines := make([]float64, len(cells))
+	for i, row := range cells {
+		ascent, descent := mathAscent*size, mathDescent*size
+		for _, cell := range row {
+			ascent, descent = math.Max(ascent, cell.ascent), math.Max(descent, cell.descent)
+		}
+		if i > 0 {
+			height += rowGap
+		}
+		baselines[i] = height + ascent
+		height += ascent + descent
+	}
+
+	ascent := height/2 + mathAxis*size
+	return mathBox{
+		width:   width,
+		ascent:  ascent,
+		descent: height - ascent,
+		draw: func(svg *strings.Builder, x, y float64) {
+			top := y - ascent
+			for i, row := range cells {
+				cx := x
+				for j, cell := range row {
+					cell.draw(svg, cx+(widths[j]-cell.width)/2, top+baselines[i])
+					cx += widths[j] + colGap
+				}
+			}
+		},
+	}
+}
+
+// mathLength converts a MathML length, such as 1em, 0.5ex, or 4px, to ems.
+func mathLength(s string, size float64) float64 {
+	s = strings.TrimSpace(s)
+	for unit, ems := range map[string]float64{"em": 1, "ex": 0.5, "px": 1.0 / mathPixelsPerE, "pt": 1.0 / 12} {
+		if v, ok := strings.CutSuffix(s, unit); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f * ems * size
+			}
+		}
+	}
+	switch s {
+	case "thinmathspace":
+		return size / 6
+	case "mediummathspace":
+		return size * 2 / 9
+	case "thickmathspace":
+		return size * 5 / 18
+	}
+	return 0
+}
+
+// mathNum formats a length in ems as SVG units.
+func mathNum(ems float64) string {
+	return strconv.FormatFloat(math.Round(ems*mathPixelsPerE*100)/100, 'f', -1, 64)
+}
+
+// renderMathSVG renders a MathML expression as an SVG document, returning
+// it with its height and baseline depth in ems.
+func renderMathSVG(n *mathNode, alt string) (svg []byte, height, depth float64) {
+	l := &mathLayout{display: n.attrs["display"] == "block"}
+	box := l.row(n.elements(), 1)
+
+	width := box.width + 2*mathPadding
+	height = box.ascent + box.descent + 2*mathPadding
+	depth = box.descent + mathPadding
+
+	var buf strings.Builder
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" role="img">`+"\n",
+		mathNum(width), mathNum(height), mathNum(width), mathNum(height))
+	buf.WriteString("<title>" + html.EscapeString(alt) + "</title>\n")
+	buf.WriteString(`<g font-family="serif" fill="#000">` + "\n")
+	box.draw(&buf, mathPadding, mathPadding+box.ascent)
+	buf.WriteString("</g>\n</svg>\n")
+	return []byte(buf.String()), height, depth
+}
+
+// mathScripted are the elements whose alt text ends in a script.
+var mathScripted = map[string]bool{
+	"msup": true, "msub": true, "msubsup": true, "mover": true, "munder": true, "munderover": true,
+}
+
+// mathAltText returns a linear text form of a MathML expression, such as
+// "(-b + √(b^2 - 4ac))/(2a)", for equations without alttext.
+func mathAltText(n *mathNode) string {
+	group := func(s string) string {
+		if utf8.RuneCountInString(s) > 1 {
+			return "(" + s + ")"
+		}
+		return s
+	}
+	row := func(elements []*mathNode) string {
+		var text strings.Builder
+		for i, c := range elements {
+			// Keep a term from running into the scripts before it, as in x^2 y
+			if i > 0 && c.name != "mo" && mathScripted[elements[i-1].name] {
+				text.WriteString(" ")
+			}
+			if c.name == "mo" {
+				op := c.content()
+				if before, after := mathOperatorSpace(op, isMathPrefix(elements, i)); before > 0 || after > 0 {
+					if before > 0 {
+						text.WriteString(" ")
+					}
+					text.WriteString(op + " ")
+					continue
+				}
+			}
+			text.WriteString(mathAltText(c))
+		}
+		return strings.TrimSpace(text.String())
+	}
+
+	switch n.name {
+	case "mi", "mn", "mo", "mtext", "ms":
+		return n.content()
+	case "msup", "mover":
+		return mathAltText(n.child(0)) + "^" + group(mathAltText(n.child(1)))
+	case "msub", "munder":
+		return mathAltText(n.child(0)) + "_" + group(mathAltText(n.child(1)))
+	case "msubsup", "munderover":
+		return mathAltText(n.child(0)) + "_" + group(mathAltText(n.child(1))) + "^" + group(mathAltText(n.child(2)))
+	case "mfrac":
+		return group(mathAltText(n.child(0))) + "/" + group(mathAltText(n.child(1)))
+	case "msqrt":
+		return "√" + group(row(n.elements()))
+	case "mroot":
+		return "root(" + mathAltText(n.child(0)) + ", " + mathAltText(n.child(1)) + ")"
+	case "mfenced":
+		return row(fencedRow(n))
+	case "mtable":
+		var rows []string
+		for _, tr := range n.elements() {
+			var cells []string
+			for _, td := range tr.elements() {
+				cells = append(cells, row(td.elements()))
+			}
+			rows = append(rows, strings.Join(cells, ", "))
+		}
+		return strings.Join(rows, "; ")
+	case "semantics", "maction":
+		return mathAltText(n.child(0))
+	case "annotation", "annotation-xml", "mphantom", "mspace":
+		return ""
+	}
+	return row(n.elements())
+}
@@ -20,13 +20,22 @@ func MergeMetadata(source, cli *model.Metadata) *model.Metadata {
 	if source != nil {
 		result.Title = source.Title
 		result.Authors = append(result.Authors, source.Authors...)
+		result.Contributors = append(result.Contributors, source.Contributors...)
 		result.Language = source.Language
 		result.Identifier = source.Identifier
 		result.Description = source.Description
 		result.Publisher = source.Publisher
 		result.Date = source.Date
 		result.Rights = source.Rights
+		result.License = source.License
 		result.CoverImage = source.CoverImage
+		result.Rendition = source.Rendition
+		result.Direction = source.Direction
+		result.Identifiers = append(result.Identifiers, source.Identifiers...)
+		result.UniqueID = source.UniqueID
+		result.Properties = append(result.Properties, source.Properties...)
+		result.Refinements = source.Refinements
+		result.CalibreColumns = append(result.CalibreColumns, source.CalibreColumns...)
 	}
 
 	// Override with CLI values if provided
@@ -18,23 +18,28 @@ import (
 // navTemplate is the template for nav.xhtml
 const navTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 <!DOCTYPE html>
-<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{.Language}}" lang="{{.Language}}">
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{.Language}}" lang="{{.Language}}"{{if .Direction}} dir="{{.Direction}}"{{end}}>
 <head>
   <meta charset="UTF-8"/>
   <title>{{.Title}}</title>
-  <link rel="stylesheet" type="text/css" href="styles/default.css"/>
+{{- if .Stylesheet}}
+  <link rel="stylesheet" type="text/css" href="{{.Stylesheet}}"/>
+{{- end}}
 </head>
 <body>
   <nav epub:type="toc" id="toc">
-    <h1>Table of Contents</h1>
+    <h1>{{.Strings.TableOfContents}}</h1>
 {{.TOCList}}
   </nav>
   <nav epub:type="landmarks" id="landmarks" hidden="">
-    <h2>Landmarks</h2>
+    <h2>{{.Strings.Landmarks}}</h2>
     <ol>
-      <li><a epub:type="toc" href="nav.xhtml">Table of Contents</a></li>
+      <li><a epub:type="toc" href="nav.xhtml">{{.Strings.TableOfContents}}</a></li>
 {{- if .HasContent}}
-      <li><a epub:type="bodymatter" href="{{.FirstChapterHref}}">Start of Content</a></li>
+      <li><a epub:type="bodymatter" href="{{.FirstChapterHref}}">{{.Strings.StartOfContent}}</a></li>
+{{- end}}
+{{- range .Landmarks}}
+      <li><a epub:type="{{.Type}}" href="{{.Href}}">{{.Title}}</a></li>
 {{- end}}
     </ol>
   </nav>
@@ -45,22 +50,35 @@ const navTemplate = `<?xml version="1.0" encoding="UTF-8"?>
 type navData struct {
 	Language         string
 	Title            string
+	Direction        string
 	TOCList          string
 	HasContent       bool
 	FirstChapterHref string
+	Landmarks        []landmark
+	Strings          generatedStrings
+	Stylesheet       string // Built-in stylesheet href, empty when it is left out
 }
 
-// generateNavDocument generates the nav.xhtml file content.
-func generateNavDocument(doc *model.Document) (string, error) {
-	tmpl, err := template.New("nav").Parse(navTemplate)
-	if err != nil {
-		return "", err
-	}
+// landmark is a landmarks navigation entry for a generated chapter.
+type landmark struct {
+	Type  string // Landmark epub:type (e.g., "index")
+	Href  string // EPUB path of the target document
+	Title string // Display text
+}
 
-	tocList := renderTOCList(doc.TOC.Entries)
+// generateNavDocument generates the nav.xhtml file content.
+func generateNavDocument(tmpl *template.Template, doc *model.Document, entries []model.TOCEntry, landmarks []landmark, strs generatedStrings, opts Options) (string, error) {
+	tocList := renderTOCList(entries, "nav.xhtml")
 
+	// Start of content is the first body matter chapter
 	var firstChapter string
-	if len(doc.Chapters) > 0 {
+	for _, chapter := range doc.Chapters {
+		if isBodyMatter(chapter) {
+			firstChapter = chapter.FileName
+			break
+		}
+	}
+	if firstChapter == "" && len(doc.Chapters) > 0 {
 		firstChapter = doc.Chapters[0].FileName
 	}
 
@@ -68,9 +86,15 @@ func generateNavDocument(doc *model.Document) (string, error) {
 	data := navData{
 		Language:         html.EscapeString(doc.Metadata.Language),
 		Title:            html.EscapeString(doc.Metadata.Title),
+		Direction:        html.EscapeString(doc.Metadata.Direction),
 		TOCList:          tocList,
 		HasContent:       len(doc.Chapters) > 0,
 		FirstChapterHref: firstChapter,
+		Landmarks:        escapeLandmarks(landmarks),
+		Strings:          escapeGeneratedStrings(strs),
+	}
+	if opts.defaultCSS() {
+		data.Stylesheet = defaultStylesheet
 	}
 
 	var buf bytes.Buffer
@@ -81,8 +105,38 @@ func generateNavDocument(doc *model.Document) (string, error) {
 	return buf.String(), nil
 }
 
-// renderTOCList renders the TOC entries as nested ordered lists.
-func renderTOCList(entries []model.TOCEntry) string {
+// escapeLandmarks escapes landmark titles and hrefs for XML safety.
+func escapeLandmarks(landmarks []landmark) []landmark {
+	escaped := make([]landmark, 0, len(landmarks))
+	for _, l := range landmarks {
+		escaped = append(escaped, landmark{
+			Type:  l.Type,
+			Href:  html.EscapeString(l.Href),
+			Title: html.EscapeString(l.Title),
+		})
+	}
+	return escaped
+}
+
+// escapeGeneratedStrings escapes generated strings for XML safety.
+func escapeGeneratedStrings(s generatedStrings) generatedStrings {
+	return generatedStrings{
+		TableOfContents: html.EscapeString(s.TableOfContents),
+		Landmarks:       html.EscapeString(s.Landmarks),
+		StartOfContent:  html.EscapeString(s.StartOfContent),
+		Colophon:        html.EscapeString(s.Colophon),
+		Index:           html.EscapeString(s.Index),
+		Bibliography:    html.EscapeString(s.Bibliography),
+		Glossary:        html.EscapeString(s.Glossary),
+		Figure:          html.EscapeString(s.Figure),
+		Table:           html.EscapeString(s.Table),
+		Notes:           html.EscapeString(s.Notes),
+	}
+}
+
+// renderTOCList renders the TOC entries as nested ordered lists, with hrefs
+// relative to the document at from.
+func renderTOCList(entries []model.TOCEntry, from string) string {
 	if len(entries) == 0 {
 		return "    <ol></ol>"
 	}
@@ -90,14 +144,14 @@ func renderTOCList(entries []model.TOCEntry) string {
 	var buf bytes.Buffer
 	buf.WriteString("    <ol>\n")
 	for _, entry := range entries {
-		renderTOCEntry(&buf, entry, 3)
+		renderTOCEntry(&buf, entry, from, 3)
 	}
 	buf.WriteString("    </ol>")
 	return buf.String()
 }
 
 // renderTOCEntry renders a single TOC entry with its children.
-func renderTOCEntry(buf *bytes.Buffer, entry model.TOCEntry, indent int) {
+func renderTOCEntry(buf *bytes.Buffer, entry model.TOCEntry, from string, indent int) {
 	indentStr := spaces(indent)
 
 	// Escape HTML in title for XML safety
@@ -107,7 +161,7 @@ func renderTOCEntry(buf *bytes.Buffer, entry model.TOCEntry, indent int) {
 	buf.WriteString("<li>\n")
 	buf.WriteString(indentStr)
 	buf.WriteString("  <a href=\"")
-	buf.WriteString(entry.Href)
+	buf.WriteString(html.EscapeString(relativeHref(from, entry.Href)))
 	buf.WriteString("\">")
 	buf.WriteString(escapedTitle)
 	buf.WriteString("</a>\n")
@@ -116,7 +170,7 @@ func renderTOCEntry(buf *bytes.Buffer, entry model.TOCEntry, indent int) {
 		buf.WriteString(indentStr)
 		buf.WriteString("  <ol>\n")
 		for _, child := range entry.Children {
-			renderTOCEntry(buf, child, indent+2)
+			renderTOCEntry(buf, child, from, indent+2)
 		}
 		buf.WriteString(indentStr)
 		buf.WriteString("  </ol>\n")
@@ -0,0 +1,113 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ncxFile is the path of the EPUB 2 navigation file within OEBPS.
+const ncxFile = "toc.ncx"
+
+// ncxDocument is the root element of an NCX navigation file.
+type ncxDocument struct {
+	XMLName xml.Name   `xml:"ncx"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Version string     `xml:"version,attr"`
+	Meta    []ncxMeta  `xml:"head>meta"`
+	Title   string     `xml:"docTitle>text"`
+	NavMap  []ncxPoint `xml:"navMap>navPoint"`
+}
+
+// ncxMeta is a head metadata entry.
+type ncxMeta struct {
+	Name    string `xml:"name,attr"`
+	Content string `xml:"content,attr"`
+}
+
+// ncxPoint is a navigation point with nested children.
+type ncxPoint struct {
+	ID        string     `xml:"id,attr"`
+	PlayOrder int        `xml:"playOrder,attr"`
+	Label     string     `xml:"navLabel>text"`
+	Content   ncxContent `xml:"content"`
+	Children  []ncxPoint `xml:"navPoint"`
+}
+
+// ncxContent is the target of a navigation point.
+type ncxContent struct {
+	Src string `xml:"src,attr"`
+}
+
+// generateNCX generates a toc.ncx file from the navigation entries, for
+// reading systems that predate the EPUB 3 navigation document.
+func generateNCX(doc *model.Document, entries []model.TOCEntry) (string, error) {
+	order := 0
+	var points func(entries []model.TOCEntry) []ncxPoint
+	points = func(entries []model.TOCEntry) []ncxPoint {
+		result := make([]ncxPoint, 0, len(entries))
+		for _, entry := range entries {
+			order++
+			point := ncxPoint{
+				ID:        fmt.Sprintf("navpoint-%d", order),
+				PlayOrder: order,
+				Label:     entry.Title,
+				Content:   ncxContent{Src: entry.Href},
+			}
+			point.Children = points(entry.Children)
+			result = append(result, point)
+		}
+		return result
+	}
+
+	navMap := points(entries)
+	if len(navMap) == 0 {
+		// The navMap requires at least one point
+		for _, chapter := range doc.Chapters {
+			order++
+			navMap = append(navMap, ncxPoint{
+				ID:        fmt.Sprintf("navpoint-%d", order),
+				PlayOrder: order,
+				Label:     chapter.Title,
+				Content:   ncxContent{Src: chapter.FileName},
+			})
+		}
+	}
+
+	ncx := ncxDocument{
+		Xmlns:   "http://www.daisy.org/z3986/2005/ncx/",
+		Version: "2005-1",
+		Meta: []ncxMeta{
+			{Name: "dtb:uid", Content: doc.Metadata.Identifier},
+			{Name: "dtb:depth", Content: strconv.Itoa(max(ncxDepth(entries), 1))},
+			{Name: "dtb:totalPageCount", Content: "0"},
+			{Name: "dtb:maxPageNumber", Content: "0"},
+		},
+		Title:  doc.Metadata.Title,
+		NavMap: navMap,
+	}
+
+	out, err := xml.MarshalIndent(ncx, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out), nil
+}
+
+// ncxDepth returns the nesting depth of the navigation entries.
+func ncxDepth(entries []model.TOCEntry) int {
+	depth := 0
+	for _, entry := range entries {
+		depth = max(depth, 1+ncxDepth(entry.Children))
+	}
+	return depth
+}
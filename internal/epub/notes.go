@@ -0,0 +1,134 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// notesFileName is the EPUB path of the generated notes chapter.
+const notesFileName = "content/notes.xhtml"
+
+// Patterns used to gather endnotes.
+var (
+	endnotesSectionRe = regexp.MustCompile(`(?s)\s*<section\b[^>]*\bepub:type=["']endnotes["'][^>]*>(.*?)</section>\s*`)
+	ruleRe            = regexp.MustCompile(`\s*<hr\b[^>]*>\s*`)
+)
+
+// chapterNotes are the endnotes gathered from one chapter.
+type chapterNotes struct {
+	title string
+	notes string
+}
+
+// gatherNotes moves the endnotes listed at the end of each chapter into a
+// notes chapter at the end of the book, under a heading for each chapter.
+// Note ids are prefixed with the chapter id so they stay unique, and note
+// references and backlinks are pointed across.
+func (b *Builder) gatherNotes(doc *model.Document) {
+	if b.opts.Notes != model.NotesBook {
+		return
+	}
+
+	var gathered []chapterNotes
+	moved := make(map[string]string) // Note targets relative to OEBPS to their new ids
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		var notes strings.Builder
+		chapter.Content = endnotesSectionRe.ReplaceAllStringFunc(chapter.Content, func(section string) string {
+			notes.WriteString(ruleRe.ReplaceAllString(endnotesSectionRe.FindStringSubmatch(section)[1], ""))
+			return "\n"
+		})
+		if notes.Len() == 0 {
+			continue
+		}
+
+		content := idAttrsRe.ReplaceAllStringFunc(notes.String(), func(attr string) string {
+			m := idAttrsRe.FindStringSubmatch(attr)
+			id := html.UnescapeString(m[1] + m[2])
+			moved[chapter.FileName+"#"+id] = chapter.ID + "-" + id
+			return ` id="` + html.EscapeString(chapter.ID+"-"+id) + `"`
+		})
+		// Links in the notes keep pointing where they did from the chapter
+		gathered = append(gathered, chapterNotes{
+			title: chapter.Title,
+			notes: retargetLinks(content, chapter.FileName, notesFileName, moved),
+		})
+	}
+	if len(gathered) == 0 {
+		return
+	}
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = retargetLinks(chapter.Content, chapter.FileName, chapter.FileName, moved)
+	}
+
+	title := b.localizedStrings().Notes
+	doc.AddChapter(model.Chapter{
+		ID:       "notes",
+		Title:    title,
+		Level:    1,
+		Content:  renderNotes(gathered, title),
+		FileName: notesFileName,
+		Order:    len(doc.Chapters),
+		Type:     "backmatter",
+	})
+	doc.TOC.AddEntry(model.TOCEntry{Title: title, Href: notesFileName, Level: 1})
+}
+
+// retargetLinks rewrites the links of content, written for the document
+// at from, for the document at to. Links to moved notes point to their
+// place in the notes chapter; other links are only rewritten when the
+// content moves to another document.
+func retargetLinks(content, from, to string, moved map[string]string) string {
+	return linkAttrRe.ReplaceAllStringFunc(content, func(attr string) string {
+		m := linkAttrRe.FindStringSubmatch(attr)
+		value := m[1] + m[2]
+		target, ok := linkTarget(from, html.UnescapeString(value))
+		if !ok {
+			return attr
+		}
+
+		file, fragment, _ := strings.Cut(target, "#")
+		if id, ok := moved[target]; ok {
+			file, fragment = notesFileName, id
+		} else if from == to {
+			return attr
+		}
+
+		href := "#" + fragment
+		if file != to {
+			href = relativeHref(to, file)
+			if fragment != "" {
+				href += "#" + fragment
+			}
+		}
+		return strings.Replace(attr, value, html.EscapeString(href), 1)
+	})
+}
+
+// renderNotes renders the gathered endnotes under a heading for each
+// chapter.
+func renderNotes(gathered []chapterNotes, title string) string {
+	var buf strings.Builder
+	buf.WriteString("<section epub:type=\"endnotes\" role=\"doc-endnotes\">\n  <h1>" + html.EscapeString(title) + "</h1>\n")
+	for _, c := range gathered {
+		buf.WriteString("  <section>\n")
+		if c.title != "" {
+			buf.WriteString("    <h2>" + html.EscapeString(c.title) + "</h2>\n")
+		}
+		buf.WriteString(strings.TrimSpace(c.notes) + "\n  </section>\n")
+	}
+	buf.WriteString("</section>")
+	return buf.String()
+}
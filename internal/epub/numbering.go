@@ -0,0 +1,101 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns used for section numbering.
+var (
+	headingStartRe = regexp.MustCompile(`(?i)<h([1-6])\b([^>]*)>`)
+	unnumberedRe   = regexp.MustCompile(`(?i)\bclass\s*=\s*["'][^"']*\bunnumbered\b`)
+)
+
+// numberSections prefixes body matter headings with hierarchical section
+// numbers (1, 1.1, 1.1.2), counted across the whole book, and applies the
+// same numbers to the matching table of contents entries. The highest
+// heading level used in the book is numbered as the top level. Headings
+// with the "unnumbered" class are skipped.
+func (b *Builder) numberSections(doc *model.Document) {
+	base := 7
+	for _, chapter := range doc.Chapters {
+		if !isBodyMatter(chapter) {
+			continue
+		}
+		for _, m := range headingStartRe.FindAllStringSubmatch(chapter.Content, -1) {
+			if level, _ := strconv.Atoi(m[1]); level < base {
+				base = level
+			}
+		}
+	}
+	if base == 7 {
+		return
+	}
+
+	var counters [6]int
+	numbers := make(map[string]string) // TOC href -> section number
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if !isBodyMatter(*chapter) {
+			continue
+		}
+
+		chapter.Content = headingStartRe.ReplaceAllStringFunc(chapter.Content, func(start string) string {
+			m := headingStartRe.FindStringSubmatch(start)
+			attrs := m[2]
+			if unnumberedRe.MatchString(attrs) {
+				return start
+			}
+
+			level, _ := strconv.Atoi(m[1])
+			depth := level - base
+			counters[depth]++
+			for d := depth + 1; d < len(counters); d++ {
+				counters[d] = 0
+			}
+
+			parts := make([]string, 0, depth+1)
+			for d := 0; d <= depth; d++ {
+				parts = append(parts, strconv.Itoa(counters[d]))
+			}
+			number := strings.Join(parts, ".")
+
+			if _, ok := numbers[chapter.FileName]; !ok {
+				numbers[chapter.FileName] = number
+			}
+			if id := attrValue(idAttrRe, attrs); id != "" {
+				numbers[chapter.FileName+"#"+id] = number
+			}
+			return start + `<span class="section-number">` + number + "</span> "
+		})
+	}
+
+	numberTOCEntries(doc.TOC.Entries, numbers)
+}
+
+// numberTOCEntries prefixes TOC entry titles with the section number of the
+// heading they link to.
+func numberTOCEntries(entries []model.TOCEntry, numbers map[string]string) {
+	for i := range entries {
+		if number, ok := numbers[entries[i].Href]; ok {
+			entries[i].Title = number + " " + entries[i].Title
+		}
+		numberTOCEntries(entries[i].Children, numbers)
+	}
+}
+
+// isBodyMatter reports whether a chapter is part of the main body.
+func isBodyMatter(chapter model.Chapter) bool {
+	return chapter.Matter() == model.BodyMatter
+}
@@ -0,0 +1,144 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns used to locate chapter openings.
+var (
+	paragraphStartRe = regexp.MustCompile(`(?i)<p\b[^>]*>`)
+	classAttrRe      = regexp.MustCompile(`(?i)(\sclass\s*=\s*["'])`)
+)
+
+// dropCapCSS styles the drop-cap span added to chapter openings.
+const dropCapCSS = `
+/* Drop caps */
+.drop-cap {
+  float: left;
+  font-size: 3.2em;
+  line-height: 0.85;
+  margin: 0.05em 0.08em 0 0;
+  font-weight: bold;
+}
+`
+
+// applyChapterOpeners marks the first heading of each body chapter with the
+// "chapter-opener" class and, when drop caps are enabled, wraps the first
+// letter of the chapter's first paragraph in a "drop-cap" span.
+func (b *Builder) applyChapterOpeners(doc *model.Document) {
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if !isBodyMatter(*chapter) {
+			continue
+		}
+		if b.opts.ChapterSpacing != "" {
+			chapter.Content = markChapterOpener(chapter.Content)
+		}
+		if b.opts.DropCaps {
+			chapter.Content = addDropCap(chapter.Content)
+		}
+	}
+}
+
+// markChapterOpener adds the "chapter-opener" class to the first heading.
+func markChapterOpener(content string) string {
+	loc := headingStartRe.FindStringIndex(content)
+	if loc == nil {
+		return content
+	}
+	return content[:loc[0]] + addClass(content[loc[0]:loc[1]], "chapter-opener") + content[loc[1]:]
+}
+
+// addDropCap wraps the first letter of the first paragraph, together with
+// any opening punctuation such as quotation marks, in a drop-cap span.
+func addDropCap(content string) string {
+	loc := paragraphStartRe.FindStringIndex(content)
+	if loc == nil {
+		return content
+	}
+
+	start := firstTextIndex(content, loc[1])
+	if start < 0 {
+		return content
+	}
+
+	end := start
+	for end < len(content) {
+		if content[end] == '&' {
+			// Character references count as punctuation
+			semi := strings.IndexByte(content[end:], ';')
+			if semi < 0 {
+				return content
+			}
+			end += semi + 1
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(content[end:])
+		if unicode.IsPunct(r) {
+			end += size
+			continue
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return content
+		}
+		end += size
+		return content[:start] + `<span class="drop-cap">` + content[start:end] + "</span>" + content[end:]
+	}
+	return content
+}
+
+// firstTextIndex returns the index of the first text character at or after
+// from, skipping tags and whitespace, or -1 if there is none.
+func firstTextIndex(content string, from int) int {
+	for i := from; i < len(content); {
+		switch content[i] {
+		case '<':
+			end := strings.IndexByte(content[i:], '>')
+			if end < 0 {
+				return -1
+			}
+			i += end + 1
+		case ' ', '\n', '\t', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return -1
+}
+
+// addClass adds a class to an element start tag.
+func addClass(start, class string) string {
+	if classAttrRe.MatchString(start) {
+		return classAttrRe.ReplaceAllString(start, "${1}"+class+" ")
+	}
+	if strings.HasSuffix(start, "/>") {
+		return strings.TrimSuffix(start, "/>") + ` class="` + class + `"/>`
+	}
+	return strings.TrimSuffix(start, ">") + ` class="` + class + `">`
+}
+
+// chapterOpenerCSS returns the stylesheet rules for the chapter opening
+// options, or an empty string when none are enabled.
+func (b *Builder) chapterOpenerCSS() string {
+	var css strings.Builder
+	if b.opts.ChapterSpacing != "" {
+		css.WriteString("\n/* Chapter openings */\n.chapter-opener {\n  margin-top: " + b.opts.ChapterSpacing + ";\n}\n")
+	}
+	if b.opts.DropCaps {
+		css.WriteString(dropCapCSS)
+	}
+	return css.String()
+}
@@ -0,0 +1,290 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"encoding/xml"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Package document namespaces.
+const (
+	opfNamespace = "http://www.idpf.org/2007/opf"
+	dcNamespace  = "http://purl.org/dc/elements/1.1/"
+)
+
+// ccPrefix declares the Creative Commons vocabulary used by cc:license links.
+const ccPrefix = "cc: http://creativecommons.org/ns#"
+
+// opfPackage is the root element of the package document.
+type opfPackage struct {
+	XMLName          xml.Name    `xml:"package"`
+	Xmlns            string      `xml:"xmlns,attr"`
+	Version          string      `xml:"version,attr"`
+	UniqueIdentifier string      `xml:"unique-identifier,attr"`
+	Prefix           string      `xml:"prefix,attr,omitempty"`
+	Metadata         opfMetadata `xml:"metadata"`
+	Manifest         opfManifest `xml:"manifest"`
+	Spine            opfSpine    `xml:"spine"`
+}
+
+// opfMetadata holds Dublin Core elements and meta refinements in order.
+type opfMetadata struct {
+	XmlnsDC  string `xml:"xmlns:dc,attr"`
+	Elements []opfElement
+}
+
+// opfElement is a dc:* element or a meta element.
+type opfElement struct {
+	XMLName  xml.Name
+	ID       string `xml:"id,attr,omitempty"`
+	Refines  string `xml:"refines,attr,omitempty"`
+	Property string `xml:"property,attr,omitempty"`
+	Scheme   string `xml:"scheme,attr,omitempty"`
+	Name     string `xml:"name,attr,omitempty"`
+	Content  string `xml:"content,attr,omitempty"`
+	Rel      string `xml:"rel,attr,omitempty"`
+	Href     string `xml:"href,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
+
+// opfManifest lists all publication resources.
+type opfManifest struct {
+	Items []opfItem `xml:"item"`
+}
+
+// opfItem is a single manifest item.
+type opfItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Fallback   string `xml:"fallback,attr,omitempty"`
+	Properties string `xml:"properties,attr,omitempty"`
+}
+
+// opfSpine defines the default reading order.
+type opfSpine struct {
+	TOC                      string       `xml:"toc,attr,omitempty"`
+	PageProgressionDirection string       `xml:"page-progression-direction,attr,omitempty"`
+	ItemRefs                 []opfItemRef `xml:"itemref"`
+}
+
+// opfItemRef is a single spine itemref.
+type opfItemRef struct {
+	IDRef      string `xml:"idref,attr"`
+	Properties string `xml:"properties,attr,omitempty"`
+}
+
+// dcElement creates a Dublin Core element.
+func dcElement(name, id, value string) opfElement {
+	return opfElement{XMLName: xml.Name{Local: "dc:" + name}, ID: id, Value: value}
+}
+
+// metaElement creates a meta element, optionally refining another element.
+func metaElement(refines, property, scheme, value string) opfElement {
+	return opfElement{
+		XMLName:  xml.Name{Local: "meta"},
+		Refines:  refines,
+		Property: property,
+		Scheme:   scheme,
+		Value:    value,
+	}
+}
+
+// generatePackageDocument generates the content.opf file content.
+// The document is marshaled with encoding/xml so that arbitrary metadata
+// text is always escaped into well-formed XML.
+func generatePackageDocument(doc *model.Document, opts Options) (string, error) {
+	pkg := opfPackage{
+		Xmlns:            opfNamespace,
+		Version:          "3.0",
+		UniqueIdentifier: "uid",
+		Metadata:         buildOPFMetadata(&doc.Metadata),
+		Prefix:           packagePrefix(&doc.Metadata),
+		Manifest:         buildOPFManifest(doc, opts),
+		Spine: opfSpine{
+			PageProgressionDirection: doc.Metadata.Direction,
+		},
+	}
+
+	for _, item := range buildSpine(doc) {
+		pkg.Spine.ItemRefs = append(pkg.Spine.ItemRefs, opfItemRef{IDRef: item.ID, Properties: item.Properties})
+	}
+
+	// EPUB 2 fallbacks: the NCX and a cover meta read by older systems
+	if opts.legacy() {
+		pkg.Manifest.Items = append(pkg.Manifest.Items, opfItem{ID: "ncx", Href: ncxFile, MediaType: "application/x-dtbncx+xml"})
+		pkg.Spine.TOC = "ncx"
+		if id := coverID(doc); id != "" {
+			pkg.Metadata.Elements = append(pkg.Metadata.Elements, opfElement{
+				XMLName: xml.Name{Local: "meta"},
+				Name:    "cover",
+				Content: id,
+			})
+		}
+	}
+
+	out, err := xml.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(out), nil
+}
+
+// buildOPFMetadata creates the package metadata elements.
+func buildOPFMetadata(meta *model.Metadata) opfMetadata {
+	m := opfMetadata{XmlnsDC: dcNamespace}
+
+	for _, id := range buildIdentifiers(meta) {
+		m.Elements = append(m.Elements, dcElement("identifier", id.ID, id.Value))
+		if id.Type != "" {
+			m.Elements = append(m.Elements, metaElement("#"+id.ID, "identifier-type", id.TypeScheme, id.Type))
+		}
+	}
+
+	m.Elements = append(m.Elements,
+		dcElement("title", "", meta.Title),
+		dcElement("language", "", meta.Language),
+	)
+	for _, lang := range meta.Languages {
+		m.Elements = append(m.Elements, dcElement("language", "", lang))
+	}
+
+	for _, c := range buildCreators(meta) {
+		m.Elements = append(m.Elements,
+			dcElement(c.Element, c.ID, c.Name),
+			metaElement("#"+c.ID, "role", "marc:relators", c.Role),
+		)
+		m.Elements = append(m.Elements, refinementElements(c.ID, c.Refinements)...)
+	}
+
+	optional := []struct{ name, value string }{
+		{"description", meta.Description},
+		{"publisher", meta.Publisher},
+		{"rights", meta.Rights},
+	}
+	for _, o := range optional {
+		if o.value != "" {
+			m.Elements = append(m.Elements, dcElement(o.name, "", o.value))
+		}
+	}
+	for _, subject := range meta.Subjects {
+		m.Elements = append(m.Elements, dcElement("subject", "", subject))
+	}
+
+	for _, c := range buildCollections(meta) {
+		collection := metaElement("", "belongs-to-collection", "", c.Name)
+		collection.ID = c.ID
+		m.Elements = append(m.Elements, collection)
+		if c.Type != "" {
+			m.Elements = append(m.Elements, metaElement("#"+c.ID, "collection-type", "", c.Type))
+		}
+		if c.Position != "" {
+			m.Elements = append(m.Elements, metaElement("#"+c.ID, "group-position", "", c.Position))
+		}
+	}
+
+	if license, ok := meta.LicenseInfo(); ok && license.URL != "" {
+		m.Elements = append(m.Elements, opfElement{XMLName: xml.Name{Local: "link"}, Rel: "cc:license", Href: license.URL})
+	}
+
+	for _, c := range buildCalibreMeta(meta) {
+		m.Elements = append(m.Elements, opfElement{XMLName: xml.Name{Local: "meta"}, Name: c.Name, Content: c.Content})
+	}
+
+	for _, p := range buildProperties(meta) {
+		element := metaElement("", p.Property, "", p.Value)
+		element.ID = p.ID
+		if p.Element != "" {
+			element = dcElement(p.Element, p.ID, p.Value)
+		}
+		m.Elements = append(m.Elements, element)
+		m.Elements = append(m.Elements, refinementElements(p.ID, p.Refinements)...)
+	}
+
+	m.Elements = append(m.Elements,
+		dcElement("date", "", meta.Date.Format("2006-01-02")),
+		metaElement("", "dcterms:modified", "", modifiedTimestamp()),
+	)
+
+	if rendition := meta.Rendition; rendition.FixedLayout() {
+		renditionMeta := []struct{ property, value string }{
+			{"rendition:layout", rendition.Layout},
+			{"rendition:orientation", rendition.Orientation},
+			{"rendition:spread", rendition.Spread},
+		}
+		for _, r := range renditionMeta {
+			if r.value != "" {
+				m.Elements = append(m.Elements, metaElement("", r.property, "", r.value))
+			}
+		}
+	}
+
+	return m
+}
+
+// refinementElements creates the meta elements refining the element with
+// the given id.
+func refinementElements(id string, refinements []model.Refinement) []opfElement {
+	elements := make([]opfElement, 0, len(refinements))
+	for _, r := range refinements {
+		elements = append(elements, metaElement("#"+id, r.Property, "", r.Value))
+	}
+	return elements
+}
+
+// packagePrefix returns the prefix attribute of the package, declaring the
+// vocabularies used beyond the reserved ones.
+func packagePrefix(meta *model.Metadata) string {
+	if license, ok := meta.LicenseInfo(); ok && license.URL != "" {
+		return ccPrefix
+	}
+	return ""
+}
+
+// buildOPFManifest creates the manifest items for navigation, stylesheet,
+// chapters, and resources.
+func buildOPFManifest(doc *model.Document, opts Options) opfManifest {
+	items := []opfItem{
+		{ID: "nav", Href: "nav.xhtml", MediaType: "application/xhtml+xml", Properties: "nav"},
+	}
+	if opts.defaultCSS() {
+		items = append(items, opfItem{ID: "css", Href: defaultStylesheet, MediaType: "text/css"})
+	}
+
+	for _, chapter := range buildChapterItems(doc.Chapters) {
+		items = append(items, opfItem{
+			ID:         chapter.ID,
+			Href:       chapter.FileName,
+			MediaType:  "application/xhtml+xml",
+			Properties: chapter.Properties,
+		})
+	}
+
+	for _, res := range doc.Resources {
+		item := opfItem{ID: res.ID, Href: res.FileName, MediaType: res.MediaType, Fallback: res.Fallback}
+		if res.IsCover {
+			item.Properties = "cover-image"
+		}
+		items = append(items, item)
+	}
+
+	return opfManifest{Items: items}
+}
+
+// coverID returns the manifest id of the cover image, or "" if none.
+func coverID(doc *model.Document) string {
+	for _, res := range doc.Resources {
+		if res.IsCover {
+			return res.ID
+		}
+	}
+	return ""
+}
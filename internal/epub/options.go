@@ -0,0 +1,96 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Supported EPUB versions. Both write version="3.0" on the package element,
+// as EPUB 3.3 requires; they differ in the legacy fallbacks included.
+const (
+	Version30 = "3.0" // Adds toc.ncx and a cover meta for older reading systems
+	Version33 = "3.3" // EPUB 3.3 without EPUB 2 fallbacks
+)
+
+// Options configures EPUB generation.
+type Options struct {
+	NavTitle       string // Overrides the localized table of contents heading
+	TOCDepth       int    // Maximum nesting depth of the navigation TOC (0 = unlimited)
+	TOCPage        bool   // Generate a visible contents page at the start of the book
+	NumberSections bool   // Prefix body headings and TOC entries with section numbers
+	DropCaps       bool   // Wrap the first letter of each body chapter in a drop cap
+	ChapterSpacing string // CSS length added above each chapter's first heading (e.g., "20vh")
+	PageBreaks     bool   // Mark explicit page breaks with numbered epub:type="pagebreak" anchors
+	Version        string // Target EPUB version: Version33 (default) or Version30
+	DefaultCSS     string // Placement of styles/default.css: DefaultCSSFirst (default), DefaultCSSLast, or DefaultCSSNone
+	CodeTheme      string // Code highlighting colors in styles/default.css: CodeThemeLight (default), CodeThemeDark, or CodeThemeNone
+	Math           string // Equation output: MathMathML (default), MathSVG, or MathBoth
+	Strict         bool   // Fail on malformed content documents and broken links instead of warning
+	CheckA11y      bool   // Audit generated documents for accessibility problems, reported through Warn
+	Workers        int    // Content documents rendered and compressed at once (0 = GOMAXPROCS)
+	Notes          string // Footnote placement; model.NotesBook gathers chapter endnotes into a notes chapter
+	Profile        string // Reader profile whose quirks are applied: ProfileKindle, ProfileKobo, ProfileApple, or ProfileGeneric
+
+	// Chapters, if set, reuses content documents from earlier builds
+	Chapters *ChapterCache
+
+	// Progress, if set, is called after each content document is written
+	Progress func(current, total int)
+
+	// Warn, if set, receives problems found in the generated documents
+	Warn func(event model.Warning)
+}
+
+// Placements of the built-in stylesheet relative to chapter stylesheets.
+// Stylesheets linked later take precedence for rules of equal specificity.
+const (
+	DefaultCSSFirst = "first" // Chapter stylesheets override the built-in rules
+	DefaultCSSLast  = "last"  // Built-in rules override chapter stylesheets
+	DefaultCSSNone  = "none"  // No built-in stylesheet is written or linked
+)
+
+// ParseDefaultCSS validates a built-in stylesheet placement, returning the
+// default placement for an empty string.
+func ParseDefaultCSS(s string) (string, error) {
+	switch s {
+	case "", DefaultCSSFirst:
+		return DefaultCSSFirst, nil
+	case DefaultCSSLast, DefaultCSSNone:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown default stylesheet placement %q", s)
+	}
+}
+
+// defaultCSS reports whether the built-in stylesheet is included.
+func (o Options) defaultCSS() bool {
+	return o.DefaultCSS != DefaultCSSNone
+}
+
+// ParseVersion validates an EPUB version name, returning the default
+// version for an empty string.
+func ParseVersion(v string) (string, error) {
+	switch v {
+	case "", "3", Version33:
+		return Version33, nil
+	case Version30:
+		return Version30, nil
+	case "2", "2.0", "2.0.1":
+		return "", fmt.Errorf("%w: EPUB %s output is not supported", ErrUnsupportedVersion, v)
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedVersion, v)
+	}
+}
+
+// legacy reports whether EPUB 2 fallbacks are included.
+func (o Options) legacy() bool {
+	return o.Version == Version30
+}
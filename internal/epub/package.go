@@ -9,95 +9,167 @@ package epub
 
 import (
 	"bytes"
+	"fmt"
 	"html"
+	"regexp"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
-// packageTemplate is the template for content.opf
-const packageTemplate = `<?xml version="1.0" encoding="UTF-8"?>
-<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">
-  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
-    <dc:identifier id="uid">{{.Identifier}}</dc:identifier>
-    <dc:title>{{.Title}}</dc:title>
-    <dc:language>{{.Language}}</dc:language>
-{{- range .Authors}}
-    <dc:creator>{{.}}</dc:creator>
-{{- end}}
-{{- if .Description}}
-    <dc:description>{{.Description}}</dc:description>
-{{- end}}
-{{- if .Publisher}}
-    <dc:publisher>{{.Publisher}}</dc:publisher>
-{{- end}}
-{{- if .Rights}}
-    <dc:rights>{{.Rights}}</dc:rights>
-{{- end}}
-    <dc:date>{{.Date}}</dc:date>
-    <meta property="dcterms:modified">{{.Modified}}</meta>
-  </metadata>
-  <manifest>
-    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
-    <item id="css" href="styles/default.css" media-type="text/css"/>
-{{- range .Chapters}}
-    <item id="{{.ID}}" href="{{.FileName}}" media-type="application/xhtml+xml"/>
-{{- end}}
-{{- range .Resources}}
-    <item id="{{.ID}}" href="{{.FileName}}" media-type="{{.MediaType}}"{{if .IsCover}} properties="cover-image"{{end}}/>
-{{- end}}
-  </manifest>
-  <spine>
-{{- range .Chapters}}
-    <itemref idref="{{.ID}}"/>
-{{- end}}
-  </spine>
-</package>`
-
-// packageData holds data for the package template
+// packageData holds data for custom package templates (package.opf.tmpl).
+// All string fields are XML-escaped.
 type packageData struct {
-	Identifier  string
+	Identifiers []identifierItem
 	Title       string
 	Language    string
-	Authors     []string
+	Languages   []string // Further languages of a multilingual book
+	Creators    []creatorItem
 	Description string
 	Publisher   string
 	Rights      string
+	Prefix      string // Package prefix attribute value, e.g. declaring cc: for LicenseURL
+	LicenseURL  string // License deed for a <link rel="cc:license"/>, or "" for none
+	Subjects    []string
+	Collections []collectionItem
+	Properties  []propertyItem // Further DC elements and meta properties from the source metadata
+	Calibre     []calibreMeta  // Calibre series and custom column metas, written as <meta name content>
 	Date        string
 	Modified    string
-	Chapters    []model.Chapter
+	Layout      string
+	Orientation string
+	Spread      string
+	Direction   string
+	Chapters    []chapterItem
 	Resources   []model.Resource
+	Spine       []spineItem
+	NCX         bool   // EPUB 2 fallbacks requested: list toc.ncx and set spine toc="ncx"
+	CoverID     string // Manifest id of the cover image, for a legacy cover meta
+	DefaultCSS  bool   // The built-in styles/default.css is written and should be listed
 }
 
-// generatePackageDocument generates the content.opf file content.
-func generatePackageDocument(doc *model.Document) (string, error) {
-	tmpl, err := template.New("package").Parse(packageTemplate)
-	if err != nil {
-		return "", err
-	}
+// chapterItem is a chapter manifest item with its detected properties.
+type chapterItem struct {
+	model.Chapter
+	Properties string
+}
+
+// identifierItem is a dc:identifier with its identifier-type refinement.
+type identifierItem struct {
+	ID         string
+	Value      string
+	Type       string
+	TypeScheme string
+}
+
+// creatorItem is a dc:creator or dc:contributor with its role and other
+// refinements, such as file-as.
+type creatorItem struct {
+	Element     string
+	ID          string
+	Name        string
+	Role        string
+	Refinements []model.Refinement
+}
+
+// propertyItem is a further DC element or meta property with its
+// refinements. Element is the dc:* element name, or "" for a meta element;
+// ID is set only when there are refinements to point at it.
+type propertyItem struct {
+	Element     string
+	ID          string
+	Property    string
+	Value       string
+	Refinements []model.Refinement
+}
 
-	now := time.Now().UTC().Format("2006-01-02T15:04:05Z")
+// collectionItem is a belongs-to-collection meta with its refinements.
+type collectionItem struct {
+	ID       string
+	Name     string
+	Type     string
+	Position string
+}
+
+// spineItem is a single itemref in the package spine.
+type spineItem struct {
+	ID         string
+	Properties string
+}
+
+// generatePackageFromTemplate generates the content.opf file content from a
+// user-provided template.
+func generatePackageFromTemplate(tmpl *template.Template, doc *model.Document, opts Options) (string, error) {
+	now := modifiedTimestamp()
 	date := doc.Metadata.Date.Format("2006-01-02")
 
-	// Escape all user-provided strings for XML safety
-	escapedAuthors := make([]string, len(doc.Metadata.Authors))
-	for i, author := range doc.Metadata.Authors {
-		escapedAuthors[i] = html.EscapeString(author)
+	identifiers := buildIdentifiers(&doc.Metadata)
+	for i := range identifiers {
+		identifiers[i].Value = html.EscapeString(identifiers[i].Value)
+		identifiers[i].Type = html.EscapeString(identifiers[i].Type)
+	}
+
+	creators := buildCreators(&doc.Metadata)
+	for i := range creators {
+		creators[i].Name = html.EscapeString(creators[i].Name)
+		creators[i].Role = html.EscapeString(creators[i].Role)
+		creators[i].Refinements = escapeRefinements(creators[i].Refinements)
+	}
+
+	properties := buildProperties(&doc.Metadata)
+	for i := range properties {
+		properties[i].Property = html.EscapeString(properties[i].Property)
+		properties[i].Value = html.EscapeString(properties[i].Value)
+		properties[i].Refinements = escapeRefinements(properties[i].Refinements)
+	}
+
+	collections := buildCollections(&doc.Metadata)
+	for i := range collections {
+		collections[i].Name = html.EscapeString(collections[i].Name)
+		collections[i].Position = html.EscapeString(collections[i].Position)
+	}
+
+	calibre := buildCalibreMeta(&doc.Metadata)
+	for i := range calibre {
+		calibre[i].Name = html.EscapeString(calibre[i].Name)
+		calibre[i].Content = html.EscapeString(calibre[i].Content)
 	}
 
 	data := packageData{
-		Identifier:  html.EscapeString(doc.Metadata.Identifier),
+		Identifiers: identifiers,
 		Title:       html.EscapeString(doc.Metadata.Title),
 		Language:    html.EscapeString(doc.Metadata.Language),
-		Authors:     escapedAuthors,
+		Languages:   escapeStrings(doc.Metadata.Languages),
+		Creators:    creators,
 		Description: html.EscapeString(doc.Metadata.Description),
 		Publisher:   html.EscapeString(doc.Metadata.Publisher),
 		Rights:      html.EscapeString(doc.Metadata.Rights),
+		Prefix:      html.EscapeString(packagePrefix(&doc.Metadata)),
+		Subjects:    escapeStrings(doc.Metadata.Subjects),
+		Collections: collections,
+		Properties:  properties,
+		Calibre:     calibre,
+		Direction:   html.EscapeString(doc.Metadata.Direction),
 		Date:        date,
 		Modified:    now,
-		Chapters:    doc.Chapters,
+		Chapters:    buildChapterItems(doc.Chapters),
 		Resources:   doc.Resources,
+		Spine:       buildSpine(doc),
+		NCX:         opts.legacy(),
+		CoverID:     html.EscapeString(coverID(doc)),
+		DefaultCSS:  opts.defaultCSS(),
+	}
+
+	if license, ok := doc.Metadata.LicenseInfo(); ok {
+		data.LicenseURL = html.EscapeString(license.URL)
+	}
+
+	if rendition := doc.Metadata.Rendition; rendition.FixedLayout() {
+		data.Layout = rendition.Layout
+		data.Orientation = html.EscapeString(rendition.Orientation)
+		data.Spread = html.EscapeString(rendition.Spread)
 	}
 
 	var buf bytes.Buffer
@@ -107,3 +179,219 @@ func generatePackageDocument(doc *model.Document) (string, error) {
 
 	return buf.String(), nil
 }
+
+// escapeStrings returns XML-escaped copies of values.
+func escapeStrings(values []string) []string {
+	escaped := make([]string, 0, len(values))
+	for _, v := range values {
+		escaped = append(escaped, html.EscapeString(v))
+	}
+	return escaped
+}
+
+// escapeRefinements returns XML-escaped copies of refinements.
+func escapeRefinements(refinements []model.Refinement) []model.Refinement {
+	escaped := make([]model.Refinement, 0, len(refinements))
+	for _, r := range refinements {
+		escaped = append(escaped, model.Refinement{Property: html.EscapeString(r.Property), Value: html.EscapeString(r.Value)})
+	}
+	return escaped
+}
+
+// modifiedTimestamp returns the dcterms:modified value for the current time.
+func modifiedTimestamp() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// buildSpine creates the spine itemrefs, including page-spread properties
+// for fixed-layout books.
+func buildSpine(doc *model.Document) []spineItem {
+	fixed := doc.Metadata.Rendition.FixedLayout()
+	items := make([]spineItem, 0, len(doc.Chapters))
+	for _, chapter := range doc.Chapters {
+		item := spineItem{ID: chapter.ID}
+		if fixed {
+			item.Properties = pageSpreadProperty(chapter.Spread)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// pageSpreadProperty maps a chapter spread value to its itemref property.
+func pageSpreadProperty(spread string) string {
+	switch spread {
+	case "left":
+		return "page-spread-left"
+	case "right":
+		return "page-spread-right"
+	case "center":
+		return "rendition:page-spread-center"
+	default:
+		return ""
+	}
+}
+
+// buildIdentifiers creates the dc:identifier entries, with the unique
+// identifier first using the "uid" id referenced by the package element.
+func buildIdentifiers(meta *model.Metadata) []identifierItem {
+	unique := newIdentifierItem("uid", model.Identifier{
+		Scheme: meta.UniqueIdentifierScheme(),
+		Value:  meta.Identifier,
+	})
+	items := []identifierItem{unique}
+
+	for i, id := range meta.OtherIdentifiers() {
+		items = append(items, newIdentifierItem(fmt.Sprintf("id-%d", i+2), id))
+	}
+	return items
+}
+
+// newIdentifierItem creates an identifier entry with an ONIX codelist 5
+// identifier-type for known schemes.
+func newIdentifierItem(id string, identifier model.Identifier) identifierItem {
+	item := identifierItem{
+		ID:    id,
+		Value: identifier.Value,
+	}
+
+	switch identifier.Scheme {
+	case "":
+		return item
+	case model.SchemeISBN:
+		item.TypeScheme = "onix:codelist5"
+		item.Type = "15" // ISBN-13
+		if len(isbnDigits(identifier.Value)) == 10 {
+			item.Type = "02" // ISBN-10
+		}
+	case model.SchemeDOI:
+		item.TypeScheme = "onix:codelist5"
+		item.Type = "06"
+	case model.SchemeUUID:
+		item.TypeScheme = "onix:codelist5"
+		item.Type = "22" // URN
+	default:
+		item.Type = identifier.Scheme
+	}
+	return item
+}
+
+// isbnDigits returns the digits (and check character X) of an ISBN.
+func isbnDigits(isbn string) string {
+	var digits strings.Builder
+	for _, r := range isbn {
+		if (r >= '0' && r <= '9') || r == 'X' || r == 'x' {
+			digits.WriteRune(r)
+		}
+	}
+	return digits.String()
+}
+
+// buildCreators creates dc:creator entries for authors and dc:contributor
+// entries for other contributors, each refined with a MARC relator role.
+func buildCreators(meta *model.Metadata) []creatorItem {
+	items := make([]creatorItem, 0, len(meta.Authors)+len(meta.Contributors))
+	for i, author := range meta.Authors {
+		items = append(items, creatorItem{
+			Element:     "creator",
+			ID:          fmt.Sprintf("creator-%d", i+1),
+			Name:        author,
+			Role:        model.RoleAuthor,
+			Refinements: meta.Refinements[author],
+		})
+	}
+	for i, contributor := range meta.Contributors {
+		items = append(items, creatorItem{
+			Element:     "contributor",
+			ID:          fmt.Sprintf("contributor-%d", i+1),
+			Name:        contributor.Name,
+			Role:        contributor.Role,
+			Refinements: meta.Refinements[contributor.Name],
+		})
+	}
+	return items
+}
+
+// buildProperties creates entries for the further DC elements and meta
+// properties of the metadata. dcterms:modified is skipped, as it is always
+// written for the time of the build.
+func buildProperties(meta *model.Metadata) []propertyItem {
+	items := make([]propertyItem, 0, len(meta.Properties))
+	for i, p := range meta.Properties {
+		if p.Value == "" || p.Name == "dcterms:modified" {
+			continue
+		}
+		item := propertyItem{Property: p.Name, Value: p.Value, Refinements: p.Refinements}
+		if p.IsDC() {
+			item.Element = strings.TrimPrefix(p.Name, "dc:")
+		}
+		if len(p.Refinements) > 0 {
+			item.ID = fmt.Sprintf("property-%d", i+1)
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// buildCollections creates belongs-to-collection entries for the series and
+// collections the book belongs to.
+func buildCollections(meta *model.Metadata) []collectionItem {
+	items := make([]collectionItem, 0, len(meta.Collections))
+	for i, c := range meta.Collections {
+		if c.Name == "" {
+			continue
+		}
+		items = append(items, collectionItem{
+			ID:       fmt.Sprintf("collection-%d", i+1),
+			Name:     c.Name,
+			Type:     c.Type,
+			Position: c.Position,
+		})
+	}
+	return items
+}
+
+// Patterns used to detect content requiring manifest properties.
+var (
+	svgElementRe    = regexp.MustCompile(`(?i)<svg[\s>/]`)
+	mathElementRe   = regexp.MustCompile(`(?i)<(?:m:)?math[\s>/]`)
+	switchElementRe = regexp.MustCompile(`<epub:switch[\s>]`)
+	scriptElementRe = regexp.MustCompile(`(?i)<script[\s>/]`)
+	formElementRe   = regexp.MustCompile(`(?i)<(?:form|input|button|select|textarea)[\s>/]`)
+	eventHandlerRe  = regexp.MustCompile(`(?i)<[a-z][^>]*\son[a-z]+\s*=`)
+)
+
+// buildChapterItems creates manifest items for chapters with properties
+// detected from their content.
+func buildChapterItems(chapters []model.Chapter) []chapterItem {
+	items := make([]chapterItem, 0, len(chapters))
+	for _, chapter := range chapters {
+		items = append(items, chapterItem{
+			Chapter:    chapter,
+			Properties: detectManifestProperties(chapter.Content),
+		})
+	}
+	return items
+}
+
+// detectManifestProperties returns the EPUB 3 manifest properties required
+// by a content document: "svg" for inline SVG, "mathml" for MathML,
+// "scripted" for scripts, event handlers, or form elements, and "switch"
+// for epub:switch.
+func detectManifestProperties(content string) string {
+	var props []string
+	if mathElementRe.MatchString(content) {
+		props = append(props, "mathml")
+	}
+	if scriptElementRe.MatchString(content) || eventHandlerRe.MatchString(content) ||
+		formElementRe.MatchString(content) {
+		props = append(props, "scripted")
+	}
+	if svgElementRe.MatchString(content) {
+		props = append(props, "svg")
+	}
+	if switchElementRe.MatchString(content) {
+		props = append(props, "switch")
+	}
+	return strings.Join(props, " ")
+}
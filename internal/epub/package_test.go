@@ -0,0 +1,86 @@
+package epub
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestDetectManifestProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"plain", "<p>Text</p>", ""},
+		{"svg", `<svg xmlns="http://www.w3.org/2000/svg"><rect/></svg>`, "svg"},
+		{"mathml", `<math xmlns="http://www.w3.org/1998/Math/MathML"><mi>x</mi></math>`, "mathml"},
+		{"script", `<script>var x = 1;</script>`, "scripted"},
+		{"event handler", `<button onclick="go()">Go</button>`, "scripted"},
+		{"combined", `<math><mi>x</mi></math><svg><g/></svg><script src="a.js"></script>`, "mathml scripted svg"},
+		{"svg image reference", `<img src="../images/figure.svg" />`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, detectManifestProperties(tt.content))
+		})
+	}
+}
+
+func TestGeneratePackageDocument_EscapesMetadata(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = `Fish & "Chips" <Deluxe>`
+	doc.Metadata.Authors = []string{"O'Brien \x01"}
+	doc.Metadata.Description = "Line one\x0bline two"
+	doc.Metadata.EnsureIdentifier()
+	doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", FileName: "content/chapter-001.xhtml"})
+
+	opf, err := generatePackageDocument(doc, Options{})
+	require.NoError(t, err)
+
+	var parsed opfPackage
+	require.NoError(t, xml.Unmarshal([]byte(opf), &parsed), opf)
+	assert.Contains(t, opf, `<dc:title>Fish &amp; &#34;Chips&#34; &lt;Deluxe&gt;</dc:title>`)
+	assert.NotContains(t, opf, "\x01")
+	assert.NotContains(t, opf, "\x0b")
+	assert.Contains(t, opf, `<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="uid">`)
+	assert.Contains(t, opf, `<metadata xmlns:dc="http://purl.org/dc/elements/1.1/">`)
+}
+
+func TestGeneratePackageDocument_Subjects(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Subjects"
+	doc.Metadata.Rights = "© 2025 Jane Doe"
+	doc.Metadata.Subjects = []string{"Fiction", "Science & Nature"}
+	doc.Metadata.EnsureIdentifier()
+	doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", FileName: "content/chapter-001.xhtml"})
+
+	opf, err := generatePackageDocument(doc, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, opf, "<dc:rights>© 2025 Jane Doe</dc:rights>")
+	assert.Contains(t, opf, "<dc:subject>Fiction</dc:subject>\n    <dc:subject>Science &amp; Nature</dc:subject>")
+}
+
+func TestGeneratePackageDocument_Collections(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Volume Two"
+	doc.Metadata.Collections = []model.Collection{
+		{Name: "The Saga", Type: model.CollectionSeries, Position: "2"},
+		{Name: "Summer Reads"},
+	}
+	doc.Metadata.EnsureIdentifier()
+	doc.AddChapter(model.Chapter{ID: "ch1", Title: "One", FileName: "content/chapter-001.xhtml"})
+
+	opf, err := generatePackageDocument(doc, Options{})
+	require.NoError(t, err)
+	assert.Contains(t, opf, `<meta id="collection-1" property="belongs-to-collection">The Saga</meta>`)
+	assert.Contains(t, opf, `<meta refines="#collection-1" property="collection-type">series</meta>`)
+	assert.Contains(t, opf, `<meta refines="#collection-1" property="group-position">2</meta>`)
+	assert.Contains(t, opf, `<meta id="collection-2" property="belongs-to-collection">Summer Reads</meta>`)
+	assert.NotContains(t, opf, `refines="#collection-2"`)
+}
@@ -0,0 +1,42 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// pageBreakMarker is the element the parsers produce for page-break directives.
+const pageBreakMarker = `<div class="page-break"></div>`
+
+// markPageBreaks turns page break markers into epub:type="pagebreak"
+// anchors numbered in reading order across the book, so reading systems can
+// announce and navigate to them.
+func (b *Builder) markPageBreaks(doc *model.Document) {
+	count := 0
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		if !strings.Contains(chapter.Content, pageBreakMarker) {
+			continue
+		}
+
+		parts := strings.Split(chapter.Content, pageBreakMarker)
+		var buf strings.Builder
+		buf.WriteString(parts[0])
+		for _, part := range parts[1:] {
+			count++
+			n := strconv.Itoa(count)
+			buf.WriteString(`<div class="page-break" id="pagebreak-` + n + `" epub:type="pagebreak" role="doc-pagebreak" title="` + n + `" aria-label="` + n + `"></div>`)
+			buf.WriteString(part)
+		}
+		chapter.Content = buf.String()
+	}
+}
@@ -0,0 +1,162 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Reader profiles, selecting the quirks of a store or device family.
+const (
+	ProfileGeneric = "generic" // Standard EPUB 3 without target-specific changes
+	ProfileKindle  = "kindle"  // Kindle: layout CSS older Kindles do not support is removed
+	ProfileKobo    = "kobo"    // Kobo: kepub sentence spans for reading statistics and highlights
+	ProfileApple   = "apple"   // Apple Books: display options enabling embedded fonts
+)
+
+// ParseProfile validates a reader profile name, returning ProfileGeneric
+// for an empty string.
+func ParseProfile(s string) (string, error) {
+	switch p := strings.ToLower(s); p {
+	case "":
+		return ProfileGeneric, nil
+	case ProfileGeneric, ProfileKindle, ProfileKobo, ProfileApple:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown reader profile %q", s)
+	}
+}
+
+// ProfileVersion returns the EPUB version a profile targets: Kindle and
+// Kobo read the NCX and legacy cover meta of Version30 books, which some
+// of their devices and converters still rely on.
+func ProfileVersion(profile string) string {
+	switch profile {
+	case ProfileKindle, ProfileKobo:
+		return Version30
+	default:
+		return Version33
+	}
+}
+
+// unsupportedKindleCSSRe matches declarations of layout features that
+// older Kindle formats ignore or render badly: flexbox, grid, and fixed or
+// absolute positioning. The separator before the declaration is kept.
+var unsupportedKindleCSSRe = regexp.MustCompile(`(?i)([;{])\s*(?:display\s*:\s*(?:inline-)?(?:flex|grid)\b|position\s*:\s*(?:fixed|absolute)\b|(?:flex|flex-[a-z-]+|grid|grid-[a-z-]+|align-items|align-content|align-self|justify-content|justify-items|gap|row-gap)\s*:)[^;}]*;?`)
+
+// restrictCSS removes the declarations a profile does not support from a
+// stylesheet.
+func restrictCSS(css, profile string) string {
+	if profile != ProfileKindle {
+		return css
+	}
+	for {
+		stripped := unsupportedKindleCSSRe.ReplaceAllString(css, "$1")
+		if stripped == css {
+			return css
+		}
+		css = stripped
+	}
+}
+
+// Patterns used to add kepub spans.
+var (
+	kepubBodyRe     = regexp.MustCompile(`(?s)(<body\b[^>]*>)(.*)(</body>)`)
+	kepubSentenceRe = regexp.MustCompile(`[^.!?…]*(?:[.!?…]+["'”’»)\]]*\s*|$)`)
+)
+
+// kepubBlocks are the elements that start a new kepub paragraph.
+var kepubBlocks = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"li": true, "dt": true, "dd": true, "td": true, "th": true, "caption": true,
+	"figcaption": true, "blockquote": true, "div": true, "pre": true, "aside": true,
+}
+
+// kepubSkipped are elements whose text is not wrapped in spans.
+var kepubSkipped = map[string]bool{
+	"script": true, "style": true, "math": true, "svg": true, "title": true,
+}
+
+// addKepubSpans converts a content document to Kobo's kepub form: each
+// sentence is wrapped in a koboSpan numbered by paragraph and sentence,
+// which Kobo readers use for highlights and reading statistics, and the
+// body content is wrapped in the book-columns and book-inner divs.
+func addKepubSpans(doc string) string {
+	loc := kepubBodyRe.FindStringSubmatchIndex(doc)
+	if loc == nil {
+		return doc
+	}
+	body := doc[loc[4]:loc[5]]
+
+	var buf strings.Builder
+	paragraph, sentence := 0, 0
+	skipped := 0
+	last := 0
+	wrap := func(text string) {
+		if skipped > 0 || strings.TrimSpace(text) == "" {
+			buf.WriteString(text)
+			return
+		}
+		for _, s := range kepubSentenceRe.FindAllString(text, -1) {
+			if strings.TrimSpace(s) == "" {
+				buf.WriteString(s)
+				continue
+			}
+			sentence++
+			fmt.Fprintf(&buf, `<span class="koboSpan" id="kobo.%d.%d">%s</span>`, paragraph, sentence, s)
+		}
+	}
+
+	for _, m := range markupTagRe.FindAllStringSubmatchIndex(body, -1) {
+		wrap(body[last:m[0]])
+		buf.WriteString(body[m[0]:m[1]])
+		last = m[1]
+
+		if m[4] < 0 {
+			continue // Comment or declaration
+		}
+		name := strings.ToLower(body[m[4]:m[5]])
+		closing := m[3] > m[2]
+		selfClosing := strings.HasSuffix(body[m[0]:m[1]], "/>")
+		switch {
+		case kepubSkipped[name] && !selfClosing:
+			if closing {
+				skipped = max(skipped-1, 0)
+			} else {
+				skipped++
+			}
+		case kepubBlocks[name] && !closing && skipped == 0:
+			paragraph++
+			sentence = 0
+		}
+	}
+	wrap(body[last:])
+
+	return doc[:loc[3]] + `<div id="book-columns"><div class="book-inner" id="book-inner">` +
+		buf.String() + `</div></div>` + doc[loc[6]:]
+}
+
+// appleDisplayOptions is META-INF/com.apple.ibooks.display-options.xml,
+// which lets Apple Books use the fonts the book specifies.
+func appleDisplayOptions(fixedLayout bool) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<display_options>
+  <platform name="*">
+    <option name="specified-fonts">true</option>
+`)
+	if fixedLayout {
+		b.WriteString("    <option name=\"fixed-layout\">true</option>\n")
+	}
+	b.WriteString(`  </platform>
+</display_options>
+`)
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package epub
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProfile(t *testing.T) {
+	for input, want := range map[string]string{"": ProfileGeneric, "Kobo": ProfileKobo, "kindle": ProfileKindle, "apple": ProfileApple} {
+		profile, err := ParseProfile(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, profile, input)
+	}
+	_, err := ParseProfile("nook")
+	assert.Error(t, err)
+}
+
+func TestAddKepubSpans(t *testing.T) {
+	doc := "<html><head><title>One. Two</title></head><body>\n<h1>Title</h1>\n<p>First one. Second <em>part</em>!</p>\n<p><math><mi>x</mi></math></p>\n</body></html>"
+
+	assert.Equal(t, "<html><head><title>One. Two</title></head><body>"+
+		`<div id="book-columns"><div class="book-inner" id="book-inner">`+"\n"+
+		`<h1><span class="koboSpan" id="kobo.1.1">Title</span></h1>`+"\n"+
+		`<p><span class="koboSpan" id="kobo.2.1">First one. </span><span class="koboSpan" id="kobo.2.2">Second </span>`+
+		`<em><span class="koboSpan" id="kobo.2.3">part</span></em><span class="koboSpan" id="kobo.2.4">!</span></p>`+"\n"+
+		"<p><math><mi>x</mi></math></p>\n"+
+		"</div></div></body></html>", addKepubSpans(doc))
+}
+
+func TestRestrictCSS(t *testing.T) {
+	css := ".row {\n  display: flex;\n  align-items: center;\n  margin: 0;\n}\n.note { position: fixed; top: 0 }\n"
+
+	assert.Equal(t, css, restrictCSS(css, ProfileKobo))
+	assert.Equal(t, ".row {\n  margin: 0;\n}\n.note { top: 0 }\n", restrictCSS(css, ProfileKindle))
+}
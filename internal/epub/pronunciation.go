@@ -0,0 +1,118 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Namespaces of pronunciation lexicons and of the SSML attributes in
+// content documents.
+const (
+	plsNamespace  = "http://www.w3.org/2005/01/pronunciation-lexicon"
+	ssmlNamespace = "http://www.w3.org/2001/10/synthesis"
+)
+
+// lexiconFileName is the EPUB path of the generated pronunciation lexicon.
+const lexiconFileName = "lexicons/pronunciations.pls"
+
+// ssmlNoMarkElements are elements whose text is never given a
+// pronunciation, as it is not read as words.
+var ssmlNoMarkElements = map[string]bool{
+	"code": true, "pre": true, "kbd": true, "samp": true, "script": true, "style": true,
+}
+
+// addPronunciations marks every whole-word occurrence of the document's
+// pronunciation terms with ssml:ph, for reading systems that read SSML,
+// and adds a lexicon of the terms, for those that read PLS.
+func (b *Builder) addPronunciations(doc *model.Document) {
+	if len(doc.Pronunciations) == 0 {
+		return
+	}
+
+	// Match longer terms first so "New York" wins over "York"
+	phonemes := make(map[string]string, len(doc.Pronunciations))
+	alternatives := make([]string, 0, len(doc.Pronunciations))
+	for _, p := range doc.Pronunciations {
+		term := textEscaper.Replace(p.Term)
+		phonemes[term] = p.Phoneme
+		alternatives = append(alternatives, regexp.QuoteMeta(term))
+	}
+	sort.SliceStable(alternatives, func(i, j int) bool { return len(alternatives[i]) > len(alternatives[j]) })
+	termRe := regexp.MustCompile(strings.Join(alternatives, "|"))
+
+	for i := range doc.Chapters {
+		chapter := &doc.Chapters[i]
+		chapter.Content = mapText(chapter.Content, ssmlNoMarkElements, func(text string) string {
+			return markPronunciations(text, termRe, phonemes)
+		})
+	}
+
+	fileName := lexiconFileName
+	for n := 2; slices.ContainsFunc(doc.Resources, func(res model.Resource) bool { return res.FileName == fileName }); n++ {
+		fileName = strings.TrimSuffix(lexiconFileName, ".pls") + "-" + strconv.Itoa(n) + ".pls"
+	}
+	doc.AddResource(model.Resource{
+		ID:        "pls-pronunciations",
+		FileName:  fileName,
+		MediaType: model.PLSMediaType,
+		Data:      []byte(renderLexicon(doc.Pronunciations, doc.Metadata.Language)),
+	})
+}
+
+// markPronunciations wraps the whole-word terms in a run of escaped text
+// in spans giving their pronunciation.
+func markPronunciations(text string, termRe *regexp.Regexp, phonemes map[string]string) string {
+	var buf strings.Builder
+	last := 0
+	for _, loc := range termRe.FindAllStringIndex(text, -1) {
+		if !isWordBoundary(text, loc[0], loc[1]) {
+			continue
+		}
+		match := text[loc[0]:loc[1]]
+		buf.WriteString(text[last:loc[0]])
+		buf.WriteString(`<span ssml:alphabet="ipa" ssml:ph="` + html.EscapeString(phonemes[match]) + `">` + match + "</span>")
+		last = loc[1]
+	}
+	buf.WriteString(text[last:])
+	return buf.String()
+}
+
+// renderLexicon renders pronunciations as a PLS lexicon in language.
+func renderLexicon(entries []model.Pronunciation, language string) string {
+	var buf strings.Builder
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<lexicon version="1.0" xmlns="` + plsNamespace + `" alphabet="ipa" xml:lang="` + html.EscapeString(language) + `">` + "\n")
+	for _, entry := range entries {
+		buf.WriteString("  <lexeme>\n")
+		buf.WriteString("    <grapheme>" + html.EscapeString(entry.Term) + "</grapheme>\n")
+		buf.WriteString("    <phoneme>" + html.EscapeString(entry.Phoneme) + "</phoneme>\n")
+		buf.WriteString("  </lexeme>\n")
+	}
+	buf.WriteString("</lexicon>\n")
+	return buf.String()
+}
+
+// lexiconHrefs returns the hrefs of the book's pronunciation lexicons
+// relative to the chapter at fileName.
+func lexiconHrefs(doc *model.Document, fileName string) []string {
+	var hrefs []string
+	for _, res := range doc.Resources {
+		if res.IsLexicon() {
+			hrefs = append(hrefs, html.EscapeString(relativeHref(fileName, res.FileName)))
+		}
+	}
+	return hrefs
+}
@@ -0,0 +1,187 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// workers returns the number of content documents rendered at once.
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// renderedChapter is a content document rendered, checked, and deflated
+// ahead of being written to the archive.
+type renderedChapter struct {
+	header    zip.FileHeader
+	data      []byte          // Deflated content document
+	malformed error           // Why the document is not well-formed, if it is not
+	issues    []model.Warning // Accessibility audit findings
+	err       error
+}
+
+// renderChapter renders a chapter's content document, checks it, and
+// deflates it, or reuses the result for the same document from
+// Options.Chapters. It only reads the builder, so chapters render
+// concurrently.
+func (b *Builder) renderChapter(chapter *model.Chapter) renderedChapter {
+	content, err := generateContentDocument(b.templates.content, chapter, &b.doc.Metadata, lexiconHrefs(b.doc, chapter.FileName), b.opts)
+	if err != nil {
+		return renderedChapter{err: err}
+	}
+	if b.opts.Profile == ProfileKobo {
+		content = addKepubSpans(content)
+	}
+
+	var key string
+	if b.opts.Chapters != nil {
+		key = chapterKey(chapter.FileName, content, b.opts.CheckA11y)
+		if r, ok := b.opts.Chapters.get(key); ok {
+			return r
+		}
+	}
+
+	r := renderedChapter{malformed: checkWellFormed(content)}
+	if b.opts.CheckA11y {
+		r.issues = auditA11y(chapter.FileName, content)
+	}
+
+	var buf bytes.Buffer
+	fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return renderedChapter{err: err}
+	}
+	if _, err := fw.Write([]byte(content)); err != nil {
+		return renderedChapter{err: err}
+	}
+	if err := fw.Close(); err != nil {
+		return renderedChapter{err: err}
+	}
+
+	r.header = zip.FileHeader{
+		Name:               "OEBPS/" + chapter.FileName,
+		Method:             zip.Deflate,
+		CRC32:              crc32.ChecksumIEEE([]byte(content)),
+		CompressedSize64:   uint64(buf.Len()),
+		UncompressedSize64: uint64(len(content)),
+	}
+	r.data = buf.Bytes()
+	if b.opts.Chapters != nil {
+		b.opts.Chapters.put(key, r)
+	}
+	return r
+}
+
+// renderChapters renders the document's chapters on Options.Workers
+// goroutines, returning a channel per chapter that receives its document.
+// At most twice as many chapters as workers are rendered ahead of the
+// ones received; closing done stops rendering further chapters.
+func (b *Builder) renderChapters(done <-chan struct{}) ([]chan renderedChapter, func()) {
+	chapters := b.doc.Chapters
+	workers := b.opts.workers()
+	results := make([]chan renderedChapter, len(chapters))
+	for i := range results {
+		results[i] = make(chan renderedChapter, 1)
+	}
+
+	slots := make(chan struct{}, 2*workers)
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range chapters {
+			select {
+			case slots <- struct{}{}:
+			case <-done:
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+	for range workers {
+		go func() {
+			for i := range jobs {
+				results[i] <- b.renderChapter(&chapters[i])
+			}
+		}()
+	}
+	return results, func() { <-slots }
+}
+
+// ChapterCache keeps content documents rendered by earlier builds, so a
+// rebuild deflates and checks only the documents whose output changed.
+// Each build drops the entries it did not use; builds sharing a cache
+// should be of the same book.
+type ChapterCache struct {
+	mu      sync.Mutex
+	entries map[string]renderedChapter
+	used    map[string]bool // Keys used since the last sweep
+}
+
+// NewChapterCache returns an empty chapter cache.
+func NewChapterCache() *ChapterCache {
+	return &ChapterCache{entries: make(map[string]renderedChapter), used: make(map[string]bool)}
+}
+
+// chapterKey identifies a rendered content document and the checks run
+// on it.
+func chapterKey(name, content string, a11y bool) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%t\x00", name, a11y)
+	io.WriteString(h, content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get returns the document stored under key, marking it used.
+func (c *ChapterCache) get(key string) (renderedChapter, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.entries[key]
+	if ok {
+		c.used[key] = true
+	}
+	return r, ok
+}
+
+// put stores r under key.
+func (c *ChapterCache) put(key string, r renderedChapter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = r
+	c.used[key] = true
+}
+
+// sweep drops the entries not used since the last sweep.
+func (c *ChapterCache) sweep() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if !c.used[key] {
+			delete(c.entries, key)
+		}
+	}
+	c.used = make(map[string]bool)
+}
@@ -0,0 +1,112 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// checkReadingOrder checks the document's chapters before the builder adds
+// its own pages. Every chapter needs a file of its own to be a spine item.
+// Chapters are renumbered to their position, as the spine follows the
+// chapter list; Order values that were set but had gaps or were out of
+// sequence are reported through Options.Warn, as are body chapters
+// missing from the table of contents.
+func (b *Builder) checkReadingOrder(doc *model.Document) error {
+	files := make(map[string]bool, len(doc.Chapters))
+	for i, chapter := range doc.Chapters {
+		if chapter.FileName == "" {
+			return fmt.Errorf("%w: chapter %d (%q) has no file name", ErrInvalidDocument, i+1, chapter.Title)
+		}
+		if files[chapter.FileName] {
+			return fmt.Errorf("%w: more than one chapter is written to %s", ErrInvalidDocument, chapter.FileName)
+		}
+		files[chapter.FileName] = true
+	}
+
+	// Orders left at zero were never set, as by AddChapter
+	numbered := false
+	for _, chapter := range doc.Chapters {
+		numbered = numbered || chapter.Order != 0
+	}
+	var gaps []string
+	for i := range doc.Chapters {
+		if order := doc.Chapters[i].Order; order != i && numbered {
+			gaps = append(gaps, fmt.Sprintf("%s has order %d at position %d", doc.Chapters[i].FileName, order, i))
+		}
+		doc.Chapters[i].Order = i
+	}
+	if len(gaps) > 0 {
+		b.warn(model.Warning{
+			Code:     model.WarnReadingOrder,
+			Severity: model.SeverityInfo,
+			Message:  fmt.Sprintf("Chapter order is not contiguous (%s); renumbered to follow the chapter list", strings.Join(gaps, ", ")),
+		})
+	}
+
+	// Body chapters should each be reachable from the navigation
+	listed := make(map[string]bool, len(doc.Chapters))
+	var walk func(entries []model.TOCEntry)
+	walk = func(entries []model.TOCEntry) {
+		for _, entry := range entries {
+			file, _, _ := strings.Cut(entry.Href, "#")
+			listed[file] = true
+			walk(entry.Children)
+		}
+	}
+	walk(doc.TOC.Entries)
+	for _, chapter := range doc.Chapters {
+		if isBodyMatter(chapter) && !listed[chapter.FileName] {
+			b.warn(model.Warning{
+				Code:    model.WarnReadingOrder,
+				File:    chapter.FileName,
+				Message: fmt.Sprintf("%s is in the reading order but not in the table of contents", chapter.FileName),
+			})
+		}
+	}
+	return nil
+}
+
+// addChapterLandmarks adds a landmark for the first chapter of each
+// structural type the chapters declare, such as a preface or appendix.
+func (b *Builder) addChapterLandmarks(doc *model.Document) {
+	seen := make(map[string]bool)
+	for _, chapter := range doc.Chapters {
+		semantic := chapter.Semantic()
+		if semantic == "" || semantic == "cover" || seen[semantic] {
+			continue
+		}
+		seen[semantic] = true
+		title := chapter.Title
+		if title == "" {
+			title = semantic
+		}
+		b.landmarks = append(b.landmarks, landmark{Type: semantic, Href: chapter.FileName, Title: title})
+	}
+}
+
+// checkLandmarks reports landmarks whose target is not a spine item.
+func (b *Builder) checkLandmarks(doc *model.Document) {
+	spine := make(map[string]bool, len(doc.Chapters))
+	for _, chapter := range doc.Chapters {
+		spine[chapter.FileName] = true
+	}
+	for _, l := range b.landmarks {
+		file, _, _ := strings.Cut(l.Href, "#")
+		if !spine[file] {
+			b.warn(model.Warning{
+				Code:    model.WarnReadingOrder,
+				File:    "nav.xhtml",
+				Message: fmt.Sprintf("Landmark %s points to %s, which is not in the reading order", l.Type, l.Href),
+			})
+		}
+	}
+}
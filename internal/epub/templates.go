@@ -0,0 +1,100 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"text/template"
+)
+
+// Template file names looked up in a template directory.
+const (
+	ContentTemplateFile = "content.xhtml.tmpl"
+	NavTemplateFile     = "nav.xhtml.tmpl"
+	PackageTemplateFile = "package.opf.tmpl"
+)
+
+// Templates holds the parsed templates used to generate EPUB documents.
+// The package document has no default template: it is generated with
+// encoding/xml unless a custom package template is loaded.
+type Templates struct {
+	content *template.Template
+	nav     *template.Template
+	pkg     *template.Template
+}
+
+// defaultTemplates parses the built-in templates once. Parsed templates
+// are safe for concurrent use, so every builder shares them.
+var defaultTemplates = sync.OnceValue(func() Templates {
+	return Templates{
+		content: template.Must(template.New("content").Parse(contentTemplate)),
+		nav:     template.Must(template.New("nav").Parse(navTemplate)),
+	}
+})
+
+// DefaultTemplates returns the built-in templates.
+func DefaultTemplates() *Templates {
+	t := defaultTemplates()
+	return &t
+}
+
+// LoadTemplates loads templates from a directory. Any template file that is
+// not present falls back to the built-in default.
+func LoadTemplates(dir string) (*Templates, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("template directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template directory: %s is not a directory", dir)
+	}
+
+	t := DefaultTemplates()
+
+	overrides := []struct {
+		file string
+		dst  **template.Template
+	}{
+		{ContentTemplateFile, &t.content},
+		{NavTemplateFile, &t.nav},
+		{PackageTemplateFile, &t.pkg},
+	}
+
+	for _, o := range overrides {
+		tmpl, err := loadTemplateFile(filepath.Join(dir, o.file))
+		if err != nil {
+			return nil, err
+		}
+		if tmpl != nil {
+			*o.dst = tmpl
+		}
+	}
+
+	return t, nil
+}
+
+// loadTemplateFile parses a template file. Returns nil if the file does not exist.
+func loadTemplateFile(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", path, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", path, err)
+	}
+	return tmpl, nil
+}
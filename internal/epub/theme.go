@@ -0,0 +1,150 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Code highlighting themes of the built-in stylesheet. They color the
+// tokens of code highlighted by highlight.js (hljs-* classes) or by
+// Pygments, Rouge, and Chroma (short classes inside .highlight or .chroma).
+const (
+	CodeThemeLight = "light" // Light code blocks, dark in readers' night modes
+	CodeThemeDark  = "dark"  // Dark code blocks in every mode
+	CodeThemeNone  = "none"  // Code blocks without token colors
+)
+
+// ParseCodeTheme validates a code highlighting theme, returning the
+// default theme for an empty string.
+func ParseCodeTheme(s string) (string, error) {
+	switch s {
+	case "", CodeThemeLight:
+		return CodeThemeLight, nil
+	case CodeThemeDark, CodeThemeNone:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unknown code theme %q", s)
+	}
+}
+
+// codePalette holds the colors of a code highlighting theme.
+type codePalette struct {
+	background string
+	text       string
+	keyword    string
+	str        string
+	comment    string
+	number     string
+	function   string
+	typ        string
+	tag        string
+	meta       string
+}
+
+// Palettes of the light and dark themes, after GitHub's.
+var (
+	lightCodePalette = codePalette{
+		background: "#f5f5f5", text: "#24292e", keyword: "#d73a49", str: "#032f62", comment: "#6a737d",
+		number: "#005cc5", function: "#6f42c1", typ: "#e36209", tag: "#22863a", meta: "#735c0f",
+	}
+	darkCodePalette = codePalette{
+		background: "#161b22", text: "#c9d1d9", keyword: "#ff7b72", str: "#a5d6ff", comment: "#8b949e",
+		number: "#79c0ff", function: "#d2a8ff", typ: "#ffa657", tag: "#7ee787", meta: "#d29922",
+	}
+)
+
+// codeTokenClasses are the token classes colored by each palette entry:
+// highlight.js classes, then the short classes of Pygments, Rouge, and
+// Chroma.
+var codeTokenClasses = []struct {
+	color    func(codePalette) string
+	hljs     []string
+	pygments []string
+}{
+	{func(p codePalette) string { return p.keyword }, []string{"keyword", "selector-tag", "literal"}, []string{"k", "kc", "kd", "kn", "kp", "kr", "ow"}},
+	{func(p codePalette) string { return p.str }, []string{"string", "regexp"}, []string{"s", "s1", "s2", "sb", "sc", "sd", "se", "sh", "si", "sr", "ss", "sx"}},
+	{func(p codePalette) string { return p.comment }, []string{"comment", "quote"}, []string{"c", "c1", "ch", "cm", "cp", "cpf", "cs"}},
+	{func(p codePalette) string { return p.number }, []string{"number", "attr", "variable"}, []string{"m", "mb", "mf", "mh", "mi", "mo", "na", "nv"}},
+	{func(p codePalette) string { return p.function }, []string{"title", "function"}, []string{"nf", "fm", "nc", "ne"}},
+	{func(p codePalette) string { return p.typ }, []string{"type", "built_in", "params"}, []string{"kt", "nb", "bp"}},
+	{func(p codePalette) string { return p.tag }, []string{"name", "selector-id", "selector-class"}, []string{"nt", "nd"}},
+	{func(p codePalette) string { return p.meta }, []string{"meta", "symbol"}, []string{"nl", "nn"}},
+}
+
+// css returns the rules coloring code blocks and their tokens, each line
+// prefixed with indent.
+func (p codePalette) css(indent string) string {
+	var css strings.Builder
+	rule := func(selectors []string, declarations string) {
+		css.WriteString(indent + strings.Join(selectors, ",\n"+indent) + " {\n" + indent + "  " + declarations + "\n" + indent + "}\n")
+	}
+
+	rule([]string{"pre", "code"}, "background-color: "+p.background+";\n"+indent+"  color: "+p.text+";")
+	for _, token := range codeTokenClasses {
+		var selectors []string
+		for _, class := range token.hljs {
+			selectors = append(selectors, ".hljs-"+class)
+		}
+		for _, class := range token.pygments {
+			selectors = append(selectors, ".highlight ."+class+", .chroma ."+class)
+		}
+		rule(selectors, "color: "+token.color(p)+";")
+	}
+	rule([]string{".hljs-comment", ".highlight .c, .highlight .c1, .highlight .cm", ".chroma .c, .chroma .c1, .chroma .cm"}, "font-style: italic;")
+	return css.String()
+}
+
+// darkModeCSS holds the rules for readers' night modes that do not depend
+// on the code theme: text on light backgrounds and light borders are
+// darkened, links lightened, and black equation images inverted.
+const darkModeCSS = `  th {
+    background-color: #2d333b;
+  }
+  th, td {
+    border-color: #444c56;
+  }
+  blockquote {
+    border-left-color: #444c56;
+  }
+  a {
+    color: #6cb6ff;
+  }
+  .packaged-by {
+    background-color: transparent !important;
+    border-color: #444c56 !important;
+  }
+  img.math, img.math-display {
+    filter: invert(1);
+  }
+`
+
+// themeCSS returns the code theme and night mode rules of the built-in
+// stylesheet. Night mode rules apply under prefers-color-scheme: dark.
+func (b *Builder) themeCSS() string {
+	theme, _ := ParseCodeTheme(b.opts.CodeTheme)
+
+	var css strings.Builder
+	switch theme {
+	case CodeThemeLight:
+		css.WriteString("\n/* Code highlighting: light theme */\n" + lightCodePalette.css(""))
+	case CodeThemeDark:
+		css.WriteString("\n/* Code highlighting: dark theme */\n" + darkCodePalette.css(""))
+	}
+
+	css.WriteString("\n/* Night mode */\n@media (prefers-color-scheme: dark) {\n" + darkModeCSS)
+	switch theme {
+	case CodeThemeLight:
+		css.WriteString(darkCodePalette.css("  "))
+	case CodeThemeNone:
+		css.WriteString("  pre, code {\n    background-color: " + darkCodePalette.background + ";\n    color: " + darkCodePalette.text + ";\n  }\n")
+	}
+	css.WriteString("}\n")
+	return css.String()
+}
@@ -0,0 +1,173 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// epubMediaType is the content of an EPUB's mimetype file.
+const epubMediaType = "application/epub+zip"
+
+// Unpack writes the entries of the EPUB at epubPath into dir as a
+// directory tree, creating dir if needed.
+func Unpack(epubPath, dir string) error {
+	r, err := zip.OpenReader(epubPath)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(f.Name)) {
+			return fmt.Errorf("%w: entry %s leads outside the package", ErrInvalidPackage, f.Name)
+		}
+		if err := unpackEntry(f, filepath.Join(dir, filepath.FromSlash(f.Name))); err != nil {
+			return fmt.Errorf("unpacking %s: %w", f.Name, err)
+		}
+	}
+	return nil
+}
+
+// unpackEntry copies an archive entry to the file at name.
+func unpackEntry(f *zip.File, name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	out, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, rc); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// Pack zips the unpacked EPUB in dir into the EPUB at epubPath: the
+// mimetype file first and uncompressed, as reading systems require, then
+// the other files in path order. Hidden files, such as .DS_Store, are left
+// out. The archive is written next to epubPath and renamed over it only
+// when complete.
+func Pack(dir, epubPath string) error {
+	if err := checkUnpacked(dir); err != nil {
+		return err
+	}
+
+	if parent := filepath.Dir(epubPath); parent != "." {
+		if err := os.MkdirAll(parent, 0o755); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(epubPath), ".toepub-*.epub")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writePacked(tmp, dir); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), epubPath)
+}
+
+// checkUnpacked checks that dir holds an EPUB: a mimetype file naming the
+// EPUB media type, and a container.xml pointing at a package document.
+func checkUnpacked(dir string) error {
+	fsys := os.DirFS(dir)
+	mimetype, err := fs.ReadFile(fsys, "mimetype")
+	if err != nil {
+		return fmt.Errorf("%w: %s has no mimetype file", ErrInvalidPackage, dir)
+	}
+	if string(bytes.TrimSpace(mimetype)) != epubMediaType {
+		return fmt.Errorf("%w: mimetype is not %s", ErrInvalidPackage, epubMediaType)
+	}
+
+	container, err := fs.ReadFile(fsys, "META-INF/container.xml")
+	if err != nil {
+		return fmt.Errorf("%w: %s has no META-INF/container.xml", ErrInvalidPackage, dir)
+	}
+	m := rootfileRe.FindSubmatch(container)
+	if m == nil {
+		return fmt.Errorf("%w: container.xml has no rootfile", ErrInvalidPackage)
+	}
+	opfPath := html.UnescapeString(string(m[1]))
+	if _, err := fs.Stat(fsys, opfPath); err != nil {
+		return fmt.Errorf("%w: package document %s not found", ErrInvalidPackage, opfPath)
+	}
+	return nil
+}
+
+// writePacked writes the files of dir to w as an EPUB archive.
+func writePacked(w io.Writer, dir string) error {
+	zw := zip.NewWriter(w)
+
+	// The mimetype is written as the builder writes it, without the line
+	// break editors tend to add
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mw, epubMediaType); err != nil {
+		return err
+	}
+
+	fsys := os.DirFS(dir)
+	err = fs.WalkDir(fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || p == "mimetype" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		return writeZipEntry(zw, p, data)
+	})
+	if err != nil {
+		return fmt.Errorf("packing %s: %w", dir, err)
+	}
+	return zw.Close()
+}
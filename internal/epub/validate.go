@@ -0,0 +1,113 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// dcLanguageRe matches a non-empty dc:language element.
+var dcLanguageRe = regexp.MustCompile(`<dc:language\b[^>]*>\s*[^<\s]`)
+
+// ValidateOptions selects the checks Validate runs in addition to the
+// well-formedness and link checks.
+type ValidateOptions struct {
+	A11y bool // Audit the documents for accessibility problems
+}
+
+// Validate checks the XHTML documents listed in the manifest of the EPUB
+// at epubPath, returning each problem found as a warning with its archive
+// path: malformed XHTML, links that do not resolve within the archive and,
+// with opts.A11y, accessibility audit failures. Problems with the book are
+// not errors; the error reports a file that is not a readable EPUB.
+func Validate(epubPath string, opts ValidateOptions) ([]model.Warning, error) {
+	r, err := zip.OpenReader(epubPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer r.Close()
+
+	opfPath, err := findPackageDocument(&r.Reader)
+	if err != nil {
+		return nil, err
+	}
+	opf, err := readZipFile(&r.Reader, opfPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading %s: %v", ErrInvalidPackage, opfPath, err)
+	}
+
+	files := make(map[string]bool, len(r.File))
+	for _, f := range r.File {
+		files[f.Name] = true
+	}
+
+	var issues []model.Warning
+	if opts.A11y && !dcLanguageRe.MatchString(opf) {
+		issues = append(issues, model.Warning{
+			Code:    model.WarnAccessibility,
+			File:    opfPath,
+			Message: fmt.Sprintf("%s: package has no dc:language (%s)", opfPath, ruleEPUBLang),
+		})
+	}
+
+	// Read the content documents in manifest order
+	var names []string
+	docs := make(map[string]string)
+	ids := make(map[string]map[string]bool)
+	for _, item := range manifestItemRe.FindAllString(opf, -1) {
+		if attrValue(mediaTypeAttrRe, item) != "application/xhtml+xml" {
+			continue
+		}
+		href := html.UnescapeString(attrValue(hrefAttrRe, item))
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+		name := path.Join(path.Dir(opfPath), href)
+		content, err := readZipFile(&r.Reader, name)
+		if err != nil {
+			issues = append(issues, model.Warning{
+				Code:    model.WarnBrokenLink,
+				File:    opfPath,
+				Message: fmt.Sprintf("Manifest item %s in %s: no such file in the book", href, opfPath),
+			})
+			continue
+		}
+		names = append(names, name)
+		docs[name] = content
+		ids[name] = documentIDs(content)
+	}
+
+	for _, name := range names {
+		doc := docs[name]
+		if err := checkWellFormed(doc); err != nil {
+			issues = append(issues, model.Warning{
+				Code:    model.WarnMalformedXHTML,
+				File:    name,
+				Message: fmt.Sprintf("%s is not well-formed XHTML: %s", name, err),
+			})
+		}
+		for _, l := range documentLinks(name, doc, files, ids) {
+			issues = append(issues, model.Warning{
+				Code:    model.WarnBrokenLink,
+				File:    name,
+				Message: fmt.Sprintf("Link %s in %s: %s", l.href, name, l.reason),
+			})
+		}
+		if opts.A11y {
+			issues = append(issues, auditA11y(name, doc)...)
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,112 @@
+package epub
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestAuditA11y(t *testing.T) {
+	doc := `<html xmlns="http://www.w3.org/1999/xhtml"><body>
+<h1>One</h1><h3>Skipped</h3><h2>Fine</h2><h3>Fine</h3><h1>Back</h1>
+<p><a href="#a"></a> <a href="#b"> </a> <a href="#c">Text</a> <a href="#d" aria-label="Named"></a>
+<a href="#e"><img src="icon.png" alt="Icon"/></a> <a id="anchor"></a></p>
+<img src="a.png"/><img src="b.png" alt=""/><img src="c.png" role="presentation"/>
+<table><tr><th>H</th></tr><tr><td>1</td></tr></table>
+<table><tr><td>1</td></tr></table>
+<table role="presentation"><tr><td>1</td></tr></table>
+</body></html>`
+
+	var messages []string
+	for _, issue := range auditA11y("content/chapter-001.xhtml", doc) {
+		assert.Equal(t, model.WarnAccessibility, issue.Code)
+		assert.Equal(t, "content/chapter-001.xhtml", issue.File)
+		messages = append(messages, issue.Message)
+	}
+	assert.Equal(t, []string{
+		"content/chapter-001.xhtml: document has no xml:lang or lang attribute (html-has-lang)",
+		"content/chapter-001.xhtml: heading h3 follows h1, skipping a level (heading-order)",
+		"content/chapter-001.xhtml: link to #a has no text (link-name)",
+		"content/chapter-001.xhtml: link to #b has no text (link-name)",
+		"content/chapter-001.xhtml: image a.png has no alt attribute (image-alt)",
+		"content/chapter-001.xhtml: table 2 has no header cells (td-has-header)",
+	}, messages)
+
+	assert.Empty(t, auditA11y("nav.xhtml", `<html xml:lang="en" lang="en"><body><h2>Contents</h2></body></html>`))
+}
+
+func TestBuilder_Build_CheckA11y(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Test Book"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  `<h1>Chapter 1</h1><h4>Deep</h4>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	doc.TOC.Entries = []model.TOCEntry{{Title: "Chapter 1", Href: "content/chapter-001.xhtml", Level: 1}}
+
+	var events []model.Warning
+	builder := NewBuilder()
+	builder.SetOptions(Options{CheckA11y: true, Warn: func(e model.Warning) { events = append(events, e) }})
+	data, err := builder.Build(doc)
+	require.NoError(t, err)
+
+	// Generated documents declare the book language
+	assert.Contains(t, readZipEntry(t, data, "OEBPS/content/chapter-001.xhtml"), `xml:lang="en" lang="en"`)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0].Message, "heading h4 follows h1")
+}
+
+func TestValidate(t *testing.T) {
+	doc := model.NewDocument()
+	doc.Metadata.Title = "Test Book"
+	doc.AddChapter(model.Chapter{
+		ID:       "ch1",
+		Title:    "Chapter 1",
+		Content:  `<h1 id="top">Chapter 1</h1><p><a href="#top">Top</a> <a href="#gone">Gone</a> <a href="chapter-009.xhtml"></a></p>`,
+		FileName: "content/chapter-001.xhtml",
+	})
+	data, err := NewBuilder().Build(doc)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	issues, err := Validate(path, ValidateOptions{})
+	require.NoError(t, err)
+	var messages []string
+	for _, issue := range issues {
+		assert.Equal(t, model.WarnBrokenLink, issue.Code)
+		messages = append(messages, issue.Message)
+	}
+	assert.Equal(t, []string{
+		`Link #gone in OEBPS/content/chapter-001.xhtml: no element with id "gone"`,
+		`Link chapter-009.xhtml in OEBPS/content/chapter-001.xhtml: no such file in the book`,
+	}, messages)
+
+	issues, err = Validate(path, ValidateOptions{A11y: true})
+	require.NoError(t, err)
+	require.Len(t, issues, 3)
+	assert.Equal(t, model.WarnAccessibility, issues[2].Code)
+	assert.Contains(t, issues[2].Message, "link to chapter-009.xhtml has no text")
+}
+
+func TestValidate_NotEPUB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "book.epub")
+	require.NoError(t, os.WriteFile(path, []byte("not a zip"), 0o644))
+	_, err := Validate(path, ValidateOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPackage)
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, zip.NewWriter(f).Close())
+	require.NoError(t, f.Close())
+	_, err = Validate(path, ValidateOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPackage)
+}
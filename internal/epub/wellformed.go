@@ -0,0 +1,69 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package epub
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// checkWellFormed parses an XHTML document as XML, returning the first
+// error with its line. HTML entities such as &nbsp; are errors, as XHTML
+// without a DTD defines only the five XML entities.
+func checkWellFormed(doc string) error {
+	d := xml.NewDecoder(strings.NewReader(doc))
+	for {
+		_, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var syntaxErr *xml.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("line %d: %s", syntaxErr.Line, syntaxErr.Msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// verify checks that a generated XHTML document is well-formed before it
+// is written. Malformed documents fail the build with Options.Strict and
+// are otherwise reported through Options.Warn and written anyway.
+func (b *Builder) verify(name, doc string) error {
+	if err := checkWellFormed(doc); err != nil {
+		return b.malformed(name, err)
+	}
+	return nil
+}
+
+// malformed reports that the document name is not well-formed, failing
+// with ErrMalformedXHTML under Options.Strict.
+func (b *Builder) malformed(name string, err error) error {
+	if b.opts.Strict {
+		return fmt.Errorf("%w: %s %s", ErrMalformedXHTML, name, err)
+	}
+	b.warn(model.Warning{
+		Code:    model.WarnMalformedXHTML,
+		File:    name,
+		Message: fmt.Sprintf("%s is not well-formed XHTML: %s", name, err),
+	})
+	return nil
+}
+
+// warn reports event through Options.Warn, if set.
+func (b *Builder) warn(event model.Warning) {
+	if b.opts.Warn != nil {
+		b.opts.Warn(event)
+	}
+}
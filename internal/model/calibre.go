@@ -0,0 +1,37 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import "regexp"
+
+// Calibre custom column datatypes.
+const (
+	CalibreText     = "text"     // Text, or tags-like text with several values
+	CalibreBool     = "bool"     // Yes/No
+	CalibreInt      = "int"      // Integer
+	CalibreFloat    = "float"    // Floating point number
+	CalibreDatetime = "datetime" // Date
+)
+
+// calibreLabelRe matches a Calibre column lookup name, without the "#".
+var calibreLabelRe = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CalibreColumn is the value of a Calibre custom column, written as a
+// calibre:user_metadata meta so that Calibre fills the column of the same
+// lookup name when the book is added to a library.
+type CalibreColumn struct {
+	Label    string   // Lookup name without the "#" (e.g., "genre")
+	Datatype string   // CalibreText, CalibreBool, CalibreInt, CalibreFloat, or CalibreDatetime
+	Values   []string // Column value; several values for a text column that holds a list
+}
+
+// ValidCalibreLabel reports whether label is a valid Calibre lookup name:
+// lowercase letters, digits, and underscores, starting with a letter.
+func ValidCalibreLabel(label string) bool {
+	return calibreLabelRe.MatchString(label)
+}
@@ -0,0 +1,32 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+// Collection types (collection-type refinement).
+const (
+	CollectionSeries = "series" // A sequence of related works, such as volumes
+	CollectionSet    = "set"    // A finite group of works published together
+)
+
+// Collection is a series or other collection the book belongs to,
+// written as a belongs-to-collection meta.
+type Collection struct {
+	Name     string // Collection title
+	Type     string // CollectionSeries, CollectionSet, or empty for unspecified
+	Position string // Position within the collection (e.g., "2" or "2.5"), optional
+}
+
+// Series returns the first series the book belongs to.
+func (m *Metadata) Series() (Collection, bool) {
+	for _, c := range m.Collections {
+		if c.Type == CollectionSeries {
+			return c, true
+		}
+	}
+	return Collection{}, false
+}
@@ -0,0 +1,85 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import "strings"
+
+// MARC relator codes for common contributor roles.
+const (
+	RoleAuthor      = "aut"
+	RoleEditor      = "edt"
+	RoleTranslator  = "trl"
+	RoleIllustrator = "ill"
+	RoleNarrator    = "nrt"
+)
+
+// relatorCodes maps human-readable role names to MARC relator codes.
+var relatorCodes = map[string]string{
+	"author":      RoleAuthor,
+	"editor":      RoleEditor,
+	"translator":  RoleTranslator,
+	"illustrator": RoleIllustrator,
+	"narrator":    RoleNarrator,
+}
+
+// marcRelators are the MARC relator codes accepted as roles, those most
+// used for books, from https://id.loc.gov/vocabulary/relators.
+var marcRelators = map[string]bool{
+	"abr": true, "act": true, "adp": true, "aft": true, "ann": true, "ant": true,
+	"arr": true, "art": true, "aui": true, "aut": true, "bkd": true, "bkp": true,
+	"ccp": true, "chr": true, "clb": true, "cmm": true, "cmp": true, "cnd": true,
+	"col": true, "com": true, "cov": true, "cre": true, "csl": true, "ctb": true,
+	"ctg": true, "cwt": true, "drt": true, "dsr": true, "dte": true, "dto": true,
+	"edc": true, "edt": true, "egr": true, "fac": true, "fmo": true, "ill": true,
+	"ilu": true, "ins": true, "isb": true, "itr": true, "ive": true, "ivr": true,
+	"lbt": true, "lyr": true, "mdc": true, "mrk": true, "mus": true, "nrt": true,
+	"oth": true, "own": true, "pat": true, "pbl": true, "pfr": true, "pht": true,
+	"prf": true, "prg": true, "pro": true, "prt": true, "red": true, "rev": true,
+	"sds": true, "sng": true, "spk": true, "spn": true, "stl": true, "trc": true,
+	"trl": true, "tyd": true, "tyg": true, "wac": true, "wam": true, "wat": true,
+	"win": true, "wpr": true, "wst": true,
+}
+
+// Contributor is a person who contributed to the book in a specific role.
+type Contributor struct {
+	Name string // Display name
+	Role string // MARC relator code (e.g., "edt", "trl")
+}
+
+// ContributorRoles returns the role names recognized in front matter and flags.
+func ContributorRoles() []string {
+	return []string{"editor", "translator", "illustrator", "narrator"}
+}
+
+// RelatorCode normalizes a role name or code to a MARC relator code,
+// returning "" for roles that are neither.
+func RelatorCode(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if code, ok := relatorCodes[role]; ok {
+		return code
+	}
+	if marcRelators[role] {
+		return role
+	}
+	return ""
+}
+
+// ParseContributor parses a "role:Name" string into a Contributor.
+// Returns false if the role is not recognized.
+func ParseContributor(s string) (Contributor, bool) {
+	role, name, ok := strings.Cut(s, ":")
+	if !ok {
+		return Contributor{}, false
+	}
+	code := RelatorCode(role)
+	name = strings.TrimSpace(name)
+	if code == "" || name == "" {
+		return Contributor{}, false
+	}
+	return Contributor{Name: name, Role: code}, true
+}
@@ -14,10 +14,13 @@ import "time"
 // It serves as the intermediate representation between input parsers
 // and the EPUB builder.
 type Document struct {
-	Metadata  Metadata        // Book publication information
-	Chapters  []Chapter       // Content chapters in reading order
-	Resources []Resource      // Embedded media files (images, stylesheets)
-	TOC       TableOfContents // Navigation hierarchy
+	Metadata       Metadata        // Book publication information
+	Chapters       []Chapter       // Content chapters in reading order
+	Resources      []Resource      // Embedded media files (images, stylesheets)
+	TOC            TableOfContents // Navigation hierarchy
+	References     []Reference     // Bibliography entries for citations
+	Glossary       []GlossaryEntry // Terms for the generated glossary
+	Pronunciations []Pronunciation // Terms marked for text-to-speech
 }
 
 // NewDocument creates a new Document with initialized slices.
@@ -53,6 +56,11 @@ type Chapter struct {
 	Content  string // XHTML content
 	FileName string // Output filename (e.g., "chapter-01.xhtml")
 	Order    int    // Reading order position in spine
+	Spread   string // Fixed-layout page spread: "left", "right", "center" or empty
+	Type     string // Body epub:type (e.g., "frontmatter"); empty means "bodymatter"
+	Language string // Language of the content document (BCP 47); empty means the book language
+
+	Stylesheets []string // Additional stylesheet paths within EPUB (e.g., "styles/intro.css")
 }
 
 // Resource represents an embedded media file (image, stylesheet, font).
@@ -63,28 +71,40 @@ type Resource struct {
 	Data       []byte // File contents
 	IsCover    bool   // True if this is the cover image
 	SourcePath string // Original source file path for loading data
+	Fallback   string // ID of the resource to show where this one's format is unsupported
 }
 
 // ConversionResult contains the outcome of a conversion operation.
 type ConversionResult struct {
-	Success    bool             // True if conversion completed successfully
-	OutputPath string           // Path to generated EPUB file
-	Warnings   []string         // Non-fatal issues encountered
-	Error      error            // Fatal error if Success is false
-	Stats      ConversionStats  // Conversion metrics
+	Success    bool            // True if conversion completed successfully
+	OutputPath string          // Path to generated EPUB file
+	Warnings   []Warning       // Non-fatal issues encountered
+	Error      error           // Fatal error if Success is false
+	Stats      ConversionStats // Conversion metrics
 }
 
 // ConversionStats contains metrics about the conversion process.
 type ConversionStats struct {
-	InputFormat  string        // Source format: "markdown", "html", "pdf"
-	InputFiles   int           // Number of input files processed
-	ChapterCount int           // Number of chapters generated
-	ImageCount   int           // Number of images embedded
-	OutputSize   int64         // EPUB file size in bytes
-	Duration     time.Duration // Processing time
+	InputFormat  string         // Source format: "markdown", "html", "pdf"
+	InputFiles   int            // Number of input files processed
+	ChapterCount int            // Number of chapters generated
+	ImageCount   int            // Number of images embedded
+	OutputSize   int64          // EPUB file size in bytes
+	Duration     time.Duration  // Processing time
+	Resources    []ResourceSize // Resources largest first, listed when a size limit is set
+}
+
+// ResourceSize is the size of one resource in the package.
+type ResourceSize struct {
+	File string // Path within the package, e.g. "images/photo.png"
+	Size int64  // Size in bytes before compression
 }
 
-// AddWarning appends a warning message to the result.
-func (r *ConversionResult) AddWarning(msg string) {
-	r.Warnings = append(r.Warnings, msg)
+// AddWarning appends a warning to the result, defaulting its severity to
+// SeverityWarning.
+func (r *ConversionResult) AddWarning(w Warning) {
+	if w.Severity == "" {
+		w.Severity = SeverityWarning
+	}
+	r.Warnings = append(r.Warnings, w)
 }
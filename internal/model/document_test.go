@@ -64,6 +64,16 @@ func TestMetadata_Merge(t *testing.T) {
 	assert.Contains(t, base.Authors, "Author 2")
 }
 
+func TestMetadata_Merge_Subjects(t *testing.T) {
+	base := &Metadata{Subjects: []string{"Old"}}
+
+	base.Merge(&Metadata{})
+	assert.Equal(t, []string{"Old"}, base.Subjects)
+
+	base.Merge(&Metadata{Subjects: []string{"New", "Other"}})
+	assert.Equal(t, []string{"New", "Other"}, base.Subjects)
+}
+
 func TestMetadata_Merge_EmptyOverride(t *testing.T) {
 	base := &Metadata{
 		Title:    "Original",
@@ -119,3 +129,70 @@ func TestChapter_Properties(t *testing.T) {
 	assert.Contains(t, chapter.FileName, "chapter-001")
 	assert.Equal(t, 0, chapter.Order)
 }
+
+func TestParseIdentifier(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Identifier
+	}{
+		{"isbn:9780306406157", Identifier{Scheme: SchemeISBN, Value: "9780306406157"}},
+		{"DOI:10.1000/182", Identifier{Scheme: SchemeDOI, Value: "10.1000/182"}},
+		{"urn:uuid:1234", Identifier{Scheme: SchemeUUID, Value: "urn:uuid:1234"}},
+		{"URN:ISBN:9780306406157", Identifier{Scheme: SchemeISBN, Value: "URN:ISBN:9780306406157"}},
+		{"urn:doi:10.1000/182", Identifier{Scheme: SchemeDOI, Value: "urn:doi:10.1000/182"}},
+		{"urn:isbn", Identifier{Value: "urn:isbn"}},
+		{"https://doi.org/10.1000/182", Identifier{Scheme: SchemeURI, Value: "https://doi.org/10.1000/182"}},
+		{"http://example.com/books/1", Identifier{Scheme: SchemeURI, Value: "http://example.com/books/1"}},
+		{"http:no-slashes", Identifier{Scheme: "http", Value: "no-slashes"}},
+		{"plain-id", Identifier{Value: "plain-id"}},
+		{"a b:c", Identifier{Value: "a b:c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseIdentifier(tt.input))
+		})
+	}
+}
+
+func TestMetadata_CheckUniqueID(t *testing.T) {
+	meta := NewMetadata()
+	assert.NoError(t, meta.CheckUniqueID())
+
+	meta.AddIdentifier(ParseIdentifier("urn:isbn:9780306406157"))
+	meta.UniqueID = SchemeISBN
+	assert.NoError(t, meta.CheckUniqueID())
+
+	meta.UniqueID = SchemeDOI
+	assert.ErrorIs(t, meta.CheckUniqueID(), ErrUnknownUniqueID)
+}
+
+func TestMetadata_EnsureIdentifier_UniqueScheme(t *testing.T) {
+	meta := NewMetadata()
+	meta.AddIdentifier(Identifier{Scheme: SchemeDOI, Value: "10.1000/182"})
+	meta.AddIdentifier(Identifier{Scheme: SchemeISBN, Value: "9780306406157"})
+	meta.UniqueID = SchemeISBN
+
+	meta.EnsureIdentifier()
+
+	assert.Equal(t, "9780306406157", meta.Identifier)
+	assert.Equal(t, SchemeISBN, meta.UniqueIdentifierScheme())
+	assert.Equal(t, []Identifier{{Scheme: SchemeDOI, Value: "10.1000/182"}}, meta.OtherIdentifiers())
+}
+
+func TestTableOfContents_Truncate(t *testing.T) {
+	toc := BuildFromHeadings([]TOCEntry{
+		{Title: "Chapter 1", Href: "ch1.xhtml", Level: 1},
+		{Title: "Section 1.1", Href: "ch1.xhtml#s1", Level: 2},
+		{Title: "Detail 1.1.1", Href: "ch1.xhtml#d1", Level: 3},
+		{Title: "Chapter 2", Href: "ch2.xhtml", Level: 1},
+	})
+
+	limited := toc.Truncate(2)
+
+	assert.Len(t, limited.FlatEntries(), 3)
+	assert.Empty(t, limited.Entries[0].Children[0].Children)
+	// The original TOC is unchanged
+	assert.Len(t, toc.FlatEntries(), 4)
+
+	assert.Same(t, toc, toc.Truncate(0))
+}
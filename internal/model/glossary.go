@@ -0,0 +1,28 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import "strings"
+
+// GlossaryEntry is a term defined in the book's glossary.
+type GlossaryEntry struct {
+	Term       string // Term as written (plain text)
+	Definition string // Definition as XHTML
+}
+
+// AddGlossaryEntry appends a glossary entry, replacing any existing entry
+// for the same term. Terms are compared case-insensitively.
+func (d *Document) AddGlossaryEntry(entry GlossaryEntry) {
+	for i, existing := range d.Glossary {
+		if strings.EqualFold(existing.Term, entry.Term) {
+			d.Glossary[i] = entry
+			return
+		}
+	}
+	d.Glossary = append(d.Glossary, entry)
+}
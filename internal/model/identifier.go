@@ -0,0 +1,214 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Identifier schemes with known dc:identifier refinements.
+const (
+	SchemeISBN = "isbn"
+	SchemeDOI  = "doi"
+	SchemeUUID = "uuid"
+	SchemeURI  = "uri" // http(s) URLs, such as https://doi.org/10.1000/182
+)
+
+// ISBNPrefix starts the URN form in which ISBNs are written.
+const ISBNPrefix = "urn:isbn:"
+
+// ErrInvalidISBN is returned for an ISBN that is malformed or whose check
+// digit is wrong.
+var ErrInvalidISBN = errors.New("invalid ISBN")
+
+// ErrUnknownUniqueID is returned when the unique-identifier scheme asked
+// for names none of the book's identifiers.
+var ErrUnknownUniqueID = errors.New("no identifier has the unique-id scheme")
+
+// isbnLabelRe matches the label or URN prefix an ISBN may be written with.
+var isbnLabelRe = regexp.MustCompile(`(?i)^(urn:isbn:|isbn(-1[03])?:?)\s*`)
+
+// Identifier is a dc:identifier value qualified by its scheme.
+type Identifier struct {
+	Scheme string // Identifier scheme (e.g., "isbn", "doi", "uuid")
+	Value  string // Identifier value (e.g., "9780306406157")
+}
+
+// ParseIdentifier parses a "scheme:value" string into an Identifier.
+// URNs such as "urn:isbn:9780306406157" take their namespace as the scheme
+// and are kept whole, as are http(s) URLs, whose scheme is SchemeURI.
+// Values without a recognizable scheme prefix are returned with an empty
+// scheme.
+func ParseIdentifier(s string) Identifier {
+	s = strings.TrimSpace(s)
+	scheme, value, ok := strings.Cut(s, ":")
+	if !ok || scheme == "" || value == "" || strings.ContainsAny(scheme, " /") {
+		return Identifier{Value: s}
+	}
+
+	scheme = strings.ToLower(scheme)
+	switch {
+	case scheme == "urn":
+		if nid, nss, ok := strings.Cut(value, ":"); ok && nid != "" && nss != "" {
+			return Identifier{Scheme: strings.ToLower(nid), Value: s}
+		}
+		return Identifier{Value: s}
+	case (scheme == "http" || scheme == "https") && strings.HasPrefix(value, "//"):
+		return Identifier{Scheme: SchemeURI, Value: s}
+	}
+	return Identifier{Scheme: scheme, Value: strings.TrimSpace(value)}
+}
+
+// AddIdentifier appends an identifier, replacing any existing one with the same scheme.
+func (m *Metadata) AddIdentifier(id Identifier) {
+	if id.Value == "" {
+		return
+	}
+	for i, existing := range m.Identifiers {
+		if id.Scheme != "" && existing.Scheme == id.Scheme {
+			m.Identifiers[i] = id
+			return
+		}
+	}
+	m.Identifiers = append(m.Identifiers, id)
+}
+
+// IdentifierByScheme returns the identifier for a scheme and whether it exists.
+func (m *Metadata) IdentifierByScheme(scheme string) (Identifier, bool) {
+	scheme = strings.ToLower(scheme)
+	for _, id := range m.Identifiers {
+		if id.Scheme == scheme {
+			return id, true
+		}
+	}
+	return Identifier{}, false
+}
+
+// CheckUniqueID checks that an identifier has the scheme UniqueID asks
+// for, if it is set.
+func (m *Metadata) CheckUniqueID() error {
+	if m.UniqueID == "" {
+		return nil
+	}
+	if _, ok := m.IdentifierByScheme(m.UniqueID); !ok {
+		return fmt.Errorf("%w %q", ErrUnknownUniqueID, m.UniqueID)
+	}
+	return nil
+}
+
+// UniqueIdentifierScheme returns the scheme of the unique identifier.
+func (m *Metadata) UniqueIdentifierScheme() string {
+	for _, id := range m.Identifiers {
+		if id.Value == m.Identifier {
+			return id.Scheme
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(m.Identifier), "urn:uuid:") {
+		return SchemeUUID
+	}
+	return ""
+}
+
+// OtherIdentifiers returns all identifiers except the unique identifier.
+func (m *Metadata) OtherIdentifiers() []Identifier {
+	result := make([]Identifier, 0, len(m.Identifiers))
+	for _, id := range m.Identifiers {
+		if id.Value != m.Identifier {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// NormalizeISBN checks an ISBN-10 or ISBN-13, written with or without
+// hyphens, spaces, a label such as "ISBN-13:", or the urn:isbn: prefix,
+// and returns it as a URN of its digits, e.g. "urn:isbn:9780306406157".
+func NormalizeISBN(s string) (string, error) {
+	value := strings.ToUpper(isbnLabelRe.ReplaceAllString(strings.TrimSpace(s), ""))
+
+	var digits strings.Builder
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9', r == 'X':
+			digits.WriteRune(r)
+		case r == '-' || r == ' ':
+		default:
+			return "", fmt.Errorf("%w %q: unexpected character %q", ErrInvalidISBN, s, r)
+		}
+	}
+
+	isbn := digits.String()
+	switch len(isbn) {
+	case 10:
+		if !validISBN10(isbn) {
+			return "", fmt.Errorf("%w %q: wrong check digit", ErrInvalidISBN, s)
+		}
+	case 13:
+		if !validISBN13(isbn) {
+			return "", fmt.Errorf("%w %q: wrong check digit", ErrInvalidISBN, s)
+		}
+	default:
+		return "", fmt.Errorf("%w %q: must have 10 or 13 digits", ErrInvalidISBN, s)
+	}
+	return ISBNPrefix + isbn, nil
+}
+
+// validISBN10 checks the mod 11 check digit of ten ISBN characters, where
+// only the last may be X for 10.
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		digit := int(r - '0')
+		if r == 'X' {
+			if i != 9 {
+				return false
+			}
+			digit = 10
+		}
+		sum += (10 - i) * digit
+	}
+	return sum%11 == 0
+}
+
+// validISBN13 checks the mod 10 check digit of thirteen ISBN digits.
+func validISBN13(isbn string) bool {
+	sum := 0
+	for i, r := range isbn {
+		if r == 'X' {
+			return false
+		}
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += weight * int(r-'0')
+	}
+	return sum%10 == 0
+}
+
+// NormalizeISBNs checks the book's ISBN identifiers and rewrites them, and
+// the unique identifier if it is one, as urn:isbn: URNs.
+func (m *Metadata) NormalizeISBNs() error {
+	for i, id := range m.Identifiers {
+		if id.Scheme != SchemeISBN {
+			continue
+		}
+		isbn, err := NormalizeISBN(id.Value)
+		if err != nil {
+			return err
+		}
+		if m.Identifier == id.Value {
+			m.Identifier = isbn
+		}
+		m.Identifiers[i].Value = isbn
+	}
+	return nil
+}
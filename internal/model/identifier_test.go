@@ -0,0 +1,53 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeISBN(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9780306406157", "urn:isbn:9780306406157"},
+		{"978-0-306-40615-7", "urn:isbn:9780306406157"},
+		{"ISBN 978 0 306 40615 7", "urn:isbn:9780306406157"},
+		{"ISBN-13: 978-0-306-40615-7", "urn:isbn:9780306406157"},
+		{"urn:isbn:9780306406157", "urn:isbn:9780306406157"},
+		{"0-306-40615-2", "urn:isbn:0306406152"},
+		{"0-8044-2957-x", "urn:isbn:080442957X"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			isbn, err := NormalizeISBN(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, isbn)
+		})
+	}
+
+	for _, input := range []string{"978-0-306-40615-8", "0-306-40615-3", "12345", "978-0-306-4061X-7", "978/0306406157", ""} {
+		_, err := NormalizeISBN(input)
+		assert.ErrorIs(t, err, ErrInvalidISBN, input)
+	}
+}
+
+func TestMetadata_NormalizeISBNs(t *testing.T) {
+	meta := NewMetadata()
+	meta.AddIdentifier(Identifier{Scheme: SchemeISBN, Value: "978-0-306-40615-7"})
+	meta.AddIdentifier(Identifier{Scheme: SchemeDOI, Value: "10.1000/182"})
+	meta.Identifier = "978-0-306-40615-7"
+
+	require.NoError(t, meta.NormalizeISBNs())
+	assert.Equal(t, "urn:isbn:9780306406157", meta.Identifier)
+	assert.Equal(t, []Identifier{
+		{Scheme: SchemeISBN, Value: "urn:isbn:9780306406157"},
+		{Scheme: SchemeDOI, Value: "10.1000/182"},
+	}, meta.Identifiers)
+	assert.Equal(t, SchemeISBN, meta.UniqueIdentifierScheme())
+
+	meta.AddIdentifier(Identifier{Scheme: SchemeISBN, Value: "978-0-306-40615-8"})
+	assert.ErrorIs(t, meta.NormalizeISBNs(), ErrInvalidISBN)
+}
@@ -0,0 +1,79 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LicenseAllRightsReserved is the preset for books under full copyright.
+const LicenseAllRightsReserved = "all-rights-reserved"
+
+// License is a license preset, expanded into the rights statement of a book
+// and, for Creative Commons licenses, a link to the license deed.
+type License struct {
+	ID    string // Preset name (e.g., "cc-by-4.0")
+	Name  string // Full license name
+	Short string // Abbreviated name (e.g., "CC BY 4.0")
+	URL   string // License deed, or "" for all rights reserved
+}
+
+// licenses lists the license presets.
+var licenses = []License{
+	{"cc-by-4.0", "Creative Commons Attribution 4.0 International", "CC BY 4.0", "https://creativecommons.org/licenses/by/4.0/"},
+	{"cc-by-sa-4.0", "Creative Commons Attribution-ShareAlike 4.0 International", "CC BY-SA 4.0", "https://creativecommons.org/licenses/by-sa/4.0/"},
+	{"cc-by-nc-4.0", "Creative Commons Attribution-NonCommercial 4.0 International", "CC BY-NC 4.0", "https://creativecommons.org/licenses/by-nc/4.0/"},
+	{"cc-by-nc-sa-4.0", "Creative Commons Attribution-NonCommercial-ShareAlike 4.0 International", "CC BY-NC-SA 4.0", "https://creativecommons.org/licenses/by-nc-sa/4.0/"},
+	{"cc-by-nd-4.0", "Creative Commons Attribution-NoDerivatives 4.0 International", "CC BY-ND 4.0", "https://creativecommons.org/licenses/by-nd/4.0/"},
+	{"cc-by-nc-nd-4.0", "Creative Commons Attribution-NonCommercial-NoDerivatives 4.0 International", "CC BY-NC-ND 4.0", "https://creativecommons.org/licenses/by-nc-nd/4.0/"},
+	{"cc0-1.0", "CC0 1.0 Universal", "CC0 1.0", "https://creativecommons.org/publicdomain/zero/1.0/"},
+	{LicenseAllRightsReserved, "All rights reserved", "", ""},
+}
+
+// LicenseNames returns the names of the license presets.
+func LicenseNames() []string {
+	names := make([]string, 0, len(licenses))
+	for _, l := range licenses {
+		names = append(names, l.ID)
+	}
+	return names
+}
+
+// LookupLicense returns the license preset with the given name, ignoring
+// case. Creative Commons presets may omit their version, as in "cc-by-sa".
+func LookupLicense(name string) (License, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, l := range licenses {
+		if name == l.ID || (l.URL != "" && name == l.ID[:strings.LastIndex(l.ID, "-")]) {
+			return l, true
+		}
+	}
+	return License{}, false
+}
+
+// Statement returns the rights statement for a work by holder, first
+// published in year.
+func (l License) Statement(holder string, year int) string {
+	switch {
+	case l.ID == LicenseAllRightsReserved:
+		return fmt.Sprintf("© %d %s. All rights reserved.", year, holder)
+	case strings.HasPrefix(l.ID, "cc0"):
+		return fmt.Sprintf("%s has dedicated this work to the public domain under the %s dedication (%s).", holder, l.Name, l.Short)
+	default:
+		return fmt.Sprintf("© %d %s. This work is licensed under the %s License (%s).", year, holder, l.Name, l.Short)
+	}
+}
+
+// LicenseInfo returns the license preset of the book, if it has one.
+func (m *Metadata) LicenseInfo() (License, bool) {
+	if m.License == "" {
+		return License{}, false
+	}
+	return LookupLicense(m.License)
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupLicense(t *testing.T) {
+	for _, name := range []string{"cc-by-sa-4.0", "cc-by-sa", "CC-BY-SA"} {
+		license, ok := LookupLicense(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, "cc-by-sa-4.0", license.ID, name)
+	}
+
+	license, ok := LookupLicense("all-rights-reserved")
+	assert.True(t, ok)
+	assert.Empty(t, license.URL)
+
+	for _, name := range []string{"", "all-rights", "gpl", "cc-by-4"} {
+		_, ok := LookupLicense(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestMetadata_EnsureDefaults_License(t *testing.T) {
+	meta := NewMetadata()
+	meta.Authors = []string{"Jane Doe"}
+	meta.Date = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	meta.License = "cc-by-4.0"
+	meta.EnsureDefaults()
+	assert.Equal(t, "© 2024 Jane Doe. This work is licensed under the Creative Commons Attribution 4.0 International License (CC BY 4.0).", meta.Rights)
+
+	// An explicit rights statement is kept
+	meta = NewMetadata()
+	meta.Rights = "Public domain."
+	meta.License = LicenseAllRightsReserved
+	meta.EnsureDefaults()
+	assert.Equal(t, "Public domain.", meta.Rights)
+
+	// A license override replaces the source statement
+	meta = NewMetadata()
+	meta.Rights = "© 2020 Old Holder."
+	meta.Merge(&Metadata{License: LicenseAllRightsReserved})
+	meta.Authors = []string{"Jane Doe"}
+	meta.Date = time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	meta.EnsureDefaults()
+	assert.Equal(t, "© 2024 Jane Doe. All rights reserved.", meta.Rights)
+}
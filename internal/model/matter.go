@@ -0,0 +1,78 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Divisions of a book, written as the epub:type of a chapter's body.
+const (
+	FrontMatter = "frontmatter"
+	BodyMatter  = "bodymatter"
+	BackMatter  = "backmatter"
+)
+
+// chapterTypes maps the structural types a chapter may declare to the
+// division that holds them.
+var chapterTypes = map[string]string{
+	FrontMatter: FrontMatter, BodyMatter: BodyMatter, BackMatter: BackMatter,
+
+	"cover": FrontMatter, "titlepage": FrontMatter, "halftitlepage": FrontMatter,
+	"copyright-page": FrontMatter, "seriespage": FrontMatter, "dedication": FrontMatter,
+	"epigraph": FrontMatter, "abstract": FrontMatter, "foreword": FrontMatter,
+	"preface": FrontMatter, "acknowledgments": FrontMatter,
+
+	"introduction": BodyMatter, "prologue": BodyMatter, "preamble": BodyMatter,
+	"part": BodyMatter, "chapter": BodyMatter, "epilogue": BodyMatter, "conclusion": BodyMatter,
+
+	"afterword": BackMatter, "appendix": BackMatter, "credits": BackMatter,
+	"contributors": BackMatter, "errata": BackMatter, "colophon": BackMatter,
+}
+
+// ParseChapterType validates a declared chapter type, such as "preface"
+// or "appendix", returning the body epub:type for it: the division, then
+// the type, as in "frontmatter preface".
+func ParseChapterType(s string) (string, error) {
+	t := strings.ToLower(strings.TrimSpace(s))
+	division, ok := chapterTypes[t]
+	if !ok {
+		return "", fmt.Errorf("unknown chapter type %q", s)
+	}
+	if t == division {
+		return t, nil
+	}
+	return division + " " + t, nil
+}
+
+// Matter returns the division holding the chapter: FrontMatter, BodyMatter,
+// or BackMatter. Chapters without a type are body matter; chapters whose
+// type names no division, as some imported EPUBs use, return "".
+func (c Chapter) Matter() string {
+	if c.Type == "" {
+		return BodyMatter
+	}
+	for _, t := range strings.Fields(c.Type) {
+		if division, ok := chapterTypes[t]; ok {
+			return division
+		}
+	}
+	return ""
+}
+
+// Semantic returns the structural type the chapter declares beyond its
+// division, such as "preface", or "" if it has none.
+func (c Chapter) Semantic() string {
+	for _, t := range strings.Fields(c.Type) {
+		if _, ok := chapterTypes[t]; ok && t != FrontMatter && t != BodyMatter && t != BackMatter {
+			return t
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// audioMediaTypes maps audio file extensions to EPUB 3 core media types.
+var audioMediaTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/mp4",
+	".opus": "audio/ogg; codecs=opus",
+}
+
+// videoMediaTypes maps video file extensions to media types.
+var videoMediaTypes = map[string]string{
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".webm": "video/webm",
+}
+
+// imageMediaTypes maps image file extensions to media types.
+var imageMediaTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+	".svg":  "image/svg+xml",
+}
+
+// AudioMediaType returns the EPUB core media type for an audio file name,
+// or an empty string if the extension is not a supported audio format.
+func AudioMediaType(name string) string {
+	return audioMediaTypes[strings.ToLower(filepath.Ext(name))]
+}
+
+// VideoMediaType returns the media type for a video file name,
+// or an empty string if the extension is not a supported video format.
+func VideoMediaType(name string) string {
+	return videoMediaTypes[strings.ToLower(filepath.Ext(name))]
+}
+
+// ImageMediaType returns the media type for an image file name,
+// or an empty string if the extension is not a supported image format.
+func ImageMediaType(name string) string {
+	return imageMediaTypes[strings.ToLower(filepath.Ext(name))]
+}
+
+// IsAudio returns true if the resource is an audio file.
+func (r *Resource) IsAudio() bool {
+	return strings.HasPrefix(r.MediaType, "audio/")
+}
+
+// IsVideo returns true if the resource is a video file.
+func (r *Resource) IsVideo() bool {
+	return strings.HasPrefix(r.MediaType, "video/")
+}
@@ -8,6 +8,8 @@
 package model
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,15 +17,94 @@ import (
 
 // Metadata contains Dublin Core metadata for the EPUB package document.
 type Metadata struct {
-	Title       string    // dc:title (required)
-	Authors     []string  // dc:creator (can be multiple)
-	Language    string    // dc:language (BCP 47, e.g., "en", "en-US")
-	Identifier  string    // dc:identifier (UUID or ISBN)
-	Description string    // dc:description
-	Publisher   string    // dc:publisher
-	Date        time.Time // dc:date (publication date)
-	Rights      string    // dc:rights
-	CoverImage  string    // Path to cover image resource
+	Title        string        // dc:title (required)
+	Authors      []string      // dc:creator (can be multiple)
+	Contributors []Contributor // dc:contributor with MARC relator roles
+	Language     string        // dc:language (BCP 47, e.g., "en", "en-US")
+	Languages    []string      // Further dc:language values of a multilingual book
+	Identifier   string        // dc:identifier used as the unique-identifier (UUID or ISBN)
+	Description  string        // dc:description
+	Publisher    string        // dc:publisher
+	Date         time.Time     // dc:date (publication date)
+	Rights       string        // dc:rights
+	License      string        // License preset (e.g., "cc-by-4.0"), expanded into Rights if unset
+	Subjects     []string      // dc:subject (keywords or subject headings)
+	Collections  []Collection  // Series and collections the book belongs to
+	CoverImage   string        // Path to cover image resource
+	Rendition    Rendition     // EPUB rendition properties (layout, spreads)
+	Direction    string        // Reading direction: "ltr", "rtl" or empty (default)
+
+	Identifiers []Identifier // All dc:identifier values with schemes (ISBN, DOI, UUID)
+	UniqueID    string       // Scheme of the identifier to use as unique-identifier
+
+	Properties  []Property              // Further DC elements and meta properties (e.g., dc:coverage, dcterms:issued)
+	Refinements map[string][]Refinement // Refinements of authors and contributors, by name (e.g., file-as)
+
+	CalibreColumns []CalibreColumn // Calibre custom column values
+}
+
+// Reading direction values (page-progression-direction).
+const (
+	DirectionLTR = "ltr"
+	DirectionRTL = "rtl"
+)
+
+// ParseDirection checks a reading direction: "ltr", "rtl", or "default",
+// which leaves it to the reading system and, like "", gives "".
+func ParseDirection(s string) (string, error) {
+	switch dir := strings.ToLower(strings.TrimSpace(s)); dir {
+	case "", "default":
+		return "", nil
+	case DirectionLTR, DirectionRTL:
+		return dir, nil
+	default:
+		return "", fmt.Errorf("invalid direction %q: must be ltr, rtl or default", s)
+	}
+}
+
+// Rendition layout values (rendition:layout).
+const (
+	LayoutReflowable   = "reflowable"
+	LayoutPrePaginated = "pre-paginated"
+)
+
+// Default viewport size used for pre-paginated books when none is given.
+const (
+	DefaultViewportWidth  = 1200
+	DefaultViewportHeight = 1600
+)
+
+// Rendition holds the EPUB 3 fixed-layout rendering properties.
+type Rendition struct {
+	Layout         string // rendition:layout ("reflowable" or "pre-paginated")
+	Orientation    string // rendition:orientation ("auto", "landscape", "portrait")
+	Spread         string // rendition:spread ("none", "landscape", "both", "auto")
+	ViewportWidth  int    // Page width in CSS pixels for fixed-layout pages
+	ViewportHeight int    // Page height in CSS pixels for fixed-layout pages
+}
+
+// FixedLayout returns true if the book uses pre-paginated layout.
+func (r Rendition) FixedLayout() bool {
+	return r.Layout == LayoutPrePaginated
+}
+
+// Merge combines two Rendition values, with override taking precedence.
+func (r *Rendition) Merge(override Rendition) {
+	if override.Layout != "" {
+		r.Layout = override.Layout
+	}
+	if override.Orientation != "" {
+		r.Orientation = override.Orientation
+	}
+	if override.Spread != "" {
+		r.Spread = override.Spread
+	}
+	if override.ViewportWidth > 0 {
+		r.ViewportWidth = override.ViewportWidth
+	}
+	if override.ViewportHeight > 0 {
+		r.ViewportHeight = override.ViewportHeight
+	}
 }
 
 // NewMetadata creates a new Metadata with default values.
@@ -34,8 +115,17 @@ func NewMetadata() *Metadata {
 	}
 }
 
-// EnsureIdentifier generates a UUID identifier if not already set.
+// EnsureIdentifier selects the unique identifier, generating a UUID
+// identifier if none is available.
 func (m *Metadata) EnsureIdentifier() {
+	if m.UniqueID != "" {
+		if id, ok := m.IdentifierByScheme(m.UniqueID); ok {
+			m.Identifier = id.Value
+		}
+	}
+	if m.Identifier == "" && len(m.Identifiers) > 0 {
+		m.Identifier = m.Identifiers[0].Value
+	}
 	if m.Identifier == "" {
 		m.Identifier = "urn:uuid:" + uuid.New().String()
 	}
@@ -53,6 +143,17 @@ func (m *Metadata) EnsureDefaults() {
 	if m.Date.IsZero() {
 		m.Date = time.Now()
 	}
+	if license, ok := m.LicenseInfo(); ok && m.Rights == "" {
+		m.Rights = license.Statement(strings.Join(m.Authors, ", "), m.Date.Year())
+	}
+	if m.Rendition.FixedLayout() {
+		if m.Rendition.ViewportWidth <= 0 {
+			m.Rendition.ViewportWidth = DefaultViewportWidth
+		}
+		if m.Rendition.ViewportHeight <= 0 {
+			m.Rendition.ViewportHeight = DefaultViewportHeight
+		}
+	}
 }
 
 // Merge combines two Metadata objects, with override taking precedence.
@@ -67,9 +168,15 @@ func (m *Metadata) Merge(override *Metadata) {
 	if len(override.Authors) > 0 {
 		m.Authors = override.Authors
 	}
+	if len(override.Contributors) > 0 {
+		m.Contributors = override.Contributors
+	}
 	if override.Language != "" {
 		m.Language = override.Language
 	}
+	if len(override.Languages) > 0 {
+		m.Languages = override.Languages
+	}
 	if override.Identifier != "" {
 		m.Identifier = override.Identifier
 	}
@@ -85,9 +192,53 @@ func (m *Metadata) Merge(override *Metadata) {
 	if override.Rights != "" {
 		m.Rights = override.Rights
 	}
+	if override.License != "" {
+		// A new license replaces a source rights statement it would contradict
+		m.License = override.License
+		m.Rights = override.Rights
+	}
+	if len(override.Subjects) > 0 {
+		m.Subjects = override.Subjects
+	}
+	if len(override.Collections) > 0 {
+		m.Collections = override.Collections
+	}
 	if override.CoverImage != "" {
 		m.CoverImage = override.CoverImage
 	}
+	m.Rendition.Merge(override.Rendition)
+	if override.Direction != "" {
+		m.Direction = override.Direction
+	}
+	for _, id := range override.Identifiers {
+		m.AddIdentifier(id)
+	}
+	if override.UniqueID != "" {
+		m.UniqueID = override.UniqueID
+	}
+	if len(override.Properties) > 0 {
+		m.Properties = override.Properties
+	}
+	if len(override.Refinements) > 0 {
+		m.Refinements = override.Refinements
+	}
+	if len(override.CalibreColumns) > 0 {
+		m.CalibreColumns = override.CalibreColumns
+	}
+}
+
+// AddLanguage adds a further language of the book, unless it is already
+// one of its languages.
+func (m *Metadata) AddLanguage(lang string) {
+	if lang == "" || strings.EqualFold(lang, m.Language) {
+		return
+	}
+	for _, existing := range m.Languages {
+		if strings.EqualFold(lang, existing) {
+			return
+		}
+	}
+	m.Languages = append(m.Languages, lang)
 }
 
 // Valid checks if required metadata fields are present.
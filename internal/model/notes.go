@@ -0,0 +1,33 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Note placements, choosing where footnotes appear in the book.
+const (
+	NotesPopup   = "popup"   // Footnote asides, hidden by reading systems that show them as pop-ups
+	NotesChapter = "chapter" // A numbered list of endnotes at the end of each chapter
+	NotesBook    = "book"    // Endnotes gathered into a notes chapter at the end of the book
+)
+
+// ParseNotePlacement validates a note placement, returning NotesPopup for
+// an empty string.
+func ParseNotePlacement(s string) (string, error) {
+	switch p := strings.ToLower(s); p {
+	case "":
+		return NotesPopup, nil
+	case NotesPopup, NotesChapter, NotesBook:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown note placement %q", s)
+	}
+}
@@ -0,0 +1,35 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+// PLSMediaType is the media type of PLS pronunciation lexicons.
+const PLSMediaType = "application/pls+xml"
+
+// Pronunciation is how text-to-speech should say a term of the book.
+type Pronunciation struct {
+	Term    string // Term as written (plain text)
+	Phoneme string // Pronunciation in the International Phonetic Alphabet
+}
+
+// AddPronunciation appends a pronunciation, replacing any existing one for
+// the same term. Terms are compared exactly, as "Reading" and "reading" may
+// be said differently.
+func (d *Document) AddPronunciation(p Pronunciation) {
+	for i, existing := range d.Pronunciations {
+		if existing.Term == p.Term {
+			d.Pronunciations[i] = p
+			return
+		}
+	}
+	d.Pronunciations = append(d.Pronunciations, p)
+}
+
+// IsLexicon returns true if the resource is a PLS pronunciation lexicon.
+func (r *Resource) IsLexicon() bool {
+	return r.MediaType == PLSMediaType
+}
@@ -0,0 +1,50 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import "strings"
+
+// Refinement is a meta element refining another metadata element, such as
+// the file-as sort name of a creator.
+type Refinement struct {
+	Property string // Refining property (e.g., "file-as", "alternate-script")
+	Value    string
+}
+
+// Property is a package metadata entry beyond those toepub writes itself:
+// a Dublin Core element such as "dc:coverage", or a meta property with a
+// reserved prefix such as "dcterms:issued" or "schema:accessMode".
+type Property struct {
+	Name        string       // Prefixed name (e.g., "dc:coverage", "dcterms:issued")
+	Value       string       // Element text
+	Refinements []Refinement // Meta elements refining this entry
+}
+
+// IsDC reports whether the property is a Dublin Core element, written as
+// a dc:* element rather than a meta element.
+func (p Property) IsDC() bool {
+	return strings.HasPrefix(p.Name, "dc:")
+}
+
+// IsPropertyName reports whether a front matter key names a metadata
+// property: a prefix, a colon, and a name, as in "dcterms:issued".
+func IsPropertyName(key string) bool {
+	prefix, name, ok := strings.Cut(key, ":")
+	return ok && prefix != "" && name != "" && !strings.ContainsAny(key, " /")
+}
+
+// AddRefinement refines the author or contributor with the given name.
+func (m *Metadata) AddRefinement(name string, r Refinement) {
+	if name == "" || r.Property == "" || r.Value == "" {
+		return
+	}
+	if m.Refinements == nil {
+		m.Refinements = make(map[string][]Refinement)
+	}
+	m.Refinements[name] = append(m.Refinements[name], r)
+}
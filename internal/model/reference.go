@@ -0,0 +1,40 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+// Reference is a bibliography entry that citations can refer to by key.
+type Reference struct {
+	Key       string   // Citation key (e.g., "smith2020")
+	Type      string   // Entry type (e.g., "book", "article")
+	Authors   []Author // Authors in order
+	Title     string   // Work title
+	Container string   // Journal or book title containing the work
+	Publisher string   // Publisher name
+	Year      string   // Year of publication
+	Volume    string   // Volume number
+	Pages     string   // Page range
+	DOI       string   // Digital Object Identifier without resolver prefix
+	URL       string   // Web address
+}
+
+// Author is a person or organization credited on a reference.
+type Author struct {
+	Family string // Family name, or the full name of an organization
+	Given  string // Given names; empty for organizations
+}
+
+// AddReference appends a reference, replacing any existing one with the same key.
+func (d *Document) AddReference(ref Reference) {
+	for i, existing := range d.References {
+		if existing.Key == ref.Key {
+			d.References[i] = ref
+			return
+		}
+	}
+	d.References = append(d.References, ref)
+}
@@ -0,0 +1,64 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Values the rendition properties take.
+var (
+	Orientations = []string{"auto", "landscape", "portrait"}     // rendition:orientation
+	Spreads      = []string{"none", "landscape", "both", "auto"} // rendition:spread
+	PageSpreads  = []string{"left", "right", "center"}           // Chapter.Spread
+)
+
+// ParseLayout checks a rendition layout, reflowable or pre-paginated, for
+// which "fixed" is short. An empty value gives "".
+func ParseLayout(s string) (string, error) {
+	layout := strings.ToLower(strings.TrimSpace(s))
+	if layout == "fixed" {
+		return LayoutPrePaginated, nil
+	}
+	return renditionValue("layout", layout, LayoutReflowable, LayoutPrePaginated)
+}
+
+// ParseOrientation checks a rendition:orientation value.
+func ParseOrientation(s string) (string, error) {
+	return renditionValue("orientation", s, Orientations...)
+}
+
+// ParseSpread checks a rendition:spread value.
+func ParseSpread(s string) (string, error) {
+	return renditionValue("spread", s, Spreads...)
+}
+
+// ParsePageSpread checks the side of a spread a fixed-layout page is on.
+func ParsePageSpread(s string) (string, error) {
+	return renditionValue("page spread", s, PageSpreads...)
+}
+
+// ParseViewport parses a fixed-layout page size written as WIDTHxHEIGHT,
+// such as 1200x1600.
+func ParseViewport(s string) (width, height int, err error) {
+	if _, err := fmt.Sscanf(strings.ToLower(strings.TrimSpace(s)), "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid viewport %q: expected WIDTHxHEIGHT", s)
+	}
+	return width, height, nil
+}
+
+// renditionValue returns s in lower case if it is one of allowed or empty.
+func renditionValue(name, s string, allowed ...string) (string, error) {
+	value := strings.ToLower(strings.TrimSpace(s))
+	if value != "" && !slices.Contains(allowed, value) {
+		return "", fmt.Errorf("invalid %s %q: must be %s or %s", name, s, strings.Join(allowed[:len(allowed)-1], ", "), allowed[len(allowed)-1])
+	}
+	return value, nil
+}
@@ -46,6 +46,29 @@ func (t *TableOfContents) FlatEntries() []TOCEntry {
 	return result
 }
 
+// Truncate returns a copy of the table of contents limited to depth nesting
+// levels. Entries below the limit are dropped; a depth <= 0 keeps all levels.
+func (t *TableOfContents) Truncate(depth int) *TableOfContents {
+	if depth <= 0 {
+		return t
+	}
+	return &TableOfContents{Entries: truncateEntries(t.Entries, depth)}
+}
+
+// truncateEntries copies entries down to the given remaining depth.
+func truncateEntries(entries []TOCEntry, depth int) []TOCEntry {
+	result := make([]TOCEntry, 0, len(entries))
+	for _, entry := range entries {
+		if depth > 1 {
+			entry.Children = truncateEntries(entry.Children, depth-1)
+		} else {
+			entry.Children = nil
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
 // flattenEntry recursively flattens an entry and its children.
 func flattenEntry(entry TOCEntry) []TOCEntry {
 	result := []TOCEntry{entry}
@@ -0,0 +1,51 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package model
+
+// Severity levels of conversion warnings.
+const (
+	SeverityInfo    = "info"    // Worth knowing; the book is as requested
+	SeverityWarning = "warning" // Something requested is missing or changed
+)
+
+// Warning codes identify the kind of issue, so automation can filter
+// warnings without matching their messages.
+const (
+	WarnMissingImage      = "missing_image"       // An image could not be read or is not supported
+	WarnCoverImage        = "cover_image"         // The cover image could not be embedded
+	WarnMissingStylesheet = "missing_stylesheet"  // A linked stylesheet could not be read
+	WarnMissingMedia      = "missing_media"       // An audio, video, or script file was not found
+	WarnResourceNotLoaded = "resource_not_loaded" // A file resource was dropped from a stream conversion
+	WarnEncodingGuessed   = "encoding_guessed"    // Input that is not UTF-8 was decoded from another encoding
+	WarnRemoteResource    = "remote_resource"     // A remote image is linked rather than embedded
+	WarnMissingAltText    = "missing_alt_text"    // An image has no meaningful alt text
+	WarnMalformedXHTML    = "malformed_xhtml"     // A generated XHTML document is not well-formed
+	WarnBrokenLink        = "broken_link"         // A link points to a file or anchor not in the book
+	WarnAccessibility     = "accessibility"       // A document fails an accessibility audit rule
+	WarnDuplicateID       = "duplicate_id"        // Colliding file names or ids were renamed
+	WarnReadingOrder      = "reading_order"       // A chapter is out of order or missing from the navigation
+	WarnOutputSize        = "output_size"         // The EPUB is larger than the size limit
+	WarnEPUBCheck         = "epubcheck"           // epubcheck reported a problem in the written EPUB
+	WarnMathRender        = "math_render"         // An equation could not be rendered as an image
+	WarnPageSkipped       = "page_skipped"        // A linked web page could not be fetched or is not HTML
+	WarnInvalidMetadata   = "invalid_metadata"    // A metadata value is not valid and was ignored
+	WarnMissingCitation   = "missing_citation"    // A citation key is not in the bibliography
+)
+
+// Warning is a non-fatal issue found during conversion.
+type Warning struct {
+	Code     string // Kind of issue, one of the Warn constants
+	Severity string // SeverityInfo or SeverityWarning
+	File     string // Input or resource file the warning is about, if any
+	Message  string // Human-readable description
+}
+
+// String returns the warning message.
+func (w Warning) String() string {
+	return w.Message
+}
@@ -0,0 +1,306 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ErrUnsupportedBibliography is returned for bibliography files that are
+// neither BibTeX (.bib) nor CSL-JSON (.json).
+var ErrUnsupportedBibliography = errors.New("unsupported bibliography format")
+
+// LoadBibliography reads references from a BibTeX or CSL-JSON file,
+// selected by file extension.
+func LoadBibliography(path string) ([]model.Reference, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBibliography(path, data)
+}
+
+// ParseBibliography parses the references of a BibTeX or CSL-JSON file
+// read from path, selected by file extension.
+func ParseBibliography(path string, data []byte) ([]model.Reference, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".bib", ".bibtex":
+		return ParseBibTeX(data)
+	case ".json":
+		return ParseCSLJSON(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBibliography, path)
+	}
+}
+
+// cslEntry is a CSL-JSON item. Only fields used for formatting are decoded.
+type cslEntry struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Title     string      `json:"title"`
+	Container string      `json:"container-title"`
+	Publisher string      `json:"publisher"`
+	Volume    json.Number `json:"volume"`
+	Page      string      `json:"page"`
+	DOI       string      `json:"DOI"`
+	URL       string      `json:"URL"`
+	Author    []cslName   `json:"author"`
+	Issued    cslDate     `json:"issued"`
+}
+
+// cslName is a CSL-JSON name variable.
+type cslName struct {
+	Family  string `json:"family"`
+	Given   string `json:"given"`
+	Literal string `json:"literal"`
+}
+
+// cslDate is a CSL-JSON date variable.
+type cslDate struct {
+	DateParts [][]json.Number `json:"date-parts"`
+	Literal   string          `json:"literal"`
+}
+
+// ParseCSLJSON parses a CSL-JSON array of references.
+func ParseCSLJSON(data []byte) ([]model.Reference, error) {
+	var entries []cslEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("parsing CSL-JSON: %w", err)
+	}
+
+	refs := make([]model.Reference, 0, len(entries))
+	for _, e := range entries {
+		if e.ID == "" {
+			continue
+		}
+		ref := model.Reference{
+			Key:       e.ID,
+			Type:      e.Type,
+			Title:     e.Title,
+			Container: e.Container,
+			Publisher: e.Publisher,
+			Volume:    e.Volume.String(),
+			Pages:     e.Page,
+			DOI:       e.DOI,
+			URL:       e.URL,
+			Year:      e.Issued.Literal,
+		}
+		if len(e.Issued.DateParts) > 0 && len(e.Issued.DateParts[0]) > 0 {
+			ref.Year = e.Issued.DateParts[0][0].String()
+		}
+		for _, name := range e.Author {
+			if name.Literal != "" {
+				ref.Authors = append(ref.Authors, model.Author{Family: name.Literal})
+				continue
+			}
+			ref.Authors = append(ref.Authors, model.Author{Family: name.Family, Given: name.Given})
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// Patterns for BibTeX parsing.
+var (
+	// bibEntryStartRe matches the start of a BibTeX entry, e.g. "@article{".
+	bibEntryStartRe = regexp.MustCompile(`@([A-Za-z]+)\s*[{(]`)
+
+	// bibNameSepRe matches the separator between names in a name list.
+	bibNameSepRe = regexp.MustCompile(`\s+and\s+`)
+)
+
+// ParseBibTeX parses BibTeX entries. @string, @preamble, and @comment
+// entries are skipped, and braces used for capitalization are removed.
+func ParseBibTeX(data []byte) ([]model.Reference, error) {
+	src := string(data)
+	var refs []model.Reference
+
+	for _, loc := range bibEntryStartRe.FindAllStringSubmatchIndex(src, -1) {
+		entryType := strings.ToLower(src[loc[2]:loc[3]])
+		if entryType == "string" || entryType == "preamble" || entryType == "comment" {
+			continue
+		}
+
+		body, ok := bibEntryBody(src, loc[1])
+		if !ok {
+			return nil, fmt.Errorf("parsing BibTeX: unterminated %s entry", entryType)
+		}
+
+		key, fields, _ := strings.Cut(body, ",")
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+
+		values := parseBibFields(fields)
+		ref := model.Reference{
+			Key:       key,
+			Type:      entryType,
+			Title:     values["title"],
+			Container: firstNonEmpty(values["journal"], values["booktitle"]),
+			Publisher: firstNonEmpty(values["publisher"], values["institution"], values["school"]),
+			Year:      values["year"],
+			Volume:    values["volume"],
+			Pages:     strings.ReplaceAll(values["pages"], "--", "–"),
+			DOI:       values["doi"],
+			URL:       values["url"],
+			Authors:   parseBibNames(values["author"]),
+		}
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// bibEntryBody returns the entry text from start up to the delimiter
+// closing the one just before start.
+func bibEntryBody(src string, start int) (string, bool) {
+	open, closing := byte('{'), byte('}')
+	if src[start-1] == '(' {
+		open, closing = '(', ')'
+	}
+
+	depth := 1
+	for i := start; i < len(src); i++ {
+		switch src[i] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return src[start:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// parseBibFields parses "name = value" pairs. Values may be braced, quoted,
+// or bare; raw values (with braces intact) are stored for name parsing.
+func parseBibFields(s string) map[string]string {
+	fields := make(map[string]string)
+	i := 0
+	for i < len(s) {
+		eq := strings.IndexByte(s[i:], '=')
+		if eq < 0 {
+			break
+		}
+		name := strings.ToLower(strings.TrimSpace(strings.Trim(strings.TrimSpace(s[i:i+eq]), ",")))
+		i += eq + 1
+		for i < len(s) && unicode.IsSpace(rune(s[i])) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+
+		var value string
+		switch s[i] {
+		case '{':
+			end := matchingBrace(s, i)
+			value = s[i+1 : end]
+			i = end + 1
+		case '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+			value = s[i+1 : i+1+end]
+			i += end + 2
+		default:
+			end := strings.IndexByte(s[i:], ',')
+			if end < 0 {
+				end = len(s) - i
+			}
+			value = s[i : i+end]
+			i += end
+		}
+		fields[name] = value
+	}
+
+	for name, value := range fields {
+		if name != "author" {
+			fields[name] = cleanBibValue(value)
+		}
+	}
+	return fields
+}
+
+// matchingBrace returns the index of the brace closing the one at open.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(s) - 1
+}
+
+// cleanBibValue removes BibTeX braces and collapses whitespace.
+func cleanBibValue(s string) string {
+	s = strings.NewReplacer("{", "", "}", "", "\\&", "&", "~", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// parseBibNames splits a BibTeX name list on " and ". Names in
+// "Family, Given" or "Given Family" form are supported; fully braced names
+// are treated as organizations.
+func parseBibNames(s string) []model.Author {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	var authors []model.Author
+	for _, name := range bibNameSepRe.Split(s, -1) {
+		name = strings.TrimSpace(name)
+		if strings.HasPrefix(name, "{") && strings.HasSuffix(name, "}") {
+			authors = append(authors, model.Author{Family: cleanBibValue(name)})
+			continue
+		}
+
+		name = cleanBibValue(name)
+		if family, given, ok := strings.Cut(name, ","); ok {
+			authors = append(authors, model.Author{Family: strings.TrimSpace(family), Given: strings.TrimSpace(given)})
+			continue
+		}
+		parts := strings.Fields(name)
+		if len(parts) == 0 {
+			continue
+		}
+		authors = append(authors, model.Author{
+			Family: parts[len(parts)-1],
+			Given:  strings.Join(parts[:len(parts)-1], " "),
+		})
+	}
+	return authors
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestParseBibTeX(t *testing.T) {
+	bib := `@comment{ignored}
+@string{acm = "ACM"}
+@book{knuth1984,
+  author    = {Knuth, Donald E.},
+  title     = {The {TeX}book (Volume A)},
+  publisher = {Addison-Wesley},
+  year      = 1984
+}
+@article{smith2020,
+  author  = "John Smith and {World Health Organization}",
+  title   = {On Things},
+  journal = {Journal of Stuff},
+  pages   = {1--10},
+  year    = {2020},
+  doi     = {10.1000/xyz}
+}`
+
+	refs, err := ParseBibTeX([]byte(bib))
+	require.NoError(t, err)
+	require.Len(t, refs, 2)
+
+	assert.Equal(t, "knuth1984", refs[0].Key)
+	assert.Equal(t, "book", refs[0].Type)
+	assert.Equal(t, "The TeXbook (Volume A)", refs[0].Title)
+	assert.Equal(t, "1984", refs[0].Year)
+	assert.Equal(t, []model.Author{{Family: "Knuth", Given: "Donald E."}}, refs[0].Authors)
+
+	assert.Equal(t, "Journal of Stuff", refs[1].Container)
+	assert.Equal(t, "1–10", refs[1].Pages)
+	assert.Equal(t, "10.1000/xyz", refs[1].DOI)
+	assert.Equal(t, []model.Author{
+		{Family: "Smith", Given: "John"},
+		{Family: "World Health Organization"},
+	}, refs[1].Authors)
+}
+
+func TestParseBibTeX_Unterminated(t *testing.T) {
+	_, err := ParseBibTeX([]byte(`@book{key, title = {Open`))
+	assert.Error(t, err)
+}
+
+func TestParseCSLJSON(t *testing.T) {
+	data := `[
+  {"id": "doe2019", "type": "article-journal", "title": "A Study",
+   "container-title": "Science", "volume": 3, "page": "5-9",
+   "author": [{"family": "Doe", "given": "Jane"}, {"literal": "ACME Lab"}],
+   "issued": {"date-parts": [[2019, 4]]}},
+  {"type": "book", "title": "No ID"}
+]`
+
+	refs, err := ParseCSLJSON([]byte(data))
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+
+	ref := refs[0]
+	assert.Equal(t, "doe2019", ref.Key)
+	assert.Equal(t, "2019", ref.Year)
+	assert.Equal(t, "3", ref.Volume)
+	assert.Equal(t, "Science", ref.Container)
+	assert.Equal(t, []model.Author{{Family: "Doe", Given: "Jane"}, {Family: "ACME Lab"}}, ref.Authors)
+}
+
+func TestLoadBibliography_UnsupportedFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "refs.ris")
+	require.NoError(t, os.WriteFile(path, []byte("TY  - BOOK"), 0644))
+
+	_, err := LoadBibliography(path)
+	assert.ErrorIs(t, err, ErrUnsupportedBibliography)
+}
+
+func TestConvertCitations(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"single", `<p>See [@knuth1984].</p>`, `<p>See <span class="citation">(<cite data-cite="knuth1984"></cite>)</span>.</p>`},
+		{"locator", `[@knuth1984, p. 12]`, `<span class="citation">(<cite data-cite="knuth1984" data-locator="p. 12"></cite>)</span>`},
+		{"multiple", `[@a; @b]`, `<span class="citation">(<cite data-cite="a"></cite>; <cite data-cite="b"></cite>)</span>`},
+		{"not a citation", `[see @a]`, `[see @a]`},
+		{"code", `<code>[@a]</code>`, `<code>[@a]</code>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, convertCitations(tt.content))
+		})
+	}
+}
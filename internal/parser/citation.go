@@ -0,0 +1,47 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// citationRe matches bracketed Markdown citations such as "[@smith2020]",
+// "[@smith2020, p. 12]", or "[@smith2020; @doe2019]".
+var citationRe = regexp.MustCompile(`\[(@[^\[\]]+)\]`)
+
+// convertCitations rewrites bracketed citations into <cite data-cite>
+// placeholders that the EPUB builder formats and links to the bibliography.
+// Brackets containing anything other than "@key" items are left unchanged.
+func convertCitations(content string) string {
+	return replaceOutsideCode(content, func(text string) string {
+		return citationRe.ReplaceAllStringFunc(text, func(match string) string {
+			items := strings.Split(citationRe.FindStringSubmatch(match)[1], ";")
+			cites := make([]string, 0, len(items))
+			for _, item := range items {
+				item = strings.TrimSpace(item)
+				if !strings.HasPrefix(item, "@") {
+					return match
+				}
+				key, locator, _ := strings.Cut(item[1:], ",")
+				key = strings.TrimSpace(key)
+				if key == "" || strings.ContainsAny(key, " \"'<>") {
+					return match
+				}
+
+				cite := `<cite data-cite="` + key + `"`
+				if locator = strings.TrimSpace(locator); locator != "" {
+					cite += ` data-locator="` + locator + `"`
+				}
+				cites = append(cites, cite+"></cite>")
+			}
+			return `<span class="citation">(` + strings.Join(cites, "; ") + ")</span>"
+		})
+	})
+}
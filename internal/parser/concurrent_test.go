@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// TestParsers_ConcurrentUse parses different documents with one parser of
+// each kind from many goroutines, as a Converter serving a batch does.
+func TestParsers_ConcurrentUse(t *testing.T) {
+	inputs := map[Parser]func(i int) string{
+		NewMarkdownParser(): func(i int) string {
+			return fmt.Sprintf("# Book %d\n\nText[^n] with ![img](img-%d.png).\n\n## Part %d\n\n[^n]: Note %d.\n", i, i, i, i)
+		},
+		NewHTMLParser(): func(i int) string {
+			return fmt.Sprintf("<html><body><h1>Book %d</h1><p>Text<br>with <img src=\"img-%d.png\"></p><h2>Part %d</h2></body></html>", i, i, i)
+		},
+	}
+
+	for p, input := range inputs {
+		serial := make([]*model.Document, 16)
+		for i := range serial {
+			doc, err := p.Parse([]byte(input(i)), ".")
+			require.NoError(t, err)
+			serial[i] = doc
+		}
+
+		concurrent := make([]*model.Document, len(serial))
+		var wg sync.WaitGroup
+		for i := range concurrent {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				doc, err := p.Parse([]byte(input(i)), ".")
+				assert.NoError(t, err)
+				concurrent[i] = doc
+			}()
+		}
+		wg.Wait()
+
+		for i := range serial {
+			assert.Equal(t, serial[i], concurrent[i], "document %d", i)
+		}
+	}
+}
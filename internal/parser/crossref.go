@@ -0,0 +1,56 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Patterns for figure and table labels and cross-references.
+var (
+	// labeledFigureRe matches a paragraph holding only an image followed by
+	// a "{#fig:label}" attribute, e.g. "![Caption](setup.png){#fig:setup}".
+	labeledFigureRe = regexp.MustCompile(`<p>(<img\b[^>]*\balt="([^"]*)"[^>]*/>)\{#(fig:[\w.:-]+)\}</p>`)
+
+	// labeledTableRe matches a table followed by a ": Caption {#tbl:label}"
+	// paragraph.
+	labeledTableRe = regexp.MustCompile(`(?s)<table>(.*?)</table>\s*<p>:\s*(.*?)\s*\{#(tbl:[\w.:-]+)\}</p>`)
+
+	// crossRefRe matches "@fig:label" and "@tbl:label" references that are not
+	// part of a word such as an email address.
+	crossRefRe = regexp.MustCompile(`(^|[^\w@])@((?:fig|tbl):[\w.:-]*\w)`)
+
+	// markupTagRe matches a single markup tag.
+	markupTagRe = regexp.MustCompile(`<[^>]*>`)
+)
+
+// convertLabels turns labeled Markdown images into numbered-figure
+// candidates (<figure id="fig:...">) and attaches captions and ids to
+// labeled tables. The EPUB builder assigns the numbers.
+func convertLabels(content string) string {
+	content = labeledFigureRe.ReplaceAllString(content, `<figure id="$3">$1<figcaption>$2</figcaption></figure>`)
+	return labeledTableRe.ReplaceAllString(content, `<table id="$3"><caption>$2</caption>$1</table>`)
+}
+
+// convertCrossRefs rewrites "@fig:label" and "@tbl:label" references into
+// <a data-ref> placeholders that the EPUB builder resolves across chapters.
+// References inside code or attribute values are not converted.
+func convertCrossRefs(content string) string {
+	return replaceOutsideCode(content, func(markup string) string {
+		var result strings.Builder
+		last := 0
+		for _, loc := range markupTagRe.FindAllStringIndex(markup, -1) {
+			result.WriteString(crossRefRe.ReplaceAllString(markup[last:loc[0]], `$1<a data-ref="$2"></a>`))
+			result.WriteString(markup[loc[0]:loc[1]])
+			last = loc[1]
+		}
+		result.WriteString(crossRefRe.ReplaceAllString(markup[last:], `$1<a data-ref="$2"></a>`))
+		return result.String()
+	})
+}
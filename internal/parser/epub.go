@@ -0,0 +1,303 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ErrInvalidEPUB is returned for archives that are not readable EPUB packages.
+var ErrInvalidEPUB = errors.New("invalid EPUB package")
+
+// generatedChapterIDs are chapters the EPUB builder adds without markers
+// in the sources. They are skipped so that converting extracted sources
+// again does not duplicate them.
+var generatedChapterIDs = map[string]bool{
+	"colophon": true,
+	"toc-page": true,
+}
+
+// Patterns used to read content documents.
+var (
+	bodyRe       = regexp.MustCompile(`(?is)<body\b([^>]*)>(.*)</body>`)
+	docTitleRe   = regexp.MustCompile(`(?is)<title\b[^>]*>(.*?)</title>`)
+	htmlLangRe   = regexp.MustCompile(`(?is)<html\b[^>]*?\s(?:xml:)?lang\s*=\s*["']([^"']+)["']`)
+	epubTypeRe   = regexp.MustCompile(`\bepub:type\s*=\s*["']([^"']*)["']`)
+	firstHeadRe  = regexp.MustCompile(`(?is)<h[1-6]\b[^>]*>(.*?)</h[1-6]>`)
+	wrapperRe    = regexp.MustCompile(`(?is)^<section\s+epub:type\s*=\s*["']([^"']+)["']\s*>(.*)</section>$`)
+	sectionTagRe = regexp.MustCompile(`(?i)<(/?)section\b[^>]*>`)
+	markupTextRe = regexp.MustCompile(`<[^>]*>`)
+)
+
+// epubContainer is META-INF/container.xml.
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+// epubPackage is the subset of the package document read by the parser.
+type epubPackage struct {
+	Metadata struct {
+		Titles      []string `xml:"title"`
+		Creators    []string `xml:"creator"`
+		Languages   []string `xml:"language"`
+		Identifiers []string `xml:"identifier"`
+		Publisher   string   `xml:"publisher"`
+		Description string   `xml:"description"`
+		Rights      string   `xml:"rights"`
+	} `xml:"metadata"`
+	Manifest []struct {
+		ID         string `xml:"id,attr"`
+		Href       string `xml:"href,attr"`
+		MediaType  string `xml:"media-type,attr"`
+		Properties string `xml:"properties,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// EPUBParser reads existing EPUB packages back into a Document. It is used
+// by the extract command to turn books into editable Markdown sources.
+type EPUBParser struct{}
+
+// NewEPUBParser creates a new EPUB parser.
+func NewEPUBParser() *EPUBParser {
+	return &EPUBParser{}
+}
+
+// Parse reads the metadata, spine chapters, and images of an EPUB archive.
+// Chapter file names and resource file names are paths relative to the
+// package document, as they appear in the manifest.
+func (p *EPUBParser) Parse(content []byte, basePath string) (*model.Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+	}
+
+	var container epubContainer
+	if err := decodeZipXML(zr, "META-INF/container.xml", &container); err != nil {
+		return nil, err
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, fmt.Errorf("%w: container.xml has no rootfile", ErrInvalidEPUB)
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg epubPackage
+	if err := decodeZipXML(zr, opfPath, &pkg); err != nil {
+		return nil, err
+	}
+	base := path.Dir(opfPath)
+
+	doc := model.NewDocument()
+	p.applyMetadata(doc, &pkg)
+
+	items := make(map[string]int, len(pkg.Manifest))
+	for i, item := range pkg.Manifest {
+		items[item.ID] = i
+	}
+
+	// Chapters in spine order
+	for _, ref := range pkg.Spine {
+		i, ok := items[ref.IDRef]
+		if !ok || generatedChapterIDs[ref.IDRef] {
+			continue
+		}
+		item := pkg.Manifest[i]
+		if item.MediaType != "application/xhtml+xml" || hasProperty(item.Properties, "nav") {
+			continue
+		}
+
+		data, err := readZipEntry(zr, resolveHref(base, item.Href))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", item.Href, err)
+		}
+		doc.AddChapter(p.parseContentDocument(string(data), item.ID, hrefPath(item.Href), len(doc.Chapters)))
+	}
+
+	// Images, including the cover
+	for _, item := range pkg.Manifest {
+		if !strings.HasPrefix(item.MediaType, "image/") {
+			continue
+		}
+		data, err := readZipEntry(zr, resolveHref(base, item.Href))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", item.Href, err)
+		}
+		doc.AddResource(model.Resource{
+			ID:        item.ID,
+			FileName:  hrefPath(item.Href),
+			MediaType: item.MediaType,
+			Data:      data,
+			IsCover:   hasProperty(item.Properties, "cover-image"),
+		})
+	}
+
+	return doc, nil
+}
+
+// SupportedExtensions returns file extensions this parser handles.
+func (p *EPUBParser) SupportedExtensions() []string {
+	return []string{".epub"}
+}
+
+// applyMetadata copies Dublin Core metadata from the package document.
+func (p *EPUBParser) applyMetadata(doc *model.Document, pkg *epubPackage) {
+	meta := &pkg.Metadata
+	if len(meta.Titles) > 0 {
+		doc.Metadata.Title = strings.TrimSpace(meta.Titles[0])
+	}
+	for _, creator := range meta.Creators {
+		if name := strings.TrimSpace(creator); name != "" {
+			doc.Metadata.Authors = append(doc.Metadata.Authors, name)
+		}
+	}
+	for i, lang := range meta.Languages {
+		if i == 0 {
+			doc.Metadata.Language = strings.TrimSpace(lang)
+		} else {
+			doc.Metadata.AddLanguage(strings.TrimSpace(lang))
+		}
+	}
+	for _, id := range meta.Identifiers {
+		doc.Metadata.AddIdentifier(model.ParseIdentifier(id))
+	}
+	doc.Metadata.Publisher = strings.TrimSpace(meta.Publisher)
+	doc.Metadata.Description = strings.TrimSpace(meta.Description)
+	doc.Metadata.Rights = strings.TrimSpace(meta.Rights)
+}
+
+// unwrapChapterSection removes the section wrapping the whole of a
+// chapter's content when it declares the chapter's structural type, as
+// written by the builder, moving the type onto the chapter.
+func unwrapChapterSection(chapter *model.Chapter) {
+	m := wrapperRe.FindStringSubmatch(chapter.Content)
+	if m == nil {
+		return
+	}
+	division := chapter.Matter()
+	if division == "" {
+		return
+	}
+	t, err := model.ParseChapterType(m[1])
+	if err != nil || !strings.HasPrefix(t, division) {
+		return
+	}
+
+	// The closing tag must belong to the wrapper, not a later sibling
+	depth := 0
+	for _, tag := range sectionTagRe.FindAllStringSubmatch(m[2], -1) {
+		if tag[1] == "" {
+			depth++
+		} else if depth--; depth < 0 {
+			return
+		}
+	}
+	if depth != 0 {
+		return
+	}
+
+	chapter.Content = strings.TrimSpace(m[2])
+	if t != model.BodyMatter+" chapter" {
+		chapter.Type = t
+	}
+}
+
+// parseContentDocument creates a chapter from the body of a content document.
+func (p *EPUBParser) parseContentDocument(content, id, fileName string, order int) model.Chapter {
+	chapter := model.Chapter{
+		ID:       id,
+		Level:    1,
+		Content:  content,
+		FileName: fileName,
+		Order:    order,
+	}
+
+	if m := bodyRe.FindStringSubmatch(content); m != nil {
+		chapter.Content = strings.TrimSpace(m[2])
+		if m := epubTypeRe.FindStringSubmatch(m[1]); m != nil && m[1] != "bodymatter" {
+			chapter.Type = m[1]
+		}
+		unwrapChapterSection(&chapter)
+	}
+
+	if m := htmlLangRe.FindStringSubmatch(content); m != nil {
+		chapter.Language = strings.TrimSpace(m[1])
+	}
+
+	if m := firstHeadRe.FindStringSubmatch(chapter.Content); m != nil {
+		chapter.Title = plainText(m[1])
+	} else if m := docTitleRe.FindStringSubmatch(content); m != nil {
+		chapter.Title = plainText(m[1])
+	}
+	return chapter
+}
+
+// plainText strips tags from an XHTML fragment and decodes entities.
+func plainText(s string) string {
+	return strings.TrimSpace(html.UnescapeString(markupTextRe.ReplaceAllString(s, "")))
+}
+
+// decodeZipXML decodes an XML archive entry into v.
+func decodeZipXML(zr *zip.Reader, name string, v interface{}) error {
+	data, err := readZipEntry(zr, name)
+	if err != nil {
+		return fmt.Errorf("%w: reading %s: %v", ErrInvalidEPUB, name, err)
+	}
+	if err := xml.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: parsing %s: %v", ErrInvalidEPUB, name, err)
+	}
+	return nil
+}
+
+// readZipEntry returns the contents of the named archive entry.
+func readZipEntry(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// hrefPath returns the decoded path part of a manifest href.
+func hrefPath(href string) string {
+	if u, err := url.Parse(href); err == nil {
+		return u.Path
+	}
+	return href
+}
+
+// resolveHref returns the archive path of a manifest href.
+func resolveHref(base, href string) string {
+	return path.Join(base, hrefPath(href))
+}
+
+// hasProperty reports whether a space-separated property list contains name.
+func hasProperty(properties, name string) bool {
+	for _, p := range strings.Fields(properties) {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
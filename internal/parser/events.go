@@ -0,0 +1,61 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// EventParser is implemented by parsers that report events found while
+// parsing, such as guessed encodings and skipped images. The converter
+// calls WithEvents once per conversion, so the returned parser must not
+// share the report function with other conversions.
+type EventParser interface {
+	WithEvents(report func(model.Warning)) Parser
+}
+
+// emit reports event if report is set.
+func emit(report func(model.Warning), event model.Warning) {
+	if report != nil {
+		report(event)
+	}
+}
+
+// decodeUTF8 returns content as UTF-8. Content that is not valid UTF-8 is
+// decoded with the encoding given by its byte order mark or, for HTML, its
+// meta charset; otherwise windows-1252 is guessed. Decoding is reported.
+func decodeUTF8(content []byte, contentType string, report func(model.Warning)) []byte {
+	if utf8.Valid(content) {
+		return content
+	}
+
+	enc, name, certain := charset.DetermineEncoding(content, contentType)
+	decoded, err := enc.NewDecoder().Bytes(content)
+	if err != nil {
+		// Leave the bytes for the parser to replace as invalid
+		return content
+	}
+
+	event := model.Warning{
+		Code:     model.WarnEncodingGuessed,
+		Severity: model.SeverityWarning,
+		Message:  fmt.Sprintf("Input is not UTF-8; guessed %s", name),
+	}
+	// Anything but the windows-1252 fallback came from a BOM or meta tag
+	if certain || name != "windows-1252" {
+		event.Severity = model.SeverityInfo
+		event.Message = fmt.Sprintf("Input decoded from its declared encoding %s", name)
+	}
+	emit(report, event)
+	return decoded
+}
@@ -0,0 +1,92 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestDecodeUTF8(t *testing.T) {
+	var events []model.Warning
+	report := func(w model.Warning) { events = append(events, w) }
+
+	// Valid UTF-8 is returned unchanged without an event
+	assert.Equal(t, "Café", string(decodeUTF8([]byte("Café"), "text/plain", report)))
+	assert.Empty(t, events)
+
+	assert.Equal(t, "Café", string(decodeUTF8([]byte("Caf\xe9"), "text/plain", report)))
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnEncodingGuessed, events[0].Code)
+	assert.Equal(t, model.SeverityWarning, events[0].Severity)
+
+	// A declared charset is used, and reported as a note
+	events = nil
+	html := "<html><head><meta charset=\"iso-8859-2\"></head><body>\xb1</body></html>"
+	assert.Contains(t, string(decodeUTF8([]byte(html), "text/html", report)), "ą")
+	require.Len(t, events, 1)
+	assert.Equal(t, model.SeverityInfo, events[0].Severity)
+	assert.Contains(t, events[0].Message, "iso-8859-2")
+}
+
+func TestMarkdownParser_WithEvents(t *testing.T) {
+	var events []model.Warning
+	p := NewMarkdownParser().WithEvents(func(w model.Warning) { events = append(events, w) })
+
+	md := "# Title\n\n![a](https://example.com/a.png) ![b](b.bmp) ![b](b.bmp)\n"
+	_, err := p.Parse([]byte(md), ".")
+	require.NoError(t, err)
+
+	// Remote images are left to the converter
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnMissingImage, events[0].Code)
+	assert.Equal(t, "b.bmp", events[0].File)
+}
+
+func TestHTMLParser_WithEvents_KeepsScriptPolicy(t *testing.T) {
+	policy := ScriptPolicy{Inline: true}
+	p := NewHTMLParser().WithScriptPolicy(policy).(*HTMLParser).WithEvents(func(model.Warning) {})
+	assert.Equal(t, policy, p.(*HTMLParser).scripts)
+}
+
+func TestMarkdownParser_Direction(t *testing.T) {
+	var events []model.Warning
+	p := NewMarkdownParser().WithEvents(func(w model.Warning) { events = append(events, w) })
+
+	doc, err := p.Parse([]byte("---\ndirection: RTL\n---\n# Title\n"), ".")
+	require.NoError(t, err)
+	assert.Equal(t, model.DirectionRTL, doc.Metadata.Direction)
+
+	doc, err = p.Parse([]byte("---\ndirection: default\n---\n# Title\n"), ".")
+	require.NoError(t, err)
+	assert.Empty(t, doc.Metadata.Direction)
+	assert.Empty(t, events)
+
+	// Values page-progression-direction does not allow are left out
+	doc, err = p.Parse([]byte("---\ndirection: up\n---\n# Title\n"), ".")
+	require.NoError(t, err)
+	assert.Empty(t, doc.Metadata.Direction)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnInvalidMetadata, events[0].Code)
+}
+
+func TestMarkdownParser_ContributorRoles(t *testing.T) {
+	var events []model.Warning
+	p := NewMarkdownParser().WithEvents(func(w model.Warning) { events = append(events, w) })
+
+	md := "---\ncontributors:\n  - {name: Ann, role: translator}\n  - {name: Bo, role: pfr}\n  - {name: Cy, role: abc}\n---\n# Title\n"
+	doc, err := p.Parse([]byte(md), ".")
+	require.NoError(t, err)
+	assert.Equal(t, []model.Contributor{
+		{Name: "Ann", Role: model.RoleTranslator},
+		{Name: "Bo", Role: "pfr"},
+	}, doc.Metadata.Contributors)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.WarnInvalidMetadata, events[0].Code)
+	assert.Contains(t, events[0].Message, `"abc"`)
+
+	_, ok := model.ParseContributor("xyz:Dee")
+	assert.False(t, ok, "unknown three-letter roles are not relator codes")
+}
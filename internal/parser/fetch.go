@@ -0,0 +1,35 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import "os"
+
+// Fetcher reads the files a document names, such as the bibliography and
+// glossary files of Markdown front matter or the caption files of a
+// gallery. A missing file is reported with an error matching
+// fs.ErrNotExist.
+type Fetcher interface {
+	Fetch(ref string) ([]byte, error)
+}
+
+// FetchParser is implemented by parsers that read the files a document
+// names. WithFetcher returns a copy of the parser reading them with
+// fetcher, leaving the receiver unchanged. With a nil fetcher the files
+// are not read and each is reported, as for content received as a stream
+// that must not reach local files.
+type FetchParser interface {
+	WithFetcher(fetcher Fetcher) Parser
+}
+
+// diskFetcher reads files from local disk, the default for parsers.
+type diskFetcher struct{}
+
+// Fetch reads the file at ref.
+func (diskFetcher) Fetch(ref string) ([]byte, error) {
+	return os.ReadFile(ref)
+}
@@ -0,0 +1,171 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// footnoteRendererPriority places the EPUB footnote renderer ahead of the
+// goldmark footnote renderer so its functions take precedence.
+const footnoteRendererPriority = 100
+
+// NotesParser is implemented by parsers that emit footnotes. WithNotes
+// returns a parser placing them as model.NotesPopup, model.NotesChapter, or
+// model.NotesBook asks, leaving the receiver unchanged.
+type NotesParser interface {
+	WithNotes(placement string) Parser
+}
+
+// epubFootnotes is a goldmark extension that renders footnote references as
+// epub:type="noteref" links and footnotes as epub:type="footnote" asides, so
+// reading systems can show them as pop-ups, or as a list of endnotes at the
+// end of the chapter for the other placements. Backlinks use goldmark's
+// renderer.
+type epubFootnotes struct {
+	notes string // Note placement
+}
+
+// Extend implements goldmark.Extender.
+func (e *epubFootnotes) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(
+		util.Prioritized(&footnoteRenderer{endnotes: e.notes != "" && e.notes != model.NotesPopup}, footnoteRendererPriority),
+	))
+}
+
+// footnoteRenderer renders footnote nodes with EPUB structural semantics.
+type footnoteRenderer struct {
+	endnotes bool // Render a list of endnotes instead of asides
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r *footnoteRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(east.KindFootnoteLink, r.renderFootnoteLink)
+	reg.Register(east.KindFootnote, r.renderFootnote)
+	reg.Register(east.KindFootnoteList, r.renderFootnoteList)
+}
+
+// renderFootnoteLink renders a reference as <sup><a epub:type="noteref">.
+func (r *footnoteRenderer) renderFootnoteLink(w util.BufWriter, _ []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	if !entering {
+		return gast.WalkContinue, nil
+	}
+	n := node.(*east.FootnoteLink)
+	is := strconv.Itoa(n.Index)
+
+	refID := "fnref"
+	if n.RefIndex > 0 {
+		refID += strconv.Itoa(n.RefIndex)
+	}
+
+	_, _ = w.WriteString(`<sup id="` + refID + ":" + is + `">`)
+	_, _ = w.WriteString(`<a epub:type="noteref" href="#fn:` + is + `" class="footnote-ref" role="doc-noteref">`)
+	_, _ = w.WriteString(is + "</a></sup>")
+	return gast.WalkContinue, nil
+}
+
+// renderFootnote renders a footnote body as <aside epub:type="footnote">,
+// or as <li epub:type="endnote"> for endnotes.
+func (r *footnoteRenderer) renderFootnote(w util.BufWriter, _ []byte, node gast.Node, entering bool) (gast.WalkStatus, error) {
+	n := node.(*east.Footnote)
+	switch {
+	case r.endnotes && entering:
+		_, _ = w.WriteString(`<li epub:type="endnote" id="fn:` + strconv.Itoa(n.Index) + `" role="doc-endnote">` + "\n")
+	case r.endnotes:
+		_, _ = w.WriteString("</li>\n")
+	case entering:
+		_, _ = w.WriteString(`<aside epub:type="footnote" id="fn:` + strconv.Itoa(n.Index) + `" role="doc-footnote">` + "\n")
+	default:
+		_, _ = w.WriteString("</aside>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+// renderFootnoteList renders the container for footnote asides or the
+// list of endnotes.
+func (r *footnoteRenderer) renderFootnoteList(w util.BufWriter, _ []byte, _ gast.Node, entering bool) (gast.WalkStatus, error) {
+	switch {
+	case r.endnotes && entering:
+		_, _ = w.WriteString(endnotesStart)
+	case r.endnotes:
+		_, _ = w.WriteString(endnotesEnd)
+	case entering:
+		_, _ = w.WriteString("<div class=\"footnotes\">\n<hr />\n")
+	default:
+		_, _ = w.WriteString("</div>\n")
+	}
+	return gast.WalkContinue, nil
+}
+
+// Markup around the endnotes at the end of a chapter.
+const (
+	endnotesStart = "<section class=\"footnotes\" epub:type=\"endnotes\" role=\"doc-endnotes\">\n<hr />\n<ol>\n"
+	endnotesEnd   = "</ol>\n</section>\n"
+)
+
+// Patterns for DPUB-ARIA footnote roles in HTML input.
+var (
+	noterefRe  = regexp.MustCompile(`<a\b([^>]*\brole=["']doc-noteref["'][^>]*)>`)
+	footnoteRe = regexp.MustCompile(`<aside\b([^>]*\brole=["']doc-footnote["'][^>]*)>`)
+
+	// footnoteAsideRe matches a footnote aside, capturing its attributes
+	// and content.
+	footnoteAsideRe = regexp.MustCompile(`(?s)<aside\b([^>]*\bepub:type=["']footnote["'][^>]*)>(.*?)</aside>\s*`)
+)
+
+// placeNotes moves the footnote asides of an XHTML fragment into a list of
+// endnotes at its end, unless placement keeps them as pop-up footnotes.
+func placeNotes(content, placement string) string {
+	if placement == "" || placement == model.NotesPopup {
+		return content
+	}
+
+	var notes strings.Builder
+	content = footnoteAsideRe.ReplaceAllStringFunc(content, func(aside string) string {
+		m := footnoteAsideRe.FindStringSubmatch(aside)
+		attrs := strings.NewReplacer(
+			`epub:type="footnote"`, `epub:type="endnote"`, `epub:type='footnote'`, `epub:type="endnote"`,
+			`role="doc-footnote"`, `role="doc-endnote"`, `role='doc-footnote'`, `role="doc-endnote"`,
+		).Replace(m[1])
+		notes.WriteString("<li" + attrs + ">" + m[2] + "</li>\n")
+		return ""
+	})
+	if notes.Len() == 0 {
+		return content
+	}
+	return content + "\n" + endnotesStart + notes.String() + endnotesEnd
+}
+
+// annotateFootnotes adds epub:type="noteref" and epub:type="footnote" to
+// HTML elements marked with the equivalent DPUB-ARIA roles.
+func annotateFootnotes(content string) string {
+	content = addEPUBType(noterefRe, content, "a", "noteref")
+	return addEPUBType(footnoteRe, content, "aside", "footnote")
+}
+
+// addEPUBType adds an epub:type attribute to elements matched by re that
+// do not already have one.
+func addEPUBType(re *regexp.Regexp, content, tag, epubType string) string {
+	return re.ReplaceAllStringFunc(content, func(start string) string {
+		attrs := re.FindStringSubmatch(start)[1]
+		if strings.Contains(attrs, "epub:type") {
+			return start
+		}
+		return "<" + tag + ` epub:type="` + epubType + `"` + attrs + ">"
+	})
+}
@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestMarkdownParser_WithNotes(t *testing.T) {
+	md := []byte("# One\n\nText[^a].\n\n[^a]: The note.\n")
+
+	doc, err := NewMarkdownParser().Parse(md, ".")
+	require.NoError(t, err)
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<aside epub:type="footnote" id="fn:1" role="doc-footnote">`)
+	assert.NotContains(t, content, "endnote")
+
+	for _, placement := range []string{model.NotesChapter, model.NotesBook} {
+		doc, err := NewMarkdownParser().WithNotes(placement).Parse(md, ".")
+		require.NoError(t, err)
+		content := doc.Chapters[0].Content
+		assert.Contains(t, content, `<a epub:type="noteref" href="#fn:1" class="footnote-ref" role="doc-noteref">1</a>`)
+		assert.Contains(t, content, `<section class="footnotes" epub:type="endnotes" role="doc-endnotes">`)
+		assert.Contains(t, content, `<li epub:type="endnote" id="fn:1" role="doc-endnote">`)
+		assert.NotContains(t, content, "<aside")
+	}
+}
+
+func TestHTMLParser_WithNotes(t *testing.T) {
+	html := `<html><body>
+    <h1>Notes</h1>
+    <p>Text<a href="#n1" role="doc-noteref">1</a></p>
+    <aside id="n1" role="doc-footnote"><p>The note.</p></aside>
+    <p>More text.</p>
+</body></html>`
+
+	doc, err := NewHTMLParser().WithNotes(model.NotesChapter).Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.NotContains(t, content, "<aside")
+	assert.Regexp(t, `(?s)More text\.</p>.*<section class="footnotes" epub:type="endnotes" role="doc-endnotes">\s*<hr />\s*<ol>\s*<li epub:type="endnote" id="n1" role="doc-endnote"><p>The note\.</p></li>\s*</ol>\s*</section>`, content)
+}
@@ -0,0 +1,124 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// galleryExtensions are the image file extensions made into gallery pages.
+var galleryExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true,
+	".webp": true, ".avif": true, ".heic": true, ".heif": true,
+}
+
+// IsGalleryImage reports whether the file name is an image that a gallery
+// can show.
+func IsGalleryImage(name string) bool {
+	return galleryExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// ParseGallery makes a photo book of the image files at paths, in order:
+// a chapter for each image, or for each page of perPage images laid out in
+// a grid. Each image is captioned with the text of a sidecar file named
+// after it, such as beach.txt for beach.jpg, or with a caption made from
+// its file name. Caption files are read with fetcher.
+func ParseGallery(paths []string, perPage int, fetcher Fetcher) (*model.Document, error) {
+	if perPage < 1 {
+		perPage = 1
+	}
+	doc := model.NewDocument()
+	names := newFileNamer("images")
+	images := NewMarkdownParser()
+
+	for start := 0; start < len(paths); start += perPage {
+		page := paths[start:min(start+perPage, len(paths))]
+
+		var content strings.Builder
+		var captions []string
+		if len(page) > 1 {
+			content.WriteString(`<div class="gallery-grid">` + "\n")
+		}
+		for _, path := range page {
+			caption, err := galleryCaption(fetcher, path)
+			if err != nil {
+				return nil, err
+			}
+			captions = append(captions, caption)
+			content.WriteString(`<figure class="gallery">` + "\n")
+			content.WriteString(`<img src="` + html.EscapeString(filepath.ToSlash(path)) + `" alt="` + html.EscapeString(caption) + `"/>` + "\n")
+			content.WriteString("<figcaption>" + html.EscapeString(caption) + "</figcaption>\n")
+			content.WriteString("</figure>\n")
+		}
+		if len(page) > 1 {
+			content.WriteString("</div>\n")
+		}
+
+		xhtml := content.String()
+		for _, img := range images.extractImageRefs(xhtml, "", names) {
+			doc.AddResource(img)
+		}
+
+		order := len(doc.Chapters)
+		chapter := model.Chapter{
+			ID:       fmt.Sprintf("chapter-%03d", order+1),
+			Title:    captions[0],
+			Level:    1,
+			Content:  images.rewriteImagePaths(xhtml, names),
+			FileName: fmt.Sprintf("content/chapter-%03d.xhtml", order+1),
+			Order:    order,
+		}
+		doc.AddChapter(chapter)
+		doc.TOC.AddEntry(model.TOCEntry{Title: chapter.Title, Href: chapter.FileName, Level: 1})
+	}
+	return doc, nil
+}
+
+// galleryCaption returns the caption of the image at path: the text of its
+// sidecar .txt file, read with fetcher, or one made from its file name
+// without a leading number, such as "Beach at Dawn" for 03-beach-at-dawn.jpg.
+func galleryCaption(fetcher Fetcher, path string) (string, error) {
+	stem := strings.TrimSuffix(path, filepath.Ext(path))
+	data, err := fetcher.Fetch(stem + ".txt")
+	if err == nil {
+		if caption := strings.Join(strings.Fields(string(data)), " "); caption != "" {
+			return caption, nil
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading caption: %w", err)
+	}
+
+	name := filepath.Base(stem)
+	if trimmed := strings.TrimLeftFunc(name, func(r rune) bool { return unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' || r == ' ' }); trimmed != "" {
+		name = trimmed
+	}
+	return TitleFromName(name), nil
+}
+
+// TitleFromName makes a title from a file name, such as "My Book" from
+// "my-book".
+func TitleFromName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' || r == '.' || unicode.IsSpace(r) })
+	for i, word := range words {
+		r := []rune(word)
+		r[0] = unicode.ToUpper(r[0])
+		words[i] = string(r)
+	}
+	if len(words) == 0 {
+		return "Untitled"
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGallery(t *testing.T) {
+	dir := t.TempDir()
+	var paths []string
+	for _, name := range []string{"01-beach-at-dawn.jpg", "old_tree.png", "IMG_0042.webp"} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte("image"), 0o644))
+		paths = append(paths, path)
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "IMG_0042.txt"), []byte("The harbour,\nfog & boats\n"), 0o644))
+
+	doc, err := ParseGallery(paths, 0, diskFetcher{})
+	require.NoError(t, err)
+	require.Len(t, doc.Chapters, 3)
+	require.Len(t, doc.Resources, 3)
+	assert.Equal(t, "Beach At Dawn", doc.Chapters[0].Title)
+	assert.Equal(t, "Old Tree", doc.Chapters[1].Title)
+	assert.Equal(t, "The harbour, fog & boats", doc.Chapters[2].Title)
+	assert.Equal(t, `<figure class="gallery">`+"\n"+
+		`<img src="../images/IMG_0042.webp" alt="The harbour, fog &amp; boats"/>`+"\n"+
+		"<figcaption>The harbour, fog &amp; boats</figcaption>\n</figure>\n", doc.Chapters[2].Content)
+	assert.Equal(t, filepath.Join(dir, "old_tree.png"), doc.Resources[1].SourcePath)
+	assert.Equal(t, "content/chapter-002.xhtml", doc.TOC.Entries[1].Href)
+
+	grid, err := ParseGallery(paths, 2, diskFetcher{})
+	require.NoError(t, err)
+	require.Len(t, grid.Chapters, 2)
+	assert.Contains(t, grid.Chapters[0].Content, `<div class="gallery-grid">`)
+	assert.Contains(t, grid.Chapters[0].Content, `alt="Old Tree"`)
+	assert.NotContains(t, grid.Chapters[1].Content, `gallery-grid`)
+}
+
+func TestTitleFromName(t *testing.T) {
+	assert.Equal(t, "My Book", TitleFromName("my-book"))
+	assert.Equal(t, "Field Notes 2", TitleFromName("field_notes 2"))
+	assert.Equal(t, "Untitled", TitleFromName("--"))
+}
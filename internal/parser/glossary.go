@@ -0,0 +1,90 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns used to find glossary definition lists.
+var (
+	// glossaryListRe matches a definition list introduced by a "glossary:"
+	// paragraph (Markdown) or marked with the glossary class or epub:type.
+	glossaryListRe = regexp.MustCompile(`(?is)<p>\s*glossary:\s*</p>\s*<dl\b[^>]*>(.*?)</dl>|<dl\b[^>]*\b(?:class|epub:type)\s*=\s*["'][^"']*\bglossary\b[^"']*["'][^>]*>(.*?)</dl>`)
+	glossaryItemRe = regexp.MustCompile(`(?is)<dt\b[^>]*>(.*?)</dt>\s*((?:<dd\b[^>]*>.*?</dd>\s*)+)`)
+	glossaryDefRe  = regexp.MustCompile(`(?is)<dd\b[^>]*>(.*?)</dd>`)
+	markupRe       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// LoadGlossary reads glossary entries from a YAML file mapping terms to
+// plain-text definitions. Entries are returned sorted by term.
+func LoadGlossary(path string) ([]model.GlossaryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGlossary(data)
+}
+
+// ParseGlossary parses glossary entries from YAML mapping terms to
+// plain-text definitions. Entries are returned sorted by term.
+func ParseGlossary(data []byte) ([]model.GlossaryEntry, error) {
+	var terms map[string]string
+	if err := yaml.Unmarshal(data, &terms); err != nil {
+		return nil, fmt.Errorf("parsing glossary: %w", err)
+	}
+
+	entries := make([]model.GlossaryEntry, 0, len(terms))
+	for term, definition := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		entries = append(entries, model.GlossaryEntry{
+			Term:       term,
+			Definition: html.EscapeString(strings.TrimSpace(definition)),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Term < entries[j].Term })
+	return entries, nil
+}
+
+// extractGlossaries removes glossary definition lists from content and
+// returns their entries. The builder collects all entries into a single
+// glossary chapter.
+func extractGlossaries(content string) (string, []model.GlossaryEntry) {
+	var entries []model.GlossaryEntry
+	content = replaceOutsideCode(content, func(text string) string {
+		return glossaryListRe.ReplaceAllStringFunc(text, func(list string) string {
+			m := glossaryListRe.FindStringSubmatch(list)
+			items := m[1] + m[2]
+			for _, item := range glossaryItemRe.FindAllStringSubmatch(items, -1) {
+				term := strings.TrimSpace(html.UnescapeString(markupRe.ReplaceAllString(item[1], "")))
+				if term == "" {
+					continue
+				}
+				var defs []string
+				for _, def := range glossaryDefRe.FindAllStringSubmatch(item[2], -1) {
+					defs = append(defs, strings.TrimSpace(def[1]))
+				}
+				entries = append(entries, model.GlossaryEntry{Term: term, Definition: strings.Join(defs, " ")})
+			}
+			return ""
+		})
+	})
+	return content, entries
+}
@@ -0,0 +1,53 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestLoadGlossary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glossary.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("EPUB: An e-book format.\nOPF: Open Packaging Format & more.\n"), 0o644))
+
+	entries, err := LoadGlossary(path)
+	require.NoError(t, err)
+	assert.Equal(t, []model.GlossaryEntry{
+		{Term: "EPUB", Definition: "An e-book format."},
+		{Term: "OPF", Definition: "Open Packaging Format &amp; more."},
+	}, entries)
+}
+
+func TestMarkdownParser_Parse_Glossary(t *testing.T) {
+	md := "# Terms\n\nSee the spine.\n\nglossary:\n\nSpine\n: The reading order.\n\nManifest\n: The list of *resources*.\n\nMore text.\n\nNot a glossary\n: Kept.\n"
+
+	doc, err := NewMarkdownParser().Parse([]byte(md), ".")
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.GlossaryEntry{
+		{Term: "Spine", Definition: "The reading order."},
+		{Term: "Manifest", Definition: "The list of <em>resources</em>."},
+	}, doc.Glossary)
+
+	content := doc.Chapters[0].Content
+	assert.NotContains(t, content, "glossary:")
+	assert.NotContains(t, content, "The reading order.")
+	assert.Contains(t, content, "<dt>Not a glossary</dt>")
+}
+
+func TestHTMLParser_Parse_Glossary(t *testing.T) {
+	html := `<html><body>
+    <h1>Terms</h1>
+    <dl class="glossary"><dt>Nav &amp; TOC</dt><dd>Navigation document.</dd></dl>
+</body></html>`
+
+	doc, err := NewHTMLParser().Parse([]byte(html), ".")
+	require.NoError(t, err)
+	assert.Equal(t, []model.GlossaryEntry{{Term: "Nav & TOC", Definition: "Navigation document."}}, doc.Glossary)
+	assert.NotContains(t, doc.Chapters[0].Content, "<dl")
+}
@@ -19,17 +19,76 @@ import (
 	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
-// HTMLParser parses HTML content to Document model.
-type HTMLParser struct{}
+var (
+	// imgSrcRe matches an img element's src attribute, capturing its value.
+	imgSrcRe = regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
+
+	// imgSrcPartsRe splits an img element's src attribute into the text
+	// before the value, the value, and the closing quote.
+	imgSrcPartsRe = regexp.MustCompile(`(<img[^>]+src=["'])([^"']+)(["'])`)
+
+	// voidElementRes close each void element the way XHTML requires.
+	voidElementRes = compileVoidElementRes()
+)
+
+// voidElementRe holds the patterns closing one void element: tags already
+// self-closed, tags with attributes, and bare tags.
+type voidElementRe struct {
+	closed, unclosed, simple *regexp.Regexp
+}
+
+// compileVoidElementRes compiles the patterns for each HTML void element.
+func compileVoidElementRes() []voidElementRe {
+	elements := []string{"br", "hr", "img", "input", "meta", "link", "area", "base", "col", "embed", "param", "source", "track", "wbr"}
+	res := make([]voidElementRe, len(elements))
+	for i, elem := range elements {
+		res[i] = voidElementRe{
+			closed:   regexp.MustCompile(`<(` + elem + `)([^>]*)\s*/>`),
+			unclosed: regexp.MustCompile(`<(` + elem + `)([^/>]*[^/])>`),
+			simple:   regexp.MustCompile(`<(` + elem + `)>`),
+		}
+	}
+	return res
+}
+
+// HTMLParser parses HTML content to Document model. It holds no state
+// between documents, so one parser can be used from many goroutines.
+type HTMLParser struct {
+	scripts ScriptPolicy
+	notes   string              // Note placement
+	report  func(model.Warning) // Receives parse events; may be nil
+}
 
 // NewHTMLParser creates a new HTML parser.
 func NewHTMLParser() *HTMLParser {
 	return &HTMLParser{}
 }
 
+// SetScriptPolicy configures which scripts are preserved instead of stripped.
+func (p *HTMLParser) SetScriptPolicy(policy ScriptPolicy) {
+	p.scripts = policy
+}
+
+// WithScriptPolicy returns a copy of the parser using the script policy.
+func (p *HTMLParser) WithScriptPolicy(policy ScriptPolicy) Parser {
+	return &HTMLParser{scripts: policy, notes: p.notes, report: p.report}
+}
+
+// WithEvents returns a copy of the parser reporting events to report.
+func (p *HTMLParser) WithEvents(report func(model.Warning)) Parser {
+	return &HTMLParser{scripts: p.scripts, notes: p.notes, report: report}
+}
+
+// WithNotes returns a copy of the parser placing footnotes as placement
+// asks.
+func (p *HTMLParser) WithNotes(placement string) Parser {
+	return &HTMLParser{scripts: p.scripts, notes: placement, report: p.report}
+}
+
 // Parse converts HTML content to a Document.
 func (p *HTMLParser) Parse(content []byte, basePath string) (*model.Document, error) {
 	doc := model.NewDocument()
+	content = decodeUTF8(content, "text/html", p.report)
 
 	// Parse HTML
 	htmlDoc, err := html.Parse(bytes.NewReader(content))
@@ -53,17 +112,54 @@ func (p *HTMLParser) Parse(content []byte, basePath string) (*model.Document, er
 	// Clean and convert to XHTML
 	xhtmlContent := p.convertToXHTML(bodyContent)
 
+	// Mark DPUB-ARIA footnotes for pop-up display, or list them as endnotes
+	xhtmlContent = placeNotes(annotateFootnotes(xhtmlContent), p.notes)
+
+	// Convert page-break directives to page break markers
+	xhtmlContent = convertPageBreaks(xhtmlContent)
+
+	// Move glossary definition lists into the document glossary
+	xhtmlContent, glossary := extractGlossaries(xhtmlContent)
+	for _, entry := range glossary {
+		doc.AddGlossaryEntry(entry)
+	}
+
+	// Resolve @fig:/@tbl: references to numbered figures and tables
+	xhtmlContent = convertCrossRefs(xhtmlContent)
+
 	// Extract image references
-	images := p.extractImageRefs(xhtmlContent, basePath)
+	names := newFileNamer("images")
+	images := p.extractImageRefs(xhtmlContent, basePath, names)
 	for _, img := range images {
 		doc.AddResource(img)
 	}
 
 	// Rewrite image paths for EPUB
-	xhtmlContent = p.rewriteImagePaths(xhtmlContent)
+	xhtmlContent = p.rewriteImagePaths(xhtmlContent, names)
+
+	// Process embedded audio and video references
+	for _, media := range extractMediaRefs(xhtmlContent, basePath) {
+		doc.AddResource(media)
+	}
+	xhtmlContent = rewriteMediaPaths(xhtmlContent)
 
-	// Strip JavaScript
-	xhtmlContent = p.stripJavaScript(xhtmlContent)
+	// Strip JavaScript, preserving scripts allowed by the script policy
+	var scripts []model.Resource
+	xhtmlContent, scripts = p.scripts.filterScripts(xhtmlContent, basePath)
+	for _, script := range scripts {
+		doc.AddResource(script)
+	}
+
+	// Preserve linked stylesheets
+	var stylesheets []string
+	for _, href := range p.extractStylesheetLinks(htmlDoc) {
+		cssResource, ok := newStylesheetResource(href, basePath)
+		if !ok {
+			continue
+		}
+		doc.AddResource(cssResource)
+		stylesheets = append(stylesheets, cssResource.FileName)
+	}
 
 	// Extract CSS
 	css := p.extractCSS(htmlDoc, basePath)
@@ -75,6 +171,7 @@ func (p *HTMLParser) Parse(content []byte, basePath string) (*model.Document, er
 			Data:      []byte(css),
 		}
 		doc.AddResource(cssResource)
+		stylesheets = append(stylesheets, cssResource.FileName)
 	}
 
 	// Create chapter
@@ -91,6 +188,8 @@ func (p *HTMLParser) Parse(content []byte, basePath string) (*model.Document, er
 		Content:  xhtmlContent,
 		FileName: "content/chapter-001.xhtml",
 		Order:    0,
+
+		Stylesheets: stylesheets,
 	}
 	doc.AddChapter(chapter)
 
@@ -145,9 +244,11 @@ func (p *HTMLParser) extractMetadata(doc *html.Node, mdoc *model.Document) {
 	findMeta(doc)
 }
 
-// extractHeadings extracts heading elements for TOC.
+// extractHeadings extracts heading elements for TOC. Headings without an
+// id are given a unique one, so TOC entries link to them.
 func (p *HTMLParser) extractHeadings(doc *html.Node) []headingInfo {
 	var headings []headingInfo
+	seen := make(map[string]bool)
 
 	var walk func(*html.Node)
 	walk = func(n *html.Node) {
@@ -173,7 +274,12 @@ func (p *HTMLParser) extractHeadings(doc *html.Node) []headingInfo {
 				id := p.getAttr(n, "id")
 				if id == "" {
 					id = generateHeadingID(text)
+					for i := 1; seen[id]; i++ {
+						id = fmt.Sprintf("%s-%d", generateHeadingID(text), i)
+					}
+					n.Attr = append(n.Attr, html.Attribute{Key: "id", Val: id})
 				}
+				seen[id] = true
 				headings = append(headings, headingInfo{
 					Level: level,
 					Title: text,
@@ -190,11 +296,15 @@ func (p *HTMLParser) extractHeadings(doc *html.Node) []headingInfo {
 	return headings
 }
 
-// extractText extracts text content from a node.
+// extractText extracts text content from a node, leaving out ruby
+// readings and their fallback parentheses.
 func (p *HTMLParser) extractText(n *html.Node) string {
 	var text strings.Builder
 	var walk func(*html.Node)
 	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && (node.Data == "rt" || node.Data == "rp") {
+			return
+		}
 		if node.Type == html.TextNode {
 			text.WriteString(node.Data)
 		}
@@ -246,20 +356,16 @@ func (p *HTMLParser) extractBody(doc *html.Node) string {
 // convertToXHTML converts HTML to valid XHTML.
 func (p *HTMLParser) convertToXHTML(content string) string {
 	// Self-close void elements
-	voidElements := []string{"br", "hr", "img", "input", "meta", "link", "area", "base", "col", "embed", "param", "source", "track", "wbr"}
-	for _, elem := range voidElements {
+	for _, re := range voidElementRes {
 		// Match <elem ...> not already self-closed and convert to <elem ... />
 		// First, normalize any existing self-closed tags
-		reAlreadyClosed := regexp.MustCompile(`<(` + elem + `)([^>]*)\s*/>`)
-		content = reAlreadyClosed.ReplaceAllString(content, `<$1$2 />`)
+		content = re.closed.ReplaceAllString(content, `<$1$2 />`)
 
 		// Then, close unclosed void elements
-		reUnclosed := regexp.MustCompile(`<(` + elem + `)([^/>]*[^/])>`)
-		content = reUnclosed.ReplaceAllString(content, `<$1$2 />`)
+		content = re.unclosed.ReplaceAllString(content, `<$1$2 />`)
 
 		// Handle simple tags like <br> or <hr>
-		reSimple := regexp.MustCompile(`<(` + elem + `)>`)
-		content = reSimple.ReplaceAllString(content, `<$1 />`)
+		content = re.simple.ReplaceAllString(content, `<$1 />`)
 	}
 
 	// Ensure lowercase tags (HTML5 is case-insensitive, XHTML requires lowercase)
@@ -271,19 +377,6 @@ func (p *HTMLParser) convertToXHTML(content string) string {
 	return content
 }
 
-// stripJavaScript removes script elements.
-func (p *HTMLParser) stripJavaScript(content string) string {
-	// Remove script tags and their content
-	scriptRe := regexp.MustCompile(`(?is)<script[^>]*>.*?</script>`)
-	content = scriptRe.ReplaceAllString(content, "")
-
-	// Remove on* event handlers
-	eventRe := regexp.MustCompile(`\s+on\w+="[^"]*"`)
-	content = eventRe.ReplaceAllString(content, "")
-
-	return content
-}
-
 // extractCSS extracts inline and style tag CSS.
 func (p *HTMLParser) extractCSS(doc *html.Node, basePath string) string {
 	var css strings.Builder
@@ -305,12 +398,34 @@ func (p *HTMLParser) extractCSS(doc *html.Node, basePath string) string {
 	return css.String()
 }
 
+// extractStylesheetLinks returns the hrefs of <link rel="stylesheet"> elements.
+func (p *HTMLParser) extractStylesheetLinks(doc *html.Node) []string {
+	var hrefs []string
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "link" {
+			rel := strings.ToLower(p.getAttr(n, "rel"))
+			if strings.Contains(rel, "stylesheet") && !strings.Contains(rel, "alternate") {
+				if href := p.getAttr(n, "href"); href != "" {
+					hrefs = append(hrefs, href)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	return hrefs
+}
+
 // extractImageRefs finds image references in content.
-func (p *HTMLParser) extractImageRefs(content string, basePath string) []model.Resource {
+func (p *HTMLParser) extractImageRefs(content string, basePath string, names *fileNamer) []model.Resource {
 	var resources []model.Resource
 
-	imgRe := regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
-	matches := imgRe.FindAllStringSubmatch(content, -1)
+	matches := imgSrcRe.FindAllStringSubmatch(content, -1)
 
 	seen := make(map[string]bool)
 	for _, match := range matches {
@@ -320,16 +435,17 @@ func (p *HTMLParser) extractImageRefs(content string, basePath string) []model.R
 
 		src := match[1]
 
-		// Skip remote URLs and data URIs
+		// Skip remote URLs and data URIs; the converter downloads or
+		// reports remote images
 		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") ||
 			strings.HasPrefix(src, "data:") {
 			continue
 		}
 
-		if seen[src] {
+		if seen[filepath.Clean(src)] {
 			continue
 		}
-		seen[src] = true
+		seen[filepath.Clean(src)] = true
 
 		baseName := filepath.Base(src)
 		ext := strings.ToLower(filepath.Ext(baseName))
@@ -344,10 +460,24 @@ func (p *HTMLParser) extractImageRefs(content string, basePath string) []model.R
 			mediaType = "image/gif"
 		case ".svg":
 			mediaType = "image/svg+xml"
+		case ".webp":
+			mediaType = "image/webp"
+		case ".avif":
+			mediaType = "image/avif"
+		case ".heic", ".heif":
+			mediaType = "image/jpeg" // Will be converted
 		default:
+			emit(p.report, model.Warning{
+				Code:    model.WarnMissingImage,
+				File:    src,
+				Message: fmt.Sprintf("Image %s: unsupported format, skipped", src),
+			})
 			continue
 		}
 
+		// Name the file after the source, numbered if another source has its name
+		fileName := names.name(src)
+
 		// Resolve source path relative to basePath
 		sourcePath := src
 		if !filepath.IsAbs(src) {
@@ -355,8 +485,8 @@ func (p *HTMLParser) extractImageRefs(content string, basePath string) []model.R
 		}
 
 		resource := model.Resource{
-			ID:         "img-" + sanitizeID(strings.TrimSuffix(baseName, ext)),
-			FileName:   "images/" + baseName,
+			ID:         "img-" + sanitizeID(strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))),
+			FileName:   fileName,
 			MediaType:  mediaType,
 			SourcePath: sourcePath, // Store resolved absolute path
 		}
@@ -367,10 +497,9 @@ func (p *HTMLParser) extractImageRefs(content string, basePath string) []model.R
 }
 
 // rewriteImagePaths updates image paths to EPUB-relative paths.
-func (p *HTMLParser) rewriteImagePaths(content string) string {
-	imgRe := regexp.MustCompile(`(<img[^>]+src=["'])([^"']+)(["'])`)
-	return imgRe.ReplaceAllStringFunc(content, func(match string) string {
-		parts := imgRe.FindStringSubmatch(match)
+func (p *HTMLParser) rewriteImagePaths(content string, names *fileNamer) string {
+	return imgSrcPartsRe.ReplaceAllStringFunc(content, func(match string) string {
+		parts := imgSrcPartsRe.FindStringSubmatch(match)
 		if len(parts) < 4 {
 			return match
 		}
@@ -383,8 +512,7 @@ func (p *HTMLParser) rewriteImagePaths(content string) string {
 			return match
 		}
 
-		baseName := filepath.Base(src)
-		newSrc := "../images/" + baseName
+		newSrc := "../" + names.name(src)
 
 		return parts[1] + newSrc + parts[3]
 	})
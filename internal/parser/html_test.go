@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -71,6 +72,19 @@ func TestHTMLParser_Parse_ExtractsHeadings(t *testing.T) {
 	assert.Equal(t, 1, entries[0].Level)
 }
 
+func TestHTMLParser_Parse_GivesHeadingsIDs(t *testing.T) {
+	html := `<html><body><h1>Notes</h1><h2 id="own">Kept</h2><h2>Notes</h2></body></html>`
+
+	doc, err := NewHTMLParser().Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<h1 id="notes">`)
+	assert.Contains(t, content, `<h2 id="own">`)
+	assert.Contains(t, content, `<h2 id="notes-1">`)
+	assert.Equal(t, "content/chapter-001.xhtml#notes-1", doc.TOC.Entries[0].Children[1].Href)
+}
+
 func TestHTMLParser_Parse_ConvertsToXHTML(t *testing.T) {
 	html := `<html>
 <body>
@@ -217,6 +231,34 @@ func TestHTMLParser_Parse_RewritesImagePaths(t *testing.T) {
 	assert.Contains(t, content, "https://example.com/remote.png")
 }
 
+func TestHTMLParser_Parse_CollidingImageNames(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<body>
+    <img src="a/photo.png" alt="First">
+    <img src="b/photo.png" alt="Second">
+    <img src="b/Photo.png" alt="Third">
+    <img src="a/./photo.png" alt="First again">
+</body>
+</html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), "/book")
+	require.NoError(t, err)
+
+	require.Len(t, doc.Resources, 3)
+	assert.Equal(t, "images/photo.png", doc.Resources[0].FileName)
+	assert.Equal(t, "images/photo-2.png", doc.Resources[1].FileName)
+	assert.Equal(t, "img-photo-2", doc.Resources[1].ID)
+	assert.Equal(t, filepath.Join("/book", "b/photo.png"), doc.Resources[1].SourcePath)
+	assert.Equal(t, "images/Photo-3.png", doc.Resources[2].FileName)
+
+	content := doc.Chapters[0].Content
+	assert.Equal(t, 2, strings.Count(content, `src="../images/photo.png"`))
+	assert.Contains(t, content, `src="../images/photo-2.png"`)
+	assert.Contains(t, content, `src="../images/Photo-3.png"`)
+}
+
 func TestHTMLParser_Parse_NoBody(t *testing.T) {
 	// HTML without body tag
 	html := `<h1>Title</h1><p>Content</p>`
@@ -247,7 +289,7 @@ func TestHTMLParser_Parse_UppercaseTags(t *testing.T) {
 
 	content := doc.Chapters[0].Content
 	// golang.org/x/net/html normalizes tags to lowercase
-	assert.Contains(t, content, "<h1>")
+	assert.Contains(t, content, `<h1 id="test">`)
 	assert.Contains(t, content, "<br />")
 	assert.Contains(t, content, "<hr />")
 }
@@ -301,3 +343,173 @@ func TestHTMLParser_Parse_HeadingIDs(t *testing.T) {
 	assert.NotEmpty(t, entries)
 	assert.Contains(t, entries[0].Href, "#custom-id")
 }
+
+func TestHTMLParser_Parse_PreservesStylesheetLinks(t *testing.T) {
+	html := `<!DOCTYPE html>
+<html>
+<head>
+    <link rel="stylesheet" href="css/chapter.css">
+    <link rel="stylesheet" href="https://example.com/remote.css">
+    <style>p { color: red; }</style>
+</head>
+<body><h1>Styled</h1></body>
+</html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), "book")
+
+	require.NoError(t, err)
+	require.Len(t, doc.Chapters, 1)
+	assert.Equal(t, []string{"styles/chapter.css", "styles/inline.css"}, doc.Chapters[0].Stylesheets)
+
+	var linked bool
+	for _, res := range doc.Resources {
+		if res.FileName == "styles/chapter.css" {
+			linked = true
+			assert.Equal(t, "text/css", res.MediaType)
+			assert.Equal(t, filepath.Join("book", "css", "chapter.css"), res.SourcePath)
+		}
+	}
+	assert.True(t, linked, "linked stylesheet resource missing")
+}
+
+func TestHTMLParser_Parse_AudioReferences(t *testing.T) {
+	html := `<html><body>
+    <h1>Listen</h1>
+    <audio controls="" src="clips/hello.mp3"></audio>
+    <audio controls=""><source src="clips/word.opus" type="audio/ogg"></audio>
+    <audio src="https://example.com/remote.mp3"></audio>
+</body></html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `src="../audio/hello.mp3"`)
+	assert.Contains(t, content, `src="../audio/word.opus"`)
+	assert.Contains(t, content, `src="https://example.com/remote.mp3"`)
+
+	mediaTypes := make(map[string]string)
+	for _, res := range doc.Resources {
+		mediaTypes[res.FileName] = res.MediaType
+	}
+	assert.Equal(t, "audio/mpeg", mediaTypes["audio/hello.mp3"])
+	assert.Equal(t, "audio/ogg; codecs=opus", mediaTypes["audio/word.opus"])
+}
+
+func TestHTMLParser_Parse_VideoWithPoster(t *testing.T) {
+	html := `<html><body>
+    <h1>Watch</h1>
+    <video controls="" poster="media/poster.jpg"><source src="media/demo.mp4" type="video/mp4"></video>
+</body></html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `poster="../images/poster.jpg"`)
+	assert.Contains(t, content, `src="../video/demo.mp4"`)
+
+	mediaTypes := make(map[string]string)
+	for _, res := range doc.Resources {
+		mediaTypes[res.FileName] = res.MediaType
+	}
+	assert.Equal(t, "video/mp4", mediaTypes["video/demo.mp4"])
+	assert.Equal(t, "image/jpeg", mediaTypes["images/poster.jpg"])
+}
+
+func TestHTMLParser_Parse_ScriptPolicy(t *testing.T) {
+	html := `<html><body>
+    <h1>Quiz</h1>
+    <script src="js/quiz.js"></script>
+    <script src="js/tracker.js"></script>
+    <script>console.log("inline");</script>
+    <button onclick="check()">Check</button>
+</body></html>`
+
+	p := NewHTMLParser()
+	p.SetScriptPolicy(ScriptPolicy{Allowed: []string{"quiz.js"}})
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<script src="../scripts/quiz.js"></script>`)
+	assert.NotContains(t, content, "tracker.js")
+	assert.NotContains(t, content, "console.log")
+	assert.NotContains(t, content, "onclick")
+
+	var script bool
+	for _, res := range doc.Resources {
+		if res.FileName == "scripts/quiz.js" {
+			script = true
+			assert.Equal(t, ScriptMediaType, res.MediaType)
+		}
+	}
+	assert.True(t, script, "whitelisted script resource missing")
+
+	p.SetScriptPolicy(ScriptPolicy{Inline: true})
+	doc, err = p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content = doc.Chapters[0].Content
+	assert.Contains(t, content, "console.log")
+	assert.Contains(t, content, `onclick="check()"`)
+	assert.NotContains(t, content, "quiz.js")
+}
+
+func TestHTMLParser_Parse_FootnoteRoles(t *testing.T) {
+	html := `<html><body>
+    <h1>Notes</h1>
+    <p>Text<a href="#n1" role="doc-noteref">1</a></p>
+    <aside id="n1" role="doc-footnote"><p>The note.</p></aside>
+    <aside id="n2" epub:type="rearnote" role="doc-footnote"><p>Kept.</p></aside>
+</body></html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<a epub:type="noteref" href="#n1" role="doc-noteref">1</a>`)
+	assert.Contains(t, content, `<aside epub:type="footnote" id="n1" role="doc-footnote">`)
+	assert.Contains(t, content, `epub:type="rearnote"`)
+}
+
+func TestHTMLParser_Parse_CrossReferences(t *testing.T) {
+	html := `<html><body>
+    <h1>Data</h1>
+    <table id="tbl:res"><caption>Results</caption><tr><td>1</td></tr></table>
+    <p title="see @tbl:res">As @tbl:res shows, but not me@tbl:res or <code>@tbl:res</code>.</p>
+</body></html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<p title="see @tbl:res">As <a data-ref="tbl:res"></a> shows, but not me@tbl:res or <code>@tbl:res</code>.</p>`)
+}
+
+func TestHTMLParser_Parse_PageBreaks(t *testing.T) {
+	html := `<html><body>
+    <h1>Part</h1>
+    <p>One</p>
+    <!-- pagebreak -->
+    <p>Two</p>
+    <hr class="scene pagebreak">
+    <p>Three</p>
+    <hr>
+    <pre><code>&lt;!-- pagebreak --&gt;</code></pre>
+</body></html>`
+
+	p := NewHTMLParser()
+	doc, err := p.Parse([]byte(html), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Equal(t, 2, strings.Count(content, `<div class="page-break"></div>`))
+	assert.NotContains(t, content, "scene pagebreak")
+	assert.Contains(t, content, "<hr />")
+}
@@ -0,0 +1,46 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Patterns for Markdown index term markers.
+var (
+	// indexShorthandRe matches "{index: term}" markers in rendered text.
+	indexShorthandRe = regexp.MustCompile(`\{index:\s*([^{}]+?)\s*\}`)
+
+	// codeBlockRe matches code spans and blocks, where markers are left as-is.
+	codeBlockRe = regexp.MustCompile(`(?is)<pre\b.*?</pre>|<code\b.*?</code>`)
+)
+
+// convertIndexMarkers rewrites "{index: term}" markers into empty
+// <span data-index="term"> elements that the EPUB builder collects into
+// the back-of-book index. Markers inside code are not converted.
+// The term is already HTML-escaped by the Markdown renderer.
+func convertIndexMarkers(content string) string {
+	return replaceOutsideCode(content, func(text string) string {
+		return indexShorthandRe.ReplaceAllString(text, `<span data-index="$1"></span>`)
+	})
+}
+
+// replaceOutsideCode applies replace to the parts of content that are not
+// inside code spans or blocks.
+func replaceOutsideCode(content string, replace func(string) string) string {
+	var result strings.Builder
+	last := 0
+	for _, loc := range codeBlockRe.FindAllStringIndex(content, -1) {
+		result.WriteString(replace(content[last:loc[0]]))
+		result.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	result.WriteString(replace(content[last:]))
+	return result.String()
+}
@@ -12,7 +12,10 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
@@ -25,33 +28,76 @@ import (
 	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
+var (
+	// headingIDCharsRe matches characters not allowed in generated heading ids.
+	headingIDCharsRe = regexp.MustCompile(`[^a-z0-9-]`)
+
+	// hyphensRe matches runs of hyphens.
+	hyphensRe = regexp.MustCompile(`-+`)
+)
+
 // MarkdownParser parses Markdown content using goldmark with GFM support.
+// The goldmark instance is built once and keeps per-document state in each
+// parse's context, so one parser can be used from many goroutines.
 type MarkdownParser struct {
-	md goldmark.Markdown
+	md     goldmark.Markdown
+	notes  string              // Note placement
+	report func(model.Warning) // Receives parse events; may be nil
+	fetch  Fetcher             // Reads files named in front matter; nil reads none
 }
 
 // NewMarkdownParser creates a new Markdown parser with GFM extensions.
 func NewMarkdownParser() *MarkdownParser {
-	md := goldmark.New(
+	return &MarkdownParser{md: newMarkdown(model.NotesPopup), notes: model.NotesPopup, fetch: diskFetcher{}}
+}
+
+// newMarkdown builds the goldmark instance, rendering footnotes for the
+// note placement.
+func newMarkdown(notes string) goldmark.Markdown {
+	return goldmark.New(
 		goldmark.WithExtensions(
-			extension.GFM, // Tables, task lists, strikethrough, autolinks
-			&frontmatter.Extender{}, // YAML/TOML front matter
+			extension.GFM,            // Tables, task lists, strikethrough, autolinks
+			&frontmatter.Extender{},  // YAML/TOML front matter
+			extension.Footnote,       // Footnotes
+			extension.DefinitionList, // Definition lists, used for glossaries
+			&epubFootnotes{notes},    // Footnotes as EPUB pop-up notes or endnotes
 		),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(), // Generate heading IDs
+			parser.WithAttribute(),     // Heading attributes, e.g. {#id .unnumbered}
 		),
 		goldmark.WithRendererOptions(
 			html.WithXHTML(),         // Generate XHTML for EPUB
 			html.WithUnsafe(),        // Allow raw HTML in markdown
 		),
 	)
+}
+
+// WithEvents returns a copy of the parser reporting events to report.
+func (p *MarkdownParser) WithEvents(report func(model.Warning)) Parser {
+	return &MarkdownParser{md: p.md, notes: p.notes, report: report, fetch: p.fetch}
+}
+
+// WithFetcher returns a copy of the parser reading the bibliography and
+// glossary files named in front matter with fetcher, or reading none if
+// fetcher is nil.
+func (p *MarkdownParser) WithFetcher(fetcher Fetcher) Parser {
+	return &MarkdownParser{md: p.md, notes: p.notes, report: p.report, fetch: fetcher}
+}
 
-	return &MarkdownParser{md: md}
+// WithNotes returns a copy of the parser placing footnotes as placement
+// asks.
+func (p *MarkdownParser) WithNotes(placement string) Parser {
+	if placement == p.notes {
+		return p
+	}
+	return &MarkdownParser{md: newMarkdown(placement), notes: placement, report: p.report, fetch: p.fetch}
 }
 
 // Parse converts Markdown content to a Document.
 func (p *MarkdownParser) Parse(content []byte, basePath string) (*model.Document, error) {
 	doc := model.NewDocument()
+	content = decodeUTF8(content, "text/plain", p.report)
 
 	// Parse front matter and content
 	var meta map[string]interface{}
@@ -64,6 +110,9 @@ func (p *MarkdownParser) Parse(content []byte, basePath string) (*model.Document
 
 	// Apply front matter metadata
 	p.applyMetadata(doc, meta)
+	if cover, ok := meta["cover"].(string); ok && cover != "" {
+		doc.Metadata.CoverImage = resolveRef(cover, basePath)
+	}
 
 	// Parse markdown to AST
 	reader := text.NewReader(body)
@@ -78,19 +127,107 @@ func (p *MarkdownParser) Parse(content []byte, basePath string) (*model.Document
 		return nil, fmt.Errorf("rendering markdown: %w", err)
 	}
 
-	htmlContent := buf.String()
+	htmlContent := convertCitations(convertIndexMarkers(convertPageBreaks(buf.String())))
+	htmlContent = convertRuby(htmlContent)
+	htmlContent = convertCrossRefs(convertLabels(htmlContent))
+
+	// Move glossary definition lists into the document glossary
+	htmlContent, glossary := extractGlossaries(htmlContent)
+	for _, entry := range glossary {
+		doc.AddGlossaryEntry(entry)
+	}
 
 	// Process image references
-	images := p.extractImageRefs(htmlContent, basePath)
+	names := newFileNamer("images")
+	images := p.extractImageRefs(htmlContent, basePath, names)
 	for _, img := range images {
 		doc.AddResource(img)
 	}
 
 	// Update image paths in content
-	htmlContent = p.rewriteImagePaths(htmlContent)
+	htmlContent = p.rewriteImagePaths(htmlContent, names)
+
+	// Process embedded audio and video references
+	for _, media := range extractMediaRefs(htmlContent, basePath) {
+		doc.AddResource(media)
+	}
+	htmlContent = rewriteMediaPaths(htmlContent)
+
+	// Create chapters from headings or single chapter. Files holding only
+	// front matter contribute metadata without adding an empty chapter.
+	if meta == nil || strings.TrimSpace(htmlContent) != "" {
+		p.createChapters(doc, htmlContent, headings)
+	}
+
+	// The file's chapters are in the language its front matter declares,
+	// which may differ from the book language in a bilingual edition
+	if langs := frontMatterLanguages(meta); len(langs) > 0 {
+		for i := range doc.Chapters {
+			doc.Chapters[i].Language = langs[0]
+		}
+	}
+
+	// The file's chapters have the structural type its front matter declares
+	if declared, ok := meta["type"].(string); ok && declared != "" {
+		chapterType, err := model.ParseChapterType(declared)
+		if err != nil {
+			return nil, fmt.Errorf("front matter type: %w", err)
+		}
+		for i := range doc.Chapters {
+			doc.Chapters[i].Type = chapterType
+		}
+	}
+
+	// A fixed-layout page declares the side of the spread it is on
+	if value, ok := meta["page-spread"].(string); ok && len(doc.Chapters) > 0 {
+		if spread, err := model.ParsePageSpread(value); err == nil {
+			doc.Chapters[0].Spread = spread
+		} else {
+			p.invalidMetadata(err)
+		}
+	}
+
+	// Link chapter-specific stylesheets declared in front matter
+	for _, href := range stringList(meta["css"]) {
+		css, ok := newStylesheetResource(href, basePath)
+		if !ok {
+			continue
+		}
+		doc.AddResource(css)
+		for i := range doc.Chapters {
+			doc.Chapters[i].Stylesheets = append(doc.Chapters[i].Stylesheets, css.FileName)
+		}
+	}
 
-	// Create chapters from headings or single chapter
-	p.createChapters(doc, htmlContent, headings)
+	// Load references from bibliography files declared in front matter
+	for _, path := range p.frontMatterFiles(meta, "bibliography", basePath) {
+		data, err := p.fetch.Fetch(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading bibliography: %w", err)
+		}
+		refs, err := ParseBibliography(path, data)
+		if err != nil {
+			return nil, fmt.Errorf("loading bibliography: %w", err)
+		}
+		for _, ref := range refs {
+			doc.AddReference(ref)
+		}
+	}
+
+	// Load terms from glossary files declared in front matter
+	for _, path := range p.frontMatterFiles(meta, "glossary", basePath) {
+		data, err := p.fetch.Fetch(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading glossary: %w", err)
+		}
+		entries, err := ParseGlossary(data)
+		if err != nil {
+			return nil, fmt.Errorf("loading glossary: %w", err)
+		}
+		for _, entry := range entries {
+			doc.AddGlossaryEntry(entry)
+		}
+	}
 
 	// Build TOC
 	doc.TOC = *p.buildTOC(headings, doc.Chapters)
@@ -98,6 +235,26 @@ func (p *MarkdownParser) Parse(content []byte, basePath string) (*model.Document
 	return doc, nil
 }
 
+// frontMatterFiles returns the paths of the files the front matter key
+// names, resolved against basePath. Without a fetcher to read them, none
+// are returned and each is reported.
+func (p *MarkdownParser) frontMatterFiles(meta map[string]interface{}, key, basePath string) []string {
+	var paths []string
+	for _, ref := range stringList(meta[key]) {
+		path := resolveRef(ref, basePath)
+		if p.fetch == nil {
+			emit(p.report, model.Warning{
+				Code:    model.WarnResourceNotLoaded,
+				File:    path,
+				Message: fmt.Sprintf("Front matter %s %s: not available when converting a stream", key, path),
+			})
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
 // SupportedExtensions returns file extensions this parser handles.
 func (p *MarkdownParser) SupportedExtensions() []string {
 	return []string{".md", ".markdown"}
@@ -152,6 +309,39 @@ func (p *MarkdownParser) extractFrontMatter(content []byte) (map[string]interfac
 	return meta, content[bodyStart:]
 }
 
+// FrontMatterWeight returns the weight a Markdown file declares with a
+// weight or order front matter key, used to order the files of a
+// directory. It reports false when the file declares none.
+func FrontMatterWeight(content []byte) (float64, bool) {
+	meta, _ := NewMarkdownParser().extractFrontMatter(content)
+	for _, key := range []string{"weight", "order"} {
+		switch v := meta[key].(type) {
+		case int:
+			return float64(v), true
+		case int64:
+			return float64(v), true
+		case uint64:
+			return float64(v), true
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FrontMatterMetadata returns the book metadata held by front matter
+// values, read as the front matter of a Markdown file is. Keys that only
+// apply to a file, such as css and cover paths, are ignored.
+func FrontMatterMetadata(meta map[string]interface{}) *model.Metadata {
+	doc := &model.Document{}
+	(&MarkdownParser{}).applyMetadata(doc, meta)
+	return &doc.Metadata
+}
+
 // applyMetadata applies front matter values to document metadata.
 func (p *MarkdownParser) applyMetadata(doc *model.Document, meta map[string]interface{}) {
 	if meta == nil {
@@ -162,23 +352,32 @@ func (p *MarkdownParser) applyMetadata(doc *model.Document, meta map[string]inte
 		doc.Metadata.Title = title
 	}
 
-	// Handle author as string or list
+	// Handle author as string or list. List entries may be {name, file-as}
+	// maps, whose other keys refine the author.
 	switch author := meta["author"].(type) {
 	case string:
 		doc.Metadata.Authors = []string{author}
 	case []interface{}:
 		for _, a := range author {
-			if s, ok := a.(string); ok {
-				doc.Metadata.Authors = append(doc.Metadata.Authors, s)
+			switch a := a.(type) {
+			case string:
+				doc.Metadata.Authors = append(doc.Metadata.Authors, a)
+			case map[string]interface{}:
+				if name, _ := a["name"].(string); name != "" {
+					doc.Metadata.Authors = append(doc.Metadata.Authors, name)
+					addRefinements(&doc.Metadata, name, a)
+				}
 			}
 		}
 	}
 
-	if lang, ok := meta["language"].(string); ok {
-		doc.Metadata.Language = lang
-	}
-	if lang, ok := meta["lang"].(string); ok {
-		doc.Metadata.Language = lang
+	doc.Metadata.Contributors = append(doc.Metadata.Contributors, parseContributors(&doc.Metadata, meta, p.report)...)
+
+	if langs := frontMatterLanguages(meta); len(langs) > 0 {
+		doc.Metadata.Language = langs[0]
+		if len(langs) > 1 {
+			doc.Metadata.Languages = langs[1:]
+		}
 	}
 
 	if desc, ok := meta["description"].(string); ok {
@@ -188,6 +387,289 @@ func (p *MarkdownParser) applyMetadata(doc *model.Document, meta map[string]inte
 	if publisher, ok := meta["publisher"].(string); ok {
 		doc.Metadata.Publisher = publisher
 	}
+
+	if id, ok := meta["identifier"].(string); ok {
+		doc.Metadata.AddIdentifier(model.ParseIdentifier(id))
+	}
+	for _, scheme := range []string{model.SchemeISBN, model.SchemeDOI} {
+		if value, ok := meta[scheme].(string); ok {
+			doc.Metadata.AddIdentifier(model.Identifier{Scheme: scheme, Value: value})
+		}
+	}
+
+	if name, ok := meta["license"].(string); ok {
+		if license, ok := model.LookupLicense(name); ok {
+			doc.Metadata.License = license.ID
+		}
+	}
+
+	if value, ok := meta["direction"].(string); ok {
+		if dir, err := model.ParseDirection(value); err == nil {
+			doc.Metadata.Direction = dir
+		} else {
+			p.invalidMetadata(err)
+		}
+	}
+
+	if rendition, ok := meta["rendition"].(map[string]interface{}); ok {
+		p.applyRendition(&doc.Metadata.Rendition, rendition)
+	}
+
+	if series, ok := meta["series"].(string); ok && series != "" {
+		collection := model.Collection{Name: series, Type: model.CollectionSeries}
+		for _, key := range []string{"series-index", "series_index"} {
+			if index, ok := meta[key]; ok && index != nil {
+				collection.Position = fmt.Sprint(index)
+			}
+		}
+		doc.Metadata.Collections = append(doc.Metadata.Collections, collection)
+	}
+	for _, name := range stringList(meta["collection"]) {
+		doc.Metadata.Collections = append(doc.Metadata.Collections, model.Collection{Name: name})
+	}
+
+	doc.Metadata.Properties = append(doc.Metadata.Properties, parseProperties(meta)...)
+	doc.Metadata.CalibreColumns = append(doc.Metadata.CalibreColumns, parseCalibreColumns(meta)...)
+}
+
+// applyRendition applies the fixed-layout properties of a "rendition" front
+// matter map: layout, orientation, spread, and viewport. Values that are
+// not valid are reported and ignored.
+func (p *MarkdownParser) applyRendition(r *model.Rendition, meta map[string]interface{}) {
+	properties := []struct {
+		key   string
+		parse func(string) (string, error)
+		value *string
+	}{
+		{"layout", model.ParseLayout, &r.Layout},
+		{"orientation", model.ParseOrientation, &r.Orientation},
+		{"spread", model.ParseSpread, &r.Spread},
+	}
+	for _, property := range properties {
+		value, ok := meta[property.key].(string)
+		if !ok {
+			continue
+		}
+		parsed, err := property.parse(value)
+		if err != nil {
+			p.invalidMetadata(err)
+			continue
+		}
+		*property.value = parsed
+	}
+
+	if value, ok := meta["viewport"].(string); ok {
+		width, height, err := model.ParseViewport(value)
+		if err != nil {
+			p.invalidMetadata(err)
+			return
+		}
+		r.ViewportWidth, r.ViewportHeight = width, height
+	}
+}
+
+// invalidMetadata reports a front matter value that was ignored.
+func (p *MarkdownParser) invalidMetadata(err error) {
+	emit(p.report, model.Warning{
+		Code:    model.WarnInvalidMetadata,
+		Message: fmt.Sprintf("Front matter: %v, ignored", err),
+	})
+}
+
+// parseContributors reads contributors from front matter, either as a
+// "contributors" list of {name, role} entries or as role keys such as
+// "editor" and "translator" holding a name or list of names. Other keys of
+// a list entry, such as file-as, are added to m as refinements. Entries
+// whose role is not a known role or MARC relator code are reported and
+// left out.
+func parseContributors(m *model.Metadata, meta map[string]interface{}, report func(model.Warning)) []model.Contributor {
+	var contributors []model.Contributor
+
+	if list, ok := meta["contributors"].([]interface{}); ok {
+		for _, item := range list {
+			entry, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _ := entry["name"].(string)
+			role, _ := entry["role"].(string)
+			code := model.RelatorCode(role)
+			if name != "" && code == "" {
+				emit(report, model.Warning{
+					Code:    model.WarnInvalidMetadata,
+					Message: fmt.Sprintf("Contributor %s: role %q is not a known role or MARC relator code, ignored", name, role),
+				})
+			}
+			if name != "" && code != "" {
+				contributors = append(contributors, model.Contributor{Name: name, Role: code})
+				addRefinements(m, name, entry)
+			}
+		}
+	}
+
+	for _, role := range model.ContributorRoles() {
+		for _, name := range stringList(meta[role]) {
+			contributors = append(contributors, model.Contributor{Name: name, Role: model.RelatorCode(role)})
+		}
+	}
+
+	return contributors
+}
+
+// addRefinements refines the named author or contributor with the keys of
+// its front matter entry other than name and role, in key order.
+func addRefinements(m *model.Metadata, name string, entry map[string]interface{}) {
+	for _, r := range refinements(entry, "name", "role") {
+		m.AddRefinement(name, r)
+	}
+}
+
+// parseProperties reads metadata properties from front matter keys with a
+// prefix, such as "dc:coverage" or "dcterms:issued". A key holds a value,
+// a list of values, or a map with a "value" and refinements such as
+// {value: ..., alternate-script: ...}.
+func parseProperties(meta map[string]interface{}) []model.Property {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		if model.IsPropertyName(key) {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var properties []model.Property
+	for _, key := range keys {
+		values, ok := meta[key].([]interface{})
+		if !ok {
+			values = []interface{}{meta[key]}
+		}
+		for _, v := range values {
+			p := model.Property{Name: key}
+			if entry, ok := v.(map[string]interface{}); ok {
+				p.Value = frontMatterString(entry["value"])
+				p.Refinements = refinements(entry, "value")
+			} else {
+				p.Value = frontMatterString(v)
+			}
+			if p.Value != "" {
+				properties = append(properties, p)
+			}
+		}
+	}
+	return properties
+}
+
+// parseCalibreColumns reads Calibre custom column values from the
+// "calibre-columns" front matter map of lookup names to values, in lookup
+// name order. The datatype follows the YAML value: text (a list for
+// several values), a boolean, an integer, a number, or a date. Invalid
+// lookup names are ignored.
+func parseCalibreColumns(meta map[string]interface{}) []model.CalibreColumn {
+	columns, ok := meta["calibre-columns"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []model.CalibreColumn
+	for label, value := range columns {
+		column := model.CalibreColumn{Label: strings.TrimPrefix(label, "#"), Datatype: model.CalibreText}
+		if !model.ValidCalibreLabel(column.Label) {
+			continue
+		}
+		switch v := value.(type) {
+		case []interface{}:
+			for _, item := range v {
+				if s := frontMatterString(item); s != "" {
+					column.Values = append(column.Values, s)
+				}
+			}
+		case bool:
+			column.Datatype = model.CalibreBool
+		case int, int64, uint64:
+			column.Datatype = model.CalibreInt
+		case float64:
+			column.Datatype = model.CalibreFloat
+		case time.Time:
+			column.Datatype = model.CalibreDatetime
+		}
+		if column.Values == nil {
+			if s := frontMatterString(value); s != "" {
+				column.Values = []string{s}
+			}
+		}
+		if len(column.Values) > 0 {
+			result = append(result, column)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.CalibreColumn) int {
+		return strings.Compare(a.Label, b.Label)
+	})
+	return result
+}
+
+// refinements converts the keys of a front matter map, except those
+// listed in skip, into refinements sorted by property.
+func refinements(entry map[string]interface{}, skip ...string) []model.Refinement {
+	var result []model.Refinement
+	for key, value := range entry {
+		if slices.Contains(skip, key) {
+			continue
+		}
+		if v := frontMatterString(value); v != "" {
+			result = append(result, model.Refinement{Property: key, Value: v})
+		}
+	}
+	slices.SortFunc(result, func(a, b model.Refinement) int {
+		return strings.Compare(a.Property, b.Property)
+	})
+	return result
+}
+
+// frontMatterString formats a scalar front matter value: a string, a
+// number, a boolean, or a date, which YAML decodes to a time.Time.
+// Lists and maps give "".
+func frontMatterString(value interface{}) string {
+	switch v := value.(type) {
+	case nil, []interface{}, map[string]interface{}:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// frontMatterLanguages returns the languages declared by the "lang" or
+// "language" front matter key, a code or a list of codes, main one first.
+func frontMatterLanguages(meta map[string]interface{}) []string {
+	if langs := stringList(meta["lang"]); len(langs) > 0 {
+		return langs
+	}
+	return stringList(meta["language"])
+}
+
+// stringList converts a front matter value holding a string or a list of
+// strings into a string slice.
+func stringList(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
 }
 
 // extractHeadings walks the AST to find all headings.
@@ -201,11 +683,19 @@ func (p *MarkdownParser) extractHeadings(doc ast.Node, source []byte) []headingI
 
 		if h, ok := n.(*ast.Heading); ok {
 			text := string(h.Text(source))
+
+			// Link to the id goldmark gave the heading, so TOC entries
+			// match the rendered anchors, including for repeated headings
 			id := generateHeadingID(text)
+			if v, ok := h.AttributeString("id"); ok {
+				if b, ok := v.([]byte); ok && len(b) > 0 {
+					id = string(b)
+				}
+			}
 
 			headings = append(headings, headingInfo{
 				Level: h.Level,
-				Title: text,
+				Title: stripRuby(text),
 				ID:    id,
 			})
 		}
@@ -232,12 +722,10 @@ func generateHeadingID(text string) string {
 	id = strings.ReplaceAll(id, " ", "-")
 
 	// Remove non-alphanumeric characters except hyphens
-	reg := regexp.MustCompile(`[^a-z0-9-]`)
-	id = reg.ReplaceAllString(id, "")
+	id = headingIDCharsRe.ReplaceAllString(id, "")
 
 	// Remove multiple consecutive hyphens
-	reg = regexp.MustCompile(`-+`)
-	id = reg.ReplaceAllString(id, "-")
+	id = hyphensRe.ReplaceAllString(id, "-")
 
 	// Trim leading/trailing hyphens
 	id = strings.Trim(id, "-")
@@ -250,12 +738,11 @@ func generateHeadingID(text string) string {
 }
 
 // extractImageRefs finds all image references in the HTML content.
-func (p *MarkdownParser) extractImageRefs(html string, basePath string) []model.Resource {
+func (p *MarkdownParser) extractImageRefs(html string, basePath string, names *fileNamer) []model.Resource {
 	var resources []model.Resource
 
 	// Match img src attributes
-	imgRe := regexp.MustCompile(`<img[^>]+src=["']([^"']+)["']`)
-	matches := imgRe.FindAllStringSubmatch(html, -1)
+	matches := imgSrcRe.FindAllStringSubmatch(html, -1)
 
 	seen := make(map[string]bool)
 	for _, match := range matches {
@@ -265,17 +752,18 @@ func (p *MarkdownParser) extractImageRefs(html string, basePath string) []model.
 
 		src := match[1]
 
-		// Skip remote URLs and data URIs
+		// Skip remote URLs and data URIs; the converter downloads or
+		// reports remote images
 		if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") ||
 			strings.HasPrefix(src, "data:") {
 			continue
 		}
 
 		// Skip duplicates
-		if seen[src] {
+		if seen[filepath.Clean(src)] {
 			continue
 		}
-		seen[src] = true
+		seen[filepath.Clean(src)] = true
 
 		// Create resource placeholder (actual loading done by converter)
 		baseName := filepath.Base(src)
@@ -293,10 +781,22 @@ func (p *MarkdownParser) extractImageRefs(html string, basePath string) []model.
 			mediaType = "image/svg+xml"
 		case ".webp":
 			mediaType = "image/png" // Will be converted
+		case ".avif":
+			mediaType = "image/avif"
+		case ".heic", ".heif":
+			mediaType = "image/jpeg" // Will be converted
 		default:
+			emit(p.report, model.Warning{
+				Code:    model.WarnMissingImage,
+				File:    src,
+				Message: fmt.Sprintf("Image %s: unsupported format, skipped", src),
+			})
 			continue // Skip unsupported formats
 		}
 
+		// Name the file after the source, numbered if another source has its name
+		fileName := names.name(src)
+
 		// Resolve source path relative to basePath
 		sourcePath := src
 		if !filepath.IsAbs(src) {
@@ -304,8 +804,8 @@ func (p *MarkdownParser) extractImageRefs(html string, basePath string) []model.
 		}
 
 		resource := model.Resource{
-			ID:         "img-" + sanitizeID(strings.TrimSuffix(baseName, ext)),
-			FileName:   "images/" + baseName,
+			ID:         "img-" + sanitizeID(strings.TrimSuffix(filepath.Base(fileName), filepath.Ext(fileName))),
+			FileName:   fileName,
 			MediaType:  mediaType,
 			SourcePath: sourcePath, // Store resolved absolute path
 			// Data will be loaded by converter
@@ -318,10 +818,9 @@ func (p *MarkdownParser) extractImageRefs(html string, basePath string) []model.
 }
 
 // rewriteImagePaths updates image paths to EPUB-relative paths.
-func (p *MarkdownParser) rewriteImagePaths(html string) string {
-	imgRe := regexp.MustCompile(`(<img[^>]+src=["'])([^"']+)(["'])`)
-	return imgRe.ReplaceAllStringFunc(html, func(match string) string {
-		parts := imgRe.FindStringSubmatch(match)
+func (p *MarkdownParser) rewriteImagePaths(html string, names *fileNamer) string {
+	return imgSrcPartsRe.ReplaceAllStringFunc(html, func(match string) string {
+		parts := imgSrcPartsRe.FindStringSubmatch(match)
 		if len(parts) < 4 {
 			return match
 		}
@@ -334,9 +833,7 @@ func (p *MarkdownParser) rewriteImagePaths(html string) string {
 			return match
 		}
 
-		// Rewrite to EPUB path
-		baseName := filepath.Base(src)
-		newSrc := "../images/" + baseName
+		newSrc := "../" + names.name(src)
 
 		return parts[1] + newSrc + parts[3]
 	})
@@ -358,8 +855,10 @@ func (p *MarkdownParser) createChapters(doc *model.Document, content string, hea
 		return
 	}
 
-	// For now, create a single chapter with all content
-	// TODO: Split content at h1/h2 boundaries for multi-chapter support
+	// One chapter per input file. The converter splits it at h1 or h2
+	// boundaries when Options.SplitLevel is set (see splitChapters), which
+	// retargets TOC entries and fragment links and moves each footnote to
+	// the part that first refers to it
 	title := headings[0].Title
 	if doc.Metadata.Title == "" {
 		doc.Metadata.Title = title
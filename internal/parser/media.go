@@ -0,0 +1,128 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// Patterns for media references in content.
+var (
+	// mediaSrcRe matches src attributes of audio, video, and source elements.
+	mediaSrcRe = regexp.MustCompile(`(<(?:audio|video|source)\b[^>]*?\ssrc=["'])([^"']+)(["'])`)
+
+	// posterRe matches poster attributes of video elements.
+	posterRe = regexp.MustCompile(`(<video\b[^>]*?\sposter=["'])([^"']+)(["'])`)
+)
+
+// mediaResourceFor returns a resource placeholder for a local audio or video
+// reference. Returns false for remote URLs, data URIs, and unsupported formats.
+func mediaResourceFor(src string, basePath string) (model.Resource, bool) {
+	if isRemoteRef(src) {
+		return model.Resource{}, false
+	}
+
+	baseName := filepath.Base(src)
+	name := sanitizeID(strings.TrimSuffix(baseName, filepath.Ext(baseName)))
+
+	res := model.Resource{SourcePath: resolveRef(src, basePath)}
+	if mediaType := model.AudioMediaType(baseName); mediaType != "" {
+		res.ID = "audio-" + name
+		res.FileName = "audio/" + baseName
+		res.MediaType = mediaType
+	} else if mediaType := model.VideoMediaType(baseName); mediaType != "" {
+		res.ID = "video-" + name
+		res.FileName = "video/" + baseName
+		res.MediaType = mediaType
+	} else {
+		return model.Resource{}, false
+	}
+
+	return res, true
+}
+
+// posterResourceFor returns an image resource placeholder for a video poster.
+func posterResourceFor(src string, basePath string) (model.Resource, bool) {
+	if isRemoteRef(src) {
+		return model.Resource{}, false
+	}
+
+	baseName := filepath.Base(src)
+	mediaType := model.ImageMediaType(baseName)
+	if mediaType == "" {
+		return model.Resource{}, false
+	}
+
+	return model.Resource{
+		ID:         "img-" + sanitizeID(strings.TrimSuffix(baseName, filepath.Ext(baseName))),
+		FileName:   "images/" + baseName,
+		MediaType:  mediaType,
+		SourcePath: resolveRef(src, basePath),
+	}, true
+}
+
+// extractMediaRefs finds local audio, video, and poster image references in content.
+func extractMediaRefs(content string, basePath string) []model.Resource {
+	var resources []model.Resource
+
+	seen := make(map[string]bool)
+	collect := func(re *regexp.Regexp, resourceFor func(string, string) (model.Resource, bool)) {
+		for _, match := range re.FindAllStringSubmatch(content, -1) {
+			src := match[2]
+			if seen[src] {
+				continue
+			}
+			seen[src] = true
+
+			if res, ok := resourceFor(src, basePath); ok {
+				resources = append(resources, res)
+			}
+		}
+	}
+
+	collect(mediaSrcRe, mediaResourceFor)
+	collect(posterRe, posterResourceFor)
+
+	return resources
+}
+
+// rewriteMediaPaths updates local audio, video, and poster references to
+// EPUB-relative paths.
+func rewriteMediaPaths(content string) string {
+	rewrite := func(content string, re *regexp.Regexp, resourceFor func(string, string) (model.Resource, bool)) string {
+		return re.ReplaceAllStringFunc(content, func(match string) string {
+			parts := re.FindStringSubmatch(match)
+			res, ok := resourceFor(parts[2], "")
+			if !ok {
+				return match
+			}
+			return parts[1] + "../" + res.FileName + parts[3]
+		})
+	}
+
+	content = rewrite(content, mediaSrcRe, mediaResourceFor)
+	return rewrite(content, posterRe, posterResourceFor)
+}
+
+// isRemoteRef returns true for remote URLs and data URIs.
+func isRemoteRef(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") ||
+		strings.HasPrefix(src, "data:")
+}
+
+// resolveRef resolves a relative reference against basePath.
+func resolveRef(src string, basePath string) string {
+	if filepath.IsAbs(src) {
+		return src
+	}
+	return filepath.Join(basePath, src)
+}
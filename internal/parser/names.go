@@ -0,0 +1,50 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fileNamer gives each source file referenced by a document its own file
+// name in a package directory. Sources whose base names collide, such as
+// a/photo.png and b/photo.png, are numbered in order of first use:
+// images/photo.png and images/photo-2.png. Names differing only in case
+// also collide, as they do on case-insensitive file systems.
+type fileNamer struct {
+	dir   string
+	names map[string]string // Cleaned source path to file name
+	used  map[string]bool   // Lowercased file names in use
+}
+
+// newFileNamer returns a namer for files in dir, such as "images".
+func newFileNamer(dir string) *fileNamer {
+	return &fileNamer{dir: dir, names: make(map[string]string), used: make(map[string]bool)}
+}
+
+// name returns the file name for src, assigning one on first use.
+func (n *fileNamer) name(src string) string {
+	key := filepath.Clean(src)
+	if name, ok := n.names[key]; ok {
+		return name
+	}
+
+	base := filepath.Base(src)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	name := n.dir + "/" + base
+	for i := 2; n.used[strings.ToLower(name)]; i++ {
+		name = n.dir + "/" + stem + "-" + strconv.Itoa(i) + ext
+	}
+
+	n.names[key] = name
+	n.used[strings.ToLower(name)] = true
+	return name
+}
@@ -0,0 +1,26 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import "regexp"
+
+// pageBreakRe matches page-break directives: a <!-- pagebreak --> comment or
+// an <hr> with the "pagebreak" class.
+var pageBreakRe = regexp.MustCompile(`(?i)<!--\s*pagebreak\s*-->|<hr\b[^>]*\bclass\s*=\s*["'][^"']*\bpagebreak\b[^"']*["'][^>]*>`)
+
+// pageBreakMarker is the element page-break directives are converted to. The
+// EPUB builder styles it and can turn it into an epub:type="pagebreak" anchor.
+const pageBreakMarker = `<div class="page-break"></div>`
+
+// convertPageBreaks replaces page-break directives outside code with page
+// break markers.
+func convertPageBreaks(content string) string {
+	return replaceOutsideCode(content, func(text string) string {
+		return pageBreakRe.ReplaceAllString(text, pageBreakMarker)
+	})
+}
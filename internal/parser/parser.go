@@ -36,6 +36,8 @@ const (
 	FormatMarkdown Format = "markdown"
 	FormatHTML     Format = "html"
 	FormatPDF      Format = "pdf"
+	FormatEPUB     Format = "epub"
+	FormatGallery  Format = "gallery" // Directories of images, made into photo pages
 	FormatUnknown  Format = "unknown"
 )
 
@@ -10,9 +10,9 @@ package parser
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -21,6 +21,10 @@ import (
 	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
+// headingMarkerRe matches the marker extractPageContent puts on heading
+// lines, capturing the heading level and text.
+var headingMarkerRe = regexp.MustCompile(`^###HEADING_(\d+)###\s*(.+)$`)
+
 // PDFParser parses PDF content to Document model.
 type PDFParser struct {
 	minHeadingFontSize float64
@@ -33,83 +37,51 @@ func NewPDFParser() *PDFParser {
 	}
 }
 
-// Parse converts PDF content to a Document.
+// Parse converts PDF content to a Document. Pages are extracted one at a
+// time and their text written straight into chapters, a new one starting
+// at each level-1 heading, so a long PDF's text is never held twice.
 func (p *PDFParser) Parse(content []byte, basePath string) (*model.Document, error) {
 	doc := model.NewDocument()
 
-	// Create a temporary file to read PDF
-	tmpFile, err := os.CreateTemp("", "toepub-*.pdf")
-	if err != nil {
-		return nil, fmt.Errorf("creating temp file: %w", err)
-	}
-	defer os.Remove(tmpFile.Name())
-	defer tmpFile.Close()
-
-	if _, err := tmpFile.Write(content); err != nil {
-		return nil, fmt.Errorf("writing temp file: %w", err)
-	}
-	if err := tmpFile.Close(); err != nil {
-		return nil, fmt.Errorf("closing temp file: %w", err)
-	}
-
-	// Open and read PDF
-	pdfFile, pdfReader, err := pdf.Open(tmpFile.Name())
+	pdfReader, err := pdf.NewReader(bytes.NewReader(content), int64(len(content)))
 	if err != nil {
 		return nil, fmt.Errorf("opening PDF: %w", err)
 	}
-	defer pdfFile.Close()
 
 	numPages := pdfReader.NumPage()
 	if numPages == 0 {
 		return nil, fmt.Errorf("PDF has no pages")
 	}
 
-	// Extract text and structure from all pages
-	var allText strings.Builder
-	var headings []headingInfo
-
+	// Extract text and structure page by page
+	chapters := newPDFChapters(doc)
 	for pageNum := 1; pageNum <= numPages; pageNum++ {
 		page := pdfReader.Page(pageNum)
 		if page.V.IsNull() {
 			continue
 		}
 
-		// Extract text content
-		pageText, pageHeadings := p.extractPageContent(page, pageNum)
-		allText.WriteString(pageText)
-		headings = append(headings, pageHeadings...)
+		pageText := p.extractPageContent(page, pageNum)
+		chapters.writeText(pageText)
 
-		// Add page break marker for multi-page documents
-		if pageNum < numPages {
-			allText.WriteString("\n\n")
-		}
+		// Pages end paragraphs
+		chapters.writeText("\n\n")
 	}
+	chapters.finish()
 
-	text := strings.TrimSpace(allText.String())
-	if text == "" {
+	if len(doc.Chapters) == 0 {
 		return nil, fmt.Errorf("PDF contains no extractable text (might be image-based)")
 	}
 
 	// Try to extract title from first heading or first line
-	title := p.extractTitle(text, headings)
+	title := p.extractTitle(chapters.firstLine, chapters.headings)
 	doc.Metadata.Title = title
-
-	// Convert text to XHTML content
-	xhtmlContent := p.textToXHTML(text, headings)
-
-	// Create chapter
-	chapter := model.Chapter{
-		ID:       "chapter-001",
-		Title:    title,
-		Level:    1,
-		Content:  xhtmlContent,
-		FileName: "content/chapter-001.xhtml",
-		Order:    0,
+	if doc.Chapters[0].Title == "" {
+		doc.Chapters[0].Title = title
 	}
-	doc.AddChapter(chapter)
 
 	// Build TOC from headings
-	doc.TOC = *p.buildTOC(headings)
+	doc.TOC = *model.BuildFromHeadings(chapters.entries)
 
 	return doc, nil
 }
@@ -119,10 +91,10 @@ func (p *PDFParser) SupportedExtensions() []string {
 	return []string{".pdf"}
 }
 
-// extractPageContent extracts text and headings from a PDF page.
-func (p *PDFParser) extractPageContent(page pdf.Page, pageNum int) (string, []headingInfo) {
+// extractPageContent extracts text from a PDF page, marking heading lines.
+// pdfChapters turns the marked lines into headings and TOC entries.
+func (p *PDFParser) extractPageContent(page pdf.Page, pageNum int) string {
 	var text strings.Builder
-	var headings []headingInfo
 
 	rows, err := page.GetTextByRow()
 	if err != nil {
@@ -131,7 +103,7 @@ func (p *PDFParser) extractPageContent(page pdf.Page, pageNum int) (string, []he
 		if err == nil {
 			text.WriteString(plainText)
 		}
-		return text.String(), headings
+		return text.String()
 	}
 
 	// Sort rows by Y position (top to bottom)
@@ -159,12 +131,6 @@ func (p *PDFParser) extractPageContent(page pdf.Page, pageNum int) (string, []he
 		// Detect potential headings based on font size
 		if maxFontSize >= p.minHeadingFontSize && p.looksLikeHeading(line) {
 			level := p.fontSizeToHeadingLevel(maxFontSize)
-			id := generateHeadingID(line)
-			headings = append(headings, headingInfo{
-				Level: level,
-				Title: line,
-				ID:    id,
-			})
 			// Mark as heading in text
 			text.WriteString(fmt.Sprintf("\n###HEADING_%d### %s\n", level, line))
 		} else {
@@ -173,7 +139,7 @@ func (p *PDFParser) extractPageContent(page pdf.Page, pageNum int) (string, []he
 		}
 	}
 
-	return text.String(), headings
+	return text.String()
 }
 
 // looksLikeHeading checks if text looks like a heading (not too long, not punctuation-heavy).
@@ -241,66 +207,110 @@ func (p *PDFParser) extractTitle(text string, headings []headingInfo) string {
 	return "Untitled Document"
 }
 
-// textToXHTML converts extracted PDF text to XHTML content.
-func (p *PDFParser) textToXHTML(text string, headings []headingInfo) string {
-	var xhtml strings.Builder
-
-	// Process text line by line
-	lines := strings.Split(text, "\n")
-	var currentParagraph strings.Builder
-	inParagraph := false
+// pdfChapters writes extracted PDF text into a document's chapters as it
+// arrives, holding only the paragraph and chapter being written. Each
+// level-1 heading after the start of the text begins a new chapter.
+type pdfChapters struct {
+	doc       *model.Document
+	chapter   strings.Builder // XHTML of the chapter being written
+	title     string          // Title of the chapter being written
+	paragraph strings.Builder // Text of the paragraph being written
+	headings  []headingInfo
+	entries   []model.TOCEntry
+	firstLine string // First short line of text, for the title
+}
 
-	headingRe := regexp.MustCompile(`^###HEADING_(\d+)###\s*(.+)$`)
+func newPDFChapters(doc *model.Document) *pdfChapters {
+	return &pdfChapters{doc: doc}
+}
 
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+// fileName returns the file of the chapter being written.
+func (w *pdfChapters) fileName() string {
+	return fmt.Sprintf("content/chapter-%03d.xhtml", len(w.doc.Chapters)+1)
+}
 
-		// Check for heading marker
-		if match := headingRe.FindStringSubmatch(line); match != nil {
-			// Close current paragraph if open
-			if inParagraph {
-				xhtml.WriteString("<p>")
-				xhtml.WriteString(escapeXML(strings.TrimSpace(currentParagraph.String())))
-				xhtml.WriteString("</p>\n")
-				currentParagraph.Reset()
-				inParagraph = false
-			}
+// writeText writes extracted text, in which lines marked by
+// extractPageContent are headings and empty lines end paragraphs. A final
+// newline only ends the last line.
+func (w *pdfChapters) writeText(text string) {
+	for line := range strings.SplitSeq(strings.TrimSuffix(text, "\n"), "\n") {
+		w.writeLine(strings.TrimSpace(line))
+	}
+}
 
-			level := match[1]
-			title := match[2]
-			id := generateHeadingID(title)
-			xhtml.WriteString(fmt.Sprintf("<h%s id=\"%s\">%s</h%s>\n", level, id, escapeXML(title), level))
-			continue
+// writeLine writes one trimmed line of extracted text.
+func (w *pdfChapters) writeLine(line string) {
+	// Check for heading marker
+	if match := headingMarkerRe.FindStringSubmatch(line); match != nil {
+		w.endParagraph()
+		level, _ := strconv.Atoi(match[1])
+		title := match[2]
+		if level == 1 && w.chapter.Len() > 0 {
+			w.endChapter()
 		}
-
-		// Empty line marks paragraph break
-		if line == "" {
-			if inParagraph {
-				xhtml.WriteString("<p>")
-				xhtml.WriteString(escapeXML(strings.TrimSpace(currentParagraph.String())))
-				xhtml.WriteString("</p>\n")
-				currentParagraph.Reset()
-				inParagraph = false
-			}
-			continue
+		if w.title == "" {
+			w.title = title
 		}
 
-		// Accumulate text for paragraph
-		if inParagraph {
-			currentParagraph.WriteString(" ")
-		}
-		currentParagraph.WriteString(line)
-		inParagraph = true
+		id := generateHeadingID(title)
+		w.headings = append(w.headings, headingInfo{Level: level, Title: title, ID: id})
+		w.entries = append(w.entries, model.TOCEntry{
+			Title: title,
+			Href:  w.fileName() + "#" + id,
+			Level: level,
+		})
+		fmt.Fprintf(&w.chapter, "<h%d id=\"%s\">%s</h%d>\n", level, id, escapeXML(title), level)
+		return
+	}
+
+	// Empty line marks paragraph break
+	if line == "" {
+		w.endParagraph()
+		return
 	}
+	if w.firstLine == "" && len(line) < 100 {
+		w.firstLine = line
+	}
+
+	// Accumulate text for paragraph
+	if w.paragraph.Len() > 0 {
+		w.paragraph.WriteString(" ")
+	}
+	w.paragraph.WriteString(line)
+}
 
-	// Close final paragraph
-	if inParagraph {
-		xhtml.WriteString("<p>")
-		xhtml.WriteString(escapeXML(strings.TrimSpace(currentParagraph.String())))
-		xhtml.WriteString("</p>\n")
+// endParagraph writes the paragraph being accumulated, if any.
+func (w *pdfChapters) endParagraph() {
+	if w.paragraph.Len() == 0 {
+		return
 	}
+	w.chapter.WriteString("<p>")
+	w.chapter.WriteString(escapeXML(w.paragraph.String()))
+	w.chapter.WriteString("</p>\n")
+	w.paragraph.Reset()
+}
+
+// endChapter adds the chapter being written to the document.
+func (w *pdfChapters) endChapter() {
+	order := len(w.doc.Chapters)
+	w.doc.AddChapter(model.Chapter{
+		ID:       fmt.Sprintf("chapter-%03d", order+1),
+		Title:    w.title,
+		Level:    1,
+		Content:  w.chapter.String(),
+		FileName: w.fileName(),
+		Order:    order,
+	})
+	w.chapter.Reset()
+	w.title = ""
+}
 
-	return xhtml.String()
+// finish ends the last paragraph and chapter.
+func (w *pdfChapters) finish() {
+	w.endParagraph()
+	if w.chapter.Len() > 0 {
+		w.endChapter()
+	}
 }
 
 // escapeXML escapes special XML characters.
@@ -325,24 +335,6 @@ func escapeXML(s string) string {
 	return buf.String()
 }
 
-// buildTOC creates table of contents from headings.
-func (p *PDFParser) buildTOC(headings []headingInfo) *model.TableOfContents {
-	var entries []model.TOCEntry
-
-	chapterFile := "content/chapter-001.xhtml"
-
-	for _, h := range headings {
-		entry := model.TOCEntry{
-			Title: h.Title,
-			Href:  chapterFile + "#" + h.ID,
-			Level: h.Level,
-		}
-		entries = append(entries, entry)
-	}
-
-	return model.BuildFromHeadings(entries)
-}
-
 // extractImagesFromPDF extracts images from PDF using pdfcpu.
 // Note: Image extraction is a separate optional step.
 func (p *PDFParser) extractImagesFromPDF(pdfPath, outputDir string) ([]model.Resource, error) {
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
 )
 
 func TestPDFParser_Parse_RealPDF(t *testing.T) {
@@ -146,31 +148,38 @@ func TestPDFParser_extractTitle(t *testing.T) {
 	}
 }
 
-func TestPDFParser_textToXHTML(t *testing.T) {
-	p := NewPDFParser()
-
-	text := `###HEADING_1### Main Title
-Some paragraph text.
-
-###HEADING_2### Section
-More text here.`
-
-	headings := []headingInfo{
-		{Level: 1, Title: "Main Title", ID: "main-title"},
-		{Level: 2, Title: "Section", ID: "section"},
-	}
-
-	result := p.textToXHTML(text, headings)
-
-	// Should contain heading tags
-	assert.Contains(t, result, "<h1")
-	assert.Contains(t, result, "<h2")
-	assert.Contains(t, result, "Main Title")
-	assert.Contains(t, result, "Section")
-
-	// Should contain paragraphs
-	assert.Contains(t, result, "<p>")
-	assert.Contains(t, result, "paragraph text")
+func TestPDFChapters(t *testing.T) {
+	doc := model.NewDocument()
+	w := newPDFChapters(doc)
+
+	// Pages arrive one at a time; a level-1 heading starts a new chapter
+	w.writeText("Preface line one\nand two.\n\n")
+	w.writeText("###HEADING_1### Main Title\nSome paragraph\n")
+	w.writeText("text.\n\n###HEADING_2### Section\nMore text here.\n")
+	w.writeText("###HEADING_1### Second <Part>\nThe end.\n")
+	w.finish()
+
+	require.Len(t, doc.Chapters, 3)
+	assert.Equal(t, "<p>Preface line one and two.</p>\n", doc.Chapters[0].Content)
+	assert.Empty(t, doc.Chapters[0].Title)
+
+	first := doc.Chapters[1]
+	assert.Equal(t, "Main Title", first.Title)
+	assert.Equal(t, "content/chapter-002.xhtml", first.FileName)
+	assert.Contains(t, first.Content, `<h1 id="main-title">Main Title</h1>`)
+	assert.Contains(t, first.Content, "<p>Some paragraph text.</p>")
+	assert.Contains(t, first.Content, `<h2 id="section">Section</h2>`)
+
+	second := doc.Chapters[2]
+	assert.Equal(t, "Second <Part>", second.Title)
+	assert.Contains(t, second.Content, "Second &lt;Part&gt;")
+	assert.Equal(t, 2, second.Order)
+
+	require.Len(t, w.entries, 3)
+	assert.Equal(t, "content/chapter-002.xhtml#main-title", w.entries[0].Href)
+	assert.Equal(t, "content/chapter-002.xhtml#section", w.entries[1].Href)
+	assert.Equal(t, "content/chapter-003.xhtml#second-part", w.entries[2].Href)
+	assert.Equal(t, "Preface line one", w.firstLine)
 }
 
 func TestEscapeXML(t *testing.T) {
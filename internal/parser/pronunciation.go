@@ -0,0 +1,63 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// LoadPronunciations reads pronunciations from a YAML file mapping terms to
+// their IPA transcriptions. Entries are returned sorted by term.
+func LoadPronunciations(path string) ([]model.Pronunciation, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePronunciations(data)
+}
+
+// ParsePronunciations parses pronunciations from YAML mapping terms to
+// their IPA transcriptions. Entries are returned sorted by term.
+func ParsePronunciations(data []byte) ([]model.Pronunciation, error) {
+	var terms map[string]string
+	if err := yaml.Unmarshal(data, &terms); err != nil {
+		return nil, fmt.Errorf("parsing pronunciations: %w", err)
+	}
+
+	entries := make([]model.Pronunciation, 0, len(terms))
+	for term, phoneme := range terms {
+		term, phoneme = strings.TrimSpace(term), strings.TrimSpace(phoneme)
+		if term == "" || phoneme == "" {
+			continue
+		}
+		entries = append(entries, model.Pronunciation{Term: term, Phoneme: phoneme})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Term < entries[j].Term })
+	return entries, nil
+}
+
+// LexiconResource creates a resource placeholder for a PLS pronunciation
+// lexicon file given by path. Data is streamed at build time.
+func LexiconResource(path string) model.Resource {
+	baseName := filepath.Base(path)
+	name := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	return model.Resource{
+		ID:         "pls-" + sanitizeID(name),
+		FileName:   "lexicons/" + name + ".pls",
+		MediaType:  model.PLSMediaType,
+		SourcePath: path,
+	}
+}
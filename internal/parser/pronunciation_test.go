@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+func TestLoadPronunciations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("Nguyen: wɪn\n\"New York\": \" nuː ˈjɔːrk \"\nEmpty: \"\"\n"), 0o644))
+
+	entries, err := LoadPronunciations(path)
+	require.NoError(t, err)
+	assert.Equal(t, []model.Pronunciation{
+		{Term: "New York", Phoneme: "nuː ˈjɔːrk"},
+		{Term: "Nguyen", Phoneme: "wɪn"},
+	}, entries)
+}
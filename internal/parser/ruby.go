@@ -0,0 +1,57 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// rubyShorthandRe matches "{base|reading}" ruby markers in rendered text,
+// with one reading per character of the base in "{東京|とう|きょう}".
+var rubyShorthandRe = regexp.MustCompile(`\{([^{}|\s][^{}|]*)\|([^{}]+)\}`)
+
+// convertRuby rewrites "{base|reading}" markers into ruby elements, with
+// parentheses around the reading for reading systems without ruby support.
+// A marker giving as many readings as the base has characters annotates
+// each character with its own; otherwise the readings annotate the whole
+// base. Markers inside code are not converted.
+func convertRuby(content string) string {
+	return replaceOutsideCode(content, func(text string) string {
+		return rubyShorthandRe.ReplaceAllStringFunc(text, func(marker string) string {
+			m := rubyShorthandRe.FindStringSubmatch(marker)
+			base, readings := m[1], strings.Split(m[2], "|")
+
+			var buf strings.Builder
+			buf.WriteString("<ruby>")
+			if len(readings) > 1 && len(readings) == utf8.RuneCountInString(base) {
+				i := 0
+				for _, r := range base {
+					writeRubyText(&buf, string(r), readings[i])
+					i++
+				}
+			} else {
+				writeRubyText(&buf, base, strings.Join(readings, ""))
+			}
+			buf.WriteString("</ruby>")
+			return buf.String()
+		})
+	})
+}
+
+// writeRubyText writes base text annotated with reading.
+func writeRubyText(buf *strings.Builder, base, reading string) {
+	buf.WriteString(base + "<rp>(</rp><rt>" + strings.TrimSpace(reading) + "</rt><rp>)</rp>")
+}
+
+// stripRuby replaces "{base|reading}" markers in plain text, such as a
+// heading title, with their base.
+func stripRuby(text string) string {
+	return rubyShorthandRe.ReplaceAllString(text, "$1")
+}
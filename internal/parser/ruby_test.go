@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownParser_Parse_Ruby(t *testing.T) {
+	md := "# {漢字|かんじ}の本\n\n{東京|とう|きょう}, {日本語|に|ほん}, {A&B|えーびー} and `{a|b}`.\n"
+
+	doc, err := NewMarkdownParser().Parse([]byte(md), ".")
+	require.NoError(t, err)
+
+	content := doc.Chapters[0].Content
+	assert.Contains(t, content, `<ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>の本</h1>`)
+	assert.Contains(t, content, `<ruby>東<rp>(</rp><rt>とう</rt><rp>)</rp>京<rp>(</rp><rt>きょう</rt><rp>)</rp></ruby>`)
+	assert.Contains(t, content, `<ruby>日本語<rp>(</rp><rt>にほん</rt><rp>)</rp></ruby>`)
+	assert.Contains(t, content, `<ruby>A&amp;B<rp>(</rp><rt>えーびー</rt><rp>)</rp></ruby>`)
+	assert.Contains(t, content, `<code>{a|b}</code>`)
+	assert.Equal(t, "漢字の本", doc.TOC.Entries[0].Title)
+}
+
+func TestHTMLParser_Parse_Ruby(t *testing.T) {
+	html := `<html><body><h1><ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>の本</h1></body></html>`
+
+	doc, err := NewHTMLParser().Parse([]byte(html), ".")
+	require.NoError(t, err)
+	assert.Contains(t, doc.Chapters[0].Content, `<ruby>漢字<rp>(</rp><rt>かんじ</rt><rp>)</rp></ruby>`)
+	assert.Equal(t, "漢字の本", doc.TOC.Entries[0].Title)
+}
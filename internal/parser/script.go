@@ -0,0 +1,110 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// ScriptMediaType is the EPUB core media type for JavaScript resources.
+const ScriptMediaType = "application/javascript"
+
+// ScriptPolicy controls which JavaScript the HTML parser preserves.
+// The zero value strips all scripts and event handlers.
+type ScriptPolicy struct {
+	Allowed []string // Glob patterns matched against script src file names ("*" allows all)
+	Inline  bool     // Preserve inline scripts and on* event handler attributes
+}
+
+// ScriptPolicyParser is implemented by parsers that support preserving
+// scripts. WithScriptPolicy returns a parser using the policy, leaving the
+// receiver unchanged so it can be shared between conversions.
+type ScriptPolicyParser interface {
+	WithScriptPolicy(policy ScriptPolicy) Parser
+}
+
+// Enabled returns true if the policy preserves any scripts.
+func (sp ScriptPolicy) Enabled() bool {
+	return len(sp.Allowed) > 0 || sp.Inline
+}
+
+// allows returns true if an external script src is whitelisted.
+func (sp ScriptPolicy) allows(src string) bool {
+	if isRemoteRef(src) {
+		return false
+	}
+	name := filepath.Base(src)
+	for _, pattern := range sp.Allowed {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Patterns for script filtering.
+var (
+	scriptElementRe = regexp.MustCompile(`(?is)<script([^>]*)>(.*?)</script>`)
+	scriptSrcRe     = regexp.MustCompile(`(\ssrc=["'])([^"']+)(["'])`)
+	eventHandlerRe  = regexp.MustCompile(`\s+on\w+="[^"]*"`)
+)
+
+// scriptResourceFor returns a resource placeholder for a local script file.
+func scriptResourceFor(src string, basePath string) model.Resource {
+	baseName := filepath.Base(src)
+	return model.Resource{
+		ID:         "script-" + sanitizeID(strings.TrimSuffix(baseName, filepath.Ext(baseName))),
+		FileName:   "scripts/" + baseName,
+		MediaType:  ScriptMediaType,
+		SourcePath: resolveRef(src, basePath),
+	}
+}
+
+// filterScripts removes scripts not allowed by the policy and rewrites the
+// src of whitelisted external scripts to their EPUB path. Returns the
+// filtered content and the script resources to embed.
+func (sp ScriptPolicy) filterScripts(content string, basePath string) (string, []model.Resource) {
+	var resources []model.Resource
+	seen := make(map[string]bool)
+
+	content = scriptElementRe.ReplaceAllStringFunc(content, func(match string) string {
+		parts := scriptElementRe.FindStringSubmatch(match)
+		attrs := parts[1]
+
+		src := scriptSrcRe.FindStringSubmatch(attrs)
+		if src == nil {
+			if sp.Inline {
+				return match
+			}
+			return ""
+		}
+
+		if !sp.allows(src[2]) {
+			return ""
+		}
+
+		res := scriptResourceFor(src[2], basePath)
+		if !seen[res.FileName] {
+			seen[res.FileName] = true
+			resources = append(resources, res)
+		}
+
+		newAttrs := scriptSrcRe.ReplaceAllString(attrs, "${1}../"+res.FileName+"${3}")
+		return "<script" + newAttrs + ">" + parts[2] + "</script>"
+	})
+
+	if !sp.Inline {
+		content = eventHandlerRe.ReplaceAllString(content, "")
+	}
+
+	return content, resources
+}
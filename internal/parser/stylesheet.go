@@ -0,0 +1,51 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+package parser
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/dauquangthanh/epub-converter/internal/model"
+)
+
+// StylesheetResource creates a stylesheet resource placeholder for a local
+// CSS file given by path, named as front matter stylesheets are.
+func StylesheetResource(path string) (model.Resource, bool) {
+	return newStylesheetResource(path, "")
+}
+
+// newStylesheetResource creates a stylesheet resource placeholder for a local
+// CSS file referenced by a document. Data is loaded by the converter.
+// Returns false for remote URLs and data URIs.
+func newStylesheetResource(href string, basePath string) (model.Resource, bool) {
+	if href == "" || strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") ||
+		strings.HasPrefix(href, "data:") {
+		return model.Resource{}, false
+	}
+
+	// Drop query strings and fragments
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		href = href[:i]
+	}
+
+	baseName := filepath.Base(href)
+	name := strings.TrimSuffix(baseName, filepath.Ext(baseName))
+
+	sourcePath := href
+	if !filepath.IsAbs(href) {
+		sourcePath = filepath.Join(basePath, href)
+	}
+
+	return model.Resource{
+		ID:         "css-" + sanitizeID(name),
+		FileName:   "styles/" + name + ".css",
+		MediaType:  "text/css",
+		SourcePath: sourcePath,
+	}, true
+}
@@ -0,0 +1,502 @@
+// ------------------------------------------------------------------
+// Developed by Dau Quang Thanh - 2025.
+// Enterprise AI Solution Architect
+//
+// Happy Reading!
+// ------------------------------------------------------------------
+
+// Package server provides an HTTP API for converting documents to EPUB.
+//
+// POST /convert accepts a multipart upload or a JSON request naming a URL,
+// and streams the EPUB back in the response. GET /healthz reports whether
+// the server is up.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"path"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/dauquangthanh/epub-converter/internal/converter"
+	"github.com/dauquangthanh/epub-converter/internal/epub"
+	"github.com/dauquangthanh/epub-converter/internal/model"
+	"github.com/dauquangthanh/epub-converter/internal/parser"
+)
+
+// Default limits used when a Config field is zero.
+const (
+	DefaultMaxConcurrent = 4
+	DefaultMaxUploadSize = 50 << 20 // 50 MB
+	DefaultFetchTimeout  = 30 * time.Second
+)
+
+// Config configures the server.
+type Config struct {
+	MaxConcurrent int           // Conversions run at once; further requests get 503
+	MaxUploadSize int64         // Largest accepted upload or fetched document, in bytes
+	AllowURLs     bool          // Accept requests naming a URL to fetch the document from
+	AllowPrivate  bool          // Fetch URLs on loopback, private, and link-local addresses too
+	MaxMemory     int64         // Memory budget of each conversion in bytes; 0 means no limit
+	FetchTimeout  time.Duration // Timeout for fetching a document from a URL
+	Logger        *slog.Logger  // Request and conversion events; nil uses slog.Default()
+}
+
+// Server handles conversion requests. Conversions share one Converter.
+type Server struct {
+	conv   *converter.Converter
+	cfg    Config
+	slots  chan struct{}
+	client *http.Client
+	log    *slog.Logger
+}
+
+// New creates a server, applying defaults for zero Config fields.
+func New(cfg Config) *Server {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if cfg.MaxUploadSize <= 0 {
+		cfg.MaxUploadSize = DefaultMaxUploadSize
+	}
+	if cfg.FetchTimeout <= 0 {
+		cfg.FetchTimeout = DefaultFetchTimeout
+	}
+	log := cfg.Logger
+	if log == nil {
+		log = slog.Default()
+	}
+
+	return &Server{
+		conv:   converter.New(),
+		cfg:    cfg,
+		slots:  make(chan struct{}, cfg.MaxConcurrent),
+		client: fetchClient(cfg),
+		log:    log,
+	}
+}
+
+// errPrivateAddress is returned for URLs that lead to an address requests
+// may not reach.
+var errPrivateAddress = errors.New("address not allowed")
+
+// fetchClient returns the client fetching documents named by URL. Unless
+// cfg.AllowPrivate is set, it refuses to connect to loopback, private, and
+// link-local addresses, checked after each name lookup and redirect, so
+// requests cannot reach the server's own network.
+func fetchClient(cfg Config) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !cfg.AllowPrivate {
+		dialer := &net.Dialer{Timeout: cfg.FetchTimeout, Control: publicAddressOnly}
+		transport.DialContext = dialer.DialContext
+		// A proxy would connect to the address on the server's behalf
+		transport.Proxy = nil
+	}
+	return &http.Client{Timeout: cfg.FetchTimeout, Transport: transport}
+}
+
+// publicAddressOnly is a net.Dialer Control function refusing connections
+// to loopback, private, link-local, and unspecified addresses.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", errPrivateAddress, ip)
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("POST /convert", s.handleConvert)
+	return mux
+}
+
+// convertRequest is a parsed conversion request.
+type convertRequest struct {
+	body     io.ReadCloser // Document content
+	name     string        // Upload or URL file name, used for format detection and the response
+	format   string        // Explicit input format, if given
+	metadata *model.Metadata
+	opts     requestOptions
+}
+
+// requestMetadata is the metadata accepted in requests.
+type requestMetadata struct {
+	Title       string   `json:"title"`
+	Authors     []string `json:"authors"`
+	Language    string   `json:"language"`
+	Publisher   string   `json:"publisher"`
+	Description string   `json:"description"`
+	Rights      string   `json:"rights"`
+	Subjects    []string `json:"subjects"`
+}
+
+// requestOptions are the conversion options accepted in requests.
+type requestOptions struct {
+	TOCPage        bool   `json:"toc_page"`
+	NumberSections bool   `json:"number_sections"`
+	SmartQuotes    bool   `json:"smart_quotes"`
+	EPUBVersion    string `json:"epub_version"`
+}
+
+// jsonRequest is the body of a JSON conversion request.
+type jsonRequest struct {
+	URL      string          `json:"url"`
+	Format   string          `json:"format"`
+	Metadata requestMetadata `json:"metadata"`
+	requestOptions
+}
+
+// handleHealth reports that the server is up, with the number of
+// conversions running.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "ok",
+		"running": len(s.slots),
+		"limit":   cap(s.slots),
+	})
+}
+
+// handleConvert converts the request's document and streams the EPUB back.
+func (s *Server) handleConvert(w http.ResponseWriter, r *http.Request) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		w.Header().Set("Retry-After", "5")
+		writeError(w, http.StatusServiceUnavailable, "busy", "too many conversions running, retry later")
+		return
+	}
+
+	start := time.Now()
+	r.Body = http.MaxBytesReader(w, r.Body, s.cfg.MaxUploadSize)
+
+	req, status, err := s.parseRequest(r)
+	if err != nil {
+		kind := "bad_request"
+		if status == http.StatusRequestEntityTooLarge {
+			kind = "too_large"
+		}
+		writeError(w, status, kind, err.Error())
+		return
+	}
+	defer req.body.Close()
+
+	format, err := s.detectFormat(req)
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, "unsupported_format", err.Error())
+		return
+	}
+
+	version, err := epub.ParseVersion(req.opts.EPUBVersion)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	opts := converter.Options{
+		CLIMetadata: req.metadata,
+		Typography:  req.opts.SmartQuotes,
+		Logger:      s.log,
+		Events:      converter.LogEvents(s.log),
+		MaxMemory:   s.cfg.MaxMemory,
+		EPUB: epub.Options{
+			TOCPage:        req.opts.TOCPage,
+			NumberSections: req.opts.NumberSections,
+			Version:        version,
+		},
+	}
+
+	out := &epubResponse{w: w, name: outputName(req.name)}
+	result, err := s.conv.ConvertReader(req.body, format, out, opts)
+	if err != nil {
+		s.log.Warn("conversion failed", "stage", "server", "file", req.name, "error", err)
+		if out.started {
+			// Headers are sent; the truncated archive signals the failure
+			return
+		}
+		status, kind := errorStatus(err)
+		writeError(w, status, kind, err.Error())
+		return
+	}
+
+	s.log.Info("converted request", "stage", "server", "file", req.name, "format", format.String(),
+		"bytes", result.Stats.OutputSize, "warnings", len(result.Warnings), "duration", time.Since(start))
+}
+
+// parseRequest reads a multipart upload or a JSON request naming a URL.
+func (s *Server) parseRequest(r *http.Request) (*convertRequest, int, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	switch mediaType {
+	case "multipart/form-data":
+		return s.parseMultipart(r)
+	case "application/json":
+		var body jsonRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid JSON request: %v", err)
+		}
+		if body.URL == "" {
+			return nil, http.StatusBadRequest, errors.New("JSON requests must give a url")
+		}
+		req := &convertRequest{format: body.Format, metadata: body.Metadata.model(), opts: body.requestOptions}
+		return s.fetchURL(r, req, body.URL)
+	default:
+		return nil, http.StatusUnsupportedMediaType, errors.New("send multipart/form-data with a file field, or application/json with a url")
+	}
+}
+
+// parseMultipart reads an upload in the "file" field, or a URL in the "url"
+// field, with metadata in form fields or a JSON "metadata" field.
+func (s *Server) parseMultipart(r *http.Request) (*convertRequest, int, error) {
+	if err := r.ParseMultipartForm(s.cfg.MaxUploadSize); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("upload larger than %d bytes", maxErr.Limit)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid upload: %v", err)
+	}
+
+	meta := requestMetadata{
+		Title:       r.FormValue("title"),
+		Authors:     r.MultipartForm.Value["author"],
+		Language:    r.FormValue("language"),
+		Publisher:   r.FormValue("publisher"),
+		Description: r.FormValue("description"),
+		Rights:      r.FormValue("rights"),
+		Subjects:    r.MultipartForm.Value["subject"],
+	}
+	if raw := r.FormValue("metadata"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid metadata field: %v", err)
+		}
+	}
+
+	req := &convertRequest{
+		format:   r.FormValue("format"),
+		metadata: meta.model(),
+		opts: requestOptions{
+			TOCPage:        formBool(r, "toc_page"),
+			NumberSections: formBool(r, "number_sections"),
+			SmartQuotes:    formBool(r, "smart_quotes"),
+			EPUBVersion:    r.FormValue("epub_version"),
+		},
+	}
+
+	file, header, err := r.FormFile("file")
+	if errors.Is(err, http.ErrMissingFile) {
+		if u := r.FormValue("url"); u != "" {
+			return s.fetchURL(r, req, u)
+		}
+		return nil, http.StatusBadRequest, errors.New("upload a file field or give a url field")
+	}
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid upload: %v", err)
+	}
+	req.body = file
+	req.name = header.Filename
+	return req, http.StatusOK, nil
+}
+
+// fetchURL sets the request body to the document at rawURL.
+func (s *Server) fetchURL(r *http.Request, req *convertRequest, rawURL string) (*convertRequest, int, error) {
+	if !s.cfg.AllowURLs {
+		return nil, http.StatusForbidden, errors.New("fetching documents from URLs is disabled on this server")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid url %q: must be an http or https URL", rawURL)
+	}
+
+	fetch, err := http.NewRequestWithContext(r.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid url %q: %v", rawURL, err)
+	}
+	resp, err := s.client.Do(fetch)
+	if errors.Is(err, errPrivateAddress) {
+		return nil, http.StatusForbidden, fmt.Errorf("fetching %s: %v", u, err)
+	}
+	if err != nil {
+		return nil, http.StatusBadGateway, fmt.Errorf("fetching %s: %v", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, http.StatusBadGateway, fmt.Errorf("fetching %s: %s", u, resp.Status)
+	}
+	if resp.ContentLength > s.cfg.MaxUploadSize {
+		resp.Body.Close()
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("document at %s is larger than %d bytes", u, s.cfg.MaxUploadSize)
+	}
+
+	// Read one byte over the limit, enough to tell it is exceeded
+	req.body = &limitedBody{
+		Reader: io.LimitReader(resp.Body, s.cfg.MaxUploadSize+1),
+		Closer: resp.Body,
+		limit:  s.cfg.MaxUploadSize,
+	}
+	req.name = path.Base(u.Path)
+	if req.format == "" {
+		req.format = formatFromMediaType(resp.Header.Get("Content-Type"))
+	}
+	return req, http.StatusOK, nil
+}
+
+// limitedBody caps a fetched body while closing the underlying response.
+// Reading past limit fails with an *http.MaxBytesError, as uploads over
+// the limit do, rather than cutting the document short.
+type limitedBody struct {
+	io.Reader
+	io.Closer
+	limit int64
+	read  int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.Reader.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		return n - int(b.read-b.limit), &http.MaxBytesError{Limit: b.limit}
+	}
+	return n, err
+}
+
+// detectFormat returns the input format from the explicit format or the
+// file name's extension.
+func (s *Server) detectFormat(req *convertRequest) (parser.Format, error) {
+	name := req.format
+	if name == "" {
+		name = strings.TrimPrefix(strings.ToLower(path.Ext(req.name)), ".")
+	}
+	switch name {
+	case "md", "markdown":
+		return parser.FormatMarkdown, nil
+	case "html", "htm":
+		return parser.FormatHTML, nil
+	case "pdf":
+		return parser.FormatPDF, nil
+	case "":
+		return parser.FormatUnknown, errors.New("cannot detect the input format: give a format field (md, html, pdf)")
+	default:
+		return parser.FormatUnknown, fmt.Errorf("unsupported input format %q: must be md, html or pdf", name)
+	}
+}
+
+// formatFromMediaType maps a fetched document's media type to a format.
+func formatFromMediaType(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/markdown", "text/x-markdown":
+		return "md"
+	case "text/html", "application/xhtml+xml":
+		return "html"
+	case "application/pdf":
+		return "pdf"
+	default:
+		return ""
+	}
+}
+
+// model converts request metadata to metadata overrides.
+func (m requestMetadata) model() *model.Metadata {
+	return &model.Metadata{
+		Title:       m.Title,
+		Authors:     m.Authors,
+		Language:    m.Language,
+		Publisher:   m.Publisher,
+		Description: m.Description,
+		Rights:      m.Rights,
+		Subjects:    m.Subjects,
+	}
+}
+
+// formBool reports whether a form field is set to a true value.
+func formBool(r *http.Request, name string) bool {
+	switch strings.ToLower(r.FormValue(name)) {
+	case "1", "true", "yes", "on":
+		return true
+	default:
+		return false
+	}
+}
+
+// outputName returns the EPUB file name for an input name.
+func outputName(name string) string {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if base == "" || base == "." || base == "/" {
+		base = "book"
+	}
+	return base + ".epub"
+}
+
+// epubResponse sends EPUB headers before the first byte of the archive, so
+// errors found before building can still be returned as JSON.
+type epubResponse struct {
+	w       http.ResponseWriter
+	name    string
+	started bool
+}
+
+func (e *epubResponse) Write(p []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		e.w.Header().Set("Content-Type", "application/epub+zip")
+		e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": e.name}))
+		e.w.WriteHeader(http.StatusOK)
+	}
+	return e.w.Write(p)
+}
+
+// errorStatus maps a conversion error to an HTTP status and error type.
+func errorStatus(err error) (int, string) {
+	var parseErr *converter.ParseError
+	switch {
+	case errors.Is(err, converter.ErrUnsupportedFmt):
+		return http.StatusUnsupportedMediaType, "unsupported_format"
+	case errors.As(err, &parseErr):
+		return http.StatusUnprocessableEntity, "parse_error"
+	case errors.Is(err, epub.ErrInvalidDocument):
+		return http.StatusUnprocessableEntity, "invalid_document"
+	case errors.Is(err, converter.ErrMemoryLimit):
+		return http.StatusRequestEntityTooLarge, "memory_limit"
+	default:
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return http.StatusRequestEntityTooLarge, "too_large"
+		}
+		return http.StatusInternalServerError, "error"
+	}
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, kind, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"type": kind, "message": message},
+	})
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(cfg Config) *Server {
+	cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return New(cfg)
+}
+
+// uploadRequest builds a multipart request uploading content as name.
+func uploadRequest(t *testing.T, name, content string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for k, v := range fields {
+		require.NoError(t, mw.WriteField(k, v))
+	}
+	if name != "" {
+		fw, err := mw.CreateFormFile("file", name)
+		require.NoError(t, err)
+		_, err = io.WriteString(fw, content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, mw.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/convert", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+// packageDocument returns content.opf from an EPUB response body.
+func packageDocument(t *testing.T, body []byte) string {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	require.NoError(t, err)
+	for _, f := range archive.File {
+		if f.Name == "OEBPS/content.opf" {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			defer rc.Close()
+			data, err := io.ReadAll(rc)
+			require.NoError(t, err)
+			return string(data)
+		}
+	}
+	t.Fatal("content.opf not found")
+	return ""
+}
+
+func TestServer_ConvertUpload(t *testing.T) {
+	srv := newTestServer(Config{})
+	req := uploadRequest(t, "notes.md", "# One\n\nHello.\n", map[string]string{
+		"title":    "Uploaded",
+		"metadata": `{"publisher": "Example Press"}`,
+	})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/epub+zip", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `filename=notes.epub`)
+	opf := packageDocument(t, rec.Body.Bytes())
+	assert.Contains(t, opf, "<dc:title>Uploaded</dc:title>")
+	assert.Contains(t, opf, "Example Press")
+}
+
+func TestServer_ConvertErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    func(t *testing.T) *http.Request
+		status int
+		kind   string
+	}{
+		{"unknown extension", func(t *testing.T) *http.Request {
+			return uploadRequest(t, "notes.txt", "text", nil)
+		}, http.StatusUnsupportedMediaType, "unsupported_format"},
+		{"missing file", func(t *testing.T) *http.Request {
+			return uploadRequest(t, "", "", map[string]string{"title": "x"})
+		}, http.StatusBadRequest, "bad_request"},
+		{"url disabled", func(t *testing.T) *http.Request {
+			req := httptest.NewRequest(http.MethodPost, "/convert", strings.NewReader(`{"url": "http://example.com/a.md"}`))
+			req.Header.Set("Content-Type", "application/json")
+			return req
+		}, http.StatusForbidden, "bad_request"},
+		{"plain body", func(t *testing.T) *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/convert", strings.NewReader("# One"))
+		}, http.StatusUnsupportedMediaType, "bad_request"},
+	}
+
+	srv := newTestServer(Config{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, tt.req(t))
+			assert.Equal(t, tt.status, rec.Code)
+
+			var body struct {
+				Error struct{ Type string } `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.Equal(t, tt.kind, body.Error.Type)
+		})
+	}
+}
+
+func TestServer_ConvertURL(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		io.WriteString(w, "<html><head><title>Fetched</title></head><body><h1>One</h1></body></html>")
+	}))
+	defer origin.Close()
+
+	srv := newTestServer(Config{AllowURLs: true, AllowPrivate: true})
+	req := httptest.NewRequest(http.MethodPost, "/convert",
+		strings.NewReader(`{"url": "`+origin.URL+`/page", "metadata": {"authors": ["Jane Doe"]}}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	opf := packageDocument(t, rec.Body.Bytes())
+	assert.Contains(t, opf, "Fetched")
+	assert.Contains(t, opf, "Jane Doe")
+}
+
+func TestServer_ConvertURLLimits(t *testing.T) {
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/markdown")
+		io.WriteString(w, "# One\n\n")
+		if r.URL.Path == "/chunked" {
+			// Flushing sends the body without a Content-Length
+			w.(http.Flusher).Flush()
+		}
+		io.WriteString(w, strings.Repeat("text ", 100))
+	}))
+	defer origin.Close()
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		path   string
+		status int
+	}{
+		{"private address", Config{AllowURLs: true}, "/small", http.StatusForbidden},
+		{"content length over limit", Config{AllowURLs: true, AllowPrivate: true, MaxUploadSize: 64}, "/long", http.StatusRequestEntityTooLarge},
+		{"body over limit", Config{AllowURLs: true, AllowPrivate: true, MaxUploadSize: 64}, "/chunked", http.StatusRequestEntityTooLarge},
+		{"within limit", Config{AllowURLs: true, AllowPrivate: true, MaxUploadSize: 1024}, "/chunked", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/convert", strings.NewReader(`{"url": "`+origin.URL+tt.path+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			newTestServer(tt.cfg).Handler().ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+		})
+	}
+}
+
+func TestPublicAddressOnly(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1:80", "[::1]:443", "10.1.2.3:80", "192.168.0.1:80", "169.254.169.254:80", "[fe80::1]:80", "[::ffff:127.0.0.1]:80", "0.0.0.0:80"} {
+		assert.ErrorIs(t, publicAddressOnly("tcp", addr, nil), errPrivateAddress, addr)
+	}
+	assert.NoError(t, publicAddressOnly("tcp", "93.184.216.34:443", nil))
+	assert.NoError(t, publicAddressOnly("tcp6", "[2606:2800:220:1::1]:443", nil))
+}
+
+func TestServer_FrontMatterFilesNotRead(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "secret.yaml")
+	require.NoError(t, os.WriteFile(secret, []byte("Password: hunter2\n"), 0o644))
+
+	srv := newTestServer(Config{})
+	for _, key := range []string{"glossary", "bibliography"} {
+		t.Run(key, func(t *testing.T) {
+			content := "---\ntitle: Probe\n" + key + ": " + secret + "\n---\n# One\n\nPassword\n"
+			rec := httptest.NewRecorder()
+			srv.Handler().ServeHTTP(rec, uploadRequest(t, "probe.md", content, nil))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+			archive, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+			require.NoError(t, err)
+			for _, f := range archive.File {
+				assert.NotContains(t, f.Name, "glossary")
+				rc, err := f.Open()
+				require.NoError(t, err)
+				data, err := io.ReadAll(rc)
+				rc.Close()
+				require.NoError(t, err)
+				assert.NotContains(t, string(data), "hunter2", f.Name)
+			}
+		})
+	}
+}
+
+func TestServer_ConcurrencyLimit(t *testing.T) {
+	srv := newTestServer(Config{MaxConcurrent: 1})
+	srv.slots <- struct{}{} // A conversion is running
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, uploadRequest(t, "a.md", "# One\n", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status": "ok", "running": 1, "limit": 1}`, rec.Body.String())
+}
+
+func TestServer_UploadTooLarge(t *testing.T) {
+	srv := newTestServer(Config{MaxUploadSize: 64})
+
+	rec := httptest.NewRecorder()
+	srv.Handler().ServeHTTP(rec, uploadRequest(t, "a.md", strings.Repeat("text ", 100), nil))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -164,6 +164,8 @@ func runToepub(t *testing.T, binary string, args ...string) *Result {
 	t.Helper()
 
 	cmd := exec.Command(binary, args...)
+	// Messages in English, whatever the locale of the machine running the tests
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
 	var stdout, stderr strings.Builder
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr